@@ -405,7 +405,8 @@ func LoadChannelsManagerMobile(cmixID int, storageTag, dbFilePath string,
 
 	// Construct new channels manager
 	m, err := channels.LoadManager(storageTag, channelsKV, user.api.GetCmix(),
-		user.api.GetRng(), model, extensionBuilders, notif.manager, wrap)
+		user.api.GetRng(), model, extensionBuilders, user.api.AddService,
+		notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -543,7 +544,7 @@ func LoadChannelsManager(cmixID int, storageTag string,
 	// Construct new channels manager
 	m, err := channels.LoadManagerBuilder(storageTag, channelsKV,
 		user.api.GetCmix(), user.api.GetRng(), eb, extensionBuilders,
-		notif.manager, wrap)
+		user.api.AddService, notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -671,7 +672,7 @@ func LoadChannelsManagerGoEventModel(cmixID int, storageTag string,
 	// Construct new channels manager
 	m, err := channels.LoadManagerBuilder(storageTag, channelsKV,
 		user.api.GetCmix(), user.api.GetRng(), goEventBuilder,
-		extensionBuilders, notif.manager, wrap)
+		extensionBuilders, user.api.AddService, notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -1427,6 +1428,290 @@ func (cm *ChannelsManager) SendReaction(channelIdBytes []byte, reaction string,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// SendPoll is used to send a poll over a channel. Members of the channel can
+// vote on one of the options with [ChannelsManager.SendPollVote]. The poll must
+// have at least two options; otherwise, [channels.InvalidPollErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - question - The question asked by the poll.
+//   - optionsJSON - JSON of a list of the option strings.
+//   - validUntilMS - The lease of the message. This will be how long the
+//     message is available from the network, in milliseconds. Use
+//     [channels.ValidForever] to last the max message life.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Example optionsJSON:
+//
+//	["Red","Green","Blue"]
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendPoll(channelIdBytes []byte, question string,
+	optionsJSON []byte, validUntilMS int64, cmixParamsJSON []byte) (
+	[]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err := parseChannelsParameters(
+		channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal options
+	var options []string
+	if err = json.Unmarshal(optionsJSON, &options); err != nil {
+		return nil, errors.Errorf("failed to JSON unmarshal options: %+v", err)
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	// Send poll
+	messageID, rnd, ephID, err :=
+		cm.api.SendPoll(channelID, question, options, lease, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SendPollVote is used to vote for an option on a poll. A user has at most one
+// vote per poll; voting again replaces the previous vote. If the option does
+// not exist in the poll, then [channels.InvalidPollErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - pollMessageIdBytes - The marshalled [channel.MessageID] of the poll.
+//   - option - The index of the option to vote for.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendPollVote(channelIdBytes,
+	pollMessageIdBytes []byte, option int, cmixParamsJSON []byte) (
+	[]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err := parseChannelsParameters(
+		channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal message ID
+	pollMessageID := cryptoMessage.ID{}
+	copy(pollMessageID[:], pollMessageIdBytes)
+
+	if option < 0 {
+		return nil, channels.InvalidPollErr
+	}
+
+	// Send vote
+	messageID, rnd, ephID, err := cm.api.SendPollVote(
+		channelID, pollMessageID, uint32(option), params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SendTyping is used to signal to the channel that the user started or stopped
+// typing. Typing indicators are sent at low priority, are only valid for
+// [channels.TypingTimeout], and are never stored. They are delivered to other
+// members of the channel via [ChannelUICallbacks] with the event type
+// [UserTyping].
+//
+// While the user is typing, the UI should call SendTyping with typing set to
+// true every [channels.TypingInterval]. Sending more often returns
+// [channels.TypingRateLimitErr], which can be safely ignored.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - typing - Set to true if the user is typing and false if they stopped.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendTyping(channelIdBytes []byte, typing bool,
+	cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err := parseChannelsParameters(
+		channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send typing indicator
+	messageID, rnd, ephID, err :=
+		cm.api.SendTyping(channelID, typing, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// ScheduleMessage queues a formatted message to be sent to the channel at a
+// future time. The queue is saved to storage so that messages are sent after a
+// restart. When the message is sent or fails, [ChannelUICallbacks] is called
+// with the event type [ScheduledSendUpdate].
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - message - The contents of the message. See [ChannelsManager.SendMessage].
+//   - sendAtNS - The time to send the message at, in Unix nanoseconds. It must
+//     be in the future.
+//   - validUntilMS - The lease of the message once it is sent, in
+//     milliseconds. Use [channels.ValidForever] to last the max message life.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and [GetDefaultCMixParams] will be used internally.
+//   - pingsJSON - JSON of a slice of public keys of users that should receive
+//     mobile notifications for the message.
+//
+// Returns:
+//   - []byte - JSON of [channels.ScheduledSend].
+func (cm *ChannelsManager) ScheduleMessage(channelIdBytes []byte,
+	message string, sendAtNS, validUntilMS int64, cmixParamsJSON []byte,
+	pingsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	pings, err := unmarshalPingsJson(pingsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := cm.api.ScheduleMessage(channelID, message,
+		time.Unix(0, sendAtNS), lease, params.CMIX, pings)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ss)
+}
+
+// ScheduleSend queues a raw message to be sent to the channel at a future
+// time. See [ChannelsManager.SendGeneric] for details on the message and
+// [ChannelsManager.ScheduleMessage] for details on scheduling.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - messageType - The message type of the message. This will be a valid
+//     [channels.MessageType].
+//   - message - The contents of the message.
+//   - sendAtNS - The time to send the message at, in Unix nanoseconds. It must
+//     be in the future.
+//   - validUntilMS - The lease of the message once it is sent, in
+//     milliseconds. Use [channels.ValidForever] to last the max message life.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and [GetDefaultCMixParams] will be used internally.
+//   - pingsMapJSON - JSON of a map of slices of [ed25519.PublicKey] of users
+//     that should receive mobile notifications for the message. Each slice keys
+//     on a [channels.PingType] that describes the type of notification it is.
+//
+// Returns:
+//   - []byte - JSON of [channels.ScheduledSend].
+func (cm *ChannelsManager) ScheduleSend(channelIdBytes []byte, messageType int,
+	message []byte, sendAtNS, validUntilMS int64, cmixParamsJSON []byte,
+	pingsMapJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	pingsMap, err := unmarshalPingsMapJson(pingsMapJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := cm.api.ScheduleSend(channelID, channels.MessageType(messageType),
+		message, time.Unix(0, sendAtNS), lease, params.CMIX, pingsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ss)
+}
+
+// GetScheduledSends returns the messages queued to be sent to the channel,
+// sorted by the time they are sent.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID]. If empty,
+//     the messages queued for all channels are returned.
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.ScheduledSend].
+//
+// Example return:
+//
+//	[{"id":0,"channelID":"YSc2bDijXIVhmIsJk2OZQjU9ei2Dn6MS8tOpXlIaUpSV","messageType":1,"payload":"EgVoZWxsbw==","validUntil":9223372036854775807,"params":{"RoundTries":10,"Timeout":45000000000,"RetryDelay":1000000000,"SendTimeout":3000000000,"DebugTag":"External","Critical":false,"BlacklistedNodes":{}},"pings":{"usrMention":null},"sendAt":"2023-05-05T23:13:34Z"}]
+func (cm *ChannelsManager) GetScheduledSends(
+	channelIdBytes []byte) ([]byte, error) {
+	var channelID *id.ID
+	if len(channelIdBytes) > 0 {
+		var err error
+		channelID, err = id.Unmarshal(channelIdBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(cm.api.GetScheduledSends(channelID))
+}
+
+// CancelScheduledSend removes the message from the queue so that it is never
+// sent. Returns [channels.ScheduledSendNotFoundErr] if the message does not
+// exist or was already sent.
+//
+// Parameters:
+//   - sendID - The ID of the [channels.ScheduledSend].
+func (cm *ChannelsManager) CancelScheduledSend(sendID int64) error {
+	return cm.api.CancelScheduledSend(uint64(sendID))
+}
+
+// RescheduleSend changes the time the queued message is sent at. Returns
+// [channels.ScheduledSendNotFoundErr] if the message does not exist or was
+// already sent.
+//
+// Parameters:
+//   - sendID - The ID of the [channels.ScheduledSend].
+//   - sendAtNS - The new time to send the message at, in Unix nanoseconds. It
+//     must be in the future.
+func (cm *ChannelsManager) RescheduleSend(sendID, sendAtNS int64) error {
+	return cm.api.RescheduleSend(uint64(sendID), time.Unix(0, sendAtNS))
+}
+
 // SendSilent is used to send to a channel a message with no notifications.
 // Its primary purpose is to communicate new nicknames without calling
 // [SendMessage].
@@ -1622,16 +1907,19 @@ func (cm *ChannelsManager) SendAdminGeneric(channelIdBytes []byte,
 }
 
 // DeleteMessage deletes the targeted message from user's view. Users may delete
-// their own messages but only the channel admin can delete other user's
-// messages. If the user is not an admin of the channel or if they are not the
-// sender of the targetMessage, then the error [channels.NotAnAdminErr] is
-// returned.
+// their own messages but only the channel admin and moderators can delete other
+// user's messages. If the user is not an admin or moderator of the channel or
+// if they are not the sender of the targetMessage, then the error
+// [channels.NotAnAdminErr] is returned.
 //
 // If undoAction is true, then the targeted message is un-deleted.
 //
 // Clients will drop the deletion if they do not recognize the target
 // message.
 //
+// Deletions sent by a moderator are not replayed by the lease system, so users
+// who join after the message has left the network will not see them.
+//
 // Parameters:
 //   - channelIdBytes - Marshalled bytes of channel [id.ID].
 //   - targetMessageIdBytes - The marshalled [channel.MessageID] of the message
@@ -1666,15 +1954,65 @@ func (cm *ChannelsManager) DeleteMessage(channelIdBytes,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// EditMessage replaces the text of the targeted message. Users may edit their
+// own messages but only the channel admin can edit other user's messages. If
+// the user is not an admin of the channel or if they are not the sender of the
+// targetMessage, then the error [channels.NotAnAdminErr] is returned.
+//
+// The previous text of the message is kept by the event model as a revision.
+// If the new text is longer than the maximum message length, then the error
+// [channels.MessageTooLongErr] is returned.
+//
+// Clients will drop the edit if they do not recognize the target message.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - targetMessageIdBytes - The marshalled [channel.MessageID] of the message
+//     you want to edit.
+//   - newText - The new text of the message.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) EditMessage(channelIdBytes,
+	targetMessageIdBytes []byte, newText string, cmixParamsJSON []byte) (
+	[]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal message ID
+	targetedMessageID := cryptoMessage.ID{}
+	copy(targetedMessageID[:], targetMessageIdBytes)
+
+	// Send message edit
+	messageID, rnd, ephID, err := cm.api.EditMessage(
+		channelID, targetedMessageID, newText, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
 // PinMessage pins the target message to the top of a channel view for all users
-// in the specified channel. Only the channel admin can pin user messages; if
-// the user is not an admin of the channel, then the error
-// [channels.NotAnAdminErr] is returned.
+// in the specified channel. Only the channel admin and moderators can pin user
+// messages; if the user is not an admin or moderator of the channel, then the
+// error [channels.NotAnAdminErr] is returned.
 //
 // If undoAction is true, then the targeted message is unpinned.
 //
 // Clients will drop the pin if they do not recognize the target message.
 //
+// Pins sent by a moderator are not replayed by the lease system, so users who
+// join after the message has left the network will not see them.
+//
 // Parameters:
 //   - channelIdBytes - Marshalled bytes of channel [id.ID].
 //   - targetMessageIdBytes - The marshalled [channel.MessageID] of the message
@@ -1719,14 +2057,59 @@ func (cm *ChannelsManager) PinMessage(channelIdBytes,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// ClosePoll closes the poll so that votes sent after it are not counted. Only
+// the channel admin can close a poll; if the user is not an admin of the
+// channel, then the error [channels.NotAnAdminErr] is returned.
+//
+// If undoAction is true, then the poll is reopened.
+//
+// Clients will drop the close if they do not recognize the poll.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - pollMessageIdBytes - The marshalled [channel.MessageID] of the poll.
+//   - undoAction - Set to true to reopen the poll.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) ClosePoll(channelIdBytes, pollMessageIdBytes []byte,
+	undoAction bool, cmixParamsJSON []byte) ([]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal message ID
+	pollMessageID := cryptoMessage.ID{}
+	copy(pollMessageID[:], pollMessageIdBytes)
+
+	// Send poll close
+	messageID, rnd, ephID, err := cm.api.ClosePoll(
+		channelID, pollMessageID, undoAction, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
 // MuteUser is used to mute a user in a channel. Muting a user will cause all
 // future messages from the user being dropped on reception. Muted users are
-// also unable to send messages. Only the channel admin can mute a user; if the
-// user is not an admin of the channel, then the error [channels.NotAnAdminErr]
-// is returned.
+// also unable to send messages. Only the channel admin and moderators can mute
+// a user; if the user is not an admin or moderator of the channel, then the
+// error [channels.NotAnAdminErr] is returned.
 //
 // If undoAction is true, then the targeted user will be unmuted.
 //
+// Mutes sent by a moderator are not replayed by the lease system, so users who
+// join after the message has left the network will not see them.
+//
 // Parameters:
 //   - channelIdBytes - Marshalled bytes of channel [id.ID].
 //   - mutedUserPubKeyBytes - The [ed25519.PublicKey] of the user you want to
@@ -1772,6 +2155,220 @@ func (cm *ChannelsManager) MuteUser(channelIdBytes, mutedUserPubKeyBytes []byte,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// AppointModerator is used to appoint a user as a moderator of a channel.
+// Moderators may delete messages, pin messages, and mute users, but cannot
+// appoint other moderators. Only the channel admin can appoint a moderator; if
+// the user is not an admin of the channel, then the error
+// [channels.NotAnAdminErr] is returned.
+//
+// If undoAction is true, then the targeted user will no longer be a moderator.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - moderatorPubKeyBytes - The [ed25519.PublicKey] of the user you want to
+//     appoint.
+//   - undoAction - Set to true to revoke the moderator.
+//   - validUntilMS - The time, in milliseconds, that the user should remain a
+//     moderator. To remain a moderator indefinitely, use [ValidForever].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) AppointModerator(channelIdBytes,
+	moderatorPubKeyBytes []byte, undoAction bool, validUntilMS int,
+	cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal Ed25519 public key
+	if len(moderatorPubKeyBytes) != ed25519.PublicKeySize {
+		return nil, errors.Errorf(
+			"user ED25519 public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(moderatorPubKeyBytes))
+	}
+
+	// Calculate lease
+	validUntil := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		validUntil = channels.ValidForever
+	}
+
+	// Send message to appoint moderator
+	messageID, rnd, ephID, err := cm.api.AppointModerator(
+		channelID, moderatorPubKeyBytes, undoAction, validUntil, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SetSlowMode sets the minimum time between two messages sent by the same user
+// to the channel. Messages sent within the interval are rejected by the sender
+// and dropped by every other member. Moderators are not limited. Only the
+// channel admin can set slow mode; if the user is not an admin of the channel,
+// then the error [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - intervalMS - The minimum time, in milliseconds, between two messages
+//     from the same user. Set to 0 to turn slow mode off.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SetSlowMode(channelIdBytes []byte, intervalMS int,
+	cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send message to set slow mode
+	messageID, rnd, ephID, err := cm.api.SetSlowMode(channelID,
+		time.Duration(intervalMS)*time.Millisecond, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// UpdateChannelMetadata replaces the description, icon, topic, and rules of the
+// channel for every member. The name cannot be changed. Members keep the newest
+// update they receive and are notified via EventModel.UpdateChannelMetadata.
+// Only the channel admin can update the metadata; if the user is not an admin
+// of the channel, then the error [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - metadataJSON - JSON of [channels.ChannelMetadata]. The timestamp is
+//     ignored and the icon may be at most [channels.MaxChannelIconSize] bytes.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) UpdateChannelMetadata(channelIdBytes,
+	metadataJSON, cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata channels.ChannelMetadata
+	if err = json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal channel metadata")
+	}
+
+	// Send message to update the metadata
+	messageID, rnd, ephID, err :=
+		cm.api.UpdateChannelMetadata(channelID, metadata, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// GetChannelMetadata returns the newest metadata of the channel. If the
+// metadata has never been updated, then the description the channel was created
+// with is returned with a zero timestamp.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - []byte - JSON of [channels.ChannelMetadata].
+//
+// Example return:
+//
+//	{"description":"Discussion of the xx network","icon":"iVBORw0KGgo=","topic":"Release 4.7","rules":"Be kind.","timestamp":"2023-05-02T12:04:10.123456789-07:00"}
+func (cm *ChannelsManager) GetChannelMetadata(
+	channelIDBytes []byte) ([]byte, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := cm.api.GetChannelMetadata(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(metadata)
+}
+
+// BanUser is used to ban a user from a channel. Banning a user mutes them,
+// hides every message they have sent to the channel, and records the reason
+// for the ban. Only the channel admin can ban a user; if the user is not an
+// admin of the channel, then the error [channels.NotAnAdminErr] is returned.
+//
+// If undoAction is true, then the targeted user will be unbanned and, unless
+// they were muted before the ban, unmuted. Messages hidden by the ban remain
+// hidden.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - bannedUserPubKeyBytes - The [ed25519.PublicKey] of the user you want to
+//     ban.
+//   - reason - The reason for the ban. It may be at most
+//     [channels.MaxBanReasonLength] bytes.
+//   - undoAction - Set to true to unban the user.
+//   - validUntilMS - The time, in milliseconds, that the user should be banned.
+//     To remain banned indefinitely, use [ValidForever].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) BanUser(channelIdBytes, bannedUserPubKeyBytes []byte,
+	reason string, undoAction bool, validUntilMS int, cmixParamsJSON []byte) (
+	[]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal Ed25519 public key
+	if len(bannedUserPubKeyBytes) != ed25519.PublicKeySize {
+		return nil, errors.Errorf(
+			"user ED25519 public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(bannedUserPubKeyBytes))
+	}
+
+	// Calculate lease
+	validUntil := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		validUntil = channels.ValidForever
+	}
+
+	// Send message to ban user
+	messageID, rnd, ephID, err := cm.api.BanUser(channelID,
+		bannedUserPubKeyBytes, reason, undoAction, validUntil, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
 // parseChannelsParameters is a helper function for the Send functions. It
 // parses the channel ID and the passed in parameters into their respective
 // objects. These objects are passed into the API via the internal send
@@ -1908,6 +2505,31 @@ func IsNicknameValid(nickname string) error {
 	return nicknames.IsValid(nickname)
 }
 
+// ParseChannelText returns the mentions, channel links, URLs, and markdown
+// formatting found in the text of a channel message. Mentions are written as
+// "<@pubKey>" and channel links as "<#channelID>", where each is base 64
+// encoded. The offsets of each span are in bytes of the UTF-8 encoded text.
+//
+// Parameters:
+//   - text - The text of a message.
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.TextSpan].
+//
+// Example return:
+//
+//	[
+//	  {"type":10,"start":0,"end":8,"value":"bold"},
+//	  {"type":3,"start":9,"end":27,"value":"https://xx.network"}
+//	]
+func ParseChannelText(text string) ([]byte, error) {
+	spans := channels.ParseText(text)
+	if spans == nil {
+		spans = []channels.TextSpan{}
+	}
+	return json.Marshal(spans)
+}
+
 // Muted returns true if the user is currently muted in the given channel.
 //
 // Parameters:
@@ -1946,6 +2568,122 @@ func (cm *ChannelsManager) GetMutedUsers(channelIDBytes []byte) ([]byte, error)
 	return json.Marshal(cm.api.GetMutedUsers(channelID))
 }
 
+// GetBannedUsers returns the list of banned users in the channel, with the
+// reason and time of each ban. If there are no banned users or if the channel
+// does not exist, an empty list is returned.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.BannedUser]. Look below for an
+//     example.
+//
+// Example return:
+//
+//	[{"pubKey":"k2IrybDXjJtqxjS6Tx/6m3bXvT/4zFYOJnACNWTvESE=","reason":"spam","timestamp":"2023-05-05T16:13:34.497158-07:00","muted":true}]
+func (cm *ChannelsManager) GetBannedUsers(
+	channelIDBytes []byte) ([]byte, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(cm.api.GetBannedUsers(channelID))
+}
+
+// IsModerator returns true if the user is a moderator in the given channel.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//   - pubKeyBytes - The [ed25519.PublicKey] of the user to check.
+//
+// Returns:
+//   - bool - True if the user is a moderator of the channel and false
+//     otherwise.
+func (cm *ChannelsManager) IsModerator(
+	channelIDBytes, pubKeyBytes []byte) (bool, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return false, err
+	}
+	return cm.api.IsModerator(channelID, pubKeyBytes), nil
+}
+
+// GetModerators returns the list of the public keys for each moderator in the
+// channel. If there are no moderators or if the channel does not exist, an
+// empty list is returned.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - []byte - JSON of an array of ed25519.PublicKey. Look below for an
+//     example.
+//
+// Example return:
+//
+//	["k2IrybDXjJtqxjS6Tx/6m3bXvT/4zFYOJnACNWTvESE=","ocELv7KyeCskLz4cm0klLWhmFLYvQL2FMDco79GTXYw="]
+func (cm *ChannelsManager) GetModerators(channelIDBytes []byte) ([]byte, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(cm.api.GetModerators(channelID))
+}
+
+// GetSlowMode returns the slow mode interval of the channel.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - int - The slow mode interval, in milliseconds. Returns 0 if slow mode is
+//     off.
+func (cm *ChannelsManager) GetSlowMode(channelIDBytes []byte) (int, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return 0, err
+	}
+	return int(cm.api.GetSlowMode(channelID).Milliseconds()), nil
+}
+
+// FloodFilter describes the local flood filter, which hides messages from a
+// user that sends more than MaxMessages messages to a channel within WindowMS
+// milliseconds.
+//
+// Example JSON:
+//
+//	{"maxMessages":5,"windowMS":10000}
+type FloodFilter struct {
+	MaxMessages int `json:"maxMessages"`
+	WindowMS    int `json:"windowMS"`
+}
+
+// SetFloodFilter sets the local flood filter, which hides messages from a user
+// that sends more than maxMessages messages to a channel within the window. The
+// filter applies to every channel and is not shared with other members.
+//
+// Parameters:
+//   - maxMessages - The number of messages a user can send to a channel within
+//     the window before their messages are hidden. Set to 0 to disable the
+//     filter.
+//   - windowMS - The window, in milliseconds. Set to 0 to disable the filter.
+func (cm *ChannelsManager) SetFloodFilter(maxMessages, windowMS int) error {
+	return cm.api.SetFloodFilter(
+		maxMessages, time.Duration(windowMS)*time.Millisecond)
+}
+
+// GetFloodFilter returns the settings of the local flood filter.
+//
+// Returns:
+//   - []byte - JSON of [FloodFilter].
+func (cm *ChannelsManager) GetFloodFilter() ([]byte, error) {
+	maxMessages, window := cm.api.GetFloodFilter()
+	return json.Marshal(FloodFilter{maxMessages, int(window.Milliseconds())})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Notifications                                                              //
 ////////////////////////////////////////////////////////////////////////////////
@@ -2237,6 +2975,113 @@ func (cm *ChannelsManager) DeleteChannelAdminKey(channelIdBytes []byte) error {
 	return cm.api.DeleteChannelAdminKey(channelID)
 }
 
+// RotateChannelAdminKey generates a new admin key for the channel and sends a
+// key rotation message, signed with the current admin key, to the channel.
+// Every member moves to the returned channel, which only differs from the
+// current channel in its admin key and ID. The move is reported to the UI as
+// an [AdminKeyRotated] event.
+//
+// The new channel keeps the secret of the current channel, so former members,
+// including banned users, can still derive it and read its messages. Rotation
+// does not remove anyone from the channel.
+//
+// Only the channel admin can rotate the key; if the user is not an admin of
+// the channel, then the error [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - string - The pretty print of the new channel.
+func (cm *ChannelsManager) RotateChannelAdminKey(
+	channelIdBytes, cmixParamsJSON []byte) (string, error) {
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return "", err
+	}
+
+	ch, _, _, _, err := cm.api.RotateChannelAdminKey(channelID, params.CMIX)
+	if err != nil {
+		return "", err
+	}
+
+	return ch.PrettyPrint(), nil
+}
+
+// TransferChannelOwnership rotates the admin key of the channel and sends the
+// new key to the new owner in a direct message. Once the round the key was
+// sent in succeeds, the key is deleted from this user's storage so that the new
+// owner is the only admin of the new channel. If the key fails to be delivered,
+// then an error is returned and the key is kept, so this user remains the admin
+// of the new channel.
+//
+// The message is never stored by either side and is not passed to the
+// [DMReceiver]. The new owner's [DMClient] hands the key to the
+// [ChannelsManager] registered with [ChannelsManager.ReceiveChannelAdminKeys],
+// which imports it once the new channel is joined. The new owner must have
+// accepted direct messages from this user.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - newOwnerPubKeyBytes - The bytes of the public key of the new owner's
+//     ED25519 signing key.
+//   - newOwnerToken - The DM token of the new owner.
+//   - dmClientID - The ID of the [DMClient] used to send the key.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - string - The pretty print of the new channel.
+func (cm *ChannelsManager) TransferChannelOwnership(channelIdBytes,
+	newOwnerPubKeyBytes []byte, newOwnerToken int32, dmClientID int,
+	cmixParamsJSON []byte) (string, error) {
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return "", err
+	}
+
+	if len(newOwnerPubKeyBytes) != ed25519.PublicKeySize {
+		return "", errors.Errorf(
+			"user ED25519 public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(newOwnerPubKeyBytes))
+	}
+
+	dmc, err := dmClients.get(dmClientID)
+	if err != nil {
+		return "", err
+	}
+
+	ch, err := cm.api.TransferChannelOwnership(channelID,
+		newOwnerPubKeyBytes, uint32(newOwnerToken), dmc.api, params.CMIX)
+	if err != nil {
+		return "", err
+	}
+
+	return ch.PrettyPrint(), nil
+}
+
+// ReceiveChannelAdminKeys registers the channel manager to receive the channel
+// admin keys transferred to this user with
+// [ChannelsManager.TransferChannelOwnership] over the given [DMClient]. Keys
+// received by the [DMClient] before registration are held in memory and
+// imported on registration.
+//
+// Parameters:
+//   - dmClientID - The ID of the [DMClient] that receives the keys.
+func (cm *ChannelsManager) ReceiveChannelAdminKeys(dmClientID int) error {
+	dmc, err := dmClients.get(dmClientID)
+	if err != nil {
+		return err
+	}
+
+	dmc.api.RegisterAdminKeyReceiver(cm.api)
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Channel Receiving Logic and Callback Registration                          //
 ////////////////////////////////////////////////////////////////////////////////
@@ -2568,6 +3413,92 @@ type EventModel interface {
 	//    muted in.
 	//  - pubKey - The Ed25519 public key of the user that is muted or unmuted.
 	MuteUser(channelID, pubkey []byte, unmute bool)
+
+	// EditMessage is called whenever the text of the message with the given
+	// [channel.MessageID] is edited. The implementation must keep every edit as
+	// a revision and display the text of the one with the newest timestamp.
+	// Edits may be received more than once and out of order.
+	//
+	// Parameters:
+	//  - messageID - The bytes of the [channel.MessageID] of the edited
+	//    message.
+	//  - editJSON - JSON of [channels.MessageEdit].
+	//
+	// Example [channels.MessageEdit] JSON:
+	//  {
+	//    "editID": "Tz7qyXwOuyTVdQx9vSLqmByNUPYxQmSv4P4uZ7JK+2g=",
+	//    "text": "edited text",
+	//    "timestamp": "2023-05-02T12:04:10.123456789-07:00",
+	//    "round": 8452
+	//  }
+	//
+	// Returns:
+	//  - int64 - The UUID of the edited message.
+	//  - Returns an error if the message cannot be edited. It must return the
+	//	  error from GetNoMessageErr if the message does not exist.
+	EditMessage(messageID, editJSON []byte) (int64, error)
+
+	// ReceivePollVote is called whenever a vote is received for the poll with
+	// the given [channel.MessageID]. Each user has at most one vote per poll; a
+	// vote replaces the previous vote from the same user if it has a newer
+	// timestamp. Votes sent after the poll was closed must be ignored. Votes
+	// may be received more than once and out of order.
+	//
+	// The poll itself is received via ReceiveMessage with the message type
+	// [channels.Poll] and its text is the JSON of [channels.CMIXChannelPoll].
+	//
+	// Parameters:
+	//  - pollID - The bytes of the [channel.MessageID] of the poll.
+	//  - voteJSON - JSON of [channels.ModelPollVote].
+	//
+	// Example [channels.ModelPollVote] JSON:
+	//  {
+	//    "messageID": "Tz7qyXwOuyTVdQx9vSLqmByNUPYxQmSv4P4uZ7JK+2g=",
+	//    "pubKey": "wT9fX9uaVHYV0qJpBsfpDoByd+G1BpzY0O5VdcpTIC4=",
+	//    "option": 1,
+	//    "timestamp": "2023-05-02T12:04:10.123456789-07:00",
+	//    "round": 8452
+	//  }
+	//
+	// Returns:
+	//  - int64 - The UUID of the poll.
+	//  - Returns an error if the vote cannot be saved. It must return the
+	//	  error from GetNoMessageErr if the poll does not exist.
+	ReceivePollVote(pollID, voteJSON []byte) (int64, error)
+
+	// ClosePoll is called whenever the channel admin closes or reopens the poll
+	// with the given [channel.MessageID]. Votes sent at or after the timestamp
+	// must not be counted.
+	//
+	// Parameters:
+	//  - pollID - The bytes of the [channel.MessageID] of the poll.
+	//  - timestamp - Time the poll was closed, in Unix nanoseconds.
+	//  - reopen - Set to true if the poll is being reopened.
+	//
+	// Returns:
+	//  - int64 - The UUID of the poll.
+	//  - Returns an error if the poll cannot be updated. It must return the
+	//	  error from GetNoMessageErr if the poll does not exist.
+	ClosePoll(pollID []byte, timestamp int64, reopen bool) (int64, error)
+
+	// UpdateChannelMetadata is called whenever the channel admin updates the
+	// description, icon, topic, or rules of the channel. It is only called
+	// with an update newer than the last one, so the stored metadata may be
+	// replaced as is.
+	//
+	// Parameters:
+	//  - channelID - Marshalled bytes of the channel [id.ID].
+	//  - metadataJSON - JSON of [channels.ChannelMetadata].
+	//
+	// Example [channels.ChannelMetadata] JSON:
+	//  {
+	//    "description": "Discussion of the xx network",
+	//    "icon": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==",
+	//    "topic": "Release 4.7",
+	//    "rules": "Be kind.",
+	//    "timestamp": "2023-05-02T12:04:10.123456789-07:00"
+	//  }
+	UpdateChannelMetadata(channelID, metadataJSON []byte)
 }
 
 // GetNoMessageErr returns the error channels.NoMessageErr, which must be
@@ -2857,6 +3788,66 @@ func (tem *toEventModel) MuteUser(
 	tem.em.MuteUser(channelID.Marshal(), pubKey, unmute)
 }
 
+// EditMessage is called whenever the text of the message with the given
+// [channel.MessageID] is edited.
+//
+// Returns an error if the message cannot be edited. It must return the error
+// from GetNoMessageErr if the message does not exist.
+func (tem *toEventModel) EditMessage(
+	messageID cryptoMessage.ID, edit channels.MessageEdit) (uint64, error) {
+	editJSON, err := json.Marshal(edit)
+	if err != nil {
+		return 0, errors.Errorf(
+			"failed to JSON marshal MessageEdit: %+v", err)
+	}
+
+	uuid, err := tem.em.EditMessage(messageID.Marshal(), editJSON)
+	return uint64(uuid), err
+}
+
+// ReceivePollVote is called whenever a vote is received for the poll with the
+// given [channel.MessageID].
+//
+// Returns an error if the vote cannot be saved. It must return the error from
+// GetNoMessageErr if the poll does not exist.
+func (tem *toEventModel) ReceivePollVote(
+	pollID cryptoMessage.ID, vote channels.ModelPollVote) (uint64, error) {
+	voteJSON, err := json.Marshal(vote)
+	if err != nil {
+		return 0, errors.Errorf(
+			"failed to JSON marshal ModelPollVote: %+v", err)
+	}
+
+	uuid, err := tem.em.ReceivePollVote(pollID.Marshal(), voteJSON)
+	return uint64(uuid), err
+}
+
+// ClosePoll is called whenever the channel admin closes or reopens the poll
+// with the given [channel.MessageID].
+//
+// Returns an error if the poll cannot be updated. It must return the error
+// from GetNoMessageErr if the poll does not exist.
+func (tem *toEventModel) ClosePoll(pollID cryptoMessage.ID,
+	timestamp time.Time, reopen bool) (uint64, error) {
+	uuid, err := tem.em.ClosePoll(pollID.Marshal(), timestamp.UnixNano(), reopen)
+	return uint64(uuid), err
+}
+
+// UpdateChannelMetadata is called whenever the channel admin updates the
+// metadata of the channel.
+func (tem *toEventModel) UpdateChannelMetadata(
+	channelID *id.ID, metadata channels.ChannelMetadata) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		jww.ERROR.Printf(
+			"Failed to JSON marshal ChannelMetadata for channel %s: %+v",
+			channelID, err)
+		return
+	}
+
+	tem.em.UpdateChannelMetadata(channelID.Marshal(), metadataJSON)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Extension Builder Tracker                                                  //
 ////////////////////////////////////////////////////////////////////////////////
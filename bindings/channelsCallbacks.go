@@ -54,6 +54,15 @@ const (
 
 	// ChannelUpdate indicates the data is [ChannelUpdateJSON].
 	ChannelUpdate int64 = 8000
+
+	// UserTyping indicates the data is [UserTypingJSON].
+	UserTyping int64 = 9000
+
+	// AdminKeyRotated indicates the data is [AdminKeyRotatedJSON].
+	AdminKeyRotated int64 = 10000
+
+	// ScheduledSendUpdate indicates the data is [ScheduledSendUpdateJSON].
+	ScheduledSendUpdate int64 = 11000
 )
 
 // channelUICallbacks is a simple wrapper for [channels.UiCallbacks].
@@ -104,6 +113,14 @@ func (cuiCB *channelUICallbacks) AdminKeysUpdate(chID *id.ID, isAdmin bool) {
 	})
 }
 
+func (cuiCB *channelUICallbacks) AdminKeyRotated(
+	oldChannelID, newChannelID *id.ID) {
+	cuiCB.eventUpdate(AdminKeyRotated, AdminKeyRotatedJSON{
+		OldChannelID: oldChannelID,
+		NewChannelID: newChannelID,
+	})
+}
+
 func (cuiCB *channelUICallbacks) DmTokenUpdate(chID *id.ID, sendToken bool) {
 	cuiCB.eventUpdate(DmTokenUpdate, DmTokenUpdateJSON{
 		ChannelId: chID,
@@ -142,6 +159,31 @@ func (cuiCB *channelUICallbacks) MessageDeleted(messageID message.ID) {
 	})
 }
 
+func (cuiCB *channelUICallbacks) UserTyping(channelID *id.ID,
+	pubKey ed25519.PublicKey, codeset uint8, nickname string, typing bool) {
+	cuiCB.eventUpdate(UserTyping, UserTypingJSON{
+		ChannelID: channelID,
+		PubKey:    pubKey,
+		Codeset:   codeset,
+		Nickname:  nickname,
+		Typing:    typing,
+	})
+}
+
+func (cuiCB *channelUICallbacks) ScheduledSendUpdate(sendID uint64,
+	channelID *id.ID, messageID message.ID, err error) {
+	ssu := ScheduledSendUpdateJSON{
+		SendID:    int64(sendID),
+		ChannelID: channelID,
+	}
+	if err != nil {
+		ssu.Error = err.Error()
+	} else {
+		ssu.MessageID = &messageID
+	}
+	cuiCB.eventUpdate(ScheduledSendUpdate, ssu)
+}
+
 func unmarshalPingsJson(b []byte) ([]ed25519.PublicKey, error) {
 	var pings []ed25519.PublicKey
 	if b != nil && len(b) > 0 {
@@ -257,6 +299,20 @@ type AdminKeysUpdateJSON struct {
 	IsAdmin   bool   `json:"IsAdmin"`
 }
 
+// AdminKeyRotatedJSON describes when the admin key of a channel is rotated and
+// the user is moved from the old channel to the new channel.
+//
+// Example JSON:
+//
+//	{
+//	  "oldChannelID":"KdkEjm+OfQuK4AyZGAqh+XPQaLfRhsO5d2NT1EIScyJX",
+//	  "newChannelID":"jTzRzdXW3RO9ta5cSB0S+Ix1tMa4JWG9IbSgmoSYMfQD"
+//	}
+type AdminKeyRotatedJSON struct {
+	OldChannelID *id.ID `json:"oldChannelID"`
+	NewChannelID *id.ID `json:"newChannelID"`
+}
+
 // DmTokenUpdateJSON describes when the sending of dm tokens is enabled or
 // disabled on a specific channel
 //
@@ -325,3 +381,41 @@ type UserMutedJSON struct {
 type MessageDeletedJSON struct {
 	MessageID message.ID `json:"messageID"`
 }
+
+// UserTypingJSON is returned any time a user in a channel starts or stops
+// typing. The UI should stop showing the user as typing if no new indicator is
+// received from them within [channels.TypingTimeout].
+//
+// Example JSON:
+//
+//	{
+//	  "channelID":"YSc2bDijXIVhmIsJk2OZQjU9ei2Dn6MS8tOpXlIaUpSV",
+//	  "pubKey":"hClzdWkMI+LM7KDFxC/iuyIc0oiMzcBXBFgH0haZAjc=",
+//	  "codeset":0,
+//	  "nickname":"Alice",
+//	  "typing":true
+//	}
+type UserTypingJSON struct {
+	ChannelID *id.ID            `json:"channelID"`
+	PubKey    ed25519.PublicKey `json:"pubKey"`
+	Codeset   uint8             `json:"codeset"`
+	Nickname  string            `json:"nickname"`
+	Typing    bool              `json:"typing"`
+}
+
+// ScheduledSendUpdateJSON is returned when a scheduled send is sent or fails.
+// If the send failed, error contains the reason and messageID is omitted.
+//
+// Example JSON:
+//
+//	{
+//	  "sendID":3,
+//	  "channelID":"YSc2bDijXIVhmIsJk2OZQjU9ei2Dn6MS8tOpXlIaUpSV",
+//	  "messageID":"i9b7tL5sUmObxqW1LApC9H/yvnQzsRfq7yc8SCBtlK0="
+//	}
+type ScheduledSendUpdateJSON struct {
+	SendID    int64       `json:"sendID"`
+	ChannelID *id.ID      `json:"channelID"`
+	MessageID *message.ID `json:"messageID,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
@@ -239,3 +239,48 @@ func Test_BuildJSON_MessageDeletedJSON(t *testing.T) {
 		fmt.Printf("//  %s\n", data)
 	}
 }
+
+// Produces example JSON of UserTypingJSON to be used for documentation.
+func Test_BuildJSON_UserTypingJSON(t *testing.T) {
+	rng := rand.New(rand.NewSource(72928915))
+
+	channelID, _ := id.NewRandomID(rng, id.User)
+	pubkey, _, _ := ed25519.GenerateKey(rng)
+
+	jsonable := UserTypingJSON{
+		ChannelID: channelID,
+		PubKey:    pubkey,
+		Codeset:   0,
+		Nickname:  "Alice",
+		Typing:    true,
+	}
+
+	data, err := json.MarshalIndent(jsonable, "//  ", "  ")
+	if err != nil {
+		t.Errorf("Failed to JSON %T: %+v", jsonable, err)
+	} else {
+		fmt.Printf("//  %s\n", data)
+	}
+}
+
+// Produces example JSON of ScheduledSendUpdateJSON to be used for
+// documentation.
+func Test_BuildJSON_ScheduledSendUpdateJSON(t *testing.T) {
+	rng := rand.New(rand.NewSource(72928915))
+
+	channelID, _ := id.NewRandomID(rng, id.User)
+	messageID := message.DeriveChannelMessageID(channelID, 5, []byte("hello"))
+
+	jsonable := ScheduledSendUpdateJSON{
+		SendID:    3,
+		ChannelID: channelID,
+		MessageID: &messageID,
+	}
+
+	data, err := json.MarshalIndent(jsonable, "//  ", "  ")
+	if err != nil {
+		t.Errorf("Failed to JSON %T: %+v", jsonable, err)
+	} else {
+		fmt.Printf("//  %s\n", data)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
@@ -113,6 +114,12 @@ func NewDMClient(cmixID, notificationsID int, privateIdentity []byte,
 		return nil, err
 	}
 
+	// Add the retention thread to services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start DM retention service")
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -175,6 +182,12 @@ func NewDMClientWithGoEventModel(cmixID, notificationsID int,
 		return nil, err
 	}
 
+	// Add the retention thread to services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start DM retention service")
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -239,6 +252,12 @@ func NewDmManagerMobile(cmixID, notificationsID int, privateIdentity []byte,
 		return nil, err
 	}
 
+	// Add the retention thread to services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start DM retention service")
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -408,6 +427,55 @@ func (dmc *DMClient) GetBlockedPartners() []byte {
 	return blockedJSON
 }
 
+// AcceptMessageRequest accepts the pending message request from the partner.
+// After accepting, all messages from the partner are received and
+// notifications are enabled.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) AcceptMessageRequest(partnerPubKey []byte) error {
+	return dmc.api.AcceptMessageRequest(partnerPubKey)
+}
+
+// DeclineMessageRequest declines the pending message request from the partner
+// and removes it from the message request inbox. If the partner sends another
+// message, a new request is created. To stop receiving messages from the
+// partner, use [DMClient.BlockPartner] instead.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) DeclineMessageRequest(partnerPubKey []byte) error {
+	return dmc.api.DeclineMessageRequest(partnerPubKey)
+}
+
+// IsMessageRequest indicates if the partner has a pending message request.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) IsMessageRequest(partnerPubKey []byte) bool {
+	return dmc.api.IsMessageRequest(partnerPubKey)
+}
+
+// GetMessageRequests returns all partners with a pending message request.
+//
+// Returns:
+//   - []byte - JSON of of an array of [ed25519.PublicKey].
+//
+// Example return:
+//
+//	[
+//	  "IuNqK7/9VPEcj1mM3b3vwtEqR5JGqcOFIvR9+pHkU/0=",
+//	  "2wv7NgA/xmXtNa6tQuM3JgGkjXXZw2NPy8FYUlq8gEU="
+//	]
+func (dmc *DMClient) GetMessageRequests() []byte {
+	requestsJSON, err := json.Marshal(dmc.api.GetMessageRequests())
+	if err != nil {
+		jww.FATAL.Panicf(
+			"[DM] Failed to JSON marshal message request list: %+v", err)
+	}
+	return requestsJSON
+}
+
 // GetNotificationLevel returns the notification level for the given channel.
 //
 // Parameters:
@@ -432,6 +500,107 @@ func (dmc *DMClient) SetMobileNotificationsLevel(
 		partnerPubKey, dm.NotificationLevel(level))
 }
 
+// SetReadReceipts enables or disables sending read receipts to the partner.
+// Read receipts are enabled for all partners by default, but are only sent when
+// [DMClient.MarkRead] is called.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//   - enabled - Set to true to send read receipts to the partner.
+func (dmc *DMClient) SetReadReceipts(partnerPubKey []byte, enabled bool) {
+	dmc.api.SetReadReceipts(partnerPubKey, enabled)
+}
+
+// ReadReceiptsEnabled returns true if read receipts are sent to the partner.
+// It returns false if read receipts are disabled for the partner or if the
+// partner is blocked or has a pending message request.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) ReadReceiptsEnabled(partnerPubKey []byte) bool {
+	return dmc.api.ReadReceiptsEnabled(partnerPubKey)
+}
+
+// GetRetention returns the retention timer set on the conversation with the
+// partner, in milliseconds. Returns zero if messages are kept forever.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) GetRetention(partnerPubKey []byte) int64 {
+	return dmc.api.GetRetention(partnerPubKey).Milliseconds()
+}
+
+// CreateGroup creates a new group DM with the given name and members. The
+// group is stored in the [DMReceiver] as a conversation with the group ID as
+// its public key. Members learn about the group when the first message is sent
+// to it.
+//
+// Parameters:
+//   - name - The name of the group.
+//   - membersJSON - JSON of an array of [dm.GroupMember]. The user must not be
+//     included. At most [dm.MaxGroupMembers] members are allowed.
+//
+// Example membersJSON:
+//
+//	[
+//	  {"pub_key": "IuNqK7/9VPEcj1mM3b3vwtEqR5JGqcOFIvR9+pHkU/0=", "token": 1234},
+//	  {"pub_key": "2wv7NgA/xmXtNa6tQuM3JgGkjXXZw2NPy8FYUlq8gEU=", "token": 5678}
+//	]
+//
+// Returns:
+//   - []byte - The ID of the group.
+func (dmc *DMClient) CreateGroup(name string, membersJSON []byte) ([]byte, error) {
+	var members []dm.GroupMember
+	if err := json.Unmarshal(membersJSON, &members); err != nil {
+		return nil, err
+	}
+	return dmc.api.CreateGroup(name, members)
+}
+
+// EditGroup replaces the name, members, and admins of the group DM. Only the
+// creator and the admins of the group can edit it. Members learn about the
+// change when the next message is sent to the group.
+//
+// Parameters:
+//   - groupID - The ID of the group.
+//   - name - The new name of the group.
+//   - membersJSON - JSON of an array of [dm.GroupMember]. The user must not be
+//     included. At most [dm.MaxGroupMembers] members are allowed.
+//   - adminsJSON - JSON of an array of Ed25519 public keys of the admins. Each
+//     admin must be a member or the user. The creator is always an admin and
+//     does not need to be included.
+//
+// Example adminsJSON:
+//
+//	["IuNqK7/9VPEcj1mM3b3vwtEqR5JGqcOFIvR9+pHkU/0="]
+func (dmc *DMClient) EditGroup(
+	groupID []byte, name string, membersJSON, adminsJSON []byte) error {
+	var members []dm.GroupMember
+	if err := json.Unmarshal(membersJSON, &members); err != nil {
+		return err
+	}
+	var admins []ed25519.PublicKey
+	if err := json.Unmarshal(adminsJSON, &admins); err != nil {
+		return err
+	}
+	return dmc.api.EditGroup(groupID, name, members, admins)
+}
+
+// GetGroup returns the group DM with the given ID.
+//
+// Parameters:
+//   - groupID - The ID of the group.
+//
+// Returns:
+//   - []byte - JSON of [dm.ModelGroup].
+func (dmc *DMClient) GetGroup(groupID []byte) ([]byte, error) {
+	group, err := dmc.api.GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(group)
+}
+
 // GetDmNotificationReportsForMe checks the notification data against the filter
 // list to determine which notifications belong to the user. A list of
 // notification reports is returned detailing all notifications for the user.
@@ -664,6 +833,159 @@ func (dmc *DMClient) SendSilent(partnerPubKeyBytes []byte,
 	return constructDMSendReport(msgID, rnd.ID, ephID)
 }
 
+// SendTyping is used to signal to a DM partner that the user started or
+// stopped typing. Typing indicators are never stored and are delivered to the
+// partner via [DmCallbacks.EventUpdate] with the event type [DmUserTyping].
+//
+// While the user is typing, SendTyping should be called with typing set to
+// true every [dm.TypingInterval]. Calling it more often returns
+// [dm.TypingRateLimitErr], which can be ignored. Call it with typing set to
+// false once the user stops typing.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - typing - True if the user is typing and false if they stopped.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - A JSON marshalled [ChannelSendReport].
+func (dmc *DMClient) SendTyping(partnerPubKeyBytes []byte,
+	partnerToken int32, typing bool, cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err := dmc.api.SendTyping(partnerPubKey,
+		uint32(partnerToken), typing, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
+// ScheduleText queues a text message to be sent to a DM partner at a future
+// time. The queue is saved to storage so that messages are sent after a
+// restart. When the message is sent or fails, [DmCallbacks.EventUpdate] is
+// called with the event type [DmScheduledSendUpdate].
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - message - The contents of the message. The message should be at most 510
+//     bytes.
+//   - sendAtNS - The time to send the message at, in Unix nanoseconds. It must
+//     be in the future.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [dm.ScheduledSend].
+func (dmc *DMClient) ScheduleText(partnerPubKeyBytes []byte,
+	partnerToken int32, message string, sendAtNS int64,
+	cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := dmc.api.ScheduleText(partnerPubKey, uint32(partnerToken),
+		message, time.Unix(0, sendAtNS), params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ss)
+}
+
+// ScheduleSend queues a raw message to be sent to a DM partner at a future
+// time. See [DMClient.Send] for details on the message and
+// [DMClient.ScheduleText] for details on scheduling.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - messageType - The message type of the message. This will be a valid
+//     [dm.MessageType].
+//   - plaintext - The contents of the message.
+//   - sendAtNS - The time to send the message at, in Unix nanoseconds. It must
+//     be in the future.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [dm.ScheduledSend].
+func (dmc *DMClient) ScheduleSend(partnerPubKeyBytes []byte,
+	partnerToken int32, messageType int, plaintext []byte, sendAtNS int64,
+	cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := dmc.api.ScheduleSend(partnerPubKey, uint32(partnerToken),
+		dm.MessageType(messageType), plaintext, time.Unix(0, sendAtNS),
+		params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ss)
+}
+
+// GetScheduledSends returns the messages queued to be sent to the DM partner,
+// sorted by the time they are sent.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key. If empty, the messages queued for all partners are
+//     returned.
+//
+// Returns:
+//   - []byte - JSON of an array of [dm.ScheduledSend].
+func (dmc *DMClient) GetScheduledSends(partnerPubKeyBytes []byte) ([]byte, error) {
+	var partnerPubKey ed25519.PublicKey
+	if len(partnerPubKeyBytes) > 0 {
+		partnerPubKey = partnerPubKeyBytes
+	}
+	return json.Marshal(dmc.api.GetScheduledSends(partnerPubKey))
+}
+
+// CancelScheduledSend removes the message from the queue so that it is never
+// sent. Returns [dm.ScheduledSendNotFoundErr] if the message does not exist or
+// was already sent.
+//
+// Parameters:
+//   - sendID - The ID of the [dm.ScheduledSend].
+func (dmc *DMClient) CancelScheduledSend(sendID int64) error {
+	return dmc.api.CancelScheduledSend(uint64(sendID))
+}
+
+// RescheduleSend changes the time the queued message is sent at. Returns
+// [dm.ScheduledSendNotFoundErr] if the message does not exist or was already
+// sent.
+//
+// Parameters:
+//   - sendID - The ID of the [dm.ScheduledSend].
+//   - sendAtNS - The new time to send the message at, in Unix nanoseconds. It
+//     must be in the future.
+func (dmc *DMClient) RescheduleSend(sendID, sendAtNS int64) error {
+	return dmc.api.RescheduleSend(uint64(sendID), time.Unix(0, sendAtNS))
+}
+
 // SendInvite is used to send to a DM partner an invitation to another
 // channel.
 //
@@ -759,6 +1081,96 @@ func (dmc *DMClient) DeleteMessage(partnerPubKeyBytes []byte, partnerToken int32
 	return constructDMSendReport(msgID, rnd.ID, ephID)
 }
 
+// MarkRead marks all messages received from the partner, up to and including
+// the given message, as read. The update is delivered to the
+// [DMReceiver.UpdateReadStatus]. If read receipts are enabled for the partner,
+// then a read receipt is sent to them. Read receipts are never sent to blocked
+// partners.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - upToMessageIdBytes - The bytes of the [message.ID] of the last message
+//     read.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - A JSON marshalled [ChannelSendReport] of the read receipt. It
+//     is nil if no read receipt was sent.
+func (dmc *DMClient) MarkRead(partnerPubKeyBytes []byte, partnerToken int32,
+	upToMessageIdBytes []byte, cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+
+	// Unmarshal message ID
+	upTo, err := message.UnmarshalID(upToMessageIdBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal cmix params
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err := dmc.api.MarkRead(
+		partnerPubKey, uint32(partnerToken), upTo, params.CMIX)
+	if err != nil {
+		return nil, err
+	} else if msgID == (message.ID{}) {
+		return nil, nil
+	}
+
+	// Construct send report
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
+// SetRetention sets the retention timer of the conversation with the partner
+// and announces it to them. Every message sent or received in the conversation
+// while the timer is set is deleted once it is older than the timer. Expired
+// messages are removed via [DMReceiver.DeleteMessage].
+//
+// The announcement is received via [DMReceiver.Receive] with a
+// [dm.MessageType] of value [dm.RetentionType]. The message will be JSON
+// encoded. Example retention JSON:
+//
+//	{
+//	  "retention": 86400000000000
+//	}
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - retentionMS - The retention timer, in milliseconds. Set to zero to keep
+//     messages forever. Otherwise, it must be at least [dm.MinRetention].
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - A JSON marshalled [ChannelSendReport].
+func (dmc *DMClient) SetRetention(partnerPubKeyBytes []byte,
+	partnerToken int32, retentionMS int64, cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+	retention := time.Duration(retentionMS) * time.Millisecond
+
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err := dmc.api.SetRetention(
+		partnerPubKey, uint32(partnerToken), retention, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
 // Send is used to send a raw message. In general, it
 // should be wrapped in a function that defines the wire protocol.
 //
@@ -810,6 +1222,101 @@ func (dmc *DMClient) Send(partnerPubKeyBytes []byte,
 	return constructDMSendReport(msgID, rnd.ID, ephID)
 }
 
+// SendGroupText is used to send a formatted message to a group DM.
+//
+// Parameters:
+//   - groupID - The ID of the group.
+//   - message - The contents of the message. This is expected to be Unicode,
+//     and thus a string data type is expected.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (dmc *DMClient) SendGroupText(groupID []byte, message string,
+	cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, err := dmc.api.SendGroupText(groupID, message, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephemeral.Id{})
+}
+
+// SendGroupReply is used to send a formatted reply to a message in a group DM.
+//
+// Parameters:
+//   - groupID - The ID of the group.
+//   - replyMessage - The contents of the reply message.
+//   - replyToBytes - The bytes of the [message.ID] of the message you wish to
+//     reply to.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (dmc *DMClient) SendGroupReply(groupID []byte, replyMessage string,
+	replyToBytes []byte, cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	replyTo, err := message.UnmarshalID(replyToBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, err := dmc.api.SendGroupReply(
+		groupID, replyMessage, replyTo, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephemeral.Id{})
+}
+
+// SendGroupReaction is used to send a reaction to a message in a group DM. The
+// reaction must be a single emoji with no other characters, and will be
+// rejected otherwise.
+//
+// Parameters:
+//   - groupID - The ID of the group.
+//   - reaction - The user's reaction. This should be a single emoji with no
+//     other characters.
+//   - reactToBytes - The bytes of the [message.ID] of the message you wish to
+//     react to.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (dmc *DMClient) SendGroupReaction(groupID []byte, reaction string,
+	reactToBytes []byte, cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	reactTo, err := message.UnmarshalID(reactToBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, err := dmc.api.SendGroupReaction(
+		groupID, reaction, reactTo, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephemeral.Id{})
+}
+
 // constructChannelSendReport is a helper function which returns a JSON
 // marshalled ChannelSendReport.
 func constructDMSendReport(dmMsgID message.ID,
@@ -900,6 +1407,15 @@ const (
 
 	// DmMessageDeleted indicates the data is [DmMessageDeletedJSON].
 	DmMessageDeleted int64 = 4000
+
+	// DmUserTyping indicates the data is [DmUserTypingJSON].
+	DmUserTyping int64 = 5000
+
+	// DmMessageRequest indicates the data is [DmMessageRequestJSON].
+	DmMessageRequest int64 = 6000
+
+	// DmScheduledSendUpdate indicates the data is [DmScheduledSendUpdateJSON].
+	DmScheduledSendUpdate int64 = 7000
 )
 
 type dmCallbacks struct {
@@ -952,6 +1468,37 @@ func (dmCBS *dmCallbacks) MessageDeleted(messageID message.ID) {
 	})
 }
 
+func (dmCBS *dmCallbacks) UserTyping(
+	partnerPubKey ed25519.PublicKey, nickname string, typing bool) {
+	dmCBS.eventUpdate(DmUserTyping, DmUserTypingJSON{
+		PubKey:   partnerPubKey,
+		Nickname: nickname,
+		Typing:   typing,
+	})
+}
+
+func (dmCBS *dmCallbacks) MessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	dmCBS.eventUpdate(DmMessageRequest, DmMessageRequestJSON{
+		PubKey:  partnerPubKey,
+		Pending: pending,
+	})
+}
+
+func (dmCBS *dmCallbacks) ScheduledSendUpdate(sendID uint64,
+	partnerPubKey ed25519.PublicKey, messageID message.ID, err error) {
+	ssu := DmScheduledSendUpdateJSON{
+		SendID: int64(sendID),
+		PubKey: partnerPubKey,
+	}
+	if err != nil {
+		ssu.Error = err.Error()
+	} else {
+		ssu.MessageID = &messageID
+	}
+	dmCBS.eventUpdate(DmScheduledSendUpdate, ssu)
+}
+
 // DmNotificationUpdateJSON contains updates describing DM notifications.
 //
 // Fields:
@@ -1056,3 +1603,65 @@ type DmMessageReceivedJSON struct {
 type DmMessageDeletedJSON struct {
 	MessageID message.ID `json:"messageID"`
 }
+
+// DmUserTypingJSON is returned any time a DM partner starts or stops typing.
+//
+// Fields:
+//   - PubKey - The public key of the partner.
+//   - Nickname - The nickname of the partner.
+//   - Typing - True if the partner is typing and false if they stopped.
+//
+// Example JSON:
+//
+//	{
+//	  "pubKey": "geWVc6it/Z3XSb9//Om6XvgI/dvEW+h4wD+mkjHidfE=",
+//	  "nickname": "Alice",
+//	  "typing": true
+//	}
+type DmUserTypingJSON struct {
+	PubKey   ed25519.PublicKey `json:"pubKey"`
+	Nickname string            `json:"nickname"`
+	Typing   bool              `json:"typing"`
+}
+
+// DmMessageRequestJSON is returned any time a message request is received from
+// a partner or a pending request is accepted, declined, or blocked.
+//
+// Fields:
+//   - PubKey - The public key of the partner.
+//   - Pending - True if the partner has a new message request and false if
+//     the request was resolved.
+//
+// Example JSON:
+//
+//	{
+//	  "pubKey": "G78F+bKzRzZLwx9k009ahkWClje3/3gnZR/0u2l/MX4=",
+//	  "pending": true
+//	}
+type DmMessageRequestJSON struct {
+	PubKey  ed25519.PublicKey `json:"pubKey"`
+	Pending bool              `json:"pending"`
+}
+
+// DmScheduledSendUpdateJSON is returned when a scheduled send is sent or
+// fails.
+//
+// Fields:
+//   - SendID - The ID of the [dm.ScheduledSend].
+//   - PubKey - The public key of the partner the message was sent to.
+//   - MessageID - The ID of the sent message. Omitted if the send failed.
+//   - Error - The reason the send failed. Omitted if the send succeeded.
+//
+// Example JSON:
+//
+//	{
+//	  "sendID": 3,
+//	  "pubKey": "G78F+bKzRzZLwx9k009ahkWClje3/3gnZR/0u2l/MX4=",
+//	  "messageID": "yGO7PZsOpEs+A1DgEIAyTXxpOwBEtMpShqV7h5EtJYw="
+//	}
+type DmScheduledSendUpdateJSON struct {
+	SendID    int64             `json:"sendID"`
+	PubKey    ed25519.PublicKey `json:"pubKey"`
+	MessageID *message.ID       `json:"messageID,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
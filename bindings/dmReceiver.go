@@ -194,6 +194,22 @@ type DMReceiver interface {
 	UpdateSentStatus(uuid int64, messageID []byte, timestamp, roundID,
 		status int64)
 
+	// UpdateReadStatus is called when messages in a conversation have been
+	// read. All messages in the conversation with the partner that were sent
+	// by senderPubKey, up to and including the message upTo, should have
+	// their receipt status set to [dm.Read].
+	//
+	// If the message upTo does not exist, then no update should be made.
+	//
+	// Parameters:
+	//  - partnerPubKey - The [ed25519.PublicKey] of the conversation partner.
+	//  - senderPubKey - The [ed25519.PublicKey] of the sender of the messages
+	//    that were read. This is the user's own key when the partner read
+	//    their messages.
+	//  - upToMessageID - The bytes of the [message.ID] of the last message
+	//    read.
+	UpdateReadStatus(partnerPubKey, senderPubKey, upToMessageID []byte)
+
 	// DeleteMessage deletes the message with the given message.ID belonging to
 	// the sender. If the message exists and belongs to the sender, then it is
 	// deleted and DeleteMessage returns true. If it does not exist, it returns
@@ -204,6 +220,15 @@ type DMReceiver interface {
 	//  - senderPubKey - The [ed25519.PublicKey] of the sender of the message.
 	DeleteMessage(messageID, senderPubKey []byte) bool
 
+	// UpdateMessageRequest marks whether the conversation with the partner is
+	// a pending message request. Request conversations should be kept in a
+	// separate inbox until they are accepted.
+	//
+	// Parameters:
+	//  - partnerPubKey - The [ed25519.PublicKey] of the conversation partner.
+	//  - pending - True if the conversation is a pending message request.
+	UpdateMessageRequest(partnerPubKey []byte, pending bool)
+
 	// GetConversation returns any conversations held by the
 	// model (receiver). JSON List of dm.ModelConversation object.
 	GetConversation(senderPubKey []byte) []byte
@@ -211,6 +236,43 @@ type DMReceiver interface {
 	// GetConversations returns any conversations held by the
 	// model (receiver). JSON List of dm.ModelConversation object.
 	GetConversations() []byte
+
+	// UpdateGroup creates or updates the group DM. The group should be stored
+	// as a conversation with the group ID as its public key. A conversation
+	// with a partner must never be replaced by a group.
+	//
+	// Parameters:
+	//  - groupJSON - JSON of [dm.ModelGroup]. The members do not include the
+	//    user. The admins do not include the creator.
+	//
+	// Example groupJSON:
+	//  {
+	//    "group_id": "R59BvVvLsPHXvabshwfXd8bxP6YN5igcX3jeP2GLGpI/A7s3aEcu6t7EYyjDzBIjng==",
+	//    "name": "My group",
+	//    "members": [
+	//      {
+	//        "pub_key": "4xEn/rxv9yw9edlb9Gilit1BurOA8RxXLcDaotZj6SU=",
+	//        "token": 2170646541
+	//      },
+	//      {
+	//        "pub_key": "5+uybUVt3/vOXZUe6rkvXvlz1faoVLytt4TPtKVOi9c=",
+	//        "token": 3104479562
+	//      }
+	//    ],
+	//    "admins": [
+	//      "4xEn/rxv9yw9edlb9Gilit1BurOA8RxXLcDaotZj6SU="
+	//    ]
+	//  }
+	UpdateGroup(groupJSON []byte)
+
+	// GetGroup returns the group DM with the given ID.
+	//
+	// Parameters:
+	//  - groupID - The ID of the group.
+	//
+	// Returns:
+	//  - JSON of [dm.ModelGroup] or nil if the group does not exist.
+	GetGroup(groupID []byte) []byte
 }
 
 // dmReceiver is a wrapper which wraps an existing DMReceiver object and
@@ -302,6 +364,12 @@ func (dmr *dmReceiver) UpdateSentStatus(uuid uint64,
 		int64(round.ID), int64(status))
 }
 
+// UpdateReadStatus is called when messages in a conversation have been read.
+func (dmr *dmReceiver) UpdateReadStatus(partnerPubKey,
+	senderPubKey ed25519.PublicKey, upTo message.ID) {
+	dmr.dr.UpdateReadStatus(partnerPubKey, senderPubKey, upTo.Marshal())
+}
+
 // DeleteMessage deletes the message with the given message.ID belonging to the
 // sender. If the message exists and belongs to the sender, then it is deleted
 // and DeleteMessage returns true. If it does not exist, it returns false.
@@ -310,6 +378,13 @@ func (dmr *dmReceiver) DeleteMessage(
 	return dmr.dr.DeleteMessage(messageID.Marshal(), senderPubKey)
 }
 
+// UpdateMessageRequest marks whether the conversation with the partner is a
+// pending message request.
+func (dmr *dmReceiver) UpdateMessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	dmr.dr.UpdateMessageRequest(partnerPubKey, pending)
+}
+
 // GetConversation returns any conversations held by the model (receiver).
 func (dmr *dmReceiver) GetConversation(senderPubKey ed25519.PublicKey) *dm.ModelConversation {
 	convoJSON := dmr.dr.GetConversation(senderPubKey)
@@ -332,3 +407,28 @@ func (dmr *dmReceiver) GetConversations() []dm.ModelConversation {
 	}
 	return convos
 }
+
+// UpdateGroup creates or updates the group DM.
+func (dmr *dmReceiver) UpdateGroup(group dm.ModelGroup) {
+	groupJSON, err := json.Marshal(group)
+	if err != nil {
+		jww.ERROR.Printf("Cannot marshal group %X: %+v", group.GroupID, err)
+		return
+	}
+	dmr.dr.UpdateGroup(groupJSON)
+}
+
+// GetGroup returns the group DM with the given ID or nil if it does not exist.
+func (dmr *dmReceiver) GetGroup(groupID ed25519.PublicKey) *dm.ModelGroup {
+	groupJSON := dmr.dr.GetGroup(groupID)
+	if len(groupJSON) == 0 {
+		return nil
+	}
+	var group dm.ModelGroup
+	err := json.Unmarshal(groupJSON, &group)
+	if err != nil {
+		jww.ERROR.Printf("Cannot unmarshal group %X: %+v", groupID, err)
+		return nil
+	}
+	return &group
+}
@@ -113,3 +113,80 @@ func newPubKey(rng io.Reader) ed25519.PublicKey {
 	pubKey, _, _ := ed25519.GenerateKey(rng)
 	return pubKey
 }
+
+// Produces example JSON of DmUserTypingJSON to be used for documentation.
+func Test_DmUserTypingJSON(t *testing.T) {
+	prng := rand.New(rand.NewSource(88413))
+
+	utJSON := DmUserTypingJSON{
+		PubKey:   newPubKey(prng),
+		Nickname: "Alice",
+		Typing:   true,
+	}
+
+	data, err := json.MarshalIndent(utJSON, "//  ", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Printf("//  %s\n", data)
+}
+
+// Produces example JSON of DmMessageRequestJSON to be used for documentation.
+func Test_DmMessageRequestJSON(t *testing.T) {
+	prng := rand.New(rand.NewSource(26311))
+
+	mrJSON := DmMessageRequestJSON{
+		PubKey:  newPubKey(prng),
+		Pending: true,
+	}
+
+	data, err := json.MarshalIndent(mrJSON, "//  ", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Printf("//  %s\n", data)
+}
+
+// Produces example JSON of DmScheduledSendUpdateJSON to be used for
+// documentation.
+func Test_DmScheduledSendUpdateJSON(t *testing.T) {
+	prng := rand.New(rand.NewSource(26311))
+
+	var messageID message.ID
+	prng.Read(messageID[:])
+	ssuJSON := DmScheduledSendUpdateJSON{
+		SendID:    3,
+		PubKey:    newPubKey(prng),
+		MessageID: &messageID,
+	}
+
+	data, err := json.MarshalIndent(ssuJSON, "//  ", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Printf("//  %s\n", data)
+}
+
+// Produces example JSON of dm.ModelGroup to be used for documentation.
+func Test_ModelGroupJSON(t *testing.T) {
+	prng := rand.New(rand.NewSource(623677))
+
+	group := dm.ModelGroup{
+		GroupID: newPubKey(prng),
+		Name:    "My group",
+		Members: []dm.GroupMember{
+			{PubKey: newPubKey(prng), Token: prng.Uint32()},
+			{PubKey: newPubKey(prng), Token: prng.Uint32()},
+		},
+	}
+
+	data, err := json.MarshalIndent(group, "//  ", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Printf("//  %s\n", data)
+}
@@ -25,8 +25,10 @@ type broadcastClient struct {
 	rng     *fastRNG.StreamGenerator
 
 	// memoized to avoid constantly reconstructing
-	asymIdentifier []byte
-	symIdentifier  []byte
+	asymIdentifier          []byte
+	symIdentifier           []byte
+	asymMultipartIdentifier []byte
+	symMultipartIdentifier  []byte
 }
 
 // NewBroadcastChannelFunc creates a broadcast Channel. Used so that it can be
@@ -46,6 +48,10 @@ func NewBroadcastChannel(channel *crypto.Channel, net Client,
 			[]byte(asymmetricRSAToPublicBroadcastServicePostfix)...),
 		symIdentifier: append(channel.ReceptionID.Marshal(),
 			[]byte(symmetricBroadcastServicePostfix)...),
+		asymMultipartIdentifier: append(channel.ReceptionID.Marshal(),
+			[]byte(asymmetricMultipartServicePostfix)...),
+		symMultipartIdentifier: append(channel.ReceptionID.Marshal(),
+			[]byte(symmetricMultipartServicePostfix)...),
 	}
 
 	if !channel.Verify() {
@@ -65,6 +71,9 @@ func NewBroadcastChannel(channel *crypto.Channel, net Client,
 // RegisterRSAtoPublicListener registers a listener for asymmetric broadcast messages.
 // Note: only one Asymmetric Listener can be registered at a time.
 // Registering a new one will overwrite the old one
+//
+// The listener also receives multi-part messages once all their parts have
+// been received.
 func (bc *broadcastClient) RegisterRSAtoPublicListener(
 	listenerCb ListenerFunc, tags []string) (Processor, error) {
 
@@ -78,6 +87,15 @@ func (bc *broadcastClient) RegisterRSAtoPublicListener(
 	service := bc.GetRSAToPublicCompressedService(tags, dummyMessageType)
 
 	bc.net.UpsertCompressedService(bc.channel.ReceptionID, service, p)
+
+	mp := &processor{
+		c:      bc.channel,
+		cb:     newReassembler(listenerCb).receive,
+		method: RSAToPublic,
+	}
+	multipartService :=
+		bc.getRSAToPublicMultipartService(tags, dummyMessageType)
+	bc.net.UpsertCompressedService(bc.channel.ReceptionID, multipartService, mp)
 	return p, nil
 }
 
@@ -85,6 +103,9 @@ func (bc *broadcastClient) RegisterRSAtoPublicListener(
 // messages.
 // Note: only one Asymmetric Listener can be registered at a time.
 // Registering a new one will overwrite the old one
+//
+// The listener also receives multi-part messages once all their parts have
+// been received.
 func (bc *broadcastClient) RegisterSymmetricListener(
 	listenerCb ListenerFunc, tags []string) (Processor, error) {
 
@@ -97,6 +118,14 @@ func (bc *broadcastClient) RegisterSymmetricListener(
 	// Metadata is ignored on a registered service, put a dummy
 	service := bc.GetSymmetricCompressedService(tags, dummyMessageType)
 	bc.net.UpsertCompressedService(bc.channel.ReceptionID, service, p)
+
+	mp := &processor{
+		c:      bc.channel,
+		cb:     newReassembler(listenerCb).receive,
+		method: Symmetric,
+	}
+	multipartService := bc.getSymmetricMultipartService(tags, dummyMessageType)
+	bc.net.UpsertCompressedService(bc.channel.ReceptionID, multipartService, mp)
 	return p, nil
 }
 
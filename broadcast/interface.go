@@ -22,6 +22,9 @@ import (
 
 // ListenerFunc is registered when creating a new broadcasting channel and
 // receives all new broadcast messages for the channel.
+//
+// For a multi-part message, the listener is called once with the reassembled
+// payload and the round of the first part. The encrypted payload is nil.
 type ListenerFunc func(payload, encryptedPayload []byte, tags []string,
 	metadata [2]byte, receptionID receptionID.EphemeralIdentity,
 	round rounds.Round)
@@ -36,6 +39,14 @@ type Channel interface {
 	// broadcast payload.
 	MaxRSAToPublicPayloadSize() int
 
+	// MaxMultipartPayloadSize returns the maximum size for a symmetric
+	// multi-part broadcast payload.
+	MaxMultipartPayloadSize() int
+
+	// MaxRSAToPublicMultipartPayloadSize returns the maximum size for an
+	// asymmetric multi-part broadcast payload.
+	MaxRSAToPublicMultipartPayloadSize() int
+
 	// Get returns the underlying broadcast.Channel object.
 	Get() *crypto.Channel
 
@@ -78,6 +89,68 @@ type Channel interface {
 		tags []string, metadata [2]byte, cMixParams cmix.CMIXParams) (
 		[]byte, rounds.Round, ephemeral.Id, error)
 
+	// BroadcastMultipart broadcasts a payload to the channel. If the payload is
+	// larger than Channel.MaxPayloadSize, it is split into parts that are
+	// reassembled by the receivers. The payload must be of the size
+	// Channel.MaxMultipartPayloadSize or smaller.
+	//
+	// The network must be healthy to send.
+	BroadcastMultipart(payload []byte, tags []string, metadata [2]byte,
+		cMixParams cmix.CMIXParams) (rounds.Round, ephemeral.Id, error)
+
+	// BroadcastMultipartWithAssembler broadcasts a payload over a channel with
+	// a payload assembled after the round of the first part is selected. If
+	// the payload is larger than Channel.MaxPayloadSize, it is split into
+	// parts. The returned round is the round of the first part.
+	//
+	// The payload must be of the size Channel.MaxMultipartPayloadSize or
+	// smaller.
+	//
+	// If a part after the first fails to send, the message is partially sent
+	// and the round of the first part is returned with the error. Receivers
+	// drop incomplete messages, so the caller must treat it as not received
+	// and send it again in full.
+	//
+	// The network must be healthy to send.
+	BroadcastMultipartWithAssembler(assembler Assembler, tags []string,
+		metadata [2]byte, cMixParams cmix.CMIXParams) (
+		rounds.Round, ephemeral.Id, error)
+
+	// BroadcastRSAToPublicMultipart broadcasts the payload to the channel. If
+	// the payload is larger than Channel.MaxRSAToPublicPayloadSize, it is split
+	// into parts that are reassembled by the receivers. The inner ciphertexts
+	// of every part are returned joined in order.
+	//
+	// The payload must be of the size
+	// Channel.MaxRSAToPublicMultipartPayloadSize or smaller and the channel
+	// rsa.PrivateKey must be passed in.
+	//
+	// The network must be healthy to send.
+	BroadcastRSAToPublicMultipart(pk rsa.PrivateKey, payload []byte,
+		tags []string, metadata [2]byte, cMixParams cmix.CMIXParams) (
+		[]byte, rounds.Round, ephemeral.Id, error)
+
+	// BroadcastRSAToPublicMultipartWithAssembler broadcasts the payload to the
+	// channel with a function that builds the payload based upon the ID of the
+	// round of the first part. If the payload is larger than
+	// Channel.MaxRSAToPublicPayloadSize, it is split into parts. The returned
+	// round is the round of the first part. The inner ciphertexts of every
+	// part are returned joined in order.
+	//
+	// The payload must be of the size
+	// Channel.MaxRSAToPublicMultipartPayloadSize or smaller and the channel
+	// rsa.PrivateKey must be passed in.
+	//
+	// If a part after the first fails to send, the message is partially sent
+	// and the round of the first part is returned with the error. Receivers
+	// drop incomplete messages, so the caller must treat it as not received
+	// and send it again in full.
+	//
+	// The network must be healthy to send.
+	BroadcastRSAToPublicMultipartWithAssembler(pk rsa.PrivateKey,
+		assembler Assembler, tags []string, metadata [2]byte,
+		cMixParams cmix.CMIXParams) ([]byte, rounds.Round, ephemeral.Id, error)
+
 	// RegisterRSAtoPublicListener registers a listener for asymmetric broadcast messages.
 	// Note: only one Asymmetric Listener can be registered at a time.
 	// Registering a new one will overwrite the old one
@@ -106,7 +179,8 @@ type Processor interface {
 	message.Processor
 
 	// ProcessAdminMessage decrypts an admin message and sends the results on
-	// the callback.
+	// the callback. The inner ciphertext may also be the joined inner
+	// ciphertexts of every part of a multi-part admin message.
 	ProcessAdminMessage(innerCiphertext []byte, tags []string, metadata [2]byte,
 		receptionID receptionID.EphemeralIdentity, round rounds.Round)
 }
@@ -0,0 +1,593 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package broadcast
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/crypto/rsa"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Error messages.
+const (
+	// broadcastClient.BroadcastMultipartWithAssembler
+	errMultipartPart = "failed to send part %d of %d; the message was " +
+		"partially sent and will not be received: %+v"
+
+	// splitMultipart
+	errMultipartID = "failed to generate multi-part message ID: %+v"
+
+	// joinMultipart
+	errJoinHeader   = "part %d of %d bytes is shorter than the header"
+	errJoinID       = "part %d belongs to message %s, expected %s"
+	errJoinIndex    = "part %d has index %d of %d parts"
+	errJoinNumParts = "got %d parts, expected %d"
+)
+
+// Tags.
+const (
+	symmetricMultipartServicePostfix  = "SymmetricBroadcastMultipart"
+	asymmetricMultipartServicePostfix = "AsymmToPublicBcastMultipart"
+)
+
+// Reassembly limits.
+//
+// These bound the memory used to reassemble messages, not who can use it. The
+// parts of a message do not identify their sender, so any member of a channel
+// can fill the listener with messages it never completes and cause the
+// incomplete messages of others to be dropped. A message that is dropped must
+// be sent again.
+const (
+	// defaultMultipartReassemblyTimeout is how long after receiving its first
+	// part that an incomplete multi-part message is dropped.
+	defaultMultipartReassemblyTimeout = 5 * time.Minute
+
+	// defaultMaxPendingMultipartMessages is the maximum number of incomplete
+	// multi-part messages kept at once per listener. Once reached, the oldest
+	// message is dropped.
+	defaultMaxPendingMultipartMessages = 64
+)
+
+// Multi-part message header.
+//
+// Each part of a multi-part message starts with the header below followed by
+// the contents of the part. Parts are sent in order, but may be received in
+// any order, and are joined once all have been received.
+//
+//	+----------------+------------+------------+----------+
+//	|   Message ID   | Part Index | Part Count | Contents |
+//	|    16 bytes    |   1 byte   |   1 byte   | variable |
+//	+----------------+------------+------------+----------+
+const (
+	multipartIDLen     = 16
+	multipartIndexPos  = multipartIDLen
+	multipartCountPos  = multipartIndexPos + 1
+	multipartHeaderLen = multipartCountPos + 1
+
+	// maxMultipartParts is the maximum number of parts a payload can be split
+	// into. It limits the memory used to reassemble a message.
+	maxMultipartParts = 32
+)
+
+// multipartID uniquely identifies all the parts of a multi-part message.
+type multipartID [multipartIDLen]byte
+
+// String returns the base 64 encoded multipartID. This function adheres to
+// the fmt.Stringer interface.
+func (mid multipartID) String() string {
+	return base64.StdEncoding.EncodeToString(mid[:])
+}
+
+// MaxMultipartPayloadSize returns the maximum size of a symmetric payload that
+// can be sent with BroadcastMultipart.
+func (bc *broadcastClient) MaxMultipartPayloadSize() int {
+	return maxMultipartPayloadSize(bc.maxSymmetricPayload())
+}
+
+// MaxRSAToPublicMultipartPayloadSize returns the maximum size of an asymmetric
+// payload that can be sent with BroadcastRSAToPublicMultipart.
+func (bc *broadcastClient) MaxRSAToPublicMultipartPayloadSize() int {
+	return maxMultipartPayloadSize(bc.MaxRSAToPublicPayloadSize())
+}
+
+// BroadcastMultipart broadcasts a payload to a symmetric channel. If the
+// payload is larger than [broadcastClient.MaxPayloadSize], it is split into
+// parts that are sent one after another and reassembled by the receivers.
+// The payload must be of size [broadcastClient.MaxMultipartPayloadSize] or
+// smaller.
+//
+// The network must be healthy to send.
+func (bc *broadcastClient) BroadcastMultipart(payload []byte, tags []string,
+	metadata [2]byte, cMixParams cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	assemble := func(rid id.Round) ([]byte, error) { return payload, nil }
+	return bc.BroadcastMultipartWithAssembler(
+		assemble, tags, metadata, cMixParams)
+}
+
+// BroadcastMultipartWithAssembler broadcasts a payload over a symmetric
+// channel with a payload assembled after the round is selected. If the payload
+// is larger than [broadcastClient.MaxPayloadSize], it is split into parts. The
+// first part is sent on the selected round and the rest are sent after it. The
+// returned round is the round of the first part.
+//
+// A payload that fits in a single message is sent the same way as
+// [broadcastClient.BroadcastWithAssembler]. Otherwise, the payload must be of
+// size [broadcastClient.MaxMultipartPayloadSize] or smaller.
+//
+// Parts are sent one after another. If a part after the first fails to send,
+// the round of the first part is returned with the error. The message is then
+// partially sent: receivers drop the incomplete message after the reassembly
+// timeout, so the caller must treat it as not received and send it again in
+// full.
+//
+// The network must be healthy to send.
+func (bc *broadcastClient) BroadcastMultipartWithAssembler(
+	assembler Assembler, tags []string, metadata [2]byte,
+	cMixParams cmix.CMIXParams) (rounds.Round, ephemeral.Id, error) {
+	if !bc.net.IsHealthy() {
+		return rounds.Round{}, ephemeral.Id{}, errors.New(errNetworkHealth)
+	}
+
+	var parts [][]byte
+	assemble := func(rid id.Round) (format.Fingerprint, cmix.Service,
+		[]byte, []byte, error) {
+		payload, err := assembler(rid)
+		if err != nil {
+			return format.Fingerprint{}, message.Service{}, nil, nil, err
+		}
+
+		// Send payloads that fit in a single message normally
+		parts = nil
+		if len(payload) <= bc.maxSymmetricPayload() {
+			return bc.encryptSymmetric(
+				payload, bc.GetSymmetricCompressedService(tags, metadata))
+		}
+
+		parts, err = bc.splitMultipart(payload, bc.maxSymmetricPayload())
+		if err != nil {
+			return format.Fingerprint{}, message.Service{}, nil, nil, err
+		}
+
+		return bc.encryptSymmetric(
+			parts[0], bc.getSymmetricMultipartService(tags, metadata))
+	}
+
+	if cMixParams.DebugTag == cmix.DefaultDebugTag {
+		cMixParams.DebugTag = symmCMixSendTag
+	}
+
+	r, ephID, err :=
+		bc.net.SendWithAssembler(bc.channel.ReceptionID, assemble, cMixParams)
+	if err != nil {
+		return rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		assemblePart := func(id.Round) (format.Fingerprint, cmix.Service,
+			[]byte, []byte, error) {
+			return bc.encryptSymmetric(
+				part, bc.getSymmetricMultipartService(tags, metadata))
+		}
+		_, _, err = bc.net.SendWithAssembler(
+			bc.channel.ReceptionID, assemblePart, cMixParams)
+		if err != nil {
+			return r, ephID,
+				errors.Errorf(errMultipartPart, i+1, len(parts), err)
+		}
+	}
+
+	return r, ephID, nil
+}
+
+// BroadcastRSAToPublicMultipart broadcasts the payload to the channel. If the
+// payload is larger than [broadcastClient.MaxRSAToPublicPayloadSize], it is
+// split into parts that are sent one after another and reassembled by the
+// receivers. The payload must be of size
+// [broadcastClient.MaxRSAToPublicMultipartPayloadSize] or smaller and the
+// channel [rsa.PrivateKey] must be passed in.
+//
+// The inner ciphertext of every part is returned joined in order. It can be
+// replayed with [Processor.ProcessAdminMessage].
+//
+// The network must be healthy to send.
+func (bc *broadcastClient) BroadcastRSAToPublicMultipart(pk rsa.PrivateKey,
+	payload []byte, tags []string, metadata [2]byte,
+	cMixParams cmix.CMIXParams) ([]byte, rounds.Round, ephemeral.Id, error) {
+	assemble := func(rid id.Round) ([]byte, error) { return payload, nil }
+	return bc.BroadcastRSAToPublicMultipartWithAssembler(
+		pk, assemble, tags, metadata, cMixParams)
+}
+
+// BroadcastRSAToPublicMultipartWithAssembler broadcasts the payload to the
+// channel with a function that builds the payload based upon the ID of the
+// selected round. If the payload is larger than
+// [broadcastClient.MaxRSAToPublicPayloadSize], it is split into parts. The
+// first part is sent on the selected round and the rest are sent after it. The
+// returned round is the round of the first part.
+//
+// A payload that fits in a single message is sent the same way as
+// [broadcastClient.BroadcastRSAToPublicWithAssembler] and its inner ciphertext
+// is returned. Otherwise, the payload must be of size
+// [broadcastClient.MaxRSAToPublicMultipartPayloadSize] or smaller and the
+// inner ciphertexts of every part are returned joined in order. Either can be
+// replayed with [Processor.ProcessAdminMessage].
+//
+// Parts are sent one after another. If a part after the first fails to send,
+// the round of the first part is returned with the error and no ciphertext.
+// The message is then partially sent: receivers drop the incomplete message
+// after the reassembly timeout, so the caller must treat it as not received
+// and send it again in full.
+//
+// The network must be healthy to send.
+func (bc *broadcastClient) BroadcastRSAToPublicMultipartWithAssembler(
+	pk rsa.PrivateKey, assembler Assembler, tags []string, metadata [2]byte,
+	cMixParams cmix.CMIXParams) ([]byte, rounds.Round, ephemeral.Id, error) {
+	if !bc.net.IsHealthy() {
+		return nil, rounds.Round{}, ephemeral.Id{}, errors.New(errNetworkHealth)
+	}
+
+	var parts [][]byte
+	var singleEncryptedPayload []byte
+	var firstEncryptedPart []byte
+	assemble := func(rid id.Round) (format.Fingerprint, cmix.Service,
+		[]byte, []byte, error) {
+		payload, err := assembler(rid)
+		if err != nil {
+			return format.Fingerprint{}, message.Service{}, nil, nil, err
+		}
+
+		// Send payloads that fit in a single message normally
+		var fp format.Fingerprint
+		var service cmix.Service
+		var encryptedPayload, mac []byte
+		parts = nil
+		if len(payload) <= bc.MaxRSAToPublicPayloadSize() {
+			singleEncryptedPayload, fp, service, encryptedPayload, mac, err =
+				bc.encryptRSAToPublic(pk, payload,
+					bc.GetRSAToPublicCompressedService(tags, metadata))
+			return fp, service, encryptedPayload, mac, err
+		}
+
+		parts, err = bc.splitMultipart(payload, bc.MaxRSAToPublicPayloadSize())
+		if err != nil {
+			return format.Fingerprint{}, message.Service{}, nil, nil, err
+		}
+
+		singleEncryptedPayload = nil
+		firstEncryptedPart, fp, service, encryptedPayload, mac, err =
+			bc.encryptRSAToPublic(pk, parts[0],
+				bc.getRSAToPublicMultipartService(tags, metadata))
+		return fp, service, encryptedPayload, mac, err
+	}
+
+	if cMixParams.DebugTag == cmix.DefaultDebugTag {
+		cMixParams.DebugTag = asymmCMixSendTag
+	}
+
+	r, ephID, err :=
+		bc.net.SendWithAssembler(bc.channel.ReceptionID, assemble, cMixParams)
+	if err != nil {
+		return nil, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	if len(parts) > 0 {
+		singleEncryptedPayload = firstEncryptedPart
+	}
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		var encryptedPart []byte
+		assemblePart := func(id.Round) (format.Fingerprint, cmix.Service,
+			[]byte, []byte, error) {
+			var fp format.Fingerprint
+			var service cmix.Service
+			var encryptedPayload, mac []byte
+			var err2 error
+			encryptedPart, fp, service, encryptedPayload, mac, err2 =
+				bc.encryptRSAToPublic(pk, part,
+					bc.getRSAToPublicMultipartService(tags, metadata))
+			return fp, service, encryptedPayload, mac, err2
+		}
+		_, _, err = bc.net.SendWithAssembler(
+			bc.channel.ReceptionID, assemblePart, cMixParams)
+		if err != nil {
+			return nil, r, ephID,
+				errors.Errorf(errMultipartPart, i+1, len(parts), err)
+		}
+		singleEncryptedPayload =
+			append(singleEncryptedPayload, encryptedPart...)
+	}
+
+	return singleEncryptedPayload, r, ephID, nil
+}
+
+// getSymmetricMultipartService returns the service used for the parts of
+// symmetric multi-part messages. It is different from the service used for
+// single messages so that clients that cannot reassemble the parts ignore
+// them.
+func (bc *broadcastClient) getSymmetricMultipartService(
+	tags []string, metadata [2]byte) message.CompressedService {
+	return message.CompressedService{
+		Identifier: bc.symMultipartIdentifier,
+		Tags:       tags,
+		Metadata:   metadata[:],
+	}
+}
+
+// getRSAToPublicMultipartService returns the service used for the parts of
+// asymmetric multi-part messages.
+func (bc *broadcastClient) getRSAToPublicMultipartService(
+	tags []string, metadata [2]byte) message.CompressedService {
+	return message.CompressedService{
+		Identifier: bc.asymMultipartIdentifier,
+		Tags:       tags,
+		Metadata:   metadata[:],
+	}
+}
+
+// splitMultipart splits the payload into parts that each fit in maxPartSize,
+// including the header, under a new random message ID.
+func (bc *broadcastClient) splitMultipart(
+	payload []byte, maxPartSize int) ([][]byte, error) {
+	if len(payload) > maxMultipartPayloadSize(maxPartSize) {
+		return nil, errors.Errorf(errPayloadSize,
+			len(payload), maxMultipartPayloadSize(maxPartSize))
+	}
+
+	var mid multipartID
+	rng := bc.rng.GetStream()
+	_, err := rng.Read(mid[:])
+	rng.Close()
+	if err != nil {
+		return nil, errors.Errorf(errMultipartID, err)
+	}
+
+	return splitMultipart(mid, payload, maxPartSize), nil
+}
+
+// splitMultipart splits the payload into parts of maxPartSize or smaller,
+// each starting with the multi-part header.
+func splitMultipart(mid multipartID, payload []byte, maxPartSize int) [][]byte {
+	contentsSize := maxPartSize - multipartHeaderLen
+	count := (len(payload) + contentsSize - 1) / contentsSize
+
+	parts := make([][]byte, count)
+	for i := range parts {
+		end := (i + 1) * contentsSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		contents := payload[i*contentsSize : end]
+
+		part := make([]byte, multipartHeaderLen+len(contents))
+		copy(part, mid[:])
+		part[multipartIndexPos] = byte(i)
+		part[multipartCountPos] = byte(count)
+		copy(part[multipartHeaderLen:], contents)
+		parts[i] = part
+	}
+
+	return parts
+}
+
+// joinMultipart joins the parts of a multi-part message, which must all be
+// given in order, into the original payload.
+func joinMultipart(parts [][]byte) ([]byte, error) {
+	var mid multipartID
+	contents := make([][]byte, len(parts))
+	for i, part := range parts {
+		if len(part) < multipartHeaderLen {
+			return nil, errors.Errorf(errJoinHeader, i, len(part))
+		}
+
+		var partID multipartID
+		copy(partID[:], part[:multipartIDLen])
+		if i == 0 {
+			mid = partID
+		} else if partID != mid {
+			return nil, errors.Errorf(errJoinID, i, partID, mid)
+		}
+
+		index := int(part[multipartIndexPos])
+		count := int(part[multipartCountPos])
+		if count != len(parts) {
+			return nil, errors.Errorf(errJoinNumParts, len(parts), count)
+		} else if index != i {
+			return nil, errors.Errorf(errJoinIndex, i, index, count)
+		}
+
+		contents[i] = part[multipartHeaderLen:]
+	}
+
+	return bytes.Join(contents, nil), nil
+}
+
+// maxMultipartPayloadSize returns the maximum size of a payload that can be
+// split into parts of maxPartSize.
+func maxMultipartPayloadSize(maxPartSize int) int {
+	if maxPartSize <= multipartHeaderLen {
+		return 0
+	}
+	return (maxPartSize - multipartHeaderLen) * maxMultipartParts
+}
+
+// reassembler collects the parts of multi-part messages and calls the listener
+// with the full payload once every part has been received. Incomplete messages
+// are dropped after the timeout and the oldest message is dropped when the
+// maximum number are being reassembled.
+type reassembler struct {
+	cb         ListenerFunc
+	timeout    time.Duration
+	maxPending int
+	messages   map[multipartID]*partialMessage
+
+	// completed are the IDs of messages completed within the timeout, used to
+	// ignore replayed parts
+	completed map[multipartID]time.Time
+
+	mux sync.Mutex
+}
+
+// partialMessage is a multi-part message that is still being received.
+type partialMessage struct {
+	parts          [][]byte
+	encryptedParts [][]byte
+	received       int
+	started        time.Time
+
+	// Values from the first part that are passed to the listener
+	tags        []string
+	metadata    [2]byte
+	receptionID receptionID.EphemeralIdentity
+	round       rounds.Round
+}
+
+// newReassembler returns a reassembler that calls the listener with each
+// reassembled message.
+func newReassembler(cb ListenerFunc) *reassembler {
+	return &reassembler{
+		cb:         cb,
+		timeout:    defaultMultipartReassemblyTimeout,
+		maxPending: defaultMaxPendingMultipartMessages,
+		messages:   make(map[multipartID]*partialMessage),
+		completed:  make(map[multipartID]time.Time),
+	}
+}
+
+// receive adheres to the ListenerFunc type and is called with the decrypted
+// payload of each part. The listener is called with the full payload, the
+// encrypted payloads of every part joined in order, and the tags, metadata,
+// and round of the first part.
+func (r *reassembler) receive(payload, encryptedPayload []byte, tags []string,
+	metadata [2]byte, receptionID receptionID.EphemeralIdentity,
+	round rounds.Round) {
+	if len(payload) < multipartHeaderLen {
+		jww.WARN.Printf("[BCAST] Dropping multi-part message part of %d "+
+			"bytes on round %d; shorter than the header", len(payload),
+			round.ID)
+		return
+	}
+
+	var mid multipartID
+	copy(mid[:], payload[:multipartIDLen])
+	index := int(payload[multipartIndexPos])
+	count := int(payload[multipartCountPos])
+	if count < 1 || count > maxMultipartParts || index >= count {
+		jww.WARN.Printf("[BCAST] Dropping part %d of %d of multi-part "+
+			"message %s on round %d; invalid part", index, count, mid, round.ID)
+		return
+	}
+
+	now := netTime.Now()
+	r.mux.Lock()
+	r.prune(now)
+
+	if _, exists := r.completed[mid]; exists {
+		r.mux.Unlock()
+		jww.DEBUG.Printf("[BCAST] Ignoring part %d of completed multi-part "+
+			"message %s on round %d", index, mid, round.ID)
+		return
+	}
+
+	pm, exists := r.messages[mid]
+	if !exists {
+		r.dropOldest()
+		pm = &partialMessage{
+			parts:          make([][]byte, count),
+			encryptedParts: make([][]byte, count),
+			started:        now,
+		}
+		r.messages[mid] = pm
+	} else if len(pm.parts) != count {
+		r.mux.Unlock()
+		jww.WARN.Printf("[BCAST] Dropping part %d of multi-part message %s "+
+			"on round %d; expected %d parts, got %d",
+			index, mid, round.ID, len(pm.parts), count)
+		return
+	}
+
+	if pm.parts[index] != nil {
+		r.mux.Unlock()
+		jww.DEBUG.Printf("[BCAST] Ignoring duplicate part %d of multi-part "+
+			"message %s on round %d", index, mid, round.ID)
+		return
+	}
+
+	pm.parts[index] = append([]byte{}, payload[multipartHeaderLen:]...)
+	pm.encryptedParts[index] = append([]byte{}, encryptedPayload...)
+	pm.received++
+	if index == 0 {
+		pm.tags, pm.metadata, pm.receptionID, pm.round =
+			tags, metadata, receptionID, round
+	}
+
+	if pm.received < len(pm.parts) {
+		r.mux.Unlock()
+		return
+	}
+
+	delete(r.messages, mid)
+	r.completed[mid] = now
+	r.mux.Unlock()
+
+	r.cb(bytes.Join(pm.parts, nil), bytes.Join(pm.encryptedParts, nil),
+		pm.tags, pm.metadata, pm.receptionID, pm.round)
+}
+
+// prune drops every message that started before the timeout and forgets
+// messages completed before the timeout. Must be called under lock.
+func (r *reassembler) prune(now time.Time) {
+	for mid, pm := range r.messages {
+		if now.Sub(pm.started) > r.timeout {
+			jww.WARN.Printf("[BCAST] Dropping multi-part message %s; "+
+				"received %d of %d parts before timing out after %s",
+				mid, pm.received, len(pm.parts), r.timeout)
+			delete(r.messages, mid)
+		}
+	}
+
+	for mid, completed := range r.completed {
+		if now.Sub(completed) > r.timeout {
+			delete(r.completed, mid)
+		}
+	}
+}
+
+// dropOldest drops the oldest message if the maximum number of messages are
+// being reassembled. Must be called under lock.
+func (r *reassembler) dropOldest() {
+	if len(r.messages) < r.maxPending {
+		return
+	}
+
+	var oldest multipartID
+	var oldestStarted time.Time
+	for mid, pm := range r.messages {
+		if oldestStarted.IsZero() || pm.started.Before(oldestStarted) {
+			oldest, oldestStarted = mid, pm.started
+		}
+	}
+
+	jww.WARN.Printf("[BCAST] Dropping multi-part message %s; too many "+
+		"messages are being reassembled", oldest)
+	delete(r.messages, oldest)
+}
@@ -0,0 +1,386 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package broadcast
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	crypto "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a payload larger than a single message sent with
+// broadcastClient.BroadcastMultipart is received once, in full, by every
+// listener and that a small payload is still received by them.
+func Test_broadcastClient_BroadcastMultipart_Smoke(t *testing.T) {
+	cMixHandler := newMockCmixHandler()
+	rngGen := fastRNG.NewStreamGenerator(1000, 10, csprng.NewSystemRNG)
+	channel, _, _ := crypto.NewChannel("MyChannel", "description",
+		crypto.Public, newMockCmix(cMixHandler).GetMaxMessageLength(),
+		rngGen.GetStream())
+
+	const n = 3
+	cbChans := make([]chan []byte, n)
+	clients := make([]Channel, n)
+	for i := range clients {
+		cbChan := make(chan []byte, 10)
+		cb := func(payload, _ []byte, _ []string, _ [2]byte,
+			_ receptionID.EphemeralIdentity, _ rounds.Round) {
+			cbChan <- payload
+		}
+
+		bc, err := NewBroadcastChannel(channel, newMockCmix(cMixHandler), rngGen)
+		if err != nil {
+			t.Fatalf("Failed to create broadcast channel: %+v", err)
+		}
+		if _, err = bc.RegisterSymmetricListener(cb, nil); err != nil {
+			t.Fatalf("Failed to register listener: %+v", err)
+		}
+
+		cbChans[i], clients[i] = cbChan, bc
+	}
+
+	prng := rand.New(rand.NewSource(42))
+	for _, size := range []int{10, clients[0].MaxPayloadSize() * 3,
+		clients[0].MaxMultipartPayloadSize()} {
+		payload := make([]byte, size)
+		prng.Read(payload)
+
+		_, _, err := clients[0].BroadcastMultipart(
+			payload, nil, [2]byte{}, cmix.GetDefaultCMIXParams())
+		if err != nil {
+			t.Fatalf("Failed to broadcast %d-byte payload: %+v", size, err)
+		}
+
+		for i, cbChan := range cbChans {
+			select {
+			case r := <-cbChan:
+				if !bytes.Equal(payload, r) {
+					t.Errorf("Client %d received wrong %d-byte payload."+
+						"\nexpected: %x\nreceived: %x", i, size, payload, r)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Client %d timed out waiting for %d-byte payload.",
+					i, size)
+			}
+			if len(cbChan) != 0 {
+				t.Errorf("Client %d received %d extra payloads.", i, len(cbChan))
+			}
+		}
+	}
+
+	// Error path: payload is too large to split
+	payload := make([]byte, clients[0].MaxMultipartPayloadSize()+1)
+	_, _, err := clients[0].BroadcastMultipart(
+		payload, nil, [2]byte{}, cmix.GetDefaultCMIXParams())
+	if err == nil {
+		t.Errorf("Did not error for %d-byte payload larger than %d bytes.",
+			len(payload), clients[0].MaxMultipartPayloadSize())
+	}
+}
+
+// Tests that when a part after the first fails to send,
+// broadcastClient.BroadcastMultipart returns an error with the round of the
+// first part and the partially sent message is not received.
+func Test_broadcastClient_BroadcastMultipart_PartialSend(t *testing.T) {
+	cMixHandler := newMockCmixHandler()
+	rngGen := fastRNG.NewStreamGenerator(1000, 10, csprng.NewSystemRNG)
+	net := &partialFailCmix{mockCmix: newMockCmix(cMixHandler), succeed: 2}
+	channel, _, _ := crypto.NewChannel("MyChannel", "description",
+		crypto.Public, net.GetMaxMessageLength(), rngGen.GetStream())
+
+	bc, err := NewBroadcastChannel(channel, net, rngGen)
+	if err != nil {
+		t.Fatalf("Failed to create broadcast channel: %+v", err)
+	}
+	received := make(chan []byte, 1)
+	_, err = bc.RegisterSymmetricListener(func(payload, _ []byte, _ []string,
+		_ [2]byte, _ receptionID.EphemeralIdentity, _ rounds.Round) {
+		received <- payload
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to register listener: %+v", err)
+	}
+
+	payload := make([]byte, bc.MaxPayloadSize()*3)
+	r, _, err := bc.BroadcastMultipart(
+		payload, nil, [2]byte{}, cmix.GetDefaultCMIXParams())
+	if err == nil {
+		t.Fatalf("Did not error when a part failed to send.")
+	} else if r.ID != 1 {
+		t.Errorf("Did not return the round of the first part."+
+			"\nexpected: %d\nreceived: %d", 1, r.ID)
+	}
+
+	select {
+	case <-received:
+		t.Errorf("Partially sent message was received.")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// partialFailCmix is a mockCmix that fails every send after the given number
+// of sends succeed. Each successful send is on the next round.
+type partialFailCmix struct {
+	*mockCmix
+	succeed int
+	sent    int
+}
+
+func (m *partialFailCmix) SendWithAssembler(recipient *id.ID,
+	assembler cmix.MessageAssembler, cp cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	if m.sent >= m.succeed {
+		return rounds.Round{}, ephemeral.Id{}, errors.New("send failed")
+	}
+	m.sent++
+	_, ephID, err := m.mockCmix.SendWithAssembler(recipient, assembler, cp)
+	return rounds.Round{ID: id.Round(m.sent)}, ephID, err
+}
+
+// Tests that a payload larger than a single message sent with
+// broadcastClient.BroadcastRSAToPublicMultipart is received in full by the
+// listener and that the returned inner ciphertext, and the one received by the
+// listener, can be replayed with Processor.ProcessAdminMessage.
+func Test_broadcastClient_BroadcastRSAToPublicMultipart_Smoke(t *testing.T) {
+	cMixHandler := newMockCmixHandler()
+	rngGen := fastRNG.NewStreamGenerator(1000, 10, csprng.NewSystemRNG)
+	channel, pk, _ := crypto.NewChannel("MyChannel", "description",
+		crypto.Public, newMockCmix(cMixHandler).GetMaxMessageLength(),
+		rngGen.GetStream())
+
+	cbChan := make(chan []byte, 10)
+	encryptedChan := make(chan []byte, 10)
+	cb := func(payload, encryptedPayload []byte, _ []string, _ [2]byte,
+		_ receptionID.EphemeralIdentity, _ rounds.Round) {
+		cbChan <- payload
+		encryptedChan <- encryptedPayload
+	}
+
+	bc, err := NewBroadcastChannel(channel, newMockCmix(cMixHandler), rngGen)
+	if err != nil {
+		t.Fatalf("Failed to create broadcast channel: %+v", err)
+	}
+	p, err := bc.RegisterRSAtoPublicListener(cb, nil)
+	if err != nil {
+		t.Fatalf("Failed to register listener: %+v", err)
+	}
+
+	prng := rand.New(rand.NewSource(42))
+	for _, size := range []int{10, bc.MaxRSAToPublicPayloadSize()*2 + 1} {
+		payload := make([]byte, size)
+		prng.Read(payload)
+
+		inner, _, _, err2 := bc.BroadcastRSAToPublicMultipart(
+			pk, payload, nil, [2]byte{}, cmix.GetDefaultCMIXParams())
+		if err2 != nil {
+			t.Fatalf("Failed to broadcast %d-byte payload: %+v", size, err2)
+		}
+
+		if len(inner) == 0 {
+			t.Errorf("No inner ciphertext returned for %d-byte payload.", size)
+		}
+
+		var received []byte
+		select {
+		case r := <-cbChan:
+			if !bytes.Equal(payload, r) {
+				t.Errorf("Received wrong %d-byte payload."+
+					"\nexpected: %x\nreceived: %x", size, payload, r)
+			}
+			received = <-encryptedChan
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %d-byte payload.", size)
+		}
+
+		// Replay both the returned and received ciphertexts
+		for _, ciphertext := range [][]byte{inner, received} {
+			p.ProcessAdminMessage(ciphertext, nil, [2]byte{},
+				receptionID.EphemeralIdentity{}, rounds.Round{})
+			select {
+			case r := <-cbChan:
+				<-encryptedChan
+				if !bytes.Equal(payload, r) {
+					t.Errorf("Replayed wrong %d-byte payload."+
+						"\nexpected: %x\nreceived: %x", size, payload, r)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Timed out waiting for replayed %d-byte payload.",
+					size)
+			}
+		}
+	}
+}
+
+// Tests that splitMultipart splits the payload into parts that fit in the
+// maximum size and that the reassembler joins them in any order, ignoring
+// duplicates, and calls the listener with the values of the first part.
+func Test_splitMultipart_reassembler(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	payload := make([]byte, 250)
+	prng.Read(payload)
+	mid := multipartID{1, 2, 3}
+	const maxPartSize = 50
+
+	parts := splitMultipart(mid, payload, maxPartSize)
+	if expected := 8; len(parts) != expected {
+		t.Fatalf("Unexpected number of parts.\nexpected: %d\nreceived: %d",
+			expected, len(parts))
+	}
+	for i, part := range parts {
+		if len(part) > maxPartSize {
+			t.Errorf("Part %d of %d bytes is larger than %d bytes.",
+				i, len(part), maxPartSize)
+		}
+	}
+
+	var received [][]byte
+	var receivedEncrypted []byte
+	var receivedRound rounds.Round
+	r := newReassembler(func(payload, encryptedPayload []byte, _ []string,
+		_ [2]byte, _ receptionID.EphemeralIdentity, round rounds.Round) {
+		received = append(received, payload)
+		receivedEncrypted = encryptedPayload
+		receivedRound = round
+	})
+
+	for _, i := range []int{3, 0, 7, 3, 1, 6, 2, 5} {
+		r.receive(parts[i], []byte{byte(i)}, nil, [2]byte{},
+			receptionID.EphemeralIdentity{}, rounds.Round{ID: id.Round(100 + i)})
+	}
+	if len(received) != 0 {
+		t.Fatalf("Listener called before every part was received.")
+	}
+
+	r.receive(parts[4], []byte{4}, nil, [2]byte{},
+		receptionID.EphemeralIdentity{}, rounds.Round{ID: 104})
+	expectedEncrypted := []byte{0, 1, 2, 3, 4, 5, 6, 7}
+	if !bytes.Equal(expectedEncrypted, receivedEncrypted) {
+		t.Errorf("Encrypted parts not joined in order."+
+			"\nexpected: %v\nreceived: %v", expectedEncrypted, receivedEncrypted)
+	}
+	if len(received) != 1 || !bytes.Equal(payload, received[0]) {
+		t.Errorf("Unexpected reassembled payload.\nexpected: %x\nreceived: %x",
+			payload, received)
+	} else if receivedRound.ID != 100 {
+		t.Errorf("Unexpected round.\nexpected: %d\nreceived: %d",
+			100, receivedRound.ID)
+	} else if len(r.messages) != 0 {
+		t.Errorf("Reassembled message not removed: %+v", r.messages)
+	}
+}
+
+// Tests that joinMultipart joins parts given in order and returns an error for
+// parts that are missing, out of order, or from different messages.
+func Test_joinMultipart(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	payload := make([]byte, 250)
+	prng.Read(payload)
+	parts := splitMultipart(multipartID{1}, payload, 50)
+
+	joined, err := joinMultipart(parts)
+	if err != nil {
+		t.Fatalf("Failed to join parts: %+v", err)
+	} else if !bytes.Equal(payload, joined) {
+		t.Errorf("Unexpected joined payload.\nexpected: %x\nreceived: %x",
+			payload, joined)
+	}
+
+	other := splitMultipart(multipartID{2}, payload, 50)
+	swapped := append([][]byte{}, parts...)
+	swapped[1], swapped[2] = swapped[2], swapped[1]
+	mixed := append([][]byte{}, parts...)
+	mixed[3] = other[3]
+	for name, bad := range map[string][][]byte{
+		"missing":   parts[:len(parts)-1],
+		"swapped":   swapped,
+		"mixed":     mixed,
+		"truncated": {parts[0][:multipartHeaderLen-1]},
+	} {
+		if _, err = joinMultipart(bad); err == nil {
+			t.Errorf("Did not error joining %s parts.", name)
+		}
+	}
+}
+
+// Tests that the reassembler drops messages that time out and the oldest
+// message when too many are being reassembled.
+func Test_reassembler_Limits(t *testing.T) {
+	payload := make([]byte, 100)
+	r := newReassembler(func([]byte, []byte, []string, [2]byte,
+		receptionID.EphemeralIdentity, rounds.Round) {
+		t.Errorf("Listener called for incomplete message.")
+	})
+	r.maxPending = 2
+
+	for i := byte(0); i < 3; i++ {
+		parts := splitMultipart(multipartID{i}, payload, 50)
+		r.receive(parts[0], nil, nil, [2]byte{},
+			receptionID.EphemeralIdentity{}, rounds.Round{})
+		r.messages[multipartID{i}].started =
+			netTime.Now().Add(time.Duration(i) * time.Second)
+	}
+
+	if _, exists := r.messages[multipartID{0}]; exists || len(r.messages) != 2 {
+		t.Errorf("Oldest message not dropped: %+v", r.messages)
+	}
+
+	r.prune(netTime.Now().Add(r.timeout + 3*time.Second/2))
+	if _, exists := r.messages[multipartID{2}]; !exists || len(r.messages) != 1 {
+		t.Errorf("Expected only the newest message to remain: %+v", r.messages)
+	}
+
+	// Parts with an invalid header are dropped
+	r.receive([]byte{1, 2, 3}, nil, nil, [2]byte{},
+		receptionID.EphemeralIdentity{}, rounds.Round{})
+	invalid := splitMultipart(multipartID{9}, payload, 50)[0]
+	invalid[multipartIndexPos] = invalid[multipartCountPos]
+	r.receive(invalid, nil, nil, [2]byte{},
+		receptionID.EphemeralIdentity{}, rounds.Round{})
+	if len(r.messages) != 1 {
+		t.Errorf("Invalid parts were stored: %+v", r.messages)
+	}
+}
+
+// Tests that the reassembler passes a complete message to the listener once
+// and ignores parts of it that are received again.
+func Test_reassembler_Completed(t *testing.T) {
+	payload := make([]byte, 100)
+	rand.New(rand.NewSource(42)).Read(payload)
+	var received [][]byte
+	r := newReassembler(func(payload, _ []byte, _ []string, _ [2]byte,
+		_ receptionID.EphemeralIdentity, _ rounds.Round) {
+		received = append(received, payload)
+	})
+
+	parts := splitMultipart(multipartID{1}, payload, 50)
+	for _, part := range append(parts, parts[0]) {
+		r.receive(part, nil, nil, [2]byte{},
+			receptionID.EphemeralIdentity{}, rounds.Round{})
+	}
+
+	if len(received) != 1 || !bytes.Equal(payload, received[0]) {
+		t.Errorf("Unexpected reassembled payloads.\nexpected: %x"+
+			"\nreceived: %x", payload, received)
+	}
+	if len(r.messages) != 0 {
+		t.Errorf("Replayed part of a completed message stored: %+v",
+			r.messages)
+	}
+}
@@ -9,16 +9,26 @@ package broadcast
 
 import (
 	"encoding/binary"
+
+	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	crypto "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/rsa"
 	"gitlab.com/elixxir/primitives/format"
 )
 
 // Error messages.
 const (
 	errDecrypt = "[BCAST] Failed to decrypt payload for broadcast %s (%q): %+v"
+
+	// processor.ProcessAdminMessage
+	errMultipartCiphertextSize = "ciphertext of %d bytes is not a " +
+		"multiple of the %d-byte ciphertext of a single message"
+
+	// processor.decryptRSAToPublicInner
+	errPayloadLength = "payload length %d is longer than the decrypted payload"
 )
 
 // processor handles channel message decryption and handling. This structure
@@ -55,20 +65,68 @@ func (p *processor) Process(msg format.Message, tags []string, metadata []byte,
 }
 
 // ProcessAdminMessage decrypts an admin message and sends the results on
-// the callback.
+// the callback. The inner ciphertext may be the inner ciphertexts of every
+// part of a multi-part message joined in order, in which case the parts are
+// joined and the full payload is sent on the callback.
 func (p *processor) ProcessAdminMessage(innerCiphertext []byte,
 	tags []string, metadata [2]byte, receptionID receptionID.EphemeralIdentity,
 	round rounds.Round) {
-	decrypted, err := p.c.DecryptRSAToPublicInner(innerCiphertext)
+	partSize := rsaToPublicInnerCiphertextSize(p.c)
+	if len(innerCiphertext) <= partSize {
+		payload, err := p.decryptRSAToPublicInner(innerCiphertext)
+		if err != nil {
+			jww.ERROR.Printf(errDecrypt, p.c.ReceptionID, p.c.Name, err)
+			return
+		}
+		p.cb(payload, innerCiphertext, tags, metadata, receptionID, round)
+		return
+	}
+
+	if len(innerCiphertext)%partSize != 0 {
+		jww.ERROR.Printf(errDecrypt, p.c.ReceptionID, p.c.Name, errors.Errorf(
+			errMultipartCiphertextSize, len(innerCiphertext), partSize))
+		return
+	}
+	parts := make([][]byte, len(innerCiphertext)/partSize)
+	for i := range parts {
+		var err error
+		parts[i], err = p.decryptRSAToPublicInner(
+			innerCiphertext[i*partSize : (i+1)*partSize])
+		if err != nil {
+			jww.ERROR.Printf(errDecrypt, p.c.ReceptionID, p.c.Name, err)
+			return
+		}
+	}
+
+	payload, err := joinMultipart(parts)
 	if err != nil {
 		jww.ERROR.Printf(errDecrypt, p.c.ReceptionID, p.c.Name, err)
 		return
 	}
+	p.cb(payload, innerCiphertext, tags, metadata, receptionID, round)
+}
+
+// decryptRSAToPublicInner decrypts the inner ciphertext of a single admin
+// message and returns its payload.
+func (p *processor) decryptRSAToPublicInner(
+	innerCiphertext []byte) ([]byte, error) {
+	decrypted, err := p.c.DecryptRSAToPublicInner(innerCiphertext)
+	if err != nil {
+		return nil, err
+	}
 	size := binary.BigEndian.Uint16(decrypted[:internalPayloadSizeLength])
-	payload :=
-		decrypted[internalPayloadSizeLength : size+internalPayloadSizeLength]
+	if int(size)+internalPayloadSizeLength > len(decrypted) {
+		return nil, errors.Errorf(errPayloadLength, size)
+	}
+	return decrypted[internalPayloadSizeLength : size+internalPayloadSizeLength],
+		nil
+}
 
-	p.cb(payload, innerCiphertext, tags, metadata, receptionID, round)
+// rsaToPublicInnerCiphertextSize returns the size of the inner ciphertext of a
+// single admin message on the channel.
+func rsaToPublicInnerCiphertextSize(c *crypto.Channel) int {
+	return c.RsaPubKeyLength*c.RSASubPayloads +
+		rsa.GetScheme().GetMarshalWireLength(c.RsaPubKeyLength)
 }
 
 // String returns a string identifying the symmetricProcessor for debugging
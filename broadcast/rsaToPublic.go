@@ -77,43 +77,17 @@ func (bc *broadcastClient) BroadcastRSAToPublicWithAssembler(
 				nil, errors.Errorf(errPayloadSize, len(payload),
 					bc.MaxRSAToPublicPayloadSize())
 		}
-		payloadLength := uint16(len(payload))
 
-		finalPayload := make([]byte, bc.maxRSAToPublicPayloadSizeRaw())
-		binary.BigEndian.PutUint16(finalPayload[:internalPayloadSizeLength],
-			payloadLength)
-		copy(finalPayload[internalPayloadSizeLength:], payload)
-
-		// Encrypt payload
-		singleEncryptedPayload, encryptedPayload, mac, fp, err =
-			bc.channel.EncryptRSAToPublic(finalPayload, pk,
-				bc.net.GetMaxMessageLength(), bc.rng.GetStream())
-		if err != nil {
-			return format.Fingerprint{}, message.Service{}, nil,
-				nil, errors.WithMessage(err,
-					"Failed to encrypt asymmetric broadcast message")
+		if cMixParams.DebugTag == cmix.DefaultDebugTag {
+			cMixParams.DebugTag = asymmCMixSendTag
 		}
 
 		// Create service using asymmetric broadcast service tag and channel
 		// reception ID allows anybody with this info to listen for messages on
 		// this channel
-		service = bc.GetRSAToPublicCompressedService(tags, metadata)
-
-		if cMixParams.DebugTag == cmix.DefaultDebugTag {
-			cMixParams.DebugTag = asymmCMixSendTag
-		}
-
-		// Create payload sized for sending over cmix
-		sizedPayload := make([]byte, bc.net.GetMaxMessageLength())
-		// Read random data into sized payload
-		_, err = bc.rng.GetStream().Read(sizedPayload)
-		if err != nil {
-			return format.Fingerprint{}, message.Service{}, nil,
-				nil, errors.WithMessage(err, "Failed to add "+
-					"random data to sized broadcast")
-		}
-		copy(sizedPayload[:len(encryptedPayload)], encryptedPayload)
-
+		singleEncryptedPayload, fp, service, encryptedPayload, mac, err =
+			bc.encryptRSAToPublic(pk, payload,
+				bc.GetRSAToPublicCompressedService(tags, metadata))
 		return
 	}
 
@@ -122,6 +96,43 @@ func (bc *broadcastClient) BroadcastRSAToPublicWithAssembler(
 	return singleEncryptedPayload, r, ephID, err
 }
 
+// encryptRSAToPublic pads and encrypts the payload with the channel's private
+// key and returns the inner ciphertext and the outer message with the service
+// in the form of a [cmix.MessageAssembler].
+func (bc *broadcastClient) encryptRSAToPublic(pk rsa.PrivateKey, payload []byte,
+	service message.CompressedService) ([]byte, format.Fingerprint,
+	cmix.Service, []byte, []byte, error) {
+	payloadLength := uint16(len(payload))
+
+	finalPayload := make([]byte, bc.maxRSAToPublicPayloadSizeRaw())
+	binary.BigEndian.PutUint16(finalPayload[:internalPayloadSizeLength],
+		payloadLength)
+	copy(finalPayload[internalPayloadSizeLength:], payload)
+
+	// Encrypt payload
+	singleEncryptedPayload, encryptedPayload, mac, fp, err :=
+		bc.channel.EncryptRSAToPublic(finalPayload, pk,
+			bc.net.GetMaxMessageLength(), bc.rng.GetStream())
+	if err != nil {
+		return nil, format.Fingerprint{}, message.Service{}, nil, nil,
+			errors.WithMessage(err,
+				"Failed to encrypt asymmetric broadcast message")
+	}
+
+	// Create payload sized for sending over cmix
+	sizedPayload := make([]byte, bc.net.GetMaxMessageLength())
+	// Read random data into sized payload
+	_, err = bc.rng.GetStream().Read(sizedPayload)
+	if err != nil {
+		return nil, format.Fingerprint{}, message.Service{}, nil, nil,
+			errors.WithMessage(err, "Failed to add "+
+				"random data to sized broadcast")
+	}
+	copy(sizedPayload[:len(encryptedPayload)], encryptedPayload)
+
+	return singleEncryptedPayload, fp, service, encryptedPayload, mac, nil
+}
+
 func (bc *broadcastClient) GetRSAToPublicCompressedService(
 	tags []string, metadata [2]byte) message.CompressedService {
 	return message.CompressedService{
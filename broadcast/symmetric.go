@@ -70,30 +70,36 @@ func (bc *broadcastClient) BroadcastWithAssembler(assembler Assembler,
 				errors.Errorf(errPayloadSize, len(payload), bc.maxSymmetricPayload())
 		}
 
-		// Encrypt payload
-		rng := bc.rng.GetStream()
-		defer rng.Close()
-		encryptedPayload, mac, fp, err = bc.channel.EncryptSymmetric(payload,
-			bc.net.GetMaxMessageLength(), rng)
-		if err != nil {
-			return format.Fingerprint{}, message.Service{},
-				nil, nil, err
+		if cMixParams.DebugTag == cmix.DefaultDebugTag {
+			cMixParams.DebugTag = symmCMixSendTag
 		}
 
 		// Create service using symmetric broadcast service tag & channel reception ID
 		// Allows anybody with this info to listen for messages on this channel
-		service = bc.GetSymmetricCompressedService(tags, metadata)
-
-		if cMixParams.DebugTag == cmix.DefaultDebugTag {
-			cMixParams.DebugTag = symmCMixSendTag
-		}
-		return
+		return bc.encryptSymmetric(
+			payload, bc.GetSymmetricCompressedService(tags, metadata))
 	}
 
 	return bc.net.SendWithAssembler(
 		bc.channel.ReceptionID, assemble, cMixParams)
 }
 
+// encryptSymmetric encrypts the payload for the symmetric channel and returns
+// it with the service in the form of a [cmix.MessageAssembler].
+func (bc *broadcastClient) encryptSymmetric(payload []byte,
+	service message.CompressedService) (format.Fingerprint, cmix.Service,
+	[]byte, []byte, error) {
+	rng := bc.rng.GetStream()
+	defer rng.Close()
+	encryptedPayload, mac, fp, err := bc.channel.EncryptSymmetric(payload,
+		bc.net.GetMaxMessageLength(), rng)
+	if err != nil {
+		return format.Fingerprint{}, message.Service{}, nil, nil, err
+	}
+
+	return fp, service, encryptedPayload, mac, nil
+}
+
 func (bc *broadcastClient) GetSymmetricCompressedService(
 	tags []string, metadata [2]byte) message.CompressedService {
 	return message.CompressedService{
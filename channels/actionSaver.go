@@ -8,6 +8,7 @@
 package channels
 
 import (
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
 	"sync"
@@ -41,6 +42,9 @@ const (
 // ActionSaver saves actions that are received that do not apply to any message
 // in storage. The actions are saved and checked against each new message to see
 // if they apply.
+//
+// Only the newest action on a message is kept, except for poll votes and user
+// edits, of which the newest from each sender is kept.
 type ActionSaver struct {
 	// actions is a map of actions that do not belong to any received messages
 	// mapped to their target message and channel.
@@ -140,12 +144,13 @@ func (as *ActionSaver) purge(now time.Time) error {
 }
 
 // AddAction inserts the saved action into the ordered list and map keyed on the
-// target message.
+// target message. The public key of the sender is passed to the action when it
+// is triggered.
 func (as *ActionSaver) AddAction(channelID *id.ID, messageID,
 	targetMessage message.ID, action MessageType, content,
-	encryptedPayload []byte, timestamp, originatingTimestamp, received time.Time,
-	lease time.Duration, originatingRound id.Round, round rounds.Round,
-	fromAdmin bool) error {
+	encryptedPayload []byte, pubKey ed25519.PublicKey, timestamp,
+	originatingTimestamp, received time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, fromAdmin bool) error {
 
 	// Generate the savedAction object to save
 	sa := &savedAction{
@@ -157,6 +162,7 @@ func (as *ActionSaver) AddAction(channelID *id.ID, messageID,
 			MessageType:          action,
 			Content:              content,
 			EncryptedPayload:     encryptedPayload,
+			PubKey:               pubKey,
 			Timestamp:            timestamp.Round(0).UTC(),
 			OriginatingTimestamp: originatingTimestamp.Round(0).UTC(),
 			Lease:                lease,
@@ -176,7 +182,7 @@ func (as *ActionSaver) addAction(sa *savedAction) error {
 	// When set to true, the list of channels IDs will be updated in storage
 	var channelIdUpdate bool
 
-	key := getMessageIdKey(sa.TargetMessage)
+	key := sa.key()
 
 	as.mux.Lock()
 	defer as.mux.Unlock()
@@ -221,43 +227,70 @@ type UpdateActionFn func() (uint64, error)
 // channel ID. If there are no saved actions for the message, then this function
 // returns nil and false. If there is a saved delete action, then
 // CheckSavedActions returns true and the message should be dropped and not be
-// passed to the event model. If there is a non-delete action, then an
+// passed to the event model. If there are non-delete actions, then an
 // UpdateActionFn is returned that must be called after the message is passed to
-// the event model.
+// the event model. It triggers every saved action on the message and returns
+// the UUID of the last one.
 func (as *ActionSaver) CheckSavedActions(
 	channelID *id.ID, targetMessage message.ID) (UpdateActionFn, bool) {
 	as.mux.RLock()
 	defer as.mux.RUnlock()
 
-	if messages, exists := as.actions[*channelID]; exists {
-		if sa, exists2 := messages[getMessageIdKey(targetMessage)]; exists2 {
-			// Once the result has been returned, delete the action
-			defer func(sa *savedAction) {
-				go func(sa *savedAction) {
-					as.mux.Lock()
-					defer as.mux.Unlock()
-					if err := as.deleteAction(sa); err != nil {
-						jww.ERROR.Printf(
-							"[CH] Failed to delete saved action: %+v", err)
-					}
-				}(sa)
-			}(sa)
-
-			if sa.MessageType == Delete {
-				return nil, true
-			} else {
-				return func() (uint64, error) {
-					return as.triggerFn(sa.ChannelID, sa.MessageID,
-						sa.MessageType, actionSaveNickname, sa.Content,
-						sa.EncryptedPayload, sa.Timestamp,
-						sa.OriginatingTimestamp, sa.Lease, sa.OriginatingRound,
-						sa.Round, sa.Status, sa.FromAdmin)
-				}, false
-			}
+	messages, exists := as.actions[*channelID]
+	if !exists {
+		return nil, false
+	}
+
+	var actions []*savedAction
+	if sa, exists2 := messages[getMessageIdKey(targetMessage)]; exists2 {
+		actions = append(actions, sa)
+	}
+
+	// Poll votes and user edits are keyed on the sender, so they are found by
+	// their target
+	for _, sa := range messages {
+		if sa.keyedOnSender() && sa.TargetMessage == targetMessage {
+			actions = append(actions, sa)
 		}
 	}
 
-	return nil, false
+	if len(actions) == 0 {
+		return nil, false
+	}
+
+	// Once the result has been returned, delete the actions
+	defer func(actions []*savedAction) {
+		go func(actions []*savedAction) {
+			as.mux.Lock()
+			defer as.mux.Unlock()
+			for _, sa := range actions {
+				if err := as.deleteAction(sa); err != nil {
+					jww.ERROR.Printf(
+						"[CH] Failed to delete saved action: %+v", err)
+				}
+			}
+		}(actions)
+	}(actions)
+
+	if actions[0].MessageType == Delete {
+		return nil, true
+	}
+
+	return func() (uint64, error) {
+		var uuid uint64
+		for _, sa := range actions {
+			var err error
+			uuid, err = as.triggerFn(sa.ChannelID, sa.MessageID,
+				sa.MessageType, actionSaveNickname, sa.Content,
+				sa.EncryptedPayload, sa.PubKey, sa.Timestamp,
+				sa.OriginatingTimestamp, sa.Lease, sa.OriginatingRound,
+				sa.Round, sa.Status, sa.FromAdmin)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return uuid, nil
+	}, false
 }
 
 // deleteAction removes the action from the map. This function also updates
@@ -265,7 +298,7 @@ func (as *ActionSaver) CheckSavedActions(
 // thread safe.
 func (as *ActionSaver) deleteAction(sa *savedAction) error {
 	var loadedSa *savedAction
-	key := getMessageIdKey(sa.TargetMessage)
+	key := sa.key()
 	if messages, exists := as.actions[*sa.ChannelID]; !exists {
 		return nil
 	} else if loadedSa, exists = messages[key]; !exists {
@@ -320,6 +353,25 @@ func getMessageIdKey(msgID message.ID) messageIdKey {
 	return messageIdKey(base64.StdEncoding.EncodeToString(msgID.Marshal()))
 }
 
+// key returns the key of the saved action in the map of actions for its
+// channel. Poll votes and user edits are keyed on the target message and the
+// sender so that they do not replace the actions of other users or the admin on
+// the message. All other actions are keyed on their target message.
+func (sa *savedAction) key() messageIdKey {
+	key := getMessageIdKey(sa.TargetMessage)
+	if sa.keyedOnSender() {
+		key += messageIdKey("/" + base64.StdEncoding.EncodeToString(sa.PubKey))
+	}
+	return key
+}
+
+// keyedOnSender returns true if the action is saved per sender. Poll votes are
+// saved per voter and edits not made by the admin are saved per sender, since
+// the sender is only checked against the target message once it arrives.
+func (sa *savedAction) keyedOnSender() bool {
+	return sa.MessageType == PollVote || (sa.MessageType == Edit && !sa.FromAdmin)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Storage Functions                                                          //
 ////////////////////////////////////////////////////////////////////////////////
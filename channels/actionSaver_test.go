@@ -9,6 +9,7 @@ package channels
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"fmt"
 	"math/rand"
 	"os"
@@ -125,8 +126,9 @@ func TestActionSaver_AddAction(t *testing.T) {
 		},
 	}
 	err := as.AddAction(e.ChannelID, e.MessageID, e.TargetMessage, e.MessageType,
-		e.Content, e.EncryptedPayload, e.Timestamp, e.OriginatingTimestamp,
-		e.Received, e.Lease, e.OriginatingRound, e.Round, e.FromAdmin)
+		e.Content, e.EncryptedPayload, e.PubKey, e.Timestamp,
+		e.OriginatingTimestamp, e.Received, e.Lease, e.OriginatingRound, e.Round,
+		e.FromAdmin)
 	if err != nil {
 		t.Fatalf("Failed to add action: %+v", err)
 	}
@@ -226,9 +228,10 @@ func TestActionSaver_CheckSavedActions(t *testing.T) {
 	const expectedUUID = 5
 	triggerFn := func(channelID *id.ID, messageID message.ID,
 		messageType MessageType, nickname string, payload,
-		encryptedPayload []byte, timestamp, originatingTimestamp time.Time,
-		lease time.Duration, originatingRound id.Round, round rounds.Round,
-		status SentStatus, fromAdmin bool) (uint64, error) {
+		encryptedPayload []byte, pubKey ed25519.PublicKey, timestamp,
+		originatingTimestamp time.Time, lease time.Duration,
+		originatingRound id.Round, round rounds.Round, status SentStatus,
+		fromAdmin bool) (uint64, error) {
 		return expectedUUID, nil
 	}
 	as := NewActionSaver(triggerFn, versioned.NewKV(ekv.MakeMemstore()))
@@ -559,7 +562,7 @@ func TestActionSaver_load_ChannelListLoadError(t *testing.T) {
 
 	channelID := randChannelID(prng, t)
 	targetID := randMessageID(prng, t)
-	err := as.AddAction(channelID, message.ID{}, targetID, 0, nil, nil,
+	err := as.AddAction(channelID, message.ID{}, targetID, 0, nil, nil, nil,
 		time.Time{}, time.Time{}, time.Time{}, 0, 0, rounds.Round{}, false)
 	if err != nil {
 		t.Fatalf("Failed to add action: %+v", err)
@@ -592,7 +595,7 @@ func TestActionSaver_load_MessagesLoadError(t *testing.T) {
 
 	channelID := randChannelID(prng, t)
 	targetID := randMessageID(prng, t)
-	err := as.AddAction(channelID, message.ID{}, targetID, 0, nil, nil,
+	err := as.AddAction(channelID, message.ID{}, targetID, 0, nil, nil, nil,
 		time.Time{}, time.Time{}, time.Time{}, 0, 0, rounds.Round{}, false)
 	if err != nil {
 		t.Fatalf("Failed to add action: %+v", err)
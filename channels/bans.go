@@ -0,0 +1,199 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// MaxBanReasonLength is the maximum length, in bytes, of the reason given
+// when banning a user. Bans are sent as admin messages, which have a small
+// payload.
+const MaxBanReasonLength = 128
+
+// BannedUser describes a user banned from a channel.
+type BannedUser struct {
+	// PubKey is the Ed25519 public key of the banned user.
+	PubKey ed25519.PublicKey `json:"pubKey"`
+
+	// Reason is the reason given by the channel admin for the ban.
+	Reason string `json:"reason"`
+
+	// Timestamp is the time the ban was sent.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Muted is true if the ban muted the user. It is false if the user was
+	// already muted when banned, in which case they stay muted once unbanned.
+	Muted bool `json:"muted"`
+}
+
+// banManager manages the list of banned users in each channel. Users are
+// banned and unbanned by the channel admin with a Ban message.
+type banManager struct {
+	// List of banned users in each channel. The internal map keys on the hex
+	// encoded ed25519.PublicKey of the banned user.
+	list map[id.ID]map[string]BannedUser
+
+	mux sync.RWMutex
+	kv  versioned.KV
+}
+
+// newOrLoadBanManager loads an existing banManager from storage, if it exists.
+// Otherwise, it initialises a new empty banManager.
+func newOrLoadBanManager(kv versioned.KV) (*banManager, error) {
+	bm := &banManager{
+		list: make(map[id.ID]map[string]BannedUser),
+		kv:   kv,
+	}
+
+	err := bm.load()
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	return bm, nil
+}
+
+// banUser adds the user to the ban list for the given channel. If the user is
+// already banned, then the reason and timestamp are replaced, but whether the
+// original ban muted the user is kept.
+func (bm *banManager) banUser(channelID *id.ID, banned BannedUser) {
+	bm.mux.Lock()
+	defer bm.mux.Unlock()
+
+	if _, exists := bm.list[*channelID]; !exists {
+		bm.list[*channelID] = make(map[string]BannedUser)
+	}
+	key := hex.EncodeToString(banned.PubKey)
+	if current, exists := bm.list[*channelID][key]; exists {
+		banned.Muted = current.Muted
+	}
+	bm.list[*channelID][key] = banned
+
+	if err := bm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save banned users: %+v", err)
+	}
+}
+
+// unbanUser removes the user from the ban list for the given channel. Returns
+// the removed ban and true if the user was banned.
+func (bm *banManager) unbanUser(
+	channelID *id.ID, pubKey ed25519.PublicKey) (BannedUser, bool) {
+	bm.mux.Lock()
+	defer bm.mux.Unlock()
+
+	key := hex.EncodeToString(pubKey)
+	banned, exists := bm.list[*channelID][key]
+	if !exists {
+		return BannedUser{}, false
+	}
+
+	bannedUsers := bm.list[*channelID]
+	delete(bannedUsers, key)
+	if len(bannedUsers) == 0 {
+		delete(bm.list, *channelID)
+	}
+
+	if err := bm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save banned users: %+v", err)
+	}
+
+	return banned, true
+}
+
+// isBanned returns true if the user is banned in the given channel.
+func (bm *banManager) isBanned(
+	channelID *id.ID, pubKey ed25519.PublicKey) bool {
+	bm.mux.RLock()
+	defer bm.mux.RUnlock()
+
+	_, exists := bm.list[*channelID][hex.EncodeToString(pubKey)]
+	return exists
+}
+
+// getBannedUsers returns every banned user in the given channel, sorted by
+// public key. Returns an empty list if no users are banned in the channel.
+func (bm *banManager) getBannedUsers(channelID *id.ID) []BannedUser {
+	bm.mux.RLock()
+	defer bm.mux.RUnlock()
+
+	bannedUsers := make([]BannedUser, 0, len(bm.list[*channelID]))
+	for _, banned := range bm.list[*channelID] {
+		bannedUsers = append(bannedUsers, banned)
+	}
+	sort.Slice(bannedUsers, func(i, j int) bool {
+		return bytes.Compare(bannedUsers[i].PubKey, bannedUsers[j].PubKey) < 0
+	})
+
+	return bannedUsers
+}
+
+// removeChannel deletes the ban list for the given channel. This should only be
+// called when leaving a channel.
+func (bm *banManager) removeChannel(channelID *id.ID) error {
+	bm.mux.Lock()
+	defer bm.mux.Unlock()
+
+	if _, exists := bm.list[*channelID]; !exists {
+		return nil
+	}
+
+	delete(bm.list, *channelID)
+	return bm.save()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage values.
+const (
+	banListStoreVer = 0
+	banListStoreKey = "banList"
+)
+
+// save stores the ban list of every channel to storage.
+func (bm *banManager) save() error {
+	data, err := json.Marshal(bm.list)
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   banListStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return bm.kv.Set(banListStoreKey, obj)
+}
+
+// load gets the ban list of every channel from storage.
+func (bm *banManager) load() error {
+	obj, err := bm.kv.Get(banListStoreKey, banListStoreVer)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(obj.Data, &bm.list); err != nil {
+		return errors.Wrap(err, "could not unmarshal ban list")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+)
+
+// Tests that newOrLoadBanManager initialises a new empty banManager and loads
+// the ban list saved by a previous one.
+func Test_newOrLoadBanManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+
+	bm, err := newOrLoadBanManager(kv)
+	require.NoError(t, err)
+	require.Empty(t, bm.list)
+
+	channelID := randChannelID(prng, t)
+	banned := BannedUser{
+		PubKey:    makeEd25519PubKey(prng, t),
+		Reason:    "spam",
+		Timestamp: time.Unix(5, 0).UTC(),
+	}
+	bm.banUser(channelID, banned)
+
+	loaded, err := newOrLoadBanManager(kv)
+	require.NoError(t, err)
+	require.Equal(t, []BannedUser{banned}, loaded.getBannedUsers(channelID))
+}
+
+// Tests that banManager.isBanned only returns true for users banned with
+// banManager.banUser and not unbanned with banManager.unbanUser.
+func Test_banManager_banUser_unbanUser(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	bm, err := newOrLoadBanManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+
+	channelID := randChannelID(prng, t)
+	alice, bob := makeEd25519PubKey(prng, t), makeEd25519PubKey(prng, t)
+	bm.banUser(channelID, BannedUser{PubKey: alice, Reason: "spam"})
+	bm.banUser(channelID, BannedUser{PubKey: bob, Reason: "spam"})
+	bm.unbanUser(channelID, bob)
+
+	require.True(t, bm.isBanned(channelID, alice))
+	require.False(t, bm.isBanned(channelID, bob))
+	require.False(t, bm.isBanned(randChannelID(prng, t), alice))
+
+	// Banning again replaces the reason
+	bm.banUser(channelID, BannedUser{PubKey: alice, Reason: "abuse"})
+	require.Equal(t, "abuse", bm.getBannedUsers(channelID)[0].Reason)
+
+	// Unbanning in an unknown channel does nothing
+	bm.unbanUser(randChannelID(prng, t), alice)
+	require.True(t, bm.isBanned(channelID, alice))
+}
+
+// Tests that banManager.getBannedUsers returns the banned users sorted by
+// public key and an empty list for a channel with no bans.
+func Test_banManager_getBannedUsers(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	bm, err := newOrLoadBanManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+
+	channelID := randChannelID(prng, t)
+	expected := make([]BannedUser, 10)
+	for i := range expected {
+		expected[i] = BannedUser{PubKey: makeEd25519PubKey(prng, t)}
+		bm.banUser(channelID, expected[i])
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i].PubKey, expected[j].PubKey) < 0
+	})
+
+	require.Equal(t, expected, bm.getBannedUsers(channelID))
+	require.Equal(t, []BannedUser{}, bm.getBannedUsers(randChannelID(prng, t)))
+}
+
+// Tests that banManager.removeChannel removes the channel from the list and
+// from storage.
+func Test_banManager_removeChannel(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	bm, err := newOrLoadBanManager(kv)
+	require.NoError(t, err)
+
+	channelID := randChannelID(prng, t)
+	pubKey := makeEd25519PubKey(prng, t)
+	bm.banUser(channelID, BannedUser{PubKey: pubKey})
+
+	require.NoError(t, bm.removeChannel(channelID))
+	require.NotContains(t, bm.list, *channelID)
+
+	loaded, err := newOrLoadBanManager(kv)
+	require.NoError(t, err)
+	require.False(t, loaded.isBanned(channelID, pubKey))
+}
+
+// Tests that manager.BanUser sends a Ban message with the user's public key
+// and the reason.
+func Test_manager_BanUser(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	pubKey := makeEd25519PubKey(rand.New(rand.NewSource(64)), t)
+
+	_, _, _, err := m.BanUser(ch.ReceptionID, pubKey, "spam", false,
+		ValidForever, cmix.CMIXParams{})
+	require.NoError(t, err)
+
+	chMsg := &ChannelMessage{}
+	require.NoError(t, proto.Unmarshal(mbc.payload, chMsg))
+	banMsg := &CMIXChannelBan{}
+	require.NoError(t, proto.Unmarshal(chMsg.Payload, banMsg))
+	require.Equal(t, []byte(pubKey), banMsg.PubKey)
+	require.Equal(t, "spam", banMsg.Reason)
+	require.False(t, banMsg.UndoAction)
+}
+
+// Error path: Tests that manager.BanUser returns NotAnAdminErr when the user
+// is not the admin of the channel.
+func Test_manager_BanUser_NotAdmin(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	require.NoError(t, m.DeleteChannelAdminKey(ch.ReceptionID))
+
+	_, _, _, err := m.BanUser(ch.ReceptionID,
+		makeEd25519PubKey(rand.New(rand.NewSource(64)), t), "spam", false,
+		ValidForever, cmix.CMIXParams{})
+	require.ErrorIs(t, err, NotAnAdminErr)
+	require.False(t, mbc.hasRun, "Message sent by non-admin.")
+}
+
+// Error path: Tests that manager.BanUser returns an error for an invalid
+// public key or a reason that is too long.
+func Test_manager_BanUser_InvalidInput(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	pubKey := makeEd25519PubKey(rand.New(rand.NewSource(64)), t)
+
+	_, _, _, err := m.BanUser(ch.ReceptionID, pubKey[:5], "spam", false,
+		ValidForever, cmix.CMIXParams{})
+	require.Error(t, err)
+
+	_, _, _, err = m.BanUser(ch.ReceptionID, pubKey,
+		strings.Repeat("a", MaxBanReasonLength+1), false, ValidForever,
+		cmix.CMIXParams{})
+	require.Error(t, err)
+	require.False(t, mbc.hasRun, "Invalid ban sent.")
+}
@@ -7,7 +7,11 @@
 
 package channels
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/scheduler"
+)
 
 var (
 	// ChannelAlreadyExistsErr is returned when attempting to join a channel
@@ -34,7 +38,36 @@ var (
 
 	// MessageTypeAlreadyRegistered is returned if a handler has already been
 	// registered with the supplied message type. Only one handler can be
-	// registered per type.
+	// registered per type, except for Text and AdminText.
 	MessageTypeAlreadyRegistered = errors.New(
 		"the given message type has already been registered")
+
+	// InvalidPollErr is returned when attempting to send a poll with fewer
+	// than two options or to vote for an option that does not exist.
+	InvalidPollErr = errors.New("the poll or poll option is invalid")
+
+	// TypingRateLimitErr is returned when a typing indicator is not sent
+	// because one was sent to the same channel within the last TypingInterval
+	// or because a stopped typing indicator was sent without a started one.
+	TypingRateLimitErr = errors.New("typing indicator rate limited")
+
+	// SlowModeErr is returned when a message is not sent because the channel
+	// is in slow mode and the user sent a message to it within the slow mode
+	// interval.
+	SlowModeErr = errors.New("channel is in slow mode")
+
+	// ScheduledSendNotFoundErr is returned when canceling or rescheduling a
+	// scheduled send that does not exist or was already sent.
+	ScheduledSendNotFoundErr = scheduler.NotFoundErr
+
+	// InvalidScheduleTimeErr is returned when scheduling a send at a time that
+	// is not in the future.
+	InvalidScheduleTimeErr = scheduler.InvalidTimeErr
+
+	// UnsupportedExtensionErr is returned by the event models returned by
+	// NewMultiEventModel and FilterReceived when an optional extension of
+	// EventModel, such as MessageQuerier, is called but the model that answers
+	// it does not implement it.
+	UnsupportedExtensionErr = errors.New(
+		"the event model does not implement the extension")
 )
@@ -163,6 +163,172 @@ type EventModel interface {
 
 	// MuteUser is called whenever a user is muted or unmuted.
 	MuteUser(channelID *id.ID, pubKey ed25519.PublicKey, unmute bool)
+
+	// EditMessage is called whenever the text of the message with the given
+	// [channel.MessageID] is edited by its sender or the channel admin. It may
+	// be called multiple times on the same edit and edits may arrive out of
+	// order, so the implementation must keep every MessageEdit as a revision
+	// and display the text of the one with the newest timestamp.
+	//
+	// The API needs to return the UUID of the edited message.
+	//
+	// Returns an error if the message cannot be edited. It must return
+	// NoMessageErr if the message does not exist.
+	EditMessage(messageID message.ID, edit MessageEdit) (uint64, error)
+
+	// ReceivePollVote is called whenever a vote is received for the poll with
+	// the given [channel.MessageID]. Each user, identified by
+	// ModelPollVote.PubKey, has at most one vote per poll; a vote replaces any
+	// vote from the same user with an older timestamp. The same vote may be
+	// received multiple times and must only be counted once. Votes with
+	// timestamps after the poll was closed must be ignored.
+	//
+	// The API needs to return the UUID of the poll.
+	//
+	// Returns an error if the vote cannot be saved. It must return NoMessageErr
+	// if the poll does not exist.
+	ReceivePollVote(pollID message.ID, vote ModelPollVote) (uint64, error)
+
+	// ClosePoll is called whenever the channel admin closes or reopens the poll
+	// with the given [channel.MessageID]. The timestamp is the time the poll was
+	// closed; votes sent after it must not be counted.
+	//
+	// The API needs to return the UUID of the poll.
+	//
+	// Returns an error if the poll cannot be updated. It must return
+	// NoMessageErr if the poll does not exist.
+	ClosePoll(pollID message.ID, timestamp time.Time, reopen bool) (
+		uint64, error)
+
+	// UpdateChannelMetadata is called whenever the channel admin updates the
+	// description, icon, topic, or rules of the channel. It is only called
+	// with an update newer than the last one it was called with, so the
+	// implementation may replace the stored metadata as is.
+	UpdateChannelMetadata(channelID *id.ID, metadata ChannelMetadata)
+}
+
+// MessageQuerier is an optional extension of EventModel for event models that
+// can return ranges of stored messages. Clients can check if their EventModel
+// supports queries via a type assertion and use it to page through history
+// without depending on the schema of the underlying storage.
+type MessageQuerier interface {
+	// QueryMessages returns the messages that match the MessageQuery, ordered
+	// from oldest to newest, and the total number of messages that match the
+	// query's filters, ignoring MessageQuery.Before, MessageQuery.After, and
+	// MessageQuery.Limit.
+	//
+	// It must return NoMessageErr if the message for a cursor does not exist.
+	QueryMessages(query MessageQuery) ([]ModelMessage, int64, error)
+}
+
+// PollTallier is an optional extension of EventModel for event models that
+// tally the votes of polls.
+type PollTallier interface {
+	// GetPollResults returns the tally of the votes of the poll with the given
+	// [channel.MessageID].
+	//
+	// It must return NoMessageErr if the poll does not exist.
+	GetPollResults(pollID message.ID) (PollResults, error)
+}
+
+// TextSpanReceiver is an optional extension of EventModel for event models
+// that store the structured content of text messages. After a Text message or
+// reply is received or edited, the spans returned by ParseText for its text
+// are passed to the model so that the UI does not need to parse the text
+// itself.
+type TextSpanReceiver interface {
+	// ReceiveTextSpans is called with the spans parsed from the text of the
+	// message with the UUID returned by ReceiveMessage, ReceiveReply, or
+	// EditMessage. The spans replace any previously received for the message.
+	// It is not called for received messages with no spans.
+	ReceiveTextSpans(uuid uint64, spans []TextSpan)
+}
+
+// Searcher is an optional extension of EventModel for event models that
+// support full-text search of stored messages.
+type Searcher interface {
+	// Search returns all messages whose text matches the SearchQuery, ordered
+	// from newest to oldest.
+	Search(query SearchQuery) ([]ModelMessage, error)
+}
+
+// PollResults is the tally of the votes of a poll.
+type PollResults struct {
+	// Counts is the number of votes for each option of the poll, in the same
+	// order as the options.
+	Counts []uint64 `json:"counts"`
+
+	// Closed is true if the channel admin has closed the poll.
+	Closed bool `json:"closed"`
+}
+
+// MessageQuery describes a range of messages in a channel to return from
+// MessageQuerier.QueryMessages. All fields except ChannelID are optional; zero
+// values apply no filter.
+type MessageQuery struct {
+	// ChannelID is the ID of the channel to get messages from.
+	ChannelID *id.ID `json:"channelID"`
+
+	// Start and End limit the results to messages with timestamps in the range
+	// [Start, End).
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Before and After are cursors that limit the results to messages sent
+	// before or after the message with the given ID. When Limit is set, the
+	// page immediately adjacent to the cursor is returned. If neither is set,
+	// the newest messages are returned.
+	Before *message.ID `json:"before,omitempty"`
+	After  *message.ID `json:"after,omitempty"`
+
+	// ParentMessageID limits the results to replies and reactions to the
+	// message with the given ID (i.e., a thread).
+	ParentMessageID *message.ID `json:"parentMessageID,omitempty"`
+
+	// PubKey limits the results to messages sent by the user with the given
+	// public key.
+	PubKey ed25519.PublicKey `json:"pubKey,omitempty"`
+
+	// PinnedOnly limits the results to pinned messages.
+	PinnedOnly bool `json:"pinnedOnly"`
+
+	// IncludeHidden includes hidden messages in the results.
+	IncludeHidden bool `json:"includeHidden"`
+
+	// Limit is the maximum number of messages to return. If it is zero, all
+	// matching messages are returned.
+	Limit int `json:"limit"`
+}
+
+// SearchQuery describes a full-text search of stored messages with
+// Searcher.Search. All fields except Text are optional; zero values apply no
+// filter.
+type SearchQuery struct {
+	// Text is the text to search for. Each word in the text must appear in a
+	// message for it to match. Matching is case-insensitive. A word ending in
+	// "*" matches any word starting with it (e.g., "hel*" matches "hello").
+	Text string `json:"text"`
+
+	// ChannelID limits the results to messages in the given channel.
+	ChannelID *id.ID `json:"channelID,omitempty"`
+
+	// SenderPubKey limits the results to messages sent by the given user.
+	SenderPubKey ed25519.PublicKey `json:"senderPubKey,omitempty"`
+
+	// Types limits the results to messages of the given types.
+	Types []MessageType `json:"types,omitempty"`
+
+	// Start and End limit the results to messages with timestamps in the range
+	// [Start, End).
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// IncludeHidden includes hidden messages in the results.
+	IncludeHidden bool `json:"includeHidden"`
+
+	// Limit is the maximum number of messages to return. If it is zero, all
+	// matching messages are returned.
+	Limit int `json:"limit"`
 }
 
 // NoMessageErr must be returned by EventModel methods (such as
@@ -200,6 +366,44 @@ type ModelMessage struct {
 	PubKey          ed25519.PublicKey `json:"pubKey"`
 	CodesetVersion  uint8             `json:"codesetVersion"`
 	DmToken         uint32            `json:"dmToken"`
+
+	// Spans are the structured contents of the text of the message. They are
+	// only set by event models that implement TextSpanReceiver.
+	Spans []TextSpan `json:"spans,omitempty"`
+}
+
+// MessageEdit describes a single revision of the text of a message.
+type MessageEdit struct {
+	// EditID is the [channel.MessageID] of the Edit message that contained this
+	// revision. It is empty for the original text of a message.
+	EditID message.ID `json:"editID"`
+
+	// Text is the text of the message for this revision.
+	Text string `json:"text"`
+
+	// Timestamp is the time the revision was sent.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Round is the ID of the round the revision was sent on.
+	Round id.Round `json:"round"`
+}
+
+// ModelPollVote describes a single vote on a poll.
+type ModelPollVote struct {
+	// MessageID is the [channel.MessageID] of the PollVote message.
+	MessageID message.ID `json:"messageID"`
+
+	// PubKey is the Ed25519 public key of the user that voted.
+	PubKey ed25519.PublicKey `json:"pubKey"`
+
+	// Option is the index of the option of the poll the user voted for.
+	Option uint32 `json:"option"`
+
+	// Timestamp is the time the vote was sent.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Round is the ID of the round the vote was sent on.
+	Round id.Round `json:"round"`
 }
 
 // MessageTypeReceiveMessage defines handlers for messages of various message
@@ -231,6 +435,11 @@ type events struct {
 	commandStore *CommandStore
 	leases       *ActionLeaseList
 	mutedUsers   *mutedUserManager
+	moderators   *moderatorManager
+	slowMode     *slowModeManager
+	flood        *floodFilter
+	bans         *banManager
+	metadata     *metadataManager
 	as           *ActionSaver
 
 	// List of registered message processors
@@ -239,6 +448,13 @@ type events struct {
 	// Used when creating new format.Message for replays
 	maxMessageLength int
 
+	// Called when a typing indicator is received; may be nil
+	userTyping func(channelID *id.ID, pubKey ed25519.PublicKey, codeset uint8,
+		nickname string, typing bool)
+
+	// Called when the admin rotates the channel key; may be nil
+	keyRotation func(channelID *id.ID, rsaPubKeyHash []byte)
+
 	mux sync.RWMutex
 }
 
@@ -346,14 +562,24 @@ func initEvents(model EventModel, maxMessageLength int, kv versioned.KV,
 
 	// Set up default message types
 	e.registered = map[MessageType]*ReceiveMessageHandler{
-		Text:        {"userTextMessage", e.receiveTextMessage, true, false, false},
-		AdminText:   {"adminTextMessage", e.receiveTextMessage, false, true, false},
-		Reaction:    {"reaction", e.receiveReaction, true, false, false},
-		Invitation:  {"invitation", e.receiveInvitation, true, false, false},
-		Delete:      {"delete", e.receiveDelete, true, true, false},
-		Pinned:      {"pinned", e.receivePinned, false, true, false},
-		Mute:        {"mute", e.receiveMute, false, true, false},
-		AdminReplay: {"adminReplay", e.receiveAdminReplay, true, true, false},
+		Text:           {"userTextMessage", e.receiveTextMessage, true, false, false},
+		AdminText:      {"adminTextMessage", e.receiveTextMessage, false, true, false},
+		Reaction:       {"reaction", e.receiveReaction, true, false, false},
+		Invitation:     {"invitation", e.receiveInvitation, true, false, false},
+		Delete:         {"delete", e.receiveDelete, true, true, false},
+		Pinned:         {"pinned", e.receivePinned, false, true, false},
+		Mute:           {"mute", e.receiveMute, false, true, false},
+		AdminReplay:    {"adminReplay", e.receiveAdminReplay, true, true, false},
+		Edit:           {"edit", e.receiveEdit, true, true, false},
+		Poll:           {"poll", e.receivePoll, true, true, false},
+		PollVote:       {"pollVote", e.receivePollVote, true, false, false},
+		PollClose:      {"pollClose", e.receivePollClose, false, true, false},
+		Moderator:      {"moderator", e.receiveModerator, false, true, false},
+		KeyRotation:    {"keyRotation", e.receiveKeyRotation, false, true, false},
+		SlowMode:       {"slowMode", e.receiveSlowMode, false, true, false},
+		Ban:            {"ban", e.receiveBan, false, true, false},
+		MetadataUpdate: {"metadataUpdate", e.receiveMetadataUpdate, false, true, false},
+		Typing:         {"typing", e.receiveTyping, true, false, false},
 	}
 
 	// Initialise list of message leases
@@ -370,6 +596,36 @@ func initEvents(model EventModel, maxMessageLength int, kv versioned.KV,
 		jww.FATAL.Panicf("[CH] Failed to initialise muted user list: %+v", err)
 	}
 
+	// Initialise list of moderators
+	e.moderators, err = newOrLoadModeratorManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise moderator list: %+v", err)
+	}
+
+	// Initialise slow mode intervals
+	e.slowMode, err = newOrLoadSlowModeManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise slow mode: %+v", err)
+	}
+
+	// Initialise local flood filter
+	e.flood, err = newOrLoadFloodFilter(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise flood filter: %+v", err)
+	}
+
+	// Initialise list of banned users
+	e.bans, err = newOrLoadBanManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise ban list: %+v", err)
+	}
+
+	// Initialise channel metadata
+	e.metadata, err = newOrLoadMetadataManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise channel metadata: %+v", err)
+	}
+
 	// Initialise action saver
 	e.as = NewActionSaver(e.triggerActionEvent, kv)
 
@@ -382,7 +638,11 @@ func initEvents(model EventModel, maxMessageLength int, kv versioned.KV,
 //
 // There can only be one handler per message type; the error
 // MessageTypeAlreadyRegistered will be returned on multiple registrations of
-// the same type.
+// the same type. The exception are the Text and AdminText types, whose handlers
+// are chained after the default handler so that modules (such as bots) can
+// observe text messages. Chained handlers are called after the message is
+// passed to the event model and receive messages in the same spaces as the
+// default handler; their returned UUID is ignored.
 //
 // To create a ReceiveMessageHandler, use NewReceiveMessageHandler.
 func (e *events) RegisterReceiveHandler(
@@ -393,8 +653,16 @@ func (e *events) RegisterReceiveHandler(
 	defer e.mux.Unlock()
 
 	// Check if the type is already registered
-	if _, exists := e.registered[messageType]; exists {
-		return MessageTypeAlreadyRegistered
+	if existing, exists := e.registered[messageType]; exists {
+		if messageType != Text && messageType != AdminText {
+			return MessageTypeAlreadyRegistered
+		}
+
+		// Chain the handler after the existing text handler
+		e.registered[messageType] = chainReceiveHandler(existing, handler)
+		jww.INFO.Printf("[CH] Chained listener %s to Message Type %s",
+			handler.name, messageType)
+		return nil
 	}
 
 	// Register the message type
@@ -403,6 +671,32 @@ func (e *events) RegisterReceiveHandler(
 	return nil
 }
 
+// chainReceiveHandler returns a ReceiveMessageHandler that calls the listener
+// of first and then the listener of next with the same message. It keeps the
+// spaces of first and returns the UUID returned by first.
+func chainReceiveHandler(
+	first, next *ReceiveMessageHandler) *ReceiveMessageHandler {
+	listener := func(channelID *id.ID, messageID message.ID,
+		messageType MessageType, nickname string, content,
+		encryptedPayload []byte, pubKey ed25519.PublicKey, dmToken uint32,
+		codeset uint8, timestamp, originatingTimestamp time.Time,
+		lease time.Duration, originatingRound id.Round, round rounds.Round,
+		status SentStatus, fromAdmin, hidden bool) uint64 {
+		uuid := first.listener(channelID, messageID, messageType, nickname,
+			content, encryptedPayload, pubKey, dmToken, codeset, timestamp,
+			originatingTimestamp, lease, originatingRound, round, status,
+			fromAdmin, hidden)
+		next.listener(channelID, messageID, messageType, nickname, content,
+			encryptedPayload, pubKey, dmToken, codeset, timestamp,
+			originatingTimestamp, lease, originatingRound, round, status,
+			fromAdmin, hidden)
+		return uuid
+	}
+
+	return NewReceiveMessageHandler(first.name+"+"+next.name, listener,
+		first.userSpace, first.adminSpace, first.mutedSpace)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Message Triggers                                                           //
 ////////////////////////////////////////////////////////////////////////////////
@@ -429,6 +723,26 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 	// Check if the user is muted on this channel
 	isMuted := e.mutedUsers.isMuted(channelID, um.ECCPublicKey)
 
+	// Moderator actions from a moderator are handled as admin actions. They are
+	// not encrypted with the admin key, so their encrypted payload is dropped to
+	// prevent the lease system from replaying them as admin messages. This
+	// means moderator actions are never replayed.
+	isModerator := isModeratorAction(umi.messageType) &&
+		e.moderators.isModerator(channelID, um.ECCPublicKey)
+	if isModerator {
+		encryptedPayload = nil
+	}
+
+	// Hide bursts of received messages from a single user. Messages sent by
+	// this user are triggered before they are delivered and are not filtered.
+	hidden := status == Delivered && isRateLimited(umi.messageType) &&
+		e.flood.isFlood(channelID, um.ECCPublicKey, timestamp)
+	if hidden {
+		jww.WARN.Printf("[CH] Hiding message %s from %x on channel %s; "+
+			"flood filter exceeded", umi.GetMessageID(), um.ECCPublicKey,
+			channelID)
+	}
+
 	// Check if there are any saved actions for this message
 	updateFn, deleted := e.as.CheckSavedActions(channelID, umi.GetMessageID())
 	if deleted {
@@ -437,7 +751,7 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 	}
 
 	// Get handler for message type
-	handler, err := e.getHandler(umi.messageType, true, false, isMuted)
+	handler, err := e.getHandler(umi.messageType, true, isModerator, isMuted)
 	if err != nil {
 		return 0, errors.Errorf("Received message %s from %x on channel %s in "+
 			"round %d that could not be handled: %s; Contents: %v",
@@ -450,7 +764,7 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 	uuid := handler.listener(channelID, umi.GetMessageID(), umi.GetMessageType(),
 		cm.Nickname, cm.Payload, encryptedPayload, um.ECCPublicKey, cm.DMToken,
 		0, timestamp, time.Unix(0, cm.LocalTimestamp), time.Duration(cm.Lease),
-		id.Round(cm.RoundID), round, status, false, false)
+		id.Round(cm.RoundID), round, status, isModerator, hidden)
 
 	// If there is an update function, then call it in a new thread
 	if updateFn != nil {
@@ -529,23 +843,29 @@ func (e *events) triggerAdminEvent(channelID *id.ID, cm *ChannelMessage,
 // triggerAdminEventFunc is triggered on for message actions.
 type triggerActionEventFunc func(channelID *id.ID, messageID message.ID,
 	messageType MessageType, nickname string, payload, encryptedPayload []byte,
-	timestamp, originatingTimestamp time.Time, lease time.Duration,
-	originatingRound id.Round, round rounds.Round, status SentStatus,
-	fromAdmin bool) (uint64, error)
+	pubKey ed25519.PublicKey, timestamp, originatingTimestamp time.Time,
+	lease time.Duration, originatingRound id.Round, round rounds.Round,
+	status SentStatus, fromAdmin bool) (uint64, error)
 
 // triggerActionEvent is an internal function that is used to trigger an action
 // on a message. Currently, this function does not receive any messages and is
-// only called by the internal lease manager to undo a message action. An action
-// is set via triggerAdminEvent and triggerEvent.
+// only called by the internal lease manager to undo a message action and by
+// the ActionSaver to apply a saved action. An action is set via
+// triggerAdminEvent and triggerEvent.
+//
+// The action is triggered as the admin if pubKey is nil.
 //
 // It will call the appropriate MessageTypeReceiveMessage, assuming one exists.
 //
 // This function adheres to the triggerActionEventFunc type.
 func (e *events) triggerActionEvent(channelID *id.ID, messageID message.ID,
 	messageType MessageType, nickname string, payload, encryptedPayload []byte,
-	timestamp, originatingTimestamp time.Time, lease time.Duration,
-	originatingRound id.Round, round rounds.Round, status SentStatus,
-	fromAdmin bool) (uint64, error) {
+	pubKey ed25519.PublicKey, timestamp, originatingTimestamp time.Time,
+	lease time.Duration, originatingRound id.Round, round rounds.Round,
+	status SentStatus, fromAdmin bool) (uint64, error) {
+	if pubKey == nil {
+		pubKey = AdminFakePubKey
+	}
 
 	// Get handler for message type
 	handler, err := e.getHandler(messageType, true, fromAdmin, false)
@@ -559,7 +879,7 @@ func (e *events) triggerActionEvent(channelID *id.ID, messageID message.ID,
 	// Call the listener. This is already in an instanced event; no new thread
 	// is needed.
 	uuid := handler.listener(channelID, messageID, messageType, nickname,
-		payload, encryptedPayload, AdminFakePubKey, 0, 0, timestamp,
+		payload, encryptedPayload, pubKey, 0, 0, timestamp,
 		originatingTimestamp, lease, originatingRound, round, status, fromAdmin,
 		false)
 	return uuid, nil
@@ -599,9 +919,11 @@ func (e *events) receiveTextMessage(channelID *id.ID, messageID message.ID,
 				makeChaDebugTag(channelID, pubKey, content, SendReplyTag)
 			jww.INFO.Printf("[CH] [%s] Received reply from %x to %x on %s",
 				tag, pubKey, txt.ReplyMessageID, channelID)
-			return e.model.ReceiveReply(
+			uuid := e.model.ReceiveReply(
 				channelID, messageID, replyTo, nickname, txt.Text, pubKey,
 				dmToken, codeset, timestamp, lease, round, Text, status, hidden)
+			e.receiveTextSpans(uuid, txt.Text, false)
+			return uuid
 		} else {
 			jww.ERROR.Printf("[CH] Failed process reply to for message %s "+
 				"from public key %x (codeset %d) on channel %s, type %s, ts: "+
@@ -617,8 +939,33 @@ func (e *events) receiveTextMessage(channelID *id.ID, messageID message.ID,
 	jww.INFO.Printf("[CH] [%s] Received message from %x on %s",
 		tag, pubKey, channelID)
 
-	return e.model.ReceiveMessage(channelID, messageID, nickname, txt.Text,
+	uuid := e.model.ReceiveMessage(channelID, messageID, nickname, txt.Text,
 		pubKey, dmToken, codeset, timestamp, lease, round, Text, status, hidden)
+	e.receiveTextSpans(uuid, txt.Text, false)
+	return uuid
+}
+
+// receiveTextSpans parses the text of the message and passes the spans to the
+// event model, if it implements TextSpanReceiver. If the message was edited,
+// the spans are passed even if there are none so that they replace the spans
+// of the previous revision.
+func (e *events) receiveTextSpans(uuid uint64, text string, edited bool) {
+	receiveTextSpans(e.model, uuid, text, edited)
+}
+
+// receiveTextSpans parses the text and passes the spans to the model, if it
+// implements TextSpanReceiver. The spans are only passed if there are any or if
+// the message was edited.
+func receiveTextSpans(model EventModel, uuid uint64, text string, edited bool) {
+	tsr, ok := model.(TextSpanReceiver)
+	if !ok || uuid == 0 {
+		return
+	}
+
+	spans := ParseText(text)
+	if len(spans) > 0 || edited {
+		tsr.ReceiveTextSpans(uuid, spans)
+	}
 }
 
 // receiveReaction is the internal function that handles the reception of
@@ -763,8 +1110,8 @@ func (e *events) receiveDelete(channelID *id.ID, messageID message.ID,
 	if err != nil {
 		if CheckNoMessageErr(err) {
 			err = e.as.AddAction(channelID, messageID, deleteMessageID,
-				messageType, nil, nil, timestamp, time.Time{}, netTime.Now(),
-				lease, 0, rounds.Round{}, fromAdmin)
+				messageType, nil, nil, pubKey, timestamp, time.Time{},
+				netTime.Now(), lease, 0, rounds.Round{}, fromAdmin)
 			if err != nil {
 				jww.ERROR.Printf("[CH] [%s] Could not add action for deletion "+
 					"message %s: %+v", tag, msgLog, err)
@@ -845,7 +1192,7 @@ func (e *events) receivePinned(channelID *id.ID, messageID message.ID,
 	if err != nil {
 		if CheckNoMessageErr(err) {
 			err = e.as.AddAction(channelID, messageID, pinnedMessageID,
-				messageType, content, encryptedPayload, timestamp,
+				messageType, content, encryptedPayload, pubKey, timestamp,
 				originatingTimestamp, netTime.Now(), lease, originatingRound,
 				round, fromAdmin)
 			if err != nil {
@@ -917,6 +1264,12 @@ func (e *events) receiveMute(channelID *id.ID, messageID message.ID,
 				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
 			return 0
 		}
+		// A banned user stays muted until they are unbanned
+		if e.bans.isBanned(channelID, mutedUser) {
+			jww.INFO.Printf("[CH] [%s] Not unmuting user %x in %s; the user "+
+				"is banned", tag, mutedUser, msgLog)
+			return 0
+		}
 		e.mutedUsers.unmuteUser(channelID, mutedUser)
 	} else {
 		err = e.leases.AddMessage(channelID, messageID, messageType, content,
@@ -935,6 +1288,729 @@ func (e *events) receiveMute(channelID *id.ID, messageID message.ID,
 	return 0
 }
 
+// receiveModerator is the internal function that handles the reception of
+// moderator appointments and revocations. Only the channel admin may appoint
+// a moderator.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveModerator(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	moderatorMsg := &CMIXChannelModerator{}
+	if err := proto.Unmarshal(content, moderatorMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			moderatorMsg, msgLog, err)
+		return 0
+	}
+
+	if len(moderatorMsg.PubKey) != ed25519.PublicKeySize {
+		jww.ERROR.Printf("[CH] Failed unmarshal public key of user targeted "+
+			"for moderator in %s: length of %d bytes required, received %d "+
+			"bytes", msgLog, ed25519.PublicKeySize, len(moderatorMsg.PubKey))
+		return 0
+	}
+
+	moderator := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(moderator[:], moderatorMsg.PubKey)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendModeratorTag)
+	jww.INFO.Printf(
+		"[CH] [%s] Received message %s from %s to channel %s to %s moderator %x",
+		tag, messageID, nickname, channelID,
+		moderatorVerb(moderatorMsg.UndoAction), moderator)
+
+	undoAction := moderatorMsg.UndoAction
+	moderatorMsg.UndoAction = true
+	payload, err := proto.Marshal(moderatorMsg)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, moderatorMsg, msgLog, err)
+		return 0
+	}
+
+	if undoAction {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+		e.moderators.removeModerator(channelID, moderator)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+		e.moderators.addModerator(channelID, moderator)
+	}
+
+	return 0
+}
+
+// receiveBan is the internal function that handles the reception of bans. A
+// ban mutes the user, hides every message they have sent to the channel, and
+// records the ban reason. Only the channel admin may ban a user.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveBan(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	banMsg := &CMIXChannelBan{}
+	if err := proto.Unmarshal(content, banMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			banMsg, msgLog, err)
+		return 0
+	}
+
+	if len(banMsg.PubKey) != ed25519.PublicKeySize {
+		jww.ERROR.Printf("[CH] Failed unmarshal public key of user targeted "+
+			"for ban in %s: length of %d bytes required, received %d bytes",
+			msgLog, ed25519.PublicKeySize, len(banMsg.PubKey))
+		return 0
+	}
+
+	bannedUser := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(bannedUser[:], banMsg.PubKey)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendBanTag)
+	jww.INFO.Printf(
+		"[CH] [%s] Received message %s from %s to channel %s to %s user %x",
+		tag, messageID, nickname, channelID, banVerb(banMsg.UndoAction),
+		bannedUser)
+
+	undoAction := banMsg.UndoAction
+	banMsg.UndoAction = true
+	payload, err := proto.Marshal(banMsg)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, banMsg, msgLog, err)
+		return 0
+	}
+
+	if undoAction {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+
+		// Only lift the mute if the ban applied it so that a user muted
+		// before the ban stays muted
+		ban, banned := e.bans.unbanUser(channelID, bannedUser)
+		if !banned || !ban.Muted {
+			return 0
+		}
+		e.mutedUsers.unmuteUser(channelID, bannedUser)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+		e.bans.banUser(channelID, BannedUser{
+			PubKey:    bannedUser,
+			Reason:    banMsg.Reason,
+			Timestamp: originatingTimestamp,
+			Muted:     !e.mutedUsers.isMuted(channelID, bannedUser),
+		})
+		e.mutedUsers.muteUser(channelID, bannedUser)
+		e.hideMessagesFrom(channelID, bannedUser, tag)
+	}
+
+	e.model.MuteUser(channelID, bannedUser, undoAction)
+
+	return 0
+}
+
+// hideMessagesFrom hides every message the user has sent to the channel in the
+// event model. Messages can only be found if the event model implements
+// MessageQuerier; otherwise, only messages received after the ban are dropped.
+func (e *events) hideMessagesFrom(
+	channelID *id.ID, pubKey ed25519.PublicKey, tag string) {
+	querier, ok := e.model.(MessageQuerier)
+	if !ok {
+		jww.WARN.Printf("[CH] [%s] Cannot hide messages from banned user %x "+
+			"in channel %s: event model %T does not support queries",
+			tag, pubKey, channelID, e.model)
+		return
+	}
+
+	msgs, _, err := querier.QueryMessages(MessageQuery{
+		ChannelID: channelID,
+		PubKey:    pubKey,
+	})
+	if errors.Is(err, UnsupportedExtensionErr) {
+		jww.WARN.Printf("[CH] [%s] Cannot hide messages from banned user %x "+
+			"in channel %s: %+v", tag, pubKey, channelID, err)
+		return
+	} else if err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to query messages from banned "+
+			"user %x in channel %s: %+v", tag, pubKey, channelID, err)
+		return
+	}
+
+	hidden := true
+	for _, msg := range msgs {
+		_, err = e.model.UpdateFromMessageID(
+			msg.MessageID, nil, nil, nil, &hidden, nil)
+		if err != nil {
+			jww.ERROR.Printf("[CH] [%s] Failed to hide message %s from "+
+				"banned user %x in channel %s: %+v",
+				tag, msg.MessageID, pubKey, channelID, err)
+		}
+	}
+
+	jww.INFO.Printf("[CH] [%s] Hid %d messages from banned user %x in "+
+		"channel %s", tag, len(msgs), pubKey, channelID)
+}
+
+// receiveKeyRotation is the internal function that handles the reception of
+// admin key rotations. The message is verified against the current admin key
+// by the admin listener, so the channel is moved to the new key as is. Only
+// the channel admin may rotate the key.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveKeyRotation(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	lease time.Duration, _ id.Round, round rounds.Round, _ SentStatus,
+	fromAdmin, _ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	rotationMsg := &CMIXChannelKeyRotation{}
+	if err := proto.Unmarshal(content, rotationMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			rotationMsg, msgLog, err)
+		return 0
+	}
+
+	if len(rotationMsg.RsaPubKeyHash) != rsaPubKeyHashLen {
+		jww.ERROR.Printf("[CH] Failed to rotate admin key in %s: hash of "+
+			"the new key must be %d bytes, received %d bytes", msgLog,
+			rsaPubKeyHashLen, len(rotationMsg.RsaPubKeyHash))
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendKeyRotationTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %s to channel %s to "+
+		"rotate the admin key to %x", tag, messageID, nickname, channelID,
+		rotationMsg.RsaPubKeyHash)
+
+	if e.keyRotation != nil {
+		go e.keyRotation(channelID, rotationMsg.RsaPubKeyHash)
+	}
+
+	return 0
+}
+
+// receiveSlowMode is the internal function that handles the reception of
+// messages setting the slow mode interval of the channel. Only the channel
+// admin may set slow mode.
+//
+// The message is added to the lease system so that it is replayed to users who
+// join later. Turning slow mode off removes it, the same as undoing an action.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveSlowMode(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	slowModeMsg := &CMIXChannelSlowMode{}
+	if err := proto.Unmarshal(content, slowModeMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			slowModeMsg, msgLog, err)
+		return 0
+	}
+
+	interval := time.Duration(slowModeMsg.Interval)
+	if interval < 0 {
+		jww.ERROR.Printf("[CH] Failed to set slow mode in %s: interval %s "+
+			"must be positive", msgLog, interval)
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendSlowModeTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %s to channel %s to "+
+		"set slow mode interval to %s", tag, messageID, nickname, channelID,
+		interval)
+
+	// Every interval is stored under the same command in the lease system so
+	// that a newer interval replaces an older one
+	payload, err := proto.Marshal(&CMIXChannelSlowMode{})
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, slowModeMsg, msgLog, err)
+		return 0
+	}
+
+	if interval == 0 {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+	}
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	if !e.slowMode.setInterval(channelID, interval, originatingTimestamp) {
+		jww.INFO.Printf("[CH] [%s] Dropped slow mode in %s; a newer interval "+
+			"is already set", tag, msgLog)
+	}
+
+	return 0
+}
+
+// receiveMetadataUpdate is the internal function that handles the reception of
+// channel metadata updates. Updates older than the current metadata are
+// dropped so that the newest update wins when updates are replayed. Only the
+// channel admin may update the metadata.
+//
+// The update is added to the lease system so that it is replayed to users who
+// join later.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveMetadataUpdate(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	metadataMsg := &CMIXChannelMetadata{}
+	if err := proto.Unmarshal(content, metadataMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			metadataMsg, msgLog, err)
+		return 0
+	}
+
+	if len(metadataMsg.Icon) > MaxChannelIconSize {
+		jww.ERROR.Printf("[CH] Failed to update channel metadata in %s: icon "+
+			"must be at most %d bytes, received %d bytes", msgLog,
+			MaxChannelIconSize, len(metadataMsg.Icon))
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendMetadataTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %s to channel %s to "+
+		"update the channel metadata", tag, messageID, nickname, channelID)
+
+	// Every update is stored under the same command in the lease system so
+	// that a newer update replaces an older one
+	payload, err := proto.Marshal(&CMIXChannelMetadata{})
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, metadataMsg, msgLog, err)
+		return 0
+	}
+
+	err = e.leases.AddMessage(channelID, messageID, messageType, content,
+		payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+		originatingRound, round, fromAdmin)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	metadata := ChannelMetadata{
+		Description: metadataMsg.Description,
+		Icon:        metadataMsg.Icon,
+		Topic:       metadataMsg.Topic,
+		Rules:       metadataMsg.Rules,
+		Timestamp:   originatingTimestamp,
+	}
+	if !e.metadata.set(channelID, metadata) {
+		jww.INFO.Printf("[CH] [%s] Dropped metadata update in %s; a newer "+
+			"update is already set", tag, msgLog)
+		return 0
+	}
+
+	e.model.UpdateChannelMetadata(channelID, metadata)
+
+	return 0
+}
+
+// receiveEdit is the internal function that handles the reception of edited
+// messages.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveEdit(channelID *id.ID, messageID message.ID,
+	messageType MessageType, _ string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	editMsg := &CMIXChannelEdit{}
+	if err := proto.Unmarshal(content, editMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			editMsg, msgLog, err)
+		return 0
+	}
+
+	var editMessageID message.ID
+	copy(editMessageID[:], editMsg.MessageID)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendEditTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %x to channel %s to "+
+		"edit message %s", tag, messageID, pubKey, channelID, editMessageID)
+
+	// Saves the edit to be applied once the target message is received. Edits
+	// not made by the admin are saved per sender and the sender is checked
+	// against the target message when the edit is replayed.
+	saveEdit := func() {
+		err := e.as.AddAction(channelID, messageID, editMessageID, messageType,
+			content, encryptedPayload, pubKey, timestamp, originatingTimestamp,
+			netTime.Now(), lease, originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf("[CH] [%s] Could not add action for edit "+
+				"message %s: %+v", tag, msgLog, err)
+		}
+	}
+
+	// Reject the edit if not from original sender or admin
+	if !fromAdmin {
+		targetMsg, err2 := e.model.GetMessage(editMessageID)
+		if err2 != nil {
+			if CheckNoMessageErr(err2) {
+				saveEdit()
+			} else {
+				jww.ERROR.Printf("[CH] [%s] Failed to find target message %s "+
+					"for edit from %s: %+v", tag, editMessageID, msgLog, err2)
+			}
+			return 0
+		}
+		if !bytes.Equal(targetMsg.PubKey, pubKey) {
+			jww.ERROR.Printf("[CH] [%s] Edit message must come from original "+
+				"sender or admin for %s", tag, msgLog)
+			return 0
+		}
+	}
+
+	edit := MessageEdit{
+		EditID:    messageID,
+		Text:      editMsg.Text,
+		Timestamp: timestamp,
+		Round:     round.ID,
+	}
+	uuid, err := e.model.EditMessage(editMessageID, edit)
+	if err != nil {
+		if CheckNoMessageErr(err) {
+			saveEdit()
+		} else {
+			jww.ERROR.Printf(
+				"[CH] [%s] Failed to edit message %s: %+v", tag, msgLog, err)
+		}
+	} else if _, ok := e.model.(TextSpanReceiver); ok {
+		// Edits may arrive out of order, so parse the current text of the
+		// message instead of the text of the edit
+		if msg, err2 := e.model.GetMessage(editMessageID); err2 != nil {
+			jww.ERROR.Printf("[CH] [%s] Failed to get edited message %s to "+
+				"parse its text: %+v", tag, msgLog, err2)
+		} else {
+			e.receiveTextSpans(uuid, string(msg.Content), true)
+		}
+	}
+
+	return uuid
+}
+
+// receivePoll is the internal function that handles the reception of polls.
+// The poll is passed to the event model as JSON of CMIXChannelPoll.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receivePoll(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, _ []byte,
+	pubKey ed25519.PublicKey, dmToken uint32, codeset uint8, timestamp,
+	_ time.Time, lease time.Duration, _ id.Round, round rounds.Round,
+	status SentStatus, fromAdmin, hidden bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	poll := &CMIXChannelPoll{}
+	if err := proto.Unmarshal(content, poll); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			poll, msgLog, err)
+		return 0
+	}
+
+	if len(poll.Options) < 2 {
+		jww.ERROR.Printf("[CH] Dropping poll with %d options in %s; a poll "+
+			"requires at least 2 options", len(poll.Options), msgLog)
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendPollTag)
+	jww.INFO.Printf("[CH] [%s] Received poll %s from %x on %s",
+		tag, messageID, pubKey, channelID)
+
+	var pollJson bytes.Buffer
+	enc := json.NewEncoder(&pollJson)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(poll); err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to JSON marshal poll: %+v", tag, err)
+		return 0
+	}
+
+	return e.model.ReceiveMessage(channelID, messageID, nickname,
+		pollJson.String(), pubKey, dmToken, codeset, timestamp, lease, round,
+		Poll, status, hidden)
+}
+
+// receivePollVote is the internal function that handles the reception of votes
+// on polls. Votes for options that do not exist in the poll are dropped. Votes
+// received before their poll are saved by the ActionSaver until the poll is
+// received.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receivePollVote(channelID *id.ID, messageID message.ID,
+	messageType MessageType, _ string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus,
+	fromAdmin, _ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	voteMsg := &CMIXChannelPollVote{}
+	if err := proto.Unmarshal(content, voteMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			voteMsg, msgLog, err)
+		return 0
+	}
+
+	var pollMessageID message.ID
+	copy(pollMessageID[:], voteMsg.PollMessageID)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendPollVoteTag)
+	jww.INFO.Printf("[CH] [%s] Received vote %s from %x on %s for option %d "+
+		"of poll %s", tag, messageID, pubKey, channelID, voteMsg.Option,
+		pollMessageID)
+
+	// Verify that the vote is for an option in the poll. Votes that arrive
+	// before their poll are saved and applied once the poll is received.
+	pollMsg, err := e.model.GetMessage(pollMessageID)
+	if err != nil {
+		if CheckNoMessageErr(err) {
+			err = e.as.AddAction(channelID, messageID, pollMessageID,
+				messageType, content, encryptedPayload, pubKey, timestamp,
+				originatingTimestamp, netTime.Now(), lease, originatingRound,
+				round, fromAdmin)
+			if err != nil {
+				jww.ERROR.Printf("[CH] [%s] Could not add action for vote "+
+					"%s: %+v", tag, msgLog, err)
+			}
+		} else {
+			jww.ERROR.Printf("[CH] [%s] Failed to find poll %s for vote in "+
+				"%s: %+v", tag, pollMessageID, msgLog, err)
+		}
+		return 0
+	}
+	poll := &CMIXChannelPoll{}
+	if pollMsg.Type != Poll {
+		jww.ERROR.Printf("[CH] [%s] Dropping vote in %s for message %s of "+
+			"type %s that is not a poll", tag, msgLog, pollMessageID,
+			pollMsg.Type)
+		return 0
+	} else if err = json.Unmarshal(pollMsg.Content, poll); err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to JSON unmarshal poll %s for vote "+
+			"in %s: %+v", tag, pollMessageID, msgLog, err)
+		return 0
+	} else if int(voteMsg.Option) >= len(poll.Options) {
+		jww.ERROR.Printf("[CH] [%s] Dropping vote in %s for option %d of poll "+
+			"%s with %d options", tag, msgLog, voteMsg.Option, pollMessageID,
+			len(poll.Options))
+		return 0
+	}
+
+	vote := ModelPollVote{
+		MessageID: messageID,
+		PubKey:    pubKey,
+		Option:    voteMsg.Option,
+		Timestamp: timestamp,
+		Round:     round.ID,
+	}
+	uuid, err := e.model.ReceivePollVote(pollMessageID, vote)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to save vote %s: %+v", tag, msgLog, err)
+	}
+
+	return uuid
+}
+
+// receivePollClose is the internal function that handles the reception of
+// closed polls. Closes are registered with the lease system so that they are
+// replayed to the channel and survive past the life of the message on the
+// network.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receivePollClose(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus,
+	fromAdmin, _ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	closeMsg := &CMIXChannelPollClose{}
+	if err := proto.Unmarshal(content, closeMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			closeMsg, msgLog, err)
+		return 0
+	}
+
+	var pollMessageID message.ID
+	copy(pollMessageID[:], closeMsg.PollMessageID)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendPollCloseTag)
+	jww.INFO.Printf(
+		"[CH] [%s] Received message %s from %s to channel %s to %s poll %s",
+		tag, messageID, nickname, channelID,
+		pollCloseVerb(closeMsg.UndoAction), pollMessageID)
+
+	undoAction := closeMsg.UndoAction
+	closeMsg.UndoAction = true
+	payload, err := proto.Marshal(closeMsg)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, closeMsg, msgLog, err)
+		return 0
+	}
+
+	if undoAction {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+	}
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	// Use the originating timestamp so that replays of the close do not move
+	// the time the poll was closed
+	uuid, err := e.model.ClosePoll(
+		pollMessageID, originatingTimestamp, undoAction)
+	if err != nil {
+		if CheckNoMessageErr(err) {
+			err = e.as.AddAction(channelID, messageID, pollMessageID,
+				messageType, content, encryptedPayload, pubKey, timestamp,
+				originatingTimestamp, netTime.Now(), lease, originatingRound,
+				round, fromAdmin)
+			if err != nil {
+				jww.ERROR.Printf("[CH] [%s] Could not add action for poll "+
+					"close %s: %+v", tag, msgLog, err)
+			}
+		} else {
+			jww.ERROR.Printf(
+				"[CH] [%s] Failed to close poll %s: %+v", tag, msgLog, err)
+		}
+	}
+
+	return uuid
+}
+
+// receiveTyping is the internal function that handles the reception of typing
+// indicators. Indicators are passed to the UI and are never stored. Indicators
+// older than TypingTimeout, such as those found when catching up on old rounds,
+// are dropped.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveTyping(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	lease time.Duration, _ id.Round, round rounds.Round, _ SentStatus,
+	fromAdmin, _ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	typingMsg := &CMIXChannelTyping{}
+	if err := proto.Unmarshal(content, typingMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			typingMsg, msgLog, err)
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendTypingTag)
+
+	if age := netTime.Since(timestamp); age > TypingTimeout {
+		jww.DEBUG.Printf("[CH] [%s] Dropping typing indicator %s from %x on "+
+			"%s that is %s old", tag, messageID, pubKey, channelID, age)
+		return 0
+	}
+
+	jww.DEBUG.Printf("[CH] [%s] Received typing indicator %s from %x on %s "+
+		"(typing: %t)", tag, messageID, pubKey, channelID, typingMsg.Typing)
+
+	if e.userTyping != nil {
+		go e.userTyping(channelID, pubKey, codeset, nickname, typingMsg.Typing)
+	}
+
+	return 0
+}
+
 // receiveAdminReplay handles replayed admin commands.
 //
 // This function adheres to the MessageTypeReceiveMessage type.
@@ -988,6 +2064,33 @@ func pinnedVerb(b bool) string {
 	return "pin"
 }
 
+// pollCloseVerb returns the correct verb for the poll close action to use for
+// logging and debugging.
+func pollCloseVerb(b bool) string {
+	if b {
+		return "reopen"
+	}
+	return "close"
+}
+
+// moderatorVerb returns the correct verb for the moderator action to use for
+// logging and debugging.
+func moderatorVerb(b bool) string {
+	if b {
+		return "revoke"
+	}
+	return "appoint"
+}
+
+// banVerb returns the correct verb for the ban action to use for logging and
+// debugging.
+func banVerb(b bool) string {
+	if b {
+		return "unban"
+	}
+	return "ban"
+}
+
 // muteVerb returns the correct verb for the mute action to use for logging and
 // debugging.
 func muteVerb(b bool) string {
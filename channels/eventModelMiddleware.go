@@ -0,0 +1,303 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// EventModelMiddleware wraps an EventModel so that it can filter or transform
+// events before they reach it. The returned EventModel must pass every event it
+// does not drop on to next.
+//
+// The simplest way to write middleware is to embed next in a struct and only
+// override the methods that need to change. For example, to hide all
+// reactions:
+//
+//	type hideReactions struct{ channels.EventModel }
+//
+//	func (h hideReactions) ReceiveReaction(channelID *id.ID, ...) uint64 {
+//		return h.EventModel.ReceiveReaction(channelID, ..., true)
+//	}
+//
+//	func HideReactions(next channels.EventModel) channels.EventModel {
+//		return hideReactions{next}
+//	}
+type EventModelMiddleware func(next EventModel) EventModel
+
+// WrapEventModel wraps the model in each middleware. The first middleware is
+// the outermost, so it sees each event first.
+//
+// Middleware that embeds next only promotes the methods of the EventModel
+// interface; it must implement any optional extensions of next, such as
+// MessageQuerier, that should remain available. The middleware returned by
+// FilterReceived forwards all of them.
+func WrapEventModel(
+	model EventModel, middleware ...EventModelMiddleware) EventModel {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		model = middleware[i](model)
+	}
+	return model
+}
+
+// ReceivedMessage contains the arguments of EventModel.ReceiveMessage,
+// EventModel.ReceiveReply, and EventModel.ReceiveReaction so that they can be
+// inspected and modified by a ReceiveFilter.
+type ReceivedMessage struct {
+	ChannelID *id.ID
+	MessageID message.ID
+
+	// ReactionTo is the ID of the message being replied or reacted to. It is
+	// empty for messages received by EventModel.ReceiveMessage.
+	ReactionTo message.ID
+
+	Nickname       string
+	Text           string
+	PubKey         ed25519.PublicKey
+	DmToken        uint32
+	CodesetVersion uint8
+	Timestamp      time.Time
+	Lease          time.Duration
+	Round          rounds.Round
+	Type           MessageType
+	Status         SentStatus
+	Hidden         bool
+}
+
+// ReceiveFilter is called on each message received by an EventModel wrapped by
+// FilterReceived. It may modify the message before it is passed on. If it
+// returns false, then the message is dropped.
+type ReceiveFilter func(msg *ReceivedMessage) bool
+
+// FilterReceived returns EventModelMiddleware that calls the filter on every
+// message received by the model, for example to drop messages from muted users
+// or to redact text.
+//
+// Dropped messages never reach the model and 0 is returned as their UUID. Any
+// later updates to them will fail with NoMessageErr. Messages sent by this user
+// have the Unsent status when they are first received; dropping them prevents
+// their sent status from being updated.
+func FilterReceived(filter ReceiveFilter) EventModelMiddleware {
+	return func(next EventModel) EventModel {
+		return &receiveFilter{
+			EventModel: next,
+			filter:     filter,
+			spansSent:  make(map[uint64]struct{}),
+		}
+	}
+}
+
+// Verify that receiveFilter adheres to the EventModel interface and its
+// optional extensions.
+var (
+	_ EventModel       = (*receiveFilter)(nil)
+	_ MessageQuerier   = (*receiveFilter)(nil)
+	_ PollTallier      = (*receiveFilter)(nil)
+	_ Searcher         = (*receiveFilter)(nil)
+	_ TextSpanReceiver = (*receiveFilter)(nil)
+)
+
+// receiveFilter is an EventModel that passes every received message through a
+// ReceiveFilter.
+type receiveFilter struct {
+	EventModel
+	filter ReceiveFilter
+
+	// spansSent holds the UUIDs of messages whose text was changed by the
+	// filter and whose spans will still be passed to ReceiveTextSpans. Their
+	// spans were already parsed from the filtered text, so the spans parsed
+	// from the original text are ignored.
+	spansSent map[uint64]struct{}
+	mux       sync.Mutex
+}
+
+// ReceiveMessage filters the message before passing it on.
+func (rf *receiveFilter) ReceiveMessage(channelID *id.ID, messageID message.ID,
+	nickname, text string, pubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	msg := &ReceivedMessage{
+		ChannelID:      channelID,
+		MessageID:      messageID,
+		Nickname:       nickname,
+		Text:           text,
+		PubKey:         pubKey,
+		DmToken:        dmToken,
+		CodesetVersion: codeset,
+		Timestamp:      timestamp,
+		Lease:          lease,
+		Round:          round,
+		Type:           messageType,
+		Status:         status,
+		Hidden:         hidden,
+	}
+	if !rf.filter(msg) {
+		return 0
+	}
+	uuid := rf.EventModel.ReceiveMessage(msg.ChannelID, msg.MessageID,
+		msg.Nickname, msg.Text, msg.PubKey, msg.DmToken, msg.CodesetVersion,
+		msg.Timestamp, msg.Lease, msg.Round, msg.Type, msg.Status, msg.Hidden)
+	rf.receiveFilteredSpans(uuid, messageType, text, msg.Text)
+	return uuid
+}
+
+// ReceiveReply filters the reply before passing it on.
+func (rf *receiveFilter) ReceiveReply(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, text string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	msg := &ReceivedMessage{
+		ChannelID:      channelID,
+		MessageID:      messageID,
+		ReactionTo:     reactionTo,
+		Nickname:       nickname,
+		Text:           text,
+		PubKey:         pubKey,
+		DmToken:        dmToken,
+		CodesetVersion: codeset,
+		Timestamp:      timestamp,
+		Lease:          lease,
+		Round:          round,
+		Type:           messageType,
+		Status:         status,
+		Hidden:         hidden,
+	}
+	if !rf.filter(msg) {
+		return 0
+	}
+	uuid := rf.EventModel.ReceiveReply(msg.ChannelID, msg.MessageID,
+		msg.ReactionTo, msg.Nickname, msg.Text, msg.PubKey, msg.DmToken,
+		msg.CodesetVersion, msg.Timestamp, msg.Lease, msg.Round, msg.Type,
+		msg.Status, msg.Hidden)
+	rf.receiveFilteredSpans(uuid, messageType, text, msg.Text)
+	return uuid
+}
+
+// ReceiveReaction filters the reaction before passing it on.
+func (rf *receiveFilter) ReceiveReaction(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, reaction string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	msg := &ReceivedMessage{
+		ChannelID:      channelID,
+		MessageID:      messageID,
+		ReactionTo:     reactionTo,
+		Nickname:       nickname,
+		Text:           reaction,
+		PubKey:         pubKey,
+		DmToken:        dmToken,
+		CodesetVersion: codeset,
+		Timestamp:      timestamp,
+		Lease:          lease,
+		Round:          round,
+		Type:           messageType,
+		Status:         status,
+		Hidden:         hidden,
+	}
+	if !rf.filter(msg) {
+		return 0
+	}
+	return rf.EventModel.ReceiveReaction(msg.ChannelID, msg.MessageID,
+		msg.ReactionTo, msg.Nickname, msg.Text, msg.PubKey, msg.DmToken,
+		msg.CodesetVersion, msg.Timestamp, msg.Lease, msg.Round, msg.Type,
+		msg.Status, msg.Hidden)
+}
+
+// receiveFilteredSpans passes the spans of the filtered text of a Text message
+// to the wrapped model if the filter changed its text, so that the spans never
+// contain text that the filter removed. The spans that the Manager parses from
+// the original text are then ignored by ReceiveTextSpans.
+func (rf *receiveFilter) receiveFilteredSpans(
+	uuid uint64, messageType MessageType, original, filtered string) {
+	if uuid == 0 || messageType != Text || original == filtered {
+		return
+	} else if _, ok := rf.EventModel.(TextSpanReceiver); !ok {
+		return
+	}
+
+	receiveTextSpans(rf.EventModel, uuid, filtered, false)
+
+	// The Manager only passes spans if the original text has any
+	if len(ParseText(original)) > 0 {
+		rf.mux.Lock()
+		rf.spansSent[uuid] = struct{}{}
+		rf.mux.Unlock()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Optional Extensions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// QueryMessages passes the query on to the wrapped model. Queries are not
+// filtered. Returns UnsupportedExtensionErr if the wrapped model does not
+// implement MessageQuerier.
+func (rf *receiveFilter) QueryMessages(
+	query MessageQuery) ([]ModelMessage, int64, error) {
+	querier, ok := rf.EventModel.(MessageQuerier)
+	if !ok {
+		return nil, 0, errors.Wrapf(UnsupportedExtensionErr,
+			"wrapped model %T does not implement MessageQuerier", rf.EventModel)
+	}
+	return querier.QueryMessages(query)
+}
+
+// GetPollResults passes the request on to the wrapped model. Returns
+// UnsupportedExtensionErr if the wrapped model does not implement PollTallier.
+func (rf *receiveFilter) GetPollResults(
+	pollID message.ID) (PollResults, error) {
+	tallier, ok := rf.EventModel.(PollTallier)
+	if !ok {
+		return PollResults{}, errors.Wrapf(UnsupportedExtensionErr,
+			"wrapped model %T does not implement PollTallier", rf.EventModel)
+	}
+	return tallier.GetPollResults(pollID)
+}
+
+// Search passes the search on to the wrapped model. Searches are not filtered.
+// Returns UnsupportedExtensionErr if the wrapped model does not implement
+// Searcher.
+func (rf *receiveFilter) Search(query SearchQuery) ([]ModelMessage, error) {
+	searcher, ok := rf.EventModel.(Searcher)
+	if !ok {
+		return nil, errors.Wrapf(UnsupportedExtensionErr,
+			"wrapped model %T does not implement Searcher", rf.EventModel)
+	}
+	return searcher.Search(query)
+}
+
+// ReceiveTextSpans passes the spans on to the wrapped model, if it implements
+// TextSpanReceiver. Spans parsed from the original text of a message whose
+// text was changed by the filter are ignored, since the spans of the filtered
+// text were already passed on.
+func (rf *receiveFilter) ReceiveTextSpans(uuid uint64, spans []TextSpan) {
+	tsr, ok := rf.EventModel.(TextSpanReceiver)
+	if !ok {
+		return
+	}
+
+	rf.mux.Lock()
+	_, sent := rf.spansSent[uuid]
+	delete(rf.spansSent, uuid)
+	rf.mux.Unlock()
+
+	if !sent {
+		tsr.ReceiveTextSpans(uuid, spans)
+	}
+}
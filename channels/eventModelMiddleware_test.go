@@ -0,0 +1,139 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that WrapEventModel applies the middleware in order, with the first
+// middleware seeing events first.
+func TestWrapEventModel(t *testing.T) {
+	var order []string
+	appendText := func(s string) EventModelMiddleware {
+		return FilterReceived(func(msg *ReceivedMessage) bool {
+			order = append(order, s)
+			msg.Text += s
+			return true
+		})
+	}
+
+	model := &MockEvent{}
+	wrapped := WrapEventModel(model, appendText("a"), appendText("b"))
+	wrapped.ReceiveMessage(&id.ID{1}, message.ID{1}, "nickname", "text", nil,
+		0, 0, time.Unix(0, 0), 0, rounds.Round{}, Text, Delivered, false)
+
+	if !reflect.DeepEqual([]string{"a", "b"}, order) {
+		t.Errorf("Unexpected middleware order: %v", order)
+	}
+	if string(model.content) != "textab" {
+		t.Errorf("Unexpected text.\nexpected: %q\nreceived: %q",
+			"textab", model.content)
+	}
+}
+
+// Tests that FilterReceived drops messages rejected by the filter and passes on
+// changes made by the filter.
+func TestFilterReceived(t *testing.T) {
+	muted := ed25519.PublicKey("muted")
+	model := &MockEvent{uuid: 1}
+	wrapped := WrapEventModel(model,
+		FilterReceived(func(msg *ReceivedMessage) bool {
+			return !bytes.Equal(msg.PubKey, muted)
+		}),
+		FilterReceived(func(msg *ReceivedMessage) bool {
+			msg.Text = "[redacted]"
+			return true
+		}))
+
+	uuid := wrapped.ReceiveReply(&id.ID{1}, message.ID{1}, message.ID{2},
+		"nickname", "secret", muted, 0, 0, time.Unix(0, 0), 0, rounds.Round{},
+		Text, Delivered, false)
+	if uuid != 0 || model.content != nil {
+		t.Errorf("Reply from muted user not dropped: %+v", model.eventReceive)
+	}
+
+	uuid = wrapped.ReceiveReaction(&id.ID{1}, message.ID{3}, message.ID{2},
+		"nickname", "secret", ed25519.PublicKey("user"), 0, 0, time.Unix(0, 0),
+		0, rounds.Round{}, Reaction, Delivered, false)
+	if uuid != 1 {
+		t.Errorf("Unexpected UUID.\nexpected: %d\nreceived: %d", 1, uuid)
+	}
+	if string(model.content) != "[redacted]" || model.reactionTo != (message.ID{2}) {
+		t.Errorf("Unexpected reaction: %+v", model.eventReceive)
+	}
+
+	// Events other than received messages are passed through unchanged
+	if _, err := wrapped.EditMessage(
+		message.ID{3}, MessageEdit{Text: "edit"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(model.content) != "edit" {
+		t.Errorf("Edit not passed through: %q", model.content)
+	}
+}
+
+// Tests that FilterReceived forwards the optional extensions of the wrapped
+// model and that the spans of a message whose text was changed by the filter
+// are parsed from the filtered text.
+func TestFilterReceived_Extensions(t *testing.T) {
+	model := &mockSpanEvent{MockEvent: MockEvent{uuid: 1},
+		spans: make(map[uint64][]TextSpan)}
+	wrapped := WrapEventModel(model,
+		FilterReceived(func(msg *ReceivedMessage) bool {
+			msg.Text = strings.ReplaceAll(msg.Text, "secret", "hidden")
+			return true
+		}))
+
+	// The Manager passes the spans parsed from the original text
+	receive := func(text string) uint64 {
+		uuid := wrapped.ReceiveMessage(&id.ID{1}, message.ID{1}, "nickname",
+			text, nil, 0, 0, time.Unix(0, 0), 0, rounds.Round{}, Text,
+			Delivered, false)
+		if spans := ParseText(text); len(spans) > 0 {
+			wrapped.(TextSpanReceiver).ReceiveTextSpans(uuid, spans)
+		}
+		return uuid
+	}
+
+	expected := map[uint64][]TextSpan{1: ParseText("**hi**")}
+	receive("**hi**")
+	if !reflect.DeepEqual(expected, model.spans) {
+		t.Errorf("Unexpected spans.\nexpected: %+v\nreceived: %+v",
+			expected, model.spans)
+	}
+
+	expected[2] = ParseText("**hidden**")
+	receive("**secret**")
+	if !reflect.DeepEqual(expected, model.spans) {
+		t.Errorf("Unexpected spans of filtered text."+
+			"\nexpected: %+v\nreceived: %+v", expected, model.spans)
+	}
+
+	_, _, err := wrapped.(MessageQuerier).QueryMessages(MessageQuery{})
+	if !errors.Is(err, UnsupportedExtensionErr) {
+		t.Errorf("Unexpected error for model without queries: %+v", err)
+	}
+	wrapped = WrapEventModel(&queryEvent{},
+		FilterReceived(func(*ReceivedMessage) bool { return true }))
+	if msgs, err2 := wrapped.(Searcher).Search(SearchQuery{}); err2 != nil ||
+		len(msgs) != 1 {
+		t.Errorf("Unexpected search results %+v: %+v", msgs, err2)
+	}
+}
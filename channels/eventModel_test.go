@@ -98,9 +98,9 @@ func Test_initEvents(t *testing.T) {
 	}
 
 	// check that all the default callbacks are registered
-	if len(e.registered) != 8 {
+	if len(e.registered) != 18 {
 		t.Errorf("The correct number of default handlers are not "+
-			"registered; %d vs %d", len(e.registered), 7)
+			"registered; %d vs %d", len(e.registered), 18)
 		// If this fails, is means the default handlers have changed. edit the
 		// number here and add tests below. be suspicious if it goes down.
 	}
@@ -119,6 +119,36 @@ func Test_initEvents(t *testing.T) {
 
 	require.Equal(t,
 		getFuncName(e.registered[Invitation].listener), getFuncName(e.receiveInvitation))
+
+	require.Equal(t,
+		getFuncName(e.registered[Edit].listener), getFuncName(e.receiveEdit))
+
+	require.Equal(t,
+		getFuncName(e.registered[Poll].listener), getFuncName(e.receivePoll))
+
+	require.Equal(t, getFuncName(e.registered[PollVote].listener),
+		getFuncName(e.receivePollVote))
+
+	require.Equal(t, getFuncName(e.registered[PollClose].listener),
+		getFuncName(e.receivePollClose))
+
+	require.Equal(t, getFuncName(e.registered[Moderator].listener),
+		getFuncName(e.receiveModerator))
+
+	require.Equal(t, getFuncName(e.registered[KeyRotation].listener),
+		getFuncName(e.receiveKeyRotation))
+
+	require.Equal(t, getFuncName(e.registered[SlowMode].listener),
+		getFuncName(e.receiveSlowMode))
+
+	require.Equal(t,
+		getFuncName(e.registered[Ban].listener), getFuncName(e.receiveBan))
+
+	require.Equal(t, getFuncName(e.registered[MetadataUpdate].listener),
+		getFuncName(e.receiveMetadataUpdate))
+
+	require.Equal(t,
+		getFuncName(e.registered[Typing].listener), getFuncName(e.receiveTyping))
 }
 
 // Unit test of NewReceiveMessageHandler.
@@ -247,6 +277,70 @@ func Test_events_RegisterReceiveHandler(t *testing.T) {
 	}
 }
 
+// Tests that events.RegisterReceiveHandler chains handlers registered for Text
+// after the default handler and that both are called on reception.
+func Test_events_RegisterReceiveHandler_ChainText(t *testing.T) {
+	me := &MockEvent{}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	var chainedCalls int
+	var chainedText string
+	listener := func(_ *id.ID, _ message.ID, _ MessageType, _ string,
+		content, _ []byte, _ ed25519.PublicKey, _ uint32, _ uint8, _,
+		_ time.Time, _ time.Duration, _ id.Round, _ rounds.Round,
+		_ SentStatus, _, _ bool) uint64 {
+		txt := &CMIXChannelText{}
+		if err := proto.Unmarshal(content, txt); err != nil {
+			t.Errorf("Failed to unmarshal text: %+v", err)
+		}
+		chainedCalls++
+		chainedText = txt.Text
+		return 5
+	}
+
+	for i := 0; i < 2; i++ {
+		err := e.RegisterReceiveHandler(Text, NewReceiveMessageHandler(
+			"bot", listener, false, true, true))
+		if err != nil {
+			t.Fatalf("Failed to chain handler %d for %s: %+v", i, Text, err)
+		}
+	}
+
+	handler := e.registered[Text]
+	if err := handler.CheckSpace(true, false, false); err != nil {
+		t.Errorf("Chained handler did not keep the default spaces: %+v", err)
+	}
+	if err := handler.CheckSpace(false, false, true); err == nil {
+		t.Errorf("Chained handler took the spaces of the chained listener.")
+	}
+
+	content, err := proto.Marshal(&CMIXChannelText{Version: 0, Text: "/ping"})
+	if err != nil {
+		t.Fatalf("Failed to marshal text: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	msgID := message.DeriveChannelMessageID(chID, 5, content)
+	uuid := handler.listener(chID, msgID, Text, "nick", content, nil, nil, 0,
+		0, netTime.Now(), time.Time{}, time.Hour, 0, rounds.Round{ID: 5},
+		Delivered, false, false)
+
+	if uuid != 0 {
+		t.Errorf("UUID not from default handler.\nexpected: %d\nreceived: %d",
+			0, uuid)
+	}
+	if !me.eventReceive.messageID.Equals(msgID) {
+		t.Errorf("Default handler not called.\nexpected: %s\nreceived: %s",
+			msgID, me.eventReceive.messageID)
+	}
+	if chainedCalls != 2 || chainedText != "/ping" {
+		t.Errorf("Chained handlers not called as expected."+
+			"\nexpected: %d calls with %q\nreceived: %d calls with %q",
+			2, "/ping", chainedCalls, chainedText)
+	}
+}
+
 func getFuncName(i interface{}) string {
 	return runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name()
 }
@@ -362,6 +456,81 @@ func Test_events_triggerEvents_noChannel(t *testing.T) {
 	}
 }
 
+// Tests that events.triggerEvent hides delivered messages from a user once they
+// exceed the flood filter and does not hide messages sent by this user.
+func Test_events_triggerEvents_FloodFilter(t *testing.T) {
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+	require.NoError(t, e.flood.set(2, time.Minute))
+
+	var hidden []bool
+	e.registered[Text] = &ReceiveMessageHandler{"text", func(_ *id.ID,
+		_ message.ID, _ MessageType, _ string, _, _ []byte,
+		_ ed25519.PublicKey, _ uint32, _ uint8, _, _ time.Time,
+		_ time.Duration, _ id.Round, _ rounds.Round, _ SentStatus, _,
+		h bool) uint64 {
+		hidden = append(hidden, h)
+		return 0
+	}, true, false, false}
+
+	chID := &id.ID{1}
+	umi, _, _ := builtTestUMI(t, Text)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+
+	for _, status := range []SentStatus{Unsent, Unsent, Unsent, Delivered,
+		Delivered, Delivered, Delivered} {
+		_, err := e.triggerEvent(chID, umi, nil, ts,
+			receptionID.EphemeralIdentity{}, r, status)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t,
+		[]bool{false, false, false, false, false, true, true}, hidden)
+}
+
+// Tests that events.triggerEvent only handles a moderator action sent as a
+// user message when the sender is a moderator of the channel and that it is
+// then handled as an admin message.
+func Test_events_triggerEvents_Moderator(t *testing.T) {
+	me := &MockEvent{}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Replace the pinned handler, which only accepts admin messages
+	dummy := &dummyMessageTypeHandler{}
+	e.registered[Pinned] = NewReceiveMessageHandler(
+		"dummy", dummy.dummyMessageTypeReceiveMessage, false, true, false)
+
+	chID := &id.ID{1}
+	umi, um, _ := builtTestUMI(t, Pinned)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+
+	// A user who is not a moderator cannot pin
+	_, err := e.triggerEvent(chID, umi, []byte("encrypted"), netTime.Now(),
+		receptionID.EphemeralIdentity{}, r, Delivered)
+	if err == nil || dummy.triggered {
+		t.Fatalf("Pin from a user who is not a moderator was handled.")
+	}
+
+	// A moderator can pin
+	e.moderators.addModerator(chID, um.ECCPublicKey)
+	_, err = e.triggerEvent(chID, umi, []byte("encrypted"), netTime.Now(),
+		receptionID.EphemeralIdentity{}, r, Delivered)
+	if err != nil {
+		t.Fatalf("Failed to handle pin from moderator: %+v", err)
+	}
+	if !dummy.triggered {
+		t.Errorf("Pin from moderator was not handled.")
+	}
+	if dummy.encryptedPayload != nil {
+		t.Errorf("Encrypted payload of moderator action not dropped: %q",
+			dummy.encryptedPayload)
+	}
+}
+
 func Test_events_triggerAdminEvents(t *testing.T) {
 	me := &MockEvent{}
 	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
@@ -455,7 +624,7 @@ func TestEvents_triggerActionEvent(t *testing.T) {
 
 	// Call the trigger
 	_, err = e.triggerActionEvent(chID, msgID, mt,
-		cm.Nickname, cm.Payload, nil, netTime.Now(), netTime.Now(),
+		cm.Nickname, cm.Payload, nil, nil, netTime.Now(), netTime.Now(),
 		time.Duration(cm.Lease), r.ID, r, Delivered, true)
 	if err != nil {
 		t.Fatal(err)
@@ -831,6 +1000,542 @@ func Test_events_receiveDelete(t *testing.T) {
 	}
 }
 
+// Unit test of events.receiveEdit.
+func Test_events_receiveEdit(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	targetMessageID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	textPayload := &CMIXChannelEdit{
+		Version:   0,
+		MessageID: targetMessageID[:],
+		Text:      "edited text",
+	}
+	textMarshaled, err := proto.Marshal(textPayload)
+	if err != nil {
+		t.Fatalf("Failed to proto marshal %T: %+v", textPayload, err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	senderUsername := "Alice"
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	me.eventReceive = eventReceive{chID, message.ID{},
+		targetMessageID, senderUsername, []byte("original text"), ts, lease, r,
+		Delivered, false, false, Text, 0, 0}
+
+	// Call the handler
+	e.receiveEdit(chID, msgID, Edit, AdminUsername, textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered,
+		true, false)
+
+	// Check the results on the model
+	if string(me.eventReceive.content) != textPayload.Text {
+		t.Errorf("Message not edited.\nexpected: %q\nreceived: %q",
+			textPayload.Text, me.eventReceive.content)
+	}
+}
+
+// Tests that events.receiveEdit drops an edit that is not from the admin or
+// the original sender of the message.
+func Test_events_receiveEdit_NotSender(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	targetMessageID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	textPayload := &CMIXChannelEdit{
+		Version:   0,
+		MessageID: targetMessageID[:],
+		Text:      "edited text",
+	}
+	textMarshaled, err := proto.Marshal(textPayload)
+	if err != nil {
+		t.Fatalf("Failed to proto marshal %T: %+v", textPayload, err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	senderUsername := "Alice"
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("original text")
+	me.eventReceive = eventReceive{chID, message.ID{},
+		targetMessageID, senderUsername, content, ts, lease, r, Delivered,
+		false, false, Text, 0, 0}
+
+	// Call the handler
+	e.receiveEdit(chID, msgID, Edit, senderUsername, textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered,
+		false, false)
+
+	// Check the results on the model
+	if !bytes.Equal(me.eventReceive.content, content) {
+		t.Errorf("Message edited by user that is not the sender."+
+			"\nexpected: %q\nreceived: %q", content, me.eventReceive.content)
+	}
+}
+
+// Tests that events.receiveEdit saves user edits received before their target
+// message and that, once the message is received, only the edit from the
+// original sender is applied.
+func Test_events_receiveEdit_EarlyEdits(t *testing.T) {
+	prng := rand.New(rand.NewSource(65))
+	alice, _ := cryptoChannel.GenerateIdentity(prng)
+	mallory, _ := cryptoChannel.GenerateIdentity(prng)
+	me := &earlyEditEvent{MockEvent: &MockEvent{}, sender: alice.PubKey}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	targetMessageID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+
+	edits := []struct {
+		sender cryptoChannel.PrivateIdentity
+		text   string
+	}{{alice, "edited by alice"}, {mallory, "edited by mallory"}}
+	for _, edit := range edits {
+		content, err := proto.Marshal(&CMIXChannelEdit{
+			MessageID: targetMessageID.Marshal(), Text: edit.text})
+		if err != nil {
+			t.Fatalf("Failed to proto marshal edit: %+v", err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+		e.receiveEdit(chID, msgID, Edit, "", content, nil, edit.sender.PubKey,
+			0, edit.sender.CodesetVersion, ts, ts, ValidForever, r.ID, r,
+			Delivered, false, false)
+	}
+	if len(me.edits) != 0 {
+		t.Fatalf("Edits for a message that does not exist were not saved: %+v",
+			me.edits)
+	}
+
+	// Receive the message and apply the saved edits
+	updateFn, deleted := e.as.CheckSavedActions(chID, targetMessageID)
+	if deleted || updateFn == nil {
+		t.Fatalf("No saved edits found for message (deleted: %t).", deleted)
+	}
+	me.received = true
+	if _, err := updateFn(); err != nil {
+		t.Fatalf("Failed to apply saved edits: %+v", err)
+	}
+
+	if len(me.edits) != 1 || me.edits[0].Text != edits[0].text {
+		t.Errorf("Unexpected edits applied.\nexpected: %q\nreceived: %+v",
+			edits[0].text, me.edits)
+	}
+}
+
+// earlyEditEvent is a MockEvent that records every edit and whose message,
+// sent by sender, does not exist until received is set.
+type earlyEditEvent struct {
+	*MockEvent
+	sender   ed25519.PublicKey
+	received bool
+	edits    []MessageEdit
+}
+
+func (m *earlyEditEvent) GetMessage(messageID message.ID) (ModelMessage, error) {
+	if !m.received {
+		return ModelMessage{}, NoMessageErr
+	}
+	msg, err := m.MockEvent.GetMessage(messageID)
+	msg.PubKey = m.sender
+	return msg, err
+}
+
+func (m *earlyEditEvent) EditMessage(
+	messageID message.ID, edit MessageEdit) (uint64, error) {
+	m.edits = append(m.edits, edit)
+	return m.MockEvent.EditMessage(messageID, edit)
+}
+
+// Unit test of events.receivePoll.
+func Test_events_receivePoll(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	textPayload := &CMIXChannelPoll{
+		Version:  0,
+		Question: "Which option?",
+		Options:  []string{"a", "b", "c"},
+	}
+	textMarshaled, err := proto.Marshal(textPayload)
+	if err != nil {
+		t.Fatalf("Failed to proto marshal %T: %+v", textPayload, err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	senderUsername := "Alice"
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call the handler
+	e.receivePoll(chID, msgID, Poll, senderUsername, textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered,
+		false, false)
+
+	// Check the results on the model
+	if me.eventReceive.messageType != Poll {
+		t.Errorf("Incorrect message type.\nexpected: %s\nreceived: %s",
+			Poll, me.eventReceive.messageType)
+	}
+	received := &CMIXChannelPoll{}
+	if err = json.Unmarshal(me.eventReceive.content, received); err != nil {
+		t.Fatalf("Failed to JSON unmarshal poll: %+v", err)
+	}
+	if !proto.Equal(textPayload, received) {
+		t.Errorf("Received poll does not match expected."+
+			"\nexpected: %s\nreceived: %s", textPayload, received)
+	}
+}
+
+// Tests that events.receivePoll drops a poll with fewer than two options.
+func Test_events_receivePoll_TooFewOptions(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	textMarshaled, err := proto.Marshal(
+		&CMIXChannelPoll{Question: "Which option?", Options: []string{"a"}})
+	if err != nil {
+		t.Fatalf("Failed to proto marshal poll: %+v", err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	ts := netTime.Now()
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.receivePoll(chID, msgID, Poll, "Alice", textMarshaled, nil, pi.PubKey,
+		0, pi.CodesetVersion, ts, ts, time.Minute, r.ID, r, Delivered, false,
+		false)
+
+	if !reflect.DeepEqual(me.eventReceive, eventReceive{}) {
+		t.Errorf("Poll with too few options was not dropped: %+v",
+			me.eventReceive)
+	}
+}
+
+// Unit test of events.receivePollVote.
+func Test_events_receivePollVote(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	pollID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	pollJSON, err := json.Marshal(&CMIXChannelPoll{
+		Question: "Which option?", Options: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	me.eventReceive = eventReceive{chID, pollID, message.ID{}, "Bob",
+		pollJSON, ts, lease, r, Delivered, false, false, Poll, 0, 0}
+
+	for _, option := range []uint32{1, 2} {
+		textMarshaled, err2 := proto.Marshal(&CMIXChannelPollVote{
+			PollMessageID: pollID.Marshal(), Option: option})
+		if err2 != nil {
+			t.Fatalf("Failed to proto marshal vote: %+v", err2)
+		}
+		msgID := message.DeriveChannelMessageID(
+			chID, uint64(r.ID), textMarshaled)
+
+		// Call the handler
+		e.receivePollVote(chID, msgID, PollVote, "Alice", textMarshaled,
+			nil, pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r,
+			Delivered, false, false)
+	}
+
+	// Check the results on the model; the vote for option 2 should have been
+	// dropped since the poll only has two options
+	if me.pollVote.Option != 1 {
+		t.Errorf("Incorrect vote option.\nexpected: %d\nreceived: %d",
+			1, me.pollVote.Option)
+	}
+	if !bytes.Equal(me.pollVote.PubKey, pi.PubKey) {
+		t.Errorf("Incorrect vote public key.\nexpected: %x\nreceived: %x",
+			pi.PubKey, me.pollVote.PubKey)
+	}
+}
+
+// Tests that events.receivePollVote saves votes received before their poll
+// and that the newest vote from each voter is applied once the poll is
+// received.
+func Test_events_receivePollVote_EarlyVotes(t *testing.T) {
+	me := &earlyVoteEvent{MockEvent: &MockEvent{}}
+	prng := rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	pollID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	alice, _ := cryptoChannel.GenerateIdentity(prng)
+	bob, _ := cryptoChannel.GenerateIdentity(prng)
+
+	// Alice changes her vote after Bob votes
+	votes := []struct {
+		voter  cryptoChannel.PrivateIdentity
+		option uint32
+		ts     time.Time
+	}{{alice, 0, ts}, {bob, 0, ts.Add(time.Second)},
+		{alice, 1, ts.Add(2 * time.Second)}}
+	for _, v := range votes {
+		content, err := proto.Marshal(&CMIXChannelPollVote{
+			PollMessageID: pollID.Marshal(), Option: v.option})
+		if err != nil {
+			t.Fatalf("Failed to proto marshal vote: %+v", err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+		e.receivePollVote(chID, msgID, PollVote, "", content, nil,
+			v.voter.PubKey, 0, v.voter.CodesetVersion, v.ts, v.ts, time.Hour,
+			r.ID, r, Delivered, false, false)
+	}
+	if len(me.votes) != 0 {
+		t.Fatalf("Votes for a poll that does not exist were not saved: %+v",
+			me.votes)
+	}
+
+	// Receive the poll and apply the saved votes
+	updateFn, deleted := e.as.CheckSavedActions(chID, pollID)
+	if deleted || updateFn == nil {
+		t.Fatalf("No saved votes found for poll (deleted: %t).", deleted)
+	}
+	pollJSON, err := json.Marshal(&CMIXChannelPoll{
+		Question: "Which option?", Options: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	me.pollReceived = true
+	me.eventReceive = eventReceive{chID, pollID, message.ID{}, "Carol",
+		pollJSON, ts, time.Hour, r, Delivered, false, false, Poll, 0, 0}
+	if _, err = updateFn(); err != nil {
+		t.Fatalf("Failed to apply saved votes: %+v", err)
+	}
+
+	expected := map[string]uint32{
+		string(alice.PubKey): 1, string(bob.PubKey): 0}
+	received := make(map[string]uint32, len(me.votes))
+	for _, vote := range me.votes {
+		received[string(vote.PubKey)] = vote.Option
+	}
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("Unexpected votes applied.\nexpected: %v\nreceived: %v",
+			expected, received)
+	}
+}
+
+// earlyVoteEvent is a MockEvent that records every vote and whose poll does not
+// exist until pollReceived is set.
+type earlyVoteEvent struct {
+	*MockEvent
+	pollReceived bool
+	votes        []ModelPollVote
+}
+
+func (m *earlyVoteEvent) GetMessage(messageID message.ID) (ModelMessage, error) {
+	if !m.pollReceived {
+		return ModelMessage{}, NoMessageErr
+	}
+	return m.MockEvent.GetMessage(messageID)
+}
+
+func (m *earlyVoteEvent) ReceivePollVote(
+	pollMessageID message.ID, vote ModelPollVote) (uint64, error) {
+	m.votes = append(m.votes, vote)
+	return m.MockEvent.ReceivePollVote(pollMessageID, vote)
+}
+
+// Tests that events.receivePollVote drops a vote for a message that is not a
+// poll.
+func Test_events_receivePollVote_NotPoll(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	targetID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	textMarshaled, err := proto.Marshal(&CMIXChannelPollVote{
+		PollMessageID: targetID.Marshal(), Option: 0})
+	if err != nil {
+		t.Fatalf("Failed to proto marshal vote: %+v", err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	ts := netTime.Now()
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	me.eventReceive = eventReceive{chID, targetID, message.ID{}, "Bob",
+		[]byte("text"), ts, time.Minute, r, Delivered, false, false, Text, 0,
+		0}
+
+	e.receivePollVote(chID, msgID, PollVote, "Alice", textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, time.Minute, r.ID, r,
+		Delivered, false, false)
+
+	if !reflect.DeepEqual(me.pollVote, ModelPollVote{}) {
+		t.Errorf("Vote for message that is not a poll was not dropped: %+v",
+			me.pollVote)
+	}
+}
+
+// Unit test of events.receivePollClose.
+func Test_events_receivePollClose(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	pollID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	textPayload := &CMIXChannelPollClose{
+		Version:       0,
+		PollMessageID: pollID[:],
+		UndoAction:    false,
+	}
+	textMarshaled, err := proto.Marshal(textPayload)
+	if err != nil {
+		t.Fatalf("Failed to proto marshal %T: %+v", textPayload, err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call the handler
+	e.receivePollClose(chID, msgID, PollClose, AdminUsername, textMarshaled,
+		nil, pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r,
+		Delivered, true, false)
+
+	// Check the results on the model
+	if !me.pollClosed {
+		t.Errorf("Poll not closed.")
+	}
+}
+
+// Tests that events.receiveTyping passes the typing indicator to the UI
+// callback and does not store it in the event model.
+func Test_events_receiveTyping(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	type typingUpdate struct {
+		channelID *id.ID
+		pubKey    ed25519.PublicKey
+		nickname  string
+		typing    bool
+	}
+	updates := make(chan typingUpdate, 1)
+	e.userTyping = func(channelID *id.ID, pubKey ed25519.PublicKey, _ uint8,
+		nickname string, typing bool) {
+		updates <- typingUpdate{channelID, pubKey, nickname, typing}
+	}
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	textMarshaled, err := proto.Marshal(&CMIXChannelTyping{Typing: true})
+	if err != nil {
+		t.Fatalf("Failed to proto marshal typing indicator: %+v", err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call the handler
+	ts := netTime.Now()
+	uuid := e.receiveTyping(chID, msgID, Typing, "Alice", textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, TypingTimeout, r.ID, r,
+		Delivered, false, false)
+	if uuid != 0 {
+		t.Errorf("Typing indicator returned a UUID: %d", uuid)
+	}
+
+	expected := typingUpdate{chID, pi.PubKey, "Alice", true}
+	select {
+	case u := <-updates:
+		if !reflect.DeepEqual(expected, u) {
+			t.Errorf("Unexpected typing update.\nexpected: %+v\nreceived: %+v",
+				expected, u)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Errorf("Timed out waiting for typing callback.")
+	}
+
+	if !reflect.DeepEqual(me.eventReceive, eventReceive{}) {
+		t.Errorf("Typing indicator was stored: %+v", me.eventReceive)
+	}
+
+	// Indicators older than TypingTimeout must be dropped
+	ts = netTime.Now().Add(-2 * TypingTimeout)
+	e.receiveTyping(chID, msgID, Typing, "Alice", textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts, ts, TypingTimeout, r.ID, r,
+		Delivered, false, false)
+	select {
+	case u := <-updates:
+		t.Errorf("Received stale typing indicator: %+v", u)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
 // Unit test of events.receivePinned.
 func Test_events_receivePinned(t *testing.T) {
 	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
@@ -929,6 +1634,397 @@ func Test_events_receiveMute(t *testing.T) {
 	}
 }
 
+// Tests that events.receiveModerator appoints the moderator and that it revokes
+// the moderator when receiving the undo action.
+func Test_events_receiveModerator(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	moderator, _, _ := ed25519.GenerateKey(prng)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+
+	for _, undoAction := range []bool{false, true} {
+		// The revocation must be in a newer round than the appointment
+		r.ID++
+		content, err := proto.Marshal(&CMIXChannelModerator{
+			Version:    0,
+			PubKey:     moderator,
+			UndoAction: undoAction,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+
+		e.receiveModerator(chID, msgID, Moderator, AdminUsername, content,
+			[]byte("encrypted"), nil, 0, 0, ts, ts, lease, r.ID, r, Delivered,
+			true, false)
+
+		if e.moderators.isModerator(chID, moderator) == undoAction {
+			t.Errorf("Unexpected moderator status after undoAction=%t.",
+				undoAction)
+		}
+	}
+}
+
+// Tests that events.receiveKeyRotation calls the key rotation hook with the
+// hash of the new admin key and does not call it for an invalid hash.
+func Test_events_receiveKeyRotation(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	type rotation struct {
+		channelID     *id.ID
+		rsaPubKeyHash []byte
+	}
+	rotationChan := make(chan rotation, 1)
+	e.keyRotation = func(channelID *id.ID, rsaPubKeyHash []byte) {
+		rotationChan <- rotation{channelID, rsaPubKeyHash}
+	}
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+
+	for _, rsaPubKeyHash := range [][]byte{
+		[]byte("invalid"), bytes.Repeat([]byte{1}, rsaPubKeyHashLen)} {
+		content, err := proto.Marshal(&CMIXChannelKeyRotation{
+			Version:       cmixChannelKeyRotationVersion,
+			RsaPubKeyHash: rsaPubKeyHash,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+
+		e.receiveKeyRotation(chID, msgID, KeyRotation, AdminUsername, content,
+			[]byte("encrypted"), nil, 0, 0, ts, ts, ValidForever, r.ID, r,
+			Delivered, true, false)
+	}
+
+	select {
+	case received := <-rotationChan:
+		if !chID.Cmp(received.channelID) {
+			t.Errorf("Unexpected channel ID.\nexpected: %s\nreceived: %s",
+				chID, received.channelID)
+		}
+		expected := bytes.Repeat([]byte{1}, rsaPubKeyHashLen)
+		if !bytes.Equal(expected, received.rsaPubKeyHash) {
+			t.Errorf("Unexpected key hash.\nexpected: %x\nreceived: %x",
+				expected, received.rsaPubKeyHash)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Timed out waiting for key rotation.")
+	}
+
+	select {
+	case received := <-rotationChan:
+		t.Errorf("Key rotated with invalid hash: %+v", received)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// Tests that events.receiveSlowMode sets the slow mode interval of the channel,
+// ignores messages older than the current interval, and stores the newest
+// interval in the lease system.
+func Test_events_receiveSlowMode(t *testing.T) {
+	prng := rand.New(rand.NewSource(65))
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+
+	payload, err := proto.Marshal(&CMIXChannelSlowMode{})
+	require.NoError(t, err)
+	fp := newCommandFingerprint(chID, SlowMode, payload)
+
+	tests := []struct {
+		interval, expected time.Duration
+		originatingTS      time.Time
+		originatingRound   id.Round
+		leaseRound         id.Round
+	}{
+		{time.Minute, time.Minute, ts, 420, 420},
+		{time.Hour, time.Minute, ts.Add(-time.Second), 419, 420},
+		{-time.Hour, time.Minute, ts.Add(time.Second), 421, 420},
+		{0, 0, ts.Add(time.Second), 422, 422},
+	}
+	for i, tt := range tests {
+		content, err := proto.Marshal(&CMIXChannelSlowMode{
+			Version:  cmixChannelSlowModeVersion,
+			Interval: int64(tt.interval),
+		})
+		require.NoError(t, err)
+		msgID := message.DeriveChannelMessageID(
+			chID, uint64(tt.originatingRound), content)
+
+		e.receiveSlowMode(chID, msgID, SlowMode, AdminUsername, content,
+			[]byte("encrypted"), nil, 0, 0, ts, tt.originatingTS, ValidForever,
+			tt.originatingRound, r, Delivered, true, false)
+
+		require.Equal(t, tt.expected, e.slowMode.getInterval(chID),
+			"Test %d: %+v", i, tt)
+		cm, exists := e.leases.rb.commandsByChannel[*chID][fp.key()]
+		require.True(t, exists, "Test %d: %+v", i, tt)
+		require.Equal(t, tt.leaseRound, cm.OriginatingRound,
+			"Test %d: %+v", i, tt)
+	}
+}
+
+// Tests that events.receiveMetadataUpdate passes the newest update to the event
+// model, drops older, replayed, and invalid updates, and stores the newest
+// update in the lease system so that it can be replayed.
+func Test_events_receiveMetadataUpdate(t *testing.T) {
+	prng := rand.New(rand.NewSource(65))
+	me := &MockEvent{}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now().Round(0)
+
+	payload, err := proto.Marshal(&CMIXChannelMetadata{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		topic, expected  string
+		icon             []byte
+		originatingTS    time.Time
+		originatingRound id.Round
+	}{
+		{"first", "first", nil, ts, 420},
+		{"older", "first", nil, ts.Add(-time.Second), 419},
+		{"replay", "first", nil, ts, 420},
+		{"large", "first", make([]byte, MaxChannelIconSize+1),
+			ts.Add(time.Second), 421},
+		{"second", "second", []byte("icon"), ts.Add(time.Second), 422},
+	}
+	for i, tt := range tests {
+		content, err := proto.Marshal(&CMIXChannelMetadata{
+			Version:     cmixChannelMetadataVersion,
+			Description: "description",
+			Icon:        tt.icon,
+			Topic:       tt.topic,
+		})
+		require.NoError(t, err)
+		msgID := message.DeriveChannelMessageID(
+			chID, uint64(tt.originatingRound), content)
+
+		e.receiveMetadataUpdate(chID, msgID, MetadataUpdate, AdminUsername,
+			content, []byte(tt.topic), nil, 0, 0, ts, tt.originatingTS,
+			ValidForever, tt.originatingRound, r, Delivered, true, false)
+
+		require.Equal(t, tt.expected, me.metadata.Topic, "Test %d: %+v", i, tt)
+		metadata, exists := e.metadata.get(chID)
+		require.True(t, exists, "Test %d: %+v", i, tt)
+		require.Equal(t, me.metadata, metadata, "Test %d: %+v", i, tt)
+
+		// The newest update is stored for replay
+		cm, err := e.leases.store.LoadCommand(chID, MetadataUpdate, payload)
+		require.NoError(t, err, "Test %d: %+v", i, tt)
+		require.Equal(t, []byte(tt.expected), cm.EncryptedPayload,
+			"Test %d: %+v", i, tt)
+	}
+	require.Equal(t, []byte("icon"), me.metadata.Icon)
+	require.True(t, ts.Add(time.Second).Equal(me.metadata.Timestamp))
+
+	// Replaying the stored update to a new user sets the same metadata
+	cm, err := e.leases.store.LoadCommand(chID, MetadataUpdate, payload)
+	require.NoError(t, err)
+	me2 := &MockEvent{}
+	e2 := initEvents(me2, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+	content, err := proto.Marshal(&CMIXChannelMetadata{
+		Version:     cmixChannelMetadataVersion,
+		Description: "description",
+		Icon:        []byte("icon"),
+		Topic:       "second",
+	})
+	require.NoError(t, err)
+	e2.receiveMetadataUpdate(chID, cm.MessageID, MetadataUpdate,
+		AdminUsername, content, cm.EncryptedPayload, nil, 0, 0, ts,
+		cm.OriginatingTimestamp, cm.Lease, cm.OriginatingRound, cm.Round,
+		Delivered, true, false)
+	require.Equal(t, "second", me2.metadata.Topic)
+	require.Equal(t, []byte("icon"), me2.metadata.Icon)
+	require.True(t, me.metadata.Timestamp.Equal(me2.metadata.Timestamp))
+}
+
+// Tests that events.receiveBan bans and mutes the user, hides their existing
+// messages, and that the ban keeps the user muted until it is undone.
+func Test_events_receiveBan(t *testing.T) {
+	prng := rand.New(rand.NewSource(65))
+	chID, _ := id.NewRandomID(prng, id.User)
+	banned, _, _ := ed25519.GenerateKey(prng)
+	other, _, _ := ed25519.GenerateKey(prng)
+	me := &mockQuerierEvent{
+		msgs: []ModelMessage{
+			{MessageID: message.ID{1}, ChannelID: chID, PubKey: banned},
+			{MessageID: message.ID{2}, ChannelID: chID, PubKey: other},
+			{MessageID: message.ID{3}, ChannelID: chID, PubKey: banned},
+		},
+		hidden: make(map[message.ID]bool),
+	}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	receive := func(msgType MessageType, msg proto.Message) {
+		// Each undo must be in a newer round than the action it undoes
+		r.ID++
+		content, err := proto.Marshal(msg)
+		require.NoError(t, err)
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+		e.registered[msgType].listener(chID, msgID, msgType, AdminUsername,
+			content, []byte("encrypted"), nil, 0, 0, ts, ts, ValidForever,
+			r.ID, r, Delivered, true, false)
+	}
+
+	receive(Ban, &CMIXChannelBan{PubKey: banned, Reason: "spam"})
+	require.True(t, e.mutedUsers.isMuted(chID, banned))
+	bannedUsers := e.bans.getBannedUsers(chID)
+	require.Len(t, bannedUsers, 1)
+	require.Equal(t, banned, bannedUsers[0].PubKey)
+	require.Equal(t, "spam", bannedUsers[0].Reason)
+	require.Equal(t,
+		map[message.ID]bool{{1}: true, {3}: true}, me.hidden)
+
+	// Unmuting a banned user keeps them muted
+	receive(Mute, &CMIXChannelMute{PubKey: banned, UndoAction: true})
+	require.True(t, e.mutedUsers.isMuted(chID, banned))
+
+	receive(Ban, &CMIXChannelBan{PubKey: banned, UndoAction: true})
+	require.False(t, e.mutedUsers.isMuted(chID, banned))
+	require.Empty(t, e.bans.getBannedUsers(chID))
+}
+
+// Tests that undoing the ban of a user who was muted before the ban keeps
+// them muted and that the messages of a banned user are hidden when the
+// querier is wrapped in a composite model and middleware.
+func Test_events_receiveBan_MutedBeforeBan(t *testing.T) {
+	prng := rand.New(rand.NewSource(66))
+	chID, _ := id.NewRandomID(prng, id.User)
+	banned, _, _ := ed25519.GenerateKey(prng)
+	me := &mockQuerierEvent{
+		msgs: []ModelMessage{
+			{MessageID: message.ID{1}, ChannelID: chID, PubKey: banned}},
+		hidden: make(map[message.ID]bool),
+	}
+	model := WrapEventModel(NewMultiEventModel(me, &MockEvent{}),
+		FilterReceived(func(*ReceivedMessage) bool { return true }))
+	e := initEvents(model, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	receive := func(msgType MessageType, msg proto.Message) {
+		r.ID++
+		content, err := proto.Marshal(msg)
+		require.NoError(t, err)
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), content)
+		e.registered[msgType].listener(chID, msgID, msgType, AdminUsername,
+			content, []byte("encrypted"), nil, 0, 0, ts, ts, ValidForever,
+			r.ID, r, Delivered, true, false)
+	}
+
+	receive(Mute, &CMIXChannelMute{PubKey: banned})
+	receive(Ban, &CMIXChannelBan{PubKey: banned, Reason: "spam"})
+	require.Equal(t, map[message.ID]bool{{1}: true}, me.hidden)
+	require.False(t, e.bans.getBannedUsers(chID)[0].Muted)
+
+	receive(Ban, &CMIXChannelBan{PubKey: banned, UndoAction: true})
+	require.Empty(t, e.bans.getBannedUsers(chID))
+	require.True(t, e.mutedUsers.isMuted(chID, banned))
+}
+
+// mockQuerierEvent is a MockEvent that implements MessageQuerier and records
+// the messages hidden with UpdateFromMessageID.
+type mockQuerierEvent struct {
+	MockEvent
+	msgs   []ModelMessage
+	hidden map[message.ID]bool
+}
+
+func (m *mockQuerierEvent) QueryMessages(
+	query MessageQuery) ([]ModelMessage, int64, error) {
+	var msgs []ModelMessage
+	for _, msg := range m.msgs {
+		if msg.ChannelID.Cmp(query.ChannelID) && msg.PubKey.Equal(query.PubKey) {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, int64(len(msgs)), nil
+}
+
+func (m *mockQuerierEvent) UpdateFromMessageID(messageID message.ID,
+	_ *time.Time, _ *rounds.Round, _, hidden *bool, _ *SentStatus) (
+	uint64, error) {
+	if hidden != nil {
+		m.hidden[messageID] = *hidden
+	}
+	return 0, nil
+}
+
+// Tests that events.receiveTextMessage passes the spans parsed from the text
+// to an event model that implements TextSpanReceiver and skips messages with
+// no spans.
+func Test_events_receiveTextMessage_TextSpans(t *testing.T) {
+	me := &mockSpanEvent{MockEvent: MockEvent{uuid: 1},
+		spans: make(map[uint64][]TextSpan)}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+	pi, err := cryptoChannel.GenerateIdentity(rand.New(rand.NewSource(64)))
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	r := rounds.Round{ID: 420}
+	for _, text := range []string{"**hi** " + MentionTag(pi.PubKey), "hi"} {
+		textMarshaled, err2 := proto.Marshal(&CMIXChannelText{Text: text})
+		if err2 != nil {
+			t.Fatalf("Failed to marshal the message proto: %+v", err2)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+		e.receiveTextMessage(chID, msgID, Text, "Alice", textMarshaled, nil,
+			pi.PubKey, 0, pi.CodesetVersion, netTime.Now(), netTime.Now(),
+			time.Minute, r.ID, r, Delivered, false, false)
+	}
+
+	expected := map[uint64][]TextSpan{1: ParseText("**hi** " +
+		MentionTag(pi.PubKey))}
+	if !reflect.DeepEqual(expected, me.spans) {
+		t.Errorf("Unexpected spans.\nexpected: %+v\nreceived: %+v",
+			expected, me.spans)
+	}
+}
+
+// mockSpanEvent is a MockEvent that implements TextSpanReceiver and records
+// the spans it receives.
+type mockSpanEvent struct {
+	MockEvent
+	spans map[uint64][]TextSpan
+}
+
+func (m *mockSpanEvent) ReceiveTextSpans(uuid uint64, spans []TextSpan) {
+	m.spans[uuid] = spans
+}
+
 // Unit test of events.receiveAdminReplay.
 func Test_events_receiveAdminReplay(t *testing.T) {
 	me, prng := &MockEvent{}, csprng.NewSystemRNG()
@@ -1032,6 +2128,9 @@ var _ EventModel = (*MockEvent)(nil)
 type MockEvent struct {
 	uuid uint64
 	eventReceive
+	pollVote   ModelPollVote
+	pollClosed bool
+	metadata   ChannelMetadata
 }
 
 func (m *MockEvent) getUUID() uint64 {
@@ -1206,6 +2305,25 @@ func (m *MockEvent) GetMessage(message.ID) (ModelMessage, error) {
 
 func (m *MockEvent) MuteUser(*id.ID, ed25519.PublicKey, bool) {}
 
+func (m *MockEvent) EditMessage(_ message.ID, edit MessageEdit) (uint64, error) {
+	m.eventReceive.content = []byte(edit.Text)
+	return m.getUUID(), nil
+}
+
+func (m *MockEvent) ReceivePollVote(_ message.ID, vote ModelPollVote) (uint64, error) {
+	m.pollVote = vote
+	return m.getUUID(), nil
+}
+
+func (m *MockEvent) ClosePoll(_ message.ID, _ time.Time, reopen bool) (uint64, error) {
+	m.pollClosed = !reopen
+	return m.getUUID(), nil
+}
+
+func (m *MockEvent) UpdateChannelMetadata(_ *id.ID, metadata ChannelMetadata) {
+	m.metadata = metadata
+}
+
 func (m *MockEvent) DeleteMessage(message.ID) error {
 	m.eventReceive = eventReceive{}
 	return nil
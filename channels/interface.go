@@ -105,10 +105,12 @@ type Manager interface {
 	// SendGeneric is used to send a raw message over a channel. In general, it
 	// should be wrapped in a function that defines the wire protocol.
 	//
-	// If the final message, before being sent over the wire, is too long, this
-	// will return an error. Due to the underlying encoding using compression,
-	// it is not possible to define the largest payload that can be sent, but it
-	// will always be possible to send a payload of 802 bytes at minimum.
+	// If the final message, before being sent over the wire, is too long to
+	// fit in a single cMix message, it is split into multiple parts that are
+	// reassembled by the receivers. If it is too long to be split, this will
+	// return an error. Due to the underlying encoding using compression, it is
+	// not possible to define the largest payload that can be sent, but it will
+	// always be possible to send a payload of 802 bytes in a single message.
 	//
 	// The meaning of validUntil depends on the use case.
 	//
@@ -131,6 +133,9 @@ type Manager interface {
 	//
 	// The message will auto delete validUntil after the round it is sent in,
 	// lasting forever if ValidForever is used.
+	//
+	// Users mentioned in the text with MentionTag are pinged automatically, in
+	// addition to the users in pings.
 	SendMessage(channelID *id.ID, msg string, validUntil time.Duration,
 		params cmix.CMIXParams, pings []ed25519.PublicKey) (
 		message.ID, rounds.Round, ephemeral.Id, error)
@@ -146,6 +151,9 @@ type Manager interface {
 	//
 	// The message will auto delete validUntil after the round it is sent in,
 	// lasting forever if ValidForever is used.
+	//
+	// Users mentioned in the text with MentionTag are pinged automatically, in
+	// addition to the users in pings.
 	SendReply(channelID *id.ID, msg string, replyTo message.ID,
 		validUntil time.Duration, params cmix.CMIXParams,
 		pings []ed25519.PublicKey) (
@@ -187,6 +195,70 @@ type Manager interface {
 		pings []ed25519.PublicKey) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// SendPoll is used to send a poll over a channel. Members of the channel
+	// can vote on one of the options with SendPollVote. The poll must have at
+	// least two options; otherwise, InvalidPollErr is returned.
+	//
+	// The message will auto delete validUntil after the round it is sent in,
+	// lasting forever if ValidForever is used.
+	SendPoll(channelID *id.ID, question string, options []string,
+		validUntil time.Duration, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendPollVote is used to vote for an option on a poll. A user has at most
+	// one vote per poll; voting again replaces the previous vote. If the option
+	// does not exist in the poll, then InvalidPollErr is returned.
+	//
+	// Clients will drop the vote if they do not recognize the poll or if the
+	// poll was closed before the vote was sent.
+	SendPollVote(channelID *id.ID, pollMessageID message.ID, option uint32,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendTyping is used to signal to the channel that the user started or
+	// stopped typing. Typing indicators are sent at low priority, are only
+	// valid for TypingTimeout, and are never stored. They are delivered to
+	// other members of the channel via [UiCallbacks.UserTyping].
+	//
+	// While the user is typing, the UI should call SendTyping with typing set
+	// to true every TypingInterval. Sending more often returns
+	// TypingRateLimitErr, which can be safely ignored.
+	SendTyping(channelID *id.ID, typing bool, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	// ScheduleSend queues a raw message to be sent to the channel at sendAt.
+	// The queue is saved to storage so that messages are sent after a restart.
+	// When the time is reached, the message is sent via SendGeneric and
+	// tracked by the SendTracker. The result is reported via
+	// [UiCallbacks.ScheduledSendUpdate]. If the network is not healthy at
+	// sendAt, the message is sent once it becomes healthy.
+	//
+	// Returns ChannelDoesNotExistsErr if the channel has not been joined and
+	// InvalidScheduleTimeErr if sendAt is not in the future.
+	ScheduleSend(channelID *id.ID, messageType MessageType, msg []byte,
+		sendAt time.Time, validUntil time.Duration, params cmix.CMIXParams,
+		pings map[PingType][]ed25519.PublicKey) (ScheduledSend, error)
+
+	// ScheduleMessage queues a text message to be sent to the channel at
+	// sendAt. See SendMessage and ScheduleSend for details.
+	ScheduleMessage(channelID *id.ID, msg string, sendAt time.Time,
+		validUntil time.Duration, params cmix.CMIXParams,
+		pings []ed25519.PublicKey) (ScheduledSend, error)
+
+	// GetScheduledSends returns the messages queued to be sent to the channel,
+	// sorted by the time they are sent. If channelID is nil, then the messages
+	// queued for all channels are returned.
+	GetScheduledSends(channelID *id.ID) []ScheduledSend
+
+	// CancelScheduledSend removes the message from the queue so that it is
+	// never sent. Returns ScheduledSendNotFoundErr if the message does not
+	// exist or was already sent.
+	CancelScheduledSend(sendID uint64) error
+
+	// RescheduleSend changes the time the queued message is sent at. Returns
+	// ScheduledSendNotFoundErr if the message does not exist or was already
+	// sent and InvalidScheduleTimeErr if sendAt is not in the future.
+	RescheduleSend(sendID uint64, sendAt time.Time) error
+
 	////////////////////////////////////////////////////////////////////////////
 	// Admin Sending                                                          //
 	////////////////////////////////////////////////////////////////////////////
@@ -195,8 +267,9 @@ type Manager interface {
 	// with admin keys, identifying it as sent by the admin. In general, it
 	// should be wrapped in a function that defines the wire protocol.
 	//
-	// If the final message, before being sent over the wire, is too long, this
-	// will return an error. The message must be at most 510 bytes long.
+	// If the final message, before being sent over the wire, is longer than
+	// 510 bytes, it is split into multiple parts that are reassembled by the
+	// receivers. If it is too long to be split, this will return an error.
 	//
 	// If the user is not an admin of the channel (i.e. does not have a private
 	// key for the channel saved to storage), then the error NotAnAdminErr is
@@ -213,44 +286,133 @@ type Manager interface {
 		message.ID, rounds.Round, ephemeral.Id, error)
 
 	// DeleteMessage deletes the targeted message from storage. Users may delete
-	// their own messages but only the channel admin can delete other user's
-	// messages. If the user is not an admin of the channel or if they are not
-	// the sender of the targetMessage, then the error NotAnAdminErr is
-	// returned.
+	// their own messages but only the channel admin and moderators can delete
+	// other user's messages. If the user is not an admin or moderator of the
+	// channel or if they are not the sender of the targetMessage, then the
+	// error NotAnAdminErr is returned.
 	//
 	// Clients will drop the deletion if they do not recognize the target
 	// message.
+	//
+	// Deletions sent by a moderator are not replayed by the lease system, so
+	// users who join after the message has left the network will not see them.
 	DeleteMessage(channelID *id.ID, targetMessage message.ID,
 		params cmix.CMIXParams) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// EditMessage replaces the text of the targeted message. Users may edit
+	// their own messages but only the channel admin can edit other user's
+	// messages. If the user is not an admin of the channel or if they are not
+	// the sender of the targetMessage, then the error NotAnAdminErr is
+	// returned.
+	//
+	// The previous text of the message is kept by the event model as a
+	// revision. If the new text is longer than the maximum message length,
+	// then the error MessageTooLongErr is returned.
+	//
+	// Clients will drop the edit if they do not recognize the target message.
+	EditMessage(channelID *id.ID, targetMessage message.ID, newText string,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
 	// PinMessage pins the target message to the top of a channel view for all
-	// users in the specified channel. Only the channel admin can pin user
-	// messages; if the user is not an admin of the channel, then the error
-	// NotAnAdminErr is returned.
+	// users in the specified channel. Only the channel admin and moderators
+	// can pin user messages; if the user is not an admin or moderator of the
+	// channel, then the error NotAnAdminErr is returned.
 	//
 	// If undoAction is true, then the targeted message is unpinned. validUntil
 	// is the time the message will be pinned for; set this to ValidForever to
 	// pin indefinitely. validUntil is ignored if undoAction is true.
 	//
 	// Clients will drop the pin if they do not recognize the target message.
+	//
+	// Pins sent by a moderator are not replayed by the lease system, so users
+	// who join after the message has left the network will not see them.
 	PinMessage(channelID *id.ID, targetMessage message.ID,
 		undoAction bool, validUntil time.Duration, params cmix.CMIXParams) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// ClosePoll closes the poll so that votes sent after it are not counted.
+	// Only the channel admin can close a poll; if the user is not an admin of
+	// the channel, then the error NotAnAdminErr is returned.
+	//
+	// If undoAction is true, then the poll is reopened.
+	//
+	// Clients will drop the close if they do not recognize the poll.
+	ClosePoll(channelID *id.ID, pollMessageID message.ID, undoAction bool,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
 	// MuteUser is used to mute a user in a channel. Muting a user will cause
 	// all future messages from the user being dropped on reception. Muted users
-	// are also unable to send messages. Only the channel admin can mute a user;
-	// if the user is not an admin of the channel, then the error NotAnAdminErr
-	// is returned.
+	// are also unable to send messages. Only the channel admin and moderators
+	// can mute a user; if the user is not an admin or moderator of the
+	// channel, then the error NotAnAdminErr is returned.
 	//
 	// If undoAction is true, then the targeted user will be unmuted. validUntil
 	// is the time the user will be muted for; set this to ValidForever to mute
 	// the user indefinitely. validUntil is ignored if undoAction is true.
+	//
+	// Mutes sent by a moderator are not replayed by the lease system, so users
+	// who join after the message has left the network will not see them.
 	MuteUser(channelID *id.ID, mutedUser ed25519.PublicKey, undoAction bool,
 		validUntil time.Duration, params cmix.CMIXParams) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// AppointModerator appoints the user as a moderator of the channel.
+	// Moderators may delete any message, pin messages, and mute users, but
+	// they cannot appoint other moderators or change the channel admin key.
+	// Only the channel admin can appoint a moderator; if the user is not an
+	// admin of the channel, then the error NotAnAdminErr is returned.
+	//
+	// The appointment is sent as an admin message, so members of the channel
+	// verify that it comes from the channel admin before accepting it.
+	//
+	// If undoAction is true, then the moderator is revoked. validUntil is the
+	// time the user will be a moderator for; set this to ValidForever to
+	// appoint the user indefinitely. validUntil is ignored if undoAction is
+	// true.
+	AppointModerator(channelID *id.ID, moderator ed25519.PublicKey,
+		undoAction bool, validUntil time.Duration, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SetSlowMode sets the minimum time between two messages sent by the same
+	// user to the channel. Text messages, reactions, invitations, and polls
+	// sent within the interval are rejected with SlowModeErr by the sender and
+	// dropped by every other member. Moderators are not limited. An interval
+	// of zero turns slow mode off.
+	//
+	// Only the channel admin can set slow mode; if the user is not an admin of
+	// the channel, then the error NotAnAdminErr is returned.
+	SetSlowMode(channelID *id.ID, interval time.Duration,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// UpdateChannelMetadata replaces the description, icon, topic, and rules
+	// of the channel for every member. The name cannot be changed because the
+	// channel ID is derived from it. ChannelMetadata.Timestamp is ignored;
+	// members keep the newest update they receive. The icon may be at most
+	// MaxChannelIconSize bytes and the whole update must fit in one admin
+	// message. Members are notified via EventModel.UpdateChannelMetadata.
+	//
+	// Only the channel admin can update the metadata; if the user is not an
+	// admin of the channel, then the error NotAnAdminErr is returned.
+	UpdateChannelMetadata(channelID *id.ID, metadata ChannelMetadata,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// BanUser bans the user from the channel. The user is muted, every
+	// message they have sent to the channel is hidden in the event model, and
+	// the ban is recorded with the reason, which may be at most
+	// MaxBanReasonLength bytes. Messages can only be hidden if the EventModel
+	// implements MessageQuerier. Only the channel admin can ban a user; if
+	// the user is not an admin of the channel, then the error NotAnAdminErr
+	// is returned.
+	//
+	// If undoAction is true, then the user is unbanned and, unless they were
+	// muted before the ban, unmuted; messages hidden by the ban remain hidden. validUntil is the time the user will be
+	// banned for; set this to ValidForever to ban the user indefinitely.
+	// validUntil is ignored if undoAction is true.
+	BanUser(channelID *id.ID, bannedUser ed25519.PublicKey, reason string,
+		undoAction bool, validUntil time.Duration, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
 	////////////////////////////////////////////////////////////////////////////
 	// Other Channel Actions                                                  //
 	////////////////////////////////////////////////////////////////////////////
@@ -273,7 +435,9 @@ type Manager interface {
 	//
 	// There can only be one handler per message type; the error
 	// MessageTypeAlreadyRegistered will be returned on multiple registrations
-	// of the same type.
+	// of the same type. The exception are the Text and AdminText types, whose
+	// handlers are chained after the default handler and are called after the
+	// message is passed to the event model.
 	//
 	// To create a ReceiveMessageHandler, use NewReceiveMessageHandler.
 	RegisterReceiveHandler(
@@ -298,6 +462,39 @@ type Manager interface {
 	// an empty list is returned.
 	GetMutedUsers(channelID *id.ID) []ed25519.PublicKey
 
+	// GetChannelMetadata returns the newest metadata of the channel. If the
+	// metadata has never been updated, then the description the channel was
+	// created with is returned and ChannelMetadata.Timestamp is zero. Returns
+	// ChannelDoesNotExistsErr if the channel has not been joined.
+	GetChannelMetadata(channelID *id.ID) (ChannelMetadata, error)
+
+	// GetBannedUsers returns the list of banned users in the channel, with
+	// the reason and time of each ban. If there are no banned users or if the
+	// channel does not exist, an empty list is returned.
+	GetBannedUsers(channelID *id.ID) []BannedUser
+
+	// IsModerator returns true if the user with the given public key is a
+	// moderator of the channel.
+	IsModerator(channelID *id.ID, pubKey ed25519.PublicKey) bool
+
+	// GetModerators returns the list of the public keys for each moderator in
+	// the channel. If there are no moderators or if the channel does not
+	// exist, an empty list is returned.
+	GetModerators(channelID *id.ID) []ed25519.PublicKey
+
+	// GetSlowMode returns the slow mode interval of the channel. Returns zero
+	// if slow mode is off.
+	GetSlowMode(channelID *id.ID) time.Duration
+
+	// SetFloodFilter sets the local flood filter, which hides messages from a
+	// user that sends more than maxMessages messages to a channel within the
+	// window. The filter applies to every channel and is not shared with
+	// other members. Setting either to zero disables the filter.
+	SetFloodFilter(maxMessages int, window time.Duration) error
+
+	// GetFloodFilter returns the settings of the local flood filter.
+	GetFloodFilter() (maxMessages int, window time.Duration)
+
 	// GetNotificationLevel returns the notification level for the given channel.
 	GetNotificationLevel(channelID *id.ID) (NotificationLevel, error)
 
@@ -370,6 +567,52 @@ type Manager interface {
 	// private key is deleted, it cannot be recovered and the channel can never
 	// have another admin.
 	DeleteChannelAdminKey(channelID *id.ID) error
+
+	// RotateChannelAdminKey generates a new admin key for the channel and
+	// sends a key rotation message, signed with the current admin key, to the
+	// channel. Members verify the rotation against the current key and move to
+	// the returned channel, which only differs from the current channel in its
+	// admin key and ID. Use this when the admin key has leaked.
+	//
+	// The new key is saved to storage. Once the rotation is received, the
+	// current channel is left, the new channel is joined, and the current key
+	// is deleted. [UiCallbacks.AdminKeyRotated] is called for every member.
+	//
+	// The new channel keeps the secret of the current channel, so former
+	// members, including banned users, can still derive it and read its
+	// messages. Rotation does not remove anyone from the channel.
+	//
+	// Returns the error NotAnAdminErr if the user is not an admin of the
+	// channel.
+	RotateChannelAdminKey(channelID *id.ID, params cmix.CMIXParams) (
+		*cryptoBroadcast.Channel, message.ID, rounds.Round, ephemeral.Id,
+		error)
+
+	// TransferChannelOwnership rotates the admin key of the channel and sends
+	// the new key to the new owner in a direct message using the sender. The
+	// message is not stored by either side. Once the round it was sent in
+	// succeeds, the key is deleted from this user's storage, so the new owner
+	// becomes the only admin of the returned channel. The new owner's DM
+	// client passes the key to ReceiveChannelAdminKey.
+	//
+	// If the key fails to be delivered, then an error is returned and the key
+	// is kept, so this user remains the admin of the new channel.
+	//
+	// Returns the error NotAnAdminErr if the user is not an admin of the
+	// channel.
+	TransferChannelOwnership(channelID *id.ID, newOwner ed25519.PublicKey,
+		newOwnerToken uint32, sender AdminKeySender, params cmix.CMIXParams) (
+		*cryptoBroadcast.Channel, error)
+
+	// ReceiveChannelAdminKey imports the PEM-encoded admin key of a channel
+	// that was transferred to this user with TransferChannelOwnership. If the
+	// rotated channel has not been joined yet, the key is held in memory and
+	// imported once the rotation is received. It adheres to
+	// dm.AdminKeyReceiver.
+	//
+	// Returns the error WrongPrivateKeyErr if the key does not belong to the
+	// channel.
+	ReceiveChannelAdminKey(channelID *id.ID, adminKey []byte) error
 }
 
 // NotAnAdminErr is returned if the user is attempting to do an admin command
@@ -494,12 +737,19 @@ type UiCallbacks interface {
 	// [Manager.DeleteChannelAdminKey]).
 	AdminKeysUpdate(chID *id.ID, isAdmin bool)
 
+	// AdminKeyRotated is called when the admin key of a channel is rotated and
+	// the user is moved from the old channel to the new channel. The history
+	// of the old channel remains in the event model. (See
+	// [Manager.RotateChannelAdminKey]).
+	AdminKeyRotated(oldChannelID, newChannelID *id.ID)
+
 	// DmTokenUpdate is a callback be called when a channel's dm token state is
 	// changed
 	DmTokenUpdate(chID *id.ID, sendToken bool)
 
-	// ChannelUpdate is called any time the user joins or leaves a channel.
-	// deleted is false when joining and true when leaving.
+	// ChannelUpdate is called any time the user joins or leaves a channel or
+	// the metadata of the channel is updated. deleted is false when joining or
+	// updating and true when leaving.
 	ChannelUpdate(channelID *id.ID, deleted bool)
 
 	// MessageReceived is called any time a new message is received or an
@@ -512,4 +762,18 @@ type UiCallbacks interface {
 
 	// MessageDeleted is called every time a message is deleted.
 	MessageDeleted(messageID message.ID)
+
+	// UserTyping is called every time a user in the channel starts or stops
+	// typing. The UI should stop showing the user as typing if no new
+	// indicator is received from them within TypingTimeout. Typing indicators
+	// are never stored.
+	UserTyping(channelID *id.ID, pubKey ed25519.PublicKey, codeset uint8,
+		nickname string, typing bool)
+
+	// ScheduledSendUpdate is called when a scheduled send is sent or fails.
+	// err is nil and messageID is the ID of the sent message if the send
+	// succeeded. Scheduled sends to a channel that is left fail with
+	// ChannelDoesNotExistsErr. (See [Manager.ScheduleSend]).
+	ScheduledSendUpdate(sendID uint64, channelID *id.ID, messageID message.ID,
+		err error)
 }
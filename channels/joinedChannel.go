@@ -192,6 +192,32 @@ func (m *manager) removeChannelUnsafe(channelID *id.ID) error {
 		return err
 	}
 
+	err = m.moderators.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.slowMode.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+	m.flood.removeChannel(channelID)
+
+	err = m.bans.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.metadata.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.removeScheduledSends(channelID)
+	if err != nil {
+		return err
+	}
+
 	err = m.leases.deleteLeaseMessages(channelID)
 	if err != nil {
 		return err
@@ -299,6 +325,7 @@ func (m *manager) registerListeners(broadcastChan broadcast.Channel,
 		chID:      channel.ReceptionID,
 		trigger:   m.events.triggerEvent,
 		checkSent: m.st.MessageReceive,
+		slowMode:  m.events.checkSlowMode,
 	}).Listen, nil)
 	if err != nil {
 		return nil, err
@@ -601,3 +601,19 @@ func (m *mockEventModel) DeleteMessage(cryptoMessage.ID) error {
 func (m *mockEventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) {
 	panic("implement me")
 }
+
+func (m *mockEventModel) EditMessage(cryptoMessage.ID, MessageEdit) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ReceivePollVote(cryptoMessage.ID, ModelPollVote) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ClosePoll(cryptoMessage.ID, time.Time, bool) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) UpdateChannelMetadata(*id.ID, ChannelMetadata) {
+	panic("implement me")
+}
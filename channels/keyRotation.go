@@ -0,0 +1,328 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/crypto/rsa"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+)
+
+// rsaPubKeyHashLen is the length of the hash of an RSA public key, as returned
+// by [cryptoBroadcast.HashPubKey].
+const rsaPubKeyHashLen = blake2b.Size256
+
+// AdminKeySender sends a channel admin key to a user over a direct message. It
+// is used by [Manager.TransferChannelOwnership] and is implemented by the DM
+// client.
+type AdminKeySender interface {
+	// SendChannelAdminKey sends the PEM-encoded admin key of the channel to the
+	// partner. The key must not be stored by the sender; it is only delivered
+	// to the partner's [Manager.ReceiveChannelAdminKey].
+	SendChannelAdminKey(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		channelID *id.ID, adminKey []byte, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+}
+
+// RotateChannelAdminKey generates a new admin key for the channel and sends a
+// key rotation message, signed with the current admin key, to the channel.
+// Because the channel ID is derived from the admin public key, every member
+// moves to the returned channel, which only differs from the current channel
+// in its admin key and ID. The history of the current channel is kept in the
+// event model. The new key is saved to storage and the current key is deleted
+// once the rotation is received. If the rotation fails to send, then the new
+// key is deleted.
+//
+// The new channel keeps the secret of the current channel, so anyone who could
+// read the current channel, including users who have left or been banned, can
+// derive the new channel and read its messages. Rotation only replaces the
+// admin key; it does not remove members.
+//
+// Only the channel admin can rotate the key; if the user is not an admin of the
+// channel, then the error NotAnAdminErr is returned.
+func (m *manager) RotateChannelAdminKey(channelID *id.ID,
+	params cmix.CMIXParams) (*cryptoBroadcast.Channel, message.ID,
+	rounds.Round, ephemeral.Id, error) {
+	jww.INFO.Printf("[CH] RotateChannelAdminKey in channel %s", channelID)
+
+	if !m.IsChannelAdmin(channelID) {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{},
+			NotAnAdminErr
+	}
+
+	ch, err := m.getChannel(channelID)
+	if err != nil {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+	current := ch.broadcast.Get()
+
+	// Generate a new key of the same size so that admin messages keep the same
+	// layout
+	stream := m.rng.GetStream()
+	pk, err := rsa.GetScheme().Generate(stream, current.RsaPubKeyLength*8)
+	stream.Close()
+	if err != nil {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.Wrap(err, "failed to generate new admin key")
+	}
+
+	rsaPubKeyHash := cryptoBroadcast.HashPubKey(pk.Public())
+	rotated, err := rotateChannel(current, rsaPubKeyHash)
+	if err != nil {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	// Save the new key before sending so that the user is the admin of the
+	// new channel when the rotation is received
+	err = m.adminKeysManager.saveChannelPrivateKey(rotated.ReceptionID, pk)
+	if err != nil {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.Wrap(err, "failed to save new admin key")
+	}
+
+	rotationMsg := &CMIXChannelKeyRotation{
+		Version:       cmixChannelKeyRotationVersion,
+		RsaPubKeyHash: rsaPubKeyHash,
+	}
+	rotationMarshaled, err := proto.Marshal(rotationMsg)
+	if err != nil {
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, rotationMarshaled, SendKeyRotationTag)
+	params = params.SetDebugTag(tag)
+	jww.INFO.Printf("[CH] [%s] Rotate admin key of channel %s to %x; new "+
+		"channel %s", tag, channelID, rsaPubKeyHash, rotated.ReceptionID)
+
+	msgID, r, ephID, err := m.SendAdminGeneric(channelID, KeyRotation,
+		rotationMarshaled, ValidForever, false, params)
+	if err != nil {
+		// No member will move to the new channel, so its key is not needed
+		err2 := m.adminKeysManager.deleteChannelPrivateKey(rotated.ReceptionID)
+		if err2 != nil {
+			jww.ERROR.Printf("[CH] [%s] Failed to delete admin key of channel "+
+				"%s after failing to send rotation: %+v",
+				tag, rotated.ReceptionID, err2)
+		}
+		return nil, message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return rotated, msgID, r, ephID, nil
+}
+
+// TransferChannelOwnership rotates the admin key of the channel and sends the
+// new key to the new owner over a direct message. The message is never stored
+// by either side; the new owner's DM client hands the key to
+// [Manager.ReceiveChannelAdminKey], which imports it once the rotation is
+// received. Once the round the key was sent in succeeds, the key is deleted
+// from this user's storage so that the new owner is the only admin of the
+// returned channel.
+//
+// Only the channel admin can transfer ownership; if the user is not an admin of
+// the channel, then the error NotAnAdminErr is returned. If the key cannot be
+// sent or its round fails or times out, then an error is returned and the key
+// is not deleted, so the user remains the admin of the new channel and can
+// transfer it again.
+func (m *manager) TransferChannelOwnership(channelID *id.ID,
+	newOwner ed25519.PublicKey, newOwnerToken uint32, sender AdminKeySender,
+	params cmix.CMIXParams) (*cryptoBroadcast.Channel, error) {
+	jww.INFO.Printf("[CH] TransferChannelOwnership of channel %s to %x",
+		channelID, newOwner)
+
+	rotated, _, _, _, err := m.RotateChannelAdminKey(channelID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := m.adminKeysManager.loadChannelPrivateKey(rotated.ReceptionID)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to load admin key of channel %s", rotated.ReceptionID)
+	}
+
+	_, r, _, err := sender.SendChannelAdminKey(newOwner, newOwnerToken,
+		rotated.ReceptionID, pk.MarshalPem(), params)
+	if err != nil {
+		return nil, errors.WithMessagef(err,
+			"failed to send admin key of channel %s to new owner",
+			rotated.ReceptionID)
+	}
+
+	// Keep the key until it is delivered so that the channel is not left
+	// without an admin if the message is lost
+	if !m.waitForRound(r.ID) {
+		return nil, errors.Errorf("failed to deliver admin key of channel %s "+
+			"to new owner in round %d", rotated.ReceptionID, r.ID)
+	}
+
+	return rotated, m.DeleteChannelAdminKey(rotated.ReceptionID)
+}
+
+// waitForRound blocks until the result of the round is known. Returns true if
+// the round succeeded and false if it failed or timed out.
+func (m *manager) waitForRound(roundID id.Round) bool {
+	succeeded := make(chan bool, 1)
+	m.net.GetRoundResults(getRoundResultsTimeout, func(allRoundsSucceeded,
+		timedOut bool, _ map[id.Round]cmix.RoundResult) {
+		succeeded <- allRoundsSucceeded && !timedOut
+	}, roundID)
+	return <-succeeded
+}
+
+// ReceiveChannelAdminKey imports the PEM-encoded admin key of a channel that was
+// transferred to this user with [Manager.TransferChannelOwnership]. It is
+// called by the DM client when the key is received.
+//
+// The key is usually received before the rotation that moves members to the
+// channel. In that case, it is held in memory and imported once the rotated
+// channel is joined.
+//
+// Returns the error WrongPrivateKeyErr if the key does not belong to the
+// channel.
+func (m *manager) ReceiveChannelAdminKey(
+	channelID *id.ID, adminKey []byte) error {
+	jww.INFO.Printf("[CH] ReceiveChannelAdminKey for channel %s", channelID)
+	pk, err := rsa.GetScheme().UnmarshalPrivateKeyPEM(adminKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse admin key")
+	}
+
+	m.adminKeysManager.pendingMux.Lock()
+	defer m.adminKeysManager.pendingMux.Unlock()
+
+	err = m.importChannelAdminKey(channelID, pk)
+	if errors.Is(err, ChannelDoesNotExistsErr) {
+		jww.INFO.Printf("[CH] Holding admin key for channel %s until the "+
+			"rotation is received", channelID)
+		m.adminKeysManager.pending[*channelID] = pk
+		return nil
+	}
+	return err
+}
+
+// importPendingAdminKey imports the admin key received for the channel before
+// it was joined, if there is one.
+func (m *manager) importPendingAdminKey(channelID *id.ID) {
+	m.adminKeysManager.pendingMux.Lock()
+	defer m.adminKeysManager.pendingMux.Unlock()
+
+	pk, exists := m.adminKeysManager.pending[*channelID]
+	if !exists {
+		return
+	}
+	delete(m.adminKeysManager.pending, *channelID)
+
+	if err := m.importChannelAdminKey(channelID, pk); err != nil {
+		jww.ERROR.Printf("[CH] Failed to import admin key received for "+
+			"channel %s: %+v", channelID, err)
+	}
+}
+
+// rotateChannelKey moves the channel to the admin key with the given hash. The
+// current channel is left and the rotated channel is joined with the same
+// settings. The current admin key, if the user has it, is deleted. Returns the
+// ID of the rotated channel.
+//
+// The rotation must have already been verified against the current admin key.
+func (m *manager) rotateChannelKey(
+	channelID *id.ID, rsaPubKeyHash []byte) (*id.ID, error) {
+	m.mux.Lock()
+	jc, err := m.getChannelUnsafe(channelID)
+	if err != nil {
+		m.mux.Unlock()
+		return nil, err
+	}
+
+	rotated, err := rotateChannel(jc.broadcast.Get(), rsaPubKeyHash)
+	if err != nil {
+		m.mux.Unlock()
+		return nil, err
+	}
+
+	if err = m.removeChannelUnsafe(channelID); err != nil {
+		m.mux.Unlock()
+		return nil, errors.WithMessage(err, "failed to leave current channel")
+	}
+
+	// The rotated channel may have already been joined on another device
+	if _, exists := m.channels[*rotated.ReceptionID]; !exists {
+		var rotatedJc *joinedChannel
+		rotatedJc, err = m.addChannelInternal(rotated, jc.dmEnabled)
+		if err == nil {
+			err = m.saveChannel(rotatedJc)
+		}
+	}
+	m.mux.Unlock()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to join rotated channel")
+	}
+
+	m.notifications.removeChannel(channelID)
+	m.notifications.addChannel(rotated.ReceptionID)
+	m.events.model.JoinChannel(rotated)
+
+	// Import the admin key if ownership of the channel was transferred to
+	// this user
+	m.importPendingAdminKey(rotated.ReceptionID)
+
+	// The current admin key can no longer be used
+	if m.IsChannelAdmin(channelID) {
+		if err = m.DeleteChannelAdminKey(channelID); err != nil {
+			jww.ERROR.Printf("[CH] Failed to delete admin key of rotated "+
+				"channel %s: %+v", channelID, err)
+		}
+	}
+
+	jww.INFO.Printf("[CH] Rotated admin key of channel %s; moved to channel %s",
+		channelID, rotated.ReceptionID)
+
+	return rotated.ReceptionID, nil
+}
+
+// rotateChannel returns a copy of the channel with the admin public key hash
+// replaced and the channel ID derived from the new hash. The new key must be
+// the same size as the current key.
+func rotateChannel(channel *cryptoBroadcast.Channel,
+	rsaPubKeyHash []byte) (*cryptoBroadcast.Channel, error) {
+	if len(rsaPubKeyHash) != rsaPubKeyHashLen {
+		return nil, errors.Errorf("admin key hash must be %d bytes; "+
+			"received %d bytes", rsaPubKeyHashLen, len(rsaPubKeyHash))
+	}
+
+	channelID, err := cryptoBroadcast.NewChannelID(channel.Name,
+		channel.Description, channel.Level, channel.Created, channel.Salt,
+		rsaPubKeyHash, cryptoBroadcast.HashSecret(channel.Secret))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive rotated channel ID")
+	}
+
+	return &cryptoBroadcast.Channel{
+		ReceptionID:     channelID,
+		Name:            channel.Name,
+		Description:     channel.Description,
+		Level:           channel.Level,
+		Created:         channel.Created,
+		Salt:            channel.Salt,
+		RsaPubKeyHash:   rsaPubKeyHash,
+		RsaPubKeyLength: channel.RsaPubKeyLength,
+		RSASubPayloads:  channel.RSASubPayloads,
+		Secret:          channel.Secret,
+	}, nil
+}
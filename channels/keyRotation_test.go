@@ -0,0 +1,398 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/broadcast"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/crypto/rsa"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+)
+
+// Tests that rotateChannel returns a valid channel with a new ID and admin key
+// hash and all other fields unchanged.
+func Test_rotateChannel(t *testing.T) {
+	m := newPrivKeyTestManager(t)
+	ch, _, err := m.generateChannel("name", "desc", cryptoBroadcast.Public, 512)
+	require.NoError(t, err)
+
+	rsaPubKeyHash := bytes.Repeat([]byte{1}, rsaPubKeyHashLen)
+	rotated, err := rotateChannel(ch, rsaPubKeyHash)
+	require.NoError(t, err)
+
+	require.True(t, rotated.Verify(), "Rotated channel does not verify.")
+	require.False(t, ch.ReceptionID.Cmp(rotated.ReceptionID),
+		"Rotated channel has the same ID as the current channel.")
+	require.Equal(t, rsaPubKeyHash, rotated.RsaPubKeyHash)
+
+	expected := *ch
+	expected.ReceptionID = rotated.ReceptionID
+	expected.RsaPubKeyHash = rotated.RsaPubKeyHash
+	require.Equal(t, expected, *rotated)
+}
+
+// Error path: Tests that rotateChannel returns an error for an admin key hash
+// of the wrong length.
+func Test_rotateChannel_InvalidHashLength(t *testing.T) {
+	m := newPrivKeyTestManager(t)
+	ch, _, err := m.generateChannel("name", "desc", cryptoBroadcast.Public, 512)
+	require.NoError(t, err)
+
+	_, err = rotateChannel(ch, []byte("short"))
+	require.Error(t, err)
+}
+
+// newKeyRotationTestManager returns a manager, with a send tracker and mock
+// broadcast channel, that is the admin of a new channel.
+func newKeyRotationTestManager(t *testing.T) (
+	*manager, *cryptoBroadcast.Channel, *mockBroadcastChannel) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+
+	m := &manager{
+		channels: make(map[id.ID]*joinedChannel),
+		local:    kv,
+		net:      &mockRoundResultsClient{},
+		rng:      crng,
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, dummyAdminKeyUpdate),
+	}
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	require.NoError(t, err)
+	mbc := &mockBroadcastChannel{crypto: ch}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	return m, ch, mbc
+}
+
+// mockRoundResultsClient is a mockBroadcastClient that reports every round as
+// successful, or as failed if failed is set.
+type mockRoundResultsClient struct {
+	mockBroadcastClient
+	failed bool
+}
+
+func (m *mockRoundResultsClient) GetRoundResults(_ time.Duration,
+	roundCallback cmix.RoundEventCallback, _ ...id.Round) {
+	go roundCallback(!m.failed, false, nil)
+}
+
+// failingBroadcastChannel is a mockBroadcastChannel whose admin messages fail
+// to send.
+type failingBroadcastChannel struct {
+	*mockBroadcastChannel
+}
+
+func (failingBroadcastChannel) BroadcastRSAToPublicMultipartWithAssembler(
+	rsa.PrivateKey, broadcast.Assembler, []string, [2]byte, cmix.CMIXParams) (
+	[]byte, rounds.Round, ephemeral.Id, error) {
+	return nil, rounds.Round{}, ephemeral.Id{}, errors.New("send error")
+}
+
+// Tests that manager.RotateChannelAdminKey saves a new admin key for the
+// rotated channel and sends a KeyRotation message with the hash of the new key.
+func Test_manager_RotateChannelAdminKey(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+
+	rotated, _, _, _, err :=
+		m.RotateChannelAdminKey(ch.ReceptionID, cmix.CMIXParams{})
+	require.NoError(t, err)
+
+	require.True(t, rotated.Verify(), "Rotated channel does not verify.")
+	require.True(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"User not admin of rotated channel %s.", rotated.ReceptionID)
+	require.True(t, m.IsChannelAdmin(ch.ReceptionID),
+		"Current admin key deleted before the rotation was received.")
+
+	pk, err := m.adminKeysManager.loadChannelPrivateKey(rotated.ReceptionID)
+	require.NoError(t, err)
+	require.True(t, rotated.IsPublicKey(pk.Public()),
+		"Saved key does not match the rotated channel.")
+
+	chMsg := &ChannelMessage{}
+	require.NoError(t, proto.Unmarshal(mbc.payload, chMsg))
+
+	rotationMsg := &CMIXChannelKeyRotation{}
+	require.NoError(t, proto.Unmarshal(chMsg.Payload, rotationMsg))
+	require.Equal(t, rotated.RsaPubKeyHash, rotationMsg.RsaPubKeyHash)
+}
+
+// Error path: Tests that manager.RotateChannelAdminKey returns NotAnAdminErr
+// when the user is not the admin of the channel.
+func Test_manager_RotateChannelAdminKey_NotAdmin(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	require.NoError(t, m.DeleteChannelAdminKey(ch.ReceptionID))
+
+	_, _, _, _, err := m.RotateChannelAdminKey(ch.ReceptionID, cmix.CMIXParams{})
+	require.ErrorIs(t, err, NotAnAdminErr)
+	require.False(t, mbc.hasRun, "Message sent by non-admin.")
+}
+
+// Error path: Tests that manager.RotateChannelAdminKey deletes the new admin
+// key when the rotation fails to send.
+func Test_manager_RotateChannelAdminKey_SendError(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	m.channels[*ch.ReceptionID] =
+		&joinedChannel{broadcast: failingBroadcastChannel{mbc}}
+
+	_, _, _, _, err := m.RotateChannelAdminKey(ch.ReceptionID, cmix.CMIXParams{})
+	require.Error(t, err)
+
+	keys, err := m.adminKeysManager.remote.GetMap(
+		adminKeysMapName, adminKeysMapVersion)
+	require.NoError(t, err)
+	require.Len(t, keys, 1, "New admin key not deleted after failed send.")
+	require.True(t, m.IsChannelAdmin(ch.ReceptionID),
+		"Current admin key deleted after failed send.")
+}
+
+// mockAdminKeySender records the admin key sent with SendChannelAdminKey.
+type mockAdminKeySender struct {
+	partnerPubKey ed25519.PublicKey
+	partnerToken  uint32
+	channelID     *id.ID
+	adminKey      []byte
+	err           error
+}
+
+func (m *mockAdminKeySender) SendChannelAdminKey(
+	partnerPubKey ed25519.PublicKey, partnerToken uint32, channelID *id.ID,
+	adminKey []byte, _ cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	m.partnerPubKey = partnerPubKey
+	m.partnerToken = partnerToken
+	m.channelID = channelID
+	m.adminKey = adminKey
+	return message.ID{}, rounds.Round{}, ephemeral.Id{}, m.err
+}
+
+// Tests that manager.TransferChannelOwnership sends the admin key of the
+// rotated channel to the new owner and deletes it from storage.
+func Test_manager_TransferChannelOwnership(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+	newOwner, err := cryptoChannel.GenerateIdentity(rand.New(rand.NewSource(5)))
+	require.NoError(t, err)
+	sender := &mockAdminKeySender{}
+
+	rotated, err := m.TransferChannelOwnership(
+		ch.ReceptionID, newOwner.PubKey, 42, sender, cmix.CMIXParams{})
+	require.NoError(t, err)
+
+	require.Equal(t, newOwner.PubKey, sender.partnerPubKey)
+	require.Equal(t, uint32(42), sender.partnerToken)
+	require.Equal(t, rotated.ReceptionID, sender.channelID)
+
+	pk, err := rsa.GetScheme().UnmarshalPrivateKeyPEM(sender.adminKey)
+	require.NoError(t, err)
+	require.True(t, rotated.IsPublicKey(pk.Public()),
+		"Sent key does not match the rotated channel.")
+
+	require.False(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"User still admin of channel %s after transferring ownership.",
+		rotated.ReceptionID)
+}
+
+// Error path: Tests that manager.TransferChannelOwnership keeps the admin key
+// of the rotated channel when it fails to send it.
+func Test_manager_TransferChannelOwnership_SendError(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	sender := &mockAdminKeySender{err: errors.New("send error")}
+
+	_, err := m.TransferChannelOwnership(
+		ch.ReceptionID, ed25519.PublicKey{}, 0, sender, cmix.CMIXParams{})
+	require.Error(t, err)
+
+	chMsg := &ChannelMessage{}
+	require.NoError(t, proto.Unmarshal(mbc.payload, chMsg))
+	rotationMsg := &CMIXChannelKeyRotation{}
+	require.NoError(t, proto.Unmarshal(chMsg.Payload, rotationMsg))
+	rotated, err := rotateChannel(ch, rotationMsg.RsaPubKeyHash)
+	require.NoError(t, err)
+	require.True(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"Admin key deleted after failing to send it.")
+}
+
+// Error path: Tests that manager.TransferChannelOwnership keeps the admin key
+// of the rotated channel when the round it was sent in fails.
+func Test_manager_TransferChannelOwnership_RoundFailed(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+	m.net.(*mockRoundResultsClient).failed = true
+	sender := &mockAdminKeySender{}
+
+	_, err := m.TransferChannelOwnership(
+		ch.ReceptionID, ed25519.PublicKey{}, 0, sender, cmix.CMIXParams{})
+	require.Error(t, err)
+	require.True(t, m.IsChannelAdmin(sender.channelID),
+		"Admin key deleted before it was delivered.")
+}
+
+// Tests that manager.ReceiveChannelAdminKey imports the admin key of a channel
+// that has already been joined.
+func Test_manager_ReceiveChannelAdminKey(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+	pk, rotated := newRotatedTestChannel(t, m, ch)
+	m.channels[*rotated.ReceptionID] =
+		&joinedChannel{broadcast: &mockBroadcastChannel{crypto: rotated}}
+
+	err := m.ReceiveChannelAdminKey(rotated.ReceptionID, pk.MarshalPem())
+	require.NoError(t, err)
+	require.True(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"Admin key of channel %s not imported.", rotated.ReceptionID)
+	require.Empty(t, m.adminKeysManager.pending)
+}
+
+// Tests that manager.ReceiveChannelAdminKey holds the admin key of a channel
+// that has not been joined and that manager.rotateChannelKey imports it once
+// the rotated channel is joined.
+func Test_manager_ReceiveChannelAdminKey_BeforeRotation(t *testing.T) {
+	pi, err := cryptoChannel.GenerateIdentity(rand.New(rand.NewSource(64)))
+	require.NoError(t, err)
+	kv := collective.TestingKV(t, ekv.MakeMemstore(), collective.StandardPrefexs, nil)
+	mFace, err := NewManagerBuilder(pi, kv, new(mockBroadcastClient),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG),
+		mockEventModelBuilder, nil, mockAddServiceFn, newMockNM(),
+		&dummyUICallback{})
+	require.NoError(t, err)
+	m := mFace.(*manager)
+
+	ch, _, err := m.generateChannel("name", "desc", cryptoBroadcast.Public, 512)
+	require.NoError(t, err)
+	require.NoError(t, m.addChannel(ch, true))
+	pk, rotated := newRotatedTestChannel(t, m, ch)
+
+	err = m.ReceiveChannelAdminKey(rotated.ReceptionID, pk.MarshalPem())
+	require.NoError(t, err)
+	require.False(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"Admin key imported before the rotation was received.")
+	require.Len(t, m.adminKeysManager.pending, 1)
+
+	rotatedID, err := m.rotateChannelKey(ch.ReceptionID, rotated.RsaPubKeyHash)
+	require.NoError(t, err)
+	require.Equal(t, rotated.ReceptionID, rotatedID)
+	require.True(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"Admin key of channel %s not imported after the rotation.",
+		rotated.ReceptionID)
+	require.Empty(t, m.adminKeysManager.pending)
+}
+
+// Error path: Tests that manager.ReceiveChannelAdminKey returns
+// WrongPrivateKeyErr for a key that does not belong to the channel.
+func Test_manager_ReceiveChannelAdminKey_WrongKey(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+	_, rotated := newRotatedTestChannel(t, m, ch)
+	m.channels[*rotated.ReceptionID] =
+		&joinedChannel{broadcast: &mockBroadcastChannel{crypto: rotated}}
+	wrongPk, _ := newRotatedTestChannel(t, m, ch)
+
+	err := m.ReceiveChannelAdminKey(rotated.ReceptionID, wrongPk.MarshalPem())
+	require.ErrorIs(t, err, WrongPrivateKeyErr)
+	require.False(t, m.IsChannelAdmin(rotated.ReceptionID),
+		"Wrong admin key imported.")
+}
+
+// Error path: Tests that manager.ReceiveChannelAdminKey returns an error for
+// an admin key that is not a PEM-encoded RSA key.
+func Test_manager_ReceiveChannelAdminKey_InvalidKey(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+
+	err := m.ReceiveChannelAdminKey(ch.ReceptionID, []byte("invalid"))
+	require.Error(t, err)
+	require.Empty(t, m.adminKeysManager.pending)
+}
+
+// newRotatedTestChannel generates a new admin key and returns it with the
+// channel rotated to it.
+func newRotatedTestChannel(t *testing.T, m *manager,
+	ch *cryptoBroadcast.Channel) (rsa.PrivateKey, *cryptoBroadcast.Channel) {
+	stream := m.rng.GetStream()
+	defer stream.Close()
+	pk, err := rsa.GetScheme().Generate(stream, 1024)
+	require.NoError(t, err)
+
+	rotated, err := rotateChannel(ch, cryptoBroadcast.HashPubKey(pk.Public()))
+	require.NoError(t, err)
+	return pk, rotated
+}
+
+// Tests that manager.rotateChannelKey leaves the current channel, joins the
+// rotated channel with the same settings, and deletes the current admin key.
+func Test_manager_rotateChannelKey(t *testing.T) {
+	pi, err := cryptoChannel.GenerateIdentity(rand.New(rand.NewSource(64)))
+	require.NoError(t, err)
+	kv := collective.TestingKV(t, ekv.MakeMemstore(), collective.StandardPrefexs, nil)
+	mFace, err := NewManagerBuilder(pi, kv, new(mockBroadcastClient),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG),
+		mockEventModelBuilder, nil, mockAddServiceFn, newMockNM(),
+		&dummyUICallback{})
+	require.NoError(t, err)
+	m := mFace.(*manager)
+
+	ch, _, err := m.generateChannel("name", "desc", cryptoBroadcast.Public, 512)
+	require.NoError(t, err)
+	require.NoError(t, m.addChannel(ch, true))
+
+	rsaPubKeyHash := bytes.Repeat([]byte{1}, rsaPubKeyHashLen)
+	rotatedID, err := m.rotateChannelKey(ch.ReceptionID, rsaPubKeyHash)
+	require.NoError(t, err)
+
+	_, exists := m.channels[*ch.ReceptionID]
+	require.False(t, exists, "Current channel not left.")
+	jc, exists := m.channels[*rotatedID]
+	require.True(t, exists, "Rotated channel not joined.")
+	require.True(t, jc.dmEnabled, "DMs not enabled in rotated channel.")
+	require.Equal(t, rsaPubKeyHash, jc.broadcast.Get().RsaPubKeyHash)
+	require.False(t, m.IsChannelAdmin(ch.ReceptionID),
+		"Admin key of current channel not deleted.")
+}
+
+// Error path: Tests that manager.rotateChannelKey returns an error for a
+// channel that has not been joined.
+func Test_manager_rotateChannelKey_NoChannel(t *testing.T) {
+	m := newPrivKeyTestManager(t)
+	_, err := m.rotateChannelKey(id.NewIdFromString("channel", id.User, t),
+		bytes.Repeat([]byte{1}, rsaPubKeyHashLen))
+	require.ErrorIs(t, err, ChannelDoesNotExistsErr)
+}
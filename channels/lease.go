@@ -301,13 +301,13 @@ func (all *ActionLeaseList) updateLeasesThread(stop *stoppable.Single) {
 				jww.DEBUG.Printf("[CH] Lease expired at %s; undoing %s for %+v",
 					lm.LeaseEnd, lm.Action, lm)
 
-				// Trigger undo
+				// Trigger undo as the admin
 				go func(lm *leaseMessage, cm *CommandMessage) {
 					_, err = all.triggerFn(lm.ChannelID, cm.MessageID,
 						lm.Action, leaseNickname, lm.Payload,
-						cm.EncryptedPayload, cm.Timestamp, lm.OriginatingTimestamp,
-						lm.Lease, cm.OriginatingRound, cm.Round, Delivered,
-						cm.FromAdmin)
+						cm.EncryptedPayload, nil, cm.Timestamp,
+						lm.OriginatingTimestamp, lm.Lease, cm.OriginatingRound,
+						cm.Round, Delivered, cm.FromAdmin)
 					if err != nil {
 						jww.ERROR.Printf("[CH] Failed to trigger %s: %+v",
 							lm.Action, err)
@@ -322,8 +322,11 @@ func (all *ActionLeaseList) updateLeasesThread(stop *stoppable.Single) {
 					"[CH] Lease triggered at %s; replaying %s for %+v",
 					lm.LeaseTrigger, lm.Action, lm)
 
-				// Trigger replay
-				go all.replayFn(lm.ChannelID, cm.EncryptedPayload)
+				// Trigger replay. Actions sent by a moderator have no admin
+				// payload to replay.
+				if len(cm.EncryptedPayload) > 0 {
+					go all.replayFn(lm.ChannelID, cm.EncryptedPayload)
+				}
 			}
 		}
 
@@ -10,6 +10,7 @@ package channels
 import (
 	"bytes"
 	"container/list"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -171,7 +172,7 @@ func TestActionLeaseList_updateLeasesThread(t *testing.T) {
 	prng := rand.New(rand.NewSource(32))
 	triggerChan := make(chan *leaseMessage, 3)
 	trigger := func(channelID *id.ID, _ message.ID, messageType MessageType,
-		nickname string, payload, _ []byte, timestamp,
+		nickname string, payload, _ []byte, _ ed25519.PublicKey, timestamp,
 		originatingTimestamp time.Time, lease time.Duration, _ id.Round,
 		_ rounds.Round, _ SentStatus, _ bool) (uint64, error) {
 		triggerChan <- &leaseMessage{
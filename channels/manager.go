@@ -27,6 +27,9 @@ import (
 	"gitlab.com/elixxir/client/v4/collective"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
 	clientNotif "gitlab.com/elixxir/client/v4/notifications"
+	"gitlab.com/elixxir/client/v4/scheduler"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/client/v4/typingIndicator"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	cryptoChannel "gitlab.com/elixxir/crypto/channel"
 	"gitlab.com/elixxir/crypto/fastRNG"
@@ -36,7 +39,13 @@ import (
 	"gitlab.com/xx_network/primitives/id/ephemeral"
 )
 
-const storageTagFormat = "channelManagerStorageTag-%s"
+const (
+	storageTagFormat = "channelManagerStorageTag-%s"
+
+	// managerStoppable is the name of the stoppable that holds the processes
+	// started by the manager.
+	managerStoppable = "ChannelsManager"
+)
 
 type manager struct {
 	// Sender Identity
@@ -75,6 +84,16 @@ type manager struct {
 	*notifications
 
 	dmCallback func(chID *id.ID, sendToken bool)
+
+	// Limits how often typing indicators are sent
+	typing *typingIndicator.Limiter
+
+	// Sends messages queued to be sent at a future time
+	scheduler *scheduler.Scheduler
+
+	// Reports the result of each scheduled send
+	scheduledSendUpdate func(
+		sendID uint64, channelID *id.ID, messageID cryptoMessage.ID, err error)
 }
 
 // Client contains the methods from [cmix.Client] that are required by the
@@ -169,15 +188,15 @@ func NewManager(identity cryptoChannel.PrivateIdentity, kv versioned.KV,
 		nm, uiCallbacks)
 	m.dmTokens = make(map[id.ID]uint32)
 
-	return m, addService(m.leases.StartProcesses)
+	return m, addService(m.startProcesses)
 }
 
 // LoadManager restores a channel Manager from disk stored at the given storage
 // tag.
 func LoadManager(storageTag string, kv versioned.KV, net Client,
 	rng *fastRNG.StreamGenerator, model EventModel,
-	extensions []ExtensionBuilder, nm NotificationsManager,
-	uiCallbacks UiCallbacks) (Manager, error) {
+	extensions []ExtensionBuilder, addService AddServiceFn,
+	nm NotificationsManager, uiCallbacks UiCallbacks) (Manager, error) {
 	jww.INFO.Printf("[CH] LoadManager for tag %s", storageTag)
 
 	// Prefix the local with the username so multiple can be run
@@ -200,21 +219,22 @@ func LoadManager(storageTag string, kv versioned.KV, net Client,
 	m := setupManager(identity, local, remote, net, rng, model, extensions, nm,
 		uiCallbacks)
 
-	return m, nil
+	return m, addService(m.startProcesses)
 }
 
 // LoadManagerBuilder restores a channel Manager from disk stored at the given storage
 // tag.
 func LoadManagerBuilder(storageTag string, kv versioned.KV, net Client,
 	rng *fastRNG.StreamGenerator, modelBuilder EventModelBuilder,
-	extensions []ExtensionBuilder, nm NotificationsManager,
-	uiCallbacks UiCallbacks) (Manager, error) {
+	extensions []ExtensionBuilder, addService AddServiceFn,
+	nm NotificationsManager, uiCallbacks UiCallbacks) (Manager, error) {
 	model, err := modelBuilder(storageTag)
 	if err != nil {
 		return nil, errors.Errorf("Failed to build event model: %+v", err)
 	}
 
-	return LoadManager(storageTag, kv, net, rng, model, extensions, nm, uiCallbacks)
+	return LoadManager(storageTag, kv, net, rng, model, extensions, addService,
+		nm, uiCallbacks)
 }
 
 func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versioned.KV,
@@ -238,6 +258,26 @@ func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versione
 		adminKeysManager: newAdminKeysManager(remote, uiCallbacks.AdminKeysUpdate),
 		broadcastMaker:   broadcast.NewBroadcastChannel,
 		dmCallback:       uiCallbacks.DmTokenUpdate,
+		typing:           typingIndicator.NewLimiter(TypingInterval),
+	}
+
+	// Deliver typing indicators from other users to the UI
+	m.events.userTyping = func(channelID *id.ID, pubKey ed25519.PublicKey,
+		codeset uint8, nickname string, typing bool) {
+		if !pubKey.Equal(m.me.PubKey) {
+			uiCallbacks.UserTyping(channelID, pubKey, codeset, nickname, typing)
+		}
+	}
+
+	// Move to the rotated channel when the admin key is rotated
+	m.events.keyRotation = func(channelID *id.ID, rsaPubKeyHash []byte) {
+		rotatedID, err := m.rotateChannelKey(channelID, rsaPubKeyHash)
+		if err != nil {
+			jww.ERROR.Printf("[CH] Failed to rotate admin key of channel "+
+				"%s: %+v", channelID, err)
+			return
+		}
+		uiCallbacks.AdminKeyRotated(channelID, rotatedID)
 	}
 
 	m.events.leases.RegisterReplayFn(m.adminReplayHandler)
@@ -276,9 +316,36 @@ func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versione
 	m.notifications = newNotifications(
 		identity.PubKey, uiCallbacks.NotificationUpdate, m, extensions, nm)
 
+	m.scheduledSendUpdate = uiCallbacks.ScheduledSendUpdate
+	var err error
+	m.scheduler, err = scheduler.NewOrLoad(
+		local, scheduledSendsStoreKey, net, m.sendScheduled)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to load scheduled sends: %+v", err)
+	}
+
 	return m
 }
 
+// startProcesses starts the action lease thread and the scheduled sends. This
+// function adheres to the [xxdk.Service] type.
+func (m *manager) startProcesses() (stoppable.Stoppable, error) {
+	leasesStop, err := m.leases.StartProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	schedulerStop, err := m.scheduler.StartProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	multi := stoppable.NewMulti(managerStoppable)
+	multi.Add(leasesStop)
+	multi.Add(schedulerStop)
+	return multi, nil
+}
+
 // adminReplayHandler registers a ReplayActionFunc with the lease system.
 func (m *manager) adminReplayHandler(channelID *id.ID, encryptedPayload []byte) {
 	messageID, r, _, err := m.replayAdminMessage(
@@ -523,6 +590,67 @@ func (m *manager) GetMutedUsers(channelID *id.ID) []ed25519.PublicKey {
 	return m.mutedUsers.getMutedUsers(channelID)
 }
 
+// GetChannelMetadata returns the newest metadata of the channel. If the
+// metadata has never been updated, then the description the channel was
+// created with is returned and ChannelMetadata.Timestamp is zero. Returns
+// ChannelDoesNotExistsErr if the channel has not been joined.
+func (m *manager) GetChannelMetadata(channelID *id.ID) (ChannelMetadata, error) {
+	jww.INFO.Printf("[CH] GetChannelMetadata in channel %s", channelID)
+	metadata, exists := m.metadata.get(channelID)
+	if exists {
+		return metadata, nil
+	}
+
+	jc, err := m.getChannel(channelID)
+	if err != nil {
+		return ChannelMetadata{}, err
+	}
+	return ChannelMetadata{Description: jc.broadcast.Get().Description}, nil
+}
+
+// GetBannedUsers returns the list of banned users in the channel, sorted by
+// public key. If there are no banned users or if the channel does not exist,
+// an empty list is returned.
+func (m *manager) GetBannedUsers(channelID *id.ID) []BannedUser {
+	jww.INFO.Printf("[CH] GetBannedUsers in channel %s", channelID)
+	return m.bans.getBannedUsers(channelID)
+}
+
+// IsModerator returns true if the user with the given public key is a
+// moderator of the channel.
+func (m *manager) IsModerator(
+	channelID *id.ID, pubKey ed25519.PublicKey) bool {
+	return m.moderators.isModerator(channelID, pubKey)
+}
+
+// GetModerators returns the list of the public keys for each moderator in the
+// channel. If there are no moderators or if the channel does not exist, an
+// empty list is returned.
+func (m *manager) GetModerators(channelID *id.ID) []ed25519.PublicKey {
+	jww.INFO.Printf("[CH] GetModerators in channel %s", channelID)
+	return m.moderators.getModerators(channelID)
+}
+
+// GetSlowMode returns the slow mode interval of the channel. Returns zero if
+// slow mode is off.
+func (m *manager) GetSlowMode(channelID *id.ID) time.Duration {
+	return m.slowMode.getInterval(channelID)
+}
+
+// SetFloodFilter sets the local flood filter, which hides messages from a user
+// that sends more than maxMessages messages to a channel within the window.
+// Setting either to zero disables the filter.
+func (m *manager) SetFloodFilter(maxMessages int, window time.Duration) error {
+	jww.INFO.Printf("[CH] SetFloodFilter to %d messages in %s",
+		maxMessages, window)
+	return m.flood.set(maxMessages, window)
+}
+
+// GetFloodFilter returns the settings of the local flood filter.
+func (m *manager) GetFloodFilter() (maxMessages int, window time.Duration) {
+	return m.flood.get()
+}
+
 // dummyUICallback is an implementation of UI callbacks that does nothing
 // it is used for tests and when nothing is passed in for UI callbacks
 type dummyUICallback struct{}
@@ -531,6 +659,10 @@ func (duiCB *dummyUICallback) AdminKeysUpdate(*id.ID, bool) {
 	jww.DEBUG.Printf("AdminKeysUpdate unimplemented in %T", duiCB)
 }
 
+func (duiCB *dummyUICallback) AdminKeyRotated(*id.ID, *id.ID) {
+	jww.DEBUG.Printf("AdminKeyRotated unimplemented in %T", duiCB)
+}
+
 func (duiCB *dummyUICallback) NicknameUpdate(*id.ID, string, bool) {
 	jww.DEBUG.Printf("NicknameUpdate unimplemented in %T", duiCB)
 }
@@ -559,3 +691,13 @@ func (duiCB *dummyUICallback) UserMuted(*id.ID, ed25519.PublicKey, bool) {
 func (duiCB *dummyUICallback) MessageDeleted(cryptoMessage.ID) {
 	jww.DEBUG.Printf("MessageDeleted unimplemented in %T", duiCB)
 }
+
+func (duiCB *dummyUICallback) UserTyping(
+	*id.ID, ed25519.PublicKey, uint8, string, bool) {
+	jww.DEBUG.Printf("UserTyping unimplemented in %T", duiCB)
+}
+
+func (duiCB *dummyUICallback) ScheduledSendUpdate(
+	uint64, *id.ID, cryptoMessage.ID, error) {
+	jww.DEBUG.Printf("ScheduledSendUpdate unimplemented in %T", duiCB)
+}
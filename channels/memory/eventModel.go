@@ -0,0 +1,808 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// Package memory contains an in-memory implementation of
+// [channels.EventModel]. It is intended as a reference implementation for
+// tests and prototypes that need a working event model without an SQLite file
+// or a hand-written mock. Nothing is persisted.
+package memory
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Verify that EventModel adheres to the channels.EventModel interface and its
+// optional extensions.
+var (
+	_ channels.EventModel       = (*EventModel)(nil)
+	_ channels.MessageQuerier   = (*EventModel)(nil)
+	_ channels.PollTallier      = (*EventModel)(nil)
+	_ channels.TextSpanReceiver = (*EventModel)(nil)
+)
+
+// EventModel is an in-memory [channels.EventModel]. It follows the same
+// semantics as the SQL event model in the storage package: messages must be
+// received on a joined channel, message IDs are unique, and leaving a channel
+// deletes all of its messages.
+//
+// EventModel is safe for concurrent use. The snapshot methods (e.g.,
+// EventModel.Messages) return copies of the stored data so that tests can make
+// assertions without racing the channel manager.
+type EventModel struct {
+	channels map[id.ID]*cryptoBroadcast.Channel
+	messages map[uint64]*storedMessage
+	uuids    map[message.ID]uint64
+	muted    map[id.ID]map[string]ed25519.PublicKey
+	metadata map[id.ID]channels.ChannelMetadata
+
+	// nextUUID is the UUID assigned to the next received message. UUIDs start
+	// at 1 because 0 is returned when a message cannot be received.
+	nextUUID uint64
+
+	mux sync.RWMutex
+}
+
+// storedMessage is a message and the state attached to it.
+type storedMessage struct {
+	channels.ModelMessage
+
+	// revisions lists every revision of the text ordered by timestamp. It is
+	// empty until the message is first edited.
+	revisions []channels.MessageEdit
+
+	// votes are the votes on a poll keyed on the public key of the voter.
+	votes map[string]channels.ModelPollVote
+
+	// pollClosed is the time the poll was closed. It is nil if the message is
+	// not a poll or if the poll is open.
+	pollClosed *time.Time
+}
+
+// NewEventModel returns a new empty in-memory event model.
+func NewEventModel() *EventModel {
+	return &EventModel{
+		channels: make(map[id.ID]*cryptoBroadcast.Channel),
+		messages: make(map[uint64]*storedMessage),
+		uuids:    make(map[message.ID]uint64),
+		muted:    make(map[id.ID]map[string]ed25519.PublicKey),
+		metadata: make(map[id.ID]channels.ChannelMetadata),
+		nextUUID: 1,
+	}
+}
+
+// Builder returns a [channels.EventModelBuilder] that always returns this
+// event model, so that it can be passed to channels.NewManagerBuilder.
+func (m *EventModel) Builder() channels.EventModelBuilder {
+	return func(string) (channels.EventModel, error) { return m, nil }
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Channels                                                                   //
+////////////////////////////////////////////////////////////////////////////////
+
+// JoinChannel is called whenever a channel is joined locally.
+func (m *EventModel) JoinChannel(channel *cryptoBroadcast.Channel) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, exists := m.channels[*channel.ReceptionID]; exists {
+		jww.ERROR.Printf("[CH MEM] Failed to JoinChannel: channel %s "+
+			"already joined", channel.ReceptionID)
+		return
+	}
+
+	m.channels[*channel.ReceptionID] = copyChannel(channel)
+}
+
+// LeaveChannel is called whenever a channel is left locally. Deletes all
+// messages in the channel.
+func (m *EventModel) LeaveChannel(channelID *id.ID) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	delete(m.channels, *channelID)
+	delete(m.muted, *channelID)
+	delete(m.metadata, *channelID)
+	for uuid, msg := range m.messages {
+		if msg.ChannelID.Cmp(channelID) {
+			delete(m.uuids, msg.MessageID)
+			delete(m.messages, uuid)
+		}
+	}
+}
+
+// UpdateChannelMetadata is called whenever the channel admin updates the
+// metadata of the channel. Updates for channels that have not been joined are
+// ignored.
+func (m *EventModel) UpdateChannelMetadata(
+	channelID *id.ID, metadata channels.ChannelMetadata) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, exists := m.channels[*channelID]; !exists {
+		jww.ERROR.Printf("[CH MEM] Failed to UpdateChannelMetadata: channel "+
+			"%s not joined", channelID)
+		return
+	}
+
+	m.metadata[*channelID] = copyMetadata(metadata)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Messages                                                                   //
+////////////////////////////////////////////////////////////////////////////////
+
+// ReceiveMessage is called whenever a message is received on a given channel.
+func (m *EventModel) ReceiveMessage(channelID *id.ID, messageID message.ID,
+	nickname, text string, pubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType channels.MessageType,
+	status channels.SentStatus, hidden bool) uint64 {
+	return m.receive(channelID, messageID, message.ID{}, nickname, text,
+		pubKey, dmToken, codeset, timestamp, lease, round, messageType, status,
+		hidden)
+}
+
+// ReceiveReply is called whenever a message is received that is a reply on a
+// given channel.
+func (m *EventModel) ReceiveReply(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, text string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType channels.MessageType,
+	status channels.SentStatus, hidden bool) uint64 {
+	return m.receive(channelID, messageID, reactionTo, nickname, text,
+		pubKey, dmToken, codeset, timestamp, lease, round, messageType, status,
+		hidden)
+}
+
+// ReceiveReaction is called whenever a reaction to a message is received on a
+// given channel.
+func (m *EventModel) ReceiveReaction(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, reaction string,
+	pubKey ed25519.PublicKey, dmToken uint32, codeset uint8,
+	timestamp time.Time, lease time.Duration, round rounds.Round,
+	messageType channels.MessageType, status channels.SentStatus,
+	hidden bool) uint64 {
+	return m.receive(channelID, messageID, reactionTo, nickname, reaction,
+		pubKey, dmToken, codeset, timestamp, lease, round, messageType, status,
+		hidden)
+}
+
+// receive stores a new message and returns its UUID. Returns 0 if the channel
+// has not been joined or if a message with the same ID already exists.
+func (m *EventModel) receive(channelID *id.ID, messageID, parentID message.ID,
+	nickname, text string, pubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType channels.MessageType,
+	status channels.SentStatus, hidden bool) uint64 {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, exists := m.channels[*channelID]; !exists {
+		jww.ERROR.Printf("[CH MEM] Failed to receive message %s: channel %s "+
+			"not joined", messageID, channelID)
+		return 0
+	} else if _, exists = m.uuids[messageID]; exists {
+		jww.ERROR.Printf(
+			"[CH MEM] Failed to receive message %s: already exists", messageID)
+		return 0
+	}
+
+	uuid := m.nextUUID
+	m.nextUUID++
+	m.uuids[messageID] = uuid
+	m.messages[uuid] = &storedMessage{ModelMessage: copyMessage(
+		channels.ModelMessage{
+			UUID:            uuid,
+			Nickname:        nickname,
+			MessageID:       messageID,
+			ChannelID:       channelID,
+			ParentMessageID: parentID,
+			Timestamp:       timestamp,
+			Lease:           lease,
+			Status:          status,
+			Hidden:          hidden,
+			Content:         []byte(text),
+			Type:            messageType,
+			Round:           round.ID,
+			PubKey:          pubKey,
+			CodesetVersion:  codeset,
+			DmToken:         dmToken,
+		})}
+
+	return uuid
+}
+
+// UpdateFromUUID is called whenever a message at the UUID is modified.
+//
+// messageID, timestamp, round, pinned, hidden, and status are all nillable. If
+// a nil value is passed, then no update is made to that field.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) UpdateFromUUID(uuid uint64, messageID *message.ID,
+	timestamp *time.Time, round *rounds.Round, pinned, hidden *bool,
+	status *channels.SentStatus) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.messages[uuid]
+	if !exists {
+		return errors.WithMessage(
+			channels.NoMessageErr, "failed to UpdateFromUUID")
+	}
+
+	if messageID != nil && *messageID != msg.MessageID {
+		if _, exists = m.uuids[*messageID]; exists {
+			return errors.Errorf("failed to UpdateFromUUID: message %s "+
+				"already exists", messageID)
+		}
+		delete(m.uuids, msg.MessageID)
+		m.uuids[*messageID] = uuid
+		msg.MessageID = *messageID
+	}
+	msg.update(timestamp, round, pinned, hidden, status)
+
+	return nil
+}
+
+// UpdateFromMessageID is called whenever a message with the message ID is
+// modified. Returns the UUID of the message.
+//
+// timestamp, round, pinned, hidden, and status are all nillable. If a nil
+// value is passed, then no update is made to that field.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) UpdateFromMessageID(messageID message.ID,
+	timestamp *time.Time, round *rounds.Round, pinned, hidden *bool,
+	status *channels.SentStatus) (uint64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists {
+		return 0, errors.WithMessage(
+			channels.NoMessageErr, "failed to UpdateFromMessageID")
+	}
+	msg.update(timestamp, round, pinned, hidden, status)
+
+	return msg.UUID, nil
+}
+
+// update sets each of the non-nil fields on the message.
+func (msg *storedMessage) update(timestamp *time.Time, round *rounds.Round,
+	pinned, hidden *bool, status *channels.SentStatus) {
+	if timestamp != nil {
+		msg.Timestamp = *timestamp
+	}
+	if round != nil {
+		msg.Round = round.ID
+	}
+	if pinned != nil {
+		msg.Pinned = *pinned
+	}
+	if hidden != nil {
+		msg.Hidden = *hidden
+	}
+	if status != nil {
+		msg.Status = *status
+	}
+}
+
+// GetMessage returns the [channels.ModelMessage] with the given [message.ID].
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) GetMessage(
+	messageID message.ID) (channels.ModelMessage, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists {
+		return channels.ModelMessage{},
+			errors.WithMessage(channels.NoMessageErr, "failed to GetMessage")
+	}
+
+	return copyMessage(msg.ModelMessage), nil
+}
+
+// DeleteMessage deletes the message with the given [message.ID].
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) DeleteMessage(messageID message.ID) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	uuid, exists := m.uuids[messageID]
+	if !exists {
+		return errors.WithMessage(channels.NoMessageErr, "failed to DeleteMessage")
+	}
+	delete(m.uuids, messageID)
+	delete(m.messages, uuid)
+
+	return nil
+}
+
+// MuteUser is called whenever a user is muted or unmuted.
+func (m *EventModel) MuteUser(
+	channelID *id.ID, pubKey ed25519.PublicKey, unmute bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if unmute {
+		delete(m.muted[*channelID], string(pubKey))
+		return
+	}
+
+	if _, exists := m.muted[*channelID]; !exists {
+		m.muted[*channelID] = make(map[string]ed25519.PublicKey)
+	}
+	m.muted[*channelID][string(pubKey)] = copyBytes(pubKey)
+}
+
+// EditMessage is called whenever the text of the message with the given
+// [message.ID] is edited. The original text of the message and each edit are
+// saved as revisions and the text of the message is set to the newest
+// revision. Edits that have already been received are ignored.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) EditMessage(
+	messageID message.ID, edit channels.MessageEdit) (uint64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists {
+		return 0, errors.WithMessage(channels.NoMessageErr, "failed to EditMessage")
+	}
+
+	// Ignore edits that have already been saved (e.g., on replay)
+	for _, r := range msg.revisions {
+		if r.EditID == edit.EditID {
+			return msg.UUID, nil
+		}
+	}
+
+	// Save the original text as the first revision on the first edit
+	if len(msg.revisions) == 0 {
+		msg.revisions = append(msg.revisions, channels.MessageEdit{
+			Text:      string(msg.Content),
+			Timestamp: msg.Timestamp,
+			Round:     msg.Round,
+		})
+	}
+
+	// Edits may arrive out of order, so only replace the text of the message
+	// if this is the newest revision
+	newest := !edit.Timestamp.Before(
+		msg.revisions[len(msg.revisions)-1].Timestamp)
+
+	msg.revisions = append(msg.revisions, edit)
+	sort.SliceStable(msg.revisions, func(i, j int) bool {
+		return msg.revisions[i].Timestamp.Before(msg.revisions[j].Timestamp)
+	})
+	if newest {
+		msg.Content = []byte(edit.Text)
+	}
+
+	return msg.UUID, nil
+}
+
+// GetMessageRevisions returns every revision of the text of the message with
+// the given [message.ID], ordered from oldest to newest. The first revision is
+// the original text of the message. Returns an empty list if the message has
+// never been edited.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (m *EventModel) GetMessageRevisions(
+	messageID message.ID) ([]channels.MessageEdit, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists {
+		return nil, errors.WithMessage(
+			channels.NoMessageErr, "failed to GetMessageRevisions")
+	}
+
+	return append([]channels.MessageEdit{}, msg.revisions...), nil
+}
+
+// ReceiveTextSpans is called with the spans parsed from the text of the
+// message with the given UUID. The spans replace any previously received for
+// the message and are returned in channels.ModelMessage.Spans.
+func (m *EventModel) ReceiveTextSpans(uuid uint64, spans []channels.TextSpan) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.messages[uuid]
+	if !exists {
+		jww.ERROR.Printf("[CH MEM] Failed to receive text spans for message "+
+			"%d: message does not exist", uuid)
+		return
+	}
+
+	msg.Spans = copySpans(spans)
+}
+
+// getMessage returns the message with the given [message.ID]. Must be called
+// while the lock is held.
+func (m *EventModel) getMessage(messageID message.ID) (*storedMessage, bool) {
+	uuid, exists := m.uuids[messageID]
+	if !exists {
+		return nil, false
+	}
+	return m.messages[uuid], true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Polls                                                                      //
+////////////////////////////////////////////////////////////////////////////////
+
+// ReceivePollVote is called whenever a vote is received for the poll with the
+// given [message.ID]. A vote replaces any vote from the same user with an older
+// timestamp. Duplicate votes and votes sent after the poll was closed are
+// ignored.
+//
+// Returns channels.NoMessageErr if the poll does not exist.
+func (m *EventModel) ReceivePollVote(
+	pollID message.ID, vote channels.ModelPollVote) (uint64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	poll, exists := m.getPoll(pollID)
+	if !exists {
+		return 0, errors.WithMessage(
+			channels.NoMessageErr, "failed to ReceivePollVote")
+	}
+
+	// Ignore votes sent after the poll was closed
+	if poll.pollClosed != nil && !vote.Timestamp.Before(*poll.pollClosed) {
+		return poll.UUID, nil
+	}
+
+	// Only replace the current vote with a newer one
+	current, exists := poll.votes[string(vote.PubKey)]
+	if exists && (current.MessageID == vote.MessageID ||
+		!vote.Timestamp.After(current.Timestamp)) {
+		return poll.UUID, nil
+	}
+
+	if poll.votes == nil {
+		poll.votes = make(map[string]channels.ModelPollVote)
+	}
+	vote.PubKey = copyBytes(vote.PubKey)
+	poll.votes[string(vote.PubKey)] = vote
+
+	return poll.UUID, nil
+}
+
+// ClosePoll is called whenever the channel admin closes or reopens the poll
+// with the given [message.ID]. Votes sent after the timestamp are removed.
+//
+// Returns channels.NoMessageErr if the poll does not exist.
+func (m *EventModel) ClosePoll(
+	pollID message.ID, timestamp time.Time, reopen bool) (uint64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	poll, exists := m.getPoll(pollID)
+	if !exists {
+		return 0, errors.WithMessage(channels.NoMessageErr, "failed to ClosePoll")
+	}
+
+	if reopen {
+		poll.pollClosed = nil
+		return poll.UUID, nil
+	}
+
+	// Remove votes that arrived before the close but were sent after it
+	for key, vote := range poll.votes {
+		if !vote.Timestamp.Before(timestamp) {
+			delete(poll.votes, key)
+		}
+	}
+	poll.pollClosed = &timestamp
+
+	return poll.UUID, nil
+}
+
+// GetPollResults returns the tally of the votes of the poll with the given
+// [message.ID].
+//
+// Returns channels.NoMessageErr if the poll does not exist.
+func (m *EventModel) GetPollResults(
+	pollID message.ID) (channels.PollResults, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	parentErr := "failed to GetPollResults"
+	poll, exists := m.getPoll(pollID)
+	if !exists {
+		return channels.PollResults{},
+			errors.WithMessage(channels.NoMessageErr, parentErr)
+	}
+
+	options := &channels.CMIXChannelPoll{}
+	if err := json.Unmarshal(poll.Content, options); err != nil {
+		return channels.PollResults{}, errors.WithMessage(err, parentErr)
+	}
+
+	results := channels.PollResults{
+		Counts: make([]uint64, len(options.Options)),
+		Closed: poll.pollClosed != nil,
+	}
+	for _, vote := range poll.votes {
+		if int(vote.Option) < len(results.Counts) {
+			results.Counts[vote.Option]++
+		}
+	}
+
+	return results, nil
+}
+
+// getPoll returns the poll with the given [message.ID]. Returns false if the
+// message does not exist or is not a poll. Must be called while the lock is
+// held.
+func (m *EventModel) getPoll(pollID message.ID) (*storedMessage, bool) {
+	poll, exists := m.getMessage(pollID)
+	if !exists || poll.Type != channels.Poll {
+		return nil, false
+	}
+	return poll, true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Queries                                                                    //
+////////////////////////////////////////////////////////////////////////////////
+
+// QueryMessages returns the messages that match the [channels.MessageQuery],
+// ordered from oldest to newest, and the total number of messages that match
+// the query's filters, ignoring the cursors and limit.
+//
+// Returns channels.NoMessageErr if the message for a cursor does not exist.
+func (m *EventModel) QueryMessages(query channels.MessageQuery) (
+	[]channels.ModelMessage, int64, error) {
+	parentErr := "failed to QueryMessages"
+	if query.ChannelID == nil {
+		return nil, 0, errors.Errorf("%s: channel ID is required", parentErr)
+	}
+
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	var matches []*storedMessage
+	for _, msg := range m.messages {
+		if matchesQuery(query, msg.ModelMessage) {
+			matches = append(matches, msg)
+		}
+	}
+	total := int64(len(matches))
+
+	// Messages are ordered by timestamp and then by UUID so that messages with
+	// the same timestamp have a stable order across pages
+	sort.Slice(matches, func(i, j int) bool {
+		return less(matches[i].ModelMessage, matches[j].ModelMessage)
+	})
+
+	if query.Before != nil {
+		cursor, exists := m.getMessage(*query.Before)
+		if !exists {
+			return nil, 0, errors.WithMessagef(channels.NoMessageErr,
+				"%s: cursor message %s not found", parentErr, query.Before)
+		}
+		n := sort.Search(len(matches), func(i int) bool {
+			return !less(matches[i].ModelMessage, cursor.ModelMessage)
+		})
+		matches = matches[:n]
+	}
+	if query.After != nil {
+		cursor, exists := m.getMessage(*query.After)
+		if !exists {
+			return nil, 0, errors.WithMessagef(channels.NoMessageErr,
+				"%s: cursor message %s not found", parentErr, query.After)
+		}
+		n := sort.Search(len(matches), func(i int) bool {
+			return less(cursor.ModelMessage, matches[i].ModelMessage)
+		})
+		matches = matches[n:]
+	}
+
+	// Get the page adjacent to the After cursor when only it is set;
+	// otherwise, get the newest messages
+	if query.Limit > 0 && len(matches) > query.Limit {
+		if query.After == nil || query.Before != nil {
+			matches = matches[len(matches)-query.Limit:]
+		} else {
+			matches = matches[:query.Limit]
+		}
+	}
+
+	msgs := make([]channels.ModelMessage, len(matches))
+	for j, msg := range matches {
+		msgs[j] = copyMessage(msg.ModelMessage)
+	}
+
+	return msgs, total, nil
+}
+
+// matchesQuery returns true if the message matches all filters of the query
+// except the cursors.
+func matchesQuery(query channels.MessageQuery, msg channels.ModelMessage) bool {
+	switch {
+	case !msg.ChannelID.Cmp(query.ChannelID):
+		return false
+	case !query.Start.IsZero() && msg.Timestamp.Before(query.Start):
+		return false
+	case !query.End.IsZero() && !msg.Timestamp.Before(query.End):
+		return false
+	case query.ParentMessageID != nil &&
+		msg.ParentMessageID != *query.ParentMessageID:
+		return false
+	case query.PubKey != nil && !query.PubKey.Equal(msg.PubKey):
+		return false
+	case query.PinnedOnly && !msg.Pinned:
+		return false
+	case !query.IncludeHidden && msg.Hidden:
+		return false
+	}
+	return true
+}
+
+// less returns true if message a is ordered before message b.
+func less(a, b channels.ModelMessage) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.UUID < b.UUID
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Snapshots                                                                  //
+////////////////////////////////////////////////////////////////////////////////
+
+// Channels returns a copy of every joined channel, ordered by channel ID.
+func (m *EventModel) Channels() []*cryptoBroadcast.Channel {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	list := make([]*cryptoBroadcast.Channel, 0, len(m.channels))
+	for _, channel := range m.channels {
+		list = append(list, copyChannel(channel))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(
+			list[i].ReceptionID[:], list[j].ReceptionID[:]) < 0
+	})
+
+	return list
+}
+
+// Messages returns a copy of every message in every channel, in the order they
+// were received.
+func (m *EventModel) Messages() []channels.ModelMessage {
+	return m.filterMessages(func(channels.ModelMessage) bool { return true })
+}
+
+// ChannelMessages returns a copy of every message in the channel, in the order
+// they were received.
+func (m *EventModel) ChannelMessages(
+	channelID *id.ID) []channels.ModelMessage {
+	return m.filterMessages(func(msg channels.ModelMessage) bool {
+		return msg.ChannelID.Cmp(channelID)
+	})
+}
+
+// filterMessages returns a copy of every message that matches the filter,
+// ordered by UUID.
+func (m *EventModel) filterMessages(
+	filter func(msg channels.ModelMessage) bool) []channels.ModelMessage {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	msgs := make([]channels.ModelMessage, 0, len(m.messages))
+	for _, msg := range m.messages {
+		if filter(msg.ModelMessage) {
+			msgs = append(msgs, copyMessage(msg.ModelMessage))
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].UUID < msgs[j].UUID })
+
+	return msgs
+}
+
+// MutedUsers returns the public keys of every user muted in the channel,
+// ordered by public key.
+func (m *EventModel) MutedUsers(channelID *id.ID) []ed25519.PublicKey {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	list := make([]ed25519.PublicKey, 0, len(m.muted[*channelID]))
+	for _, pubKey := range m.muted[*channelID] {
+		list = append(list, copyBytes(pubKey))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+
+	return list
+}
+
+// ChannelMetadata returns a copy of the newest metadata of the channel. Returns
+// false if the metadata of the channel has not been updated.
+func (m *EventModel) ChannelMetadata(
+	channelID *id.ID) (channels.ChannelMetadata, bool) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	metadata, exists := m.metadata[*channelID]
+	return copyMetadata(metadata), exists
+}
+
+// copyChannel returns a deep copy of the channel.
+func copyChannel(channel *cryptoBroadcast.Channel) *cryptoBroadcast.Channel {
+	c := *channel
+	c.ReceptionID = channel.ReceptionID.DeepCopy()
+	c.Salt = copyBytes(channel.Salt)
+	c.RsaPubKeyHash = copyBytes(channel.RsaPubKeyHash)
+	c.Secret = copyBytes(channel.Secret)
+	return &c
+}
+
+// copyMessage returns a copy of the message that shares no memory with the
+// original.
+func copyMessage(msg channels.ModelMessage) channels.ModelMessage {
+	if msg.ChannelID != nil {
+		msg.ChannelID = msg.ChannelID.DeepCopy()
+	}
+	msg.Content = copyBytes(msg.Content)
+	msg.PubKey = copyBytes(msg.PubKey)
+	msg.Spans = copySpans(msg.Spans)
+	return msg
+}
+
+// copySpans returns a deep copy of the text spans. Empty lists become nil.
+func copySpans(spans []channels.TextSpan) []channels.TextSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	c := make([]channels.TextSpan, len(spans))
+	for i, span := range spans {
+		c[i] = span
+		c[i].PubKey = copyBytes(span.PubKey)
+		if span.ChannelID != nil {
+			c[i].ChannelID = span.ChannelID.DeepCopy()
+		}
+	}
+	return c
+}
+
+// copyMetadata returns a deep copy of the channel metadata.
+func copyMetadata(metadata channels.ChannelMetadata) channels.ChannelMetadata {
+	metadata.Icon = copyBytes(metadata.Icon)
+	return metadata
+}
+
+// copyBytes returns a copy of the byte slice. Nil slices stay nil.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
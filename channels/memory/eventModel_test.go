@@ -0,0 +1,393 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package memory
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// newTestChannel returns a new EventModel with a joined channel.
+func newTestChannel(t *testing.T) (*EventModel, *id.ID) {
+	m := NewEventModel()
+	channelID := id.NewIdFromString(t.Name(), id.User, t)
+	m.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: channelID,
+		Name:        t.Name(),
+		Description: t.Name(),
+	})
+	return m, channelID
+}
+
+// receiveText receives a text message with the given timestamp offset and
+// returns its ID.
+func receiveText(t *testing.T, m *EventModel, channelID *id.ID, text string,
+	offset time.Duration) message.ID {
+	msgID := message.DeriveChannelMessageID(channelID, 1, []byte(text))
+	uuid := m.ReceiveMessage(channelID, msgID, "nickname", text,
+		ed25519.PublicKey("sender"), 5, 0, time.Unix(0, 0).Add(offset), 0,
+		rounds.Round{ID: 1}, channels.Text, channels.Delivered, false)
+	if uuid == 0 {
+		t.Fatalf("Failed to receive message %q.", text)
+	}
+	return msgID
+}
+
+// Tests that messages can be received, updated, retrieved, and deleted.
+func TestEventModel_Messages(t *testing.T) {
+	m, channelID := newTestChannel(t)
+
+	msgID := receiveText(t, m, channelID, "text", 0)
+	replyID := message.DeriveChannelMessageID(channelID, 2, []byte("reply"))
+	replyUUID := m.ReceiveReply(channelID, replyID, msgID, "nickname", "reply",
+		ed25519.PublicKey("sender"), 5, 0, time.Unix(1, 0), 0,
+		rounds.Round{ID: 2}, channels.Text, channels.Unsent, false)
+
+	// Duplicate message IDs are rejected
+	if uuid := m.ReceiveMessage(channelID, msgID, "nickname", "text", nil, 0,
+		0, time.Unix(0, 0), 0, rounds.Round{}, channels.Text, 0, false); uuid != 0 {
+		t.Errorf("Received duplicate message with UUID %d.", uuid)
+	}
+
+	newID := message.DeriveChannelMessageID(channelID, 3, []byte("reply"))
+	status := channels.Delivered
+	err := m.UpdateFromUUID(replyUUID, &newID, nil, nil, nil, nil, &status)
+	if err != nil {
+		t.Fatalf("Failed to update reply: %+v", err)
+	}
+	pinned := true
+	uuid, err := m.UpdateFromMessageID(newID, nil, nil, &pinned, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to update reply: %+v", err)
+	} else if uuid != replyUUID {
+		t.Errorf("Unexpected UUID.\nexpected: %d\nreceived: %d", replyUUID, uuid)
+	}
+
+	reply, err := m.GetMessage(newID)
+	if err != nil {
+		t.Fatalf("Failed to get reply: %+v", err)
+	}
+	if reply.ParentMessageID != msgID || !reply.Pinned ||
+		reply.Status != channels.Delivered || string(reply.Content) != "reply" {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+	if _, err = m.GetMessage(replyID); !channels.CheckNoMessageErr(err) {
+		t.Errorf("Unexpected error for old message ID: %+v", err)
+	}
+
+	if err = m.DeleteMessage(newID); err != nil {
+		t.Fatalf("Failed to delete reply: %+v", err)
+	}
+	if err = m.DeleteMessage(newID); !channels.CheckNoMessageErr(err) {
+		t.Errorf("Unexpected error deleting reply twice: %+v", err)
+	}
+	if msgs := m.Messages(); len(msgs) != 1 || msgs[0].MessageID != msgID {
+		t.Errorf("Unexpected messages: %+v", msgs)
+	}
+}
+
+// Tests that EventModel.ReceiveMessage rejects messages on channels that have
+// not been joined and that EventModel.LeaveChannel deletes the channel's
+// messages.
+func TestEventModel_LeaveChannel(t *testing.T) {
+	m, channelID := newTestChannel(t)
+	msgID := receiveText(t, m, channelID, "text", 0)
+	m.MuteUser(channelID, ed25519.PublicKey("sender"), false)
+
+	m.LeaveChannel(channelID)
+	if _, err := m.GetMessage(msgID); !channels.CheckNoMessageErr(err) {
+		t.Errorf("Got message after leaving its channel: %+v", err)
+	}
+	if len(m.Channels()) != 0 || len(m.MutedUsers(channelID)) != 0 {
+		t.Errorf("Channel not removed: %+v", m.Channels())
+	}
+
+	if uuid := m.ReceiveMessage(channelID, msgID, "nickname", "text", nil, 0,
+		0, time.Unix(0, 0), 0, rounds.Round{}, channels.Text, 0, false); uuid != 0 {
+		t.Errorf("Received message on channel that was left: %d", uuid)
+	}
+}
+
+// Tests that EventModel.MuteUser adds and removes users from
+// EventModel.MutedUsers.
+func TestEventModel_MuteUser(t *testing.T) {
+	m, channelID := newTestChannel(t)
+
+	m.MuteUser(channelID, ed25519.PublicKey("bob"), false)
+	m.MuteUser(channelID, ed25519.PublicKey("alice"), false)
+	m.MuteUser(channelID, ed25519.PublicKey("carol"), false)
+	m.MuteUser(channelID, ed25519.PublicKey("carol"), true)
+
+	expected := []ed25519.PublicKey{
+		ed25519.PublicKey("alice"), ed25519.PublicKey("bob")}
+	if muted := m.MutedUsers(channelID); !reflect.DeepEqual(expected, muted) {
+		t.Errorf("Unexpected muted users.\nexpected: %q\nreceived: %q",
+			expected, muted)
+	}
+}
+
+// Tests that EventModel.UpdateChannelMetadata replaces the metadata returned by
+// EventModel.ChannelMetadata and ignores channels that have not been joined.
+func TestEventModel_UpdateChannelMetadata(t *testing.T) {
+	m, channelID := newTestChannel(t)
+	if _, exists := m.ChannelMetadata(channelID); exists {
+		t.Errorf("Metadata exists before it was updated.")
+	}
+
+	for _, topic := range []string{"first", "second"} {
+		expected := channels.ChannelMetadata{
+			Description: "description",
+			Icon:        []byte("icon"),
+			Topic:       topic,
+			Timestamp:   time.Unix(5, 0),
+		}
+		m.UpdateChannelMetadata(channelID, expected)
+
+		metadata, exists := m.ChannelMetadata(channelID)
+		if !exists || !reflect.DeepEqual(expected, metadata) {
+			t.Errorf("Unexpected metadata (%t).\nexpected: %+v\nreceived: %+v",
+				exists, expected, metadata)
+		}
+	}
+
+	otherID := id.NewIdFromString("other", id.User, t)
+	m.UpdateChannelMetadata(otherID, channels.ChannelMetadata{Topic: "topic"})
+	if _, exists := m.ChannelMetadata(otherID); exists {
+		t.Errorf("Metadata set for channel that was not joined.")
+	}
+
+	m.LeaveChannel(channelID)
+	if _, exists := m.ChannelMetadata(channelID); exists {
+		t.Errorf("Metadata not removed after leaving the channel.")
+	}
+}
+
+// Tests that EventModel.EditMessage keeps every revision and displays the
+// newest, even when edits arrive out of order or are replayed.
+func TestEventModel_EditMessage(t *testing.T) {
+	m, channelID := newTestChannel(t)
+	msgID := receiveText(t, m, channelID, "original", 0)
+
+	edits := []channels.MessageEdit{
+		{EditID: message.ID{2}, Text: "second", Timestamp: time.Unix(2, 0)},
+		{EditID: message.ID{1}, Text: "first", Timestamp: time.Unix(1, 0)},
+		{EditID: message.ID{2}, Text: "second", Timestamp: time.Unix(2, 0)},
+	}
+	for j, edit := range edits {
+		if _, err := m.EditMessage(msgID, edit); err != nil {
+			t.Fatalf("Failed to edit message (%d): %+v", j, err)
+		}
+	}
+
+	msg, err := m.GetMessage(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get message: %+v", err)
+	} else if string(msg.Content) != "second" {
+		t.Errorf("Unexpected text.\nexpected: %q\nreceived: %q",
+			"second", msg.Content)
+	}
+
+	revisions, err := m.GetMessageRevisions(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %+v", err)
+	}
+	expected := []channels.MessageEdit{
+		{Text: "original", Timestamp: time.Unix(0, 0), Round: 1},
+		edits[1], edits[0],
+	}
+	if !reflect.DeepEqual(expected, revisions) {
+		t.Errorf("Unexpected revisions.\nexpected: %+v\nreceived: %+v",
+			expected, revisions)
+	}
+
+	_, err = m.EditMessage(message.ID{9}, edits[0])
+	if !channels.CheckNoMessageErr(err) {
+		t.Errorf("Unexpected error editing unknown message: %+v", err)
+	}
+}
+
+// Tests that the spans received by EventModel.ReceiveTextSpans are returned
+// with the message and cannot be modified by the caller.
+func TestEventModel_ReceiveTextSpans(t *testing.T) {
+	m, channelID := newTestChannel(t)
+	text := "**hi** " + channels.ChannelLinkTag(channelID)
+	msgID := receiveText(t, m, channelID, text, 0)
+
+	msg, err := m.GetMessage(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get message: %+v", err)
+	}
+
+	spans := channels.ParseText(text)
+	m.ReceiveTextSpans(msg.UUID, spans)
+	spans[1].ChannelID[0]++
+
+	msg, err = m.GetMessage(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get message: %+v", err)
+	} else if !reflect.DeepEqual(channels.ParseText(text), msg.Spans) {
+		t.Errorf("Unexpected spans.\nexpected: %+v\nreceived: %+v",
+			channels.ParseText(text), msg.Spans)
+	}
+}
+
+// Tests that poll votes are tallied and that closing a poll removes late votes.
+func TestEventModel_Polls(t *testing.T) {
+	m, channelID := newTestChannel(t)
+
+	pollJson, err := json.Marshal(&channels.CMIXChannelPoll{
+		Question: "Question?", Options: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pollID := message.DeriveChannelMessageID(channelID, 1, pollJson)
+	m.ReceiveMessage(channelID, pollID, "nickname", string(pollJson),
+		ed25519.PublicKey("sender"), 0, 0, time.Unix(0, 0), 0,
+		rounds.Round{ID: 1}, channels.Poll, 0, false)
+
+	votes := []channels.ModelPollVote{
+		{MessageID: message.ID{1}, PubKey: []byte("alice"), Option: 0,
+			Timestamp: time.Unix(1, 0)},
+		{MessageID: message.ID{2}, PubKey: []byte("alice"), Option: 1,
+			Timestamp: time.Unix(2, 0)},
+		{MessageID: message.ID{3}, PubKey: []byte("bob"), Option: 1,
+			Timestamp: time.Unix(3, 0)},
+		{MessageID: message.ID{4}, PubKey: []byte("carol"), Option: 0,
+			Timestamp: time.Unix(5, 0)},
+	}
+	for j, vote := range votes {
+		if _, err = m.ReceivePollVote(pollID, vote); err != nil {
+			t.Fatalf("Failed to receive vote %d: %+v", j, err)
+		}
+	}
+
+	if _, err = m.ClosePoll(pollID, time.Unix(4, 0), false); err != nil {
+		t.Fatalf("Failed to close poll: %+v", err)
+	}
+
+	expected := channels.PollResults{Counts: []uint64{0, 2}, Closed: true}
+	results, err := m.GetPollResults(pollID)
+	if err != nil {
+		t.Fatalf("Failed to get poll results: %+v", err)
+	} else if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Unexpected poll results.\nexpected: %+v\nreceived: %+v",
+			expected, results)
+	}
+
+	textID := receiveText(t, m, channelID, "text", 0)
+	if _, err = m.GetPollResults(textID); !channels.CheckNoMessageErr(err) {
+		t.Errorf("Unexpected error for message that is not a poll: %+v", err)
+	}
+}
+
+// Tests that EventModel.QueryMessages filters and pages through messages.
+func TestEventModel_QueryMessages(t *testing.T) {
+	m, channelID := newTestChannel(t)
+
+	msgIDs := make([]message.ID, 5)
+	for j := range msgIDs {
+		msgIDs[j] = receiveText(
+			t, m, channelID, strconv.Itoa(j), time.Duration(j)*time.Minute)
+	}
+	hidden := true
+	if _, err := m.UpdateFromMessageID(
+		msgIDs[4], nil, nil, nil, &hidden, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    channels.MessageQuery
+		expected []int
+		total    int64
+	}{
+		{channels.MessageQuery{}, []int{0, 1, 2, 3}, 4},
+		{channels.MessageQuery{IncludeHidden: true}, []int{0, 1, 2, 3, 4}, 5},
+		{channels.MessageQuery{Limit: 2}, []int{2, 3}, 4},
+		{channels.MessageQuery{Before: &msgIDs[3], Limit: 2}, []int{1, 2}, 4},
+		{channels.MessageQuery{After: &msgIDs[0], Limit: 2}, []int{1, 2}, 4},
+		{channels.MessageQuery{Start: time.Unix(60, 0),
+			End: time.Unix(180, 0)}, []int{1, 2}, 2},
+		{channels.MessageQuery{PubKey: ed25519.PublicKey("sender")},
+			[]int{0, 1, 2, 3}, 4},
+		{channels.MessageQuery{PubKey: ed25519.PublicKey("other")}, nil, 0},
+	}
+
+	for j, tt := range tests {
+		tt.query.ChannelID = channelID
+		msgs, total, err := m.QueryMessages(tt.query)
+		if err != nil {
+			t.Fatalf("Failed to query messages (%d): %+v", j, err)
+		}
+		var received []int
+		for _, msg := range msgs {
+			received = append(received, int(msg.Content[0]-'0'))
+		}
+		if !reflect.DeepEqual(tt.expected, received) || total != tt.total {
+			t.Errorf("Unexpected results (%d).\nexpected: %v (%d)"+
+				"\nreceived: %v (%d)", j, tt.expected, tt.total, received, total)
+		}
+	}
+
+	_, _, err := m.QueryMessages(
+		channels.MessageQuery{ChannelID: channelID, Before: &message.ID{9}})
+	if !channels.CheckNoMessageErr(err) {
+		t.Errorf("Unexpected error for unknown cursor: %+v", err)
+	}
+}
+
+// Tests that the snapshot methods return copies that are not affected by later
+// changes to the EventModel and that the EventModel can be used concurrently.
+func TestEventModel_Snapshots(t *testing.T) {
+	m, channelID := newTestChannel(t)
+
+	var wg sync.WaitGroup
+	for j := 0; j < 10; j++ {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			text := strconv.Itoa(j)
+			msgID := message.DeriveChannelMessageID(channelID, 1, []byte(text))
+			if m.ReceiveMessage(channelID, msgID, "nickname", text, nil, 0, 0,
+				time.Unix(0, 0), 0, rounds.Round{}, channels.Text, 0, false) == 0 {
+				t.Errorf("Failed to receive message %q.", text)
+			}
+			m.ChannelMessages(channelID)
+		}(j)
+	}
+	wg.Wait()
+
+	msgs := m.ChannelMessages(channelID)
+	if len(msgs) != 10 {
+		t.Fatalf("Unexpected number of messages.\nexpected: %d\nreceived: %d",
+			10, len(msgs))
+	}
+	for j := range msgs {
+		if msgs[j].UUID != uint64(j+1) {
+			t.Errorf("Message %d has UUID %d.", j, msgs[j].UUID)
+		}
+	}
+
+	msgs[0].Content[0] = 'x'
+	msgs[0].ChannelID[0] = 'x'
+	msg, err := m.GetMessage(msgs[0].MessageID)
+	if err != nil {
+		t.Fatal(err)
+	} else if msg.Content[0] == 'x' || !msg.ChannelID.Cmp(channelID) {
+		t.Errorf("Snapshot shares memory with the model: %+v", msg)
+	}
+}
@@ -38,6 +38,16 @@ const (
 	// Invitation denotes that the message is an invitation to another channel.
 	Invitation MessageType = 5
 
+	// Poll denotes that the message is a poll that users can vote on.
+	Poll MessageType = 6
+
+	// PollVote denotes that the message is a vote on a poll.
+	PollVote MessageType = 7
+
+	// Typing denotes that the message signals that the sender started or
+	// stopped typing. It is delivered to the UI and is never stored.
+	Typing MessageType = 8
+
 	////////////////////////////////////////////////////////////////////////////
 	// Message Actions                                                        //
 	////////////////////////////////////////////////////////////////////////////
@@ -56,6 +66,34 @@ const (
 	// AdminReplay denotes that the message contains an admin message.
 	AdminReplay MessageType = 104
 
+	// Edit denotes that the message replaces the text of a previously sent
+	// message. The previous text is kept as a revision by the event model.
+	Edit MessageType = 105
+
+	// PollClose denotes that the poll should be closed. Votes sent after the
+	// poll is closed are not counted.
+	PollClose MessageType = 106
+
+	// Moderator denotes that the message appoints or revokes a moderator of the
+	// channel. Moderators may delete, pin, and mute but cannot appoint others.
+	Moderator MessageType = 107
+
+	// KeyRotation denotes that the message moves the channel to a new admin
+	// key. Members replace the channel with one derived from the new key.
+	KeyRotation MessageType = 108
+
+	// SlowMode denotes that the message sets the minimum time between two
+	// messages from the same user in the channel.
+	SlowMode MessageType = 109
+
+	// Ban denotes that the message bans a user from the channel. Banned users
+	// are muted and their existing messages are hidden.
+	Ban MessageType = 110
+
+	// MetadataUpdate denotes that the message replaces the description, icon,
+	// topic, and rules of the channel.
+	MetadataUpdate MessageType = 111
+
 	////////////////////////////////////////////////////////////////////////////
 	// Extensions                                                             //
 	////////////////////////////////////////////////////////////////////////////
@@ -79,6 +117,12 @@ func (mt MessageType) String() string {
 		return "Silent"
 	case Invitation:
 		return "Invitation"
+	case Poll:
+		return "Poll"
+	case PollVote:
+		return "PollVote"
+	case Typing:
+		return "Typing"
 	case Delete:
 		return "Delete"
 	case Pinned:
@@ -87,6 +131,20 @@ func (mt MessageType) String() string {
 		return "Mute"
 	case AdminReplay:
 		return "AdminReplay"
+	case Edit:
+		return "Edit"
+	case PollClose:
+		return "PollClose"
+	case Moderator:
+		return "Moderator"
+	case KeyRotation:
+		return "KeyRotation"
+	case SlowMode:
+		return "SlowMode"
+	case Ban:
+		return "Ban"
+	case MetadataUpdate:
+		return "MetadataUpdate"
 	case FileTransfer:
 		return "FileTransfer"
 	default:
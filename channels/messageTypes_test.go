@@ -17,10 +17,17 @@ import (
 func TestMessageType_String_Consistency(t *testing.T) {
 	expectedStrings := map[MessageType]string{
 		Text: "Text", AdminText: "AdminText", Reaction: "Reaction", Silent: "Silent", Invitation: "Invitation",
+		Poll: "Poll", PollVote: "PollVote", Typing: "Typing",
 		Delete: "Delete", Pinned: "Pinned", Mute: "Mute",
-		AdminReplay: "AdminReplay", FileTransfer: "FileTransfer",
-		Invitation + 1: fmt.Sprintf("Unknown messageType %d", Invitation+1),
-		Invitation + 2: fmt.Sprintf("Unknown messageType %d", Invitation+2),
+		AdminReplay: "AdminReplay", Edit: "Edit", PollClose: "PollClose",
+		Moderator:      "Moderator",
+		KeyRotation:    "KeyRotation",
+		SlowMode:       "SlowMode",
+		Ban:            "Ban",
+		MetadataUpdate: "MetadataUpdate",
+		FileTransfer:   "FileTransfer",
+		Typing + 1:     fmt.Sprintf("Unknown messageType %d", Typing+1),
+		Typing + 2:     fmt.Sprintf("Unknown messageType %d", Typing+2),
 	}
 
 	for mt, expected := range expectedStrings {
@@ -47,8 +54,9 @@ func TestMessageType_Bytes_Consistency(t *testing.T) {
 // Tests that a MessageType marshalled via MessageType.Marshal and unmarshalled
 // via UnmarshalMessageType matches the original.
 func TestMessageType_Marshal_UnmarshalMessageType(t *testing.T) {
-	tests := []MessageType{Text, AdminText, Reaction, Delete, Pinned, Mute,
-		AdminReplay, FileTransfer}
+	tests := []MessageType{Text, AdminText, Reaction, Poll, PollVote, Typing,
+		Delete, Pinned, Mute, AdminReplay, Edit, PollClose, Moderator,
+		KeyRotation, SlowMode, Ban, MetadataUpdate, FileTransfer}
 
 	for _, mt := range tests {
 		data := mt.Marshal()
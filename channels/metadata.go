@@ -0,0 +1,155 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// MaxChannelIconSize is the maximum size, in bytes, of a channel icon. The
+// whole update must also fit in a single admin message, so the space left for
+// the icon shrinks as the other fields grow.
+const MaxChannelIconSize = 512
+
+// ChannelMetadata is the editable metadata of a channel. The channel name is
+// not included because the channel ID is derived from it and it cannot change.
+// The description replaces the description the channel was created with.
+type ChannelMetadata struct {
+	// Description is the description of the channel.
+	Description string `json:"description"`
+
+	// Icon is a small image used as the icon of the channel. It may be empty.
+	Icon []byte `json:"icon,omitempty"`
+
+	// Topic is the current topic of the channel.
+	Topic string `json:"topic"`
+
+	// Rules is the text of the rules of the channel.
+	Rules string `json:"rules"`
+
+	// Timestamp is the time the update was sent. It is ignored when sending an
+	// update. It is zero if the metadata has never been updated.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// metadataManager tracks the newest metadata update of each channel. Updates
+// are sent by the channel admin with a MetadataUpdate message.
+type metadataManager struct {
+	list map[id.ID]ChannelMetadata
+
+	mux sync.RWMutex
+	kv  versioned.KV
+}
+
+// newOrLoadMetadataManager loads an existing metadataManager from storage, if
+// it exists. Otherwise, it initialises a new empty metadataManager.
+func newOrLoadMetadataManager(kv versioned.KV) (*metadataManager, error) {
+	mm := &metadataManager{
+		list: make(map[id.ID]ChannelMetadata),
+		kv:   kv,
+	}
+
+	err := mm.load()
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	return mm, nil
+}
+
+// set replaces the metadata of the channel. The metadata is only set if its
+// timestamp is newer than the timestamp of the current metadata so that
+// replayed or out of order updates do not undo a newer update. Returns true if
+// the metadata was set.
+func (mm *metadataManager) set(channelID *id.ID, metadata ChannelMetadata) bool {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	current, exists := mm.list[*channelID]
+	if exists && !metadata.Timestamp.After(current.Timestamp) {
+		return false
+	}
+
+	mm.list[*channelID] = metadata
+
+	if err := mm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save channel metadata: %+v", err)
+	}
+
+	return true
+}
+
+// get returns the metadata of the channel. Returns false if the metadata of
+// the channel has never been updated.
+func (mm *metadataManager) get(channelID *id.ID) (ChannelMetadata, bool) {
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+	metadata, exists := mm.list[*channelID]
+	return metadata, exists
+}
+
+// removeChannel deletes the metadata of the channel. This should only be called
+// when leaving a channel.
+func (mm *metadataManager) removeChannel(channelID *id.ID) error {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	if _, exists := mm.list[*channelID]; !exists {
+		return nil
+	}
+
+	delete(mm.list, *channelID)
+	return mm.save()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage values.
+const (
+	channelMetadataStoreVer = 0
+	channelMetadataStoreKey = "channelMetadata"
+)
+
+// save stores the metadata of every channel to storage.
+func (mm *metadataManager) save() error {
+	data, err := json.Marshal(mm.list)
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   channelMetadataStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return mm.kv.Set(channelMetadataStoreKey, obj)
+}
+
+// load gets the metadata of every channel from storage.
+func (mm *metadataManager) load() error {
+	obj, err := mm.kv.Get(channelMetadataStoreKey, channelMetadataStoreVer)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(obj.Data, &mm.list); err != nil {
+		return errors.Wrap(err, "could not unmarshal channel metadata")
+	}
+	return nil
+}
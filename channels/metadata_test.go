@@ -0,0 +1,160 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that newOrLoadMetadataManager initialises a new empty metadataManager
+// and loads the metadata saved by a previous one.
+func Test_newOrLoadMetadataManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+
+	mm, err := newOrLoadMetadataManager(kv)
+	require.NoError(t, err)
+	require.Empty(t, mm.list)
+
+	channelID := randChannelID(prng, t)
+	metadata := ChannelMetadata{
+		Description: "description",
+		Icon:        []byte("icon"),
+		Topic:       "topic",
+		Rules:       "rules",
+		Timestamp:   time.Unix(5, 0).UTC(),
+	}
+	require.True(t, mm.set(channelID, metadata))
+
+	loaded, err := newOrLoadMetadataManager(kv)
+	require.NoError(t, err)
+	received, exists := loaded.get(channelID)
+	require.True(t, exists)
+	require.Equal(t, metadata, received)
+}
+
+// Tests that metadataManager.set only replaces the metadata with newer
+// updates.
+func Test_metadataManager_set(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	mm, err := newOrLoadMetadataManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+
+	require.True(t, mm.set(channelID,
+		ChannelMetadata{Topic: "first", Timestamp: time.Unix(10, 0)}))
+	require.False(t, mm.set(channelID,
+		ChannelMetadata{Topic: "older", Timestamp: time.Unix(5, 0)}),
+		"Older update set.")
+	require.False(t, mm.set(channelID,
+		ChannelMetadata{Topic: "replay", Timestamp: time.Unix(10, 0)}),
+		"Replayed update set.")
+	require.True(t, mm.set(channelID,
+		ChannelMetadata{Topic: "second", Timestamp: time.Unix(15, 0)}))
+
+	metadata, exists := mm.get(channelID)
+	require.True(t, exists)
+	require.Equal(t, "second", metadata.Topic)
+
+	_, exists = mm.get(randChannelID(prng, t))
+	require.False(t, exists)
+}
+
+// Tests that metadataManager.removeChannel deletes the metadata of the channel
+// from memory and storage.
+func Test_metadataManager_removeChannel(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	mm, err := newOrLoadMetadataManager(kv)
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+	mm.set(channelID, ChannelMetadata{Topic: "topic", Timestamp: time.Unix(5, 0)})
+
+	require.NoError(t, mm.removeChannel(channelID))
+	_, exists := mm.get(channelID)
+	require.False(t, exists)
+
+	loaded, err := newOrLoadMetadataManager(kv)
+	require.NoError(t, err)
+	_, exists = loaded.get(channelID)
+	require.False(t, exists)
+}
+
+// Tests that manager.UpdateChannelMetadata sends a MetadataUpdate message with
+// the new metadata.
+func Test_manager_UpdateChannelMetadata(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+	metadata := ChannelMetadata{
+		Description: "description",
+		Icon:        []byte("icon"),
+		Topic:       "topic",
+		Rules:       "rules",
+	}
+
+	_, _, _, err :=
+		m.UpdateChannelMetadata(ch.ReceptionID, metadata, cmix.CMIXParams{})
+	require.NoError(t, err)
+
+	chMsg := &ChannelMessage{}
+	require.NoError(t, proto.Unmarshal(mbc.payload, chMsg))
+	metadataMsg := &CMIXChannelMetadata{}
+	require.NoError(t, proto.Unmarshal(chMsg.Payload, metadataMsg))
+	require.Equal(t, metadata.Description, metadataMsg.Description)
+	require.Equal(t, metadata.Icon, metadataMsg.Icon)
+	require.Equal(t, metadata.Topic, metadataMsg.Topic)
+	require.Equal(t, metadata.Rules, metadataMsg.Rules)
+}
+
+// Error path: Tests that manager.UpdateChannelMetadata returns NotAnAdminErr
+// when the user is not the admin of the channel and an error for an icon that
+// is too large.
+func Test_manager_UpdateChannelMetadata_Errors(t *testing.T) {
+	m, ch, mbc := newKeyRotationTestManager(t)
+
+	_, _, _, err := m.UpdateChannelMetadata(ch.ReceptionID, ChannelMetadata{
+		Icon: bytes.Repeat([]byte{1}, MaxChannelIconSize+1)}, cmix.CMIXParams{})
+	require.Error(t, err)
+
+	require.NoError(t, m.DeleteChannelAdminKey(ch.ReceptionID))
+	_, _, _, err = m.UpdateChannelMetadata(
+		ch.ReceptionID, ChannelMetadata{Topic: "topic"}, cmix.CMIXParams{})
+	require.ErrorIs(t, err, NotAnAdminErr)
+	require.False(t, mbc.hasRun, "Update sent.")
+}
+
+// Tests that manager.GetChannelMetadata returns the description the channel was
+// created with until the metadata is updated and returns ChannelDoesNotExistsErr
+// for an unknown channel.
+func Test_manager_GetChannelMetadata(t *testing.T) {
+	m, ch, _ := newKeyRotationTestManager(t)
+	m.events = initEvents(&MockEvent{}, 512, m.local, m.rng)
+
+	metadata, err := m.GetChannelMetadata(ch.ReceptionID)
+	require.NoError(t, err)
+	require.Equal(t, ChannelMetadata{Description: ch.Description}, metadata)
+
+	updated := ChannelMetadata{Topic: "topic", Timestamp: time.Unix(5, 0)}
+	m.metadata.set(ch.ReceptionID, updated)
+	metadata, err = m.GetChannelMetadata(ch.ReceptionID)
+	require.NoError(t, err)
+	require.Equal(t, updated, metadata)
+
+	_, err = m.GetChannelMetadata(id.NewIdFromString("channel", id.User, t))
+	require.ErrorIs(t, err, ChannelDoesNotExistsErr)
+}
@@ -0,0 +1,189 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// moderatorActions are the message types that a moderator may send. They are
+// treated as admin messages when sent by a moderator.
+var moderatorActions = map[MessageType]struct{}{
+	Delete: {},
+	Pinned: {},
+	Mute:   {},
+}
+
+// isModeratorAction returns true if moderators may send the message type.
+func isModeratorAction(messageType MessageType) bool {
+	_, exists := moderatorActions[messageType]
+	return exists
+}
+
+// moderatorManager manages the list of moderators in each channel. Moderators
+// are appointed and revoked by the channel admin with a Moderator message.
+type moderatorManager struct {
+	// List of moderators in each channel. The internal map keys on the hex
+	// encoded ed25519.PublicKey of the moderator.
+	list map[id.ID]map[string]struct{}
+
+	mux sync.RWMutex
+	kv  versioned.KV
+}
+
+// newOrLoadModeratorManager loads an existing moderatorManager from storage,
+// if it exists. Otherwise, it initialises a new empty moderatorManager.
+func newOrLoadModeratorManager(kv versioned.KV) (*moderatorManager, error) {
+	mm := &moderatorManager{
+		list: make(map[id.ID]map[string]struct{}),
+		kv:   kv,
+	}
+
+	err := mm.load()
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	return mm, nil
+}
+
+// addModerator adds the user to the moderator list for the given channel.
+func (mm *moderatorManager) addModerator(
+	channelID *id.ID, pubKey ed25519.PublicKey) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	if _, exists := mm.list[*channelID]; !exists {
+		mm.list[*channelID] = make(map[string]struct{})
+	}
+	mm.list[*channelID][hex.EncodeToString(pubKey)] = struct{}{}
+
+	if err := mm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save moderators: %+v", err)
+	}
+}
+
+// removeModerator removes the user from the moderator list for the given
+// channel.
+func (mm *moderatorManager) removeModerator(
+	channelID *id.ID, pubKey ed25519.PublicKey) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	moderators, exists := mm.list[*channelID]
+	if !exists {
+		return
+	}
+
+	delete(moderators, hex.EncodeToString(pubKey))
+	if len(moderators) == 0 {
+		delete(mm.list, *channelID)
+	}
+
+	if err := mm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save moderators: %+v", err)
+	}
+}
+
+// isModerator returns true if the user is a moderator in the given channel.
+func (mm *moderatorManager) isModerator(
+	channelID *id.ID, pubKey ed25519.PublicKey) bool {
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+
+	_, exists := mm.list[*channelID][hex.EncodeToString(pubKey)]
+	return exists
+}
+
+// getModerators returns the public key of each moderator in the given channel,
+// sorted. Returns an empty list if the channel has no moderators.
+func (mm *moderatorManager) getModerators(
+	channelID *id.ID) []ed25519.PublicKey {
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+
+	moderators := make([]ed25519.PublicKey, 0, len(mm.list[*channelID]))
+	for key := range mm.list[*channelID] {
+		pubKey, err := hex.DecodeString(key)
+		if err != nil {
+			jww.ERROR.Printf("[CH] Could not decode moderator public key in "+
+				"channel %s: %+v", channelID, err)
+			continue
+		}
+		moderators = append(moderators, pubKey)
+	}
+	sort.Slice(moderators, func(i, j int) bool {
+		return bytes.Compare(moderators[i], moderators[j]) < 0
+	})
+
+	return moderators
+}
+
+// removeChannel deletes the moderator list for the given channel. This should
+// only be called when leaving a channel.
+func (mm *moderatorManager) removeChannel(channelID *id.ID) error {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	if _, exists := mm.list[*channelID]; !exists {
+		return nil
+	}
+
+	delete(mm.list, *channelID)
+	return mm.save()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage values.
+const (
+	moderatorListStoreVer = 0
+	moderatorListStoreKey = "moderatorList"
+)
+
+// save stores the moderator list of every channel to storage.
+func (mm *moderatorManager) save() error {
+	data, err := json.Marshal(mm.list)
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   moderatorListStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return mm.kv.Set(moderatorListStoreKey, obj)
+}
+
+// load gets the moderator list of every channel from storage.
+func (mm *moderatorManager) load() error {
+	obj, err := mm.kv.Get(moderatorListStoreKey, moderatorListStoreVer)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(obj.Data, &mm.list); err != nil {
+		return errors.Wrap(err, "could not unmarshal moderator list")
+	}
+	return nil
+}
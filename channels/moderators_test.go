@@ -0,0 +1,181 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that newOrLoadModeratorManager initialises a new empty
+// moderatorManager when called for the first time and that it loads the
+// moderatorManager from storage after the original has been saved.
+func Test_newOrLoadModeratorManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	expected := &moderatorManager{
+		list: make(map[id.ID]map[string]struct{}),
+		kv:   kv,
+	}
+
+	mm, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Errorf("Failed to create new moderatorManager: %+v", err)
+	}
+
+	if !reflect.DeepEqual(expected, mm) {
+		t.Errorf("New moderatorManager does not match expected."+
+			"\nexpected: %+v\nreceived: %+v", expected, mm)
+	}
+
+	mm.addModerator(randChannelID(prng, t), makeEd25519PubKey(prng, t))
+
+	loadedMm, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Errorf("Failed to load moderatorManager: %+v", err)
+	}
+
+	if !reflect.DeepEqual(mm.list, loadedMm.list) {
+		t.Errorf("Loaded moderatorManager does not match expected."+
+			"\nexpected: %+v\nreceived: %+v", mm.list, loadedMm.list)
+	}
+}
+
+// Tests that moderatorManager.isModerator only returns true for users added
+// with moderatorManager.addModerator and not removed with
+// moderatorManager.removeModerator.
+func Test_moderatorManager_addModerator_removeModerator(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	mm, err := newOrLoadModeratorManager(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := make(map[id.ID][]ed25519.PublicKey)
+	for i := 0; i < 10; i++ {
+		channelID := randChannelID(prng, t)
+		expected[*channelID] = make([]ed25519.PublicKey, 20)
+		for j := range expected[*channelID] {
+			pubKey := makeEd25519PubKey(prng, t)
+			expected[*channelID][j] = pubKey
+			mm.addModerator(channelID, pubKey)
+			if j%2 != 0 {
+				mm.removeModerator(channelID, pubKey)
+			}
+		}
+	}
+
+	for channelID, pubKeys := range expected {
+		for j, pubKey := range pubKeys {
+			if j%2 == 0 && !mm.isModerator(&channelID, pubKey) {
+				t.Errorf("User %x in channel %s is not a moderator when they "+
+					"should be (%d).", pubKey, channelID, j)
+			} else if j%2 != 0 && mm.isModerator(&channelID, pubKey) {
+				t.Errorf("User %x in channel %s is a moderator when they "+
+					"should not be (%d).", pubKey, channelID, j)
+			}
+		}
+	}
+
+	// Check that removeModerator does nothing for a nonexistent channel and
+	// that isModerator returns false for it
+	channelID, pubKey := randChannelID(prng, t), makeEd25519PubKey(prng, t)
+	mm.removeModerator(channelID, pubKey)
+	if mm.isModerator(channelID, pubKey) {
+		t.Errorf("User is a moderator in channel that does not exist.")
+	}
+}
+
+// Tests that moderatorManager.getModerators returns the expected sorted list
+// of public keys and an empty list for a channel with no moderators.
+func Test_moderatorManager_getModerators(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	mm, err := newOrLoadModeratorManager(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelID := randChannelID(prng, t)
+	expected := make([]ed25519.PublicKey, 25)
+	for j := range expected {
+		expected[j] = makeEd25519PubKey(prng, t)
+		mm.addModerator(channelID, expected[j])
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i], expected[j]) < 0
+	})
+
+	moderators := mm.getModerators(channelID)
+	if !reflect.DeepEqual(expected, moderators) {
+		t.Errorf("Unexpected list of moderators."+
+			"\nexpected: %x\nreceived: %x", expected, moderators)
+	}
+
+	moderators = mm.getModerators(randChannelID(prng, t))
+	if !reflect.DeepEqual([]ed25519.PublicKey{}, moderators) {
+		t.Errorf("Unexpected list of moderators for unknown channel."+
+			"\nexpected: %x\nreceived: %x", []ed25519.PublicKey{}, moderators)
+	}
+}
+
+// Tests that moderatorManager.removeChannel removes the channel from the list
+// and from storage.
+func Test_moderatorManager_removeChannel(t *testing.T) {
+	prng := rand.New(rand.NewSource(189))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	mm, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelID := randChannelID(prng, t)
+	pubKey := makeEd25519PubKey(prng, t)
+	mm.addModerator(channelID, pubKey)
+
+	if err = mm.removeChannel(channelID); err != nil {
+		t.Fatalf("Failed to remove channel: %+v", err)
+	}
+
+	if _, exists := mm.list[*channelID]; exists {
+		t.Errorf("Channel not removed from list.")
+	}
+
+	loadedMm, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedMm.isModerator(channelID, pubKey) {
+		t.Errorf("Channel not removed from storage.")
+	}
+}
+
+// Tests that only delete, pin, and mute messages are moderator actions.
+func Test_isModeratorAction(t *testing.T) {
+	for mt, expected := range map[MessageType]bool{
+		Delete:    true,
+		Pinned:    true,
+		Mute:      true,
+		Text:      false,
+		AdminText: false,
+		Moderator: false,
+		Edit:      false,
+	} {
+		if isModeratorAction(mt) != expected {
+			t.Errorf("Unexpected result for %s.\nexpected: %t\nreceived: %t",
+				mt, expected, !expected)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Verify that multiEventModel adheres to the EventModel interface and its
+// optional extensions.
+var (
+	_ EventModel       = (*multiEventModel)(nil)
+	_ MessageQuerier   = (*multiEventModel)(nil)
+	_ PollTallier      = (*multiEventModel)(nil)
+	_ Searcher         = (*multiEventModel)(nil)
+	_ TextSpanReceiver = (*multiEventModel)(nil)
+)
+
+// multiEventModel is an EventModel that forwards every event to several event
+// models. The first model is the primary; its return values and errors are
+// returned to the caller. Errors from the other models are logged.
+type multiEventModel struct {
+	primary   EventModel
+	secondary []EventModel
+
+	// uuids maps the UUID of a pending message in the primary model to its
+	// UUID in each secondary model. Only messages received with the Unsent
+	// status are tracked, since they are the only ones updated by UUID, and
+	// they are removed once they are Delivered or Failed.
+	uuids map[uint64][]uint64
+	mux   sync.Mutex
+}
+
+// NewMultiEventModel returns an EventModel that forwards every event to each of
+// the given event models, such as a database, a metrics sink, and a webhook
+// bridge. Events are forwarded in order.
+//
+// The first model is the primary model. The UUIDs, messages, and errors
+// returned to the Manager come from it, while errors from the other models are
+// only logged.
+//
+// Optional extensions of EventModel are forwarded as well. Queries made with
+// MessageQuerier, PollTallier, and Searcher are answered by the primary model;
+// UnsupportedExtensionErr is returned if it does not implement them. Text spans
+// are passed to every model that implements TextSpanReceiver.
+//
+// Each model assigns its own UUIDs, so the composite model keeps a map from the
+// primary's UUID to the others' for messages that are pending send. The map is
+// held in memory; pending sends that are updated after a restart only update
+// the primary model.
+//
+// Use WrapEventModel to filter or transform the events that reach an
+// individual model.
+func NewMultiEventModel(primary EventModel, others ...EventModel) EventModel {
+	return &multiEventModel{
+		primary:   primary,
+		secondary: others,
+		uuids:     make(map[uint64][]uint64),
+	}
+}
+
+// JoinChannel is called whenever a channel is joined locally.
+func (mm *multiEventModel) JoinChannel(channel *cryptoBroadcast.Channel) {
+	mm.primary.JoinChannel(channel)
+	for _, model := range mm.secondary {
+		model.JoinChannel(channel)
+	}
+}
+
+// LeaveChannel is called whenever a channel is left locally.
+func (mm *multiEventModel) LeaveChannel(channelID *id.ID) {
+	mm.primary.LeaveChannel(channelID)
+	for _, model := range mm.secondary {
+		model.LeaveChannel(channelID)
+	}
+}
+
+// ReceiveMessage is called whenever a message is received on a given channel.
+func (mm *multiEventModel) ReceiveMessage(channelID *id.ID,
+	messageID message.ID, nickname, text string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	return mm.receive(status, func(model EventModel, secondary bool) uint64 {
+		uuid := model.ReceiveMessage(channelID, messageID, nickname, text,
+			pubKey, dmToken, codeset, timestamp, lease, round, messageType,
+			status, hidden)
+		if secondary && messageType == Text {
+			receiveTextSpans(model, uuid, text, false)
+		}
+		return uuid
+	})
+}
+
+// ReceiveReply is called whenever a message is received that is a reply on a
+// given channel.
+func (mm *multiEventModel) ReceiveReply(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, text string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	return mm.receive(status, func(model EventModel, secondary bool) uint64 {
+		uuid := model.ReceiveReply(channelID, messageID, reactionTo, nickname,
+			text, pubKey, dmToken, codeset, timestamp, lease, round,
+			messageType, status, hidden)
+		if secondary && messageType == Text {
+			receiveTextSpans(model, uuid, text, false)
+		}
+		return uuid
+	})
+}
+
+// ReceiveReaction is called whenever a reaction to a message is received on a
+// given channel.
+func (mm *multiEventModel) ReceiveReaction(channelID *id.ID, messageID,
+	reactionTo message.ID, nickname, reaction string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	return mm.receive(status, func(model EventModel, _ bool) uint64 {
+		return model.ReceiveReaction(channelID, messageID, reactionTo,
+			nickname, reaction, pubKey, dmToken, codeset, timestamp, lease,
+			round, messageType, status, hidden)
+	})
+}
+
+// receive calls the receive function on every model and returns the UUID from
+// the primary model. The UUIDs from the other models are saved if the message
+// is pending send so that UpdateFromUUID can be forwarded.
+//
+// The Manager only passes text spans to the primary model, since it only knows
+// its UUIDs, so the receive function is told whether the model is a secondary
+// model that it must pass the spans to itself.
+func (mm *multiEventModel) receive(status SentStatus,
+	receive func(model EventModel, secondary bool) uint64) uint64 {
+	uuid := receive(mm.primary, false)
+
+	uuids := make([]uint64, len(mm.secondary))
+	for i, model := range mm.secondary {
+		uuids[i] = receive(model, true)
+	}
+
+	if status == Unsent && uuid != 0 && len(mm.secondary) > 0 {
+		mm.mux.Lock()
+		mm.uuids[uuid] = uuids
+		mm.mux.Unlock()
+	}
+
+	return uuid
+}
+
+// UpdateFromUUID is called whenever a message at the UUID is modified. The
+// update is forwarded to the other models using their UUID for the message.
+func (mm *multiEventModel) UpdateFromUUID(uuid uint64, messageID *message.ID,
+	timestamp *time.Time, round *rounds.Round, pinned, hidden *bool,
+	status *SentStatus) error {
+	mm.mux.Lock()
+	uuids, exists := mm.uuids[uuid]
+	if status != nil && (*status == Delivered || *status == Failed) {
+		delete(mm.uuids, uuid)
+	}
+	mm.mux.Unlock()
+
+	err := mm.primary.UpdateFromUUID(
+		uuid, messageID, timestamp, round, pinned, hidden, status)
+
+	if !exists && len(mm.secondary) > 0 {
+		jww.WARN.Printf("[CH] Multi event model has no UUIDs for message %d; "+
+			"only updating the primary model", uuid)
+	}
+
+	for i, model := range mm.secondary {
+		if !exists || uuids[i] == 0 {
+			continue
+		}
+		err2 := model.UpdateFromUUID(
+			uuids[i], messageID, timestamp, round, pinned, hidden, status)
+		if err2 != nil {
+			jww.ERROR.Printf("[CH] Failed to update message %d in event "+
+				"model %d: %+v", uuids[i], i+1, err2)
+		}
+	}
+
+	return err
+}
+
+// UpdateFromMessageID is called whenever a message with the message ID is
+// modified.
+func (mm *multiEventModel) UpdateFromMessageID(messageID message.ID,
+	timestamp *time.Time, round *rounds.Round, pinned, hidden *bool,
+	status *SentStatus) (uint64, error) {
+	uuid, err := mm.primary.UpdateFromMessageID(
+		messageID, timestamp, round, pinned, hidden, status)
+	for i, model := range mm.secondary {
+		_, err2 := model.UpdateFromMessageID(
+			messageID, timestamp, round, pinned, hidden, status)
+		mm.logErr(i, "update message", messageID, err2)
+	}
+	return uuid, err
+}
+
+// GetMessage returns the message with the given [message.ID] from the primary
+// model.
+func (mm *multiEventModel) GetMessage(messageID message.ID) (ModelMessage, error) {
+	return mm.primary.GetMessage(messageID)
+}
+
+// DeleteMessage deletes the message with the given [message.ID] from every
+// model.
+func (mm *multiEventModel) DeleteMessage(messageID message.ID) error {
+	err := mm.primary.DeleteMessage(messageID)
+	for i, model := range mm.secondary {
+		mm.logErr(i, "delete message", messageID, model.DeleteMessage(messageID))
+	}
+	return err
+}
+
+// MuteUser is called whenever a user is muted or unmuted.
+func (mm *multiEventModel) MuteUser(
+	channelID *id.ID, pubKey ed25519.PublicKey, unmute bool) {
+	mm.primary.MuteUser(channelID, pubKey, unmute)
+	for _, model := range mm.secondary {
+		model.MuteUser(channelID, pubKey, unmute)
+	}
+}
+
+// EditMessage is called whenever the text of the message with the given
+// [message.ID] is edited.
+func (mm *multiEventModel) EditMessage(
+	messageID message.ID, edit MessageEdit) (uint64, error) {
+	uuid, err := mm.primary.EditMessage(messageID, edit)
+	for i, model := range mm.secondary {
+		uuid2, err2 := model.EditMessage(messageID, edit)
+		mm.logErr(i, "edit message", messageID, err2)
+		if err2 != nil {
+			continue
+		} else if _, ok := model.(TextSpanReceiver); !ok {
+			continue
+		}
+
+		// Parse the current text of the message, since edits may arrive out
+		// of order
+		msg, err2 := model.GetMessage(messageID)
+		if err2 != nil {
+			mm.logErr(i, "get edited message", messageID, err2)
+			continue
+		}
+		receiveTextSpans(model, uuid2, string(msg.Content), true)
+	}
+	return uuid, err
+}
+
+// ReceivePollVote is called whenever a vote is received for the poll with the
+// given [message.ID].
+func (mm *multiEventModel) ReceivePollVote(
+	pollID message.ID, vote ModelPollVote) (uint64, error) {
+	uuid, err := mm.primary.ReceivePollVote(pollID, vote)
+	for i, model := range mm.secondary {
+		_, err2 := model.ReceivePollVote(pollID, vote)
+		mm.logErr(i, "receive vote for poll", pollID, err2)
+	}
+	return uuid, err
+}
+
+// ClosePoll is called whenever the channel admin closes or reopens the poll
+// with the given [message.ID].
+func (mm *multiEventModel) ClosePoll(
+	pollID message.ID, timestamp time.Time, reopen bool) (uint64, error) {
+	uuid, err := mm.primary.ClosePoll(pollID, timestamp, reopen)
+	for i, model := range mm.secondary {
+		_, err2 := model.ClosePoll(pollID, timestamp, reopen)
+		mm.logErr(i, "close poll", pollID, err2)
+	}
+	return uuid, err
+}
+
+// UpdateChannelMetadata is called whenever the channel admin updates the
+// metadata of the channel.
+func (mm *multiEventModel) UpdateChannelMetadata(
+	channelID *id.ID, metadata ChannelMetadata) {
+	mm.primary.UpdateChannelMetadata(channelID, metadata)
+	for _, model := range mm.secondary {
+		model.UpdateChannelMetadata(channelID, metadata)
+	}
+}
+
+// logErr logs the error returned by the secondary model at index i, if there
+// is one. A missing message is only logged at the debug level, since models
+// that filter events may not have every message.
+func (mm *multiEventModel) logErr(
+	i int, action string, messageID message.ID, err error) {
+	if err == nil {
+		return
+	} else if CheckNoMessageErr(err) {
+		jww.DEBUG.Printf("[CH] Failed to %s %s in event model %d: %+v",
+			action, messageID, i+1, err)
+		return
+	}
+	jww.ERROR.Printf("[CH] Failed to %s %s in event model %d: %+v",
+		action, messageID, i+1, err)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Optional Extensions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// QueryMessages returns the messages that match the MessageQuery from the
+// primary model. Returns UnsupportedExtensionErr if the primary model does not
+// implement MessageQuerier.
+func (mm *multiEventModel) QueryMessages(
+	query MessageQuery) ([]ModelMessage, int64, error) {
+	querier, ok := mm.primary.(MessageQuerier)
+	if !ok {
+		return nil, 0, errors.Wrapf(UnsupportedExtensionErr,
+			"primary model %T does not implement MessageQuerier", mm.primary)
+	}
+	return querier.QueryMessages(query)
+}
+
+// GetPollResults returns the tally of the votes of the poll from the primary
+// model. Returns UnsupportedExtensionErr if the primary model does not
+// implement PollTallier.
+func (mm *multiEventModel) GetPollResults(
+	pollID message.ID) (PollResults, error) {
+	tallier, ok := mm.primary.(PollTallier)
+	if !ok {
+		return PollResults{}, errors.Wrapf(UnsupportedExtensionErr,
+			"primary model %T does not implement PollTallier", mm.primary)
+	}
+	return tallier.GetPollResults(pollID)
+}
+
+// Search returns the messages that match the SearchQuery from the primary
+// model. Returns UnsupportedExtensionErr if the primary model does not
+// implement Searcher.
+func (mm *multiEventModel) Search(query SearchQuery) ([]ModelMessage, error) {
+	searcher, ok := mm.primary.(Searcher)
+	if !ok {
+		return nil, errors.Wrapf(UnsupportedExtensionErr,
+			"primary model %T does not implement Searcher", mm.primary)
+	}
+	return searcher.Search(query)
+}
+
+// ReceiveTextSpans passes the spans to the primary model, if it implements
+// TextSpanReceiver. The UUID is the primary model's, so the secondary models
+// are passed their spans when the message is received or edited.
+func (mm *multiEventModel) ReceiveTextSpans(uuid uint64, spans []TextSpan) {
+	if tsr, ok := mm.primary.(TextSpanReceiver); ok {
+		tsr.ReceiveTextSpans(uuid, spans)
+	}
+}
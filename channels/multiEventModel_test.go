@@ -0,0 +1,219 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that multiEventModel forwards received messages and updates to every
+// model and returns the UUID of the primary model.
+func Test_multiEventModel_Receive(t *testing.T) {
+	primary := &uuidRecorder{MockEvent: MockEvent{uuid: 1}}
+	secondary := &uuidRecorder{MockEvent: MockEvent{uuid: 100}}
+	mm := NewMultiEventModel(primary, secondary)
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	msgID := message.DeriveChannelMessageID(channelID, 1, []byte("text"))
+	uuid := mm.ReceiveMessage(channelID, msgID, "nickname", "text", nil, 0, 0,
+		time.Unix(0, 0), 0, rounds.Round{}, Text, Unsent, false)
+	if uuid != 1 {
+		t.Errorf("Unexpected UUID.\nexpected: %d\nreceived: %d", 1, uuid)
+	}
+	for i, model := range []*uuidRecorder{primary, secondary} {
+		if model.messageID != msgID || string(model.content) != "text" {
+			t.Errorf("Model %d did not receive message: %+v",
+				i, model.eventReceive)
+		}
+	}
+
+	// Each model must be updated with its own UUID for the message
+	status := Delivered
+	err := mm.UpdateFromUUID(uuid, nil, nil, nil, nil, nil, &status)
+	if err != nil {
+		t.Fatalf("Failed to update message: %+v", err)
+	}
+	if !reflect.DeepEqual([]uint64{1}, primary.updated) {
+		t.Errorf("Unexpected primary UUIDs: %v", primary.updated)
+	}
+	if !reflect.DeepEqual([]uint64{100}, secondary.updated) {
+		t.Errorf("Unexpected secondary UUIDs: %v", secondary.updated)
+	}
+	if secondary.status != Delivered {
+		t.Errorf("Secondary model not updated: %s", secondary.status)
+	}
+
+	// The UUIDs are forgotten once the message is delivered
+	if _, exists := mm.(*multiEventModel).uuids[uuid]; exists {
+		t.Errorf("UUID %d not removed after delivery.", uuid)
+	}
+}
+
+// Tests that multiEventModel only tracks the UUIDs of messages pending send.
+func Test_multiEventModel_receive_NotPending(t *testing.T) {
+	mm := NewMultiEventModel(&MockEvent{uuid: 1}, &MockEvent{uuid: 100})
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	mm.ReceiveReaction(channelID, message.ID{1}, message.ID{2}, "nickname",
+		"👍", nil, 0, 0, time.Unix(0, 0), 0, rounds.Round{}, Reaction,
+		Delivered, false)
+
+	if n := len(mm.(*multiEventModel).uuids); n != 0 {
+		t.Errorf("Tracked %d UUIDs for received message.", n)
+	}
+}
+
+// Tests that multiEventModel returns errors from the primary model only.
+func Test_multiEventModel_Errors(t *testing.T) {
+	primary := &MockEvent{}
+	mm := NewMultiEventModel(primary, &noMessageEventModel{})
+
+	uuid, err := mm.EditMessage(message.ID{1}, MessageEdit{Text: "edit"})
+	if err != nil {
+		t.Errorf("Returned error from secondary model: %+v", err)
+	} else if string(primary.content) != "edit" {
+		t.Errorf("Primary model not edited: %q", primary.content)
+	}
+
+	mm = NewMultiEventModel(&noMessageEventModel{}, primary)
+	uuid, err = mm.EditMessage(message.ID{1}, MessageEdit{Text: "edit"})
+	if !CheckNoMessageErr(err) {
+		t.Errorf("Did not return error from primary model: %+v", err)
+	} else if uuid != 0 {
+		t.Errorf("Unexpected UUID: %d", uuid)
+	}
+}
+
+// Tests that multiEventModel passes the text spans of received and edited
+// messages to every model that implements TextSpanReceiver using the model's
+// own UUID for the message.
+func Test_multiEventModel_ReceiveTextSpans(t *testing.T) {
+	primary := &mockSpanEvent{MockEvent: MockEvent{uuid: 1},
+		spans: make(map[uint64][]TextSpan)}
+	secondary := &mockSpanEvent{MockEvent: MockEvent{uuid: 100},
+		spans: make(map[uint64][]TextSpan)}
+	mm := NewMultiEventModel(primary, &MockEvent{uuid: 50}, secondary)
+
+	// The Manager passes the spans to the composite model with the UUID
+	// returned by the primary model
+	text := "**hi**"
+	channelID := id.NewIdFromString("channel", id.User, t)
+	uuid := mm.ReceiveMessage(channelID, message.ID{1}, "nickname", text, nil,
+		0, 0, time.Unix(0, 0), 0, rounds.Round{}, Text, Delivered, false)
+	mm.(TextSpanReceiver).ReceiveTextSpans(uuid, ParseText(text))
+
+	expected := map[uint64][]TextSpan{1: ParseText(text)}
+	if !reflect.DeepEqual(expected, primary.spans) {
+		t.Errorf("Unexpected primary spans.\nexpected: %+v\nreceived: %+v",
+			expected, primary.spans)
+	}
+	expected = map[uint64][]TextSpan{100: ParseText(text)}
+	if !reflect.DeepEqual(expected, secondary.spans) {
+		t.Errorf("Unexpected secondary spans.\nexpected: %+v\nreceived: %+v",
+			expected, secondary.spans)
+	}
+
+	// Edits replace the spans, even if the new text has none
+	if _, err := mm.EditMessage(message.ID{1}, MessageEdit{Text: "hi"}); err != nil {
+		t.Fatalf("Failed to edit message: %+v", err)
+	}
+	if spans, exists := secondary.spans[101]; !exists || len(spans) != 0 {
+		t.Errorf("Spans of edited message not replaced: %+v", secondary.spans)
+	}
+}
+
+// Tests that multiEventModel answers queries with the primary model and
+// returns UnsupportedExtensionErr if the primary model cannot answer them.
+func Test_multiEventModel_QueryMessages(t *testing.T) {
+	channelID := id.NewIdFromString("channel", id.User, t)
+	querier := &mockQuerierEvent{msgs: []ModelMessage{
+		{MessageID: message.ID{1}, ChannelID: channelID}}}
+	mm := NewMultiEventModel(querier, &MockEvent{})
+
+	msgs, n, err := mm.(MessageQuerier).QueryMessages(
+		MessageQuery{ChannelID: channelID})
+	if err != nil {
+		t.Fatalf("Failed to query messages: %+v", err)
+	} else if n != 1 || !reflect.DeepEqual(querier.msgs, msgs) {
+		t.Errorf("Unexpected messages (%d).\nexpected: %+v\nreceived: %+v",
+			n, querier.msgs, msgs)
+	}
+
+	mm = NewMultiEventModel(&MockEvent{}, querier)
+	_, _, err = mm.(MessageQuerier).QueryMessages(
+		MessageQuery{ChannelID: channelID})
+	if !errors.Is(err, UnsupportedExtensionErr) {
+		t.Errorf("Unexpected error for primary model without queries."+
+			"\nexpected: %v\nreceived: %+v", UnsupportedExtensionErr, err)
+	}
+}
+
+// Tests that multiEventModel returns poll results and search results from the
+// primary model and returns UnsupportedExtensionErr if the primary model does
+// not implement PollTallier or Searcher.
+func Test_multiEventModel_PollTallier_Searcher(t *testing.T) {
+	mm := NewMultiEventModel(&queryEvent{}, &MockEvent{})
+	results, err := mm.(PollTallier).GetPollResults(message.ID{1})
+	if err != nil || !reflect.DeepEqual([]uint64{1, 2}, results.Counts) {
+		t.Errorf("Unexpected poll results %+v: %+v", results, err)
+	}
+	msgs, err := mm.(Searcher).Search(SearchQuery{Text: "text"})
+	if err != nil || len(msgs) != 1 {
+		t.Errorf("Unexpected search results %+v: %+v", msgs, err)
+	}
+
+	mm = NewMultiEventModel(&MockEvent{}, &queryEvent{})
+	if _, err = mm.(PollTallier).GetPollResults(
+		message.ID{1}); !errors.Is(err, UnsupportedExtensionErr) {
+		t.Errorf("Unexpected poll results error: %+v", err)
+	}
+	if _, err = mm.(Searcher).Search(
+		SearchQuery{Text: "text"}); !errors.Is(err, UnsupportedExtensionErr) {
+		t.Errorf("Unexpected search error: %+v", err)
+	}
+}
+
+// queryEvent is a MockEvent that implements PollTallier and Searcher.
+type queryEvent struct{ MockEvent }
+
+func (*queryEvent) GetPollResults(message.ID) (PollResults, error) {
+	return PollResults{Counts: []uint64{1, 2}}, nil
+}
+
+func (*queryEvent) Search(SearchQuery) ([]ModelMessage, error) {
+	return []ModelMessage{{MessageID: message.ID{1}}}, nil
+}
+
+// uuidRecorder is a MockEvent that records the UUIDs passed to UpdateFromUUID.
+type uuidRecorder struct {
+	MockEvent
+	updated []uint64
+}
+
+func (u *uuidRecorder) UpdateFromUUID(uuid uint64, messageID *message.ID,
+	timestamp *time.Time, round *rounds.Round, pinned, hidden *bool,
+	status *SentStatus) error {
+	u.updated = append(u.updated, uuid)
+	return u.MockEvent.UpdateFromUUID(
+		uuid, messageID, timestamp, round, pinned, hidden, status)
+}
+
+// noMessageEventModel is a MockEvent that does not have any messages.
+type noMessageEventModel struct{ MockEvent }
+
+func (*noMessageEventModel) EditMessage(message.ID, MessageEdit) (uint64, error) {
+	return 0, NoMessageErr
+}
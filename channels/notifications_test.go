@@ -770,6 +770,27 @@ func (m *mockChannel) BroadcastRSAToPublicWithAssembler(
 	[]byte, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannel) MaxMultipartPayloadSize() int            { panic("implement me") }
+func (m *mockChannel) MaxRSAToPublicMultipartPayloadSize() int { panic("implement me") }
+func (m *mockChannel) BroadcastMultipart([]byte, []string, [2]byte,
+	cmix.CMIXParams) (rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannel) BroadcastMultipartWithAssembler(
+	broadcast.Assembler, []string, [2]byte, cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannel) BroadcastRSAToPublicMultipart(
+	rsa.PrivateKey, []byte, []string, [2]byte, cmix.CMIXParams) (
+	[]byte, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannel) BroadcastRSAToPublicMultipartWithAssembler(
+	rsa.PrivateKey, broadcast.Assembler, []string, [2]byte, cmix.CMIXParams) (
+	[]byte, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
 func (m *mockChannel) RegisterRSAtoPublicListener(
 	broadcast.ListenerFunc, []string) (broadcast.Processor, error) {
 	panic("implement me")
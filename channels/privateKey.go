@@ -112,7 +112,16 @@ func (m *manager) ImportChannelAdminKey(
 		return WrongPrivateKeyErr
 	}
 
-	c, err := m.getChannel(decryptedChannelID)
+	return m.importChannelAdminKey(channelID, pk)
+}
+
+// importChannelAdminKey saves the private key for the channel once it is
+// verified to be the channel's admin key. Returns the error
+// ChannelDoesNotExistsErr if the channel has not been joined and
+// WrongPrivateKeyErr if the key does not belong to the channel.
+func (m *manager) importChannelAdminKey(
+	channelID *id.ID, pk rsa.PrivateKey) error {
+	c, err := m.getChannel(channelID)
 	if err != nil {
 		return err
 	}
@@ -152,6 +161,11 @@ type adminKeysManager struct {
 	callback func(ch *id.ID, isAdmin bool)
 	remote   versioned.KV
 	mux      sync.RWMutex
+
+	// Admin keys transferred to this user for channels that have not been
+	// joined yet. They are only kept in memory.
+	pending    map[id.ID]rsa.PrivateKey
+	pendingMux sync.Mutex
 }
 
 // newAdminKeysManager is a constructor for the adminKeysManager.
@@ -163,7 +177,11 @@ func newAdminKeysManager(
 		jww.FATAL.Panicf("[CH] Admin keys failed to prefix KV: %+v", err)
 	}
 
-	adminMan := &adminKeysManager{remote: kvRemote, callback: cb}
+	adminMan := &adminKeysManager{
+		remote:   kvRemote,
+		callback: cb,
+		pending:  make(map[id.ID]rsa.PrivateKey),
+	}
 
 	err = adminMan.remote.ListenOnRemoteMap(
 		adminKeysMapName, adminKeysMapVersion, adminMan.mapUpdate, false)
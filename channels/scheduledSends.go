@@ -0,0 +1,227 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/scheduler"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// scheduledSendsStoreKey is the storage key of the channel scheduler.
+const scheduledSendsStoreKey = "scheduledSends"
+
+// ScheduledSend describes a message that is queued to be sent to a channel at
+// a future time.
+type ScheduledSend struct {
+	// ID uniquely identifies the scheduled send. It is used to cancel or
+	// reschedule the send.
+	ID uint64 `json:"id"`
+
+	// ChannelID is the ID of the channel the message is sent to.
+	ChannelID *id.ID `json:"channelID"`
+
+	// MessageType is the type of the message.
+	MessageType MessageType `json:"messageType"`
+
+	// Payload is the message content passed to Manager.SendGeneric.
+	Payload []byte `json:"payload"`
+
+	// ValidUntil is the lease of the message once it is sent.
+	ValidUntil time.Duration `json:"validUntil"`
+
+	// Params are the cMix parameters used when sending the message.
+	Params cmix.CMIXParams `json:"params"`
+
+	// Pings are the users that are notified of the message.
+	Pings map[PingType][]ed25519.PublicKey `json:"pings,omitempty"`
+
+	// SendAt is the time the message is sent at. If the network is not
+	// healthy at that time, the message is sent once it becomes healthy.
+	SendAt time.Time `json:"sendAt"`
+}
+
+// scheduledSendDetails is everything in a ScheduledSend, other than the
+// channel and the time, that is saved in [scheduler.Send.Details].
+type scheduledSendDetails struct {
+	MessageType MessageType                      `json:"messageType"`
+	Payload     []byte                           `json:"payload"`
+	ValidUntil  time.Duration                    `json:"validUntil"`
+	Params      cmix.CMIXParams                  `json:"params"`
+	Pings       map[PingType][]ed25519.PublicKey `json:"pings,omitempty"`
+}
+
+// newScheduledSend builds a ScheduledSend from the send saved in the
+// scheduler.
+func newScheduledSend(s scheduler.Send) (ScheduledSend, error) {
+	channelID, err := id.Unmarshal(s.Conversation)
+	if err != nil {
+		return ScheduledSend{}, errors.Wrapf(err,
+			"could not unmarshal channel ID of scheduled send %d", s.ID)
+	}
+
+	var details scheduledSendDetails
+	if err = json.Unmarshal(s.Details, &details); err != nil {
+		return ScheduledSend{}, errors.Wrapf(err,
+			"could not unmarshal scheduled send %d", s.ID)
+	}
+
+	return ScheduledSend{
+		ID:          s.ID,
+		ChannelID:   channelID,
+		MessageType: details.MessageType,
+		Payload:     details.Payload,
+		ValidUntil:  details.ValidUntil,
+		Params:      details.Params,
+		Pings:       details.Pings,
+		SendAt:      s.SendAt,
+	}, nil
+}
+
+// newScheduledSends builds a ScheduledSend from each send saved in the
+// scheduler. Sends that cannot be decoded are skipped.
+func newScheduledSends(sends []scheduler.Send) []ScheduledSend {
+	list := make([]ScheduledSend, 0, len(sends))
+	for _, s := range sends {
+		ss, err := newScheduledSend(s)
+		if err != nil {
+			jww.ERROR.Printf("[CH] %+v", err)
+			continue
+		}
+		list = append(list, ss)
+	}
+	return list
+}
+
+// ScheduleSend queues a raw message to be sent to the channel at sendAt. When
+// the time is reached, the message is sent via Manager.SendGeneric and the
+// result is reported via UiCallbacks.ScheduledSendUpdate. The message is
+// tracked by the SendTracker once it is sent.
+//
+// Returns ChannelDoesNotExistsErr if the channel has not been joined and
+// InvalidScheduleTimeErr if sendAt is not in the future.
+func (m *manager) ScheduleSend(channelID *id.ID, messageType MessageType,
+	msg []byte, sendAt time.Time, validUntil time.Duration,
+	params cmix.CMIXParams, pings map[PingType][]ed25519.PublicKey) (
+	ScheduledSend, error) {
+	jww.INFO.Printf("[CH] ScheduleSend %s message to channel %s at %s",
+		messageType, channelID, sendAt)
+
+	if _, err := m.getChannel(channelID); err != nil {
+		return ScheduledSend{}, err
+	}
+
+	details, err := json.Marshal(scheduledSendDetails{
+		MessageType: messageType,
+		Payload:     msg,
+		ValidUntil:  validUntil,
+		Params:      params,
+		Pings:       pings,
+	})
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	s, err := m.scheduler.Add(channelID.Marshal(), details, sendAt)
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	return newScheduledSend(s)
+}
+
+// ScheduleMessage queues a text message to be sent to the channel at sendAt.
+// See Manager.SendMessage for details on the message and Manager.ScheduleSend
+// for details on scheduling.
+func (m *manager) ScheduleMessage(channelID *id.ID, msg string,
+	sendAt time.Time, validUntil time.Duration, params cmix.CMIXParams,
+	pings []ed25519.PublicKey) (ScheduledSend, error) {
+	txt := &CMIXChannelText{
+		Version: cmixChannelTextVersion,
+		Text:    msg,
+	}
+
+	txtMarshaled, err := proto.Marshal(txt)
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	pings = addMentionPings(msg, pings, m.me.PubKey)
+	pingMap := map[PingType][]ed25519.PublicKey{MentionPing: pings}
+
+	return m.ScheduleSend(
+		channelID, Text, txtMarshaled, sendAt, validUntil, params, pingMap)
+}
+
+// GetScheduledSends returns the messages queued to be sent to the channel,
+// sorted by the time they are sent. If channelID is nil, then the messages
+// queued for all channels are returned.
+func (m *manager) GetScheduledSends(channelID *id.ID) []ScheduledSend {
+	var conversation []byte
+	if channelID != nil {
+		conversation = channelID.Marshal()
+	}
+	return newScheduledSends(m.scheduler.Get(conversation))
+}
+
+// CancelScheduledSend removes the message from the queue so that it is never
+// sent. Returns ScheduledSendNotFoundErr if the message does not exist or was
+// already sent.
+func (m *manager) CancelScheduledSend(sendID uint64) error {
+	jww.INFO.Printf("[CH] CancelScheduledSend %d", sendID)
+	return m.scheduler.Cancel(sendID)
+}
+
+// RescheduleSend changes the time the queued message is sent at. Returns
+// ScheduledSendNotFoundErr if the message does not exist or was already sent
+// and InvalidScheduleTimeErr if sendAt is not in the future.
+func (m *manager) RescheduleSend(sendID uint64, sendAt time.Time) error {
+	jww.INFO.Printf("[CH] RescheduleSend %d to %s", sendID, sendAt)
+	_, err := m.scheduler.Reschedule(sendID, sendAt)
+	return err
+}
+
+// removeScheduledSends cancels all messages queued to be sent to the channel
+// and reports each one as failed with ChannelDoesNotExistsErr.
+func (m *manager) removeScheduledSends(channelID *id.ID) error {
+	removed, err := m.scheduler.RemoveConversation(channelID.Marshal())
+	for _, s := range removed {
+		go m.scheduledSendUpdate(
+			s.ID, channelID, message.ID{}, ChannelDoesNotExistsErr)
+	}
+	return err
+}
+
+// sendScheduled sends the scheduled message through the normal send path and
+// reports the result via UiCallbacks.ScheduledSendUpdate.
+func (m *manager) sendScheduled(s scheduler.Send) {
+	ss, err := newScheduledSend(s)
+	if err != nil {
+		jww.ERROR.Printf("[CH] Failed to send scheduled send: %+v", err)
+		return
+	}
+
+	jww.INFO.Printf("[CH] Sending scheduled send %d to channel %s "+
+		"scheduled for %s", ss.ID, ss.ChannelID, ss.SendAt)
+	messageID, _, _, err := m.SendGeneric(ss.ChannelID, ss.MessageType,
+		ss.Payload, ss.ValidUntil, true, ss.Params, ss.Pings)
+	if err != nil {
+		jww.ERROR.Printf("[CH] Failed to send scheduled send %d to channel "+
+			"%s: %+v", ss.ID, ss.ChannelID, err)
+	}
+	m.scheduledSendUpdate(ss.ID, ss.ChannelID, messageID, err)
+}
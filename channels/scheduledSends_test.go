@@ -0,0 +1,122 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/scheduler"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a ScheduledSend added via manager.ScheduleSend is returned by
+// manager.GetScheduledSends with all of its fields after the scheduler is
+// reloaded from storage.
+func Test_manager_ScheduleSend_Load(t *testing.T) {
+	prng := rand.New(rand.NewSource(84))
+	m := newTestManager(t)
+
+	channelIDs := []*id.ID{randChannelID(prng, t), randChannelID(prng, t)}
+	for _, channelID := range channelIDs {
+		m.channels[*channelID] = &joinedChannel{}
+	}
+
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	pings := map[PingType][]ed25519.PublicKey{ReplyPing: {pubKey}}
+	sendAt := netTime.Now().Add(time.Hour)
+	var expected []ScheduledSend
+	for i, offset := range []time.Duration{3, 1, 2} {
+		ss, err := m.ScheduleSend(channelIDs[0], Text, []byte("hello"),
+			sendAt.Add(offset*time.Minute), time.Duration(i)*time.Minute,
+			cmix.GetDefaultCMIXParams(), pings)
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), ss.ID)
+		expected = append(expected, ss)
+	}
+	expected = []ScheduledSend{expected[1], expected[2], expected[0]}
+
+	var err error
+	m.scheduler, err = scheduler.NewOrLoad(m.local, scheduledSendsStoreKey,
+		new(mockBroadcastClient), m.sendScheduled)
+	require.NoError(t, err)
+	received := m.GetScheduledSends(channelIDs[0])
+	require.Len(t, received, len(expected))
+	for i, ss := range received {
+		// The stoppable in the cMix params is not saved
+		require.Equal(t, expected[i].Params.DebugTag, ss.Params.DebugTag)
+		ss.Params = expected[i].Params
+		require.Equal(t, expected[i], ss)
+	}
+
+	require.Empty(t, m.GetScheduledSends(channelIDs[1]))
+	require.Len(t, m.GetScheduledSends(nil), len(expected))
+}
+
+// Tests that manager.removeScheduledSends removes only the sends to the channel
+// and reports each one as failed with ChannelDoesNotExistsErr.
+func Test_manager_removeScheduledSends(t *testing.T) {
+	prng := rand.New(rand.NewSource(84))
+	m := newTestManager(t)
+	updates := make(chan error, 10)
+	m.scheduledSendUpdate = func(_ uint64, _ *id.ID, _ message.ID, err error) {
+		updates <- err
+	}
+
+	channelIDs := []*id.ID{randChannelID(prng, t), randChannelID(prng, t)}
+	for _, channelID := range channelIDs {
+		m.channels[*channelID] = &joinedChannel{}
+	}
+	sendAt := netTime.Now().Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		_, err := m.ScheduleSend(channelIDs[i%2], Text, []byte("hello"),
+			sendAt, ValidForever, cmix.GetDefaultCMIXParams(), nil)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, m.removeScheduledSends(channelIDs[0]))
+	require.Empty(t, m.GetScheduledSends(channelIDs[0]))
+	require.Len(t, m.GetScheduledSends(channelIDs[1]), 2)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-updates:
+			require.ErrorIs(t, err, ChannelDoesNotExistsErr)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for update %d.", i)
+		}
+	}
+}
+
+// Error path: Tests that manager.ScheduleSend returns an error for a channel
+// that has not been joined and for a time that is not in the future.
+func Test_manager_ScheduleSend_Errors(t *testing.T) {
+	prng := rand.New(rand.NewSource(84))
+	m := newTestManager(t)
+
+	channelID := randChannelID(prng, t)
+	_, err := m.ScheduleSend(channelID, Text, []byte("hello"),
+		netTime.Now().Add(time.Hour), ValidForever,
+		cmix.GetDefaultCMIXParams(), nil)
+	require.ErrorIs(t, err, ChannelDoesNotExistsErr)
+
+	m.channels[*channelID] = &joinedChannel{}
+	_, err = m.ScheduleSend(channelID, Text, []byte("hello"),
+		netTime.Now().Add(-time.Hour), ValidForever,
+		cmix.GetDefaultCMIXParams(), nil)
+	require.ErrorIs(t, err, InvalidScheduleTimeErr)
+
+	require.ErrorIs(t, m.RescheduleSend(0, netTime.Now().Add(time.Hour)),
+		ScheduledSendNotFoundErr)
+}
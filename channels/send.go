@@ -12,6 +12,7 @@ import (
 	"crypto/ed25519"
 	"crypto/hmac"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/emoji"
+	"gitlab.com/elixxir/client/v4/typingIndicator"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	"gitlab.com/elixxir/crypto/message"
 	"gitlab.com/xx_network/primitives/id"
@@ -32,12 +34,22 @@ import (
 
 const (
 	/* Versions for various message types */
-	cmixChannelTextVersion       = 0
-	cmixChannelReactionVersion   = 0
-	cmixChannelInvitationVersion = 0
-	cmixChannelSilentVersion     = 0
-	cmixChannelDeleteVersion     = 0
-	cmixChannelPinVersion        = 0
+	cmixChannelTextVersion        = 0
+	cmixChannelReactionVersion    = 0
+	cmixChannelInvitationVersion  = 0
+	cmixChannelSilentVersion      = 0
+	cmixChannelDeleteVersion      = 0
+	cmixChannelPinVersion         = 0
+	cmixChannelEditVersion        = 0
+	cmixChannelPollVersion        = 0
+	cmixChannelPollVoteVersion    = 0
+	cmixChannelPollCloseVersion   = 0
+	cmixChannelTypingVersion      = 0
+	cmixChannelModeratorVersion   = 0
+	cmixChannelKeyRotationVersion = 0
+	cmixChannelSlowModeVersion    = 0
+	cmixChannelBanVersion         = 0
+	cmixChannelMetadataVersion    = 0
 
 	// SendMessageTag is the base tag used when generating a debug tag for
 	// sending a message.
@@ -71,6 +83,46 @@ const (
 	// message.
 	SendMuteTag = "ChMute"
 
+	// SendEditTag is the base tag used when generating a debug tag for an edit
+	// message.
+	SendEditTag = "ChEdit"
+
+	// SendPollTag is the base tag used when generating a debug tag for a poll
+	// message.
+	SendPollTag = "ChPoll"
+
+	// SendPollVoteTag is the base tag used when generating a debug tag for a
+	// poll vote message.
+	SendPollVoteTag = "ChPollVote"
+
+	// SendPollCloseTag is the base tag used when generating a debug tag for a
+	// poll close message.
+	SendPollCloseTag = "ChPollClose"
+
+	// SendTypingTag is the base tag used when generating a debug tag for a
+	// typing indicator.
+	SendTypingTag = "ChTyping"
+
+	// SendModeratorTag is the base tag used when generating a debug tag for a
+	// moderator message.
+	SendModeratorTag = "ChModerator"
+
+	// SendKeyRotationTag is the base tag used when generating a debug tag for
+	// a key rotation message.
+	SendKeyRotationTag = "ChKeyRotation"
+
+	// SendSlowModeTag is the base tag used when generating a debug tag for a
+	// slow mode message.
+	SendSlowModeTag = "ChSlowMode"
+
+	// SendBanTag is the base tag used when generating a debug tag for a ban
+	// message.
+	SendBanTag = "ChBan"
+
+	// SendMetadataTag is the base tag used when generating a debug tag for a
+	// channel metadata update.
+	SendMetadataTag = "ChMetadata"
+
 	// SendAdminReplayTag is the base tag used when generating a debug tag for
 	// an admin replay message.
 	SendAdminReplayTag = "ChAdminReplay"
@@ -92,10 +144,12 @@ func timeNow() string { return netTime.Now().Format("15:04:05.9999999") }
 // SendGeneric is used to send a raw message over a channel. In general, it
 // should be wrapped in a function that defines the wire protocol.
 //
-// If the final message, before being sent over the wire, is too long, this will
-// return an error. Due to the underlying encoding using compression, it is not
-// possible to define the largest payload that can be sent, but it will always
-// be possible to send a payload of 802 bytes at minimum.
+// If the final message, before being sent over the wire, is too long to fit in
+// a single cMix message, it is split into multiple parts that are reassembled
+// by the receivers. If it is too long to be split, this will return an error.
+// Due to the underlying encoding using compression, it is not possible to
+// define the largest payload that can be sent, but it will always be possible
+// to send a payload of 802 bytes in a single message.
 //
 // The meaning of validUntil depends on the use case.
 //
@@ -130,6 +184,19 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 			errors.Errorf("user muted in channel %s", channelID)
 	}
 
+	// Reject the send if the channel is in slow mode and the user sent a
+	// message to it within the interval. Moderators are not limited.
+	rateLimited := isRateLimited(messageType) &&
+		!m.moderators.isModerator(channelID, m.me.PubKey)
+	if rateLimited {
+		wait := m.slowMode.waitTime(channelID, m.me.PubKey, netTime.Now())
+		if wait > 0 {
+			return message.ID{}, rounds.Round{}, ephemeral.Id{},
+				errors.WithMessagef(SlowModeErr,
+					"wait %s before sending to channel %s", wait, channelID)
+		}
+	}
+
 	// Note: We log sends on exit, and append what happened to the message
 	// this cuts down on clutter in the log.
 	log := fmt.Sprintf(
@@ -245,12 +312,14 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 	log += fmt.Sprintf("Broadcasting message at %s. ", timeNow())
 	tags := makeUserPingTags(pingsMap)
 	mt := messageType.Marshal()
-	r, ephID, err := ch.broadcast.BroadcastWithAssembler(assemble, tags,
-		[2]byte{mt[0], mt[1]}, params)
+	r, ephID, err := ch.broadcast.BroadcastMultipartWithAssembler(assemble,
+		tags, [2]byte{mt[0], mt[1]}, params)
 	if err != nil {
 		printErr = true
 		log += fmt.Sprintf("ERROR Broadcast failed at %s: %s. ", timeNow(), err)
 
+		// A multi-part message that was only partially sent is never
+		// received, so it is marked as failed the same as any other
 		if errDenote := m.st.failedSend(uuid); errDenote != nil {
 			log += fmt.Sprintf("Failed to denote failed broadcast: %s", err)
 		}
@@ -260,6 +329,10 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 	log += fmt.Sprintf(
 		"Broadcast succeeded at %s on round %d, success!", timeNow(), r.ID)
 
+	if rateLimited {
+		m.slowMode.recordSend(channelID, m.me.PubKey, netTime.Now())
+	}
+
 	if tracked {
 		err = m.st.send(uuid, messageID, r)
 		if err != nil {
@@ -282,7 +355,9 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 // lasting forever if ValidForever is used.
 //
 // Pings are a list of ed25519 public keys that will receive notifications
-// for this message. They must be in the channel and have notifications enabled
+// for this message. They must be in the channel and have notifications enabled.
+// Users mentioned in the text with MentionTag are added to the pings
+// automatically.
 func (m *manager) SendMessage(channelID *id.ID, msg string,
 	validUntil time.Duration, params cmix.CMIXParams, pings []ed25519.PublicKey) (
 	message.ID, rounds.Round, ephemeral.Id, error) {
@@ -302,6 +377,7 @@ func (m *manager) SendMessage(channelID *id.ID, msg string,
 		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
 	}
 
+	pings = addMentionPings(msg, pings, m.me.PubKey)
 	pingMap := map[PingType][]ed25519.PublicKey{MentionPing: pings}
 
 	return m.SendGeneric(
@@ -321,7 +397,9 @@ func (m *manager) SendMessage(channelID *id.ID, msg string,
 // lasting forever if ValidForever is used.
 //
 // Pings are a list of ed25519 public keys that will receive notifications
-// for this message. They must be in the channel and have notifications enabled
+// for this message. They must be in the channel and have notifications enabled.
+// Users mentioned in the text with MentionTag are added to the pings
+// automatically.
 func (m *manager) SendReply(channelID *id.ID, msg string,
 	replyTo message.ID, validUntil time.Duration,
 	params cmix.CMIXParams, pings []ed25519.PublicKey) (
@@ -349,6 +427,7 @@ func (m *manager) SendReply(channelID *id.ID, msg string,
 			"failed getting message %s from event model", replyTo)
 	}
 
+	pings = addMentionPings(msg, pings, m.me.PubKey)
 	pingMap := map[PingType][]ed25519.PublicKey{
 		ReplyPing:   {mm.PubKey},
 		MentionPing: pings,
@@ -517,8 +596,9 @@ func (m *manager) replayAdminMessage(channelID *id.ID, encryptedPayload []byte,
 // admin keys, identifying it as sent by the admin. In general, it should be
 // wrapped in a function that defines the wire protocol.
 //
-// If the final message, before being sent over the wire, is too long, this will
-// return an error. The message must be at most 510 bytes long.
+// If the final message, before being sent over the wire, is longer than 510
+// bytes, it is split into multiple parts that are reassembled by the receivers.
+// If it is too long to be split, this will return an error.
 //
 // If the user is not an admin of the channel (i.e. does not have a private
 // key for the channel saved to storage), then an error is returned.
@@ -621,7 +701,8 @@ func (m *manager) SendAdminGeneric(channelID *id.ID, messageType MessageType,
 			DeriveChannelMessageID(channelID, chMsg.RoundID, chMsgSerial)
 
 		// Check if the message is too long
-		if len(chMsgSerial) > ch.broadcast.MaxRSAToPublicPayloadSize() {
+		if len(chMsgSerial) >
+			ch.broadcast.MaxRSAToPublicMultipartPayloadSize() {
 			return nil, MessageTooLongErr
 		}
 
@@ -631,7 +712,7 @@ func (m *manager) SendAdminGeneric(channelID *id.ID, messageType MessageType,
 	log += fmt.Sprintf("Broadcasting message at %s. ", timeNow())
 	mt := messageType.Marshal()
 	encryptedPayload, r, ephID, err := ch.broadcast.
-		BroadcastRSAToPublicWithAssembler(privKey, assemble, nil,
+		BroadcastRSAToPublicMultipartWithAssembler(privKey, assemble, nil,
 			[2]byte{mt[0], mt[1]}, params)
 	if err != nil {
 		printErr = true
@@ -668,11 +749,15 @@ func (m *manager) SendAdminGeneric(channelID *id.ID, messageType MessageType,
 }
 
 // DeleteMessage deletes the targeted message from storage. Users may delete
-// their own messages but only the channel admin can delete other user's
-// messages. If the user is not an admin of the channel or if they are not the
-// sender of the targetMessage, then the error NotAnAdminErr is returned.
+// their own messages but only the channel admin and moderators can delete other
+// user's messages. If the user is not an admin or moderator of the channel or
+// if they are not the sender of the targetMessage, then the error NotAnAdminErr
+// is returned.
 //
 // Clients will drop the deletion if they do not recognize the target message.
+//
+// Deletions sent by a moderator are not replayed by the lease system, so users
+// who join after the message has left the network will not see them.
 func (m *manager) DeleteMessage(channelID *id.ID,
 	targetMessage message.ID, params cmix.CMIXParams) (
 	message.ID, rounds.Round, ephemeral.Id, error) {
@@ -684,7 +769,7 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 	// Load private key from storage. If it does not exist, then check if the
 	// user is the sender of the message to delete.
 	isChannelAdmin := m.IsChannelAdmin(channelID)
-	if !isChannelAdmin {
+	if !isChannelAdmin && !m.IsModerator(channelID, m.me.PubKey) {
 		msg, err := m.events.model.GetMessage(targetMessage)
 		if err != nil {
 			return message.ID{}, rounds.Round{}, ephemeral.Id{},
@@ -696,7 +781,7 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 		if !bytes.Equal(msg.PubKey, m.me.PubKey) {
 			return message.ID{}, rounds.Round{}, ephemeral.Id{},
 				errors.Errorf("can only delete message you are sender of " +
-					"or if you are the channel admin.")
+					"or if you are the channel admin or a moderator.")
 		}
 	}
 
@@ -721,12 +806,217 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 	}
 }
 
+// EditMessage replaces the text of the targeted message. Users may edit their
+// own messages but only the channel admin can edit other user's messages. If
+// the user is not an admin of the channel or if they are not the sender of the
+// targetMessage, then the error NotAnAdminErr is returned.
+//
+// The previous text of the message is kept by the event model as a revision.
+// If the new text is longer than the maximum message length, then the error
+// MessageTooLongErr is returned.
+//
+// Clients will drop the edit if they do not recognize the target message.
+func (m *manager) EditMessage(channelID *id.ID, targetMessage message.ID,
+	newText string, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, targetMessage.Bytes(), SendEditTag)
+	jww.INFO.Printf("[CH] [%s] Edit message %s in channel %s",
+		tag, targetMessage, channelID)
+
+	if len(newText) > m.events.maxMessageLength {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, MessageTooLongErr
+	}
+
+	// If the user is the sender of the message, then the edit is sent as a
+	// normal user message. Otherwise, the user must be the channel admin.
+	msg, err := m.events.model.GetMessage(targetMessage)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"failed to find targeted message %s to edit: %+v",
+			targetMessage, err)
+	}
+
+	isSender := bytes.Equal(msg.PubKey, m.me.PubKey)
+	if !isSender && !m.IsChannelAdmin(channelID) {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, NotAnAdminErr
+	}
+
+	editMessage := &CMIXChannelEdit{
+		Version:   cmixChannelEditVersion,
+		MessageID: targetMessage.Bytes(),
+		Text:      newText,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	editMarshaled, err := proto.Marshal(editMessage)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	if isSender {
+		return m.SendGeneric(
+			channelID, Edit, editMarshaled, ValidForever, false, params, nil)
+	}
+	return m.SendAdminGeneric(
+		channelID, Edit, editMarshaled, ValidForever, false, params)
+}
+
+// SendPoll is used to send a poll over a channel. Members of the channel can
+// vote on one of the options with SendPollVote. The poll must have at least two
+// options; otherwise, InvalidPollErr is returned.
+//
+// The message will auto delete validUntil after the round it is sent in,
+// lasting forever if ValidForever is used.
+func (m *manager) SendPoll(channelID *id.ID, question string,
+	options []string, validUntil time.Duration, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, []byte(question), SendPollTag)
+	jww.INFO.Printf("[CH] [%s] SendPoll with %d options on channel %s",
+		tag, len(options), channelID)
+
+	if len(options) < 2 {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, InvalidPollErr
+	}
+
+	poll := &CMIXChannelPoll{
+		Version:  cmixChannelPollVersion,
+		Question: question,
+		Options:  options,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	pollMarshaled, err := proto.Marshal(poll)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendGeneric(
+		channelID, Poll, pollMarshaled, validUntil, true, params, nil)
+}
+
+// SendPollVote is used to vote for an option on a poll. A user has at most one
+// vote per poll; voting again replaces the previous vote. If the option does
+// not exist in the poll, then InvalidPollErr is returned.
+//
+// Clients will drop the vote if they do not recognize the poll or if the poll
+// was closed before the vote was sent.
+func (m *manager) SendPollVote(channelID *id.ID, pollMessageID message.ID,
+	option uint32, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, pollMessageID.Bytes(), SendPollVoteTag)
+	jww.INFO.Printf("[CH] [%s] SendPollVote for option %d of poll %s on "+
+		"channel %s", tag, option, pollMessageID, channelID)
+
+	// Verify that the option exists in the poll
+	pollMsg, err := m.events.model.GetMessage(pollMessageID)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"failed to find poll %s to vote on: %+v", pollMessageID, err)
+	}
+	poll := &CMIXChannelPoll{}
+	if pollMsg.Type != Poll || json.Unmarshal(pollMsg.Content, poll) != nil ||
+		int(option) >= len(poll.Options) {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, InvalidPollErr
+	}
+
+	vote := &CMIXChannelPollVote{
+		Version:       cmixChannelPollVoteVersion,
+		PollMessageID: pollMessageID.Bytes(),
+		Option:        option,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	voteMarshaled, err := proto.Marshal(vote)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendGeneric(
+		channelID, PollVote, voteMarshaled, ValidForever, false, params, nil)
+}
+
+// SendTyping is used to signal to the channel that the user started or stopped
+// typing. Typing indicators are sent at low priority, are only valid for
+// TypingTimeout, and are never stored. They are delivered to other members of
+// the channel via [UiCallbacks.UserTyping].
+//
+// While the user is typing, the UI should call SendTyping with typing set to
+// true every TypingInterval. Sending more often returns TypingRateLimitErr,
+// which can be safely ignored.
+func (m *manager) SendTyping(channelID *id.ID, typing bool,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	if !m.typing.Allow(channelID.Bytes(), typing, netTime.Now()) {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, TypingRateLimitErr
+	}
+
+	tag := makeChaDebugTag(channelID, m.me.PubKey, nil, SendTypingTag)
+	jww.DEBUG.Printf(
+		"[CH] [%s] SendTyping on channel %s (typing: %t)", tag, channelID, typing)
+
+	typingMsg := &CMIXChannelTyping{
+		Version: cmixChannelTypingVersion,
+		Typing:  typing,
+	}
+
+	params = typingIndicator.Params(params.SetDebugTag(tag))
+
+	typingMarshaled, err := proto.Marshal(typingMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	// Typing indicators are not tracked so that they are never stored
+	return m.SendGeneric(channelID, Typing, typingMarshaled, TypingTimeout,
+		false, params, nil)
+}
+
+// ClosePoll closes the poll so that votes sent after it are not counted. Only
+// the channel admin can close a poll; if the user is not an admin of the
+// channel, then the error NotAnAdminErr is returned.
+//
+// If undoAction is true, then the poll is reopened.
+//
+// Clients will drop the close if they do not recognize the poll.
+func (m *manager) ClosePoll(channelID *id.ID, pollMessageID message.ID,
+	undoAction bool, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, pollMessageID.Bytes(), SendPollCloseTag)
+	jww.INFO.Printf("[CH] [%s] %s poll %s in channel %s",
+		tag, pollCloseVerb(undoAction), pollMessageID, channelID)
+
+	closeMsg := &CMIXChannelPollClose{
+		Version:       cmixChannelPollCloseVersion,
+		PollMessageID: pollMessageID.Bytes(),
+		UndoAction:    undoAction,
+	}
+	closeMarshaled, err := proto.Marshal(closeMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	params = params.SetDebugTag(tag)
+
+	return m.SendAdminGeneric(
+		channelID, PollClose, closeMarshaled, ValidForever, false, params)
+}
+
 // PinMessage pins the target message to the top of a channel view for all
-// users in the specified channel. Only the channel admin can pin user messages.
+// users in the specified channel. Only the channel admin and moderators can pin
+// user messages.
 //
 // If undoAction is true, then the targeted message is unpinned.
 //
 // Clients will drop the pin if they do not recognize the target message.
+//
+// Pins sent by a moderator are not replayed by the lease system, so users who
+// join after the message has left the network will not see them.
 func (m *manager) PinMessage(channelID *id.ID,
 	targetMessage message.ID, undoAction bool,
 	validUntil time.Duration, params cmix.CMIXParams) (
@@ -754,17 +1044,20 @@ func (m *manager) PinMessage(channelID *id.ID,
 
 	params = params.SetDebugTag(tag)
 
-	return m.SendAdminGeneric(
-		channelID, Pinned, pinnedMarshaled, validUntil, false, params)
+	return m.sendModeratorAction(
+		channelID, Pinned, pinnedMarshaled, validUntil, params)
 }
 
 // MuteUser is used to mute a user in a channel. Muting a user will cause all
 // future messages from the user being dropped on reception. Muted users are
-// also unable to send messages. Only the channel admin can mute a user; if the
-// user is not an admin of the channel, then the error NotAnAdminErr is
-// returned.
+// also unable to send messages. Only the channel admin and moderators can mute
+// a user; if the user is not an admin or moderator of the channel, then the
+// error NotAnAdminErr is returned.
 //
 // If undoAction is true, then the targeted user will be unmuted.
+//
+// Mutes sent by a moderator are not replayed by the lease system, so users who
+// join after the message has left the network will not see them.
 func (m *manager) MuteUser(channelID *id.ID, mutedUser ed25519.PublicKey,
 	undoAction bool, validUntil time.Duration, params cmix.CMIXParams) (
 	message.ID, rounds.Round, ephemeral.Id, error) {
@@ -791,8 +1084,171 @@ func (m *manager) MuteUser(channelID *id.ID, mutedUser ed25519.PublicKey,
 		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
 	}
 
+	return m.sendModeratorAction(
+		channelID, Mute, mutedMarshaled, validUntil, params)
+}
+
+// AppointModerator appoints the user as a moderator of the channel. Moderators
+// may delete any message, pin messages, and mute users. Only the channel admin
+// can appoint a moderator; if the user is not an admin of the channel, then the
+// error NotAnAdminErr is returned.
+//
+// If undoAction is true, then the moderator is revoked.
+func (m *manager) AppointModerator(channelID *id.ID,
+	moderator ed25519.PublicKey, undoAction bool, validUntil time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, moderator, SendModeratorTag)
+	jww.INFO.Printf("[CH] [%s] %s moderator %x in channel %s for %s",
+		tag, moderatorVerb(undoAction), moderator, channelID, validUntil)
+
+	if len(moderator) != ed25519.PublicKeySize {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"moderator public key must be %d bytes; received %d bytes",
+			ed25519.PublicKeySize, len(moderator))
+	}
+
+	moderatorMsg := &CMIXChannelModerator{
+		Version:    cmixChannelModeratorVersion,
+		PubKey:     moderator,
+		UndoAction: undoAction,
+	}
+	moderatorMarshaled, err := proto.Marshal(moderatorMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	params = params.SetDebugTag(tag)
+
+	return m.SendAdminGeneric(
+		channelID, Moderator, moderatorMarshaled, validUntil, false, params)
+}
+
+// SetSlowMode sets the minimum time between two messages sent by the same user
+// to the channel. Messages sent within the interval are rejected by the sender
+// and dropped by every other member. Moderators are not limited. An interval of
+// zero turns slow mode off. Only the channel admin can set slow mode; if the
+// user is not an admin of the channel, then the error NotAnAdminErr is
+// returned.
+func (m *manager) SetSlowMode(channelID *id.ID, interval time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	if interval < 0 {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"slow mode interval must be positive; received %s", interval)
+	}
+
+	slowModeMsg := &CMIXChannelSlowMode{
+		Version:  cmixChannelSlowModeVersion,
+		Interval: int64(interval),
+	}
+	slowModeMarshaled, err := proto.Marshal(slowModeMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, slowModeMarshaled, SendSlowModeTag)
+	jww.INFO.Printf("[CH] [%s] Set slow mode interval in channel %s to %s",
+		tag, channelID, interval)
+
+	params = params.SetDebugTag(tag)
+
+	return m.SendAdminGeneric(
+		channelID, SlowMode, slowModeMarshaled, ValidForever, false, params)
+}
+
+// UpdateChannelMetadata replaces the description, icon, topic, and rules of
+// the channel for every member. ChannelMetadata.Timestamp is ignored; members
+// order updates by the time they were sent and keep the newest. The icon may be
+// at most MaxChannelIconSize bytes. Only the channel admin can update the
+// metadata; if the user is not an admin of the channel, then the error
+// NotAnAdminErr is returned.
+func (m *manager) UpdateChannelMetadata(channelID *id.ID,
+	metadata ChannelMetadata, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	if len(metadata.Icon) > MaxChannelIconSize {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"channel icon must be at most %d bytes; received %d bytes",
+			MaxChannelIconSize, len(metadata.Icon))
+	}
+
+	metadataMsg := &CMIXChannelMetadata{
+		Version:     cmixChannelMetadataVersion,
+		Description: metadata.Description,
+		Icon:        metadata.Icon,
+		Topic:       metadata.Topic,
+		Rules:       metadata.Rules,
+	}
+	metadataMarshaled, err := proto.Marshal(metadataMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, metadataMarshaled, SendMetadataTag)
+	jww.INFO.Printf("[CH] [%s] Update metadata of channel %s", tag, channelID)
+
+	params = params.SetDebugTag(tag)
+
+	return m.SendAdminGeneric(channelID, MetadataUpdate, metadataMarshaled,
+		ValidForever, false, params)
+}
+
+// BanUser bans the user from the channel. The user is muted, every message
+// they have sent to the channel is hidden in the event model, and the ban is
+// recorded with the given reason. The reason may be at most
+// MaxBanReasonLength bytes. Only the channel admin can ban a user; if the user
+// is not an admin of the channel, then the error NotAnAdminErr is returned.
+//
+// If undoAction is true, then the user is unbanned and, unless they were muted
+// before the ban, unmuted. Messages hidden by the ban remain hidden.
+func (m *manager) BanUser(channelID *id.ID, bannedUser ed25519.PublicKey,
+	reason string, undoAction bool, validUntil time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, bannedUser, SendBanTag)
+	jww.INFO.Printf("[CH] [%s] %s user %x in channel %s for %s",
+		tag, banVerb(undoAction), bannedUser, channelID, validUntil)
+
+	if len(bannedUser) != ed25519.PublicKeySize {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"banned user public key must be %d bytes; received %d bytes",
+			ed25519.PublicKeySize, len(bannedUser))
+	} else if len(reason) > MaxBanReasonLength {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"ban reason must be at most %d bytes; received %d bytes",
+			MaxBanReasonLength, len(reason))
+	}
+
+	banMsg := &CMIXChannelBan{
+		Version:    cmixChannelBanVersion,
+		PubKey:     bannedUser,
+		Reason:     reason,
+		UndoAction: undoAction,
+	}
+	banMarshaled, err := proto.Marshal(banMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	params = params.SetDebugTag(tag)
+
+	return m.SendAdminGeneric(
+		channelID, Ban, banMarshaled, validUntil, false, params)
+}
+
+// sendModeratorAction sends the action as an admin message if the user is the
+// channel admin. Otherwise, if the user is a moderator of the channel, then it
+// is sent as a user message signed by the moderator. Returns NotAnAdminErr if
+// the user is neither.
+func (m *manager) sendModeratorAction(channelID *id.ID,
+	messageType MessageType, msg []byte, validUntil time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	if !m.IsChannelAdmin(channelID) && m.IsModerator(channelID, m.me.PubKey) {
+		return m.SendGeneric(
+			channelID, messageType, msg, validUntil, false, params, nil)
+	}
+
 	return m.SendAdminGeneric(
-		channelID, Mute, mutedMarshaled, validUntil, false, params)
+		channelID, messageType, msg, validUntil, false, params)
 }
 
 // makeChaDebugTag is a debug helper that creates non-unique msg identifier.
@@ -11,10 +11,13 @@ import (
 	"bytes"
 	"crypto/ed25519"
 	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"gitlab.com/elixxir/client/v4/broadcast"
@@ -23,6 +26,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/collective"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/typingIndicator"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	cryptoChannel "gitlab.com/elixxir/crypto/channel"
 	"gitlab.com/elixxir/crypto/fastRNG"
@@ -184,6 +188,22 @@ func Test_manager_SendAdminGeneric(t *testing.T) {
 			messageID)
 	}
 
+	// Messages larger than a single admin message are sent in multiple parts
+	longMsg := make([]byte, mbc.MaxRSAToPublicPayloadSize()*2)
+	_, _, _, err = m.SendAdminGeneric(ch.ReceptionID, messageType, longMsg,
+		validUntil, false, cmix.GetDefaultCMIXParams())
+	if err != nil {
+		t.Errorf("Failed to SendAdminGeneric long message: %v", err)
+	}
+
+	// Error path: message is too long to split into parts
+	longMsg = make([]byte, mbc.MaxRSAToPublicMultipartPayloadSize())
+	_, _, _, err = m.SendAdminGeneric(ch.ReceptionID, messageType, longMsg,
+		validUntil, false, cmix.GetDefaultCMIXParams())
+	if err != MessageTooLongErr {
+		t.Errorf("Unexpected error for message too long to send."+
+			"\nexpected: %v\nreceived: %v", MessageTooLongErr, err)
+	}
 }
 
 func Test_manager_SendMessage(t *testing.T) {
@@ -511,6 +531,83 @@ func Test_manager_SendSilent(t *testing.T) {
 
 }
 
+func Test_manager_SendTyping(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	require.NoError(t, err)
+
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		events:          initEvents(&mockEventModel{}, 512, kv, crng),
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string), remote: nil},
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity,
+			rounds.Round, SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round,
+			*bool, *bool, *SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+		typing:           typingIndicator.NewLimiter(TypingInterval),
+	}
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	require.NoError(t, err)
+
+	mbc := &mockBroadcastChannel{
+		crypto: ch,
+	}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	// A stop without a start must not be sent
+	_, _, _, err = m.SendTyping(
+		ch.ReceptionID, false, cmix.GetDefaultCMIXParams())
+	require.ErrorIs(t, err, TypingRateLimitErr)
+	require.False(t, mbc.hasRun)
+
+	// Send typing indicator
+	messageID, _, _, err := m.SendTyping(
+		ch.ReceptionID, true, cmix.GetDefaultCMIXParams())
+	require.NoError(t, err)
+
+	// Verify the message was handled correctly
+
+	// Decode the user message
+	umi, err := unmarshalUserMessageInternal(mbc.payload, ch.ReceptionID, Typing)
+	require.NoError(t, err)
+
+	// Do checks of the data
+	require.True(t, umi.GetMessageID().Equals(messageID))
+	require.Equal(t, TypingTimeout.Nanoseconds(), umi.GetChannelMessage().Lease)
+	require.Equal(t, uint(typingIndicator.RoundTries), mbc.params.RoundTries)
+
+	// Decode the typing message
+	typingMsg := &CMIXChannelTyping{}
+	err = proto.Unmarshal(umi.GetChannelMessage().Payload, typingMsg)
+	require.NoError(t, err)
+	require.True(t, typingMsg.Typing)
+
+	// A second indicator within TypingInterval must be rate limited
+	_, _, _, err = m.SendTyping(
+		ch.ReceptionID, true, cmix.GetDefaultCMIXParams())
+	require.ErrorIs(t, err, TypingRateLimitErr)
+}
+
 func Test_manager_SendInvite(t *testing.T) {
 	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
 	prng := rand.New(rand.NewSource(64))
@@ -662,6 +759,207 @@ func Test_manager_DeleteMessage(t *testing.T) {
 	}
 }
 
+func Test_manager_EditMessage(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	m := &manager{
+		me:       pi,
+		channels: make(map[id.ID]*joinedChannel),
+		local:    kv,
+		rng:      crng,
+		events:   initEvents(&MockEvent{}, 512, kv, crng),
+		st: loadSendTracker(&mockBroadcastClient{}, kv,
+			func(*id.ID, *userMessageInternal, []byte, time.Time,
+				receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+				uint64, error) {
+				return 0, nil
+			}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+				message.ID, receptionID.EphemeralIdentity,
+				rounds.Round, SentStatus) (uint64, error) {
+				return 0, nil
+			}, func(uint64, *message.ID, *time.Time, *rounds.Round,
+				*bool, *bool, *SentStatus) error {
+				return nil
+			}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+	}
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	if err != nil {
+		t.Fatalf("Failed to generate channel: %+v", err)
+	}
+	targetedMessageID := message.ID{56}
+	newText := "edited text"
+	mbc := &mockBroadcastChannel{}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	messageID, round, _, err := m.EditMessage(
+		ch.ReceptionID, targetedMessageID, newText, cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("EditMessage error: %+v", err)
+	}
+
+	// Verify the message was handled correctly
+	expectedMessageID := message.
+		DeriveChannelMessageID(ch.ReceptionID, uint64(round.ID), mbc.payload)
+	if !expectedMessageID.Equals(messageID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			expectedMessageID, messageID)
+	}
+
+	// Decode the channel message
+	chMgs := &ChannelMessage{}
+	if err = proto.Unmarshal(mbc.payload, chMgs); err != nil {
+		t.Fatalf("Could not proto unmarshal ChannelMessage: %+v", err)
+	}
+
+	if chMgs.RoundID != returnedRound {
+		t.Errorf("Incorrect round ID.\nexpected: %d\nreceived: %d",
+			returnedRound, chMgs.RoundID)
+	}
+
+	// Decode the edit message
+	editMsg := &CMIXChannelEdit{}
+	err = proto.Unmarshal(chMgs.Payload, editMsg)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelEdit: %+v", err)
+	}
+
+	if !bytes.Equal(editMsg.MessageID, targetedMessageID[:]) {
+		t.Errorf("Incorrect MessageID.\nexpected: %v\nreceived: %v",
+			targetedMessageID, editMsg.MessageID)
+	}
+	if editMsg.Text != newText {
+		t.Errorf("Incorrect text.\nexpected: %q\nreceived: %q",
+			newText, editMsg.Text)
+	}
+}
+
+// Tests that manager.EditMessage returns MessageTooLongErr when the new text is
+// longer than the maximum message length.
+func Test_manager_EditMessage_TooLong(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	m := &manager{
+		channels: make(map[id.ID]*joinedChannel),
+		events:   initEvents(&MockEvent{}, 512, kv, crng),
+	}
+
+	newText := strings.Repeat("a", m.events.maxMessageLength+1)
+	_, _, _, err := m.EditMessage(
+		id.NewIdFromString("channel", id.User, t), message.ID{56}, newText,
+		cmix.CMIXParams{})
+	if !errors.Is(err, MessageTooLongErr) {
+		t.Errorf("Unexpected error for text of length %d."+
+			"\nexpected: %v\nreceived: %+v", len(newText), MessageTooLongErr, err)
+	}
+}
+
+func Test_manager_SendPoll(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string), remote: nil},
+		events:          initEvents(&MockEvent{}, 512, kv, crng),
+		st: loadSendTracker(&mockBroadcastClient{}, kv,
+			func(*id.ID, *userMessageInternal, []byte, time.Time,
+				receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+				uint64, error) {
+				return 0, nil
+			}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+				message.ID, receptionID.EphemeralIdentity,
+				rounds.Round, SentStatus) (uint64, error) {
+				return 0, nil
+			}, func(uint64, *message.ID, *time.Time, *rounds.Round,
+				*bool, *bool, *SentStatus) error {
+				return nil
+			}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+	}
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	if err != nil {
+		t.Fatalf("Failed to generate channel: %+v", err)
+	}
+	question := "Which option?"
+	options := []string{"a", "b", "c"}
+	mbc := &mockBroadcastChannel{}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	// A poll with fewer than two options must be rejected
+	_, _, _, err = m.SendPoll(ch.ReceptionID, question, options[:1],
+		ValidForever, cmix.CMIXParams{})
+	if !errors.Is(err, InvalidPollErr) {
+		t.Errorf("Unexpected error for poll with one option."+
+			"\nexpected: %v\nreceived: %+v", InvalidPollErr, err)
+	}
+
+	messageID, _, _, err := m.SendPoll(
+		ch.ReceptionID, question, options, ValidForever, cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("SendPoll error: %+v", err)
+	}
+
+	// Verify the message was handled correctly
+
+	// Decode the user message
+	umi, err := unmarshalUserMessageInternal(mbc.payload, ch.ReceptionID, Poll)
+	if err != nil {
+		t.Fatalf("Failed to decode the user message: %+v", err)
+	}
+
+	// Do checks of the data
+	if !umi.GetMessageID().Equals(messageID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			messageID, umi.messageID)
+	}
+
+	if umi.GetChannelMessage().RoundID != returnedRound {
+		t.Errorf("Incorrect round ID.\nexpected: %d\nreceived: %d",
+			returnedRound, umi.GetChannelMessage().RoundID)
+	}
+
+	// Decode the poll message
+	pollMsg := &CMIXChannelPoll{}
+	err = proto.Unmarshal(umi.GetChannelMessage().Payload, pollMsg)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelPoll: %+v", err)
+	}
+
+	if pollMsg.Question != question {
+		t.Errorf("Incorrect question.\nexpected: %q\nreceived: %q",
+			question, pollMsg.Question)
+	}
+	if !reflect.DeepEqual(pollMsg.Options, options) {
+		t.Errorf("Incorrect options.\nexpected: %q\nreceived: %q",
+			options, pollMsg.Options)
+	}
+}
+
 func Test_manager_PinMessage(t *testing.T) {
 	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
 	mem := ekv.MakeMemstore()
@@ -736,6 +1034,78 @@ func Test_manager_PinMessage(t *testing.T) {
 	}
 }
 
+func Test_manager_ClosePoll(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+
+	m := &manager{
+		channels: make(map[id.ID]*joinedChannel),
+		local:    kv,
+		rng:      crng,
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+	}
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	if err != nil {
+		t.Fatalf("Failed to generate channel: %+v", err)
+	}
+	pollID := message.ID{56}
+	mbc := &mockBroadcastChannel{}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	messageID, round, _, err :=
+		m.ClosePoll(ch.ReceptionID, pollID, false, cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("ClosePoll error: %+v", err)
+	}
+
+	// Verify the message was handled correctly
+	expectedMessageID := message.
+		DeriveChannelMessageID(ch.ReceptionID, uint64(round.ID), mbc.payload)
+	if !expectedMessageID.Equals(messageID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			expectedMessageID, messageID)
+	}
+
+	// Decode the channel message
+	chMgs := &ChannelMessage{}
+	if err = proto.Unmarshal(mbc.payload, chMgs); err != nil {
+		t.Fatalf("Could not proto unmarshal ChannelMessage: %+v", err)
+	}
+
+	// Decode the poll close message
+	closeMsg := &CMIXChannelPollClose{}
+	err = proto.Unmarshal(chMgs.Payload, closeMsg)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelPollClose: %+v", err)
+	}
+
+	if !bytes.Equal(closeMsg.PollMessageID, pollID[:]) {
+		t.Errorf("Incorrect PollMessageID.\nexpected: %v\nreceived: %v",
+			pollID, closeMsg.PollMessageID)
+	}
+	if closeMsg.UndoAction {
+		t.Errorf("UndoAction should be false.")
+	}
+}
+
 func Test_manager_MuteUser(t *testing.T) {
 	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
 	prng := rand.New(rand.NewSource(64))
@@ -874,6 +1244,35 @@ func (m *mockBroadcastChannel) BroadcastRSAToPublicWithAssembler(
 	return nil, rounds.Round{ID: returnedRound}, ephemeral.Id{}, err
 }
 
+func (m *mockBroadcastChannel) MaxMultipartPayloadSize() int            { return 4096 }
+func (m *mockBroadcastChannel) MaxRSAToPublicMultipartPayloadSize() int { return 2048 }
+
+func (m *mockBroadcastChannel) BroadcastMultipart(payload []byte, tags []string,
+	metadata [2]byte, cMixParams cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	return m.Broadcast(payload, tags, metadata, cMixParams)
+}
+
+func (m *mockBroadcastChannel) BroadcastMultipartWithAssembler(
+	assembler broadcast.Assembler, tags []string, metadata [2]byte,
+	cMixParams cmix.CMIXParams) (rounds.Round, ephemeral.Id, error) {
+	return m.BroadcastWithAssembler(assembler, tags, metadata, cMixParams)
+}
+
+func (m *mockBroadcastChannel) BroadcastRSAToPublicMultipart(pk rsa.PrivateKey,
+	payload []byte, tags []string, metadata [2]byte,
+	cMixParams cmix.CMIXParams) ([]byte, rounds.Round, ephemeral.Id, error) {
+	return m.BroadcastRSAtoPublic(pk, payload, tags, metadata, cMixParams)
+}
+
+func (m *mockBroadcastChannel) BroadcastRSAToPublicMultipartWithAssembler(
+	pk rsa.PrivateKey, assembler broadcast.Assembler, tags []string,
+	metadata [2]byte, cMixParams cmix.CMIXParams) (
+	[]byte, rounds.Round, ephemeral.Id, error) {
+	return m.BroadcastRSAToPublicWithAssembler(
+		pk, assembler, tags, metadata, cMixParams)
+}
+
 func (m *mockBroadcastChannel) RegisterRSAtoPublicListener(
 	broadcast.ListenerFunc, []string) (broadcast.Processor, error) {
 	panic("implement me")
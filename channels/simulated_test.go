@@ -0,0 +1,173 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/cmix/simulated"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/client/v4/xxdk"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that a message sent by one channel manager on the simulated network is
+// received by another manager that joined the channel.
+func TestManager_SimulatedNetwork(t *testing.T) {
+	net, err := simulated.NewNetwork(simulated.DefaultParams())
+	if err != nil {
+		t.Fatalf("Failed to create simulated network: %+v", err)
+	}
+	prng := rand.New(rand.NewSource(42))
+
+	sender, _ := newSimulatedManager(t, net, prng)
+	receiver, received := newSimulatedManager(t, net, prng)
+
+	ch, err := sender.GenerateChannel("name", "description", cryptoBroadcast.Public)
+	if err != nil {
+		t.Fatalf("Failed to generate channel: %+v", err)
+	}
+	if err = sender.JoinChannel(ch); err != nil {
+		t.Fatalf("Sender failed to join channel: %+v", err)
+	}
+	if err = receiver.JoinChannel(ch); err != nil {
+		t.Fatalf("Receiver failed to join channel: %+v", err)
+	}
+
+	text := "Hello, channel."
+	msgID, _, _, err := sender.SendMessage(
+		ch.ReceptionID, text, ValidForever, cmix.GetDefaultCMIXParams(), nil)
+	if err != nil {
+		t.Fatalf("Failed to send message: %+v", err)
+	}
+
+	select {
+	case r := <-received:
+		if !r.channelID.Cmp(ch.ReceptionID) {
+			t.Errorf("Received message on wrong channel."+
+				"\nexpected: %s\nreceived: %s", ch.ReceptionID, r.channelID)
+		}
+		if r.messageID != msgID {
+			t.Errorf("Received wrong message ID.\nexpected: %s\nreceived: %s",
+				msgID, r.messageID)
+		}
+		if r.text != text {
+			t.Errorf("Received wrong text.\nexpected: %q\nreceived: %q",
+				text, r.text)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for message.")
+	}
+}
+
+// newSimulatedManager creates a channel manager on its own client on the
+// simulated network. Returns the manager and a channel that receives every
+// message passed to its event model. Its processes are stopped when the test
+// ends.
+func newSimulatedManager(t *testing.T, net *simulated.Network,
+	prng *rand.Rand) (Manager, chan simulatedMessage) {
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("Failed to generate identity: %+v", err)
+	}
+
+	kv := collective.TestingKV(
+		t, ekv.MakeMemstore(), collective.StandardPrefexs, nil)
+	c := net.NewClient(id.NewIdFromBytes(pi.PubKey, t), kv, nil)
+	addService := func(sp xxdk.Service) error {
+		s, err := sp()
+		if err != nil {
+			return err
+		}
+		stopOnCleanup(t, s)
+		return nil
+	}
+	if err = addService(func() (stoppable.Stoppable, error) {
+		return c.Follow(nil)
+	}); err != nil {
+		t.Fatalf("Failed to follow network: %+v", err)
+	}
+
+	model := &simulatedEventModel{
+		MockEvent: &MockEvent{}, received: make(chan simulatedMessage, 10)}
+	m, err := NewManager(pi, kv, c,
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG), model, nil,
+		addService, newMockNM(), &dummyUICallback{})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %+v", err)
+	}
+
+	return m, model.received
+}
+
+// stopOnCleanup closes the stoppable when the test ends and waits for it to
+// stop.
+func stopOnCleanup(t *testing.T, s stoppable.Stoppable) {
+	t.Cleanup(func() {
+		if err := s.Close(); err != nil {
+			t.Errorf("Failed to close %s: %+v", s.Name(), err)
+		}
+		if err := stoppable.WaitForStopped(s, 5*time.Second); err != nil {
+			t.Errorf("Failed to stop %s: %+v", s.Name(), err)
+		}
+	})
+}
+
+// simulatedMessage is a message received by a simulatedEventModel.
+type simulatedMessage struct {
+	channelID *id.ID
+	messageID message.ID
+	text      string
+}
+
+// simulatedEventModel is a MockEvent that reports received messages on a
+// channel. Calls to the MockEvent are serialized since messages arrive from
+// the network concurrently.
+type simulatedEventModel struct {
+	*MockEvent
+	received chan simulatedMessage
+	mux      sync.Mutex
+}
+
+func (m *simulatedEventModel) ReceiveMessage(channelID *id.ID,
+	messageID message.ID, nickname, text string, pubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, lease time.Duration,
+	round rounds.Round, messageType MessageType, status SentStatus,
+	hidden bool) uint64 {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	uuid := m.MockEvent.ReceiveMessage(channelID, messageID, nickname, text,
+		pubKey, dmToken, codeset, timestamp, lease, round, messageType, status,
+		hidden)
+	if status == Delivered {
+		m.received <- simulatedMessage{channelID, messageID, text}
+	}
+	return uuid
+}
+
+func (m *simulatedEventModel) UpdateFromUUID(uuid uint64,
+	messageID *message.ID, timestamp *time.Time, round *rounds.Round,
+	pinned, hidden *bool, status *SentStatus) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.MockEvent.UpdateFromUUID(
+		uuid, messageID, timestamp, round, pinned, hidden, status)
+}
@@ -0,0 +1,431 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// slowModeTolerance is the fraction of the slow mode interval, as a divisor,
+// that a received message may arrive early. It allows for the difference
+// between the clock of the sender and the round timestamp of the message.
+const slowModeTolerance = 10
+
+// rateLimitedTypes are the message types limited by slow mode and the flood
+// filter. Actions, such as deletions and typing indicators, are not limited.
+var rateLimitedTypes = map[MessageType]struct{}{
+	Text:       {},
+	Reaction:   {},
+	Invitation: {},
+	Poll:       {},
+}
+
+// isRateLimited returns true if the message type is limited by slow mode and
+// the flood filter.
+func isRateLimited(messageType MessageType) bool {
+	_, exists := rateLimitedTypes[messageType]
+	return exists
+}
+
+// slowModeCheckFunc returns true if a message of the given type from the user
+// is allowed by the slow mode of the channel.
+type slowModeCheckFunc func(channelID *id.ID, pubKey ed25519.PublicKey,
+	messageType MessageType, timestamp time.Time) bool
+
+// checkSlowMode returns true if a message of the given type from the user is
+// allowed by the slow mode of the channel. Moderators are not limited.
+//
+// This function adheres to the slowModeCheckFunc type.
+func (e *events) checkSlowMode(channelID *id.ID, pubKey ed25519.PublicKey,
+	messageType MessageType, timestamp time.Time) bool {
+	if !isRateLimited(messageType) || e.moderators.isModerator(channelID, pubKey) {
+		return true
+	}
+	return e.slowMode.allow(channelID, pubKey, timestamp)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Slow Mode                                                                  //
+////////////////////////////////////////////////////////////////////////////////
+
+// slowModeManager tracks the slow mode interval of each channel, which is set
+// by the channel admin with a SlowMode message, and the last message sent by
+// each user so that messages sent within the interval can be dropped.
+type slowModeManager struct {
+	// Slow mode interval of each channel.
+	intervals map[id.ID]slowModeInterval
+
+	// Timestamp of the last accepted message from each user in each channel.
+	// The internal map keys on the hex encoded ed25519.PublicKey of the user.
+	// It is saved with the intervals so that users cannot get around slow mode
+	// when the client restarts.
+	lastMessage map[id.ID]map[string]time.Time
+
+	mux sync.Mutex
+	kv  versioned.KV
+}
+
+// slowModeInterval is the slow mode interval of a channel and the timestamp of
+// the SlowMode message that set it.
+type slowModeInterval struct {
+	Interval  time.Duration `json:"interval"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// slowModeManagerDisk is the JSON representation of the slowModeManager saved
+// to storage.
+type slowModeManagerDisk struct {
+	Intervals   map[id.ID]slowModeInterval     `json:"intervals"`
+	LastMessage map[id.ID]map[string]time.Time `json:"lastMessage"`
+}
+
+// newOrLoadSlowModeManager loads an existing slowModeManager from storage, if
+// it exists. Otherwise, it initialises a new empty slowModeManager.
+func newOrLoadSlowModeManager(kv versioned.KV) (*slowModeManager, error) {
+	smm := &slowModeManager{
+		intervals:   make(map[id.ID]slowModeInterval),
+		lastMessage: make(map[id.ID]map[string]time.Time),
+		kv:          kv,
+	}
+
+	err := smm.load()
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	return smm, nil
+}
+
+// setInterval sets the slow mode interval of the channel. An interval of zero
+// turns slow mode off and clears the message history of the channel. The
+// interval is only set if the timestamp is newer than the timestamp of the
+// current interval so that replayed or out of order messages do not undo a
+// newer setting. Returns true if the interval was set.
+func (smm *slowModeManager) setInterval(
+	channelID *id.ID, interval time.Duration, timestamp time.Time) bool {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	current, exists := smm.intervals[*channelID]
+	if exists && !timestamp.After(current.Timestamp) {
+		return false
+	}
+
+	smm.intervals[*channelID] = slowModeInterval{interval, timestamp}
+	if interval <= 0 {
+		delete(smm.lastMessage, *channelID)
+	}
+
+	if err := smm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save slow mode intervals: %+v", err)
+	}
+
+	return true
+}
+
+// getInterval returns the slow mode interval of the channel. Returns zero if
+// slow mode is off.
+func (smm *slowModeManager) getInterval(channelID *id.ID) time.Duration {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+	return smm.intervals[*channelID].Interval
+}
+
+// allow returns true if a message from the user with the given timestamp is
+// allowed by the slow mode of the channel and records it as the last message of
+// the user. Messages are dropped if they are within the interval of the last
+// accepted message from the user, before or after it.
+func (smm *slowModeManager) allow(
+	channelID *id.ID, pubKey ed25519.PublicKey, timestamp time.Time) bool {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	interval := smm.intervals[*channelID].Interval
+	if interval <= 0 {
+		return true
+	}
+
+	key := hex.EncodeToString(pubKey)
+	last, exists := smm.lastMessage[*channelID][key]
+	if exists {
+		elapsed := timestamp.Sub(last)
+		if elapsed < 0 {
+			elapsed = -elapsed
+		}
+		if elapsed < interval-interval/slowModeTolerance {
+			return false
+		}
+		if timestamp.Before(last) {
+			return true
+		}
+	}
+
+	if _, exists = smm.lastMessage[*channelID]; !exists {
+		smm.lastMessage[*channelID] = make(map[string]time.Time)
+	}
+	smm.lastMessage[*channelID][key] = timestamp
+
+	if err := smm.save(); err != nil {
+		jww.ERROR.Printf("[CH] Failed to save last slow mode message from %x "+
+			"in channel %s: %+v", pubKey, channelID, err)
+	}
+
+	return true
+}
+
+// waitTime returns how long the user has to wait before they can send a
+// message to the channel. Returns zero if they can send now.
+func (smm *slowModeManager) waitTime(
+	channelID *id.ID, pubKey ed25519.PublicKey, now time.Time) time.Duration {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	interval := smm.intervals[*channelID].Interval
+	last, exists := smm.lastMessage[*channelID][hex.EncodeToString(pubKey)]
+	if interval <= 0 || !exists {
+		return 0
+	}
+
+	if wait := interval - now.Sub(last); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// recordSend records that the user sent a message to the channel at the given
+// time.
+func (smm *slowModeManager) recordSend(
+	channelID *id.ID, pubKey ed25519.PublicKey, now time.Time) {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	if _, exists := smm.lastMessage[*channelID]; !exists {
+		smm.lastMessage[*channelID] = make(map[string]time.Time)
+	}
+	smm.lastMessage[*channelID][hex.EncodeToString(pubKey)] = now
+
+	if err := smm.save(); err != nil {
+		jww.ERROR.Printf("[CH] Failed to save last slow mode message sent "+
+			"in channel %s: %+v", channelID, err)
+	}
+}
+
+// removeChannel deletes the slow mode interval and message history of the
+// channel. This should only be called when leaving a channel.
+func (smm *slowModeManager) removeChannel(channelID *id.ID) error {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	_, intervalExists := smm.intervals[*channelID]
+	_, lastMessageExists := smm.lastMessage[*channelID]
+	if !intervalExists && !lastMessageExists {
+		return nil
+	}
+
+	delete(smm.intervals, *channelID)
+	delete(smm.lastMessage, *channelID)
+	return smm.save()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Flood Filter                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+// floodFilter is a local filter that hides messages from a user that sends
+// more than maxMessages messages to a channel within the window. Unlike slow
+// mode, it is not set by the channel admin and only affects this user.
+type floodFilter struct {
+	maxMessages int
+	window      time.Duration
+
+	// Timestamps of the recent messages from each user in each channel. The
+	// internal map keys on the hex encoded ed25519.PublicKey of the user. It is
+	// not saved to storage.
+	recent map[id.ID]map[string][]time.Time
+
+	mux sync.Mutex
+	kv  versioned.KV
+}
+
+// floodFilterDisk is the JSON representation of the floodFilter settings
+// saved to storage.
+type floodFilterDisk struct {
+	MaxMessages int           `json:"maxMessages"`
+	Window      time.Duration `json:"window"`
+}
+
+// newOrLoadFloodFilter loads an existing floodFilter from storage, if it
+// exists. Otherwise, it initialises a new disabled floodFilter.
+func newOrLoadFloodFilter(kv versioned.KV) (*floodFilter, error) {
+	ff := &floodFilter{
+		recent: make(map[id.ID]map[string][]time.Time),
+		kv:     kv,
+	}
+
+	err := ff.load()
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	return ff, nil
+}
+
+// set sets the maximum number of messages a user may send to a channel within
+// the window before their messages are hidden. Setting either to zero disables
+// the filter.
+func (ff *floodFilter) set(maxMessages int, window time.Duration) error {
+	if maxMessages < 0 || window < 0 {
+		return errors.Errorf("flood filter must be positive; received %d "+
+			"messages in %s", maxMessages, window)
+	}
+
+	ff.mux.Lock()
+	defer ff.mux.Unlock()
+
+	ff.maxMessages, ff.window = maxMessages, window
+	ff.recent = make(map[id.ID]map[string][]time.Time)
+
+	return ff.save()
+}
+
+// get returns the maximum number of messages a user may send to a channel
+// within the window before their messages are hidden.
+func (ff *floodFilter) get() (maxMessages int, window time.Duration) {
+	ff.mux.Lock()
+	defer ff.mux.Unlock()
+	return ff.maxMessages, ff.window
+}
+
+// isFlood records a message from the user and returns true if it is over the
+// limit of the filter and should be hidden.
+func (ff *floodFilter) isFlood(
+	channelID *id.ID, pubKey ed25519.PublicKey, timestamp time.Time) bool {
+	ff.mux.Lock()
+	defer ff.mux.Unlock()
+
+	if ff.maxMessages <= 0 || ff.window <= 0 {
+		return false
+	}
+
+	if _, exists := ff.recent[*channelID]; !exists {
+		ff.recent[*channelID] = make(map[string][]time.Time)
+	}
+
+	// Keep only the messages within the window, including this one
+	key := hex.EncodeToString(pubKey)
+	recent := append(ff.recent[*channelID][key], timestamp)
+	start := timestamp.Add(-ff.window)
+	kept := recent[:0]
+	for _, ts := range recent {
+		if ts.After(start) {
+			kept = append(kept, ts)
+		}
+	}
+	ff.recent[*channelID][key] = kept
+
+	return len(kept) > ff.maxMessages
+}
+
+// removeChannel deletes the message history of the channel. This should only be
+// called when leaving a channel.
+func (ff *floodFilter) removeChannel(channelID *id.ID) {
+	ff.mux.Lock()
+	defer ff.mux.Unlock()
+	delete(ff.recent, *channelID)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage values.
+const (
+	slowModeStoreVer    = 0
+	slowModeStoreKey    = "slowModeIntervals"
+	floodFilterStoreVer = 0
+	floodFilterStoreKey = "floodFilter"
+)
+
+// save stores the slow mode interval of every channel and the last message
+// from each user to storage.
+func (smm *slowModeManager) save() error {
+	data, err := json.Marshal(slowModeManagerDisk{smm.intervals, smm.lastMessage})
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   slowModeStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return smm.kv.Set(slowModeStoreKey, obj)
+}
+
+// load gets the slow mode interval of every channel and the last message from
+// each user from storage.
+func (smm *slowModeManager) load() error {
+	obj, err := smm.kv.Get(slowModeStoreKey, slowModeStoreVer)
+	if err != nil {
+		return err
+	}
+
+	var disk slowModeManagerDisk
+	if err = json.Unmarshal(obj.Data, &disk); err != nil {
+		return errors.Wrap(err, "could not unmarshal slow mode intervals")
+	}
+	if disk.Intervals != nil {
+		smm.intervals = disk.Intervals
+	}
+	if disk.LastMessage != nil {
+		smm.lastMessage = disk.LastMessage
+	}
+	return nil
+}
+
+// save stores the flood filter settings to storage.
+func (ff *floodFilter) save() error {
+	data, err := json.Marshal(floodFilterDisk{ff.maxMessages, ff.window})
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   floodFilterStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return ff.kv.Set(floodFilterStoreKey, obj)
+}
+
+// load gets the flood filter settings from storage.
+func (ff *floodFilter) load() error {
+	obj, err := ff.kv.Get(floodFilterStoreKey, floodFilterStoreVer)
+	if err != nil {
+		return err
+	}
+
+	var disk floodFilterDisk
+	if err = json.Unmarshal(obj.Data, &disk); err != nil {
+		return errors.Wrap(err, "could not unmarshal flood filter")
+	}
+	ff.maxMessages, ff.window = disk.MaxMessages, disk.Window
+	return nil
+}
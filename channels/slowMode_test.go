@@ -0,0 +1,282 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that newOrLoadSlowModeManager initialises a new empty slowModeManager
+// and loads the intervals and last messages saved by a previous one.
+func Test_newOrLoadSlowModeManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+
+	smm, err := newOrLoadSlowModeManager(kv)
+	require.NoError(t, err)
+	require.Empty(t, smm.intervals)
+
+	channelID := randChannelID(prng, t)
+	smm.setInterval(channelID, time.Minute, time.Unix(5, 0))
+	pubKey := makeEd25519PubKey(prng, t)
+	require.True(t, smm.allow(channelID, pubKey, time.Unix(10, 0)))
+
+	loaded, err := newOrLoadSlowModeManager(kv)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, loaded.getInterval(channelID))
+	require.True(t,
+		time.Unix(5, 0).Equal(loaded.intervals[*channelID].Timestamp))
+	require.False(t, loaded.allow(channelID, pubKey, time.Unix(20, 0)))
+	require.Equal(t, 50*time.Second,
+		loaded.waitTime(channelID, pubKey, time.Unix(20, 0)))
+}
+
+// Tests that slowModeManager.setInterval only sets intervals that are newer
+// than the current interval.
+func Test_slowModeManager_setInterval(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	smm, err := newOrLoadSlowModeManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+
+	require.True(t, smm.setInterval(channelID, time.Minute, time.Unix(10, 0)))
+	require.False(t, smm.setInterval(channelID, time.Hour, time.Unix(5, 0)),
+		"Older interval set.")
+	require.False(t, smm.setInterval(channelID, time.Hour, time.Unix(10, 0)),
+		"Replayed interval set.")
+	require.Equal(t, time.Minute, smm.getInterval(channelID))
+
+	require.True(t, smm.setInterval(channelID, 0, time.Unix(15, 0)))
+	require.Zero(t, smm.getInterval(channelID))
+}
+
+// Tests that slowModeManager.allow drops messages from a user that are within
+// the interval of their last accepted message, before or after it, and that
+// each user and channel is limited separately.
+func Test_slowModeManager_allow(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	smm, err := newOrLoadSlowModeManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+	channelID, otherChannelID := randChannelID(prng, t), randChannelID(prng, t)
+	alice, bob := makeEd25519PubKey(prng, t), makeEd25519PubKey(prng, t)
+	start := time.Unix(1000, 0)
+
+	// Everything is allowed when slow mode is off
+	require.True(t, smm.allow(channelID, alice, start))
+	require.True(t, smm.allow(channelID, alice, start))
+
+	smm.setInterval(channelID, time.Minute, start)
+	tests := []struct {
+		pubKey  []byte
+		offset  time.Duration
+		allowed bool
+	}{
+		{alice, 0, true},
+		{alice, 30 * time.Second, false},
+		{bob, 30 * time.Second, true},
+		{alice, 55 * time.Second, true}, // Within the tolerance
+		{alice, 20 * time.Second, false},
+		{alice, -10 * time.Second, true},
+		{alice, time.Minute, false},
+		{alice, 2 * time.Minute, true},
+	}
+	for i, tt := range tests {
+		allowed := smm.allow(channelID, tt.pubKey, start.Add(tt.offset))
+		require.Equal(t, tt.allowed, allowed, "Test %d: %+v", i, tt)
+	}
+
+	require.True(t, smm.allow(otherChannelID, alice, start))
+	require.True(t, smm.allow(otherChannelID, alice, start))
+}
+
+// Tests that slowModeManager.waitTime returns the time left in the interval
+// since the message recorded with slowModeManager.recordSend.
+func Test_slowModeManager_waitTime(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	smm, err := newOrLoadSlowModeManager(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+	pubKey := makeEd25519PubKey(prng, t)
+	now := time.Unix(1000, 0)
+
+	smm.recordSend(channelID, pubKey, now)
+	require.Zero(t, smm.waitTime(channelID, pubKey, now),
+		"Wait required when slow mode is off.")
+
+	smm.setInterval(channelID, time.Minute, now)
+	require.Equal(t, 45*time.Second,
+		smm.waitTime(channelID, pubKey, now.Add(15*time.Second)))
+	require.Zero(t, smm.waitTime(channelID, pubKey, now.Add(time.Minute)))
+	require.Zero(t, smm.waitTime(
+		channelID, makeEd25519PubKey(prng, t), now.Add(15*time.Second)))
+}
+
+// Tests that slowModeManager.removeChannel deletes the interval of the channel
+// from memory and storage.
+func Test_slowModeManager_removeChannel(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	smm, err := newOrLoadSlowModeManager(kv)
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+	smm.setInterval(channelID, time.Minute, time.Unix(10, 0))
+	smm.recordSend(channelID, makeEd25519PubKey(prng, t), time.Unix(10, 0))
+
+	require.NoError(t, smm.removeChannel(channelID))
+	require.Zero(t, smm.getInterval(channelID))
+	require.NotContains(t, smm.lastMessage, *channelID)
+
+	loaded, err := newOrLoadSlowModeManager(kv)
+	require.NoError(t, err)
+	require.Zero(t, loaded.getInterval(channelID))
+	require.NotContains(t, loaded.lastMessage, *channelID)
+}
+
+// Tests that floodFilter.isFlood returns true once a user sends more than the
+// maximum number of messages within the window.
+func Test_floodFilter_isFlood(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	ff, err := newOrLoadFloodFilter(versioned.NewKV(ekv.MakeMemstore()))
+	require.NoError(t, err)
+	channelID := randChannelID(prng, t)
+	alice, bob := makeEd25519PubKey(prng, t), makeEd25519PubKey(prng, t)
+	start := time.Unix(1000, 0)
+
+	// The filter is disabled by default
+	for i := 0; i < 10; i++ {
+		require.False(t, ff.isFlood(channelID, alice, start))
+	}
+
+	require.NoError(t, ff.set(3, 10*time.Second))
+	tests := []struct {
+		pubKey []byte
+		offset time.Duration
+		flood  bool
+	}{
+		{alice, 0, false},
+		{alice, time.Second, false},
+		{alice, 2 * time.Second, false},
+		{alice, 3 * time.Second, true},
+		{bob, 3 * time.Second, false},
+		{alice, 9 * time.Second, true},
+		{alice, 12 * time.Second, false},
+	}
+	for i, tt := range tests {
+		flood := ff.isFlood(channelID, tt.pubKey, start.Add(tt.offset))
+		require.Equal(t, tt.flood, flood, "Test %d: %+v", i, tt)
+	}
+}
+
+// Tests that floodFilter.set saves the settings to storage and that they are
+// loaded by newOrLoadFloodFilter.
+func Test_floodFilter_set(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	ff, err := newOrLoadFloodFilter(kv)
+	require.NoError(t, err)
+
+	require.NoError(t, ff.set(5, time.Minute))
+	loaded, err := newOrLoadFloodFilter(kv)
+	require.NoError(t, err)
+	maxMessages, window := loaded.get()
+	require.Equal(t, 5, maxMessages)
+	require.Equal(t, time.Minute, window)
+
+	require.Error(t, ff.set(-1, time.Minute))
+	require.Error(t, ff.set(5, -time.Minute))
+}
+
+// Tests that events.checkSlowMode only limits rate limited message types and
+// does not limit moderators.
+func Test_events_checkSlowMode(t *testing.T) {
+	prng := rand.New(rand.NewSource(32))
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+	channelID := randChannelID(prng, t)
+	user, moderator := makeEd25519PubKey(prng, t), makeEd25519PubKey(prng, t)
+	e.moderators.addModerator(channelID, moderator)
+	now := time.Unix(1000, 0)
+	e.slowMode.setInterval(channelID, time.Minute, now)
+
+	require.True(t, e.checkSlowMode(channelID, user, Text, now))
+	require.False(t, e.checkSlowMode(channelID, user, Text, now))
+	require.True(t, e.checkSlowMode(channelID, user, Delete, now))
+	require.True(t, e.checkSlowMode(channelID, moderator, Text, now))
+	require.True(t, e.checkSlowMode(channelID, moderator, Text, now))
+}
+
+// Tests that manager.SendGeneric returns SlowModeErr when sending a second
+// message to a channel within the slow mode interval and that other message
+// types are not limited.
+func Test_manager_SendGeneric_SlowMode(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	require.NoError(t, err)
+
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		events:          initEvents(&mockEventModel{}, 512, kv, crng),
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string), remote: nil},
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+	}
+
+	channelID := randChannelID(prng, t)
+	m.channels[*channelID] = &joinedChannel{broadcast: &mockBroadcastChannel{}}
+	m.slowMode.setInterval(channelID, time.Hour, time.Unix(10, 0))
+	params := cmix.CMIXParams{DebugTag: "ChannelTest"}
+
+	_, _, _, err = m.SendGeneric(
+		channelID, Text, []byte("hello"), ValidForever, false, params, nil)
+	require.NoError(t, err)
+
+	_, _, _, err = m.SendGeneric(
+		channelID, Text, []byte("hello"), ValidForever, false, params, nil)
+	require.ErrorIs(t, err, SlowModeErr)
+
+	_, _, _, err = m.SendGeneric(
+		channelID, Silent, []byte("hello"), ValidForever, false, params, nil)
+	require.NoError(t, err)
+
+	// Moderators are not limited
+	m.moderators.addModerator(channelID, pi.PubKey)
+	_, _, _, err = m.SendGeneric(
+		channelID, Text, []byte("hello"), ValidForever, false, params, nil)
+	require.NoError(t, err)
+}
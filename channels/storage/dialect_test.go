@@ -0,0 +1,331 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// testDialectors lists the databases that TestDialects runs the event model
+// test suite against. Each function returns a dialector for an empty database.
+//
+// SQLite is always tested, both as SQLite and as a generic database without
+// the SQLite specific features. To test another database, register its dialector
+// from a file with a build tag so that its driver is only required when the tag
+// is set. For example:
+//
+//	//go:build postgres
+//
+//	func init() {
+//		testDialectors["postgres"] = func(*testing.T) gorm.Dialector {
+//			return postgres.Open(os.Getenv("POSTGRES_TEST_DSN"))
+//		}
+//	}
+var testDialectors = map[string]func(t *testing.T) gorm.Dialector{
+	"sqlite": func(t *testing.T) gorm.Dialector {
+		return sqlite.Open(fmt.Sprintf(
+			"file:%s?mode=memory&cache=shared", t.Name()))
+	},
+	"generic": func(t *testing.T) gorm.Dialector {
+		return genericDialector{sqlite.Open(fmt.Sprintf(
+			"file:%s?mode=memory&cache=shared&_foreign_keys=on", t.Name()))}
+	},
+}
+
+// genericDialector is a SQLite dialector that reports a different name. The
+// event model only enables SQLite features, such as the full-text search
+// index, when the dialector is named SQLite, so this tests the path taken by
+// every other database.
+type genericDialector struct {
+	gorm.Dialector
+}
+
+// Name returns a name that is not SQLite.
+func (genericDialector) Name() string { return "generic" }
+
+// Runs the dialect-agnostic event model test suite against every database in
+// testDialectors.
+func TestDialects(t *testing.T) {
+	tests := map[string]func(t *testing.T, model *impl){
+		"Messages": testDialectMessages,
+		"Polls":    testDialectPolls,
+		"Search":   testDialectSearch,
+	}
+
+	for name, newDialector := range testDialectors {
+		t.Run(name, func(t *testing.T) {
+			for testName, test := range tests {
+				t.Run(testName, func(t *testing.T) {
+					test(t, newDialectImpl(t, newDialector(t)))
+				})
+			}
+		})
+	}
+}
+
+// newDialectImpl opens a new impl with the dialector. All tables are dropped
+// when the test completes so that the next test starts with an empty database.
+func newDialectImpl(t *testing.T, dialector gorm.Dialector) *impl {
+	model, err := newImplWithDialector(dialector, &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to open %s database: %+v", dialector.Name(), err)
+	}
+
+	t.Cleanup(func() {
+		err2 := model.db.Migrator().DropTable(&PollVote{}, &MessageRevision{},
+			&Message{}, &Channel{}, &File{})
+		if err2 != nil {
+			t.Errorf("Failed to drop tables: %+v", err2)
+		}
+		if sqlDb, err2 := model.db.DB(); err2 == nil {
+			_ = sqlDb.Close()
+		}
+	})
+
+	return model
+}
+
+// Tests that the tables created on MySQL are valid. MySQL rejects a literal
+// default on TEXT and BLOB columns, so none may have one, and the search text
+// must be a TEXT column so that it fits messages of any length. The statements
+// are generated with a dry run, so no server is needed.
+func TestMySQLSchema(t *testing.T) {
+	ddl := &ddlLogger{Interface: logger.Discard}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(127.0.0.1:3306)/db",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: ddl})
+	if err != nil {
+		t.Fatalf("Failed to open MySQL dialector: %+v", err)
+	}
+
+	err = db.Migrator().CreateTable(&Channel{}, &Message{}, &MessageRevision{},
+		&PollVote{}, &File{})
+	if err != nil {
+		t.Fatalf("Failed to create tables: %+v", err)
+	}
+
+	textDefault := regexp.MustCompile(
+		"` (tiny|medium|long)?(text|blob)[^,]* DEFAULT ")
+	var messagesTable string
+	for _, statement := range ddl.statements {
+		if column := textDefault.FindString(statement); column != "" {
+			t.Errorf("TEXT or BLOB column has a default: %s\n%s",
+				column, statement)
+		}
+		if strings.HasPrefix(statement, "CREATE TABLE `messages`") {
+			messagesTable = statement
+		}
+	}
+
+	if !strings.Contains(messagesTable, "`search_text` longtext,") {
+		t.Errorf("Search text column is not a nullable TEXT column: %s",
+			messagesTable)
+	}
+}
+
+// ddlLogger is a gorm logger that records the SQL of every statement.
+type ddlLogger struct {
+	logger.Interface
+	statements []string
+}
+
+func (l *ddlLogger) Trace(_ context.Context, _ time.Time,
+	fc func() (sql string, rowsAffected int64), _ error) {
+	sql, _ := fc()
+	l.statements = append(l.statements, sql)
+}
+
+// joinTestChannel joins a new channel with an ID derived from the test name.
+func joinTestChannel(t *testing.T, model *impl) *id.ID {
+	channelID := id.NewIdFromString(t.Name(), id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: channelID,
+		Name:        t.Name(),
+		Description: t.Name(),
+	})
+	return channelID
+}
+
+// Tests that messages can be received, updated, retrieved, and deleted, and
+// that leaving a channel deletes its messages.
+func testDialectMessages(t *testing.T, model *impl) {
+	channelID := joinTestChannel(t, model)
+
+	msgID := message.DeriveChannelMessageID(channelID, 1, []byte("text"))
+	timestamp := time.Now().Round(time.Second).UTC()
+	uuid := model.ReceiveMessage(channelID, msgID, "nickname", "text",
+		ed25519.PublicKey("sender"), 5, 0, timestamp, 0, rounds.Round{ID: 1},
+		channels.Text, channels.Unsent, false)
+	if uuid == 0 {
+		t.Fatal("Failed to receive message.")
+	}
+
+	replyID := message.DeriveChannelMessageID(channelID, 2, []byte("reply"))
+	model.ReceiveReply(channelID, replyID, msgID, "nickname", "reply",
+		ed25519.PublicKey("sender"), 5, 0, timestamp.Add(time.Second), 0,
+		rounds.Round{ID: 2}, channels.Text, channels.Delivered, false)
+
+	pinned := true
+	_, err := model.UpdateFromMessageID(
+		msgID, nil, nil, &pinned, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to update message: %+v", err)
+	}
+
+	msg, err := model.GetMessage(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get message: %+v", err)
+	}
+	if msg.Content == nil || string(msg.Content) != "text" || !msg.Pinned ||
+		!msg.Timestamp.Equal(timestamp) {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+
+	reply, err := model.GetMessage(replyID)
+	if err != nil {
+		t.Fatalf("Failed to get reply: %+v", err)
+	}
+	if reply.ParentMessageID != msgID {
+		t.Errorf("Unexpected reply parent.\nexpected: %s\nreceived: %s",
+			msgID, reply.ParentMessageID)
+	}
+
+	if err = model.DeleteMessage(replyID); err != nil {
+		t.Fatalf("Failed to delete reply: %+v", err)
+	}
+	if _, err = model.GetMessage(replyID); err == nil {
+		t.Error("Got reply after it was deleted.")
+	}
+
+	model.LeaveChannel(channelID)
+	if _, err = model.GetMessage(msgID); err == nil {
+		t.Error("Got message after leaving its channel.")
+	}
+}
+
+// Tests that poll votes are tallied.
+func testDialectPolls(t *testing.T, model *impl) {
+	channelID := joinTestChannel(t, model)
+
+	pollJson, err := json.Marshal(&channels.CMIXChannelPoll{
+		Question: "Question?", Options: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pollID := message.DeriveChannelMessageID(channelID, 1, pollJson)
+	timestamp := time.Now().Round(time.Second).UTC()
+	model.ReceiveMessage(channelID, pollID, "nickname", string(pollJson),
+		ed25519.PublicKey("sender"), 0, 0, timestamp, 0, rounds.Round{ID: 1},
+		channels.Poll, 0, false)
+
+	votes := []channels.ModelPollVote{
+		{MessageID: message.ID{1}, PubKey: []byte("alice"), Option: 1,
+			Timestamp: timestamp.Add(time.Minute)},
+		{MessageID: message.ID{2}, PubKey: []byte("bob"), Option: 1,
+			Timestamp: timestamp.Add(time.Minute)},
+	}
+	for j, vote := range votes {
+		if _, err = model.ReceivePollVote(pollID, vote); err != nil {
+			t.Fatalf("Failed to receive vote %d: %+v", j, err)
+		}
+	}
+
+	expected := channels.PollResults{Counts: []uint64{0, 2}}
+	results, err := model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatalf("Failed to get poll results: %+v", err)
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Unexpected poll results.\nexpected: %+v\nreceived: %+v",
+			expected, results)
+	}
+}
+
+// Tests that impl.Search returns the same messages with and without the
+// full-text search index, including messages that were edited.
+func testDialectSearch(t *testing.T, model *impl) {
+	channelID := joinTestChannel(t, model)
+
+	texts := []string{"Hello world", "hello, there", "goodbye world",
+		"hello-world", "shell"}
+	msgIDs := make([]message.ID, len(texts))
+	timestamp := time.Now().Round(time.Second).UTC()
+	for j, text := range texts {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			channelID, uint64(j), []byte(text))
+		model.ReceiveMessage(channelID, msgIDs[j], "nickname", text,
+			ed25519.PublicKey("sender"), 0, 0,
+			timestamp.Add(time.Duration(j)*time.Minute), 0,
+			rounds.Round{ID: id.Round(j)}, channels.Text, 0, false)
+	}
+
+	_, err := model.EditMessage(msgIDs[4], channels.MessageEdit{
+		EditID:    message.DeriveChannelMessageID(channelID, 5, []byte("edit")),
+		Text:      "shell edited",
+		Timestamp: timestamp.Add(5 * time.Minute),
+		Round:     5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to edit message: %+v", err)
+	}
+
+	tests := []struct {
+		query    SearchQuery
+		expected []int
+	}{
+		{SearchQuery{Text: "hello"}, []int{3, 1, 0}},
+		{SearchQuery{Text: "HELLO world"}, []int{3, 0}},
+		{SearchQuery{Text: "hel*"}, []int{3, 1, 0}},
+		{SearchQuery{Text: "hello-world"}, []int{3, 0}},
+		{SearchQuery{Text: "ell"}, nil},
+		{SearchQuery{Text: "world", Limit: 1}, []int{3}},
+		{SearchQuery{Text: "edited"}, []int{4}},
+	}
+
+	for _, tt := range tests {
+		msgs, err := model.Search(tt.query)
+		if err != nil {
+			t.Fatalf("Failed to search for %q: %+v", tt.query.Text, err)
+		}
+		var received []int
+		for _, msg := range msgs {
+			for j := range msgIDs {
+				if msg.MessageID == msgIDs[j] {
+					received = append(received, j)
+				}
+			}
+		}
+		if !reflect.DeepEqual(received, tt.expected) {
+			t.Errorf("Unexpected results for %q (full-text search: %t)."+
+				"\nexpected: %v\nreceived: %v", tt.query.Text,
+				model.fullTextSearch, tt.expected, received)
+		}
+	}
+}
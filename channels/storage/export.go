@@ -0,0 +1,274 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gorm.io/gorm"
+)
+
+// ExportVersion is the version of the ChannelExport format written by this
+// package. It is incremented whenever the format changes in a way that older
+// readers cannot load.
+const ExportVersion = 1
+
+// Exporter is implemented by the [channels.EventModel] returned by
+// NewEventModel. It allows the history of a channel to be archived.
+type Exporter interface {
+	// ExportChannel returns every stored message in the channel with the given
+	// ID, including reactions, replies, pins, edits, and poll votes.
+	ExportChannel(channelID *id.ID) (*ChannelExport, error)
+}
+
+// ChannelExport is the archived history of a single channel. It is written as
+// versioned JSON by WriteJSON and as a static HTML transcript by WriteHTML, and
+// can be loaded into an event model using ImportChannel.
+type ChannelExport struct {
+	// Version is the ExportVersion the export was written with.
+	Version int `json:"version"`
+
+	// Exported is the time the export was created.
+	Exported time.Time `json:"exported"`
+
+	Channel ExportedChannel `json:"channel"`
+
+	// Users lists everyone that sent a message in the channel with the
+	// nickname of their most recent message, ordered by public key.
+	Users []ExportedUser `json:"users"`
+
+	// Messages lists every message in the channel ordered from oldest to
+	// newest. Replies and reactions reference the message they respond to by
+	// its ParentMessageID.
+	Messages []ExportedMessage `json:"messages"`
+}
+
+// ExportedChannel describes the exported channel.
+type ExportedChannel struct {
+	ID          *id.ID `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ExportedUser describes a user that sent messages in the exported channel.
+type ExportedUser struct {
+	PubKey         ed25519.PublicKey `json:"pubKey"`
+	Nickname       string            `json:"nickname"`
+	DmToken        uint32            `json:"dmToken"`
+	CodesetVersion uint8             `json:"codesetVersion"`
+}
+
+// ExportedMessage describes a single exported message.
+type ExportedMessage struct {
+	MessageID message.ID `json:"messageID"`
+
+	// ParentMessageID is the ID of the message that this message replies or
+	// reacts to. It is omitted for messages that are neither.
+	ParentMessageID *message.ID `json:"parentMessageID,omitempty"`
+
+	Type           channels.MessageType `json:"type"`
+	Nickname       string               `json:"nickname"`
+	PubKey         ed25519.PublicKey    `json:"pubKey"`
+	DmToken        uint32               `json:"dmToken"`
+	CodesetVersion uint8                `json:"codesetVersion"`
+	Timestamp      time.Time            `json:"timestamp"`
+	Lease          time.Duration        `json:"lease"`
+	Round          id.Round             `json:"round"`
+	Status         channels.SentStatus  `json:"status"`
+	Hidden         bool                 `json:"hidden"`
+	Pinned         bool                 `json:"pinned"`
+
+	// Text is the current text of the message. Messages whose contents are not
+	// valid UTF-8 have their contents in Content instead.
+	Text    string `json:"text,omitempty"`
+	Content []byte `json:"content,omitempty"`
+
+	// Revisions lists every revision of the text of an edited message, oldest
+	// first. The first revision is the original text.
+	Revisions []channels.MessageEdit `json:"revisions,omitempty"`
+
+	// PollVotes lists the votes on a poll and PollClosed is the time the poll
+	// was closed, if it has been.
+	PollVotes  []channels.ModelPollVote `json:"pollVotes,omitempty"`
+	PollClosed *time.Time               `json:"pollClosed,omitempty"`
+}
+
+// Verify that impl adheres to the Exporter interface.
+var _ Exporter = (*impl)(nil)
+
+// ExportChannel returns every stored message in the channel with the given
+// ID, ordered from oldest to newest.
+func (i *impl) ExportChannel(channelID *id.ID) (*ChannelExport, error) {
+	parentErr := "failed to ExportChannel"
+
+	channel := &Channel{}
+	var messages []Message
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(channel, "id = ?", channelID.Marshal()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("timestamp")
+		}).Preload("PollVotes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("timestamp")
+		}).Where("channel_id = ?", channelID.Marshal()).
+			Order("timestamp").Order("id").Find(&messages).Error
+	})
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	export := &ChannelExport{
+		Version:  ExportVersion,
+		Exported: time.Now(),
+		Channel: ExportedChannel{
+			ID:          channelID,
+			Name:        channel.Name,
+			Description: channel.Description,
+		},
+		Messages: make([]ExportedMessage, len(messages)),
+	}
+
+	users := make(map[string]ExportedUser)
+	for j := range messages {
+		export.Messages[j], err = exportMessage(&messages[j])
+		if err != nil {
+			return nil, errors.WithMessage(err, parentErr)
+		}
+
+		// Messages are ordered by timestamp, so the last nickname is the newest
+		msg := export.Messages[j]
+		users[string(msg.PubKey)] = ExportedUser{
+			PubKey:         msg.PubKey,
+			Nickname:       msg.Nickname,
+			DmToken:        msg.DmToken,
+			CodesetVersion: msg.CodesetVersion,
+		}
+	}
+
+	export.Users = make([]ExportedUser, 0, len(users))
+	for _, user := range users {
+		export.Users = append(export.Users, user)
+	}
+	sort.Slice(export.Users, func(a, b int) bool {
+		return bytes.Compare(export.Users[a].PubKey, export.Users[b].PubKey) < 0
+	})
+
+	return export, nil
+}
+
+// exportMessage converts a Message from storage into an ExportedMessage.
+func exportMessage(msg *Message) (ExportedMessage, error) {
+	messageID, err := message.UnmarshalID(msg.MessageId)
+	if err != nil {
+		return ExportedMessage{}, err
+	}
+
+	exported := ExportedMessage{
+		MessageID:      messageID,
+		Type:           channels.MessageType(msg.Type),
+		Nickname:       msg.Nickname,
+		PubKey:         msg.Pubkey,
+		DmToken:        msg.DmToken,
+		CodesetVersion: msg.CodesetVersion,
+		Timestamp:      msg.Timestamp,
+		Lease:          msg.Lease,
+		Round:          id.Round(msg.Round),
+		Status:         channels.SentStatus(msg.Status),
+		Hidden:         msg.Hidden != nil && *msg.Hidden,
+		Pinned:         msg.Pinned != nil && *msg.Pinned,
+		PollClosed:     msg.PollClosed,
+	}
+
+	if msg.ParentMessageId != nil {
+		parentID, err := message.UnmarshalID(msg.ParentMessageId)
+		if err != nil {
+			return ExportedMessage{}, err
+		}
+		exported.ParentMessageID = &parentID
+	}
+
+	if utf8.Valid(msg.Text) {
+		exported.Text = string(msg.Text)
+	} else {
+		exported.Content = msg.Text
+	}
+
+	for _, r := range msg.Revisions {
+		var editID message.ID
+		copy(editID[:], r.EditId)
+		exported.Revisions = append(exported.Revisions, channels.MessageEdit{
+			EditID:    editID,
+			Text:      string(r.Text),
+			Timestamp: r.Timestamp,
+			Round:     id.Round(r.Round),
+		})
+	}
+
+	for _, v := range msg.PollVotes {
+		voteID, err := message.UnmarshalID(v.VoteId)
+		if err != nil {
+			return ExportedMessage{}, err
+		}
+		exported.PollVotes = append(exported.PollVotes, channels.ModelPollVote{
+			MessageID: voteID,
+			PubKey:    v.Pubkey,
+			Option:    v.Option,
+			Timestamp: v.Timestamp,
+			Round:     id.Round(v.Round),
+		})
+	}
+
+	return exported, nil
+}
+
+// content returns the current contents of the message.
+func (em *ExportedMessage) content() []byte {
+	if em.Content != nil {
+		return em.Content
+	}
+	return []byte(em.Text)
+}
+
+// WriteJSON writes the export to w as indented JSON.
+func (e *ChannelExport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// ReadChannelExport reads a ChannelExport written by WriteJSON. Returns an
+// error if the export was written by a newer version of this package.
+func ReadChannelExport(r io.Reader) (*ChannelExport, error) {
+	export := &ChannelExport{}
+	if err := json.NewDecoder(r).Decode(export); err != nil {
+		return nil, errors.Wrap(err, "failed to decode channel export")
+	}
+
+	if export.Version < 1 || export.Version > ExportVersion {
+		return nil, errors.Errorf("unsupported channel export version %d; "+
+			"this version supports up to %d", export.Version, ExportVersion)
+	}
+	if export.Channel.ID == nil {
+		return nil, errors.New("channel export is missing the channel ID")
+	}
+
+	return export, nil
+}
@@ -0,0 +1,188 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"io"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+)
+
+// transcriptTemplate is the static HTML transcript written by WriteHTML. It
+// has no scripts or external resources so that it can be archived as is.
+var transcriptTemplate = template.Must(template.New("transcript").Parse(
+	`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Channel.Name}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: auto; }
+article { border-bottom: 1px solid #ddd; padding: 0.5em 0; }
+.meta { color: #666; font-size: 0.85em; }
+.hidden { opacity: 0.5; }
+.pinned { border-left: 3px solid #c90; padding-left: 0.5em; }
+.text { white-space: pre-wrap; }
+.reactions span { margin-right: 0.75em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Channel.Name}}</h1>
+<p>{{.Channel.Description}}</p>
+<p class="meta">Channel {{.Channel.ID}} &middot; exported {{.Exported}} &middot; format version {{.Version}}</p>
+</header>
+<main>
+{{range .Messages}}<article id="{{.Anchor}}" class="{{if .Pinned}}pinned{{end}}{{if .Hidden}} hidden{{end}}">
+<div class="meta"><strong>{{.Nickname}}</strong> <code>{{.Sender}}</code> &middot; <time datetime="{{.ISOTime}}">{{.Time}}</time>{{if .Pinned}} &middot; pinned{{end}}{{if .Edited}} &middot; edited{{end}}{{if .Hidden}} &middot; hidden{{end}}{{if ne .Type "Text"}} &middot; {{.Type}}{{end}}</div>
+{{if .ReplyTo}}<div class="meta">In reply to <a href="#{{.ReplyTo}}">this message</a></div>
+{{end}}{{if .Poll}}<div class="text"><strong>{{.Poll.Question}}</strong>{{if .Poll.Closed}} (closed){{end}}</div>
+<ul>{{range .Poll.Options}}<li>{{.Option}}: {{.Votes}}</li>{{end}}</ul>
+{{else}}<div class="text">{{.Text}}</div>
+{{end}}{{if .Reactions}}<div class="reactions">{{range .Reactions}}<span>{{.Reaction}} {{.Count}}</span>{{end}}</div>
+{{end}}</article>
+{{end}}</main>
+</body>
+</html>
+`))
+
+// transcript is the data rendered by transcriptTemplate.
+type transcript struct {
+	Version  int
+	Exported string
+	Channel  ExportedChannel
+	Messages []transcriptMessage
+}
+
+// transcriptMessage is a single message in a transcript. Reactions are not
+// listed as messages but are counted on the message they react to.
+type transcriptMessage struct {
+	Anchor    string
+	ReplyTo   string
+	Nickname  string
+	Sender    string
+	Time      string
+	ISOTime   string
+	Type      string
+	Text      string
+	Pinned    bool
+	Hidden    bool
+	Edited    bool
+	Poll      *transcriptPoll
+	Reactions []transcriptReaction
+}
+
+// transcriptPoll is the question, options, and tally of a poll.
+type transcriptPoll struct {
+	Question string
+	Closed   bool
+	Options  []transcriptPollOption
+}
+
+// transcriptPollOption is a single option of a poll and its number of votes.
+type transcriptPollOption struct {
+	Option string
+	Votes  int
+}
+
+// transcriptReaction is the number of times a reaction was used on a message.
+type transcriptReaction struct {
+	Reaction string
+	Count    int
+}
+
+// WriteHTML writes the export to w as a static HTML transcript. The transcript
+// is meant to be read by people; use WriteJSON to write an export that can be
+// imported.
+func (e *ChannelExport) WriteHTML(w io.Writer) error {
+	t := transcript{
+		Version:  e.Version,
+		Exported: e.Exported.UTC().Format(time.RFC1123),
+		Channel:  e.Channel,
+	}
+
+	// Index of each message in the transcript so reactions can be counted
+	indexes := make(map[message.ID]int, len(e.Messages))
+	for _, msg := range e.Messages {
+		if msg.Type == channels.Reaction && msg.ParentMessageID != nil {
+			if j, exists := indexes[*msg.ParentMessageID]; exists {
+				t.Messages[j].addReaction(msg.Text)
+				continue
+			}
+		}
+
+		indexes[msg.MessageID] = len(t.Messages)
+		t.Messages = append(t.Messages, newTranscriptMessage(&msg))
+	}
+
+	return transcriptTemplate.Execute(w, t)
+}
+
+// newTranscriptMessage builds the transcriptMessage for the ExportedMessage.
+func newTranscriptMessage(msg *ExportedMessage) transcriptMessage {
+	tm := transcriptMessage{
+		Anchor:   messageAnchor(msg.MessageID),
+		Nickname: msg.Nickname,
+		Sender:   base64.StdEncoding.EncodeToString(msg.PubKey),
+		Time:     msg.Timestamp.UTC().Format(time.RFC1123),
+		ISOTime:  msg.Timestamp.UTC().Format(time.RFC3339),
+		Type:     msg.Type.String(),
+		Text:     msg.Text,
+		Pinned:   msg.Pinned,
+		Hidden:   msg.Hidden,
+		Edited:   len(msg.Revisions) > 1,
+	}
+	if msg.ParentMessageID != nil {
+		tm.ReplyTo = messageAnchor(*msg.ParentMessageID)
+	}
+	if msg.Content != nil {
+		tm.Text = base64.StdEncoding.EncodeToString(msg.Content)
+	}
+
+	if msg.Type == channels.Poll {
+		options := &channels.CMIXChannelPoll{}
+		if err := json.Unmarshal([]byte(msg.Text), options); err == nil {
+			tm.Poll = &transcriptPoll{
+				Question: options.Question,
+				Closed:   msg.PollClosed != nil,
+			}
+			tm.Poll.Options = make(
+				[]transcriptPollOption, len(options.Options))
+			for j, option := range options.Options {
+				tm.Poll.Options[j].Option = option
+			}
+			for _, vote := range msg.PollVotes {
+				if int(vote.Option) < len(tm.Poll.Options) {
+					tm.Poll.Options[vote.Option].Votes++
+				}
+			}
+		}
+	}
+
+	return tm
+}
+
+// addReaction counts the reaction on the message.
+func (tm *transcriptMessage) addReaction(reaction string) {
+	for j := range tm.Reactions {
+		if tm.Reactions[j].Reaction == reaction {
+			tm.Reactions[j].Count++
+			return
+		}
+	}
+	tm.Reactions = append(tm.Reactions, transcriptReaction{reaction, 1})
+}
+
+// messageAnchor returns the HTML element ID of the message in a transcript.
+func messageAnchor(messageID message.ID) string {
+	return "msg-" + base64.RawURLEncoding.EncodeToString(messageID.Bytes())
+}
@@ -0,0 +1,217 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/channels/memory"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that a channel exported by impl.ExportChannel and written as JSON can
+// be read back and imported into a fresh event model with the same messages,
+// reactions, replies, pins, edits, and poll votes.
+func TestImpl_ExportChannel(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_ExportChannel"
+	channelID := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: channelID,
+		Name:        testString,
+		Description: "description",
+	})
+	defer model.LeaveChannel(channelID)
+
+	alice := ed25519.PublicKey("alice")
+	bob := ed25519.PublicKey("bob")
+	start := time.Now().Round(0).UTC()
+	ids := make([]message.ID, 6)
+	for j := range ids {
+		ids[j] = message.DeriveChannelMessageID(
+			channelID, uint64(j), []byte(testString))
+	}
+
+	model.ReceiveMessage(channelID, ids[0], "Alice", "Hello", alice, 1, 0,
+		start, 0, rounds.Round{ID: 1}, channels.Text, channels.Delivered, false)
+	model.ReceiveReply(channelID, ids[1], ids[0], "Bob", "Hi Alice", bob, 2, 0,
+		start.Add(time.Minute), 0, rounds.Round{ID: 2}, channels.Text,
+		channels.Delivered, false)
+	model.ReceiveReaction(channelID, ids[2], ids[0], "Bob", "👍", bob, 2, 0,
+		start.Add(2*time.Minute), 0, rounds.Round{ID: 3}, channels.Reaction,
+		channels.Delivered, false)
+	pollJson, err := json.Marshal(&channels.CMIXChannelPoll{
+		Question: "Lunch?", Options: []string{"Yes", "No"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model.ReceiveMessage(channelID, ids[3], "Alice", string(pollJson), alice, 1,
+		0, start.Add(3*time.Minute), 0, rounds.Round{ID: 4}, channels.Poll,
+		channels.Delivered, false)
+
+	pinned := true
+	if _, err = model.UpdateFromMessageID(
+		ids[0], nil, nil, &pinned, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err = model.EditMessage(ids[1], channels.MessageEdit{
+		EditID:    ids[4],
+		Text:      "Hi, Alice",
+		Timestamp: start.Add(4 * time.Minute),
+		Round:     5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = model.ReceivePollVote(ids[3], channels.ModelPollVote{
+		MessageID: ids[5],
+		PubKey:    bob,
+		Option:    1,
+		Timestamp: start.Add(5 * time.Minute),
+		Round:     6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = model.ClosePoll(ids[3], start.Add(6*time.Minute), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export, err := model.ExportChannel(channelID)
+	if err != nil {
+		t.Fatalf("Failed to export channel: %+v", err)
+	}
+	if len(export.Messages) != 4 {
+		t.Fatalf("Unexpected number of messages.\nexpected: %d\nreceived: %d",
+			4, len(export.Messages))
+	}
+	expectedUsers := []ExportedUser{
+		{PubKey: alice, Nickname: "Alice", DmToken: 1},
+		{PubKey: bob, Nickname: "Bob", DmToken: 2},
+	}
+	if !reflect.DeepEqual(expectedUsers, export.Users) {
+		t.Errorf("Unexpected users.\nexpected: %+v\nreceived: %+v",
+			expectedUsers, export.Users)
+	}
+
+	var buf bytes.Buffer
+	if err = export.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadChannelExport(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read export: %+v", err)
+	}
+
+	imported := memory.NewEventModel()
+	if err = ImportChannel(imported, loaded); err != nil {
+		t.Fatalf("Failed to import channel: %+v", err)
+	}
+
+	received := imported.ChannelMessages(channelID)
+	if len(received) != len(export.Messages) {
+		t.Fatalf("Unexpected number of imported messages."+
+			"\nexpected: %d\nreceived: %d", len(export.Messages), len(received))
+	}
+	for j, msg := range received {
+		expected := export.Messages[j]
+		if msg.MessageID != expected.MessageID ||
+			string(msg.Content) != expected.Text ||
+			msg.Pinned != expected.Pinned || msg.Nickname != expected.Nickname ||
+			!msg.Timestamp.Equal(expected.Timestamp) {
+			t.Errorf("Imported message %d does not match export."+
+				"\nexpected: %+v\nreceived: %+v", j, expected, msg)
+		}
+	}
+
+	revisions, err := imported.GetMessageRevisions(ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[1].Text != "Hi, Alice" {
+		t.Errorf("Unexpected revisions: %+v", revisions)
+	}
+	results, err := imported.GetPollResults(ids[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedResults := channels.PollResults{Counts: []uint64{0, 1}, Closed: true}
+	if !reflect.DeepEqual(expectedResults, results) {
+		t.Errorf("Unexpected poll results.\nexpected: %+v\nreceived: %+v",
+			expectedResults, results)
+	}
+}
+
+// Tests that ChannelExport.WriteHTML writes a transcript with the messages,
+// counts reactions on the message they react to, and escapes message text.
+func TestChannelExport_WriteHTML(t *testing.T) {
+	parentID := message.ID{1}
+	export := &ChannelExport{
+		Version:  ExportVersion,
+		Exported: time.Unix(0, 0),
+		Channel:  ExportedChannel{ID: &id.ID{1}, Name: "General"},
+		Messages: []ExportedMessage{
+			{MessageID: parentID, Type: channels.Text, Nickname: "Alice",
+				Text: "<b>hello</b>", Pinned: true},
+			{MessageID: message.ID{2}, ParentMessageID: &parentID,
+				Type: channels.Reaction, Text: "👍"},
+			{MessageID: message.ID{3}, ParentMessageID: &parentID,
+				Type: channels.Reaction, Text: "👍"},
+			{MessageID: message.ID{4}, ParentMessageID: &parentID,
+				Type: channels.Text, Nickname: "Bob", Text: "reply"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteHTML(&buf); err != nil {
+		t.Fatalf("Failed to write HTML: %+v", err)
+	}
+	transcript := buf.String()
+
+	for _, s := range []string{"<h1>General</h1>", "&lt;b&gt;hello&lt;/b&gt;",
+		"👍 2", "In reply to", messageAnchor(parentID), "pinned"} {
+		if !strings.Contains(transcript, s) {
+			t.Errorf("Transcript does not contain %q:\n%s", s, transcript)
+		}
+	}
+	if strings.Contains(transcript, "<b>hello</b>") {
+		t.Errorf("Transcript contains unescaped text:\n%s", transcript)
+	}
+	if n := strings.Count(transcript, "<article"); n != 2 {
+		t.Errorf("Unexpected number of messages in transcript."+
+			"\nexpected: %d\nreceived: %d", 2, n)
+	}
+}
+
+// Tests that ReadChannelExport rejects exports with an unsupported version.
+func TestReadChannelExport_Version(t *testing.T) {
+	for _, version := range []int{0, ExportVersion + 1} {
+		data := `{"version":` + strconv.Itoa(version) + `,"channel":{}}`
+		if _, err := ReadChannelExport(strings.NewReader(data)); err == nil {
+			t.Errorf("Did not reject export with version %d.", version)
+		}
+	}
+}
@@ -9,8 +9,10 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
+	"encoding/json"
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/channels"
@@ -28,6 +30,10 @@ const (
 
 	// Determines maximum runtime (in seconds) of DB queries.
 	dbTimeout = 3 * time.Second
+
+	// Name of the SQLite gorm.Dialector. Some features, such as the full-text
+	// search index, are only available on SQLite.
+	sqliteDialect = "sqlite"
 )
 
 // newContext builds a context for database operations.
@@ -78,6 +84,38 @@ func (i *impl) LeaveChannel(channelID *id.ID) {
 	go i.cbs.ChannelUpdate(channelID, true)
 }
 
+// UpdateChannelMetadata is called whenever the channel admin updates the
+// metadata of the channel. Replaces the description, topic, rules, and icon of
+// the Channel.
+func (i *impl) UpdateChannelMetadata(
+	channelID *id.ID, metadata channels.ChannelMetadata) {
+	parentErr := errors.New("failed to UpdateChannelMetadata")
+
+	// Select the columns so that empty values are also saved
+	ctx, cancel := newContext()
+	result := i.db.WithContext(ctx).Model(&Channel{Id: channelID.Marshal()}).
+		Select("Description", "Topic", "Rules", "Icon").
+		Updates(Channel{
+			Description: metadata.Description,
+			Topic:       metadata.Topic,
+			Rules:       metadata.Rules,
+			Icon:        metadata.Icon,
+		})
+	cancel()
+
+	if result.Error != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to update Channel: %+v", result.Error))
+		return
+	} else if result.RowsAffected == 0 {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Channel %s does not exist", channelID))
+		return
+	}
+	jww.DEBUG.Printf("Successfully updated metadata of channel: %s", channelID)
+	go i.cbs.ChannelUpdate(channelID, false)
+}
+
 // ReceiveMessage is called whenever a message is received on a given channel.
 // Creates the Message.
 func (i *impl) ReceiveMessage(channelID *id.ID, messageID message.ID, nickname,
@@ -248,9 +286,10 @@ func (i *impl) UpdateFromMessageID(messageID message.ID, timestamp *time.Time,
 func (i *impl) GetMessage(messageID message.ID) (channels.ModelMessage, error) {
 	parentErr := "failed to GetMessage"
 
-	result := &Message{MessageId: messageID.Bytes()}
+	result := &Message{}
 	ctx, cancel := newContext()
-	err := i.db.WithContext(ctx).Take(result).Error
+	err := i.db.WithContext(ctx).Take(
+		result, "message_id = ?", messageID.Marshal()).Error
 	cancel()
 	if err != nil {
 		if errors.Is(gorm.ErrRecordNotFound, err) {
@@ -259,39 +298,11 @@ func (i *impl) GetMessage(messageID message.ID) (channels.ModelMessage, error) {
 		return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
 	}
 
-	var channelId *id.ID
-	if result.ChannelId != nil {
-		channelId, err = id.Unmarshal(result.ChannelId)
-		if err != nil {
-			return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
-		}
-	}
-
-	var parentMsgId message.ID
-	if result.ParentMessageId != nil {
-		parentMsgId, err = message.UnmarshalID(result.ParentMessageId)
-		if err != nil {
-			return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
-		}
+	msg, err := convertMessage(result)
+	if err != nil {
+		return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
 	}
-
-	return channels.ModelMessage{
-		UUID:            uint64(result.Id),
-		Nickname:        result.Nickname,
-		MessageID:       messageID,
-		ChannelID:       channelId,
-		ParentMessageID: parentMsgId,
-		Timestamp:       result.Timestamp,
-		Lease:           result.Lease,
-		Status:          channels.SentStatus(result.Status),
-		Hidden:          *result.Hidden,
-		Pinned:          *result.Pinned,
-		Content:         result.Text,
-		Type:            channels.MessageType(result.Type),
-		Round:           id.Round(result.Round),
-		PubKey:          result.Pubkey,
-		CodesetVersion:  result.CodesetVersion,
-	}, nil
+	return msg, nil
 }
 
 // MuteUser is called whenever a user is muted or unmuted.
@@ -322,6 +333,131 @@ func (i *impl) DeleteMessage(messageID message.ID) error {
 	return nil
 }
 
+// EditMessage is called whenever the text of the message with the given
+// [message.ID] is edited. The original text of the message and each edit are
+// saved as a MessageRevision and the text of the message is set to the newest
+// revision. Edits that have already been received are ignored.
+//
+// Returns an error if the message cannot be edited. It must return
+// channels.NoMessageErr if the message does not exist.
+func (i *impl) EditMessage(
+	messageID message.ID, edit channels.MessageEdit) (uint64, error) {
+	parentErr := "failed to EditMessage"
+
+	currentMessage := &Message{}
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(currentMessage, "message_id = ?", messageID.Marshal()).Error
+		if err != nil {
+			return err
+		}
+
+		var revisions []MessageRevision
+		err = tx.Where("message_uuid = ?", currentMessage.Id).
+			Order("timestamp").Find(&revisions).Error
+		if err != nil {
+			return err
+		}
+
+		// Ignore edits that have already been saved (e.g., on replay)
+		for _, r := range revisions {
+			if bytes.Equal(r.EditId, edit.EditID.Marshal()) {
+				return nil
+			}
+		}
+
+		// Save the original text as the first revision on the first edit
+		if len(revisions) == 0 {
+			original := MessageRevision{
+				MessageUuid: currentMessage.Id,
+				Text:        currentMessage.Text,
+				Timestamp:   currentMessage.Timestamp,
+				Round:       currentMessage.Round,
+			}
+			if err = tx.Create(&original).Error; err != nil {
+				return err
+			}
+			revisions = append(revisions, original)
+		}
+
+		newRevision := MessageRevision{
+			MessageUuid: currentMessage.Id,
+			EditId:      edit.EditID.Marshal(),
+			Text:        []byte(edit.Text),
+			Timestamp:   edit.Timestamp,
+			Round:       int64(edit.Round),
+		}
+		if err = tx.Create(&newRevision).Error; err != nil {
+			return err
+		}
+
+		// Edits may arrive out of order, so only replace the text of the
+		// message if this is the newest revision
+		if newRevision.Timestamp.Before(
+			revisions[len(revisions)-1].Timestamp) {
+			return nil
+		}
+		updates := map[string]interface{}{"text": newRevision.Text}
+		if !i.fullTextSearch {
+			updates["search_text"] = searchText(edit.Text)
+		}
+		return tx.Model(currentMessage).Updates(updates).Error
+	})
+	cancel()
+
+	if err != nil {
+		if errors.Is(gorm.ErrRecordNotFound, err) {
+			return 0, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return 0, errors.WithMessage(err, parentErr)
+	}
+	channelId := &id.ID{}
+	copy(channelId[:], currentMessage.ChannelId)
+
+	go i.cbs.MessageReceived(currentMessage.Id, channelId, true)
+	return uint64(currentMessage.Id), nil
+}
+
+// GetMessageRevisions returns every revision of the text of the message with
+// the given [message.ID], ordered from oldest to newest. The first revision is
+// the original text of the message. Returns an empty list if the message has
+// never been edited.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (i *impl) GetMessageRevisions(
+	messageID message.ID) ([]channels.MessageEdit, error) {
+	parentErr := "failed to GetMessageRevisions"
+
+	result := &Message{}
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("timestamp")
+	}).Take(result, "message_id = ?", messageID.Marshal()).Error
+	cancel()
+	if err != nil {
+		if errors.Is(gorm.ErrRecordNotFound, err) {
+			return nil, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	edits := make([]channels.MessageEdit, len(result.Revisions))
+	for j, r := range result.Revisions {
+		var editID message.ID
+		copy(editID[:], r.EditId)
+		edits[j] = channels.MessageEdit{
+			EditID:    editID,
+			Text:      string(r.Text),
+			Timestamp: r.Timestamp,
+			Round:     id.Round(r.Round),
+		}
+	}
+
+	return edits, nil
+}
+
 // receiveHelper is a generic helper for receiving a Message.
 // Returns UUID of the received Message as defined by the database.
 func (i *impl) receiveHelper(channelID *id.ID, messageID message.ID,
@@ -336,6 +472,9 @@ func (i *impl) receiveHelper(channelID *id.ID, messageID message.ID,
 		channelID.Marshal(), messageID.Bytes(), parentMsgId, nickname,
 		text, pubKey, dmToken, codeset, timestamp, lease, round.ID,
 		messageType, false, hidden, status)
+	if !i.fullTextSearch {
+		msgToInsert.SearchText = searchText(text)
+	}
 
 	ctx, cancel := newContext()
 	err := i.db.WithContext(ctx).Create(msgToInsert).Error
@@ -349,6 +488,56 @@ func (i *impl) receiveHelper(channelID *id.ID, messageID message.ID,
 	return uint64(msgToInsert.Id), nil
 }
 
+// convertMessage is a private helper that converts a Message from storage into
+// a [channels.ModelMessage].
+func convertMessage(msg *Message) (channels.ModelMessage, error) {
+	var channelId *id.ID
+	if msg.ChannelId != nil {
+		var err error
+		channelId, err = id.Unmarshal(msg.ChannelId)
+		if err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	var messageId, parentMsgId message.ID
+	copy(messageId[:], msg.MessageId)
+	if msg.ParentMessageId != nil {
+		var err error
+		parentMsgId, err = message.UnmarshalID(msg.ParentMessageId)
+		if err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	var spans []channels.TextSpan
+	if len(msg.Spans) > 0 {
+		if err := json.Unmarshal(msg.Spans, &spans); err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	return channels.ModelMessage{
+		UUID:            uint64(msg.Id),
+		Nickname:        msg.Nickname,
+		MessageID:       messageId,
+		ChannelID:       channelId,
+		ParentMessageID: parentMsgId,
+		Timestamp:       msg.Timestamp,
+		Lease:           msg.Lease,
+		Status:          channels.SentStatus(msg.Status),
+		Hidden:          *msg.Hidden,
+		Pinned:          *msg.Pinned,
+		Content:         msg.Text,
+		Type:            channels.MessageType(msg.Type),
+		Round:           id.Round(msg.Round),
+		PubKey:          msg.Pubkey,
+		CodesetVersion:  msg.CodesetVersion,
+		DmToken:         msg.DmToken,
+		Spans:           spans,
+	}, nil
+}
+
 // buildMessage is a private helper that converts typical [channels.EventModel]
 // inputs into a basic Message structure for insertion into storage.
 //
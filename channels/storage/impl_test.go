@@ -11,6 +11,7 @@ package storage
 
 import (
 	"crypto/ed25519"
+	"reflect"
 	"testing"
 	"time"
 
@@ -100,3 +101,152 @@ func TestImpl(t *testing.T) {
 		t.Fatal("Expected to be unable to get deleted Message")
 	}
 }
+
+// Tests that impl.EditMessage updates the text of the message to the newest
+// revision, ignores duplicate edits, and that impl.GetMessageRevisions returns
+// every revision in order.
+func TestImpl_EditMessage(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_EditMessage"
+	testChannelId := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: testChannelId,
+		Name:        testString,
+		Description: testString,
+	})
+	defer model.LeaveChannel(testChannelId)
+
+	testMsgId := message.DeriveChannelMessageID(
+		testChannelId, 10, []byte(testString))
+	timestamp := time.Now().Round(0)
+	uuid := model.ReceiveMessage(testChannelId, testMsgId, testString,
+		"original", []byte(testString), 0, 0, timestamp, 0, rounds.Round{ID: 10}, 0, 0, false)
+
+	// Editing a message that does not exist must return NoMessageErr
+	_, err = model.EditMessage(message.ID{1}, channels.MessageEdit{})
+	if !channels.CheckNoMessageErr(err) {
+		t.Fatalf("Did not get expected error for unknown message: %+v", err)
+	}
+
+	edits := []channels.MessageEdit{
+		{EditID: message.ID{2}, Text: "second",
+			Timestamp: timestamp.Add(2 * time.Minute), Round: 12},
+		{EditID: message.ID{1}, Text: "first",
+			Timestamp: timestamp.Add(time.Minute), Round: 11},
+		{EditID: message.ID{2}, Text: "second",
+			Timestamp: timestamp.Add(2 * time.Minute), Round: 12},
+	}
+	for j, edit := range edits {
+		editedUuid, err2 := model.EditMessage(testMsgId, edit)
+		if err2 != nil {
+			t.Fatalf("Failed to edit message (%d): %+v", j, err2)
+		} else if editedUuid != uuid {
+			t.Errorf("UUIDs differ (%d).\nexpected: %d\nreceived: %d",
+				j, uuid, editedUuid)
+		}
+	}
+
+	gotMsg, err := model.GetMessage(testMsgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotMsg.Content) != "second" {
+		t.Errorf("Message text not set to newest revision."+
+			"\nexpected: %q\nreceived: %q", "second", gotMsg.Content)
+	}
+
+	revisions, err := model.GetMessageRevisions(testMsgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"original", "first", "second"}
+	if len(revisions) != len(expected) {
+		t.Fatalf("Unexpected number of revisions.\nexpected: %d\nreceived: %d",
+			len(expected), len(revisions))
+	}
+	for j, r := range revisions {
+		if r.Text != expected[j] {
+			t.Errorf("Unexpected text for revision %d.\nexpected: %q"+
+				"\nreceived: %q", j, expected[j], r.Text)
+		}
+	}
+	if revisions[0].EditID != (message.ID{}) {
+		t.Errorf("Original revision should not have an edit ID: %s",
+			revisions[0].EditID)
+	}
+}
+
+// channelUpdateCbs is a dummyCbs that reports calls to ChannelUpdate.
+type channelUpdateCbs struct {
+	dummyCbs
+	updates chan *id.ID
+}
+
+func (c *channelUpdateCbs) ChannelUpdate(channelID *id.ID, deleted bool) {
+	if !deleted {
+		c.updates <- channelID
+	}
+}
+
+// Tests that impl.UpdateChannelMetadata replaces the description, topic,
+// rules, and icon of the channel and calls ChannelUpdate.
+func TestImpl_UpdateChannelMetadata(t *testing.T) {
+	cbs := &channelUpdateCbs{updates: make(chan *id.ID, 10)}
+	model, err := newImpl("", cbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_UpdateChannelMetadata"
+	testChannelId := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: testChannelId,
+		Name:        testString,
+		Description: testString,
+	})
+	defer model.LeaveChannel(testChannelId)
+	<-cbs.updates
+
+	metadata := channels.ChannelMetadata{
+		Description: "description",
+		Icon:        []byte("icon"),
+		Topic:       "topic",
+		Rules:       "rules",
+	}
+	model.UpdateChannelMetadata(testChannelId, metadata)
+	select {
+	case channelID := <-cbs.updates:
+		if !testChannelId.Cmp(channelID) {
+			t.Errorf("Unexpected channel ID.\nexpected: %s\nreceived: %s",
+				testChannelId, channelID)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Timed out waiting for ChannelUpdate.")
+	}
+
+	// Empty values must replace the previous values
+	metadata.Topic = ""
+	model.UpdateChannelMetadata(testChannelId, metadata)
+	<-cbs.updates
+
+	channel := &Channel{}
+	err = model.db.Take(channel, "id = ?", testChannelId.Marshal()).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Channel{
+		Id:          testChannelId.Marshal(),
+		Name:        testString,
+		Description: metadata.Description,
+		Icon:        metadata.Icon,
+		Rules:       metadata.Rules,
+	}
+	if !reflect.DeepEqual(expected, *channel) {
+		t.Errorf("Unexpected channel.\nexpected: %+v\nreceived: %+v",
+			expected, *channel)
+	}
+}
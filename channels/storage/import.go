@@ -0,0 +1,109 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+)
+
+// ImportChannel loads a ChannelExport into the event model. The channel is
+// joined and each message is received in order, followed by its pin, edits,
+// and poll votes, so that the model ends up with the same history as the model
+// the export was taken from.
+//
+// The model should not already contain the channel. The UUIDs assigned by the
+// model are not preserved.
+func ImportChannel(model channels.EventModel, export *ChannelExport) error {
+	if export.Version < 1 || export.Version > ExportVersion {
+		return errors.Errorf("unsupported channel export version %d",
+			export.Version)
+	}
+
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: export.Channel.ID,
+		Name:        export.Channel.Name,
+		Description: export.Channel.Description,
+	})
+
+	for j := range export.Messages {
+		if err := importMessage(model, export, &export.Messages[j]); err != nil {
+			return errors.WithMessagef(err, "failed to import message %s",
+				export.Messages[j].MessageID)
+		}
+	}
+
+	return nil
+}
+
+// importMessage receives a single ExportedMessage and replays its pin, edits,
+// and poll votes on the model.
+func importMessage(
+	model channels.EventModel, export *ChannelExport, msg *ExportedMessage) error {
+	// Edited messages are received with their original text and then each
+	// edit is replayed
+	text := string(msg.content())
+	if len(msg.Revisions) > 0 {
+		text = msg.Revisions[0].Text
+	}
+
+	channelID := export.Channel.ID
+	round := rounds.Round{ID: msg.Round}
+	var uuid uint64
+	switch {
+	case msg.ParentMessageID == nil:
+		uuid = model.ReceiveMessage(channelID, msg.MessageID, msg.Nickname,
+			text, msg.PubKey, msg.DmToken, msg.CodesetVersion, msg.Timestamp,
+			msg.Lease, round, msg.Type, msg.Status, msg.Hidden)
+	case msg.Type == channels.Reaction:
+		uuid = model.ReceiveReaction(channelID, msg.MessageID,
+			*msg.ParentMessageID, msg.Nickname, text, msg.PubKey, msg.DmToken,
+			msg.CodesetVersion, msg.Timestamp, msg.Lease, round, msg.Type,
+			msg.Status, msg.Hidden)
+	default:
+		uuid = model.ReceiveReply(channelID, msg.MessageID,
+			*msg.ParentMessageID, msg.Nickname, text, msg.PubKey, msg.DmToken,
+			msg.CodesetVersion, msg.Timestamp, msg.Lease, round, msg.Type,
+			msg.Status, msg.Hidden)
+	}
+	if uuid == 0 {
+		return errors.New("message was not received by the event model")
+	}
+
+	if msg.Pinned {
+		pinned := true
+		_, err := model.UpdateFromMessageID(
+			msg.MessageID, nil, nil, &pinned, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(msg.Revisions) > 0 {
+		for _, edit := range msg.Revisions[1:] {
+			if _, err := model.EditMessage(msg.MessageID, edit); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, vote := range msg.PollVotes {
+		if _, err := model.ReceivePollVote(msg.MessageID, vote); err != nil {
+			return err
+		}
+	}
+	if msg.PollClosed != nil {
+		_, err := model.ClosePoll(msg.MessageID, *msg.PollClosed, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
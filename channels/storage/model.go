@@ -10,6 +10,10 @@ import (
 	"time"
 )
 
+// Byte slice columns that are keys or indexed have a size so that they are
+// stored as VARBINARY instead of BLOB in MySQL, which cannot index a BLOB
+// without a prefix length. The size is ignored by SQLite and PostgreSQL.
+
 // Message defines the SQL representation of a single Message.
 //
 // A Message belongs to one Channel.
@@ -21,9 +25,9 @@ import (
 type Message struct {
 	Id              int64         `gorm:"primaryKey;autoIncrement:true"`
 	Nickname        string        `gorm:"not null"`
-	MessageId       []byte        `gorm:"uniqueIndex;not null"`
-	ChannelId       []byte        `gorm:"index;not null"`
-	ParentMessageId []byte        `gorm:"index"`
+	MessageId       []byte        `gorm:"size:64;uniqueIndex;not null"`
+	ChannelId       []byte        `gorm:"size:64;index;not null"`
+	ParentMessageId []byte        `gorm:"size:64;index"`
 	Timestamp       time.Time     `gorm:"index;not null"`
 	Lease           time.Duration `gorm:"not null"`
 	Status          uint8         `gorm:"not null"`
@@ -31,6 +35,12 @@ type Message struct {
 	Type            uint16        `gorm:"not null"`
 	Round           int64         `gorm:"not null"`
 
+	// SearchText is the lowercase words of Text used by Search on databases
+	// without the full-text search index. It is empty otherwise. It is always
+	// written on insert, so it has no default; a literal default is rejected
+	// on MySQL TEXT columns and a size would limit the length of messages.
+	SearchText string
+
 	// Pointer to enforce zero-value reading in ORM.
 	Hidden *bool `gorm:"not null"`
 	Pinned *bool `gorm:"index;not null"`
@@ -39,23 +49,74 @@ type Message struct {
 	Pubkey         []byte `gorm:"not null"`
 	DmToken        uint32 `gorm:"not null"`
 	CodesetVersion uint8  `gorm:"not null"`
+
+	Revisions []MessageRevision `gorm:"foreignKey:MessageUuid;constraint:OnDelete:CASCADE"`
+
+	// PollClosed is the time a poll was closed by the channel admin. It is nil
+	// if the message is not a poll or if the poll is open.
+	PollClosed *time.Time `gorm:""`
+	PollVotes  []PollVote `gorm:"foreignKey:PollUuid;constraint:OnDelete:CASCADE"`
+
+	// Spans is the JSON of the []channels.TextSpan parsed from the text of
+	// the message. It is nil if the text has no spans.
+	Spans []byte `gorm:""`
+}
+
+// MessageRevision defines the SQL representation of a single revision of the
+// text of a Message.
+//
+// A MessageRevision belongs to one Message. A Message only has revisions once
+// it has been edited, at which point its original text is saved as the first
+// revision.
+type MessageRevision struct {
+	Id          int64 `gorm:"primaryKey;autoIncrement:true"`
+	MessageUuid int64 `gorm:"index;not null"`
+
+	// EditId is the message ID of the edit that contained this revision. It is
+	// nil for the original text of the Message.
+	EditId    []byte    `gorm:"size:64;uniqueIndex"`
+	Text      []byte    `gorm:"not null"`
+	Timestamp time.Time `gorm:"not null"`
+	Round     int64     `gorm:"not null"`
+}
+
+// PollVote defines the SQL representation of a single vote on a poll.
+//
+// A PollVote belongs to one Message (the poll). Each user has at most one vote
+// per poll.
+type PollVote struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement:true"`
+	PollUuid int64  `gorm:"uniqueIndex:idx_poll_votes_voter;not null"`
+	Pubkey   []byte `gorm:"size:64;uniqueIndex:idx_poll_votes_voter;not null"`
+
+	// VoteId is the message ID of the vote.
+	VoteId    []byte    `gorm:"not null"`
+	Option    uint32    `gorm:"not null"`
+	Timestamp time.Time `gorm:"not null"`
+	Round     int64     `gorm:"not null"`
 }
 
 // Channel defines the SQL representation of a single Channel.
 //
 // A Channel has many Message.
 type Channel struct {
-	Id          []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	Id          []byte `gorm:"size:64;primaryKey;not null;autoIncrement:false"`
 	Name        string `gorm:"not null"`
 	Description string `gorm:"not null"`
 
+	// Topic, Rules, and Icon are set by channel metadata updates. Description
+	// is also replaced by updates.
+	Topic string
+	Rules string
+	Icon  []byte
+
 	Messages []Message `gorm:"constraint:OnDelete:CASCADE"`
 }
 
 // File defines the SQL representation of a single File.
 type File struct {
 	// Id is a unique identifier for a given File.
-	Id []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	Id []byte `gorm:"size:64;primaryKey;not null;autoIncrement:false"`
 
 	// Data stores the actual contents of the File.
 	Data []byte
@@ -0,0 +1,197 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verify that impl adheres to the channels.PollTallier interface.
+var _ channels.PollTallier = (*impl)(nil)
+
+// ReceivePollVote is called whenever a vote is received for the poll with the
+// given [message.ID]. A vote replaces any vote from the same user with an older
+// timestamp. Duplicate votes and votes sent after the poll was closed are
+// ignored.
+//
+// Returns an error if the vote cannot be saved. It must return
+// channels.NoMessageErr if the poll does not exist.
+func (i *impl) ReceivePollVote(
+	pollID message.ID, vote channels.ModelPollVote) (uint64, error) {
+	parentErr := "failed to ReceivePollVote"
+
+	poll := &Message{}
+	var updated bool
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(poll, "message_id = ? AND type = ?",
+			pollID.Marshal(), uint16(channels.Poll)).Error
+		if err != nil {
+			return err
+		}
+
+		// Ignore votes sent after the poll was closed
+		if poll.PollClosed != nil && !vote.Timestamp.Before(*poll.PollClosed) {
+			return nil
+		}
+
+		newVote := &PollVote{
+			PollUuid:  poll.Id,
+			Pubkey:    vote.PubKey,
+			VoteId:    vote.MessageID.Marshal(),
+			Option:    vote.Option,
+			Timestamp: vote.Timestamp,
+			Round:     int64(vote.Round),
+		}
+
+		currentVote := &PollVote{}
+		err = tx.Take(currentVote, "poll_uuid = ? AND pubkey = ?",
+			poll.Id, []byte(vote.PubKey)).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			updated = true
+			return tx.Create(newVote).Error
+		} else if err != nil {
+			return err
+		}
+
+		// Only replace the current vote with a newer one
+		if bytes.Equal(currentVote.VoteId, newVote.VoteId) ||
+			!newVote.Timestamp.After(currentVote.Timestamp) {
+			return nil
+		}
+		newVote.Id = currentVote.Id
+		updated = true
+		return tx.Save(newVote).Error
+	})
+	cancel()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return 0, errors.WithMessage(err, parentErr)
+	}
+
+	if updated {
+		channelId := &id.ID{}
+		copy(channelId[:], poll.ChannelId)
+		go i.cbs.MessageReceived(poll.Id, channelId, true)
+	}
+	return uint64(poll.Id), nil
+}
+
+// ClosePoll is called whenever the channel admin closes or reopens the poll
+// with the given [message.ID]. Votes sent after the timestamp are removed.
+//
+// Returns an error if the poll cannot be updated. It must return
+// channels.NoMessageErr if the poll does not exist.
+func (i *impl) ClosePoll(
+	pollID message.ID, timestamp time.Time, reopen bool) (uint64, error) {
+	parentErr := "failed to ClosePoll"
+
+	poll := &Message{}
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(poll, "message_id = ? AND type = ?",
+			pollID.Marshal(), uint16(channels.Poll)).Error
+		if err != nil {
+			return err
+		}
+
+		if reopen {
+			return tx.Model(poll).Update("poll_closed", nil).Error
+		}
+
+		// Remove votes that arrived before the close but were sent after it
+		err = tx.Where("poll_uuid = ? AND timestamp >= ?", poll.Id, timestamp).
+			Delete(&PollVote{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(poll).Update("poll_closed", timestamp).Error
+	})
+	cancel()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return 0, errors.WithMessage(err, parentErr)
+	}
+
+	channelId := &id.ID{}
+	copy(channelId[:], poll.ChannelId)
+	go i.cbs.MessageReceived(poll.Id, channelId, true)
+	return uint64(poll.Id), nil
+}
+
+// GetPollResults returns the tally of the votes of the poll with the given
+// [message.ID].
+//
+// Returns channels.NoMessageErr if the poll does not exist.
+func (i *impl) GetPollResults(pollID message.ID) (channels.PollResults, error) {
+	parentErr := "failed to GetPollResults"
+
+	poll := &Message{}
+	var tally []struct {
+		Option uint32
+		Count  uint64
+	}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(poll, "message_id = ? AND type = ?",
+			pollID.Marshal(), uint16(channels.Poll)).Error
+		if err != nil {
+			return err
+		}
+
+		// option is a reserved word in MySQL so it must be quoted
+		return tx.Model(&PollVote{}).
+			Select("?, count(*) AS count", clause.Column{Name: "option"}).
+			Where("poll_uuid = ?", poll.Id).Group("option").
+			Scan(&tally).Error
+	})
+	cancel()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return channels.PollResults{},
+				errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return channels.PollResults{}, errors.WithMessage(err, parentErr)
+	}
+
+	options := &channels.CMIXChannelPoll{}
+	if err = json.Unmarshal(poll.Text, options); err != nil {
+		return channels.PollResults{}, errors.WithMessage(err, parentErr)
+	}
+
+	results := channels.PollResults{
+		Counts: make([]uint64, len(options.Options)),
+		Closed: poll.PollClosed != nil,
+	}
+	for _, t := range tally {
+		if int(t.Option) < len(results.Counts) {
+			results.Counts[t.Option] = t.Count
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,125 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.ReceivePollVote only keeps the newest vote of each user,
+// that impl.ClosePoll stops votes from being counted, and that
+// impl.GetPollResults returns the correct tally.
+func TestImpl_ReceivePollVote(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_ReceivePollVote"
+	testChannelId := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: testChannelId,
+		Name:        testString,
+		Description: testString,
+	})
+	defer model.LeaveChannel(testChannelId)
+
+	pollJson, err := json.Marshal(&channels.CMIXChannelPoll{
+		Question: "Question?", Options: []string{"a", "b", "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pollID := message.DeriveChannelMessageID(
+		testChannelId, 10, []byte(testString))
+	timestamp := time.Now().Round(0)
+	model.ReceiveMessage(testChannelId, pollID, testString, string(pollJson),
+		[]byte(testString), 0, 0, timestamp, 0, rounds.Round{ID: 10},
+		channels.Poll, 0, false)
+
+	// Voting on a poll that does not exist must return NoMessageErr
+	_, err = model.ReceivePollVote(message.ID{1}, channels.ModelPollVote{})
+	if !channels.CheckNoMessageErr(err) {
+		t.Fatalf("Did not get expected error for unknown poll: %+v", err)
+	}
+
+	votes := []channels.ModelPollVote{
+		{MessageID: message.ID{1}, PubKey: []byte("alice"), Option: 0,
+			Timestamp: timestamp.Add(1 * time.Minute)},
+		{MessageID: message.ID{2}, PubKey: []byte("bob"), Option: 0,
+			Timestamp: timestamp.Add(2 * time.Minute)},
+		// Replaces alice's first vote
+		{MessageID: message.ID{3}, PubKey: []byte("alice"), Option: 2,
+			Timestamp: timestamp.Add(3 * time.Minute)},
+		// Older than bob's current vote and is ignored
+		{MessageID: message.ID{4}, PubKey: []byte("bob"), Option: 1,
+			Timestamp: timestamp.Add(1 * time.Minute)},
+		// Duplicate of alice's current vote
+		{MessageID: message.ID{3}, PubKey: []byte("alice"), Option: 2,
+			Timestamp: timestamp.Add(3 * time.Minute)},
+	}
+	for j, vote := range votes {
+		if _, err = model.ReceivePollVote(pollID, vote); err != nil {
+			t.Fatalf("Failed to receive vote %d: %+v", j, err)
+		}
+	}
+
+	expected := channels.PollResults{Counts: []uint64{1, 0, 1}}
+	results, err := model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Unexpected poll results.\nexpected: %+v\nreceived: %+v",
+			expected, results)
+	}
+
+	// Close the poll and check that later votes are ignored
+	_, err = model.ClosePoll(pollID, timestamp.Add(4*time.Minute), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = model.ReceivePollVote(pollID, channels.ModelPollVote{
+		MessageID: message.ID{5}, PubKey: []byte("carol"), Option: 1,
+		Timestamp: timestamp.Add(5 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Closed = true
+	results, err = model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Unexpected poll results after close."+
+			"\nexpected: %+v\nreceived: %+v", expected, results)
+	}
+
+	// Reopen the poll
+	_, err = model.ClosePoll(pollID, timestamp.Add(4*time.Minute), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err = model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatal(err)
+	} else if results.Closed {
+		t.Errorf("Poll not reopened.")
+	}
+}
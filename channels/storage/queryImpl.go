@@ -0,0 +1,133 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gorm.io/gorm"
+)
+
+// Verify that impl adheres to the channels.MessageQuerier interface.
+var _ channels.MessageQuerier = (*impl)(nil)
+
+// QueryMessages returns the messages that match the [channels.MessageQuery],
+// ordered from oldest to newest, and the total number of messages that match
+// the query's filters, ignoring the cursors and limit.
+//
+// Returns channels.NoMessageErr if the message for a cursor does not exist.
+func (i *impl) QueryMessages(query channels.MessageQuery) (
+	[]channels.ModelMessage, int64, error) {
+	parentErr := "failed to QueryMessages"
+	if query.ChannelID == nil {
+		return nil, 0, errors.Errorf("%s: channel ID is required", parentErr)
+	}
+
+	// filters applies all conditions of the query except the cursors
+	filters := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("channel_id = ?", query.ChannelID.Marshal())
+		if !query.Start.IsZero() {
+			tx = tx.Where("timestamp >= ?", query.Start)
+		}
+		if !query.End.IsZero() {
+			tx = tx.Where("timestamp < ?", query.End)
+		}
+		if query.ParentMessageID != nil {
+			tx = tx.Where(
+				"parent_message_id = ?", query.ParentMessageID.Marshal())
+		}
+		if query.PubKey != nil {
+			tx = tx.Where("pubkey = ?", []byte(query.PubKey))
+		}
+		if query.PinnedOnly {
+			tx = tx.Where("pinned = ?", true)
+		}
+		if !query.IncludeHidden {
+			tx = tx.Where("hidden = ?", false)
+		}
+		return tx
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	db := i.db.WithContext(ctx)
+
+	var total int64
+	err := db.Model(&Message{}).Scopes(filters).Count(&total).Error
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, parentErr)
+	}
+
+	page := db.Scopes(filters)
+
+	// Messages are ordered by timestamp and then by UUID so that messages with
+	// the same timestamp have a stable order across pages
+	if query.Before != nil {
+		cursor, err2 := getCursor(db, *query.Before)
+		if err2 != nil {
+			return nil, 0, errors.WithMessage(err2, parentErr)
+		}
+		page = page.Where("(timestamp < ? OR (timestamp = ? AND id < ?))",
+			cursor.Timestamp, cursor.Timestamp, cursor.Id)
+	}
+	if query.After != nil {
+		cursor, err2 := getCursor(db, *query.After)
+		if err2 != nil {
+			return nil, 0, errors.WithMessage(err2, parentErr)
+		}
+		page = page.Where("(timestamp > ? OR (timestamp = ? AND id > ?))",
+			cursor.Timestamp, cursor.Timestamp, cursor.Id)
+	}
+
+	// Get the page adjacent to the After cursor when only it is set;
+	// otherwise, get the newest messages
+	descending := query.After == nil || query.Before != nil
+	if descending {
+		page = page.Order("timestamp desc").Order("id desc")
+	} else {
+		page = page.Order("timestamp").Order("id")
+	}
+	if query.Limit > 0 {
+		page = page.Limit(query.Limit)
+	}
+
+	var results []*Message
+	if err = page.Find(&results).Error; err != nil {
+		return nil, 0, errors.WithMessage(err, parentErr)
+	}
+
+	msgs := make([]channels.ModelMessage, len(results))
+	for j, result := range results {
+		k := j
+		if descending {
+			k = len(results) - 1 - j
+		}
+		msgs[k], err = convertMessage(result)
+		if err != nil {
+			return nil, 0, errors.WithMessage(err, parentErr)
+		}
+	}
+
+	return msgs, total, nil
+}
+
+// getCursor returns the Message with the given [message.ID] to use as a cursor
+// in a query. Returns channels.NoMessageErr if the message does not exist.
+func getCursor(db *gorm.DB, messageID message.ID) (*Message, error) {
+	cursor := &Message{}
+	err := db.Select("id", "timestamp").
+		Take(cursor, "message_id = ?", messageID.Marshal()).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithMessagef(channels.NoMessageErr,
+				"cursor message %s not found", messageID)
+		}
+		return nil, err
+	}
+	return cursor, nil
+}
@@ -0,0 +1,185 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.QueryMessages returns the expected messages and total count
+// for each filter and cursor.
+func TestImpl_QueryMessages(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_QueryMessages"
+	channelID := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: channelID,
+		Name:        testString,
+		Description: testString,
+	})
+	defer model.LeaveChannel(channelID)
+
+	// Insert 10 messages one minute apart. Every third message is a reply to
+	// the first message, every fourth message is pinned, and every fifth
+	// message is sent by another user. The last message is hidden.
+	start := time.Now().Round(0).UTC()
+	msgIDs := make([]message.ID, 10)
+	for j := range msgIDs {
+		text := strconv.Itoa(j)
+		msgIDs[j] = message.DeriveChannelMessageID(
+			channelID, uint64(j), []byte(text))
+		ts := start.Add(time.Duration(j) * time.Minute)
+		hidden := j == len(msgIDs)-1
+		pubKey := []byte(testString)
+		if j%5 == 1 {
+			pubKey = []byte("other")
+		}
+		if j%3 == 2 {
+			model.ReceiveReply(channelID, msgIDs[j], msgIDs[0], testString,
+				text, pubKey, 0, 0, ts, 0,
+				rounds.Round{ID: id.Round(j)}, channels.Text, 0, hidden)
+		} else {
+			model.ReceiveMessage(channelID, msgIDs[j], testString, text,
+				pubKey, 0, 0, ts, 0, rounds.Round{ID: id.Round(j)},
+				channels.Text, 0, hidden)
+		}
+		if j%4 == 0 {
+			pinned := true
+			_, err = model.UpdateFromMessageID(
+				msgIDs[j], nil, nil, &pinned, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    channels.MessageQuery
+		expected []int
+		total    int64
+	}{{
+		name:     "All",
+		query:    channels.MessageQuery{},
+		expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8},
+		total:    9,
+	}, {
+		name:     "IncludeHidden",
+		query:    channels.MessageQuery{IncludeHidden: true},
+		expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		total:    10,
+	}, {
+		name:     "Newest",
+		query:    channels.MessageQuery{Limit: 3},
+		expected: []int{6, 7, 8},
+		total:    9,
+	}, {
+		name: "TimeRange",
+		query: channels.MessageQuery{
+			Start: start.Add(2 * time.Minute), End: start.Add(5 * time.Minute)},
+		expected: []int{2, 3, 4},
+		total:    3,
+	}, {
+		name:     "Before",
+		query:    channels.MessageQuery{Before: &msgIDs[5], Limit: 2},
+		expected: []int{3, 4},
+		total:    9,
+	}, {
+		name:     "After",
+		query:    channels.MessageQuery{After: &msgIDs[5], Limit: 2},
+		expected: []int{6, 7},
+		total:    9,
+	}, {
+		name: "BeforeAndAfter",
+		query: channels.MessageQuery{
+			Before: &msgIDs[7], After: &msgIDs[2]},
+		expected: []int{3, 4, 5, 6},
+		total:    9,
+	}, {
+		name:     "Thread",
+		query:    channels.MessageQuery{ParentMessageID: &msgIDs[0]},
+		expected: []int{2, 5, 8},
+		total:    3,
+	}, {
+		name:     "PinnedOnly",
+		query:    channels.MessageQuery{PinnedOnly: true},
+		expected: []int{0, 4, 8},
+		total:    3,
+	}, {
+		name:     "PubKey",
+		query:    channels.MessageQuery{PubKey: []byte("other")},
+		expected: []int{1, 6},
+		total:    2,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.ChannelID = channelID
+			msgs, total, err2 := model.QueryMessages(tt.query)
+			if err2 != nil {
+				t.Fatalf("Failed to query messages: %+v", err2)
+			}
+
+			if total != tt.total {
+				t.Errorf("Unexpected total.\nexpected: %d\nreceived: %d",
+					tt.total, total)
+			}
+
+			received := make([]int, len(msgs))
+			for j, msg := range msgs {
+				received[j], _ = strconv.Atoi(string(msg.Content))
+				if msg.MessageID != msgIDs[received[j]] {
+					t.Errorf("Unexpected message ID for message %d."+
+						"\nexpected: %s\nreceived: %s",
+						received[j], msgIDs[received[j]], msg.MessageID)
+				}
+			}
+			if len(received) != len(tt.expected) {
+				t.Fatalf("Unexpected messages.\nexpected: %v\nreceived: %v",
+					tt.expected, received)
+			}
+			for j := range received {
+				if received[j] != tt.expected[j] {
+					t.Fatalf("Unexpected messages.\nexpected: %v"+
+						"\nreceived: %v", tt.expected, received)
+				}
+			}
+		})
+	}
+}
+
+// Tests that impl.QueryMessages returns channels.NoMessageErr when the message
+// for a cursor does not exist.
+func TestImpl_QueryMessages_UnknownCursor(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = model.QueryMessages(channels.MessageQuery{
+		ChannelID: id.NewIdFromString("channel", id.User, t),
+		Before:    &message.ID{1},
+	})
+	if !channels.CheckNoMessageErr(err) {
+		t.Errorf("Did not get expected error for unknown cursor: %+v", err)
+	}
+}
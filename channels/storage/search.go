@@ -0,0 +1,201 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gorm.io/gorm"
+)
+
+// Searcher is implemented by the [channels.EventModel] returned by
+// NewEventModel. It allows for full-text search of stored messages. It is an
+// alias of [channels.Searcher].
+type Searcher = channels.Searcher
+
+// SearchQuery describes a full-text search of stored messages. It is an alias
+// of [channels.SearchQuery].
+type SearchQuery = channels.SearchQuery
+
+// Verify that impl adheres to the Searcher interface.
+var _ Searcher = (*impl)(nil)
+
+// searchSchema is the SQL that creates the full-text search index and the
+// triggers that keep it in sync with the messages table.
+//
+// FTS4 is used instead of FTS5 because FTS5 requires building SQLite with a
+// build tag, while FTS4 is always available.
+var searchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts
+	USING fts4(content="messages", text, tokenize=unicode61)`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_bu
+	BEFORE UPDATE OF text ON messages
+	BEGIN DELETE FROM messages_fts WHERE docid=old.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_bd
+	BEFORE DELETE ON messages
+	BEGIN DELETE FROM messages_fts WHERE docid=old.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_au
+	AFTER UPDATE OF text ON messages
+	BEGIN INSERT INTO messages_fts(docid, text) VALUES (new.id, new.text); END`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_ai
+	AFTER INSERT ON messages
+	BEGIN INSERT INTO messages_fts(docid, text) VALUES (new.id, new.text); END`,
+}
+
+// initSearch creates the full-text search index, if it does not already exist,
+// and indexes all existing messages.
+func initSearch(db *gorm.DB) error {
+	exists := db.Migrator().HasTable("messages_fts")
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range searchSchema {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+
+		// Index messages stored before the index was created
+		if !exists {
+			return tx.Exec(
+				"INSERT INTO messages_fts(messages_fts) VALUES('rebuild')").Error
+		}
+		return nil
+	})
+}
+
+// Search returns all messages whose text matches the SearchQuery, ordered from
+// newest to oldest.
+func (i *impl) Search(query SearchQuery) ([]channels.ModelMessage, error) {
+	parentErr := "failed to Search"
+
+	match := buildMatchQuery(query.Text)
+	if match == "" {
+		return nil, errors.Errorf("%s: search text is empty", parentErr)
+	}
+
+	ctx, cancel := newContext()
+	tx := i.db.WithContext(ctx)
+	if i.fullTextSearch {
+		tx = tx.Where(
+			"id IN (SELECT docid FROM messages_fts WHERE messages_fts MATCH ?)",
+			match)
+	} else {
+		for _, pattern := range buildLikePatterns(query.Text) {
+			tx = tx.Where("search_text LIKE ?", pattern)
+		}
+	}
+	if query.ChannelID != nil {
+		tx = tx.Where("channel_id = ?", query.ChannelID.Marshal())
+	}
+	if query.SenderPubKey != nil {
+		tx = tx.Where("pubkey = ?", []byte(query.SenderPubKey))
+	}
+	if len(query.Types) > 0 {
+		tx = tx.Where("type IN ?", query.Types)
+	}
+	if !query.Start.IsZero() {
+		tx = tx.Where("timestamp >= ?", query.Start)
+	}
+	if !query.End.IsZero() {
+		tx = tx.Where("timestamp < ?", query.End)
+	}
+	if !query.IncludeHidden {
+		tx = tx.Where("hidden = ?", false)
+	}
+	tx = tx.Order("timestamp desc").Order("id desc")
+
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var results []*Message
+	err := tx.Find(&results).Error
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	msgs := make([]channels.ModelMessage, len(results))
+	for j, result := range results {
+		msgs[j], err = convertMessage(result)
+		if err != nil {
+			return nil, errors.WithMessage(err, parentErr)
+		}
+	}
+
+	return msgs, nil
+}
+
+// buildMatchQuery converts search text into an FTS MATCH expression that
+// requires every word in the text. Each word is quoted so that characters in
+// the text are never interpreted as query syntax, except for a trailing "*",
+// which is kept to allow prefix searches.
+func buildMatchQuery(text string) string {
+	words := strings.Fields(text)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+
+		word = strings.ReplaceAll(word, `"`, `""`)
+		if prefix {
+			word += "*"
+		}
+		terms = append(terms, `"`+word+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// buildLikePatterns converts search text into LIKE patterns for the
+// search_text column that require every word in the text. Each pattern matches
+// the words of one search word as whole words, except for a trailing "*", which
+// allows the last word to be a prefix. The patterns match the same messages as
+// the expression built by buildMatchQuery. Words only contain letters and
+// numbers, so they never contain LIKE wildcards.
+func buildLikePatterns(text string) []string {
+	var patterns []string
+	for _, word := range strings.Fields(text) {
+		phrase := tokenize(word)
+		if len(phrase) == 0 {
+			continue
+		}
+
+		pattern := "% " + strings.Join(phrase, " ")
+		if strings.HasSuffix(word, "*") {
+			pattern += "%"
+		} else {
+			pattern += " %"
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// searchText returns the value of the search_text column for a message with
+// the text. It contains the lowercase words of the text separated and
+// surrounded by single spaces so that the patterns built by buildLikePatterns
+// only match whole words.
+func searchText(text string) string {
+	words := tokenize(text)
+	if len(words) == 0 {
+		return ""
+	}
+	return " " + strings.Join(words, " ") + " "
+}
+
+// tokenize splits the text into lowercase words the same way the unicode61
+// tokenizer of the full-text search index does.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,144 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.Search returns the expected messages for each filter and
+// that the index is kept in sync when messages are edited and deleted.
+func TestImpl_Search(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_Search"
+	channelIDs := []*id.ID{
+		id.NewIdFromString(testString+"1", id.User, t),
+		id.NewIdFromString(testString+"2", id.User, t),
+	}
+	for _, channelID := range channelIDs {
+		model.JoinChannel(&cryptoBroadcast.Channel{
+			ReceptionID: channelID,
+			Name:        testString,
+			Description: testString,
+		})
+		defer model.LeaveChannel(channelID)
+	}
+
+	alice := ed25519.PublicKey("alice")
+	bob := ed25519.PublicKey("bob")
+	start := time.Now().Round(0).UTC()
+	messages := []struct {
+		channelID *id.ID
+		sender    ed25519.PublicKey
+		text      string
+		mType     channels.MessageType
+		hidden    bool
+	}{
+		{channelIDs[0], alice, "Hello world", channels.Text, false},
+		{channelIDs[0], bob, "hello there, Alice", channels.Text, false},
+		{channelIDs[1], alice, "HELLO from the other channel", channels.Text, false},
+		{channelIDs[0], bob, "hello", channels.AdminText, false},
+		{channelIDs[0], alice, "hello, this is hidden", channels.Text, true},
+		{channelIDs[0], alice, "goodbye world", channels.Text, false},
+	}
+	msgIDs := make([]message.ID, len(messages))
+	for j, m := range messages {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			m.channelID, uint64(j), []byte(testString+m.text))
+		model.ReceiveMessage(m.channelID, msgIDs[j], testString, m.text,
+			m.sender, 0, 0, start.Add(time.Duration(j)*time.Minute), 0,
+			rounds.Round{ID: id.Round(j)}, m.mType, 0, m.hidden)
+	}
+
+	tests := []struct {
+		name     string
+		query    SearchQuery
+		expected []int
+	}{
+		{"Text", SearchQuery{Text: "hello"}, []int{3, 2, 1, 0}},
+		{"AllWords", SearchQuery{Text: "hello world"}, []int{0}},
+		{"Prefix", SearchQuery{Text: "hel*"}, []int{3, 2, 1, 0}},
+		{"Syntax", SearchQuery{Text: `"world" OR`}, nil},
+		{"Channel", SearchQuery{
+			Text: "hello", ChannelID: channelIDs[1]}, []int{2}},
+		{"Sender", SearchQuery{
+			Text: "hello", SenderPubKey: alice}, []int{2, 0}},
+		{"Type", SearchQuery{
+			Text: "hello", Types: []channels.MessageType{channels.AdminText}},
+			[]int{3}},
+		{"DateRange", SearchQuery{Text: "hello",
+			Start: start.Add(time.Minute), End: start.Add(3 * time.Minute)},
+			[]int{2, 1}},
+		{"IncludeHidden", SearchQuery{Text: "hello", IncludeHidden: true},
+			[]int{4, 3, 2, 1, 0}},
+		{"Limit", SearchQuery{Text: "hello", Limit: 2}, []int{3, 2}},
+	}
+
+	check := func(t *testing.T, query SearchQuery, expected []int) {
+		msgs, err2 := model.Search(query)
+		if err2 != nil {
+			t.Fatalf("Failed to search: %+v", err2)
+		}
+		var received []int
+		for _, msg := range msgs {
+			for j := range msgIDs {
+				if msg.MessageID == msgIDs[j] {
+					received = append(received, j)
+				}
+			}
+		}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("Unexpected search results for %q."+
+				"\nexpected: %v\nreceived: %v", query.Text, expected, received)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, tt.query, tt.expected)
+		})
+	}
+
+	// Edit a message and ensure only the new text is found
+	_, err = model.EditMessage(msgIDs[5], channels.MessageEdit{
+		EditID:    message.ID{5},
+		Text:      "hello again",
+		Timestamp: start.Add(10 * time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, SearchQuery{Text: "goodbye"}, nil)
+	check(t, SearchQuery{Text: "again"}, []int{5})
+
+	// Delete a message and ensure it is not found
+	if err = model.DeleteMessage(msgIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+	check(t, SearchQuery{Text: "world"}, nil)
+
+	// Searching without text is an error
+	if _, err = model.Search(SearchQuery{Text: "  "}); err == nil {
+		t.Error("Did not get an error when searching for empty text.")
+	}
+}
@@ -0,0 +1,70 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Verify that impl adheres to the channels.TextSpanReceiver interface.
+var _ channels.TextSpanReceiver = (*impl)(nil)
+
+// ReceiveTextSpans is called with the spans parsed from the text of the
+// message with the given UUID. The spans replace any previously saved for the
+// message and are returned in [channels.ModelMessage.Spans].
+func (i *impl) ReceiveTextSpans(uuid uint64, spans []channels.TextSpan) {
+	parentErr := errors.New("failed to ReceiveTextSpans")
+
+	var data []byte
+	if len(spans) > 0 {
+		var err error
+		data, err = json.Marshal(spans)
+		if err != nil {
+			jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+				"Unable to marshal spans: %+v", err))
+			return
+		}
+	}
+
+	msg := &Message{Id: int64(uuid)}
+	ctx, cancel := newContext()
+	result := i.db.WithContext(ctx).Model(msg).Update("spans", data)
+	cancel()
+
+	if result.Error != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to update Message %d: %+v", uuid, result.Error))
+		return
+	} else if result.RowsAffected == 0 {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Message %d does not exist", uuid))
+		return
+	}
+
+	ctx, cancel = newContext()
+	err := i.db.WithContext(ctx).Select("channel_id").Take(msg).Error
+	cancel()
+	if err != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to get channel of Message %d: %+v", uuid, err))
+		return
+	}
+
+	channelID, err := id.Unmarshal(msg.ChannelId)
+	if err != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to unmarshal channel ID of Message %d: %+v", uuid, err))
+		return
+	}
+
+	go i.cbs.MessageReceived(msg.Id, channelID, true)
+}
@@ -0,0 +1,66 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that the spans received by impl.ReceiveTextSpans are returned with the
+// message and that empty spans clear them.
+func TestImpl_ReceiveTextSpans(t *testing.T) {
+	model, err := newImpl("", &dummyCbs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testString := "TestImpl_ReceiveTextSpans"
+	testChannelId := id.NewIdFromString(testString, id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{
+		ReceptionID: testChannelId,
+		Name:        testString,
+		Description: testString,
+	})
+	defer model.LeaveChannel(testChannelId)
+
+	text := "**hi** " + channels.ChannelLinkTag(testChannelId)
+	testMsgId := message.DeriveChannelMessageID(
+		testChannelId, 10, []byte(testString))
+	uuid := model.ReceiveMessage(testChannelId, testMsgId, testString, text,
+		[]byte(testString), 0, 0, time.Now(), 0, rounds.Round{ID: 10},
+		channels.Text, 0, false)
+
+	spans := channels.ParseText(text)
+	model.ReceiveTextSpans(uuid, spans)
+
+	gotMsg, err := model.GetMessage(testMsgId)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(spans, gotMsg.Spans) {
+		t.Errorf("Unexpected spans.\nexpected: %+v\nreceived: %+v",
+			spans, gotMsg.Spans)
+	}
+
+	model.ReceiveTextSpans(uuid, nil)
+	gotMsg, err = model.GetMessage(testMsgId)
+	if err != nil {
+		t.Fatal(err)
+	} else if gotMsg.Spans != nil {
+		t.Errorf("Spans not cleared: %+v", gotMsg.Spans)
+	}
+}
@@ -207,6 +207,63 @@ func (x *CMIXChannelSilentMessage) GetVersion() uint32 {
 	return 0
 }
 
+// CMIXChannelTyping is the payload for a Typing MessageType. It signals that
+// the sender started or stopped typing in the channel. It is never stored.
+type CMIXChannelTyping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Typing  bool   `protobuf:"varint,2,opt,name=typing,proto3" json:"typing,omitempty"`
+}
+
+func (x *CMIXChannelTyping) Reset() {
+	*x = CMIXChannelTyping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelTyping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelTyping) ProtoMessage() {}
+
+func (x *CMIXChannelTyping) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelTyping.ProtoReflect.Descriptor instead.
+func (*CMIXChannelTyping) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CMIXChannelTyping) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelTyping) GetTyping() bool {
+	if x != nil {
+		return x.Typing
+	}
+	return false
+}
+
 // CMIXChannelDelete is the payload for a Delete MessageType. It deletes the
 // message with the messageID from storage.
 type CMIXChannelDelete struct {
@@ -214,27 +271,551 @@ type CMIXChannelDelete struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	MessageID []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"` // The [channel.MessageID] of the message to delete
+	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MessageID []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"` // The [channel.MessageID] of the message to delete
+}
+
+func (x *CMIXChannelDelete) Reset() {
+	*x = CMIXChannelDelete{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelDelete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelDelete) ProtoMessage() {}
+
+func (x *CMIXChannelDelete) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelDelete.ProtoReflect.Descriptor instead.
+func (*CMIXChannelDelete) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CMIXChannelDelete) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelDelete) GetMessageID() []byte {
+	if x != nil {
+		return x.MessageID
+	}
+	return nil
+}
+
+// CMIXChannelPinned is the payload for a Pinned MessageType. It pins a specific
+// message to a channel.
+type CMIXChannelPinned struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MessageID  []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"`    // The [channel.MessageID] of the message to pin
+	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the message is unpinned
+}
+
+func (x *CMIXChannelPinned) Reset() {
+	*x = CMIXChannelPinned{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelPinned) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelPinned) ProtoMessage() {}
+
+func (x *CMIXChannelPinned) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelPinned.ProtoReflect.Descriptor instead.
+func (*CMIXChannelPinned) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CMIXChannelPinned) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelPinned) GetMessageID() []byte {
+	if x != nil {
+		return x.MessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelPinned) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
+// CMIXChannelMute is the payload for a Mute MessageType. It mutes a specific
+// user so all future messages from them will be dropped when received. It also
+// prevents the user from sending messages.
+type CMIXChannelMute struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PubKey     []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`          // The [ed25519.PublicKey] of the user to mute
+	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the user is un-muted
+}
+
+func (x *CMIXChannelMute) Reset() {
+	*x = CMIXChannelMute{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelMute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelMute) ProtoMessage() {}
+
+func (x *CMIXChannelMute) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelMute.ProtoReflect.Descriptor instead.
+func (*CMIXChannelMute) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CMIXChannelMute) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelMute) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *CMIXChannelMute) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
+// CMIXChannelEdit is the payload for an Edit MessageType. It replaces the text
+// of the message with the messageID. Only the original sender or the channel
+// admin may edit a message.
+type CMIXChannelEdit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MessageID []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"` // The [channel.MessageID] of the message to edit
+	Text      string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`           // The replacement text
+}
+
+func (x *CMIXChannelEdit) Reset() {
+	*x = CMIXChannelEdit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelEdit) ProtoMessage() {}
+
+func (x *CMIXChannelEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelEdit.ProtoReflect.Descriptor instead.
+func (*CMIXChannelEdit) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CMIXChannelEdit) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelEdit) GetMessageID() []byte {
+	if x != nil {
+		return x.MessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelEdit) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// CMIXChannelInvitation is the payload for a Invitation MessageType. It invites
+// members of a channel to a separate channel.
+type CMIXChannelInvitation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Text       string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	InviteLink string `protobuf:"bytes,3,opt,name=inviteLink,proto3" json:"inviteLink,omitempty"`
+	Password   string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *CMIXChannelInvitation) Reset() {
+	*x = CMIXChannelInvitation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelInvitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelInvitation) ProtoMessage() {}
+
+func (x *CMIXChannelInvitation) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelInvitation.ProtoReflect.Descriptor instead.
+func (*CMIXChannelInvitation) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CMIXChannelInvitation) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelInvitation) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CMIXChannelInvitation) GetInviteLink() string {
+	if x != nil {
+		return x.InviteLink
+	}
+	return ""
+}
+
+func (x *CMIXChannelInvitation) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// CMIXChannelPoll is the payload for a Poll MessageType. It asks members of
+// the channel to vote on one of the options.
+type CMIXChannelPoll struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Question string   `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Options  []string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *CMIXChannelPoll) Reset() {
+	*x = CMIXChannelPoll{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelPoll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelPoll) ProtoMessage() {}
+
+func (x *CMIXChannelPoll) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelPoll.ProtoReflect.Descriptor instead.
+func (*CMIXChannelPoll) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CMIXChannelPoll) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelPoll) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *CMIXChannelPoll) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// CMIXChannelPollVote is the payload for a PollVote MessageType. Each user has
+// at most one vote per poll; a newer vote replaces an older one.
+type CMIXChannelPollVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PollMessageID []byte `protobuf:"bytes,2,opt,name=pollMessageID,proto3" json:"pollMessageID,omitempty"` // The [channel.MessageID] of the poll
+	Option        uint32 `protobuf:"varint,3,opt,name=option,proto3" json:"option,omitempty"`              // The index of the option voted for
+}
+
+func (x *CMIXChannelPollVote) Reset() {
+	*x = CMIXChannelPollVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelPollVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelPollVote) ProtoMessage() {}
+
+func (x *CMIXChannelPollVote) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelPollVote.ProtoReflect.Descriptor instead.
+func (*CMIXChannelPollVote) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CMIXChannelPollVote) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelPollVote) GetPollMessageID() []byte {
+	if x != nil {
+		return x.PollMessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelPollVote) GetOption() uint32 {
+	if x != nil {
+		return x.Option
+	}
+	return 0
+}
+
+// CMIXChannelPollClose is the payload for a PollClose MessageType. It closes
+// the poll with the pollMessageID. Only the channel admin may close a poll.
+type CMIXChannelPollClose struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PollMessageID []byte `protobuf:"bytes,2,opt,name=pollMessageID,proto3" json:"pollMessageID,omitempty"` // The [channel.MessageID] of the poll
+	UndoAction    bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"`      // If true, the poll is reopened
+}
+
+func (x *CMIXChannelPollClose) Reset() {
+	*x = CMIXChannelPollClose{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelPollClose) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelPollClose) ProtoMessage() {}
+
+func (x *CMIXChannelPollClose) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelPollClose.ProtoReflect.Descriptor instead.
+func (*CMIXChannelPollClose) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CMIXChannelPollClose) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelPollClose) GetPollMessageID() []byte {
+	if x != nil {
+		return x.PollMessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelPollClose) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
+// CMIXChannelModerator is the payload for a Moderator MessageType. It appoints
+// the user as a moderator of the channel, which allows them to delete, pin, and
+// mute. Only the channel admin may appoint a moderator.
+type CMIXChannelModerator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PubKey     []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`          // The [ed25519.PublicKey] of the moderator
+	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the moderator is revoked
 }
 
-func (x *CMIXChannelDelete) Reset() {
-	*x = CMIXChannelDelete{}
+func (x *CMIXChannelModerator) Reset() {
+	*x = CMIXChannelModerator{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_text_proto_msgTypes[3]
+		mi := &file_text_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CMIXChannelDelete) String() string {
+func (x *CMIXChannelModerator) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CMIXChannelDelete) ProtoMessage() {}
+func (*CMIXChannelModerator) ProtoMessage() {}
 
-func (x *CMIXChannelDelete) ProtoReflect() protoreflect.Message {
-	mi := &file_text_proto_msgTypes[3]
+func (x *CMIXChannelModerator) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,54 +826,63 @@ func (x *CMIXChannelDelete) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CMIXChannelDelete.ProtoReflect.Descriptor instead.
-func (*CMIXChannelDelete) Descriptor() ([]byte, []int) {
-	return file_text_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use CMIXChannelModerator.ProtoReflect.Descriptor instead.
+func (*CMIXChannelModerator) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CMIXChannelDelete) GetVersion() uint32 {
+func (x *CMIXChannelModerator) GetVersion() uint32 {
 	if x != nil {
 		return x.Version
 	}
 	return 0
 }
 
-func (x *CMIXChannelDelete) GetMessageID() []byte {
+func (x *CMIXChannelModerator) GetPubKey() []byte {
 	if x != nil {
-		return x.MessageID
+		return x.PubKey
 	}
 	return nil
 }
 
-// CMIXChannelPinned is the payload for a Pinned MessageType. It pins a specific
-// message to a channel.
-type CMIXChannelPinned struct {
+func (x *CMIXChannelModerator) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
+// CMIXChannelKeyRotation is the payload for a KeyRotation MessageType. It moves
+// the channel to a new RSA admin key. Because the channel ID is derived from
+// the hash of the admin public key, the channel is replaced by a new channel
+// that only differs in its admin key. Only the channel admin may rotate the
+// key.
+type CMIXChannelKeyRotation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	MessageID  []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"`    // The [channel.MessageID] of the message to pin
-	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the message is unpinned
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	RsaPubKeyHash []byte `protobuf:"bytes,2,opt,name=rsaPubKeyHash,proto3" json:"rsaPubKeyHash,omitempty"` // The hash of the new RSA public key
 }
 
-func (x *CMIXChannelPinned) Reset() {
-	*x = CMIXChannelPinned{}
+func (x *CMIXChannelKeyRotation) Reset() {
+	*x = CMIXChannelKeyRotation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_text_proto_msgTypes[4]
+		mi := &file_text_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CMIXChannelPinned) String() string {
+func (x *CMIXChannelKeyRotation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CMIXChannelPinned) ProtoMessage() {}
+func (*CMIXChannelKeyRotation) ProtoMessage() {}
 
-func (x *CMIXChannelPinned) ProtoReflect() protoreflect.Message {
-	mi := &file_text_proto_msgTypes[4]
+func (x *CMIXChannelKeyRotation) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -303,62 +893,114 @@ func (x *CMIXChannelPinned) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CMIXChannelPinned.ProtoReflect.Descriptor instead.
-func (*CMIXChannelPinned) Descriptor() ([]byte, []int) {
-	return file_text_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use CMIXChannelKeyRotation.ProtoReflect.Descriptor instead.
+func (*CMIXChannelKeyRotation) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CMIXChannelPinned) GetVersion() uint32 {
+func (x *CMIXChannelKeyRotation) GetVersion() uint32 {
 	if x != nil {
 		return x.Version
 	}
 	return 0
 }
 
-func (x *CMIXChannelPinned) GetMessageID() []byte {
+func (x *CMIXChannelKeyRotation) GetRsaPubKeyHash() []byte {
 	if x != nil {
-		return x.MessageID
+		return x.RsaPubKeyHash
 	}
 	return nil
 }
 
-func (x *CMIXChannelPinned) GetUndoAction() bool {
+// CMIXChannelSlowMode is the payload for a SlowMode MessageType. It sets the
+// minimum time between two messages sent by the same user in the channel.
+// Only the channel admin may set slow mode.
+type CMIXChannelSlowMode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Interval int64  `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"` // Interval in nanoseconds; slow mode is off if 0
+}
+
+func (x *CMIXChannelSlowMode) Reset() {
+	*x = CMIXChannelSlowMode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelSlowMode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelSlowMode) ProtoMessage() {}
+
+func (x *CMIXChannelSlowMode) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelSlowMode.ProtoReflect.Descriptor instead.
+func (*CMIXChannelSlowMode) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CMIXChannelSlowMode) GetVersion() uint32 {
 	if x != nil {
-		return x.UndoAction
+		return x.Version
 	}
-	return false
+	return 0
 }
 
-// CMIXChannelMute is the payload for a Mute MessageType. It mutes a specific
-// user so all future messages from them will be dropped when received. It also
-// prevents the user from sending messages.
-type CMIXChannelMute struct {
+func (x *CMIXChannelSlowMode) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+// CMIXChannelBan is the payload for a Ban MessageType. It mutes the user and
+// hides every message they have sent to the channel. Only the channel admin may
+// ban a user.
+type CMIXChannelBan struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	PubKey     []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`          // The [ed25519.PublicKey] of the user to mute
-	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the user is un-muted
+	PubKey     []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`          // The [ed25519.PublicKey] of the banned user
+	Reason     string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`          // The reason the user was banned
+	UndoAction bool   `protobuf:"varint,4,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, the user is unbanned
 }
 
-func (x *CMIXChannelMute) Reset() {
-	*x = CMIXChannelMute{}
+func (x *CMIXChannelBan) Reset() {
+	*x = CMIXChannelBan{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_text_proto_msgTypes[5]
+		mi := &file_text_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CMIXChannelMute) String() string {
+func (x *CMIXChannelBan) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CMIXChannelMute) ProtoMessage() {}
+func (*CMIXChannelBan) ProtoMessage() {}
 
-func (x *CMIXChannelMute) ProtoReflect() protoreflect.Message {
-	mi := &file_text_proto_msgTypes[5]
+func (x *CMIXChannelBan) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -369,62 +1011,72 @@ func (x *CMIXChannelMute) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CMIXChannelMute.ProtoReflect.Descriptor instead.
-func (*CMIXChannelMute) Descriptor() ([]byte, []int) {
-	return file_text_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use CMIXChannelBan.ProtoReflect.Descriptor instead.
+func (*CMIXChannelBan) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *CMIXChannelMute) GetVersion() uint32 {
+func (x *CMIXChannelBan) GetVersion() uint32 {
 	if x != nil {
 		return x.Version
 	}
 	return 0
 }
 
-func (x *CMIXChannelMute) GetPubKey() []byte {
+func (x *CMIXChannelBan) GetPubKey() []byte {
 	if x != nil {
 		return x.PubKey
 	}
 	return nil
 }
 
-func (x *CMIXChannelMute) GetUndoAction() bool {
+func (x *CMIXChannelBan) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CMIXChannelBan) GetUndoAction() bool {
 	if x != nil {
 		return x.UndoAction
 	}
 	return false
 }
 
-// CMIXChannelInvitation is the payload for a Invitation MessageType. It invites
-// members of a channel to a separate channel.
-type CMIXChannelInvitation struct {
+// CMIXChannelMetadata is the payload for a MetadataUpdate MessageType. It
+// replaces the editable metadata of the channel. The name cannot be changed
+// because the channel ID is derived from it. Only the channel admin may update
+// the metadata.
+type CMIXChannelMetadata struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	Text       string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	InviteLink string `protobuf:"bytes,3,opt,name=inviteLink,proto3" json:"inviteLink,omitempty"`
-	Password   string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Version     uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // The description shown in place of the original
+	Icon        []byte `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`               // A small image used as the channel icon
+	Topic       string `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`             // The current topic of the channel
+	Rules       string `protobuf:"bytes,5,opt,name=rules,proto3" json:"rules,omitempty"`             // The rules of the channel
 }
 
-func (x *CMIXChannelInvitation) Reset() {
-	*x = CMIXChannelInvitation{}
+func (x *CMIXChannelMetadata) Reset() {
+	*x = CMIXChannelMetadata{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_text_proto_msgTypes[6]
+		mi := &file_text_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CMIXChannelInvitation) String() string {
+func (x *CMIXChannelMetadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CMIXChannelInvitation) ProtoMessage() {}
+func (*CMIXChannelMetadata) ProtoMessage() {}
 
-func (x *CMIXChannelInvitation) ProtoReflect() protoreflect.Message {
-	mi := &file_text_proto_msgTypes[6]
+func (x *CMIXChannelMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,35 +1087,42 @@ func (x *CMIXChannelInvitation) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CMIXChannelInvitation.ProtoReflect.Descriptor instead.
-func (*CMIXChannelInvitation) Descriptor() ([]byte, []int) {
-	return file_text_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use CMIXChannelMetadata.ProtoReflect.Descriptor instead.
+func (*CMIXChannelMetadata) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CMIXChannelInvitation) GetVersion() uint32 {
+func (x *CMIXChannelMetadata) GetVersion() uint32 {
 	if x != nil {
 		return x.Version
 	}
 	return 0
 }
 
-func (x *CMIXChannelInvitation) GetText() string {
+func (x *CMIXChannelMetadata) GetDescription() string {
 	if x != nil {
-		return x.Text
+		return x.Description
 	}
 	return ""
 }
 
-func (x *CMIXChannelInvitation) GetInviteLink() string {
+func (x *CMIXChannelMetadata) GetIcon() []byte {
 	if x != nil {
-		return x.InviteLink
+		return x.Icon
+	}
+	return nil
+}
+
+func (x *CMIXChannelMetadata) GetTopic() string {
+	if x != nil {
+		return x.Topic
 	}
 	return ""
 }
 
-func (x *CMIXChannelInvitation) GetPassword() string {
+func (x *CMIXChannelMetadata) GetRules() string {
 	if x != nil {
-		return x.Password
+		return x.Rules
 	}
 	return ""
 }
@@ -490,36 +1149,101 @@ var file_text_proto_rawDesc = []byte{
 	0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x69, 0x6c, 0x65, 0x6e, 0x74, 0x4d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
 	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x22, 0x4b, 0x0a, 0x11, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x45, 0x0a, 0x11, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54,
+	0x79, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x22, 0x6b, 0x0a,
-	0x11, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x69, 0x6e, 0x6e,
-	0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e,
-	0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
-	0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x63, 0x0a, 0x0f, 0x43, 0x4d,
-	0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x75, 0x74, 0x65, 0x12, 0x18, 0x0a,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65,
-	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12,
-	0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
-	0x81, 0x01, 0x0a, 0x15, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49,
-	0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x16, 0x0a, 0x06, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x22, 0x4b, 0x0a, 0x11, 0x43, 0x4d, 0x49, 0x58, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x49, 0x44, 0x22, 0x6b, 0x0a, 0x11, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
 	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74,
-	0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x76,
-	0x69, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49,
+	0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x63, 0x0a, 0x0f, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x4d, 0x75, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x5d, 0x0a, 0x0f, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x64, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49,
+	0x44, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x81, 0x01, 0x0a, 0x15, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x61, 0x0a, 0x0f, 0x43, 0x4d, 0x49,
+	0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x6c, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x6d, 0x0a, 0x13,
+	0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x6c, 0x56,
+	0x6f, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x76, 0x0a, 0x14, 0x43,
+	0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x6c, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x68, 0x0a, 0x14, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a,
+	0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x58, 0x0a,
+	0x16, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x52,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x73, 0x61, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x48, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x72, 0x73, 0x61, 0x50, 0x75, 0x62,
+	0x4b, 0x65, 0x79, 0x48, 0x61, 0x73, 0x68, 0x22, 0x4b, 0x0a, 0x13, 0x43, 0x4d, 0x49, 0x58, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x6c, 0x6f, 0x77, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x22, 0x7a, 0x0a, 0x0e, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x42, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x91, 0x01, 0x0a, 0x13, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x14,
+	0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -534,15 +1258,25 @@ func file_text_proto_rawDescGZIP() []byte {
 	return file_text_proto_rawDescData
 }
 
-var file_text_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_text_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_text_proto_goTypes = []interface{}{
 	(*CMIXChannelText)(nil),          // 0: channels.CMIXChannelText
 	(*CMIXChannelReaction)(nil),      // 1: channels.CMIXChannelReaction
 	(*CMIXChannelSilentMessage)(nil), // 2: channels.CMIXChannelSilentMessage
-	(*CMIXChannelDelete)(nil),        // 3: channels.CMIXChannelDelete
-	(*CMIXChannelPinned)(nil),        // 4: channels.CMIXChannelPinned
-	(*CMIXChannelMute)(nil),          // 5: channels.CMIXChannelMute
-	(*CMIXChannelInvitation)(nil),    // 6: channels.CMIXChannelInvitation
+	(*CMIXChannelTyping)(nil),        // 3: channels.CMIXChannelTyping
+	(*CMIXChannelDelete)(nil),        // 4: channels.CMIXChannelDelete
+	(*CMIXChannelPinned)(nil),        // 5: channels.CMIXChannelPinned
+	(*CMIXChannelMute)(nil),          // 6: channels.CMIXChannelMute
+	(*CMIXChannelEdit)(nil),          // 7: channels.CMIXChannelEdit
+	(*CMIXChannelInvitation)(nil),    // 8: channels.CMIXChannelInvitation
+	(*CMIXChannelPoll)(nil),          // 9: channels.CMIXChannelPoll
+	(*CMIXChannelPollVote)(nil),      // 10: channels.CMIXChannelPollVote
+	(*CMIXChannelPollClose)(nil),     // 11: channels.CMIXChannelPollClose
+	(*CMIXChannelModerator)(nil),     // 12: channels.CMIXChannelModerator
+	(*CMIXChannelKeyRotation)(nil),   // 13: channels.CMIXChannelKeyRotation
+	(*CMIXChannelSlowMode)(nil),      // 14: channels.CMIXChannelSlowMode
+	(*CMIXChannelBan)(nil),           // 15: channels.CMIXChannelBan
+	(*CMIXChannelMetadata)(nil),      // 16: channels.CMIXChannelMetadata
 }
 var file_text_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -595,7 +1329,7 @@ func file_text_proto_init() {
 			}
 		}
 		file_text_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CMIXChannelDelete); i {
+			switch v := v.(*CMIXChannelTyping); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -607,7 +1341,7 @@ func file_text_proto_init() {
 			}
 		}
 		file_text_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CMIXChannelPinned); i {
+			switch v := v.(*CMIXChannelDelete); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -619,7 +1353,7 @@ func file_text_proto_init() {
 			}
 		}
 		file_text_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CMIXChannelMute); i {
+			switch v := v.(*CMIXChannelPinned); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -631,6 +1365,30 @@ func file_text_proto_init() {
 			}
 		}
 		file_text_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelMute); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelEdit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CMIXChannelInvitation); i {
 			case 0:
 				return &v.state
@@ -642,6 +1400,102 @@ func file_text_proto_init() {
 				return nil
 			}
 		}
+		file_text_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelPoll); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelPollVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelPollClose); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelModerator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelKeyRotation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelSlowMode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelBan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_text_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -649,7 +1503,7 @@ func file_text_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_text_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
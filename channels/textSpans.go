@@ -0,0 +1,406 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// SpanType describes the kind of structured content found in the text of a
+// message.
+type SpanType uint8
+
+const (
+	// Mention is a reference to a user written as "<@pubKey>", where pubKey
+	// is the base 64 encoded Ed25519 public key of the user. Use MentionTag to
+	// create one. Mentioned users are pinged automatically.
+	Mention SpanType = 1
+
+	// ChannelLink is a reference to a channel written as "<#channelID>",
+	// where channelID is the base 64 encoded channel ID. Use ChannelLinkTag to
+	// create one.
+	ChannelLink SpanType = 2
+
+	// URL is an HTTP or HTTPS link.
+	URL SpanType = 3
+
+	// Bold is text surrounded by "**".
+	Bold SpanType = 10
+
+	// Italic is text surrounded by "*" or "_".
+	Italic SpanType = 11
+
+	// Strikethrough is text surrounded by "~~".
+	Strikethrough SpanType = 12
+
+	// Code is inline code surrounded by "`". Its contents are not parsed.
+	Code SpanType = 13
+
+	// CodeBlock is a block of code surrounded by "```". Its contents are not
+	// parsed.
+	CodeBlock SpanType = 14
+)
+
+// String returns a human-readable name for the SpanType for logging and
+// debugging. This function adheres to the fmt.Stringer interface.
+func (st SpanType) String() string {
+	switch st {
+	case Mention:
+		return "Mention"
+	case ChannelLink:
+		return "ChannelLink"
+	case URL:
+		return "URL"
+	case Bold:
+		return "Bold"
+	case Italic:
+		return "Italic"
+	case Strikethrough:
+		return "Strikethrough"
+	case Code:
+		return "Code"
+	case CodeBlock:
+		return "CodeBlock"
+	default:
+		return "Invalid SpanType: " + strconv.Itoa(int(st))
+	}
+}
+
+// TextSpan is a piece of structured content found in the text of a message by
+// ParseText. Spans of formatting may contain other spans.
+type TextSpan struct {
+	// Type is the kind of content.
+	Type SpanType `json:"type"`
+
+	// Start and End are the byte offsets of the span in the text, including
+	// any delimiters (e.g., the "**" around bold text). The span covers
+	// text[Start:End].
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Value is the content of the span without its delimiters. For a URL, it
+	// is the link.
+	Value string `json:"value"`
+
+	// PubKey is the public key of the mentioned user. It is only set for a
+	// Mention.
+	PubKey ed25519.PublicKey `json:"pubKey,omitempty"`
+
+	// ChannelID is the ID of the linked channel. It is only set for a
+	// ChannelLink.
+	ChannelID *id.ID `json:"channelID,omitempty"`
+}
+
+// Delimiters and tags recognised by ParseText.
+const (
+	mentionTagStart     = "<@"
+	channelLinkTagStart = "<#"
+	tagEnd              = ">"
+	boldDelim           = "**"
+	strikeDelim         = "~~"
+	codeDelim           = "`"
+	codeBlockDelim      = "```"
+
+	// maxSpanDepth is the maximum number of formatting spans that may be
+	// nested. Deeper formatting is left as plain text.
+	maxSpanDepth = 8
+)
+
+// urlSchemes are the URL schemes that are recognised as links. Other schemes
+// are left as plain text so that the UI never renders an unsafe link.
+var urlSchemes = []string{"https://", "http://"}
+
+// MentionTag returns the tag used in the text of a message to mention the user
+// with the public key.
+func MentionTag(pubKey ed25519.PublicKey) string {
+	return mentionTagStart + base64.StdEncoding.EncodeToString(pubKey) + tagEnd
+}
+
+// ChannelLinkTag returns the tag used in the text of a message to link to the
+// channel.
+func ChannelLinkTag(channelID *id.ID) string {
+	return channelLinkTagStart +
+		base64.StdEncoding.EncodeToString(channelID.Marshal()) + tagEnd
+}
+
+// ParseText returns the mentions, channel links, URLs, and formatting found in
+// the text of a message, ordered by their start offset. When spans start at
+// the same offset, the outer span is first.
+//
+// Only a safe subset of markdown is supported: bold, italic, strikethrough,
+// inline code, and code blocks. A backslash escapes the character after it.
+// Unclosed delimiters are left as plain text.
+func ParseText(text string) []TextSpan {
+	var spans []TextSpan
+	parseSpans(text, 0, len(text), 0, &spans)
+	return spans
+}
+
+// parseSpans parses text[start:end] and appends each span found to spans.
+// Formatting spans are parsed recursively up to maxSpanDepth.
+func parseSpans(text string, start, end, depth int, spans *[]TextSpan) {
+	for i := start; i < end; {
+		s := text[i:end]
+
+		switch {
+		case s[0] == '\\' && len(s) > 1:
+			// Skip the escaped character
+			_, size := utf8.DecodeRuneInString(s[1:])
+			i += 1 + size
+			continue
+
+		case strings.HasPrefix(s, codeBlockDelim):
+			if n, ok := parseDelimited(text, i, end, codeBlockDelim,
+				CodeBlock, false, depth, spans); ok {
+				i = n
+				continue
+			}
+			i += len(codeBlockDelim)
+			continue
+
+		case strings.HasPrefix(s, codeDelim):
+			if n, ok := parseDelimited(
+				text, i, end, codeDelim, Code, false, depth, spans); ok {
+				i = n
+				continue
+			}
+
+		case strings.HasPrefix(s, boldDelim):
+			if n, ok := parseDelimited(
+				text, i, end, boldDelim, Bold, true, depth, spans); ok {
+				i = n
+				continue
+			}
+			i += len(boldDelim)
+			continue
+
+		case strings.HasPrefix(s, strikeDelim):
+			if n, ok := parseDelimited(text, i, end, strikeDelim,
+				Strikethrough, true, depth, spans); ok {
+				i = n
+				continue
+			}
+			i += len(strikeDelim)
+			continue
+
+		case s[0] == '*' || (s[0] == '_' && !isWordBefore(text, i)):
+			if n, ok := parseDelimited(
+				text, i, end, s[:1], Italic, true, depth, spans); ok {
+				i = n
+				continue
+			}
+
+		case strings.HasPrefix(s, mentionTagStart):
+			if span, ok := parseMention(s); ok {
+				span.Start, span.End = i, i+span.End
+				*spans = append(*spans, span)
+				i = span.End
+				continue
+			}
+
+		case strings.HasPrefix(s, channelLinkTagStart):
+			if span, ok := parseChannelLink(s); ok {
+				span.Start, span.End = i, i+span.End
+				*spans = append(*spans, span)
+				i = span.End
+				continue
+			}
+
+		case !isWordBefore(text, i):
+			if span, ok := parseURL(s); ok {
+				span.Start, span.End = i, i+span.End
+				*spans = append(*spans, span)
+				i = span.End
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(s)
+		i += size
+	}
+}
+
+// parseDelimited parses the span starting at text[start] that is surrounded by
+// the delimiter. If nested is true, the contents are parsed for more spans.
+// Returns the offset after the closing delimiter and true if the span is
+// valid. The contents may not be empty and formatting may not start or end
+// with whitespace.
+func parseDelimited(text string, start, end int, delim string, st SpanType,
+	nested bool, depth int, spans *[]TextSpan) (int, bool) {
+	if depth >= maxSpanDepth {
+		return 0, false
+	}
+
+	contentStart := start + len(delim)
+	closing := strings.Index(text[contentStart:end], delim)
+	if closing < 1 {
+		return 0, false
+	}
+	contentEnd := contentStart + closing
+	content := text[contentStart:contentEnd]
+
+	if nested {
+		first, _ := utf8.DecodeRuneInString(content)
+		last, _ := utf8.DecodeLastRuneInString(content)
+		if unicode.IsSpace(first) || unicode.IsSpace(last) {
+			return 0, false
+		}
+	}
+
+	spanEnd := contentEnd + len(delim)
+	*spans = append(*spans, TextSpan{
+		Type:  st,
+		Start: start,
+		End:   spanEnd,
+		Value: content,
+	})
+
+	if nested {
+		parseSpans(text, contentStart, contentEnd, depth+1, spans)
+	}
+
+	return spanEnd, true
+}
+
+// parseMention parses a mention tag at the start of s. The returned span's End
+// is the length of the tag.
+func parseMention(s string) (TextSpan, bool) {
+	data, n, ok := parseTag(s, mentionTagStart)
+	if !ok || len(data) != ed25519.PublicKeySize {
+		return TextSpan{}, false
+	}
+
+	return TextSpan{
+		Type:   Mention,
+		End:    n,
+		Value:  s[len(mentionTagStart) : n-len(tagEnd)],
+		PubKey: data,
+	}, true
+}
+
+// parseChannelLink parses a channel link tag at the start of s. The returned
+// span's End is the length of the tag.
+func parseChannelLink(s string) (TextSpan, bool) {
+	data, n, ok := parseTag(s, channelLinkTagStart)
+	if !ok {
+		return TextSpan{}, false
+	}
+
+	channelID, err := id.Unmarshal(data)
+	if err != nil {
+		return TextSpan{}, false
+	}
+
+	return TextSpan{
+		Type:      ChannelLink,
+		End:       n,
+		Value:     s[len(channelLinkTagStart) : n-len(tagEnd)],
+		ChannelID: channelID,
+	}, true
+}
+
+// parseTag decodes the base 64 data in a tag that starts with the prefix at
+// the start of s. Returns the data and the length of the tag.
+func parseTag(s, prefix string) ([]byte, int, bool) {
+	closing := strings.Index(s, tagEnd)
+	if closing < len(prefix)+1 {
+		return nil, 0, false
+	}
+
+	data, err := base64.StdEncoding.DecodeString(s[len(prefix):closing])
+	if err != nil {
+		return nil, 0, false
+	}
+
+	return data, closing + len(tagEnd), true
+}
+
+// parseURL parses an HTTP or HTTPS link at the start of s. The link ends at
+// whitespace or a "<" and trailing punctuation is excluded. The returned
+// span's End is the length of the link.
+func parseURL(s string) (TextSpan, bool) {
+	var scheme string
+	for _, prefix := range urlSchemes {
+		if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+			scheme = prefix
+			break
+		}
+	}
+	if scheme == "" {
+		return TextSpan{}, false
+	}
+
+	n := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '<'
+	})
+	if n < 0 {
+		n = len(s)
+	}
+	n = len(strings.TrimRight(s[:n], ".,:;!?'\")]*_~`"))
+
+	link := s[:n]
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" || len(link) <= len(scheme) {
+		return TextSpan{}, false
+	}
+
+	return TextSpan{Type: URL, End: n, Value: link}, true
+}
+
+// isWordBefore returns true if the character before text[i] is a letter or
+// number. It is used to ignore underscores and URLs in the middle of words.
+func isWordBefore(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// getMentions returns the public keys of every user mentioned in the text,
+// excluding duplicates, in the order they first appear.
+func getMentions(text string) []ed25519.PublicKey {
+	var mentions []ed25519.PublicKey
+	for _, span := range ParseText(text) {
+		if span.Type == Mention && !containsPubKey(mentions, span.PubKey) {
+			mentions = append(mentions, span.PubKey)
+		}
+	}
+	return mentions
+}
+
+// addMentionPings adds every user mentioned in the text to the pings, except
+// for the sender and users already in the list.
+func addMentionPings(text string, pings []ed25519.PublicKey,
+	sender ed25519.PublicKey) []ed25519.PublicKey {
+	for _, pubKey := range getMentions(text) {
+		if !pubKey.Equal(sender) && !containsPubKey(pings, pubKey) {
+			pings = append(pings, pubKey)
+		}
+	}
+	return pings
+}
+
+// containsPubKey returns true if the list contains the public key.
+func containsPubKey(list []ed25519.PublicKey, pubKey ed25519.PublicKey) bool {
+	for _, k := range list {
+		if bytes.Equal(k, pubKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that ParseText returns the expected spans for formatting and links.
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		text  string
+		spans []TextSpan
+	}{
+		{"plain text", nil},
+		{"**bold**", []TextSpan{{Type: Bold, Start: 0, End: 8, Value: "bold"}}},
+		{"a *b* _c_", []TextSpan{
+			{Type: Italic, Start: 2, End: 5, Value: "b"},
+			{Type: Italic, Start: 6, End: 9, Value: "c"}}},
+		{"~~gone~~", []TextSpan{
+			{Type: Strikethrough, Start: 0, End: 8, Value: "gone"}}},
+		{"**a _b_**", []TextSpan{
+			{Type: Bold, Start: 0, End: 9, Value: "a _b_"},
+			{Type: Italic, Start: 4, End: 7, Value: "b"}}},
+		{"`**not bold**`", []TextSpan{
+			{Type: Code, Start: 0, End: 14, Value: "**not bold**"}}},
+		{"```\ncode *x*\n```", []TextSpan{
+			{Type: CodeBlock, Start: 0, End: 16, Value: "\ncode *x*\n"}}},
+		{"see https://xx.network/path?q=1.", []TextSpan{
+			{Type: URL, Start: 4, End: 31, Value: "https://xx.network/path?q=1"}}},
+		{"(http://a.io)", []TextSpan{
+			{Type: URL, Start: 1, End: 12, Value: "http://a.io"}}},
+		{"**https://a.io**", []TextSpan{
+			{Type: Bold, Start: 0, End: 16, Value: "https://a.io"},
+			{Type: URL, Start: 2, End: 14, Value: "https://a.io"}}},
+		{"snake_case_name", nil},
+		{"javascript:alert(1) ftp://a.io", nil},
+		{"https://", nil},
+		{"** not bold **", nil},
+		{"**unclosed", nil},
+		{`\*not italic\*`, nil},
+		{"**", nil},
+		{"<@not a key>", nil},
+		{"ünïcödé *ök*", []TextSpan{
+			{Type: Italic, Start: 12, End: 17, Value: "ök"}}},
+	}
+
+	for i, tt := range tests {
+		spans := ParseText(tt.text)
+		if !reflect.DeepEqual(tt.spans, spans) {
+			t.Errorf("Unexpected spans for %q (%d).\nexpected: %+v"+
+				"\nreceived: %+v", tt.text, i, tt.spans, spans)
+		}
+		for _, span := range spans {
+			if span.Start < 0 || span.End > len(tt.text) ||
+				span.Start >= span.End {
+				t.Errorf("Span %+v out of range of %q (%d).", span, tt.text, i)
+			}
+		}
+	}
+}
+
+// Tests that ParseText finds mentions and channel links created by MentionTag
+// and ChannelLinkTag.
+func TestParseText_MentionsAndChannelLinks(t *testing.T) {
+	prng := rand.New(rand.NewSource(6523))
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	channelID, _ := id.NewRandomID(prng, id.User)
+
+	mention, link := MentionTag(pubKey), ChannelLinkTag(channelID)
+	text := "hi " + mention + ", see *" + link + "*"
+	spans := ParseText(text)
+
+	expected := []TextSpan{{
+		Type:   Mention,
+		Start:  3,
+		End:    3 + len(mention),
+		Value:  mention[2 : len(mention)-1],
+		PubKey: pubKey,
+	}, {
+		Type:  Italic,
+		Start: strings.Index(text, "*"),
+		End:   len(text),
+		Value: link,
+	}, {
+		Type:      ChannelLink,
+		Start:     strings.Index(text, link),
+		End:       len(text) - 1,
+		Value:     link[2 : len(link)-1],
+		ChannelID: channelID,
+	}}
+	if !reflect.DeepEqual(expected, spans) {
+		t.Errorf("Unexpected spans for %q.\nexpected: %+v\nreceived: %+v",
+			text, expected, spans)
+	}
+}
+
+// Tests that parseSpans does not parse formatting nested deeper than
+// maxSpanDepth but still parses links.
+func Test_parseSpans_MaxDepth(t *testing.T) {
+	text := "**bold** https://a.io"
+	var spans []TextSpan
+	parseSpans(text, 0, len(text), maxSpanDepth, &spans)
+
+	expected := []TextSpan{{Type: URL, Start: 9, End: 21, Value: "https://a.io"}}
+	if !reflect.DeepEqual(expected, spans) {
+		t.Errorf("Unexpected spans.\nexpected: %+v\nreceived: %+v",
+			expected, spans)
+	}
+}
+
+// Tests that addMentionPings adds each user mentioned in the text once and
+// skips the sender and users that are already pinged.
+func Test_addMentionPings(t *testing.T) {
+	prng := rand.New(rand.NewSource(6523))
+	sender, _, _ := ed25519.GenerateKey(prng)
+	a, _, _ := ed25519.GenerateKey(prng)
+	b, _, _ := ed25519.GenerateKey(prng)
+	c, _, _ := ed25519.GenerateKey(prng)
+
+	text := MentionTag(a) + MentionTag(sender) + " `" + MentionTag(c) + "` " +
+		MentionTag(b) + MentionTag(a)
+	pings := addMentionPings(text, []ed25519.PublicKey{b}, sender)
+
+	expected := []ed25519.PublicKey{b, a}
+	if !reflect.DeepEqual(expected, pings) {
+		t.Errorf("Unexpected pings.\nexpected: %x\nreceived: %x",
+			expected, pings)
+	}
+}
@@ -0,0 +1,26 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"gitlab.com/elixxir/client/v4/typingIndicator"
+)
+
+const (
+	// TypingInterval is the minimum amount of time between two typing
+	// indicators sent to the same channel while the user is typing. The UI
+	// should resend the indicator at this interval for as long as the user
+	// keeps typing.
+	TypingInterval = typingIndicator.Interval
+
+	// TypingTimeout is how long a typing indicator is valid for. Indicators
+	// older than this are dropped on reception, and the UI should stop showing
+	// a user as typing if it has not received a new indicator from them within
+	// this time.
+	TypingTimeout = typingIndicator.Timeout
+)
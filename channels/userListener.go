@@ -26,6 +26,7 @@ type userListener struct {
 	chID      *id.ID
 	trigger   triggerEventFunc
 	checkSent messageReceiveFunc
+	slowMode  slowModeCheckFunc
 }
 
 // Listen is called when a message is received for the user listener.
@@ -71,6 +72,17 @@ func (ul *userListener) Listen(payload, encryptedPayload []byte, tags []string,
 	ts := message.VetTimestamp(
 		time.Unix(0, cm.LocalTimestamp), round.Timestamps[states.QUEUED], msgID)
 
+	// Drop the message if the user sent another within the slow mode interval.
+	// The round timestamp is used because the sender controls the timestamp on
+	// the message and could choose one that evades the interval.
+	if !ul.slowMode(
+		ul.chID, um.ECCPublicKey, mt, round.Timestamps[states.QUEUED]) {
+		jww.WARN.Printf("[CH] Message %s on channel %s from %x dropped; "+
+			"sent within the slow mode interval", msgID, ul.chID,
+			um.ECCPublicKey)
+		return
+	}
+
 	// Submit the message to the event model for listening
 	uuid, err := ul.trigger(
 		ul.chID, umi, encryptedPayload, ts, receptionID,
@@ -47,6 +47,11 @@ func (ted *triggerEventDummy) triggerEvent(chID *id.ID,
 	return 0, nil
 }
 
+// allowSlowMode adheres to the slowModeCheckFunc type and allows every message.
+func allowSlowMode(*id.ID, ed25519.PublicKey, MessageType, time.Time) bool {
+	return true
+}
+
 // Tests the happy path.
 func Test_userListener_Listen(t *testing.T) {
 
@@ -100,6 +105,7 @@ func Test_userListener_Listen(t *testing.T) {
 		checkSent: func(message.ID, rounds.Round) bool {
 			return false
 		},
+		slowMode: allowSlowMode,
 	}
 
 	// Call the listener
@@ -187,6 +193,7 @@ func Test_userListener_Listen_BadUserSig(t *testing.T) {
 		checkSent: func(message.ID, rounds.Round) bool {
 			return false
 		},
+		slowMode: allowSlowMode,
 	}
 
 	// Call the listener
@@ -250,6 +257,7 @@ func Test_userListener_Listen_BadRound(t *testing.T) {
 		checkSent: func(message.ID, rounds.Round) bool {
 			return false
 		},
+		slowMode: allowSlowMode,
 	}
 
 	// Call the listener
@@ -286,6 +294,7 @@ func Test_userListener_Listen_BadMessage(t *testing.T) {
 		checkSent: func(message.ID, rounds.Round) bool {
 			return false
 		},
+		slowMode: allowSlowMode,
 	}
 
 	// Call the listener
@@ -352,6 +361,7 @@ func Test_userListener_Listen_BadSizedBroadcast(t *testing.T) {
 		checkSent: func(message.ID, rounds.Round) bool {
 			return false
 		},
+		slowMode: allowSlowMode,
 	}
 
 	// Call the listener
@@ -364,3 +374,83 @@ func Test_userListener_Listen_BadSizedBroadcast(t *testing.T) {
 		t.Fatalf("Data returned after invalid listen")
 	}
 }
+
+// Tests that the message is rejected when it is sent within the slow mode
+// interval of the channel and that the interval is checked against the round
+// timestamp, not the timestamp chosen by the sender.
+func Test_userListener_Listen_SlowMode(t *testing.T) {
+	// Build inputs
+	chID := &id.ID{}
+	chID[0] = 1
+
+	r := rounds.Round{ID: 420, Timestamps: make(map[states.Round]time.Time)}
+	r.Timestamps[states.QUEUED] = netTime.Now()
+
+	rng := rand.New(rand.NewSource(42))
+	pub, priv, err := ed25519.GenerateKey(rng)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 keypair, cant run test")
+	}
+
+	cm := &ChannelMessage{
+		Lease:          int64(time.Hour),
+		RoundID:        uint64(r.ID),
+		Payload:        []byte("blarg"),
+		LocalTimestamp: r.Timestamps[states.QUEUED].Add(-time.Minute).UnixNano(),
+	}
+
+	cmSerial, err := proto.Marshal(cm)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto: %+v", err)
+	}
+
+	um := &UserMessage{
+		Message:      cmSerial,
+		Signature:    ed25519.Sign(priv, cmSerial),
+		ECCPublicKey: pub,
+	}
+
+	umSerial, err := proto.Marshal(um)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto: %+v", err)
+	}
+
+	// Build the listener
+	dummy := &triggerEventDummy{}
+	mt := MessageType(42)
+	var checked bool
+	var checkedTimestamp time.Time
+
+	al := userListener{
+		chID:    chID,
+		name:    &mockNameService{validChMsg: true},
+		trigger: dummy.triggerEvent,
+		checkSent: func(message.ID, rounds.Round) bool {
+			return false
+		},
+		slowMode: func(channelID *id.ID, pubKey ed25519.PublicKey,
+			messageType MessageType, timestamp time.Time) bool {
+			checked = channelID.Cmp(chID) && pubKey.Equal(pub) &&
+				messageType == mt
+			checkedTimestamp = timestamp
+			return false
+		},
+	}
+
+	// Call the listener
+	al.Listen(umSerial, nil, nil, mt.Marshal(),
+		receptionID.EphemeralIdentity{}, r)
+
+	// Check the results
+	if !checked {
+		t.Errorf("Slow mode not checked with the message sender and type.")
+	}
+	if !checkedTimestamp.Equal(r.Timestamps[states.QUEUED]) {
+		t.Errorf("Slow mode not checked with the round timestamp."+
+			"\nexpected: %s\nreceived: %s",
+			r.Timestamps[states.QUEUED], checkedTimestamp)
+	}
+	if dummy.gotData {
+		t.Fatalf("Data returned after message sent in slow mode")
+	}
+}
@@ -0,0 +1,338 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package channelsBot dispatches slash commands sent in channels (e.g.
+// "/roll 20") to registered handlers. It handles argument parsing, permission
+// checks, per-user cooldowns, and replying to the command with the result.
+package channelsBot
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// helpCommand is the name of the built-in command that lists every command.
+const helpCommand = "help"
+
+// Error messages.
+var (
+	// CommandAlreadyRegisteredErr is returned when registering a command with
+	// a name that is already registered.
+	CommandAlreadyRegisteredErr = errors.New(
+		"a command with the given name has already been registered")
+
+	// BotAlreadyStartedErr is returned when starting a bot more than once.
+	BotAlreadyStartedErr = errors.New("the bot has already been started")
+)
+
+// Manager is the subset of channels.Manager used by the Bot.
+type Manager interface {
+	// RegisterReceiveHandler registers a listener for a message type. See
+	// channels.Manager.RegisterReceiveHandler.
+	RegisterReceiveHandler(messageType channels.MessageType,
+		handler *channels.ReceiveMessageHandler) error
+
+	// SendReply is used to send a formatted message in reply to another
+	// message. See channels.Manager.SendReply.
+	SendReply(channelID *id.ID, msg string, replyTo message.ID,
+		validUntil time.Duration, params cmix.CMIXParams,
+		pings []ed25519.PublicKey) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	// IsModerator returns true if the user is a moderator of the channel.
+	IsModerator(channelID *id.ID, pubKey ed25519.PublicKey) bool
+
+	// GetIdentity returns the public identity of the user of the manager.
+	GetIdentity() cryptoChannel.Identity
+}
+
+// Bot receives text messages on every channel joined by the Manager and runs
+// the commands contained in them.
+type Bot struct {
+	m      Manager
+	params Params
+	pubKey ed25519.PublicKey
+
+	// Registered commands keyed on their name
+	commands map[string]Command
+
+	// The last time each user ran each command; used to enforce cooldowns
+	lastUse map[cooldownKey]time.Time
+
+	// Messages sent before this time are ignored so that old commands are not
+	// run again when the bot restarts
+	started time.Time
+
+	mux sync.Mutex
+}
+
+// cooldownKey identifies a command run by a user in a channel.
+type cooldownKey struct {
+	channelID id.ID
+	pubKey    string
+	command   string
+}
+
+// NewBot creates a new Bot for the channel manager. The built-in help command
+// is registered automatically. Commands are registered with Bot.Register and
+// no messages are received until Bot.Start is called.
+func NewBot(m Manager, params Params) *Bot {
+	b := &Bot{
+		m:        m,
+		params:   params,
+		pubKey:   m.GetIdentity().PubKey,
+		commands: make(map[string]Command),
+		lastUse:  make(map[cooldownKey]time.Time),
+	}
+
+	b.commands[helpCommand] = Command{
+		Name:        helpCommand,
+		Description: "Lists every command.",
+		Permission:  Anyone,
+		Handler:     b.help,
+	}
+
+	return b
+}
+
+// Register adds the command to the bot. Returns CommandAlreadyRegisteredErr if
+// a command with the same name exists or an error if the command is invalid.
+func (b *Bot) Register(cmd Command) error {
+	if err := cmd.validate(); err != nil {
+		return err
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if _, exists := b.commands[cmd.Name]; exists {
+		return CommandAlreadyRegisteredErr
+	}
+
+	b.commands[cmd.Name] = cmd
+	jww.INFO.Printf("[BOT] Registered command %s%s (permission: %s, "+
+		"cooldown: %s)", b.params.Prefix, cmd.Name, cmd.Permission, cmd.Cooldown)
+	return nil
+}
+
+// Start registers the bot to receive text and admin text messages. Messages
+// sent before Start is called are ignored. Returns BotAlreadyStartedErr if
+// called more than once.
+func (b *Bot) Start() error {
+	b.mux.Lock()
+	if !b.started.IsZero() {
+		b.mux.Unlock()
+		return BotAlreadyStartedErr
+	}
+	b.started = netTime.Now()
+	b.mux.Unlock()
+
+	for _, mt := range []channels.MessageType{channels.Text, channels.AdminText} {
+		err := b.m.RegisterReceiveHandler(mt, channels.NewReceiveMessageHandler(
+			"channelsBot", b.receive, true, true, false))
+		if err != nil {
+			return errors.Wrapf(err, "failed to register bot for %s", mt)
+		}
+	}
+
+	jww.INFO.Printf("[BOT] Started bot with prefix %q", b.params.Prefix)
+	return nil
+}
+
+// receive is the channels.MessageTypeReceiveMessage registered for text
+// messages. Commands are run on their own thread so that message processing
+// is not blocked.
+func (b *Bot) receive(channelID *id.ID, messageID message.ID,
+	messageType channels.MessageType, nickname string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	_ time.Duration, _ id.Round, _ rounds.Round, _ channels.SentStatus,
+	fromAdmin, hidden bool) uint64 {
+	if hidden || pubKey.Equal(b.pubKey) {
+		return 0
+	}
+
+	txt := &channels.CMIXChannelText{}
+	if err := proto.Unmarshal(content, txt); err != nil {
+		jww.WARN.Printf("[BOT] Failed to unmarshal %s message %s on "+
+			"channel %s: %+v", messageType, messageID, channelID, err)
+		return 0
+	}
+
+	name, rawArgs, ok := parseCommand(txt.Text, b.params.Prefix)
+	if !ok {
+		return 0
+	}
+
+	ctx := Context{
+		ChannelID: channelID,
+		MessageID: messageID,
+		Sender:    pubKey,
+		Nickname:  nickname,
+		Codeset:   codeset,
+		Command:   name,
+		RawArgs:   rawArgs,
+		Timestamp: timestamp,
+		FromAdmin: fromAdmin,
+	}
+
+	go b.handle(ctx)
+	return 0
+}
+
+// handle checks that the sender is allowed to run the command, runs it, and
+// replies with the result.
+func (b *Bot) handle(ctx Context) {
+	if ctx.Timestamp.Before(b.started) {
+		jww.DEBUG.Printf("[BOT] Ignoring command %s%s from before the bot "+
+			"started in message %s", b.params.Prefix, ctx.Command, ctx.MessageID)
+		return
+	}
+
+	b.mux.Lock()
+	cmd, exists := b.commands[ctx.Command]
+	b.mux.Unlock()
+
+	if !exists {
+		if b.params.ReplyToUnknown {
+			b.reply(ctx, fmt.Sprintf("Unknown command %s%s. Use %s%s to list "+
+				"commands.", b.params.Prefix, ctx.Command, b.params.Prefix,
+				helpCommand))
+		}
+		return
+	}
+
+	if !b.hasPermission(ctx, cmd.Permission) {
+		jww.INFO.Printf("[BOT] Denied %s%s to %x in channel %s",
+			b.params.Prefix, cmd.Name, ctx.Sender, ctx.ChannelID)
+		b.reply(ctx, fmt.Sprintf("Only %ss may use %s%s.",
+			cmd.Permission, b.params.Prefix, cmd.Name))
+		return
+	}
+
+	if wait := b.checkCooldown(ctx, cmd); wait > 0 {
+		b.reply(ctx, fmt.Sprintf("Please wait %s before using %s%s again.",
+			wait.Round(time.Second), b.params.Prefix, cmd.Name))
+		return
+	}
+
+	args, err := splitArgs(ctx.RawArgs)
+	if err != nil {
+		b.reply(ctx, fmt.Sprintf("Error: %s", err))
+		return
+	}
+	ctx.Args = args
+
+	jww.INFO.Printf("[BOT] Running command %s%s for %x in channel %s",
+		b.params.Prefix, cmd.Name, ctx.Sender, ctx.ChannelID)
+	reply, err := cmd.Handler(ctx)
+	if err != nil {
+		jww.WARN.Printf("[BOT] Command %s%s in message %s failed: %+v",
+			b.params.Prefix, cmd.Name, ctx.MessageID, err)
+		reply = fmt.Sprintf("Error: %s", err)
+	}
+
+	if reply != "" {
+		b.reply(ctx, reply)
+	}
+}
+
+// hasPermission returns true if the sender of the command has the permission.
+// The channel admin is considered a moderator.
+func (b *Bot) hasPermission(ctx Context, p Permission) bool {
+	switch p {
+	case Anyone:
+		return true
+	case Moderator:
+		return ctx.FromAdmin || b.m.IsModerator(ctx.ChannelID, ctx.Sender)
+	case Admin:
+		return ctx.FromAdmin
+	default:
+		return false
+	}
+}
+
+// checkCooldown returns how much longer the sender must wait before running
+// the command again. If the sender does not have to wait, then the current
+// use is recorded and zero is returned.
+func (b *Bot) checkCooldown(ctx Context, cmd Command) time.Duration {
+	if cmd.Cooldown <= 0 {
+		return 0
+	}
+
+	key := cooldownKey{*ctx.ChannelID, string(ctx.Sender), cmd.Name}
+	now := netTime.Now()
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if last, exists := b.lastUse[key]; exists {
+		if elapsed := now.Sub(last); elapsed < cmd.Cooldown {
+			return cmd.Cooldown - elapsed
+		}
+	}
+
+	b.lastUse[key] = now
+	return 0
+}
+
+// reply sends the text as a reply to the message containing the command.
+func (b *Bot) reply(ctx Context, text string) {
+	_, _, _, err := b.m.SendReply(ctx.ChannelID, text, ctx.MessageID,
+		b.params.ValidUntil, b.params.Cmix, nil)
+	if err != nil {
+		jww.ERROR.Printf("[BOT] Failed to reply to %s%s in message %s on "+
+			"channel %s: %+v", b.params.Prefix, ctx.Command, ctx.MessageID,
+			ctx.ChannelID, err)
+	}
+}
+
+// help is the handler for the built-in help command. It lists every command
+// sorted by name.
+func (b *Bot) help(Context) (string, error) {
+	b.mux.Lock()
+	cmds := make([]Command, 0, len(b.commands))
+	for _, cmd := range b.commands {
+		cmds = append(cmds, cmd)
+	}
+	b.mux.Unlock()
+
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+
+	var sb strings.Builder
+	sb.WriteString("Commands:")
+	for _, cmd := range cmds {
+		sb.WriteString("\n" + b.params.Prefix + cmd.Name)
+		if cmd.Usage != "" {
+			sb.WriteString(" " + cmd.Usage)
+		}
+		if cmd.Description != "" {
+			sb.WriteString(" - " + cmd.Description)
+		}
+		if cmd.Permission != Anyone {
+			sb.WriteString(" (" + cmd.Permission.String() + ")")
+		}
+	}
+
+	return sb.String(), nil
+}
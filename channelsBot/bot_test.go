@@ -0,0 +1,422 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsBot
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that Bot.Register adds a command and that registering a duplicate or
+// invalid command returns an error.
+func TestBot_Register(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	cmd := Command{Name: "ping", Handler: pong}
+	if err := b.Register(cmd); err != nil {
+		t.Fatalf("Failed to register command: %+v", err)
+	}
+	if _, exists := b.commands["ping"]; !exists {
+		t.Errorf("Command %q not registered.", cmd.Name)
+	}
+
+	for _, name := range []string{"ping", helpCommand} {
+		err := b.Register(Command{Name: name, Handler: pong})
+		if err != CommandAlreadyRegisteredErr {
+			t.Errorf("Unexpected error for duplicate command %q."+
+				"\nexpected: %v\nreceived: %+v",
+				name, CommandAlreadyRegisteredErr, err)
+		}
+	}
+
+	if err := b.Register(Command{Name: "Ping", Handler: pong}); err == nil {
+		t.Errorf("Did not get an error for an invalid command.")
+	}
+}
+
+// Tests that Bot.Start registers a handler for both text types and that
+// starting twice returns BotAlreadyStartedErr.
+func TestBot_Start(t *testing.T) {
+	b, m := newTestBot(t)
+
+	if err := b.Start(); err != nil {
+		t.Fatalf("Failed to start bot: %+v", err)
+	}
+
+	for _, mt := range []channels.MessageType{channels.Text, channels.AdminText} {
+		if _, exists := m.handlers[mt]; !exists {
+			t.Errorf("No handler registered for %s.", mt)
+		}
+	}
+
+	if err := b.Start(); err != BotAlreadyStartedErr {
+		t.Errorf("Unexpected error when starting twice."+
+			"\nexpected: %v\nreceived: %+v", BotAlreadyStartedErr, err)
+	}
+}
+
+// Tests that a command received by the registered handler is run with the
+// parsed arguments and that its result is sent as a reply.
+func TestBot_receive(t *testing.T) {
+	b, m := newTestBot(t)
+
+	argsChan := make(chan []string, 1)
+	err := b.Register(Command{Name: "echo", Handler: func(ctx Context) (
+		string, error) {
+		argsChan <- ctx.Args
+		return strings.Join(ctx.Args, "|"), nil
+	}})
+	if err != nil {
+		t.Fatalf("Failed to register command: %+v", err)
+	}
+	if err = b.Start(); err != nil {
+		t.Fatalf("Failed to start bot: %+v", err)
+	}
+
+	msgID := receiveText(t, b, channels.Text, `/echo one "two three"`,
+		newPubKey(t), false)
+
+	select {
+	case args := <-argsChan:
+		expected := []string{"one", "two three"}
+		if !reflect.DeepEqual(expected, args) {
+			t.Errorf("Unexpected args.\nexpected: %q\nreceived: %q",
+				expected, args)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for command to run.")
+	}
+
+	r := m.waitForReply(t)
+	if r.text != "one|two three" || r.replyTo != msgID {
+		t.Errorf("Unexpected reply.\nexpected: %q to %s\nreceived: %q to %s",
+			"one|two three", msgID, r.text, r.replyTo)
+	}
+}
+
+// Tests that the registered handler ignores messages from the bot itself,
+// hidden messages, and messages that are not commands.
+func TestBot_receive_Ignored(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	ran := make(chan struct{}, 3)
+	err := b.Register(Command{Name: "ping", Handler: func(Context) (
+		string, error) {
+		ran <- struct{}{}
+		return "", nil
+	}})
+	if err != nil {
+		t.Fatalf("Failed to register command: %+v", err)
+	}
+	if err = b.Start(); err != nil {
+		t.Fatalf("Failed to start bot: %+v", err)
+	}
+
+	receiveText(t, b, channels.Text, "/ping", b.pubKey, false)
+	receiveText(t, b, channels.Text, "/ping", newPubKey(t), true)
+	receiveText(t, b, channels.Text, "ping", newPubKey(t), false)
+
+	select {
+	case <-ran:
+		t.Errorf("Command ran for an ignored message.")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Tests that Bot.handle ignores commands sent before the bot started.
+func TestBot_handle_BeforeStart(t *testing.T) {
+	b, m := newTestBot(t)
+	_ = b.Register(Command{Name: "ping", Handler: pong})
+	b.started = netTime.Now()
+
+	ctx := newContext("ping", newPubKey(t), false)
+	ctx.Timestamp = b.started.Add(-time.Minute)
+	b.handle(ctx)
+
+	if len(m.replies) != 0 {
+		t.Errorf("Replied to a command sent before the bot started: %+v",
+			m.replies)
+	}
+}
+
+// Tests that Bot.handle only runs admin and moderator commands for users
+// with the permission.
+func TestBot_handle_Permission(t *testing.T) {
+	b, m := newTestBot(t)
+	for _, cmd := range []Command{
+		{Name: "anyone", Permission: Anyone, Handler: pong},
+		{Name: "mod", Permission: Moderator, Handler: pong},
+		{Name: "admin", Permission: Admin, Handler: pong},
+	} {
+		if err := b.Register(cmd); err != nil {
+			t.Fatalf("Failed to register %q: %+v", cmd.Name, err)
+		}
+	}
+
+	user, mod := newPubKey(t), newPubKey(t)
+	m.moderators[string(mod)] = true
+
+	tests := []struct {
+		command   string
+		sender    ed25519.PublicKey
+		fromAdmin bool
+		allowed   bool
+	}{
+		{"anyone", user, false, true},
+		{"mod", user, false, false},
+		{"mod", mod, false, true},
+		{"mod", user, true, true},
+		{"admin", mod, false, false},
+		{"admin", user, true, true},
+	}
+
+	for i, tt := range tests {
+		m.replies = nil
+		b.handle(newContext(tt.command, tt.sender, tt.fromAdmin))
+
+		if len(m.replies) != 1 {
+			t.Fatalf("Expected 1 reply for %q (%d), got %d",
+				tt.command, i, len(m.replies))
+		}
+		if allowed := m.replies[0].text == "pong"; allowed != tt.allowed {
+			t.Errorf("Unexpected permission for %q (%d)."+
+				"\nexpected: %t\nreceived: %t (%q)",
+				tt.command, i, tt.allowed, allowed, m.replies[0].text)
+		}
+	}
+}
+
+// Tests that Bot.handle enforces the cooldown per user and per channel.
+func TestBot_handle_Cooldown(t *testing.T) {
+	b, m := newTestBot(t)
+	err := b.Register(Command{Name: "ping", Cooldown: time.Hour, Handler: pong})
+	if err != nil {
+		t.Fatalf("Failed to register command: %+v", err)
+	}
+
+	user1, user2 := newPubKey(t), newPubKey(t)
+	ctx1 := newContext("ping", user1, false)
+	otherChannel := newContext("ping", user1, false)
+	otherChannel.ChannelID = &id.ID{2}
+
+	for i, ctx := range []Context{
+		ctx1, ctx1, newContext("ping", user2, false), otherChannel} {
+		b.handle(ctx)
+		expectPong := i != 1
+		if got := m.replies[i].text == "pong"; got != expectPong {
+			t.Errorf("Unexpected reply %d: %q", i, m.replies[i].text)
+		}
+	}
+
+	if !strings.Contains(m.replies[1].text, "wait") {
+		t.Errorf("Cooldown reply does not ask the user to wait: %q",
+			m.replies[1].text)
+	}
+}
+
+// Tests that Bot.handle only replies to unknown commands when
+// Params.ReplyToUnknown is set.
+func TestBot_handle_Unknown(t *testing.T) {
+	b, m := newTestBot(t)
+
+	b.handle(newContext("missing", newPubKey(t), false))
+	if len(m.replies) != 0 {
+		t.Errorf("Replied to unknown command: %+v", m.replies)
+	}
+
+	b.params.ReplyToUnknown = true
+	b.handle(newContext("missing", newPubKey(t), false))
+	if len(m.replies) != 1 || !strings.Contains(m.replies[0].text, "/missing") {
+		t.Errorf("Unexpected reply to unknown command: %+v", m.replies)
+	}
+}
+
+// Tests that Bot.handle replies with the error returned by the handler and
+// does not reply when the handler returns an empty string.
+func TestBot_handle_HandlerResult(t *testing.T) {
+	b, m := newTestBot(t)
+	_ = b.Register(Command{Name: "fail", Handler: func(Context) (string, error) {
+		return "ignored", errors.New("dice fell off the table")
+	}})
+	_ = b.Register(Command{Name: "quiet", Handler: func(Context) (string, error) {
+		return "", nil
+	}})
+
+	b.handle(newContext("quiet", newPubKey(t), false))
+	if len(m.replies) != 0 {
+		t.Errorf("Replied to command with empty result: %+v", m.replies)
+	}
+
+	b.handle(newContext("fail", newPubKey(t), false))
+	expected := "Error: dice fell off the table"
+	if len(m.replies) != 1 || m.replies[0].text != expected {
+		t.Errorf("Unexpected reply.\nexpected: %q\nreceived: %+v",
+			expected, m.replies)
+	}
+}
+
+// Tests that the built-in help command lists every command in order.
+func TestBot_help(t *testing.T) {
+	b, _ := newTestBot(t)
+	_ = b.Register(Command{Name: "roll", Usage: "<sides>",
+		Description: "Rolls a die.", Handler: pong})
+	_ = b.Register(Command{Name: "kick", Permission: Moderator, Handler: pong})
+
+	reply, err := b.help(Context{})
+	if err != nil {
+		t.Fatalf("Help returned an error: %+v", err)
+	}
+
+	expected := "Commands:" +
+		"\n/help - Lists every command." +
+		"\n/kick (moderator)" +
+		"\n/roll <sides> - Rolls a die."
+	if reply != expected {
+		t.Errorf("Unexpected help.\nexpected: %q\nreceived: %q",
+			expected, reply)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Test Utilities                                                             //
+////////////////////////////////////////////////////////////////////////////////
+
+// pong is a Handler that always replies "pong".
+func pong(Context) (string, error) { return "pong", nil }
+
+// newTestBot returns a Bot with default params using a mockManager.
+func newTestBot(t testing.TB) (*Bot, *mockManager) {
+	m := newMockManager(t)
+	return NewBot(m, DefaultParams()), m
+}
+
+// newContext returns a Context for the command on channel 1.
+func newContext(
+	command string, sender ed25519.PublicKey, fromAdmin bool) Context {
+	return Context{
+		ChannelID: &id.ID{1},
+		MessageID: message.ID{byte(len(command))},
+		Sender:    sender,
+		Command:   command,
+		Timestamp: netTime.Now(),
+		FromAdmin: fromAdmin,
+	}
+}
+
+// newPubKey generates a new random ed25519.PublicKey.
+func newPubKey(t testing.TB) ed25519.PublicKey {
+	pubKey, _, err := ed25519.GenerateKey(csprng.NewSystemRNG())
+	if err != nil {
+		t.Fatalf("Failed to generate key: %+v", err)
+	}
+	return pubKey
+}
+
+// reply records a call to mockManager.SendReply.
+type reply struct {
+	channelID *id.ID
+	text      string
+	replyTo   message.ID
+}
+
+// mockManager adheres to the Manager interface.
+type mockManager struct {
+	identity   cryptoChannel.PrivateIdentity
+	handlers   map[channels.MessageType]*channels.ReceiveMessageHandler
+	moderators map[string]bool
+	replies    []reply
+	replyChan  chan reply
+	mux        sync.Mutex
+}
+
+func newMockManager(t testing.TB) *mockManager {
+	pi, err := cryptoChannel.GenerateIdentity(csprng.NewSystemRNG())
+	if err != nil {
+		t.Fatalf("Failed to generate identity: %+v", err)
+	}
+	return &mockManager{
+		identity:   pi,
+		handlers:   make(map[channels.MessageType]*channels.ReceiveMessageHandler),
+		moderators: make(map[string]bool),
+		replyChan:  make(chan reply, 10),
+	}
+}
+
+// receiveText passes a text message to the listener of the bot and returns its
+// message ID.
+func receiveText(t testing.TB, b *Bot, mt channels.MessageType, text string,
+	pubKey ed25519.PublicKey, hidden bool) message.ID {
+	content, err := proto.Marshal(&channels.CMIXChannelText{Text: text})
+	if err != nil {
+		t.Fatalf("Failed to marshal text: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	msgID := message.DeriveChannelMessageID(chID, 1, content)
+	b.receive(chID, msgID, mt, "nick", content, nil, pubKey, 0, 0,
+		netTime.Now().Add(time.Second), time.Time{}, channels.ValidForever, 1,
+		rounds.Round{ID: 1}, channels.Delivered, mt == channels.AdminText,
+		hidden)
+	return msgID
+}
+
+// waitForReply waits for a reply to be sent.
+func (m *mockManager) waitForReply(t testing.TB) reply {
+	select {
+	case r := <-m.replyChan:
+		return r
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for reply.")
+	}
+	return reply{}
+}
+
+func (m *mockManager) RegisterReceiveHandler(messageType channels.MessageType,
+	handler *channels.ReceiveMessageHandler) error {
+	m.handlers[messageType] = handler
+	return nil
+}
+
+func (m *mockManager) SendReply(channelID *id.ID, msg string,
+	replyTo message.ID, _ time.Duration, _ cmix.CMIXParams,
+	_ []ed25519.PublicKey) (message.ID, rounds.Round, ephemeral.Id, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	r := reply{channelID, msg, replyTo}
+	m.replies = append(m.replies, r)
+	select {
+	case m.replyChan <- r:
+	default:
+	}
+	return message.ID{}, rounds.Round{}, ephemeral.Id{}, nil
+}
+
+func (m *mockManager) IsModerator(_ *id.ID, pubKey ed25519.PublicKey) bool {
+	return m.moderators[string(pubKey)]
+}
+
+func (m *mockManager) GetIdentity() cryptoChannel.Identity {
+	return m.identity.GetIdentity()
+}
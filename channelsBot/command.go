@@ -0,0 +1,211 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsBot
+
+import (
+	"crypto/ed25519"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// MaxCommandNameLen is the maximum length, in bytes, of a command name.
+const MaxCommandNameLen = 32
+
+// validCommandName matches the allowed command names: lowercase letters,
+// digits, dashes, and underscores, starting with a letter.
+var validCommandName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Permission describes who is allowed to run a command.
+type Permission uint8
+
+const (
+	// Anyone allows every user that can post in the channel to run the
+	// command.
+	Anyone Permission = iota
+
+	// Moderator allows channel moderators and the channel admin to run the
+	// command.
+	Moderator
+
+	// Admin only allows the channel admin to run the command. The command must
+	// be sent as an admin message.
+	Admin
+)
+
+// String returns a human-readable version of [Permission], used for debugging
+// and logging. This function adheres to the [fmt.Stringer] interface.
+func (p Permission) String() string {
+	switch p {
+	case Anyone:
+		return "anyone"
+	case Moderator:
+		return "moderator"
+	case Admin:
+		return "admin"
+	default:
+		return "INVALID PERMISSION: " + strconv.Itoa(int(p))
+	}
+}
+
+// Handler is called when a command is run. The returned string is sent as a
+// reply to the message containing the command; no reply is sent if it is
+// empty. If an error is returned, it is sent as the reply instead.
+type Handler func(ctx Context) (reply string, err error)
+
+// Command describes a slash command that can be registered with a Bot.
+type Command struct {
+	// Name is the name of the command without the prefix (e.g. "roll" for
+	// "/roll"). It must be lowercase and no longer than MaxCommandNameLen.
+	Name string
+
+	// Usage describes the arguments of the command (e.g. "<sides>"). It is
+	// shown in the help text.
+	Usage string
+
+	// Description is shown in the help text.
+	Description string
+
+	// Permission is the minimum permission needed to run the command.
+	Permission Permission
+
+	// Cooldown is the minimum time a user must wait between two uses of the
+	// command in the same channel. Set to 0 for no cooldown.
+	Cooldown time.Duration
+
+	// Handler is called when the command is run.
+	Handler Handler
+}
+
+// Context contains the information about a received command that is passed to
+// its Handler.
+type Context struct {
+	// ChannelID is the ID of the channel the command was sent to.
+	ChannelID *id.ID
+
+	// MessageID is the ID of the message containing the command.
+	MessageID message.ID
+
+	// Sender is the public key of the user that sent the command.
+	Sender ed25519.PublicKey
+
+	// Nickname is the nickname of the sender, if one is set.
+	Nickname string
+
+	// Codeset is the codeset version of the sender's identity.
+	Codeset uint8
+
+	// Command is the name of the command that was run.
+	Command string
+
+	// Args is the list of arguments passed to the command. Arguments are
+	// separated by whitespace unless enclosed in double quotes.
+	Args []string
+
+	// RawArgs is the unparsed text following the command name.
+	RawArgs string
+
+	// Timestamp is the time the message was sent.
+	Timestamp time.Time
+
+	// FromAdmin is true if the command was sent as an admin message.
+	FromAdmin bool
+}
+
+// validate returns an error if the command is missing a handler or has an
+// invalid name or permission.
+func (c Command) validate() error {
+	if len(c.Name) > MaxCommandNameLen {
+		return errors.Errorf("command name %q longer than %d bytes",
+			c.Name, MaxCommandNameLen)
+	} else if !validCommandName.MatchString(c.Name) {
+		return errors.Errorf("invalid command name %q", c.Name)
+	} else if c.Permission > Admin {
+		return errors.Errorf("invalid permission %s for command %q",
+			c.Permission, c.Name)
+	} else if c.Handler == nil {
+		return errors.Errorf("command %q has no handler", c.Name)
+	}
+	return nil
+}
+
+// parseCommand splits the text of a message into the command name and the
+// unparsed arguments. The name is converted to lowercase. Returns false if the
+// text does not start with the prefix followed by a name.
+func parseCommand(text, prefix string) (name, rawArgs string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, prefix) {
+		return "", "", false
+	}
+	text = text[len(prefix):]
+
+	i := strings.IndexFunc(text, isSpace)
+	if i == -1 {
+		name = text
+	} else {
+		name, rawArgs = text[:i], strings.TrimSpace(text[i:])
+	}
+
+	if name == "" {
+		return "", "", false
+	}
+	return strings.ToLower(name), rawArgs, true
+}
+
+// splitArgs splits the raw arguments on whitespace. Text enclosed in double
+// quotes is kept as a single argument and a backslash escapes the next
+// character. Returns an error if a quote is not closed.
+func splitArgs(rawArgs string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var inQuotes, escaped, inArg bool
+
+	for _, r := range rawArgs {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped, inArg = true, true
+		case r == '"':
+			inQuotes, inArg = !inQuotes, true
+		case isSpace(r) && !inQuotes:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inQuotes {
+		return nil, errors.New("unterminated quote in arguments")
+	} else if escaped {
+		current.WriteRune('\\')
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// isSpace returns true if the rune is a space, tab, or newline.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
@@ -0,0 +1,111 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsBot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Tests that parseCommand returns the expected name and arguments for a list
+// of messages.
+func Test_parseCommand(t *testing.T) {
+	tests := []struct {
+		text, name, rawArgs string
+		ok                  bool
+	}{
+		{"/ping", "ping", "", true},
+		{"  /PING  ", "ping", "", true},
+		{"/roll 20", "roll", "20", true},
+		{"/echo  hello   world ", "echo", "hello   world", true},
+		{"/echo\thello", "echo", "hello", true},
+		{"hello /ping", "", "", false},
+		{"/", "", "", false},
+		{"/ ping", "", "", false},
+		{"", "", "", false},
+	}
+
+	for i, tt := range tests {
+		name, rawArgs, ok := parseCommand(tt.text, "/")
+		if name != tt.name || rawArgs != tt.rawArgs || ok != tt.ok {
+			t.Errorf("Unexpected result for %q (%d)."+
+				"\nexpected: %q %q %t\nreceived: %q %q %t", tt.text, i,
+				tt.name, tt.rawArgs, tt.ok, name, rawArgs, ok)
+		}
+	}
+}
+
+// Tests that splitArgs splits on whitespace, keeps quoted text together, and
+// handles escaped characters.
+func Test_splitArgs(t *testing.T) {
+	tests := []struct {
+		rawArgs string
+		args    []string
+	}{
+		{"", nil},
+		{"one", []string{"one"}},
+		{"one two  three", []string{"one", "two", "three"}},
+		{`"one two" three`, []string{"one two", "three"}},
+		{`say "" now`, []string{"say", "", "now"}},
+		{`a\ b c`, []string{"a b", "c"}},
+		{`"say \"hi\""`, []string{`say "hi"`}},
+		{`trailing\`, []string{`trailing\`}},
+	}
+
+	for i, tt := range tests {
+		args, err := splitArgs(tt.rawArgs)
+		if err != nil {
+			t.Errorf("Failed to split %q (%d): %+v", tt.rawArgs, i, err)
+		} else if !reflect.DeepEqual(tt.args, args) {
+			t.Errorf("Unexpected args for %q (%d).\nexpected: %q\nreceived: %q",
+				tt.rawArgs, i, tt.args, args)
+		}
+	}
+}
+
+// Error path: Tests that splitArgs returns an error for an unterminated quote.
+func Test_splitArgs_UnterminatedQuote(t *testing.T) {
+	_, err := splitArgs(`one "two`)
+	if err == nil {
+		t.Errorf("Did not get an error for an unterminated quote.")
+	}
+}
+
+// Tests that Command.validate returns an error for each invalid command and
+// no error for a valid command.
+func TestCommand_validate(t *testing.T) {
+	h := func(Context) (string, error) { return "", nil }
+
+	valid := []Command{
+		{Name: "ping", Handler: h},
+		{Name: "roll-d20", Permission: Admin, Handler: h},
+		{Name: "a_1", Permission: Moderator, Handler: h},
+	}
+	for i, cmd := range valid {
+		if err := cmd.validate(); err != nil {
+			t.Errorf("Failed to validate command %q (%d): %+v", cmd.Name, i, err)
+		}
+	}
+
+	invalid := []Command{
+		{Name: "", Handler: h},
+		{Name: "Ping", Handler: h},
+		{Name: "1ping", Handler: h},
+		{Name: "pi ng", Handler: h},
+		{Name: strings.Repeat("a", MaxCommandNameLen+1), Handler: h},
+		{Name: "ping", Permission: Admin + 1, Handler: h},
+		{Name: "ping"},
+	}
+	for i, cmd := range invalid {
+		if err := cmd.validate(); err == nil {
+			t.Errorf("Invalid command %q (%d) did not return an error.",
+				cmd.Name, i)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsBot
+
+import (
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix"
+)
+
+const (
+	defaultPrefix = "/"
+)
+
+// Params contains parameters used by the Bot.
+type Params struct {
+	// Prefix is the string that every command must start with.
+	Prefix string `json:"prefix"`
+
+	// ReplyToUnknown, if true, replies to commands that are not registered.
+	// Leave this false if more than one bot shares a channel.
+	ReplyToUnknown bool `json:"replyToUnknown"`
+
+	// ValidUntil is the lease of reply messages. Replies are kept forever if
+	// set to channels.ValidForever.
+	ValidUntil time.Duration `json:"validUntil"`
+
+	// Cmix are the parameters used when sending replies.
+	Cmix cmix.CMIXParams `json:"cmix"`
+}
+
+// DefaultParams returns a Params object filled with the default values.
+func DefaultParams() Params {
+	return Params{
+		Prefix:         defaultPrefix,
+		ReplyToUnknown: false,
+		ValidUntil:     channels.ValidForever,
+		Cmix:           cmix.GetDefaultCMIXParams(),
+	}
+}
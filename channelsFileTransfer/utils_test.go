@@ -232,6 +232,18 @@ func (m *mockCmix) SendWithAssembler(*id.ID, cmix.MessageAssembler, cmix.CMIXPar
 func (m *mockCmix) SendManyWithAssembler([]*id.ID, cmix.ManyMessageAssembler, cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockCmix) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockCmix) GetQueuedSends() []cmix.QueuedSend {
+	panic("implement me")
+}
+func (m *mockCmix) CancelQueuedSend(uint64) error {
+	panic("implement me")
+}
+func (m *mockCmix) SetOutboxCallback(cmix.OutboxCallback) {
+	panic("implement me")
+}
 func (m *mockCmix) AddIdentity(*id.ID, time.Time, bool, message.Processor) {}
 func (m *mockCmix) AddIdentityWithHistory(*id.ID, time.Time, time.Time, bool, message.Processor) {
 	panic("implement me")
@@ -540,6 +552,21 @@ func (m *mockEventModel) GetMessage(cryptoMessage.ID) (channels.ModelMessage, er
 }
 func (m *mockEventModel) DeleteMessage(cryptoMessage.ID) error     { panic("implement me") }
 func (m *mockEventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) { panic("implement me") }
+func (m *mockEventModel) EditMessage(cryptoMessage.ID, channels.MessageEdit) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ReceivePollVote(cryptoMessage.ID, channels.ModelPollVote) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ClosePoll(cryptoMessage.ID, time.Time, bool) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) UpdateChannelMetadata(*id.ID, channels.ChannelMetadata) {
+	panic("implement me")
+}
 
 ////////////////////////////////////////////////////////////////////////////////
 // Mock Channels Manager                                                      //
@@ -588,6 +615,28 @@ func (m *mockChannelsManager) SendSilent(*id.ID, time.Duration, cmix.CMIXParams)
 func (m *mockChannelsManager) SendInvite(*id.ID, string, *cryptoBroadcast.Channel, string, time.Duration, cmix.CMIXParams, []ed25519.PublicKey) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannelsManager) SendPoll(*id.ID, string, []string, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SendPollVote(*id.ID, cryptoMessage.ID, uint32, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SendTyping(*id.ID, bool, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) ScheduleSend(*id.ID, channels.MessageType, []byte, time.Time, time.Duration, cmix.CMIXParams, map[channels.PingType][]ed25519.PublicKey) (channels.ScheduledSend, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) ScheduleMessage(*id.ID, string, time.Time, time.Duration, cmix.CMIXParams, []ed25519.PublicKey) (channels.ScheduledSend, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetScheduledSends(*id.ID) []channels.ScheduledSend {
+	panic("implement me")
+}
+func (m *mockChannelsManager) CancelScheduledSend(uint64) error { panic("implement me") }
+func (m *mockChannelsManager) RescheduleSend(uint64, time.Time) error {
+	panic("implement me")
+}
 func (m *mockChannelsManager) GetNotificationStatus(*id.ID) (clientNotif.NotificationState, error) {
 	panic("implement me")
 }
@@ -622,12 +671,33 @@ func (m *mockChannelsManager) SendAdminGeneric(*id.ID, channels.MessageType, []b
 func (m *mockChannelsManager) DeleteMessage(*id.ID, cryptoMessage.ID, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannelsManager) EditMessage(*id.ID, cryptoMessage.ID, string, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
 func (m *mockChannelsManager) PinMessage(*id.ID, cryptoMessage.ID, bool, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannelsManager) ClosePoll(*id.ID, cryptoMessage.ID, bool, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
 func (m *mockChannelsManager) MuteUser(*id.ID, ed25519.PublicKey, bool, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannelsManager) AppointModerator(*id.ID, ed25519.PublicKey, bool, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SetSlowMode(*id.ID, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) UpdateChannelMetadata(*id.ID, channels.ChannelMetadata, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetChannelMetadata(*id.ID) (channels.ChannelMetadata, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) BanUser(*id.ID, ed25519.PublicKey, string, bool, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
 func (m *mockChannelsManager) GetIdentity() cryptoChannel.Identity          { panic("implement me") }
 func (m *mockChannelsManager) ExportPrivateIdentity(string) ([]byte, error) { panic("implement me") }
 func (m *mockChannelsManager) GetStorageTag() string                        { panic("implement me") }
@@ -643,6 +713,14 @@ func (m *mockChannelsManager) GetNickname(*id.ID) (nickname string, exists bool)
 
 func (m *mockChannelsManager) Muted(*id.ID) bool                        { panic("implement me") }
 func (m *mockChannelsManager) GetMutedUsers(*id.ID) []ed25519.PublicKey { panic("implement me") }
+func (m *mockChannelsManager) IsModerator(*id.ID, ed25519.PublicKey) bool {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetModerators(*id.ID) []ed25519.PublicKey    { panic("implement me") }
+func (m *mockChannelsManager) GetSlowMode(*id.ID) time.Duration            { panic("implement me") }
+func (m *mockChannelsManager) GetBannedUsers(*id.ID) []channels.BannedUser { panic("implement me") }
+func (m *mockChannelsManager) SetFloodFilter(int, time.Duration) error     { panic("implement me") }
+func (m *mockChannelsManager) GetFloodFilter() (int, time.Duration)        { panic("implement me") }
 func (m *mockChannelsManager) GetNotificationLevel(*id.ID) (channels.NotificationLevel, error) {
 	panic("implement me")
 }
@@ -660,3 +738,13 @@ func (m *mockChannelsManager) ImportChannelAdminKey(*id.ID, string, []byte) erro
 	panic("implement me")
 }
 func (m *mockChannelsManager) DeleteChannelAdminKey(*id.ID) error { panic("implement me") }
+func (m *mockChannelsManager) RotateChannelAdminKey(*id.ID, cmix.CMIXParams) (*cryptoBroadcast.Channel, cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) TransferChannelOwnership(*id.ID, ed25519.PublicKey, uint32, channels.AdminKeySender, cmix.CMIXParams) (*cryptoBroadcast.Channel, error) {
+	panic("implement me")
+}
+
+func (m *mockChannelsManager) ReceiveChannelAdminKey(*id.ID, []byte) error {
+	panic("implement me")
+}
@@ -457,9 +457,31 @@ func (m *eventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) {
 	jww.WARN.Printf("MuteUser is unimplemented in the CLI event model!")
 }
 
+func (m *eventModel) EditMessage(message.ID, channels.MessageEdit) (uint64, error) {
+	jww.WARN.Printf("EditMessage is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
+func (m *eventModel) ReceivePollVote(message.ID, channels.ModelPollVote) (uint64, error) {
+	jww.WARN.Printf("ReceivePollVote is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
+func (m *eventModel) ClosePoll(message.ID, time.Time, bool) (uint64, error) {
+	jww.WARN.Printf("ClosePoll is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
+func (m *eventModel) UpdateChannelMetadata(*id.ID, channels.ChannelMetadata) {
+	jww.WARN.Printf("UpdateChannelMetadata is unimplemented in the CLI event model!")
+}
+
 type channelCbs struct{}
 
 func (c *channelCbs) AdminKeysUpdate(*id.ID, bool) {}
+func (c *channelCbs) AdminKeyRotated(oldChannelID, newChannelID *id.ID) {
+	jww.INFO.Printf("AdminKeyRotated(%s, %s)", oldChannelID, newChannelID)
+}
 func (c *channelCbs) NicknameUpdate(channelID *id.ID, nickname string,
 	exists bool) {
 	jww.INFO.Printf("NickNameUpdate(%s, %s, %v)", channelID, nickname, exists)
@@ -473,6 +495,16 @@ func (c *channelCbs) ChannelUpdate(*id.ID, bool)                {}
 func (c *channelCbs) MessageReceived(int64, *id.ID, bool)       {}
 func (c *channelCbs) UserMuted(*id.ID, ed25519.PublicKey, bool) {}
 func (c *channelCbs) MessageDeleted(message.ID)                 {}
+func (c *channelCbs) UserTyping(channelID *id.ID, pubKey ed25519.PublicKey,
+	_ uint8, nickname string, typing bool) {
+	jww.INFO.Printf("UserTyping(%s, %x, %s, %v)",
+		channelID, pubKey, nickname, typing)
+}
+func (c *channelCbs) ScheduledSendUpdate(sendID uint64, channelID *id.ID,
+	messageID message.ID, err error) {
+	jww.INFO.Printf("ScheduledSendUpdate(%d, %s, %s, %v)",
+		sendID, channelID, messageID, err)
+}
 
 func init() {
 	channelsCmd.Flags().String(channelsNameFlag, "ChannelName",
@@ -0,0 +1,239 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"crypto/rand"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+	"github.com/spf13/viper"
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/channelsBot"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/xx_network/primitives/netTime"
+	"gitlab.com/xx_network/primitives/utils"
+)
+
+// maxRollSides is the largest die that can be rolled by the example bot.
+const maxRollSides = 1000
+
+// channelsBotCmd runs an example bot that answers slash commands in a channel.
+var channelsBotCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Runs an example bot that answers slash commands in a channel.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Create client
+		cmixParams, e2eParams := initParams()
+		authCbs := makeAuthCallbacks(
+			viper.GetBool(unsafeChannelCreationFlag), e2eParams)
+		user := initE2e(cmixParams, e2eParams, authCbs)
+
+		// Print user's reception ID
+		jww.INFO.Printf("User: %s", user.GetReceptionIdentity().ID)
+
+		// Wait for user to be connected to network
+		err := user.StartNetworkFollower(5 * time.Second)
+		if err != nil {
+			jww.FATAL.Panicf("[BOT] %+v", err)
+		}
+
+		// Wait until connected or crash on timeout
+		connected := make(chan bool, 10)
+		user.GetCmix().AddHealthCallback(func(c bool) { connected <- c })
+		waitUntilConnected(connected)
+
+		// After connection, wait until registered with at least 85% of nodes
+		for numReg, total := 1, 100; numReg < (total*3)/4; {
+			time.Sleep(1 * time.Second)
+
+			numReg, total, err = user.GetNodeRegistrationStatus()
+			if err != nil {
+				jww.FATAL.Panicf("Node registration status error: %+v", err)
+			}
+
+			jww.INFO.Printf("Registering with nodes (%d/%d)...", numReg, total)
+		}
+
+		// Load or make channel private identity
+		chanIdentityPath := viper.GetString(channelsBotChanIdPathFlag)
+		rng := user.GetRng().GetStream()
+		chanIdentity, err := getOrMakeChannelIdentity(chanIdentityPath, rng)
+		rng.Close()
+		if err != nil {
+			jww.FATAL.Panicf("[BOT] Failed to get or make channel private "+
+				"identity %q: %+v", chanIdentityPath, err)
+		}
+
+		// Construct channels manager
+		em := &eventModel{}
+		em.api, err = channels.NewManager(chanIdentity,
+			user.GetStorage().GetKV(), user.GetCmix(), user.GetRng(), em, nil,
+			user.AddService, nil, &channelCbs{})
+		if err != nil {
+			jww.FATAL.Panicf("[BOT] Failed to create channels manager: %+v", err)
+		}
+
+		// Load the channel and join it if requested
+		marshalledChan, err := utils.ReadFile(
+			viper.GetString(channelsBotChanPathFlag))
+		if err != nil {
+			jww.FATAL.Panicf("[BOT] Failed to read channel from file: %+v", err)
+		}
+		channel, err := cryptoBroadcast.UnmarshalChannel(marshalledChan)
+		if err != nil {
+			jww.FATAL.Panicf("[BOT] Failed to unmarshal channel: %+v", err)
+		}
+		if viper.GetBool(channelsBotJoinFlag) {
+			err = em.api.JoinChannel(channel)
+			if err != nil && !errors.Is(err, channels.ChannelAlreadyExistsErr) {
+				jww.FATAL.Panicf("[BOT] Failed to join channel: %+v", err)
+			}
+		}
+
+		// Create and start the bot
+		p := channelsBot.DefaultParams()
+		if viper.IsSet(channelsBotPrefixFlag) {
+			p.Prefix = viper.GetString(channelsBotPrefixFlag)
+		}
+		bot := channelsBot.NewBot(em.api, p)
+		stop := make(chan struct{}, 1)
+		for _, c := range exampleBotCommands(stop) {
+			if err = bot.Register(c); err != nil {
+				jww.FATAL.Panicf(
+					"[BOT] Failed to register command %q: %+v", c.Name, err)
+			}
+		}
+		if err = bot.Start(); err != nil {
+			jww.FATAL.Panicf("[BOT] Failed to start bot: %+v", err)
+		}
+		jww.INFO.Printf("[BOT] Listening for commands in channel %s (ID %s)",
+			channel.Name, channel.ReceptionID)
+
+		// Run until the run time elapses, the bot is stopped by the channel
+		// admin, or the user terminates the program
+		var timeout <-chan time.Time
+		if runTime := viper.GetDuration(channelsBotRunTimeFlag); runTime > 0 {
+			timeout = time.After(runTime)
+		}
+		c := make(chan os.Signal, 10)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		select {
+		case <-timeout:
+			jww.INFO.Printf("[BOT] Run time elapsed.")
+		case <-stop:
+			jww.INFO.Printf("[BOT] Stopped by channel admin.")
+		case <-c:
+			jww.INFO.Printf("[BOT] Interrupted.")
+		}
+
+		// Stop network follower
+		if err = user.StopNetworkFollower(); err != nil {
+			jww.WARN.Printf("[BOT] Failed to stop network follower: %+v", err)
+		}
+
+		jww.INFO.Printf("[BOT] Completed execution.")
+	},
+}
+
+// exampleBotCommands returns the commands of the example bot. The stop channel
+// is signalled when the channel admin runs the stop command.
+func exampleBotCommands(stop chan<- struct{}) []channelsBot.Command {
+	started := netTime.Now()
+	return []channelsBot.Command{{
+		Name:        "ping",
+		Description: "Replies with pong.",
+		Handler: func(channelsBot.Context) (string, error) {
+			return "pong", nil
+		},
+	}, {
+		Name:        "echo",
+		Usage:       "<text>",
+		Description: "Repeats the text.",
+		Cooldown:    5 * time.Second,
+		Handler: func(ctx channelsBot.Context) (string, error) {
+			if ctx.RawArgs == "" {
+				return "", errors.New("nothing to echo")
+			}
+			return ctx.RawArgs, nil
+		},
+	}, {
+		Name:        "roll",
+		Usage:       "[sides]",
+		Description: "Rolls a die with 6 sides or the given number of sides.",
+		Cooldown:    10 * time.Second,
+		Handler: func(ctx channelsBot.Context) (string, error) {
+			sides := int64(6)
+			if len(ctx.Args) > 0 {
+				var err error
+				sides, err = strconv.ParseInt(ctx.Args[0], 10, 64)
+				if err != nil || sides < 2 || sides > maxRollSides {
+					return "", errors.Errorf(
+						"sides must be a number from 2 to %d", maxRollSides)
+				}
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(sides))
+			if err != nil {
+				return "", err
+			}
+			return "Rolled " + n.Add(n, big.NewInt(1)).String(), nil
+		},
+	}, {
+		Name:        "uptime",
+		Description: "Shows how long the bot has been running.",
+		Permission:  channelsBot.Moderator,
+		Handler: func(channelsBot.Context) (string, error) {
+			return "Up for " + netTime.Since(started).Round(time.Second).String(),
+				nil
+		},
+	}, {
+		Name:        "stop",
+		Description: "Stops the bot.",
+		Permission:  channelsBot.Admin,
+		Handler: func(channelsBot.Context) (string, error) {
+			select {
+			case stop <- struct{}{}:
+			default:
+			}
+			return "Stopping.", nil
+		},
+	}}
+}
+
+func init() {
+	channelsBotCmd.Flags().String(channelsBotChanIdPathFlag, "",
+		"The file path for the channel identity of the bot.")
+	bindFlagHelper(channelsBotChanIdPathFlag, channelsBotCmd)
+
+	channelsBotCmd.Flags().String(channelsBotChanPathFlag, "",
+		"The file path of the channel the bot listens in.")
+	bindFlagHelper(channelsBotChanPathFlag, channelsBotCmd)
+
+	channelsBotCmd.Flags().Bool(channelsBotJoinFlag, false,
+		"Determines if the channel loaded from 'botChannelPath' will be "+
+			"joined.")
+	bindFlagHelper(channelsBotJoinFlag, channelsBotCmd)
+
+	channelsBotCmd.Flags().String(channelsBotPrefixFlag, "/",
+		"The prefix that every command starts with.")
+	bindFlagHelper(channelsBotPrefixFlag, channelsBotCmd)
+
+	channelsBotCmd.Flags().Duration(channelsBotRunTimeFlag, 0,
+		"How long the bot runs for. Runs until interrupted if 0.")
+	bindFlagHelper(channelsBotRunTimeFlag, channelsBotCmd)
+
+	channelsCmd.AddCommand(channelsBotCmd)
+}
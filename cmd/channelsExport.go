@@ -0,0 +1,135 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+	"github.com/spf13/viper"
+	"gitlab.com/elixxir/client/v4/channels/storage"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/utils"
+)
+
+// channelsExportCmd exports the history of a channel from a channels event
+// model database. It does not connect to the network.
+var channelsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the history of a channel as JSON or as an HTML transcript.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dbPath, err := utils.ExpandPath(
+			viper.GetString(channelsExportDatabaseFlag))
+		if err != nil {
+			jww.FATAL.Panicf("[%s] Failed to expand database path: %+v",
+				channelsPrintHeader, err)
+		}
+		if !utils.Exists(dbPath) {
+			jww.FATAL.Panicf("[%s] Database %q does not exist",
+				channelsPrintHeader, dbPath)
+		}
+
+		channelID, err := parseChannelID(
+			viper.GetString(channelsExportChannelIdFlag))
+		if err != nil {
+			jww.FATAL.Panicf("[%s] Failed to parse channel ID: %+v",
+				channelsPrintHeader, err)
+		}
+
+		model, err := storage.NewEventModel(dbPath, &exportCbs{})
+		if err != nil {
+			jww.FATAL.Panicf("[%s] Failed to open database: %+v",
+				channelsPrintHeader, err)
+		}
+		export, err := model.(storage.Exporter).ExportChannel(channelID)
+		if err != nil {
+			jww.FATAL.Panicf("[%s] Failed to export channel %s: %+v",
+				channelsPrintHeader, channelID, err)
+		}
+
+		var out io.Writer = os.Stdout
+		if outPath := viper.GetString(channelsExportOutputFlag); outPath != "" {
+			outPath, err = utils.ExpandPath(outPath)
+			if err != nil {
+				jww.FATAL.Panicf("[%s] Failed to expand output path: %+v",
+					channelsPrintHeader, err)
+			}
+			f, err := os.Create(outPath)
+			if err != nil {
+				jww.FATAL.Panicf("[%s] Failed to create output file: %+v",
+					channelsPrintHeader, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		switch format := viper.GetString(channelsExportFormatFlag); format {
+		case "json":
+			err = export.WriteJSON(out)
+		case "html":
+			err = export.WriteHTML(out)
+		default:
+			jww.FATAL.Panicf("[%s] Unknown export format %q; must be json "+
+				"or html", channelsPrintHeader, format)
+		}
+		if err != nil {
+			jww.FATAL.Panicf("[%s] Failed to write export: %+v",
+				channelsPrintHeader, err)
+		}
+
+		jww.INFO.Printf("[%s] Exported %d messages from channel %s",
+			channelsPrintHeader, len(export.Messages), channelID)
+	},
+}
+
+// parseChannelID parses a base 64 encoded channel ID, as printed by
+// [id.ID.String].
+func parseChannelID(s string) (*id.ID, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return id.Unmarshal(data)
+}
+
+// exportCbs implements storage.UiCallbacks. No callbacks are needed when only
+// exporting.
+type exportCbs struct{}
+
+func (exportCbs) ChannelUpdate(*id.ID, bool)                {}
+func (exportCbs) MessageReceived(int64, *id.ID, bool)       {}
+func (exportCbs) UserMuted(*id.ID, ed25519.PublicKey, bool) {}
+func (exportCbs) MessageDeleted(message.ID)                 {}
+
+func init() {
+	channelsExportCmd.Flags().String(channelsExportDatabaseFlag, "",
+		"Path to the channels event model database to export from.")
+	bindFlagHelper(channelsExportDatabaseFlag, channelsExportCmd)
+
+	channelsExportCmd.Flags().String(channelsExportChannelIdFlag, "",
+		"Base 64 encoded ID of the channel to export.")
+	bindFlagHelper(channelsExportChannelIdFlag, channelsExportCmd)
+
+	channelsExportCmd.Flags().String(channelsExportFormatFlag, "json",
+		"Format of the export. Either 'json', which can be imported, or "+
+			"'html', a static transcript.")
+	bindFlagHelper(channelsExportFormatFlag, channelsExportCmd)
+
+	channelsExportCmd.Flags().String(channelsExportOutputFlag, "",
+		"Path to write the export to. Writes to stdout if empty.")
+	bindFlagHelper(channelsExportOutputFlag, channelsExportCmd)
+
+	channelsCmd.AddCommand(channelsExportCmd)
+}
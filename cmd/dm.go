@@ -116,6 +116,10 @@ var dmCmd = &cobra.Command{
 		if err != nil {
 			jww.FATAL.Panicf("%+v", err)
 		}
+		err = user.AddService(dmClient.StartProcesses)
+		if err != nil {
+			jww.FATAL.Panicf("%+v", err)
+		}
 
 		err = user.StartNetworkFollower(5 * time.Second)
 		if err != nil {
@@ -338,10 +342,16 @@ func (r *receiver) UpdateSentStatus(uuid uint64, messageID message.ID,
 	msg.status = status
 }
 
+func (r *receiver) UpdateReadStatus(_, _ ed25519.PublicKey, upTo message.ID) {
+	jww.INFO.Printf("UpdateReadStatus: %v", upTo)
+}
+
 func (r *receiver) DeleteMessage(message.ID, ed25519.PublicKey) bool {
 	return true
 }
 
+func (r *receiver) UpdateMessageRequest(ed25519.PublicKey, bool) {}
+
 func (r *receiver) GetConversation(ed25519.PublicKey) *dm.ModelConversation {
 	return nil
 }
@@ -349,3 +359,11 @@ func (r *receiver) GetConversation(ed25519.PublicKey) *dm.ModelConversation {
 func (r *receiver) GetConversations() []dm.ModelConversation {
 	return nil
 }
+
+func (r *receiver) UpdateGroup(group dm.ModelGroup) {
+	jww.INFO.Printf("UpdateGroup: %X", group.GroupID)
+}
+
+func (r *receiver) GetGroup(ed25519.PublicKey) *dm.ModelGroup {
+	return nil
+}
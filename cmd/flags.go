@@ -115,6 +115,19 @@ const (
 	channelsNewFlag              = "newChannel"
 	channelsSendFlag             = "sendToChannel"
 
+	///////////////// Channels export subcommand flags ////////////////////////
+	channelsExportDatabaseFlag  = "exportDatabase"
+	channelsExportChannelIdFlag = "exportChannelID"
+	channelsExportFormatFlag    = "exportFormat"
+	channelsExportOutputFlag    = "exportOutput"
+
+	///////////////// Channels bot subcommand flags ///////////////////////////
+	channelsBotChanIdPathFlag = "botChannelIdentityPath"
+	channelsBotChanPathFlag   = "botChannelPath"
+	channelsBotJoinFlag       = "botJoinChannel"
+	channelsBotPrefixFlag     = "botPrefix"
+	channelsBotRunTimeFlag    = "botRunTime"
+
 	///////////////// File Transfer subcommand flags //////////////////////////
 	channelsFtChanIdPathFlag    = "ftChannelIdentityPath"
 	channelsFtChanPathFlag      = "ftChannelPath"
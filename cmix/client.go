@@ -77,7 +77,8 @@ type client struct {
 	address.Space
 	identity.Tracker
 	health.Monitor
-	crit           *critical
+	outbox         *outbox
+	limiter        *sendLimiter
 	attemptTracker attempts.SendAttemptTracker
 
 	// Earliest tracked round
@@ -205,7 +206,7 @@ func (c *client) initialize(ndfile *ndf.NetworkDefinition) error {
 	c.Registrar, err = nodes.LoadRegistrar(
 		c.session, c.Sender, c.comms, c.rng, nodeChan, func() int {
 			return int(atomic.LoadUint64(c.numNodes))
-		})
+		}, c.param.Metrics)
 	if err != nil {
 		return err
 	}
@@ -225,7 +226,7 @@ func (c *client) initialize(ndfile *ndf.NetworkDefinition) error {
 	// Set up round handler
 	c.Pickup = pickup.NewPickup(
 		c.param.Pickup, c.Handler.GetMessageReceptionChannel(), c.Sender,
-		c.Retriever, c.comms, c.rng, c.instance, c.session)
+		c.Retriever, c.comms, c.rng, c.instance, c.session, c.param.Metrics)
 
 	// Add the identity system
 	c.Tracker = identity.NewOrLoadTracker(c.session, c.Space)
@@ -235,26 +236,34 @@ func (c *client) initialize(ndfile *ndf.NetworkDefinition) error {
 	// Set up the health monitor
 	c.Monitor = health.Init(c.instance, c.param.NetworkHealthTimeout)
 
-	// Set up critical message tracking (sendCmix only)
-	critSender := func(msg format.Message, recipient *id.ID, params CMIXParams,
-	) (rounds.Round, ephemeral.Id, error) {
+	// Set up the outbox, which also tracks critical messages (sendCmix only)
+	outboxSender := func(msg format.Message, recipient *id.ID,
+		params CMIXParams) (rounds.Round, ephemeral.Id, error) {
 		compiler := func(round id.Round) (format.Message, error) {
 			return msg, nil
 		}
 		r, eid, _, sendErr := sendCmixHelper(c.Sender, compiler, recipient, params, c.instance,
 			c.session.GetCmixGroup(), c.Registrar, c.rng, c.events,
-			c.session.GetTransmissionID(), c.comms, c.attemptTracker)
+			c.session.GetTransmissionID(), c.comms, c.attemptTracker,
+			c.param.Metrics)
 		return r, eid, sendErr
 
 	}
 
-	c.crit = newCritical(c.session.GetKV(), c.Monitor,
-		c.instance.GetRoundEvents(), critSender)
+	c.limiter = newSendLimiter(c.param)
+	c.outbox, err = newOrLoadOutbox(c.session.GetKV(), c.Monitor,
+		c.instance.GetRoundEvents(), c.limiter, outboxSender)
+	if err != nil {
+		return err
+	}
 
 	// Report health events
 	c.AddHealthCallback(func(isHealthy bool) {
 		c.events.Report(5, "health", "IsHealthy", strconv.FormatBool(isHealthy))
 	})
+	if c.param.Metrics != nil {
+		c.AddHealthCallback(c.param.Metrics.HealthChanged)
+	}
 
 	return nil
 }
@@ -267,7 +276,7 @@ func (c *client) initialize(ndfile *ndf.NetworkDefinition) error {
 //   - Message Handling Worker Group (/network/message/handle.go)
 //   - health tracker (/network/health)
 //   - Garbled Messages (/network/message/inProgress.go)
-//   - Critical Messages (/network/message/critical.go)
+//   - Outbox and Critical Messages (/cmix/outbox.go)
 //   - Ephemeral ID tracking (network/address/tracker.go)
 func (c *client) Follow(report ClientErrorReport) (stoppable.Stoppable, error) {
 	multi := stoppable.NewMulti("networkManager")
@@ -300,8 +309,8 @@ func (c *client) Follow(report ClientErrorReport) (stoppable.Stoppable, error) {
 	// Start the processes for the identity handler
 	multi.Add(c.Tracker.StartProcesses())
 
-	//Start the critical processing thread
-	multi.Add(c.crit.startProcessies())
+	// Start the outbox thread, which also resends critical messages
+	multi.Add(c.outbox.startProcesses())
 
 	//start the host pool thread
 	multi.Add(c.Sender.StartProcesses())
@@ -134,6 +134,9 @@ func (c *client) followNetwork(report ClientErrorReport,
 			estimatedSkew := c.skewTracker.Aggregate()
 			// invert the skew because we need to reverse it
 			netTime.SetOffset(-estimatedSkew)
+			if c.param.Metrics != nil {
+				c.param.Metrics.ClockSkew(estimatedSkew)
+			}
 
 			// Update ticker if tracker period changes
 			newTrackPeriod := c.GetTrackNetworkPeriod()
@@ -202,6 +205,7 @@ func (c *client) follow(identity receptionID.IdentityUse,
 	var rtt time.Duration
 	var sendTo *id.ID
 	var startTime time.Time
+	pollStart := netTime.Now()
 
 	result, err := c.SendToAny(func(host *connect.Host) (interface{}, error) {
 		jww.DEBUG.Printf("[Follow] Executing poll for %v(%s) range: %s-%s(%s) from %s",
@@ -222,6 +226,9 @@ func (c *client) follow(identity receptionID.IdentityUse,
 	}
 
 	now := netTime.Now()
+	if c.param.Metrics != nil {
+		c.param.Metrics.FollowerPoll(now.Sub(pollStart), err)
+	}
 
 	if err != nil {
 		if report != nil {
@@ -366,7 +373,9 @@ func (c *client) follow(identity receptionID.IdentityUse,
 	// Check rounds using the round checker function, which determines if there
 	// are messages waiting in rounds and then sends signals to the appropriate
 	// handling threads
+	roundsChecked := 0
 	roundChecker := func(rid id.Round) bool {
+		roundsChecked++
 		hasMessage := Checker(rid, filterList, identity.CR)
 		if !hasMessage && c.verboseRounds != nil {
 			c.verboseRounds.denote(rid, RoundState(NoMessageAvailable))
@@ -425,6 +434,9 @@ func (c *client) follow(identity receptionID.IdentityUse,
 	earliestRemaining, roundsWithMessages, roundsUnknown :=
 		gwRoundsState.RangeUnchecked(
 			updatedEarliestRound, c.param.KnownRoundsThreshold, roundChecker, 100)
+	if c.param.Metrics != nil {
+		c.param.Metrics.RoundsChecked(roundsChecked)
+	}
 
 	jww.DEBUG.Printf("[Follow] Processed RangeUnchecked for %d, Oldest: %d, "+
 		"firstUnchecked: %d, last Checked: %d, threshold: %d, "+
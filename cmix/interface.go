@@ -134,6 +134,33 @@ type Client interface {
 	SendManyWithAssembler(recipients []*id.ID, assembler ManyMessageAssembler,
 		params CMIXParams) (rounds.Round, []ephemeral.Id, error)
 
+	/* === Outbox =========================================================== */
+
+	// QueueSend adds a cMix message to the persistent outbox and returns its
+	// ID without waiting for it to be sent. Messages in the outbox are sent in
+	// the background, in order of CMIXParams.Priority, while the network is
+	// healthy and survive restarts. The result of the send is reported to the
+	// OutboxCallback set with SetOutboxCallback.
+	//
+	// Messages with CMIXParams.Critical set are resent until the round they
+	// are sent on completes.
+	QueueSend(recipient *id.ID, fingerprint format.Fingerprint,
+		service Service, payload, mac []byte, cmixParams CMIXParams) (
+		uint64, error)
+
+	// GetQueuedSends returns every message in the outbox, ordered by ID. This
+	// includes critical messages waiting to be resent.
+	GetQueuedSends() []QueuedSend
+
+	// CancelQueuedSend removes the message from the outbox. Returns
+	// QueuedSendNotFoundErr if it does not exist and QueuedSendInProgressErr
+	// if it is currently being sent.
+	CancelQueuedSend(sendID uint64) error
+
+	// SetOutboxCallback sets the callback that is called when a message in
+	// the outbox finishes sending. It replaces any previously set callback.
+	SetOutboxCallback(cb OutboxCallback)
+
 	/* === Message Reception ================================================ */
 	/* Identities are all network identities which the client is currently
 	   trying to pick up message on. An identity must be added to receive
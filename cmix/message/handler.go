@@ -120,12 +120,17 @@ func (h *handler) StartProcesses() stoppable.Stoppable {
 // handleMessages is a long-running thread that receives each Bundle from messageReception
 // and processes the messages in the Bundle
 func (h *handler) handleMessages(stop *stoppable.Single) {
+	// Tracks messages that are still being processed so that the worker does
+	// not report stopped while a processor may still write to storage
+	processing := sync.WaitGroup{}
 	for {
 		select {
 		case <-stop.Quit():
+			processing.Wait()
 			stop.ToStopped()
 			return
 		case bundle := <-h.messageReception:
+			processing.Add(len(bundle.Messages))
 			go func() {
 				wg := sync.WaitGroup{}
 				wg.Add(len(bundle.Messages))
@@ -135,6 +140,7 @@ func (h *handler) handleMessages(stop *stoppable.Single) {
 						msg.Digest())
 
 					go func() {
+						defer processing.Done()
 						count, ts := h.inProcess.Add(
 							msg, bundle.RoundInfo.Raw, bundle.Identity)
 						wg.Done()
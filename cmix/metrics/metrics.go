@@ -0,0 +1,51 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package metrics defines the Sink that the cMix client reports metrics to and
+// an adapter that exposes them in the Prometheus text exposition format.
+//
+// A Sink is set in cmix.Params. When it is not set, no metrics are collected.
+package metrics
+
+import (
+	"time"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Sink receives metrics from the cMix client. Its methods are called from
+// many threads and must not block.
+type Sink interface {
+	// FollowerPoll is called after every poll of the network by the follower
+	// with the time the poll took and the error, if it failed.
+	FollowerPoll(latency time.Duration, err error)
+
+	// RoundsChecked is called after every successful poll with the number of
+	// rounds checked for messages for the polled identity.
+	RoundsChecked(n int)
+
+	// MessagesPickedUp is called with the number of messages picked up from a
+	// gateway for a round.
+	MessagesPickedUp(n int)
+
+	// SendAttempt is called after every attempt to send messages to a gateway
+	// with the error, if it failed.
+	SendAttempt(gateway *id.ID, err error)
+
+	// NodeRegistration is called every time the client registers with a node
+	// with the number of nodes it is registered with and the number of nodes
+	// in the network.
+	NodeRegistration(registered, total int)
+
+	// ClockSkew is called after every iteration of the follower with the
+	// estimated offset of the local clock from the network.
+	ClockSkew(skew time.Duration)
+
+	// HealthChanged is called with the health of the network when the sink is
+	// added and every time the health changes.
+	HealthChanged(healthy bool)
+}
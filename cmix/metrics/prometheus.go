@@ -0,0 +1,267 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// namespace prefixes the name of every metric.
+const namespace = "xxdk_cmix_"
+
+// pollLatencyBuckets are the upper bounds, in seconds, of the buckets of the
+// follower poll latency histogram.
+var pollLatencyBuckets = []float64{.05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelEscaper escapes label values as required by the exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// Prometheus is a Sink that keeps the metrics in memory and writes them in the
+// Prometheus text exposition format. It implements http.Handler so that it
+// can be scraped directly.
+type Prometheus struct {
+	polls           uint64
+	pollFailures    uint64
+	pollLatencySum  float64
+	pollLatencyHist []uint64
+
+	roundsChecked    uint64
+	messagesPickedUp uint64
+
+	sendAttempts map[string]uint64
+	sendFailures map[string]uint64
+
+	registeredNodes int
+	totalNodes      int
+
+	clockSkew time.Duration
+
+	healthy           bool
+	healthKnown       bool
+	healthTransitions uint64
+
+	mux sync.Mutex
+}
+
+// Prometheus adheres to the Sink interface.
+var _ Sink = (*Prometheus)(nil)
+
+// NewPrometheus returns a new Prometheus sink with all metrics at zero.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		pollLatencyHist: make([]uint64, len(pollLatencyBuckets)),
+		sendAttempts:    make(map[string]uint64),
+		sendFailures:    make(map[string]uint64),
+	}
+}
+
+// FollowerPoll counts the poll and adds its latency to the histogram.
+func (p *Prometheus) FollowerPoll(latency time.Duration, err error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.polls++
+	if err != nil {
+		p.pollFailures++
+	}
+
+	seconds := latency.Seconds()
+	p.pollLatencySum += seconds
+	for i, bound := range pollLatencyBuckets {
+		if seconds <= bound {
+			p.pollLatencyHist[i]++
+		}
+	}
+}
+
+// RoundsChecked adds to the number of rounds checked.
+func (p *Prometheus) RoundsChecked(n int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.roundsChecked += uint64(n)
+}
+
+// MessagesPickedUp adds to the number of messages picked up.
+func (p *Prometheus) MessagesPickedUp(n int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.messagesPickedUp += uint64(n)
+}
+
+// SendAttempt counts the attempt, and the failure, for the gateway.
+func (p *Prometheus) SendAttempt(gateway *id.ID, err error) {
+	gwID := gateway.String()
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.sendAttempts[gwID]++
+	if err != nil {
+		p.sendFailures[gwID]++
+	}
+}
+
+// NodeRegistration sets the number of registered nodes and total nodes.
+func (p *Prometheus) NodeRegistration(registered, total int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.registeredNodes, p.totalNodes = registered, total
+}
+
+// ClockSkew sets the clock skew.
+func (p *Prometheus) ClockSkew(skew time.Duration) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.clockSkew = skew
+}
+
+// HealthChanged sets the health and counts the transition if it changed.
+func (p *Prometheus) HealthChanged(healthy bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.healthKnown && p.healthy != healthy {
+		p.healthTransitions++
+	}
+	p.healthy, p.healthKnown = healthy, true
+}
+
+// WriteTo writes all metrics to w in the Prometheus text exposition format.
+// This function adheres to the io.WriterTo interface.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	p.mux.Lock()
+	writeHeader(&buf, "follower_polls_total", "counter",
+		"Number of network polls by the follower.")
+	writeSample(&buf, "follower_polls_total", "", float64(p.polls))
+
+	writeHeader(&buf, "follower_poll_failures_total", "counter",
+		"Number of network polls by the follower that failed.")
+	writeSample(&buf, "follower_poll_failures_total", "",
+		float64(p.pollFailures))
+
+	writeHeader(&buf, "follower_poll_latency_seconds", "histogram",
+		"Time taken by network polls by the follower.")
+	for i, bound := range pollLatencyBuckets {
+		writeSample(&buf, "follower_poll_latency_seconds_bucket",
+			label("le", formatFloat(bound)), float64(p.pollLatencyHist[i]))
+	}
+	writeSample(&buf, "follower_poll_latency_seconds_bucket",
+		label("le", "+Inf"), float64(p.polls))
+	writeSample(&buf, "follower_poll_latency_seconds_sum", "",
+		p.pollLatencySum)
+	writeSample(&buf, "follower_poll_latency_seconds_count", "",
+		float64(p.polls))
+
+	writeHeader(&buf, "rounds_checked_total", "counter",
+		"Number of rounds checked for messages.")
+	writeSample(&buf, "rounds_checked_total", "", float64(p.roundsChecked))
+
+	writeHeader(&buf, "messages_picked_up_total", "counter",
+		"Number of messages picked up from gateways.")
+	writeSample(&buf, "messages_picked_up_total", "",
+		float64(p.messagesPickedUp))
+
+	writeHeader(&buf, "send_attempts_total", "counter",
+		"Number of attempts to send messages to each gateway.")
+	writeGatewaySamples(&buf, "send_attempts_total", p.sendAttempts)
+
+	writeHeader(&buf, "send_failures_total", "counter",
+		"Number of failed attempts to send messages to each gateway.")
+	writeGatewaySamples(&buf, "send_failures_total", p.sendFailures)
+
+	writeHeader(&buf, "registered_nodes", "gauge",
+		"Number of nodes the client is registered with.")
+	writeSample(&buf, "registered_nodes", "", float64(p.registeredNodes))
+
+	writeHeader(&buf, "nodes", "gauge",
+		"Number of nodes in the network.")
+	writeSample(&buf, "nodes", "", float64(p.totalNodes))
+
+	writeHeader(&buf, "clock_skew_seconds", "gauge",
+		"Estimated offset of the local clock from the network.")
+	writeSample(&buf, "clock_skew_seconds", "", p.clockSkew.Seconds())
+
+	writeHeader(&buf, "healthy", "gauge",
+		"1 if the network is healthy, otherwise 0.")
+	healthy := 0.
+	if p.healthy {
+		healthy = 1
+	}
+	writeSample(&buf, "healthy", "", healthy)
+
+	writeHeader(&buf, "health_transitions_total", "counter",
+		"Number of times the health of the network changed.")
+	writeSample(&buf, "health_transitions_total", "",
+		float64(p.healthTransitions))
+	p.mux.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format. This
+// function adheres to the http.Handler interface.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if _, err := p.WriteTo(w); err != nil {
+		jww.WARN.Printf("Failed to write metrics: %+v", err)
+	}
+}
+
+// writeHeader writes the HELP and TYPE lines of the metric.
+func writeHeader(buf *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(buf, "# HELP %s%s %s\n", namespace, name, help)
+	fmt.Fprintf(buf, "# TYPE %s%s %s\n", namespace, name, metricType)
+}
+
+// writeSample writes a single sample of the metric. The labels must already be
+// formatted.
+func writeSample(buf *bytes.Buffer, name, labels string, value float64) {
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(buf, "%s%s%s %s\n", namespace, name, labels,
+		formatFloat(value))
+}
+
+// writeGatewaySamples writes a sample for each gateway, sorted by gateway ID.
+func writeGatewaySamples(
+	buf *bytes.Buffer, name string, values map[string]uint64) {
+	gateways := make([]string, 0, len(values))
+	for gwID := range values {
+		gateways = append(gateways, gwID)
+	}
+	sort.Strings(gateways)
+
+	for _, gwID := range gateways {
+		writeSample(buf, name, label("gateway", gwID), float64(values[gwID]))
+	}
+}
+
+// label formats a label with its value escaped.
+func label(name, value string) string {
+	return name + `="` + labelEscaper.Replace(value) + `"`
+}
+
+// formatFloat formats a sample value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
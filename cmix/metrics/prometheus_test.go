@@ -0,0 +1,143 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that Prometheus.WriteTo writes the samples of every reported metric.
+func TestPrometheus_WriteTo(t *testing.T) {
+	p := NewPrometheus()
+	gw1 := id.NewIdFromString("gateway1", id.Gateway, t)
+	gw2 := id.NewIdFromString("gateway2", id.Gateway, t)
+
+	p.FollowerPoll(30*time.Millisecond, nil)
+	p.FollowerPoll(300*time.Millisecond, errors.New("poll failed"))
+	p.FollowerPoll(20*time.Second, nil)
+	p.RoundsChecked(5)
+	p.RoundsChecked(7)
+	p.MessagesPickedUp(2)
+	p.SendAttempt(gw1, nil)
+	p.SendAttempt(gw1, errors.New("send failed"))
+	p.SendAttempt(gw2, nil)
+	p.NodeRegistration(4, 10)
+	p.NodeRegistration(5, 10)
+	p.ClockSkew(-1500 * time.Millisecond)
+	p.HealthChanged(true)
+
+	var buf bytes.Buffer
+	if _, err := p.WriteTo(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %+v", err)
+	}
+
+	expected := []string{
+		`xxdk_cmix_follower_polls_total 3`,
+		`xxdk_cmix_follower_poll_failures_total 1`,
+		`xxdk_cmix_follower_poll_latency_seconds_bucket{le="0.05"} 1`,
+		`xxdk_cmix_follower_poll_latency_seconds_bucket{le="0.25"} 1`,
+		`xxdk_cmix_follower_poll_latency_seconds_bucket{le="0.5"} 2`,
+		`xxdk_cmix_follower_poll_latency_seconds_bucket{le="10"} 2`,
+		`xxdk_cmix_follower_poll_latency_seconds_bucket{le="+Inf"} 3`,
+		`xxdk_cmix_follower_poll_latency_seconds_sum 20.33`,
+		`xxdk_cmix_follower_poll_latency_seconds_count 3`,
+		`xxdk_cmix_rounds_checked_total 12`,
+		`xxdk_cmix_messages_picked_up_total 2`,
+		`xxdk_cmix_send_attempts_total{gateway="` + gw1.String() + `"} 2`,
+		`xxdk_cmix_send_attempts_total{gateway="` + gw2.String() + `"} 1`,
+		`xxdk_cmix_send_failures_total{gateway="` + gw1.String() + `"} 1`,
+		`xxdk_cmix_registered_nodes 5`,
+		`xxdk_cmix_nodes 10`,
+		`xxdk_cmix_clock_skew_seconds -1.5`,
+		`xxdk_cmix_healthy 1`,
+		`xxdk_cmix_health_transitions_total 0`,
+		`# TYPE xxdk_cmix_follower_poll_latency_seconds histogram`,
+		`# TYPE xxdk_cmix_send_attempts_total counter`,
+		`# TYPE xxdk_cmix_healthy gauge`,
+	}
+	lines := strings.Split(buf.String(), "\n")
+	for _, exp := range expected {
+		if !containsLine(lines, exp) {
+			t.Errorf("Output missing line %q:\n%s", exp, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(),
+		`send_failures_total{gateway="`+gw2.String()+`"}`) {
+		t.Errorf("Output has failures for gateway with no failures:\n%s",
+			buf.String())
+	}
+}
+
+// Tests that Prometheus.HealthChanged only counts changes in health as
+// transitions.
+func TestPrometheus_HealthChanged(t *testing.T) {
+	p := NewPrometheus()
+
+	for _, healthy := range []bool{false, false, true, true, false, true} {
+		p.HealthChanged(healthy)
+	}
+
+	if p.healthTransitions != 3 {
+		t.Errorf("Incorrect number of health transitions."+
+			"\nexpected: %d\nreceived: %d", 3, p.healthTransitions)
+	}
+	if !p.healthy {
+		t.Errorf("Incorrect health.\nexpected: %t\nreceived: %t",
+			true, p.healthy)
+	}
+}
+
+// Tests that Prometheus.ServeHTTP responds with the metrics and the content
+// type of the exposition format.
+func TestPrometheus_ServeHTTP(t *testing.T) {
+	p := NewPrometheus()
+	p.MessagesPickedUp(4)
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Incorrect content type.\nexpected: %q\nreceived: %q",
+			ContentType, ct)
+	}
+
+	var expected bytes.Buffer
+	if _, err := p.WriteTo(&expected); err != nil {
+		t.Fatalf("Failed to write metrics: %+v", err)
+	}
+	if w.Body.String() != expected.String() {
+		t.Errorf("Incorrect body.\nexpected: %s\nreceived: %s",
+			expected.String(), w.Body.String())
+	}
+}
+
+// Tests that label escapes backslashes, quotes, and new lines.
+func Test_label(t *testing.T) {
+	expected := `gateway="a\\b\"c\nd"`
+	if l := label("gateway", "a\\b\"c\nd"); l != expected {
+		t.Errorf("Incorrect label.\nexpected: %s\nreceived: %s", expected, l)
+	}
+}
+
+// containsLine returns true if one of the lines is equal to the line.
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
@@ -185,6 +185,9 @@ func registerWithNode(sender gateway.Sender, comms RegisterNodeCommsInterface,
 	}
 
 	r.add(nodeID, transmissionKey, validUntil, keyId)
+	if r.metrics != nil {
+		r.metrics.NodeRegistration(r.NumRegisteredNodes(), r.numnodesGetter())
+	}
 
 	jww.INFO.Printf("Completed registration with node %s,"+
 		" took %s", nodeID, time.Since(start))
@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/cmix/gateway"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
 	"gitlab.com/elixxir/client/v4/stoppable"
 	"gitlab.com/elixxir/comms/network"
@@ -65,6 +66,9 @@ type registrar struct {
 
 	numnodesGetter func() int
 
+	// metrics is told the registration progress. It may be nil.
+	metrics metrics.Sink
+
 	c chan network.NodeGateway
 
 	enableImmediateSending  bool
@@ -75,7 +79,8 @@ type registrar struct {
 // exist.
 func LoadRegistrar(session session, sender gateway.Sender,
 	comms RegisterNodeCommsInterface, rngGen *fastRNG.StreamGenerator,
-	c chan network.NodeGateway, numNodesGetter func() int) (Registrar, error) {
+	c chan network.NodeGateway, numNodesGetter func() int,
+	sink metrics.Sink) (Registrar, error) {
 
 	running := int64(0)
 
@@ -90,6 +95,7 @@ func LoadRegistrar(session session, sender gateway.Sender,
 		resumer:        make(chan interface{}),
 		numberRunning:  &running,
 		numnodesGetter: numNodesGetter,
+		metrics:        sink,
 	}
 
 	obj, err := kv.Get(storeKey, currentKeyVersion)
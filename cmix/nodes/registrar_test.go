@@ -43,7 +43,7 @@ func TestLoadRegistrar_New(t *testing.T) {
 	nodeChan := make(chan commNetwork.NodeGateway, InputChanLen)
 
 	r, err := LoadRegistrar(session, sender, &MockClientComms{},
-		rngGen, nodeChan, func() int { return 100 })
+		rngGen, nodeChan, func() int { return 100 }, nil)
 	if err != nil {
 		t.Fatalf("Failed to create new registrar: %+v", err)
 	}
@@ -76,7 +76,7 @@ func TestLoadRegistrar_Load(t *testing.T) {
 
 	// Load the store and check its attributes
 	r, err := LoadRegistrar(
-		testR.session, testR.sender, testR.comms, testR.rng, testR.c, func() int { return 100 })
+		testR.session, testR.sender, testR.comms, testR.rng, testR.c, func() int { return 100 }, nil)
 	if err != nil {
 		t.Fatalf("Unable to load store: %+v", err)
 	}
@@ -104,7 +104,7 @@ func makeTestRegistrar(mockComms *MockClientComms, t *testing.T) *registrar {
 	nodeChan := make(chan commNetwork.NodeGateway, InputChanLen)
 
 	r, err := LoadRegistrar(
-		session, sender, mockComms, rngGen, nodeChan, func() int { return 100 })
+		session, sender, mockComms, rngGen, nodeChan, func() int { return 100 }, nil)
 	if err != nil {
 		t.Fatalf("Failed to create new registrar: %+v", err)
 	}
@@ -0,0 +1,602 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmix
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix/health"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	ds "gitlab.com/elixxir/comms/network/dataStructures"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage keys.
+const (
+	outboxStoreKey         = "cmixOutbox"
+	outboxStoreVer         = 0
+	outboxEntryStoreKey    = "cmixOutboxEntry/"
+	outboxEntryStoreVer    = 0
+	criticalRawMessagesKey = "RawCriticalMessages"
+)
+
+// criticalRoundTimeout is how long to wait for the round a critical message
+// was sent on to complete before resending it.
+const criticalRoundTimeout = 1 * time.Minute
+
+// Errors returned by the outbox.
+var (
+	// QueuedSendNotFoundErr is returned when a queued send does not exist.
+	QueuedSendNotFoundErr = errors.New("queued send not found")
+
+	// QueuedSendInProgressErr is returned when cancelling a queued send that
+	// is currently being sent.
+	QueuedSendInProgressErr = errors.New("queued send is being sent")
+)
+
+// OutboxCallback is called when a send queued in the outbox finishes. On
+// success, it receives the round the message was sent on. On failure, it
+// receives the error. Critical messages are resent until they succeed and are
+// only reported on success.
+type OutboxCallback func(sendID uint64, round rounds.Round,
+	ephID ephemeral.Id, err error)
+
+// QueuedSend describes a send in the outbox.
+type QueuedSend struct {
+	// ID uniquely identifies the send in the outbox.
+	ID uint64 `json:"id"`
+
+	// Recipient is the cMix ID of the recipient.
+	Recipient *id.ID `json:"recipient"`
+
+	// Priority is the class of the send.
+	Priority Priority `json:"priority"`
+
+	// Queued is when the send was added to the outbox.
+	Queued time.Time `json:"queued"`
+
+	// Attempts is the number of failed attempts to send.
+	Attempts uint `json:"attempts"`
+
+	// DebugTag is the debug tag from the send's CMIXParams.
+	DebugTag string `json:"debugTag"`
+
+	// Sending is true while the send is in progress.
+	Sending bool `json:"sending"`
+}
+
+// QueueSend adds a cMix message to the persistent outbox and returns its ID
+// without waiting for it to be sent. Messages in the outbox are sent in the
+// background, in order of CMIXParams.Priority, while the network is healthy
+// and survive restarts. The result of the send is reported to the
+// OutboxCallback set with SetOutboxCallback.
+//
+// Messages with CMIXParams.Critical set are resent until the round they are
+// sent on completes.
+func (c *client) QueueSend(recipient *id.ID, fingerprint format.Fingerprint,
+	service Service, payload, mac []byte, cmixParams CMIXParams) (
+	uint64, error) {
+	msg, err := c.buildMessage(recipient, fingerprint, service, payload, mac)
+	if err != nil {
+		return 0, err
+	}
+	return c.outbox.queue(msg, recipient, cmixParams)
+}
+
+// GetQueuedSends returns every message in the outbox, ordered by ID. This
+// includes critical messages waiting to be resent.
+func (c *client) GetQueuedSends() []QueuedSend {
+	return c.outbox.get()
+}
+
+// CancelQueuedSend removes the message from the outbox. Returns
+// QueuedSendNotFoundErr if it does not exist and QueuedSendInProgressErr if it
+// is currently being sent.
+func (c *client) CancelQueuedSend(sendID uint64) error {
+	return c.outbox.cancel(sendID)
+}
+
+// SetOutboxCallback sets the callback that is called when a message in the
+// outbox finishes sending. It replaces any previously set callback.
+func (c *client) SetOutboxCallback(cb OutboxCallback) {
+	c.outbox.setCallback(cb)
+}
+
+// roundEventRegistrar is an interface for the round events system to allow
+// for easy testing.
+type roundEventRegistrar interface {
+	AddRoundEventChan(rid id.Round, eventChan chan ds.EventReturn,
+		timeout time.Duration, validStates ...states.Round) *ds.EventCallback
+}
+
+// outboxSender is an anonymous function that takes the data the outbox knows
+// for sending. It should call sendCmixHelper and use scope sharing in an
+// anonymous function to include the structures from client that the outbox is
+// not aware of.
+type outboxSender func(msg format.Message, recipient *id.ID,
+	params CMIXParams) (rounds.Round, ephemeral.Id, error)
+
+// outbox is a persistent queue of cMix messages. Messages are sent in order of
+// priority, limited by the sendLimiter, while the network is healthy. Critical
+// messages stay in the outbox until the round they are sent on completes.
+type outbox struct {
+	entries map[uint64]*outboxEntry
+	nextID  uint64
+
+	kv          versioned.KV
+	hm          health.Monitor
+	roundEvents roundEventRegistrar
+	limiter     *sendLimiter
+	send        outboxSender
+	cb          OutboxCallback
+	trigger     chan struct{}
+	mux         sync.Mutex
+}
+
+// outboxEntry is a message in the outbox.
+type outboxEntry struct {
+	ID        uint64     `json:"id"`
+	Recipient *id.ID     `json:"recipient"`
+	Message   []byte     `json:"message"`
+	Params    CMIXParams `json:"params"`
+	Priority  Priority   `json:"priority"`
+	Queued    time.Time  `json:"queued"`
+	Attempts  uint       `json:"attempts"`
+
+	// sending is true while the message is being sent
+	sending bool
+
+	// held is true when a critical message failed to send and is waiting for
+	// the network to become healthy again
+	held bool
+}
+
+// outboxDisk is the stored list of messages in the outbox.
+type outboxDisk struct {
+	NextID uint64   `json:"nextID"`
+	IDs    []uint64 `json:"ids"`
+}
+
+// newOrLoadOutbox loads the outbox from storage or initialises a new one if
+// none exists. Critical messages saved by older versions of the client are
+// moved into the outbox.
+func newOrLoadOutbox(kv versioned.KV, hm health.Monitor,
+	roundEvents roundEventRegistrar, limiter *sendLimiter,
+	send outboxSender) (*outbox, error) {
+	o := &outbox{
+		entries:     make(map[uint64]*outboxEntry),
+		kv:          kv,
+		hm:          hm,
+		roundEvents: roundEvents,
+		limiter:     limiter,
+		send:        send,
+		trigger:     make(chan struct{}, 1),
+	}
+
+	if err := o.load(); err != nil && kv.Exists(err) {
+		return nil, errors.Wrap(err, "failed to load outbox")
+	}
+
+	if err := o.migrateCritical(); err != nil {
+		return nil, errors.Wrap(err, "failed to move critical messages")
+	}
+
+	limiter.onRelease = o.triggerSend
+	hm.AddHealthCallback(func(healthy bool) {
+		if healthy {
+			o.releaseHeld()
+			o.triggerSend()
+		}
+	})
+
+	return o, nil
+}
+
+// startProcesses starts the thread that sends queued messages.
+func (o *outbox) startProcesses() *stoppable.Single {
+	stop := stoppable.NewSingle("outboxStopper")
+	go o.run(stop)
+	return stop
+}
+
+// run sends queued messages every time a message is added, a send finishes,
+// or the network becomes healthy.
+func (o *outbox) run(stop *stoppable.Single) {
+	o.triggerSend()
+	for {
+		select {
+		case <-stop.Quit():
+			stop.ToStopped()
+			return
+		case <-o.trigger:
+			o.sendQueued(stop)
+		}
+	}
+}
+
+// triggerSend wakes up the send thread without blocking.
+func (o *outbox) triggerSend() {
+	select {
+	case o.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// queue adds the message to the outbox to be sent in the background and
+// returns its ID.
+func (o *outbox) queue(
+	msg format.Message, recipient *id.ID, params CMIXParams) (uint64, error) {
+	o.mux.Lock()
+	e, err := o.add(msg, recipient, params)
+	o.mux.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	jww.INFO.Printf("[OUTBOX] Queued %s message %d to %s (msgDigest: %s)",
+		e.Priority, e.ID, recipient, msg.Digest())
+	o.triggerSend()
+	return e.ID, nil
+}
+
+// addSending adds a critical message that is being sent by the caller to the
+// outbox so that it is resent if the send fails or the client restarts. The
+// caller must report the result of the send to sent.
+func (o *outbox) addSending(
+	msg format.Message, recipient *id.ID, params CMIXParams) (uint64, error) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	e, err := o.add(msg, recipient, params)
+	if err != nil {
+		return 0, err
+	}
+	e.sending = true
+	return e.ID, nil
+}
+
+// sent handles the result of a critical message sent by the caller that was
+// added with addSending. It waits for the round to complete.
+func (o *outbox) sent(
+	sendID uint64, round rounds.Round, ephID ephemeral.Id, err error) {
+	if err == nil {
+		err = o.trackRound(round.ID)
+	}
+	o.done(sendID, round, ephID, err, false)
+}
+
+// add adds a new message to the outbox and saves it. Must be called under
+// lock.
+func (o *outbox) add(msg format.Message, recipient *id.ID,
+	params CMIXParams) (*outboxEntry, error) {
+	e := &outboxEntry{
+		ID:        o.nextID,
+		Recipient: recipient.DeepCopy(),
+		Message:   msg.MarshalImmutable(),
+		Params:    params,
+		Priority:  params.GetPriority(),
+		Queued:    netTime.Now(),
+	}
+	e.Params.Critical = false
+
+	if err := o.saveEntry(e); err != nil {
+		return nil, err
+	}
+	o.entries[e.ID] = e
+	o.nextID++
+	if err := o.save(); err != nil {
+		delete(o.entries, e.ID)
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// get returns every message in the outbox, ordered by ID.
+func (o *outbox) get() []QueuedSend {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	list := make([]QueuedSend, 0, len(o.entries))
+	for _, e := range o.entries {
+		list = append(list, QueuedSend{
+			ID:        e.ID,
+			Recipient: e.Recipient.DeepCopy(),
+			Priority:  e.Priority,
+			Queued:    e.Queued,
+			Attempts:  e.Attempts,
+			DebugTag:  e.Params.DebugTag,
+			Sending:   e.sending,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// cancel removes the message from the outbox. Returns QueuedSendNotFoundErr if
+// it does not exist and QueuedSendInProgressErr if it is being sent.
+func (o *outbox) cancel(sendID uint64) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	e, exists := o.entries[sendID]
+	if !exists {
+		return QueuedSendNotFoundErr
+	} else if e.sending {
+		return QueuedSendInProgressErr
+	}
+
+	return o.remove(sendID)
+}
+
+// setCallback sets the callback called when a queued send finishes.
+func (o *outbox) setCallback(cb OutboxCallback) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.cb = cb
+}
+
+// sendQueued starts sending every message that is not already being sent, in
+// order of priority, as long as the sendLimiter allows it.
+func (o *outbox) sendQueued(stop *stoppable.Single) {
+	if !o.hm.IsHealthy() {
+		return
+	}
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	for _, e := range o.getPending() {
+		if !o.limiter.tryAcquire(e.Priority) {
+			continue
+		}
+
+		msg, err := format.Unmarshal(e.Message)
+		if err != nil {
+			o.limiter.release(e.Priority)
+			jww.ERROR.Printf("[OUTBOX] Removing message %d that could not "+
+				"be unmarshalled: %+v", e.ID, err)
+			if err = o.remove(e.ID); err != nil {
+				jww.ERROR.Printf("[OUTBOX] Failed to remove message %d: %+v",
+					e.ID, err)
+			}
+			continue
+		}
+
+		e.sending = true
+		params := e.Params
+		params.Stop = stop
+		go o.sendEntry(e.ID, msg, e.Recipient, e.Priority, params)
+	}
+}
+
+// getPending returns every message that is not being sent or held, ordered by
+// priority rank and then by the order they were queued in. Must be called
+// under lock.
+func (o *outbox) getPending() []*outboxEntry {
+	pending := make([]*outboxEntry, 0, len(o.entries))
+	for _, e := range o.entries {
+		if !e.sending && !e.held {
+			pending = append(pending, e)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		ri, rj := pending[i].Priority.rank(), pending[j].Priority.rank()
+		if ri != rj {
+			return ri > rj
+		}
+		return pending[i].ID < pending[j].ID
+	})
+	return pending
+}
+
+// sendEntry sends a message from the outbox and handles the result.
+func (o *outbox) sendEntry(sendID uint64, msg format.Message,
+	recipient *id.ID, priority Priority, params CMIXParams) {
+	jww.INFO.Printf("[OUTBOX] Sending %s message %d to %s (msgDigest: %s)",
+		priority, sendID, recipient, msg.Digest())
+
+	round, ephID, err := o.send(msg, recipient, params)
+	o.limiter.release(priority)
+
+	if err == nil && priority == Critical {
+		err = o.trackRound(round.ID)
+	}
+
+	o.done(sendID, round, ephID, err, true)
+}
+
+// trackRound waits for the round a critical message was sent on to complete.
+func (o *outbox) trackRound(rid id.Round) error {
+	sendResults := make(chan ds.EventReturn, 1)
+	o.roundEvents.AddRoundEventChan(rid, sendResults, criticalRoundTimeout,
+		states.COMPLETED, states.FAILED)
+
+	success, _, numTimeOut := TrackResults(sendResults, 1)
+	if success {
+		return nil
+	} else if numTimeOut > 0 {
+		return errors.Errorf("round %d timed out", rid)
+	}
+	return errors.Errorf("round %d failed", rid)
+}
+
+// done removes a message from the outbox once it is sent. Critical messages
+// that fail are held until the network becomes healthy again. Other messages
+// are removed when they fail. If report is true, the callback is called when
+// the message is removed.
+func (o *outbox) done(sendID uint64, round rounds.Round, ephID ephemeral.Id,
+	err error, report bool) {
+	o.mux.Lock()
+	e, exists := o.entries[sendID]
+	if !exists {
+		o.mux.Unlock()
+		return
+	}
+	e.sending = false
+
+	if err != nil && e.Priority == Critical {
+		e.held = true
+		e.Attempts++
+		if err2 := o.saveEntry(e); err2 != nil {
+			jww.ERROR.Printf("[OUTBOX] Failed to save message %d: %+v",
+				sendID, err2)
+		}
+		o.mux.Unlock()
+		jww.ERROR.Printf("[OUTBOX] Critical message %d to %s failed on "+
+			"attempt %d and will be resent: %+v",
+			sendID, e.Recipient, e.Attempts, err)
+		return
+	}
+
+	if err2 := o.remove(sendID); err2 != nil {
+		jww.ERROR.Printf("[OUTBOX] Failed to remove message %d: %+v",
+			sendID, err2)
+	}
+	cb := o.cb
+	o.mux.Unlock()
+
+	if err != nil {
+		jww.ERROR.Printf("[OUTBOX] %s message %d to %s failed: %+v",
+			e.Priority, sendID, e.Recipient, err)
+	} else {
+		jww.INFO.Printf("[OUTBOX] %s message %d to %s sent on round %d",
+			e.Priority, sendID, e.Recipient, round.ID)
+	}
+
+	if report && cb != nil {
+		cb(sendID, round, ephID, err)
+	}
+}
+
+// releaseHeld allows every critical message that failed to be resent.
+func (o *outbox) releaseHeld() {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	for _, e := range o.entries {
+		e.held = false
+	}
+}
+
+// migrateCritical moves the critical messages saved by the critical message
+// buffer used by older versions of the client into the outbox.
+func (o *outbox) migrateCritical() error {
+	cmb, err := LoadCmixMessageBuffer(o.kv, criticalRawMessagesKey)
+	if err != nil {
+		// The buffer does not exist or was already moved
+		return nil
+	}
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	for msg, recipient, params, has := cmb.Next(); has; msg, recipient,
+		params, has = cmb.Next() {
+		params.Critical = true
+		if _, err = o.add(msg, recipient, params); err != nil {
+			cmb.Failed(msg, recipient)
+			return err
+		}
+		cmb.Succeeded(msg, recipient)
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// save stores the list of messages in the outbox. Must be called under lock.
+func (o *outbox) save() error {
+	od := outboxDisk{NextID: o.nextID, IDs: make([]uint64, 0, len(o.entries))}
+	for sendID := range o.entries {
+		od.IDs = append(od.IDs, sendID)
+	}
+	sort.Slice(od.IDs, func(i, j int) bool { return od.IDs[i] < od.IDs[j] })
+
+	data, err := json.Marshal(od)
+	if err != nil {
+		return err
+	}
+
+	return o.kv.Set(outboxStoreKey, &versioned.Object{
+		Version:   outboxStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	})
+}
+
+// load loads the outbox and every message in it from storage.
+func (o *outbox) load() error {
+	obj, err := o.kv.Get(outboxStoreKey, outboxStoreVer)
+	if err != nil {
+		return err
+	}
+
+	var od outboxDisk
+	if err = json.Unmarshal(obj.Data, &od); err != nil {
+		return err
+	}
+
+	o.nextID = od.NextID
+	for _, sendID := range od.IDs {
+		obj, err = o.kv.Get(makeOutboxEntryKey(sendID), outboxEntryStoreVer)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load message %d", sendID)
+		}
+
+		e := &outboxEntry{}
+		if err = json.Unmarshal(obj.Data, e); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal message %d", sendID)
+		}
+		o.entries[sendID] = e
+	}
+
+	return nil
+}
+
+// saveEntry stores the message.
+func (o *outbox) saveEntry(e *outboxEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return o.kv.Set(makeOutboxEntryKey(e.ID), &versioned.Object{
+		Version:   outboxEntryStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	})
+}
+
+// remove deletes the message from the outbox and storage. Must be called under
+// lock.
+func (o *outbox) remove(sendID uint64) error {
+	delete(o.entries, sendID)
+	if err := o.save(); err != nil {
+		return err
+	}
+	return o.kv.Delete(makeOutboxEntryKey(sendID), outboxEntryStoreVer)
+}
+
+// makeOutboxEntryKey generates the storage key for the message in the outbox.
+func makeOutboxEntryKey(sendID uint64) string {
+	return outboxEntryStoreKey + strconv.FormatUint(sendID, 10)
+}
@@ -0,0 +1,461 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmix
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+)
+
+// Tests that outbox.queue adds messages that are returned by outbox.get and
+// that outbox.cancel removes them.
+func Test_outbox_queue_get_cancel(t *testing.T) {
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		&mockRoundEventRegistrar{}, mockCriticalSender, t)
+	prng := rand.New(rand.NewSource(42))
+
+	params := GetDefaultCMIXParams()
+	params.DebugTag = "myTag"
+	expected := make([]QueuedSend, 5)
+	for i := range expected {
+		params.Priority = Priority(i % numPriorities)
+		recipient := id.NewIdFromUInt(uint64(i), id.User, t)
+		sendID, err := o.queue(newTestOutboxMessage(prng), recipient, params)
+		if err != nil {
+			t.Fatalf("Failed to queue message %d: %+v", i, err)
+		} else if sendID != uint64(i) {
+			t.Errorf("Unexpected ID for message %d.\nexpected: %d\nreceived: %d",
+				i, i, sendID)
+		}
+		expected[i] = QueuedSend{ID: sendID, Recipient: recipient,
+			Priority: params.Priority, DebugTag: params.DebugTag}
+	}
+
+	checkQueuedSends(expected, o.get(), t)
+
+	if err := o.cancel(2); err != nil {
+		t.Errorf("Failed to cancel message: %+v", err)
+	}
+	expected = append(expected[:2], expected[3:]...)
+	checkQueuedSends(expected, o.get(), t)
+
+	// Error path: message does not exist
+	if err := o.cancel(2); err != QueuedSendNotFoundErr {
+		t.Errorf("Unexpected error for cancelled message."+
+			"\nexpected: %v\nreceived: %v", QueuedSendNotFoundErr, err)
+	}
+
+	// Error path: message is being sent
+	o.entries[3].sending = true
+	if err := o.cancel(3); err != QueuedSendInProgressErr {
+		t.Errorf("Unexpected error for message being sent."+
+			"\nexpected: %v\nreceived: %v", QueuedSendInProgressErr, err)
+	}
+}
+
+// Tests that newOrLoadOutbox loads every message saved by a previous outbox
+// and continues numbering after the last ID.
+func Test_newOrLoadOutbox_Load(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	o := newTestOutbox(kv, &mockRoundEventRegistrar{}, mockCriticalSender, t)
+	prng := rand.New(rand.NewSource(42))
+
+	params := GetDefaultCMIXParams()
+	params.Critical = true
+	for i := 0; i < 4; i++ {
+		recipient := id.NewIdFromUInt(uint64(i), id.User, t)
+		if _, err := o.queue(newTestOutboxMessage(prng), recipient, params); err != nil {
+			t.Fatalf("Failed to queue message %d: %+v", i, err)
+		}
+	}
+	if err := o.cancel(0); err != nil {
+		t.Fatalf("Failed to cancel message: %+v", err)
+	}
+
+	loaded := newTestOutbox(kv, &mockRoundEventRegistrar{}, mockCriticalSender, t)
+	checkQueuedSends(o.get(), loaded.get(), t)
+	for sendID, e := range o.entries {
+		if !reflect.DeepEqual(e.Message, loaded.entries[sendID].Message) {
+			t.Errorf("Loaded message %d does not match original."+
+				"\nexpected: %x\nreceived: %x",
+				sendID, e.Message, loaded.entries[sendID].Message)
+		}
+	}
+
+	sendID, err := loaded.queue(newTestOutboxMessage(prng),
+		id.NewIdFromUInt(5, id.User, t), params)
+	if err != nil {
+		t.Fatalf("Failed to queue message: %+v", err)
+	} else if sendID != 4 {
+		t.Errorf("Unexpected ID after loading.\nexpected: %d\nreceived: %d",
+			4, sendID)
+	}
+}
+
+// Tests that newOrLoadOutbox moves critical messages saved by the critical
+// message buffer used by older clients into the outbox.
+func Test_newOrLoadOutbox_MigrateCritical(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	cmb, err := NewOrLoadCmixMessageBuffer(kv, criticalRawMessagesKey)
+	if err != nil {
+		t.Fatalf("Failed to create critical message buffer: %+v", err)
+	}
+
+	prng := rand.New(rand.NewSource(42))
+	expected := make(map[id.ID]QueuedSend)
+	for i := 0; i < 3; i++ {
+		recipient := id.NewIdFromUInt(uint64(i), id.User, t)
+		cmb.Add(newTestOutboxMessage(prng), recipient, GetDefaultCMIXParams())
+		expected[*recipient] = QueuedSend{Recipient: recipient,
+			Priority: Critical, DebugTag: DefaultDebugTag}
+	}
+
+	// The buffer does not keep the order messages were added in, so the
+	// messages are compared by recipient
+	check := func(qs []QueuedSend) {
+		received := make(map[id.ID]QueuedSend, len(qs))
+		for i, q := range qs {
+			if q.ID != uint64(i) {
+				t.Errorf("Unexpected ID for message %d."+
+					"\nexpected: %d\nreceived: %d", i, i, q.ID)
+			}
+			q.ID, q.Queued = 0, time.Time{}
+			received[*q.Recipient] = q
+		}
+		if !reflect.DeepEqual(expected, received) {
+			t.Errorf("Unexpected queued sends.\nexpected: %+v\nreceived: %+v",
+				expected, received)
+		}
+	}
+
+	o := newTestOutbox(kv, &mockRoundEventRegistrar{}, mockCriticalSender, t)
+	check(o.get())
+
+	// Loading again must not add the messages twice
+	o = newTestOutbox(kv, &mockRoundEventRegistrar{}, mockCriticalSender, t)
+	check(o.get())
+}
+
+// Tests that outbox.sendQueued starts the highest priority messages first and
+// only as many of each priority as the sendLimiter allows.
+func Test_outbox_sendQueued_Priority(t *testing.T) {
+	sending := make(chan *id.ID, 10)
+	finish := make(chan struct{})
+	send := func(_ format.Message, recipient *id.ID, _ CMIXParams) (
+		rounds.Round, ephemeral.Id, error) {
+		sending <- recipient
+		<-finish
+		return rounds.Round{ID: 1}, ephemeral.Id{}, nil
+	}
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		&mockRoundEventRegistrar{}, send, t)
+	o.limiter = newSendLimiter(Params{
+		CriticalSendLimit: 1, InteractiveSendLimit: 1, BulkSendLimit: 1})
+	o.limiter.onRelease = o.triggerSend
+	prng := rand.New(rand.NewSource(42))
+
+	params := GetDefaultCMIXParams()
+	for i, p := range []Priority{Bulk, Bulk, Interactive, Interactive} {
+		params.Priority = p
+		_, err := o.queue(newTestOutboxMessage(prng),
+			id.NewIdFromUInt(uint64(i), id.User, t), params)
+		if err != nil {
+			t.Fatalf("Failed to queue message %d: %+v", i, err)
+		}
+	}
+
+	stop := stoppable.NewSingle("test")
+	o.sendQueued(stop)
+
+	started := make(map[id.ID]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case recipient := <-sending:
+			started[*recipient] = true
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for send %d to start.", i)
+		}
+	}
+	expected := map[id.ID]bool{
+		*id.NewIdFromUInt(0, id.User, t): true,
+		*id.NewIdFromUInt(2, id.User, t): true,
+	}
+	if !reflect.DeepEqual(expected, started) {
+		t.Errorf("Unexpected sends started.\nexpected: %v\nreceived: %v",
+			expected, started)
+	}
+
+	select {
+	case recipient := <-sending:
+		t.Errorf("Send to %s started over the limit.", recipient)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Finishing the sends triggers the next ones
+	select {
+	case <-o.trigger:
+	default:
+	}
+	close(finish)
+	waitForOutboxSize(o, 2, t)
+	select {
+	case <-o.trigger:
+	default:
+		t.Errorf("Finished sends did not trigger sending.")
+	}
+	o.sendQueued(stop)
+	waitForOutboxSize(o, 0, t)
+}
+
+// Tests that outbox.getPending orders messages of mixed priorities as
+// Critical, then Interactive, then Bulk, and keeps the order they were queued
+// in within each priority.
+func Test_outbox_getPending_MixedPriorities(t *testing.T) {
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		&mockRoundEventRegistrar{}, nil, t)
+	prng := rand.New(rand.NewSource(42))
+
+	params := GetDefaultCMIXParams()
+	priorities := []Priority{
+		Bulk, Interactive, Critical, Bulk, Critical, Interactive, Bulk}
+	for i, p := range priorities {
+		params.Priority = p
+		_, err := o.queue(newTestOutboxMessage(prng),
+			id.NewIdFromUInt(uint64(i), id.User, t), params)
+		if err != nil {
+			t.Fatalf("Failed to queue message %d: %+v", i, err)
+		}
+	}
+
+	expected := []uint64{2, 4, 1, 5, 0, 3, 6}
+	o.mux.Lock()
+	pending := o.getPending()
+	o.mux.Unlock()
+	received := make([]uint64, len(pending))
+	for i, e := range pending {
+		received[i] = e.ID
+	}
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("Unexpected send order.\nexpected: %v\nreceived: %v",
+			expected, received)
+	}
+}
+
+// Tests that a critical message that fails to send is kept in the outbox and
+// is not resent until outbox.releaseHeld is called, and that it is reported to
+// the callback once it succeeds.
+func Test_outbox_Critical(t *testing.T) {
+	mr := &mockRoundEventRegistrar{}
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		mr, mockFailCriticalSender, t)
+	reported := make(chan error, 10)
+	o.setCallback(func(sendID uint64, round rounds.Round, _ ephemeral.Id,
+		err error) {
+		if round.ID != 1 {
+			t.Errorf("Unexpected round.\nexpected: %d\nreceived: %d",
+				1, round.ID)
+		}
+		reported <- err
+	})
+
+	params := GetDefaultCMIXParams()
+	params.Critical = true
+	_, err := o.queue(newTestOutboxMessage(rand.New(rand.NewSource(42))),
+		id.NewIdFromString("recipient", id.User, t), params)
+	if err != nil {
+		t.Fatalf("Failed to queue message: %+v", err)
+	}
+
+	stop := stoppable.NewSingle("test")
+	o.sendQueued(stop)
+	waitForOutboxIdle(o, t)
+
+	if qs := o.get(); len(qs) != 1 || qs[0].Attempts != 1 {
+		t.Fatalf("Failed critical message not held: %+v", qs)
+	}
+
+	// The held message is not resent until the network is healthy again
+	o.send = mockCriticalSender
+	o.sendQueued(stop)
+	if qs := o.get(); qs[0].Sending {
+		t.Errorf("Held message was resent.")
+	}
+
+	// The round times out, so the message is held again
+	mr.statusReturn = true
+	o.releaseHeld()
+	o.sendQueued(stop)
+	waitForOutboxIdle(o, t)
+	if qs := o.get(); len(qs) != 1 || qs[0].Attempts != 2 {
+		t.Fatalf("Timed out critical message not held: %+v", qs)
+	}
+
+	mr.statusReturn = false
+	o.releaseHeld()
+	o.sendQueued(stop)
+	waitForOutboxSize(o, 0, t)
+
+	select {
+	case err = <-reported:
+		if err != nil {
+			t.Errorf("Unexpected error reported: %+v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Timed out waiting for callback.")
+	}
+	if len(reported) != 0 {
+		t.Errorf("Callback called %d extra times.", len(reported))
+	}
+}
+
+// Tests that a non-critical message that fails to send is removed from the
+// outbox and reported to the callback.
+func Test_outbox_Failure(t *testing.T) {
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		&mockRoundEventRegistrar{}, mockFailCriticalSender, t)
+	reported := make(chan error, 1)
+	o.setCallback(func(_ uint64, _ rounds.Round, _ ephemeral.Id, err error) {
+		reported <- err
+	})
+
+	_, err := o.queue(newTestOutboxMessage(rand.New(rand.NewSource(42))),
+		id.NewIdFromString("recipient", id.User, t), GetDefaultCMIXParams())
+	if err != nil {
+		t.Fatalf("Failed to queue message: %+v", err)
+	}
+
+	o.sendQueued(stoppable.NewSingle("test"))
+	waitForOutboxSize(o, 0, t)
+
+	select {
+	case err = <-reported:
+		if err == nil {
+			t.Errorf("No error reported for failed send.")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Timed out waiting for callback.")
+	}
+}
+
+// Tests that a critical message added with outbox.addSending cannot be
+// cancelled, is kept after a failed send, and is removed without calling the
+// callback after a successful send.
+func Test_outbox_addSending_sent(t *testing.T) {
+	o := newTestOutbox(versioned.NewKV(ekv.MakeMemstore()),
+		&mockRoundEventRegistrar{}, mockCriticalSender, t)
+	o.setCallback(func(uint64, rounds.Round, ephemeral.Id, error) {
+		t.Errorf("Callback called for message sent by the caller.")
+	})
+	prng := rand.New(rand.NewSource(42))
+	recipient := id.NewIdFromString("recipient", id.User, t)
+	params := GetDefaultCMIXParams()
+	params.Critical = true
+
+	sendID, err := o.addSending(newTestOutboxMessage(prng), recipient, params)
+	if err != nil {
+		t.Fatalf("Failed to add message: %+v", err)
+	}
+	if err = o.cancel(sendID); err != QueuedSendInProgressErr {
+		t.Errorf("Unexpected error for message being sent."+
+			"\nexpected: %v\nreceived: %v", QueuedSendInProgressErr, err)
+	}
+
+	o.sent(sendID, rounds.Round{}, ephemeral.Id{}, errors.New("send failed"))
+	if qs := o.get(); len(qs) != 1 || qs[0].Sending || qs[0].Attempts != 1 {
+		t.Errorf("Failed critical message not held: %+v", qs)
+	}
+
+	sendID, err = o.addSending(newTestOutboxMessage(prng), recipient, params)
+	if err != nil {
+		t.Fatalf("Failed to add message: %+v", err)
+	}
+	o.sent(sendID, rounds.Round{ID: 1}, ephemeral.Id{}, nil)
+	if qs := o.get(); len(qs) != 1 || qs[0].ID == sendID {
+		t.Errorf("Sent critical message not removed: %+v", qs)
+	}
+}
+
+// newTestOutbox creates a new outbox for testing.
+func newTestOutbox(kv versioned.KV, mr *mockRoundEventRegistrar,
+	send outboxSender, t testing.TB) *outbox {
+	o, err := newOrLoadOutbox(
+		kv, &mockMonitor{}, mr, newSendLimiter(GetDefaultParams()), send)
+	if err != nil {
+		t.Fatalf("Failed to create outbox: %+v", err)
+	}
+	return o
+}
+
+// newTestOutboxMessage creates a cMix message with random contents.
+func newTestOutboxMessage(prng *rand.Rand) format.Message {
+	msg := format.NewMessage(2048)
+	contents := make([]byte, msg.ContentsSize())
+	prng.Read(contents)
+	msg.SetContents(contents)
+	return msg
+}
+
+// checkQueuedSends checks that the received QueuedSend list matches the
+// expected, ignoring the time each was queued.
+func checkQueuedSends(expected, received []QueuedSend, t *testing.T) {
+	if len(expected) != len(received) {
+		t.Errorf("Unexpected number of queued sends."+
+			"\nexpected: %d\nreceived: %d", len(expected), len(received))
+		return
+	}
+
+	for i := range expected {
+		if received[i].Queued.IsZero() {
+			t.Errorf("Queued send %d has no queued time.", i)
+		}
+		received[i].Queued = expected[i].Queued
+		if !reflect.DeepEqual(expected[i], received[i]) {
+			t.Errorf("Unexpected queued send %d."+
+				"\nexpected: %+v\nreceived: %+v", i, expected[i], received[i])
+		}
+	}
+}
+
+// waitForOutboxSize waits for the outbox to contain the given number of
+// messages, none of which are being sent.
+func waitForOutboxSize(o *outbox, size int, t *testing.T) {
+	timeout := time.After(time.Second)
+	for {
+		qs := o.get()
+		sending := false
+		for _, q := range qs {
+			sending = sending || q.Sending
+		}
+		if len(qs) == size && !sending {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("Timed out waiting for outbox to have %d messages: %+v",
+				size, qs)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// waitForOutboxIdle waits for no messages in the outbox to be sending.
+func waitForOutboxIdle(o *outbox, t *testing.T) {
+	waitForOutboxSize(o, len(o.get()), t)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/cmix/pickup"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/stoppable"
@@ -83,6 +84,18 @@ type Params struct {
 	// gateways in this list.
 	WhitelistedGateways []string
 
+	// CriticalSendLimit, InteractiveSendLimit, and BulkSendLimit are the
+	// maximum number of concurrent sends of each Priority. A limit of zero uses
+	// the default.
+	CriticalSendLimit    uint
+	InteractiveSendLimit uint
+	BulkSendLimit        uint
+
+	// Metrics receives metrics from the follower, message pickup, sends, node
+	// registration, clock skew tracking, and health tracking. If it is nil, no
+	// metrics are collected. It is not marshalled.
+	Metrics metrics.Sink
+
 	Rounds     rounds.Params
 	Pickup     pickup.Params
 	Message    message.Params
@@ -107,6 +120,9 @@ type paramsDisk struct {
 	Historical                rounds.Params
 	MaxParallelIdentityTracks uint
 	EnableImmediateSending    bool
+	CriticalSendLimit         uint
+	InteractiveSendLimit      uint
+	BulkSendLimit             uint
 }
 
 // GetDefaultParams returns a Params object containing the
@@ -125,6 +141,9 @@ func GetDefaultParams() Params {
 		MaxParallelIdentityTracks: 5,
 		ClockSkewClamp:            50 * time.Millisecond,
 		EnableImmediateSending:    false,
+		CriticalSendLimit:         defaultCriticalSendLimit,
+		InteractiveSendLimit:      defaultInteractiveSendLimit,
+		BulkSendLimit:             defaultBulkSendLimit,
 	}
 	n.Rounds = rounds.GetDefaultParams()
 	n.Pickup = pickup.GetDefaultParams()
@@ -165,6 +184,9 @@ func (p Params) MarshalJSON() ([]byte, error) {
 		Historical:                p.Historical,
 		MaxParallelIdentityTracks: p.MaxParallelIdentityTracks,
 		EnableImmediateSending:    p.EnableImmediateSending,
+		CriticalSendLimit:         p.CriticalSendLimit,
+		InteractiveSendLimit:      p.InteractiveSendLimit,
+		BulkSendLimit:             p.BulkSendLimit,
 	}
 
 	return json.Marshal(&pDisk)
@@ -194,6 +216,9 @@ func (p *Params) UnmarshalJSON(data []byte) error {
 		Historical:                pDisk.Historical,
 		MaxParallelIdentityTracks: pDisk.MaxParallelIdentityTracks,
 		EnableImmediateSending:    pDisk.EnableImmediateSending,
+		CriticalSendLimit:         pDisk.CriticalSendLimit,
+		InteractiveSendLimit:      pDisk.InteractiveSendLimit,
+		BulkSendLimit:             pDisk.BulkSendLimit,
 	}
 
 	return nil
@@ -234,6 +259,11 @@ type CMIXParams struct {
 	// Probe tells the client that this send can be used to test network performance,
 	// that outgoing latency is not important
 	Probe bool
+
+	// Priority is the class of the send. Each class has its own limit on the
+	// number of concurrent sends. It is ignored for critical messages, which
+	// always have the Critical priority.
+	Priority Priority
 }
 
 // cMixParamsDisk will be the marshal-able and umarshal-able object.
@@ -245,6 +275,7 @@ type cMixParamsDisk struct {
 	DebugTag         string
 	BlacklistedNodes NodeMap
 	Critical         bool
+	Priority         Priority
 }
 
 func GetDefaultCMIXParams() CMIXParams {
@@ -284,6 +315,7 @@ func (p CMIXParams) MarshalJSON() ([]byte, error) {
 		DebugTag:         p.DebugTag,
 		Critical:         p.Critical,
 		BlacklistedNodes: p.BlacklistedNodes,
+		Priority:         p.Priority,
 	}
 
 	return json.Marshal(&pDisk)
@@ -306,11 +338,21 @@ func (p *CMIXParams) UnmarshalJSON(data []byte) error {
 		DebugTag:         pDisk.DebugTag,
 		Critical:         pDisk.Critical,
 		BlacklistedNodes: pDisk.BlacklistedNodes,
+		Priority:         pDisk.Priority,
 	}
 
 	return nil
 }
 
+// GetPriority returns the Priority of the send. Critical messages always have
+// the Critical priority.
+func (p CMIXParams) GetPriority() Priority {
+	if p.Critical {
+		return Critical
+	}
+	return p.Priority
+}
+
 // SetDebugTag appends the debug tag if one already exists,
 // otherwise it just used the new debug tag
 func (p CMIXParams) SetDebugTag(newTag string) CMIXParams {
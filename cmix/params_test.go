@@ -67,6 +67,7 @@ func TestCMIXParams_JSON_Marshal_Unmarshal(t *testing.T) {
 			*id.NewIdFromString("node3", id.Node, t): true,
 		},
 		Critical: true,
+		Priority: Bulk,
 	}
 
 	data, err := json.Marshal(p)
@@ -13,6 +13,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/gateway"
 	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
 	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/cmix/pickup/store"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/stoppable"
@@ -44,13 +45,16 @@ type pickup struct {
 	gatewayMessageRequests chan *pickupRequest
 
 	unchecked *store.UncheckedRoundStore
+
+	// metrics is told the number of messages picked up. It may be nil.
+	metrics metrics.Sink
 }
 
 func NewPickup(params Params, bundles chan<- message.Bundle,
 	sender gateway.Sender, historical rounds.Retriever,
 	comms MessageRetrievalComms,
 	rng *fastRNG.StreamGenerator, instance RoundGetter,
-	session storage.Session) Pickup {
+	session storage.Session, sink metrics.Sink) Pickup {
 	unchecked := store.NewOrLoadUncheckedStore(session.GetKV())
 
 	m := &pickup{
@@ -65,6 +69,7 @@ func NewPickup(params Params, bundles chan<- message.Bundle,
 		session:                session,
 		comms:                  comms,
 		gatewayMessageRequests: make(chan *pickupRequest, params.LookupRoundsBufferLen),
+		metrics:                sink,
 	}
 
 	return m
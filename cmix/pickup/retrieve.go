@@ -216,6 +216,9 @@ func (m *pickup) processBundle(bundle message.Bundle, rid receptionID.EphemeralI
 		}
 		bundle.RoundInfo = ri
 		m.messageBundles <- bundle
+		if m.metrics != nil {
+			m.metrics.MessagesPickedUp(len(bundle.Messages))
+		}
 
 		jww.DEBUG.Printf("Removing round %d from unchecked store", ri.ID)
 		err := m.unchecked.Remove(
@@ -12,10 +12,12 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/gateway"
 	ephemeral2 "gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
 	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/stoppable"
 	"gitlab.com/elixxir/comms/network"
 	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/primitives/format"
 	"gitlab.com/xx_network/comms/connect"
 	"gitlab.com/xx_network/crypto/csprng"
 	"gitlab.com/xx_network/primitives/id"
@@ -426,3 +428,37 @@ func Test_manager_processMessageRetrieval_MultipleGateways(t *testing.T) {
 			expectedPayload, testBundle.Messages[0].GetPayloadA())
 	}
 }
+
+// Tests that pickup.processBundle reports the number of messages picked up to
+// the metrics sink.
+func Test_pickup_processBundle_Metrics(t *testing.T) {
+	testManager := newManager(t)
+	bundleChan := make(chan message.Bundle, 1)
+	testManager.messageBundles = bundleChan
+	sink := &mockMetricsSink{}
+	testManager.metrics = sink
+
+	identity := ephemeral2.EphemeralIdentity{
+		Source: id.NewIdFromString("source", id.User, t)}
+	bundle := message.Bundle{
+		Round:    5,
+		Messages: make([]format.Message, 3),
+	}
+	testManager.processBundle(bundle, identity, rounds.Round{ID: 5})
+	<-bundleChan
+
+	if sink.messagesPickedUp != len(bundle.Messages) {
+		t.Errorf("Incorrect number of messages picked up."+
+			"\nexpected: %d\nreceived: %d",
+			len(bundle.Messages), sink.messagesPickedUp)
+	}
+}
+
+// mockMetricsSink records the number of messages picked up. Other metrics are
+// not supported.
+type mockMetricsSink struct {
+	metrics.Sink
+	messagesPickedUp int
+}
+
+func (m *mockMetricsSink) MessagesPickedUp(n int) { m.messagesPickedUp += n }
@@ -19,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/cmix/gateway"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/cmix/nodes"
 	"gitlab.com/elixxir/client/v4/event"
 	"gitlab.com/elixxir/client/v4/stoppable"
@@ -62,6 +63,11 @@ import (
 func (c *client) Send(recipient *id.ID, fingerprint format.Fingerprint,
 	service Service, payload, mac []byte, cmixParams CMIXParams) (
 	rounds.Round, ephemeral.Id, error) {
+	if cmixParams.Critical {
+		return c.sendCritical(
+			recipient, fingerprint, service, payload, mac, cmixParams)
+	}
+
 	// create an internal assembler function to pass to sendWithAssembler
 	assembler := func(rid id.Round) (format.Fingerprint, Service,
 		[]byte, []byte, error) {
@@ -98,7 +104,8 @@ func (c *client) SendWithAssembler(recipient *id.ID, assembler MessageAssembler,
 }
 
 // sendWithAssembler wraps the passed in MessageAssembler in a messageAssembler
-// for sendCmixHelper, and sets up critical message handling where applicable.
+// for sendCmixHelper. The send waits until the sendLimiter allows a send of its
+// priority.
 func (c *client) sendWithAssembler(recipient *id.ID, assembler MessageAssembler,
 	cmixParams CMIXParams) (
 	rounds.Round, ephemeral.Id, error) {
@@ -115,44 +122,100 @@ func (c *client) sendWithAssembler(recipient *id.ID, assembler MessageAssembler,
 			return format.Message{}, err
 		}
 
-		if len(payload) != c.maxMsgLen {
-			return format.Message{}, errors.Errorf(
-				"bad message length (%d, need %d)",
-				len(payload), c.maxMsgLen)
-		}
-
-		// Build message. Will panic if inputs are not correct.
-		msg := format.NewMessage(c.session.GetCmixGroup().GetP().ByteLen())
-		msg.SetContents(payload)
-		msg.SetKeyFP(fingerprint)
-		sih, err := service.Hash(recipient, msg.GetContents())
+		msg, err := c.buildMessage(recipient, fingerprint, service, payload, mac)
 		if err != nil {
 			return format.Message{}, err
 		}
-		msg.SetSIH(sih)
-		msg.SetMac(mac)
 
 		jww.TRACE.Printf("sendCmix Contents: %v, KeyFP: %v, MAC: %v, SIH: %v",
 			msg.GetContents(), msg.GetKeyFP(), msg.GetMac(),
 			msg.GetSIH())
 
-		if cmixParams.Critical {
-			c.crit.AddProcessing(msg, recipient, cmixParams)
-		}
 		return msg, nil
 	}
 
-	r, ephID, msg, rtnErr := sendCmixHelper(c.Sender, assemblerFunc, recipient, cmixParams,
-		c.instance, c.session.GetCmixGroup(), c.Registrar, c.rng, c.events,
-		c.session.GetTransmissionID(), c.comms, c.attemptTracker)
+	return c.sendLimited(recipient, assemblerFunc, cmixParams)
+}
 
-	if cmixParams.Critical {
-		c.crit.handle(msg, recipient, r.ID, rtnErr)
+// sendCritical sends a critical cMix message. The message is built once and
+// added to the outbox before it is sent so that it is resent if the send fails,
+// even before a round is found, or if the client restarts.
+func (c *client) sendCritical(recipient *id.ID, fingerprint format.Fingerprint,
+	service Service, payload, mac []byte, cmixParams CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	if !c.Monitor.IsHealthy() {
+		return rounds.Round{}, ephemeral.Id{}, errors.New(
+			"Cannot send cmix message when the network is not healthy")
+	}
+
+	msg, err := c.buildMessage(recipient, fingerprint, service, payload, mac)
+	if err != nil {
+		return rounds.Round{}, ephemeral.Id{}, err
 	}
 
+	criticalID, err := c.outbox.addSending(msg, recipient, cmixParams)
+	if err != nil {
+		return rounds.Round{}, ephemeral.Id{}, errors.WithMessage(err,
+			"failed to add critical message to the outbox")
+	}
+
+	assemblerFunc := func(id.Round) (format.Message, error) {
+		return msg.Copy(), nil
+	}
+	r, ephID, rtnErr := c.sendLimited(recipient, assemblerFunc, cmixParams)
+
+	c.outbox.sent(criticalID, r, ephID, rtnErr)
+
 	return r, ephID, rtnErr
 }
 
+// sendLimited sends the message with sendCmixHelper once the sendLimiter
+// allows a send of its priority.
+func (c *client) sendLimited(recipient *id.ID, assemblerFunc messageAssembler,
+	cmixParams CMIXParams) (rounds.Round, ephemeral.Id, error) {
+	// The time spent waiting for the limiter counts toward the timeout. Only
+	// the send is given the reduced timeout so that critical messages are
+	// resent from the outbox with the full timeout.
+	priority := cmixParams.GetPriority()
+	sendParams := cmixParams
+	var err error
+	sendParams.Timeout, err =
+		c.limiter.acquire(priority, cmixParams.Stop, cmixParams.Timeout)
+	if err != nil {
+		return rounds.Round{}, ephemeral.Id{}, err
+	}
+	defer c.limiter.release(priority)
+
+	r, ephID, _, err := sendCmixHelper(c.Sender, assemblerFunc, recipient,
+		sendParams, c.instance, c.session.GetCmixGroup(), c.Registrar, c.rng,
+		c.events, c.session.GetTransmissionID(), c.comms, c.attemptTracker,
+		c.param.Metrics)
+	return r, ephID, err
+}
+
+// buildMessage builds the cMix message to the recipient from the payload.
+func (c *client) buildMessage(recipient *id.ID, fingerprint format.Fingerprint,
+	service Service, payload, mac []byte) (format.Message, error) {
+	if len(payload) != c.maxMsgLen {
+		return format.Message{}, errors.Errorf(
+			"bad message length (%d, need %d)",
+			len(payload), c.maxMsgLen)
+	}
+
+	// Build message. Will panic if inputs are not correct.
+	msg := format.NewMessage(c.session.GetCmixGroup().GetP().ByteLen())
+	msg.SetContents(payload)
+	msg.SetKeyFP(fingerprint)
+	sih, err := service.Hash(recipient, msg.GetContents())
+	if err != nil {
+		return format.Message{}, err
+	}
+	msg.SetSIH(sih)
+	msg.SetMac(mac)
+
+	return msg, nil
+}
+
 // sendCmixHelper is a helper function for client.SendCMIX.
 // NOTE: Payloads sent are not end-to-end encrypted; metadata is NOT protected
 // with this call. See SendE2E for end-to-end encryption and full privacy
@@ -167,7 +230,7 @@ func sendCmixHelper(sender gateway.Sender, assembler messageAssembler,
 	recipient *id.ID, cmixParams CMIXParams, instance *network.Instance,
 	grp *cyclic.Group, nodes nodes.Registrar, rng *fastRNG.StreamGenerator,
 	events event.Reporter, senderId *id.ID, comms SendCmixCommsInterface,
-	attemptTracker attempts.SendAttemptTracker) (
+	attemptTracker attempts.SendAttemptTracker, sink metrics.Sink) (
 	rounds.Round, ephemeral.Id, format.Message, error) {
 
 	if cmixParams.RoundTries == 0 {
@@ -330,6 +393,9 @@ func sendCmixHelper(sender gateway.Sender, assembler messageAssembler,
 			result, err := comms.SendPutMessage(host, wrappedMsg, timeout)
 			jww.TRACE.Printf("[Send-%s] sendFunc %s put message",
 				cmixParams.DebugTag, host)
+			if sink != nil {
+				sink.SendAttempt(host.GetId(), err)
+			}
 
 			if err != nil {
 				err := handlePutMessageError(
@@ -0,0 +1,195 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmix
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/stoppable"
+)
+
+// Priority is the class of a cMix send. Each class has its own limit on the
+// number of concurrent sends so that a large number of bulk sends cannot
+// starve interactive ones. Queued sends of a higher priority are started
+// first: Critical, then Interactive, then Bulk.
+type Priority uint8
+
+const (
+	// Interactive is the priority of messages a user is waiting on, such as
+	// chat messages. It is the default priority.
+	Interactive Priority = iota
+
+	// Bulk is the priority of large volumes of messages that are not time
+	// sensitive, such as file parts.
+	Bulk
+
+	// Critical is the priority of messages that must be received. Sends with
+	// CMIXParams.Critical set always have this priority. Critical messages in
+	// the outbox are resent until the round they are sent on completes.
+	Critical
+)
+
+// numPriorities is the number of valid Priority values.
+const numPriorities = int(Critical) + 1
+
+// Default limits on the number of concurrent sends per priority.
+const (
+	defaultCriticalSendLimit    = 4
+	defaultInteractiveSendLimit = 16
+	defaultBulkSendLimit        = 4
+)
+
+// String returns a human-readable name for the Priority for logging and
+// debugging. This function adheres to the fmt.Stringer interface.
+func (p Priority) String() string {
+	switch p {
+	case Interactive:
+		return "Interactive"
+	case Bulk:
+		return "Bulk"
+	case Critical:
+		return "Critical"
+	default:
+		return "INVALID PRIORITY " + strconv.Itoa(int(p))
+	}
+}
+
+// sendLimiter limits the number of concurrent sends of each priority.
+type sendLimiter struct {
+	slots [numPriorities]chan struct{}
+
+	// onRelease is called every time a slot is released
+	onRelease func()
+}
+
+// newSendLimiter returns a sendLimiter with the limits in the Params. A limit
+// of zero uses the default limit.
+func newSendLimiter(params Params) *sendLimiter {
+	limits := [numPriorities]uint{
+		Interactive: params.InteractiveSendLimit,
+		Bulk:        params.BulkSendLimit,
+		Critical:    params.CriticalSendLimit,
+	}
+	defaults := [numPriorities]uint{
+		Interactive: defaultInteractiveSendLimit,
+		Bulk:        defaultBulkSendLimit,
+		Critical:    defaultCriticalSendLimit,
+	}
+
+	sl := &sendLimiter{onRelease: func() {}}
+	for i := range sl.slots {
+		if limits[i] == 0 {
+			limits[i] = defaults[i]
+		}
+		sl.slots[i] = make(chan struct{}, limits[i])
+	}
+
+	return sl
+}
+
+// acquire blocks until a send of the priority can start. Returns an error if
+// the stoppable is stopped or the timeout is reached first. A timeout of zero
+// waits indefinitely.
+//
+// Returns the part of the timeout left after waiting, so that the time spent
+// waiting counts toward the timeout of the send. A timeout of zero is returned
+// unchanged.
+func (sl *sendLimiter) acquire(p Priority, stop *stoppable.Single,
+	timeout time.Duration) (time.Duration, error) {
+	slots := sl.slots[p.index()]
+
+	// Start without a timer if a slot is free
+	select {
+	case slots <- struct{}{}:
+		return timeout, nil
+	default:
+	}
+
+	start := time.Now()
+	if err := sl.wait(p, slots, stop, timeout); err != nil {
+		return 0, err
+	}
+	if timeout == 0 {
+		return 0, nil
+	}
+
+	remaining := timeout - time.Since(start)
+	if remaining <= 0 {
+		sl.release(p)
+		return 0, errors.Errorf(
+			"timed out after %s waiting to send %s message", timeout, p)
+	}
+	return remaining, nil
+}
+
+// wait blocks until a slot is acquired, the stoppable is stopped, or the
+// timeout is reached.
+func (sl *sendLimiter) wait(p Priority, slots chan struct{},
+	stop *stoppable.Single, timeout time.Duration) error {
+	var quit <-chan struct{}
+	if stop != nil {
+		quit = stop.Quit()
+	}
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-quit:
+		return errors.Errorf("stopped waiting to send %s message", p)
+	case <-timedOut:
+		return errors.Errorf(
+			"timed out after %s waiting to send %s message", timeout, p)
+	}
+}
+
+// tryAcquire returns true if a send of the priority can start now.
+func (sl *sendLimiter) tryAcquire(p Priority) bool {
+	select {
+	case sl.slots[p.index()] <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release marks a send of the priority as finished.
+func (sl *sendLimiter) release(p Priority) {
+	<-sl.slots[p.index()]
+	sl.onRelease()
+}
+
+// rank returns the order in which queued sends of the priority are started.
+// Sends with a higher rank are started first. Invalid priorities are treated
+// as Interactive.
+func (p Priority) rank() int {
+	switch Priority(p.index()) {
+	case Critical:
+		return 2
+	case Bulk:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// index returns the index of the priority in a sendLimiter. Invalid priorities
+// are treated as Interactive.
+func (p Priority) index() int {
+	if int(p) >= numPriorities {
+		return int(Interactive)
+	}
+	return int(p)
+}
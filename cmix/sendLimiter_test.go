@@ -0,0 +1,161 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package cmix
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/stoppable"
+)
+
+// Tests that newSendLimiter uses the limits in the Params and the defaults
+// for limits that are zero.
+func Test_newSendLimiter(t *testing.T) {
+	sl := newSendLimiter(Params{InteractiveSendLimit: 2, BulkSendLimit: 1})
+
+	expected := map[Priority]int{
+		Interactive: 2,
+		Bulk:        1,
+		Critical:    defaultCriticalSendLimit,
+	}
+	for p, limit := range expected {
+		if c := cap(sl.slots[p]); c != limit {
+			t.Errorf("Unexpected limit for %s.\nexpected: %d\nreceived: %d",
+				p, limit, c)
+		}
+	}
+}
+
+// Tests that sendLimiter.tryAcquire only allows as many sends of a priority
+// as its limit, independent of other priorities, and that
+// sendLimiter.release frees a slot and calls onRelease.
+func Test_sendLimiter_tryAcquire_release(t *testing.T) {
+	sl := newSendLimiter(Params{
+		CriticalSendLimit: 1, InteractiveSendLimit: 2, BulkSendLimit: 1})
+	var released int
+	sl.onRelease = func() { released++ }
+
+	for i := 0; i < 2; i++ {
+		if !sl.tryAcquire(Interactive) {
+			t.Errorf("Failed to acquire interactive slot %d.", i)
+		}
+	}
+	if sl.tryAcquire(Interactive) {
+		t.Errorf("Acquired interactive slot over the limit.")
+	}
+	if !sl.tryAcquire(Bulk) {
+		t.Errorf("Interactive sends blocked bulk send.")
+	}
+
+	sl.release(Interactive)
+	if released != 1 {
+		t.Errorf("onRelease not called.")
+	}
+	if !sl.tryAcquire(Interactive) {
+		t.Errorf("Failed to acquire released interactive slot.")
+	}
+}
+
+// Tests that sendLimiter.acquire waits for a slot to be released and returns
+// an error when it times out or is stopped first.
+func Test_sendLimiter_acquire(t *testing.T) {
+	sl := newSendLimiter(Params{BulkSendLimit: 1})
+	stop := stoppable.NewSingle("test")
+
+	if _, err := sl.acquire(Bulk, stop, time.Second); err != nil {
+		t.Fatalf("Failed to acquire free slot: %+v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sl.release(Bulk)
+	}()
+	if _, err := sl.acquire(Bulk, stop, time.Second); err != nil {
+		t.Errorf("Failed to acquire released slot: %+v", err)
+	}
+
+	if _, err := sl.acquire(Bulk, stop, 10*time.Millisecond); err == nil {
+		t.Errorf("Did not time out waiting for slot.")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = stop.Close()
+	}()
+	if _, err := sl.acquire(Bulk, stop, 0); err == nil {
+		t.Errorf("Did not stop waiting for slot.")
+	}
+}
+
+// Tests that sendLimiter.acquire returns the timeout less the time spent
+// waiting for a slot, and returns a timeout of zero unchanged.
+func Test_sendLimiter_acquire_RemainingTimeout(t *testing.T) {
+	sl := newSendLimiter(Params{BulkSendLimit: 1})
+	stop := stoppable.NewSingle("test")
+
+	remaining, err := sl.acquire(Bulk, stop, time.Second)
+	if err != nil {
+		t.Fatalf("Failed to acquire free slot: %+v", err)
+	}
+	if remaining != time.Second {
+		t.Errorf("Timeout changed without waiting."+
+			"\nexpected: %s\nreceived: %s", time.Second, remaining)
+	}
+
+	wait := 50 * time.Millisecond
+	go func() {
+		time.Sleep(wait)
+		sl.release(Bulk)
+	}()
+	remaining, err = sl.acquire(Bulk, stop, time.Second)
+	if err != nil {
+		t.Fatalf("Failed to acquire released slot: %+v", err)
+	}
+	if remaining > time.Second-wait || remaining <= 0 {
+		t.Errorf("Timeout not reduced by the time spent waiting."+
+			"\nexpected: <= %s\nreceived: %s", time.Second-wait, remaining)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sl.release(Bulk)
+	}()
+	remaining, err = sl.acquire(Bulk, stop, 0)
+	if err != nil {
+		t.Fatalf("Failed to acquire released slot: %+v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("Timeout of zero changed.\nexpected: %s\nreceived: %s",
+			time.Duration(0), remaining)
+	}
+}
+
+// Tests that Priority.index treats invalid priorities as Interactive.
+func TestPriority_index(t *testing.T) {
+	for p, expected := range map[Priority]int{
+		Interactive: 0, Bulk: 1, Critical: 2, 3: 0, 255: 0} {
+		if i := p.index(); i != expected {
+			t.Errorf("Unexpected index for %s.\nexpected: %d\nreceived: %d",
+				p, expected, i)
+		}
+	}
+}
+
+// Tests that Priority.rank orders Critical above Interactive above Bulk and
+// treats invalid priorities as Interactive.
+func TestPriority_rank(t *testing.T) {
+	if !(Critical.rank() > Interactive.rank() &&
+		Interactive.rank() > Bulk.rank()) {
+		t.Errorf("Incorrect rank order: Critical=%d Interactive=%d Bulk=%d",
+			Critical.rank(), Interactive.rank(), Bulk.rank())
+	}
+	if Priority(255).rank() != Interactive.rank() {
+		t.Errorf("Invalid priority not ranked as Interactive.")
+	}
+}
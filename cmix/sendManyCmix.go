@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/cmix/gateway"
+	"gitlab.com/elixxir/client/v4/cmix/metrics"
 	"gitlab.com/elixxir/client/v4/cmix/nodes"
 	"gitlab.com/elixxir/client/v4/event"
 	"gitlab.com/elixxir/client/v4/stoppable"
@@ -103,7 +104,8 @@ func (c *client) SendManyWithAssembler(recipients []*id.ID,
 }
 
 // sendManyWithAssembler wraps the passed in ManyMessageAssembler in a
-// manyMessageAssembler for sendManyCmixHelper.
+// manyMessageAssembler for sendManyCmixHelper. The send waits until the
+// sendLimiter allows a send of its priority.
 func (c *client) sendManyWithAssembler(recipients []*id.ID,
 	assembler ManyMessageAssembler, params CMIXParams) (rounds.Round,
 	[]ephemeral.Id, error) {
@@ -114,6 +116,15 @@ func (c *client) sendManyWithAssembler(recipients []*id.ID,
 				" network is not healthy")
 	}
 
+	priority := params.GetPriority()
+	// The time spent waiting for the limiter counts toward the timeout
+	var err error
+	params.Timeout, err = c.limiter.acquire(priority, params.Stop, params.Timeout)
+	if err != nil {
+		return rounds.Round{}, []ephemeral.Id{}, err
+	}
+	defer c.limiter.release(priority)
+
 	assemblerFunc := func(rid id.Round) ([]assembledCmixMessage, error) {
 		messages, err := assembler(rid)
 		if err != nil {
@@ -142,7 +153,8 @@ func (c *client) sendManyWithAssembler(recipients []*id.ID,
 
 	return sendManyCmixHelper(c.Sender, assemblerFunc, recipients, params,
 		c.instance, c.session.GetCmixGroup(), c.Registrar, c.rng, c.events,
-		c.session.GetTransmissionID(), c.comms, c.attemptTracker)
+		c.session.GetTransmissionID(), c.comms, c.attemptTracker,
+		c.param.Metrics)
 }
 
 // assembledCmixMessage is a message structure containing the ready-to-send
@@ -167,7 +179,7 @@ func sendManyCmixHelper(sender gateway.Sender, assembler manyMessageAssembler,
 	recipients []*id.ID, param CMIXParams, instance *network.Instance,
 	grp *cyclic.Group, registrar nodes.Registrar, rng *fastRNG.StreamGenerator,
 	events event.Reporter, senderId *id.ID, comms SendCmixCommsInterface,
-	attemptTracker attempts.SendAttemptTracker) (
+	attemptTracker attempts.SendAttemptTracker, sink metrics.Sink) (
 	rounds.Round, []ephemeral.Id, error) {
 
 	if param.RoundTries == 0 {
@@ -329,6 +341,9 @@ func sendManyCmixHelper(sender gateway.Sender, assembler manyMessageAssembler,
 			wrappedMessage.Target = target.Marshal()
 			result, err := comms.SendPutManyMessages(
 				host, wrappedMessage, timeout)
+			if sink != nil {
+				sink.SendAttempt(host.GetId(), err)
+			}
 			if err != nil {
 				err := handlePutMessageError(firstGateway, registrar,
 					recipientsStr, bestRound, err)
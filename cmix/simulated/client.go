@@ -0,0 +1,717 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package simulated
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/gateway"
+	"gitlab.com/elixxir/client/v4/cmix/identity"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/event"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	pb "gitlab.com/elixxir/comms/mixmessages"
+	"gitlab.com/elixxir/comms/network"
+	ds "gitlab.com/elixxir/comms/network/dataStructures"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/comms/connect"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Error messages.
+const (
+	// Client.Follow
+	alreadyFollowingErr = "client is already following the network"
+
+	// Client.SendToAny and Client.SendToPreferred
+	noGatewaysErr = "the simulated network has no gateways"
+
+	// Client.RegisterAddressSpaceNotification
+	addressSpaceTagExistsErr = "address space notification tag %q already exists"
+)
+
+// Client is a client on a simulated Network. It adheres to the cmix.Client
+// interface.
+//
+// Messages received while the client is not following the network are held,
+// as they would be by gateways, and handled once it starts following.
+type Client struct {
+	net     *Network
+	handler message.Handler
+
+	identities map[id.ID]identity.TrackedID
+
+	following bool
+	pending   []message.Bundle
+
+	healthy         bool
+	wasHealthy      bool
+	healthCallbacks map[uint64]func(bool)
+	nextHealthID    uint64
+
+	addressSpaceChans map[string]chan uint8
+
+	queued     map[uint64]cmix.QueuedSend
+	nextSendID uint64
+	outboxCb   cmix.OutboxCallback
+
+	mux sync.RWMutex
+}
+
+// Client adheres to the cmix.Client interface.
+var _ cmix.Client = (*Client)(nil)
+
+// newClient creates a new Client on the Network that tracks the reception ID.
+func newClient(net *Network, receptionID *id.ID, kv versioned.KV,
+	events event.Reporter) *Client {
+	if events == nil {
+		events = event.NewEventManager()
+	}
+
+	c := &Client{
+		net: net,
+		handler: message.NewHandler(
+			message.GetDefaultParams(), kv, events, receptionID),
+		identities:        make(map[id.ID]identity.TrackedID),
+		healthCallbacks:   make(map[uint64]func(bool)),
+		addressSpaceChans: make(map[string]chan uint8),
+		queued:            make(map[uint64]cmix.QueuedSend),
+	}
+	c.AddIdentity(receptionID, identity.Forever, true, nil)
+
+	return c
+}
+
+// Follow starts handling received messages. The client is healthy while it
+// is following and the network is healthy.
+func (c *Client) Follow(cmix.ClientErrorReport) (stoppable.Stoppable, error) {
+	c.mux.Lock()
+	if c.following {
+		c.mux.Unlock()
+		return nil, errors.New(alreadyFollowingErr)
+	}
+	c.following = true
+	pending := c.pending
+	c.pending = nil
+	c.mux.Unlock()
+
+	multi := stoppable.NewMulti("SimulatedClient")
+	multi.Add(c.handler.StartProcesses())
+
+	stop := stoppable.NewSingle("SimulatedFollower")
+	go func() {
+		<-stop.Quit()
+		c.mux.Lock()
+		c.following = false
+		c.mux.Unlock()
+		c.updateHealth()
+		stop.ToStopped()
+	}()
+	multi.Add(stop)
+
+	for _, b := range pending {
+		c.receive(b)
+	}
+	c.updateHealth()
+
+	return multi, nil
+}
+
+// SetTrackNetworkPeriod does nothing; the simulated network pushes messages to
+// clients instead of being polled.
+func (c *Client) SetTrackNetworkPeriod(time.Duration) {}
+
+////////////////////////////////////////////////////////////////////////////////
+// Sending                                                                    //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetMaxMessageLength returns the size of the payload of a cMix message.
+func (c *Client) GetMaxMessageLength() int {
+	return c.net.maxMsgLen
+}
+
+// Send sends a single cMix message on a new round. It returns once the message
+// is queued on the round; use GetRoundResults to wait for the round to
+// complete. Critical messages are resent by the network if their round fails.
+func (c *Client) Send(recipient *id.ID, fingerprint format.Fingerprint,
+	service cmix.Service, payload, mac []byte, cmixParams cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	return c.SendWithAssembler(recipient,
+		func(id.Round) (format.Fingerprint, cmix.Service, []byte, []byte,
+			error) {
+			return fingerprint, service, payload, mac, nil
+		}, cmixParams)
+}
+
+// SendMany sends multiple cMix messages on the same round.
+func (c *Client) SendMany(messages []cmix.TargetedCmixMessage,
+	params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
+	recipients := make([]*id.ID, len(messages))
+	for i := range messages {
+		recipients[i] = messages[i].Recipient
+	}
+	return c.SendManyWithAssembler(recipients,
+		func(id.Round) ([]cmix.TargetedCmixMessage, error) {
+			return messages, nil
+		}, params)
+}
+
+// SendWithAssembler sends a single cMix message built by the assembler for the
+// round it is sent on.
+func (c *Client) SendWithAssembler(recipient *id.ID,
+	assembler cmix.MessageAssembler, cmixParams cmix.CMIXParams) (
+	rounds.Round, ephemeral.Id, error) {
+	round, ephIDs, err := c.SendManyWithAssembler([]*id.ID{recipient},
+		func(rid id.Round) ([]cmix.TargetedCmixMessage, error) {
+			fingerprint, service, payload, mac, err := assembler(rid)
+			if err != nil {
+				return nil, err
+			}
+			return []cmix.TargetedCmixMessage{{
+				Recipient:   recipient,
+				Payload:     payload,
+				Fingerprint: fingerprint,
+				Service:     service,
+				Mac:         mac,
+			}}, nil
+		}, cmixParams)
+	if err != nil {
+		return rounds.Round{}, ephemeral.Id{}, err
+	}
+	return round, ephIDs[0], nil
+}
+
+// SendManyWithAssembler sends multiple cMix messages built by the assembler
+// for the round they are sent on.
+func (c *Client) SendManyWithAssembler(_ []*id.ID,
+	assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (
+	rounds.Round, []ephemeral.Id, error) {
+	rid := c.net.reserveRound()
+	messages, err := assembler(rid)
+	if err != nil {
+		return rounds.Round{}, nil, err
+	}
+
+	packets := make([]packet, len(messages))
+	for i, m := range messages {
+		msg, err2 := c.buildMessage(
+			m.Recipient, m.Fingerprint, m.Service, m.Payload, m.Mac)
+		if err2 != nil {
+			return rounds.Round{}, nil, err2
+		}
+		packets[i] = packet{
+			msg:       msg,
+			recipient: m.Recipient.DeepCopy(),
+			critical:  params.Critical,
+		}
+	}
+
+	round, err := c.net.startRound(rid, packets)
+	if err != nil {
+		return rounds.Round{}, nil, err
+	}
+
+	ephIDs := make([]ephemeral.Id, len(packets))
+	for i := range packets {
+		ephIDs[i] = packets[i].ephID
+	}
+
+	return round, ephIDs, nil
+}
+
+// buildMessage builds a cMix message the same way the real client does.
+func (c *Client) buildMessage(recipient *id.ID, fingerprint format.Fingerprint,
+	service cmix.Service, payload, mac []byte) (format.Message, error) {
+	if len(payload) != c.net.maxMsgLen {
+		return format.Message{}, errors.Errorf(
+			"bad message length (%d, need %d)", len(payload), c.net.maxMsgLen)
+	}
+
+	msg := format.NewMessage(c.net.instance.GetCmixGroup().GetP().ByteLen())
+	msg.SetContents(payload)
+	msg.SetKeyFP(fingerprint)
+	sih, err := service.Hash(recipient, msg.GetContents())
+	if err != nil {
+		return format.Message{}, err
+	}
+	msg.SetSIH(sih)
+	msg.SetMac(mac)
+
+	return msg, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Outbox                                                                     //
+////////////////////////////////////////////////////////////////////////////////
+
+// QueueSend sends the message in the background and reports the result to
+// the OutboxCallback. Unlike the real outbox, queued sends are not saved and
+// are started immediately, so they cannot be cancelled.
+func (c *Client) QueueSend(recipient *id.ID, fingerprint format.Fingerprint,
+	service cmix.Service, payload, mac []byte, cmixParams cmix.CMIXParams) (
+	uint64, error) {
+	if _, err := c.buildMessage(
+		recipient, fingerprint, service, payload, mac); err != nil {
+		return 0, err
+	}
+
+	c.mux.Lock()
+	sendID := c.nextSendID
+	c.nextSendID++
+	c.queued[sendID] = cmix.QueuedSend{
+		ID:        sendID,
+		Recipient: recipient.DeepCopy(),
+		Priority:  cmixParams.GetPriority(),
+		Queued:    netTime.Now(),
+		DebugTag:  cmixParams.DebugTag,
+		Sending:   true,
+	}
+	c.mux.Unlock()
+
+	go func() {
+		round, ephID, err := c.Send(
+			recipient, fingerprint, service, payload, mac, cmixParams)
+
+		c.mux.Lock()
+		delete(c.queued, sendID)
+		cb := c.outboxCb
+		c.mux.Unlock()
+
+		if cb != nil {
+			cb(sendID, round, ephID, err)
+		}
+	}()
+
+	return sendID, nil
+}
+
+// GetQueuedSends returns the queued sends that have not finished.
+func (c *Client) GetQueuedSends() []cmix.QueuedSend {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	list := make([]cmix.QueuedSend, 0, len(c.queued))
+	for _, qs := range c.queued {
+		list = append(list, qs)
+	}
+	return list
+}
+
+// CancelQueuedSend always fails because queued sends are started immediately.
+func (c *Client) CancelQueuedSend(sendID uint64) error {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	if _, exists := c.queued[sendID]; exists {
+		return cmix.QueuedSendInProgressErr
+	}
+	return cmix.QueuedSendNotFoundErr
+}
+
+// SetOutboxCallback sets the callback called when a queued send finishes.
+func (c *Client) SetOutboxCallback(cb cmix.OutboxCallback) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.outboxCb = cb
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Message Reception                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// AddIdentity adds an identity to receive messages for. Identities do not
+// expire on the simulated network.
+func (c *Client) AddIdentity(id *id.ID, validUntil time.Time, persistent bool,
+	fallthroughProcessor message.Processor) {
+	c.mux.Lock()
+	c.identities[*id] = identity.TrackedID{
+		Source:     id.DeepCopy(),
+		ValidUntil: validUntil,
+		Persistent: persistent,
+		Creation:   netTime.Now(),
+	}
+	c.mux.Unlock()
+
+	if fallthroughProcessor != nil {
+		c.handler.AddFallthrough(id, fallthroughProcessor)
+	}
+}
+
+// AddIdentityWithHistory adds an identity to receive messages for. The
+// simulated network does not keep old messages, so the history is ignored.
+func (c *Client) AddIdentityWithHistory(id *id.ID, validUntil, _ time.Time,
+	persistent bool, fallthroughProcessor message.Processor) {
+	c.AddIdentity(id, validUntil, persistent, fallthroughProcessor)
+}
+
+// RemoveIdentity stops receiving messages for the identity.
+func (c *Client) RemoveIdentity(id *id.ID) {
+	c.mux.Lock()
+	delete(c.identities, *id)
+	c.mux.Unlock()
+	c.handler.RemoveFallthrough(id)
+}
+
+// GetIdentity returns the tracked identity.
+func (c *Client) GetIdentity(get *id.ID) (identity.TrackedID, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	tracked, exists := c.identities[*get]
+	if !exists {
+		return identity.TrackedID{}, errors.Errorf(
+			"identity %s is not tracked", get)
+	}
+	return tracked, nil
+}
+
+// AddFingerprint adds a fingerprint to process messages with.
+func (c *Client) AddFingerprint(identity *id.ID,
+	fingerprint format.Fingerprint, mp message.Processor) error {
+	return c.handler.AddFingerprint(identity, fingerprint, mp)
+}
+
+// DeleteFingerprint deletes a fingerprint.
+func (c *Client) DeleteFingerprint(
+	identity *id.ID, fingerprint format.Fingerprint) {
+	c.handler.DeleteFingerprint(identity, fingerprint)
+}
+
+// DeleteClientFingerprints deletes all fingerprints of the identity.
+func (c *Client) DeleteClientFingerprints(identity *id.ID) {
+	c.handler.DeleteClientFingerprints(identity)
+}
+
+// AddService adds a service to process messages with.
+func (c *Client) AddService(clientID *id.ID, newService message.Service,
+	response message.Processor) {
+	c.handler.AddService(clientID, newService, response)
+}
+
+// UpsertCompressedService adds or replaces a compressed service to process
+// messages with.
+func (c *Client) UpsertCompressedService(clientID *id.ID,
+	newService message.CompressedService, response message.Processor) {
+	c.handler.UpsertCompressedService(clientID, newService, response)
+}
+
+// DeleteService deletes a service.
+func (c *Client) DeleteService(clientID *id.ID, toDelete message.Service,
+	processor message.Processor) {
+	c.handler.DeleteService(clientID, toDelete, processor)
+}
+
+// DeleteClientService deletes all services of the identity.
+func (c *Client) DeleteClientService(clientID *id.ID) {
+	c.handler.DeleteClientService(clientID)
+}
+
+// DeleteCompressedService deletes a compressed service.
+func (c *Client) DeleteCompressedService(clientID *id.ID,
+	toDelete message.CompressedService, processor message.Processor) {
+	c.handler.DeleteCompressedService(clientID, toDelete, processor)
+}
+
+// TrackServices adds a tracker to be called when services change.
+func (c *Client) TrackServices(tracker message.ServicesTracker) {
+	c.handler.TrackServices(tracker)
+}
+
+// GetServices returns the current list of services.
+func (c *Client) GetServices() (
+	message.ServiceList, message.CompressedServiceList) {
+	return c.handler.GetServices()
+}
+
+// CheckInProgressMessages retries processing messages that could not be
+// processed when received.
+func (c *Client) CheckInProgressMessages() {
+	c.handler.CheckInProgressMessages()
+}
+
+// getMatchingIdentities returns the source ID of every tracked identity with
+// the given ephemeral ID at the given time.
+func (c *Client) getMatchingIdentities(
+	ephID ephemeral.Id, ts time.Time) []*id.ID {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var sources []*id.ID
+	for _, tracked := range c.identities {
+		trackedEphID, err := getEphemeralID(tracked.Source, c.net.params, ts)
+		if err == nil && trackedEphID == ephID {
+			sources = append(sources, tracked.Source)
+		}
+	}
+	return sources
+}
+
+// receive hands the bundle to the message handler or holds it until the
+// client is following the network.
+func (c *Client) receive(b message.Bundle) {
+	c.mux.Lock()
+	if !c.following {
+		c.pending = append(c.pending, b)
+		c.mux.Unlock()
+		return
+	}
+	c.mux.Unlock()
+
+	go func() { c.handler.GetMessageReceptionChannel() <- b }()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Health Monitor                                                             //
+////////////////////////////////////////////////////////////////////////////////
+
+// IsHealthy returns true if the client is following a healthy network.
+func (c *Client) IsHealthy() bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.healthy
+}
+
+// WasHealthy returns true if the client has ever been healthy.
+func (c *Client) WasHealthy() bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.wasHealthy
+}
+
+// AddHealthCallback adds a function that is called with the health every
+// time it changes. It is also called with the current health. Returns an ID
+// to remove it with.
+func (c *Client) AddHealthCallback(f func(bool)) uint64 {
+	c.mux.Lock()
+	healthID := c.nextHealthID
+	c.nextHealthID++
+	c.healthCallbacks[healthID] = f
+	healthy := c.healthy
+	c.mux.Unlock()
+
+	go f(healthy)
+	return healthID
+}
+
+// RemoveHealthCallback removes the health callback with the ID.
+func (c *Client) RemoveHealthCallback(healthID uint64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.healthCallbacks, healthID)
+}
+
+// updateHealth updates the health of the client and calls the health
+// callbacks if it changed.
+func (c *Client) updateHealth() {
+	networkHealthy := c.net.IsHealthy()
+
+	c.mux.Lock()
+	healthy := c.following && networkHealthy
+	if healthy == c.healthy {
+		c.mux.Unlock()
+		return
+	}
+	c.healthy = healthy
+	c.wasHealthy = c.wasHealthy || healthy
+	callbacks := make([]func(bool), 0, len(c.healthCallbacks))
+	for _, f := range c.healthCallbacks {
+		callbacks = append(callbacks, f)
+	}
+	c.mux.Unlock()
+
+	for _, f := range callbacks {
+		f(healthy)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Nodes                                                                      //
+////////////////////////////////////////////////////////////////////////////////
+
+// HasNode returns true if the node is on the simulated network. Clients are
+// always registered with every node.
+func (c *Client) HasNode(nid *id.ID) bool {
+	for _, node := range c.net.def.Nodes {
+		if nid.Cmp(id.NewIdFromBytes(node.ID, nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NumRegisteredNodes returns the number of nodes on the simulated network.
+func (c *Client) NumRegisteredNodes() int {
+	return len(c.net.def.Nodes)
+}
+
+// TriggerNodeRegistration does nothing; clients are always registered with
+// every node.
+func (c *Client) TriggerNodeRegistration(*id.ID) {}
+
+// PauseNodeRegistrations does nothing.
+func (c *Client) PauseNodeRegistrations(time.Duration) error { return nil }
+
+// ChangeNumberOfNodeRegistrations does nothing.
+func (c *Client) ChangeNumberOfNodeRegistrations(int, time.Duration) error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Rounds                                                                     //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetRoundResults calls the callback once every round in the list has
+// completed or failed, or the timeout is reached.
+func (c *Client) GetRoundResults(timeout time.Duration,
+	roundCallback cmix.RoundEventCallback, roundList ...id.Round) {
+	go func() {
+		roundEvents := c.net.instance.GetRoundEvents()
+		results := make(map[id.Round]cmix.RoundResult, len(roundList))
+		resolved := make(map[id.Round]bool, len(roundList))
+		eventChan := make(chan ds.EventReturn, len(roundList))
+
+		for _, rid := range roundList {
+			if _, exists := results[rid]; exists {
+				continue
+			}
+			results[rid] = cmix.RoundResult{Status: cmix.TimeOut}
+
+			// Register before checking the round so that a round finishing in
+			// between is not missed
+			roundEvents.AddRoundEventChan(
+				rid, eventChan, timeout, states.COMPLETED, states.FAILED)
+			if ri, err := c.net.instance.GetRound(rid); err == nil {
+				if result, done := getRoundResult(ri); done {
+					results[rid] = result
+					resolved[rid] = true
+				}
+			}
+		}
+
+		for len(resolved) < len(results) {
+			er := <-eventChan
+			rid := id.Round(er.RoundInfo.ID)
+			if resolved[rid] {
+				continue
+			}
+			resolved[rid] = true
+			if !er.TimedOut {
+				results[rid], _ = getRoundResult(er.RoundInfo)
+			}
+		}
+
+		allRoundsSucceeded, timedOut := true, false
+		for _, result := range results {
+			allRoundsSucceeded =
+				allRoundsSucceeded && result.Status == cmix.Succeeded
+			timedOut = timedOut || result.Status == cmix.TimeOut
+		}
+
+		roundCallback(allRoundsSucceeded, timedOut, results)
+	}()
+}
+
+// LookupHistoricalRound looks up the round and calls the callback with it.
+func (c *Client) LookupHistoricalRound(
+	rid id.Round, callback rounds.RoundResultCallback) error {
+	ri, err := c.net.instance.GetRound(rid)
+	if err != nil {
+		return err
+	}
+	go callback(rounds.MakeRound(ri), true)
+	return nil
+}
+
+// getRoundResult returns the result of the round and true if the round has
+// completed or failed.
+func getRoundResult(ri *pb.RoundInfo) (cmix.RoundResult, bool) {
+	switch states.Round(ri.State) {
+	case states.COMPLETED:
+		return cmix.RoundResult{
+			Status: cmix.Succeeded, Round: rounds.MakeRound(ri)}, true
+	case states.FAILED:
+		return cmix.RoundResult{
+			Status: cmix.Failed, Round: rounds.MakeRound(ri)}, true
+	default:
+		return cmix.RoundResult{Status: cmix.TimeOut}, false
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Sender                                                                     //
+////////////////////////////////////////////////////////////////////////////////
+
+// SendToAny always returns an error because there are no gateways.
+func (c *Client) SendToAny(func(host *connect.Host) (interface{}, error),
+	*stoppable.Single) (interface{}, error) {
+	return nil, errors.New(noGatewaysErr)
+}
+
+// SendToPreferred always returns an error because there are no gateways.
+func (c *Client) SendToPreferred([]*id.ID, gateway.SendToPreferredFunc,
+	*stoppable.Single, time.Duration) (interface{}, error) {
+	return nil, errors.New(noGatewaysErr)
+}
+
+// GetHostParams returns the default host parameters.
+func (c *Client) GetHostParams() connect.HostParams {
+	return connect.GetDefaultHostParams()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Address Space                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetAddressSpace returns the address space size of the simulated network.
+func (c *Client) GetAddressSpace() uint8 {
+	return c.net.params.AddressSpaceSize
+}
+
+// RegisterAddressSpaceNotification returns a channel that receives the
+// address space size. The size never changes, so it is only sent once.
+func (c *Client) RegisterAddressSpaceNotification(tag string) (
+	chan uint8, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, exists := c.addressSpaceChans[tag]; exists {
+		return nil, errors.Errorf(addressSpaceTagExistsErr, tag)
+	}
+
+	sizeChan := make(chan uint8, 1)
+	sizeChan <- c.net.params.AddressSpaceSize
+	c.addressSpaceChans[tag] = sizeChan
+	return sizeChan, nil
+}
+
+// UnregisterAddressSpaceNotification removes the notification channel.
+func (c *Client) UnregisterAddressSpaceNotification(tag string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.addressSpaceChans, tag)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Accessors                                                                  //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetInstance returns the network instance shared by every client on the
+// simulated network. It contains the NDF and the fake rounds.
+func (c *Client) GetInstance() *network.Instance {
+	return c.net.instance
+}
+
+// GetVerboseRounds returns a message that verbose round tracking is not
+// supported.
+func (c *Client) GetVerboseRounds() string {
+	return "Verbose Round tracking not enabled"
+}
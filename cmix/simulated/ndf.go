@@ -0,0 +1,125 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package simulated
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/xx_network/crypto/signature/ec"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/ndf"
+)
+
+// Groups used by the simulated network.
+const (
+	e2ePrime = "E2EE983D031DC1DB6F1A7A67DF0E9A8E5561DB8E8D49413394C049B" +
+		"7A8ACCEDC298708F121951D9CF920EC5D146727AA4AE535B0922C688B55B3DD2AE" +
+		"DF6C01C94764DAB937935AA83BE36E67760713AB44A6337C20E7861575E745D31F" +
+		"8B9E9AD8412118C62A3E2E29DF46B0864D0C951C394A5CBBDC6ADC718DD2A3E041" +
+		"023DBB5AB23EBB4742DE9C1687B5B34FA48C3521632C4A530E8FFB1BC51DADDF45" +
+		"3B0B2717C2BC6669ED76B4BDD5C9FF558E88F26E5785302BEDBCA23EAC5ACE9209" +
+		"6EE8A60642FB61E8F3D24990B8CB12EE448EEF78E184C7242DD161C7738F32BF29" +
+		"A841698978825B4111B4BC3E1E198455095958333D776D8B2BEEED3A1A1A221A6E" +
+		"37E664A64B83981C46FFDDC1A45E3D5211AAF8BFBC072768C4F50D7D7803D2D4F2" +
+		"78DE8014A47323631D7E064DE81C0C6BFA43EF0E6998860F1390B5D3FEACAF1696" +
+		"015CB79C3F9C2D93D961120CD0E5F12CBB687EAB045241F96789C38E89D796138E" +
+		"6319BE62E35D87B1048CA28BE389B575E994DCA755471584A09EC723742DC35873" +
+		"847AEF49F66E43873"
+	e2eGenerator = "2"
+
+	cmixPrime = "9DB6FB5951B66BB6FE1E140F1D2CE5502374161FD6538DF1648218642F0B5C48" +
+		"C8F7A41AADFA187324B87674FA1822B00F1ECF8136943D7C55757264E5A1A44F" +
+		"FE012E9936E00C1D3E9310B01C7D179805D3058B2A9F4BB6F9716BFE6117C6B5" +
+		"B3CC4D9BE341104AD4A80AD6C94E005F4B993E14F091EB51743BF33050C38DE2" +
+		"35567E1B34C3D6A5C0CEAA1A0F368213C3D19843D0B4B09DCB9FC72D39C8DE41" +
+		"F1BF14D4BB4563CA28371621CAD3324B6A2D392145BEBFAC748805236F5CA2FE" +
+		"92B871CD8F9C36D3292B5509CA8CAA77A2ADFC7BFD77DDA6F71125A7456FEA15" +
+		"3E433256A2261C6A06ED3693797E7995FAD5AABBCFBE3EDA2741E375404AE25B"
+	cmixGenerator = "5C7FF6B06F8F143FE8288433493E4769C4D988ACE5BE25A0E24809670716C613" +
+		"D7B0CEE6932F8FAA7C44D2CB24523DA53FBE4F6EC3595892D1AA58C4328A06C4" +
+		"6A15662E7EAA703A1DECF8BBB2D05DBE2EB956C142A338661D10461C0D135472" +
+		"085057F3494309FFA73C611F78B32ADBB5740C361C9F35BE90997DB2014E2EF5" +
+		"AA61782F52ABEB8BD6432C4DD097BC5423B285DAFB60DC364E8161F4A2A35ACA" +
+		"3A10B1C4D203CC76A470A33AFDCBDD92959859ABD8B56E1725252D78EAC66E71" +
+		"BA9AE3F1DD2487199874393CD4D832186800654760E1E34C09E4D155179F9EC0" +
+		"DC4473F996BDCE6EED1CABED8B6F116F7AD9CF505DF0F998E34AB27514B0FFE7"
+)
+
+// The permissioning certificate is only used to create the permissioning host
+// that the network instance requires. It is never used to connect.
+const (
+	certKeySize  = 2048
+	certName     = "simulated"
+	certValidFor = 10 * 365 * 24 * time.Hour
+)
+
+// newNDF generates the NDF for a simulated network with the given number of
+// nodes. Round updates are verified with the elliptic public key. All
+// addresses are left blank so that clients never try to contact a server.
+// Returns the NDF and the marshalled node IDs to use as a round topology.
+func newNDF(pubKey *ec.PublicKey, cert []byte, numNodes int) (
+	*ndf.NetworkDefinition, [][]byte) {
+	def := &ndf.NetworkDefinition{
+		Registration: ndf.Registration{
+			TlsCertificate: string(cert),
+			EllipticPubKey: pubKey.MarshalText(),
+		},
+		Nodes:    make([]ndf.Node, numNodes),
+		Gateways: make([]ndf.Gateway, numNodes),
+		E2E: ndf.Group{
+			Prime:     e2ePrime,
+			Generator: e2eGenerator,
+		},
+		CMIX: ndf.Group{
+			Prime:     cmixPrime,
+			Generator: cmixGenerator,
+		},
+	}
+
+	topology := make([][]byte, numNodes)
+	for i := range def.Nodes {
+		nodeID := makeNodeID(i, id.Node)
+		def.Nodes[i] = ndf.Node{ID: nodeID.Bytes(), Status: ndf.Active}
+		def.Gateways[i] = ndf.Gateway{ID: makeNodeID(i, id.Gateway).Bytes()}
+		topology[i] = nodeID.Bytes()
+	}
+
+	return def, topology
+}
+
+// newPermissioningCert generates a PEM-encoded self-signed TLS certificate for
+// the permissioning host.
+func newPermissioningCert() ([]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, certKeySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate certificate key")
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: certName},
+		DNSNames:     []string{certName},
+		NotBefore:    now,
+		NotAfter:     now.Add(certValidFor),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
@@ -0,0 +1,384 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package simulated provides an in-process simulated cMix network for
+// integration testing. Every Client on a Network implements cmix.Client, so
+// xxdk clients and the modules built on them (e2e, channels, dm, file
+// transfer, etc.) can exchange real encrypted messages in a single process
+// without any gateways or nodes.
+//
+// Messages sent on the Network are put on fake rounds. When a round completes,
+// each message is routed by the ephemeral ID of its recipient to every client
+// tracking a matching identity and is then dispatched by fingerprint and
+// service the same way the real client does. The latency, message loss, and
+// round failure rate of the Network can be configured with Params.
+package simulated
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/event"
+	"gitlab.com/elixxir/comms/client"
+	pb "gitlab.com/elixxir/comms/mixmessages"
+	"gitlab.com/elixxir/comms/network"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/comms/connect"
+	"gitlab.com/xx_network/comms/signature"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/crypto/signature/ec"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/ndf"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Network is a simulated cMix network. Create clients on it with NewClient.
+type Network struct {
+	params Params
+	def    *ndf.NetworkDefinition
+	nodes  [][]byte
+
+	// key signs round updates in place of the permissioning server
+	key      *ec.PrivateKey
+	instance *network.Instance
+
+	maxMsgLen int
+
+	clients map[*Client]struct{}
+	healthy bool
+
+	rng          *rand.Rand
+	nextRoundID  id.Round
+	nextUpdateID uint64
+
+	// roundMux serialises round updates so that they are added to the
+	// instance in order of update ID
+	roundMux sync.Mutex
+	mux      sync.RWMutex
+}
+
+// packet is a message on a round.
+type packet struct {
+	msg       format.Message
+	recipient *id.ID
+	ephID     ephemeral.Id
+	critical  bool
+}
+
+// NewNetwork creates a new healthy simulated Network.
+func NewNetwork(params Params) (*Network, error) {
+	if params.NumNodes < 1 {
+		return nil, errors.Errorf(
+			"simulated network needs at least one node, got %d",
+			params.NumNodes)
+	}
+
+	key, err := ec.NewKeyPair(csprng.NewSystemRNG())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate round signing key")
+	}
+
+	cert, err := newPermissioningCert()
+	if err != nil {
+		return nil, err
+	}
+
+	def, nodes := newNDF(key.GetPublic(), cert, params.NumNodes)
+
+	comms, err := client.NewClientComms(&id.DummyUser, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = comms.AddHost(
+		&id.Permissioning, "", cert, connect.GetDefaultHostParams())
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := network.NewInstance(
+		comms.ProtoComms, def, def, nil, network.None, true)
+	if err != nil {
+		return nil, err
+	}
+
+	primeLen := instance.GetCmixGroup().GetP().ByteLen()
+
+	return &Network{
+		params:      params,
+		def:         def,
+		nodes:       nodes,
+		key:         key,
+		instance:    instance,
+		maxMsgLen:   format.NewMessage(primeLen).ContentsSize(),
+		clients:     make(map[*Client]struct{}),
+		healthy:     true,
+		rng:         rand.New(rand.NewSource(params.Seed)),
+		nextRoundID: 1,
+	}, nil
+}
+
+// NewClient creates a new Client on the Network. The reception ID is tracked
+// as an identity, as the real client does with the reception ID in its
+// session. The KV stores messages that could not be processed when received.
+func (n *Network) NewClient(receptionID *id.ID, kv versioned.KV,
+	events event.Reporter) *Client {
+	c := newClient(n, receptionID, kv, events)
+
+	n.mux.Lock()
+	n.clients[c] = struct{}{}
+	n.mux.Unlock()
+
+	return c
+}
+
+// RemoveClient removes the Client from the Network. It will no longer receive
+// messages.
+func (n *Network) RemoveClient(c *Client) {
+	n.mux.Lock()
+	delete(n.clients, c)
+	n.mux.Unlock()
+}
+
+// NDF returns the NDF of the Network. Use it to create storage for xxdk
+// clients on the Network.
+func (n *Network) NDF() *ndf.NetworkDefinition {
+	return n.def.DeepCopy()
+}
+
+// SetHealthy simulates the network becoming available or unavailable. Sends
+// fail while the network is unhealthy. Health callbacks of following clients
+// are called on every change.
+func (n *Network) SetHealthy(healthy bool) {
+	n.mux.Lock()
+	changed := n.healthy != healthy
+	n.healthy = healthy
+	clients := n.getClients()
+	n.mux.Unlock()
+
+	if changed {
+		for _, c := range clients {
+			c.updateHealth()
+		}
+	}
+}
+
+// IsHealthy returns true if the Network is healthy.
+func (n *Network) IsHealthy() bool {
+	n.mux.RLock()
+	defer n.mux.RUnlock()
+	return n.healthy
+}
+
+// getClients returns a list of all clients. Must be called under lock.
+func (n *Network) getClients() []*Client {
+	clients := make([]*Client, 0, len(n.clients))
+	for c := range n.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// reserveRound returns the ID of a new round for messages to be assembled
+// for.
+func (n *Network) reserveRound() id.Round {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	rid := n.nextRoundID
+	n.nextRoundID++
+	return rid
+}
+
+// startRound queues the packets on the round and returns the round. The
+// ephemeral ID of each packet is set for the time the round is queued. The
+// round completes, or fails, after the latency.
+func (n *Network) startRound(rid id.Round, packets []packet) (
+	rounds.Round, error) {
+	if !n.IsHealthy() {
+		return rounds.Round{}, errors.New("simulated network is not healthy")
+	}
+
+	now := netTime.Now()
+	for i := range packets {
+		ephID, err := getEphemeralID(packets[i].recipient, n.params, now)
+		if err != nil {
+			return rounds.Round{}, errors.Wrapf(err,
+				"failed to get ephemeral ID for %s", packets[i].recipient)
+		}
+		packets[i].ephID = ephID
+	}
+
+	timestamps := make([]uint64, states.NUM_STATES)
+	timestamps[states.QUEUED] = uint64(now.UnixNano())
+	ri, err := n.updateRound(rid, states.QUEUED, timestamps, len(packets))
+	if err != nil {
+		return rounds.Round{}, err
+	}
+
+	n.mux.Lock()
+	latency := n.params.Latency
+	if n.params.LatencyJitter > 0 {
+		latency += time.Duration(n.rng.Int63n(int64(n.params.LatencyJitter)))
+	}
+	n.mux.Unlock()
+
+	time.AfterFunc(latency, func() { n.completeRound(rid, timestamps, packets) })
+
+	return rounds.MakeRound(ri), nil
+}
+
+// completeRound completes or fails the round. On completion, the packets are
+// delivered. On failure, critical packets are resent on a new round.
+func (n *Network) completeRound(
+	rid id.Round, timestamps []uint64, packets []packet) {
+	n.mux.Lock()
+	failed := n.roll(n.params.RoundFailureRate)
+	var delivered []packet
+	if !failed {
+		delivered = make([]packet, 0, len(packets))
+		for _, p := range packets {
+			if !n.roll(n.params.LossRate) {
+				delivered = append(delivered, p)
+			}
+		}
+	}
+	clients := n.getClients()
+	n.mux.Unlock()
+
+	state := states.COMPLETED
+	if failed {
+		state = states.FAILED
+	}
+	timestamps[state] = uint64(netTime.Now().UnixNano())
+	ri, err := n.updateRound(rid, state, timestamps, len(packets))
+	if err != nil {
+		jww.ERROR.Printf("[SIM] Failed to update round %d to %s: %+v",
+			rid, state, err)
+		return
+	}
+
+	if failed {
+		jww.DEBUG.Printf("[SIM] Round %d with %d messages failed",
+			rid, len(packets))
+		n.resendCritical(packets)
+		return
+	}
+
+	jww.DEBUG.Printf("[SIM] Round %d completed and delivered %d of %d "+
+		"messages", rid, len(delivered), len(packets))
+	n.deliver(rounds.MakeRound(ri), delivered, clients)
+}
+
+// resendCritical puts the critical packets on a new round.
+func (n *Network) resendCritical(packets []packet) {
+	critical := make([]packet, 0, len(packets))
+	for _, p := range packets {
+		if p.critical {
+			critical = append(critical, p)
+		}
+	}
+	if len(critical) == 0 {
+		return
+	}
+
+	rid := n.reserveRound()
+	if _, err := n.startRound(rid, critical); err != nil {
+		jww.ERROR.Printf("[SIM] Failed to resend critical messages on "+
+			"round %d: %+v", rid, err)
+	}
+}
+
+// deliver hands each packet to every client tracking an identity with the
+// same ephemeral ID as the recipient of the packet.
+func (n *Network) deliver(round rounds.Round, packets []packet,
+	clients []*Client) {
+	ts := round.Timestamps[states.QUEUED]
+	for _, c := range clients {
+		bundles := make(map[id.ID]*message.Bundle)
+		for _, p := range packets {
+			for _, source := range c.getMatchingIdentities(p.ephID, ts) {
+				b, exists := bundles[*source]
+				if !exists {
+					b = &message.Bundle{
+						Round:     round.ID,
+						RoundInfo: round,
+						Finish:    func() {},
+						Identity: receptionID.EphemeralIdentity{
+							EphId:  p.ephID,
+							Source: source,
+						},
+					}
+					bundles[*source] = b
+				}
+				b.Messages = append(b.Messages, p.msg)
+			}
+		}
+
+		for _, b := range bundles {
+			c.receive(*b)
+		}
+	}
+}
+
+// updateRound signs the round info with the new state and adds it to the
+// instance, which triggers round events.
+func (n *Network) updateRound(rid id.Round, state states.Round,
+	timestamps []uint64, batchSize int) (*pb.RoundInfo, error) {
+	n.roundMux.Lock()
+	defer n.roundMux.Unlock()
+
+	n.nextUpdateID++
+	ri := &pb.RoundInfo{
+		ID:               uint64(rid),
+		UpdateID:         n.nextUpdateID,
+		State:            uint32(state),
+		BatchSize:        uint32(batchSize),
+		Topology:         n.nodes,
+		Timestamps:       append([]uint64{}, timestamps...),
+		AddressSpaceSize: uint32(n.params.AddressSpaceSize),
+	}
+	if err := signature.SignEddsa(ri, n.key); err != nil {
+		return nil, errors.Wrapf(err, "failed to sign round %d", rid)
+	}
+
+	if err := n.instance.RoundUpdates([]*pb.RoundInfo{ri}); err != nil {
+		return nil, errors.Wrapf(err, "failed to add round %d", rid)
+	}
+
+	return ri, nil
+}
+
+// roll returns true with the given probability. Must be called under lock.
+func (n *Network) roll(probability float64) bool {
+	return probability > 0 && n.rng.Float64() < probability
+}
+
+// getEphemeralID returns the ephemeral ID of the identity at the given time.
+func getEphemeralID(
+	identity *id.ID, params Params, ts time.Time) (ephemeral.Id, error) {
+	ephID, _, _, err := ephemeral.GetId(
+		identity, uint(params.AddressSpaceSize), ts.UnixNano())
+	return ephID, err
+}
+
+// makeNodeID generates a deterministic node or gateway ID.
+func makeNodeID(i int, idType id.Type) *id.ID {
+	nid := &id.ID{}
+	copy(nid[:], "simulated")
+	binary.BigEndian.PutUint64(nid[id.ArrIDLen-9:], uint64(i))
+	nid.SetType(idType)
+	return nid
+}
@@ -0,0 +1,342 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package simulated
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that a message sent with a fingerprint is delivered to the processor
+// of the recipient, with the correct contents, round, and ephemeral ID.
+func TestNetwork_Fingerprint(t *testing.T) {
+	n, params := newTestNetwork(t, DefaultParams())
+	sender, _ := newTestClient(t, n, 0)
+	recipient, recipientID := newTestClient(t, n, 1)
+
+	fp := format.NewFingerprint([]byte("fingerprint"))
+	processor := newMockProcessor()
+	if err := recipient.AddFingerprint(recipientID, fp, processor); err != nil {
+		t.Fatalf("Failed to add fingerprint: %+v", err)
+	}
+
+	payload, mac := newTestPayload(sender, 42)
+	round, ephID, err :=
+		sender.Send(recipientID, fp, message.Service{}, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+
+	select {
+	case r := <-processor.c:
+		if !bytes.Equal(payload, r.msg.GetContents()) {
+			t.Errorf("Received wrong contents.\nexpected: %v\nreceived: %v",
+				payload, r.msg.GetContents())
+		}
+		if r.round.ID != round.ID {
+			t.Errorf("Received on wrong round.\nexpected: %d\nreceived: %d",
+				round.ID, r.round.ID)
+		}
+		if r.receptionID.EphId != ephID {
+			t.Errorf("Received on wrong ephemeral ID."+
+				"\nexpected: %d\nreceived: %d", ephID, r.receptionID.EphId)
+		}
+		if !r.receptionID.Source.Cmp(recipientID) {
+			t.Errorf("Received for wrong identity.\nexpected: %s\nreceived: %s",
+				recipientID, r.receptionID.Source)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for message.")
+	}
+}
+
+// Tests that a message sent to a service is delivered to the processor of the
+// recipient and that the sender does not receive it.
+func TestNetwork_Service(t *testing.T) {
+	n, params := newTestNetwork(t, DefaultParams())
+	sender, _ := newTestClient(t, n, 0)
+	recipient, recipientID := newTestClient(t, n, 1)
+
+	service := message.Service{Identifier: recipientID[:], Tag: "test"}
+	processor := newMockProcessor()
+	recipient.AddService(recipientID, service, processor)
+	senderProcessor := newMockProcessor()
+	sender.AddService(recipientID, service, senderProcessor)
+
+	payload, mac := newTestPayload(sender, 42)
+	_, _, err := sender.Send(
+		recipientID, format.Fingerprint{}, service, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+
+	select {
+	case r := <-processor.c:
+		if !bytes.Equal(payload, r.msg.GetContents()) {
+			t.Errorf("Received wrong contents.\nexpected: %v\nreceived: %v",
+				payload, r.msg.GetContents())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for message.")
+	}
+
+	select {
+	case <-senderProcessor.c:
+		t.Errorf("Sender received message not addressed to it.")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Tests that messages received before the client starts following the network
+// are held and delivered once it does.
+func TestNetwork_Pending(t *testing.T) {
+	n, params := newTestNetwork(t, DefaultParams())
+	sender, _ := newTestClient(t, n, 0)
+	recipientID := id.NewIdFromUInt(1, id.User, t)
+	recipient := n.NewClient(
+		recipientID, versioned.NewKV(ekv.MakeMemstore()), nil)
+
+	fp := format.NewFingerprint([]byte("fingerprint"))
+	processor := newMockProcessor()
+	if err := recipient.AddFingerprint(recipientID, fp, processor); err != nil {
+		t.Fatalf("Failed to add fingerprint: %+v", err)
+	}
+
+	payload, mac := newTestPayload(sender, 42)
+	round, _, err :=
+		sender.Send(recipientID, fp, message.Service{}, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+	waitForRound(t, sender, round.ID, true)
+
+	select {
+	case <-processor.c:
+		t.Fatalf("Received message before following the network.")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	followTestClient(t, recipient)
+
+	select {
+	case <-processor.c:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for message.")
+	}
+}
+
+// Tests that no messages are delivered when all messages are lost, but that
+// the round still completes.
+func TestNetwork_LossRate(t *testing.T) {
+	p := DefaultParams()
+	p.LossRate = 1
+	n, params := newTestNetwork(t, p)
+	sender, _ := newTestClient(t, n, 0)
+	recipient, recipientID := newTestClient(t, n, 1)
+
+	fp := format.NewFingerprint([]byte("fingerprint"))
+	processor := newMockProcessor()
+	if err := recipient.AddFingerprint(recipientID, fp, processor); err != nil {
+		t.Fatalf("Failed to add fingerprint: %+v", err)
+	}
+
+	payload, mac := newTestPayload(sender, 42)
+	round, _, err :=
+		sender.Send(recipientID, fp, message.Service{}, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+	waitForRound(t, sender, round.ID, true)
+
+	select {
+	case <-processor.c:
+		t.Errorf("Received message that should have been lost.")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Tests that GetRoundResults reports failed rounds and that critical messages
+// on failed rounds are resent on a new round.
+func TestNetwork_RoundFailure(t *testing.T) {
+	p := DefaultParams()
+	p.RoundFailureRate = 1
+	n, params := newTestNetwork(t, p)
+	sender, _ := newTestClient(t, n, 0)
+	_, recipientID := newTestClient(t, n, 1)
+
+	payload, mac := newTestPayload(sender, 42)
+	round, _, err := sender.Send(recipientID, format.Fingerprint{},
+		message.Service{}, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+	waitForRound(t, sender, round.ID, false)
+
+	params.Critical = true
+	round, _, err = sender.Send(recipientID, format.Fingerprint{},
+		message.Service{}, payload, mac, params)
+	if err != nil {
+		t.Fatalf("Failed to send: %+v", err)
+	}
+	waitForRound(t, sender, round.ID, false)
+
+	// The resent message is put on the next round
+	waitForRound(t, sender, round.ID+1, false)
+}
+
+// Tests that the health of clients follows the health of the Network and that
+// sends fail while it is unhealthy.
+func TestNetwork_SetHealthy(t *testing.T) {
+	n, params := newTestNetwork(t, DefaultParams())
+	c, cID := newTestClient(t, n, 0)
+
+	healthChan := make(chan bool, 10)
+	c.AddHealthCallback(func(healthy bool) { healthChan <- healthy })
+	expectHealth(t, healthChan, true)
+
+	n.SetHealthy(false)
+	expectHealth(t, healthChan, false)
+	if c.IsHealthy() {
+		t.Errorf("Client is healthy when the network is not.")
+	}
+
+	payload, mac := newTestPayload(c, 42)
+	_, _, err := c.Send(
+		cID, format.Fingerprint{}, message.Service{}, payload, mac, params)
+	if err == nil {
+		t.Errorf("Send succeeded on an unhealthy network.")
+	}
+
+	n.SetHealthy(true)
+	expectHealth(t, healthChan, true)
+	if !c.IsHealthy() || !c.WasHealthy() {
+		t.Errorf("Client is not healthy when the network is.")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Utility Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// newTestNetwork creates a new Network with a seeded RNG and returns it with
+// parameters to send with.
+func newTestNetwork(t testing.TB, p Params) (*Network, cmix.CMIXParams) {
+	p.Seed = 42
+	n, err := NewNetwork(p)
+	if err != nil {
+		t.Fatalf("Failed to create network: %+v", err)
+	}
+	return n, cmix.GetDefaultCMIXParams()
+}
+
+// newTestClient creates a new Client on the Network that is following it.
+func newTestClient(t testing.TB, n *Network, i uint64) (*Client, *id.ID) {
+	receptionID := id.NewIdFromUInt(i, id.User, t)
+	c := n.NewClient(receptionID, versioned.NewKV(ekv.MakeMemstore()), nil)
+	followTestClient(t, c)
+	return c, receptionID
+}
+
+// followTestClient starts following the network and stops when the test ends.
+func followTestClient(t testing.TB, c *Client) {
+	s, err := c.Follow(nil)
+	if err != nil {
+		t.Fatalf("Failed to follow network: %+v", err)
+	}
+	t.Cleanup(func() {
+		if err = s.Close(); err != nil {
+			t.Errorf("Failed to stop following: %+v", err)
+		}
+		if err = stoppable.WaitForStopped(s, time.Second); err != nil {
+			t.Errorf("Failed to wait for stop: %+v", err)
+		}
+	})
+}
+
+// newTestPayload returns a random payload and MAC to send.
+func newTestPayload(c *Client, seed int64) ([]byte, []byte) {
+	prng := rand.New(rand.NewSource(seed))
+	payload := make([]byte, c.GetMaxMessageLength())
+	prng.Read(payload)
+	mac := make([]byte, format.MacLen)
+	prng.Read(mac)
+	mac[0] &= 0x7F
+	return payload, mac
+}
+
+// waitForRound waits for the round to complete or fail.
+func waitForRound(t testing.TB, c *Client, rid id.Round, succeeded bool) {
+	resultChan := make(chan bool, 1)
+	c.GetRoundResults(time.Second, func(allRoundsSucceeded, timedOut bool,
+		_ map[id.Round]cmix.RoundResult) {
+		if timedOut {
+			t.Errorf("Timed out waiting for round %d.", rid)
+		}
+		resultChan <- allRoundsSucceeded
+	}, rid)
+
+	select {
+	case allRoundsSucceeded := <-resultChan:
+		if allRoundsSucceeded != succeeded {
+			t.Errorf("Unexpected round result for round %d."+
+				"\nexpected: %t\nreceived: %t",
+				rid, succeeded, allRoundsSucceeded)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for round %d results.", rid)
+	}
+}
+
+// expectHealth waits for the expected health on the channel.
+func expectHealth(t testing.TB, healthChan chan bool, expected bool) {
+	select {
+	case healthy := <-healthChan:
+		if healthy != expected {
+			t.Errorf("Unexpected health.\nexpected: %t\nreceived: %t",
+				expected, healthy)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for health %t.", expected)
+	}
+}
+
+// mockProcessor adheres to the message.Processor interface and sends every
+// processed message on its channel.
+type mockProcessor struct {
+	c chan processed
+}
+
+// processed is a message received by the mockProcessor.
+type processed struct {
+	msg         format.Message
+	receptionID receptionID.EphemeralIdentity
+	round       rounds.Round
+}
+
+func newMockProcessor() *mockProcessor {
+	return &mockProcessor{c: make(chan processed, 10)}
+}
+
+func (mp *mockProcessor) Process(msg format.Message, _ []string, _ []byte,
+	receptionID receptionID.EphemeralIdentity, round rounds.Round) {
+	mp.c <- processed{msg, receptionID, round}
+}
+
+func (mp *mockProcessor) String() string { return "mockProcessor" }
@@ -0,0 +1,65 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package simulated
+
+import (
+	"time"
+)
+
+// Default values for the Params.
+const (
+	defaultLatency          = 50 * time.Millisecond
+	defaultAddressSpaceSize = 16
+	defaultNumNodes         = 3
+)
+
+// Params configures the behaviour of a simulated Network.
+type Params struct {
+	// Latency is the time between a message being sent and the round it is
+	// sent on completing. Messages are delivered when the round completes.
+	Latency time.Duration
+
+	// LatencyJitter is the maximum random duration added to the Latency of
+	// each round.
+	LatencyJitter time.Duration
+
+	// LossRate is the probability, between 0 and 1, that a message on a
+	// completed round is not delivered.
+	LossRate float64
+
+	// RoundFailureRate is the probability, between 0 and 1, that a round
+	// fails. No messages on a failed round are delivered.
+	RoundFailureRate float64
+
+	// AddressSpaceSize is the size, in bits, of the ephemeral ID address
+	// space. Smaller sizes cause more ephemeral ID collisions between
+	// identities.
+	AddressSpaceSize uint8
+
+	// NumNodes is the number of nodes in the NDF and in each round's
+	// topology.
+	NumNodes int
+
+	// Seed seeds the source of randomness used for latency, loss, and round
+	// failures so that runs can be reproduced.
+	Seed int64
+}
+
+// DefaultParams returns a Params object with a reliable network with a small
+// latency.
+func DefaultParams() Params {
+	return Params{
+		Latency:          defaultLatency,
+		LatencyJitter:    0,
+		LossRate:         0,
+		RoundFailureRate: 0,
+		AddressSpaceSize: defaultAddressSpaceSize,
+		NumNodes:         defaultNumNodes,
+		Seed:             time.Now().UnixNano(),
+	}
+}
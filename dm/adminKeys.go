@@ -0,0 +1,72 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"sync"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// adminKeyHandler passes channel admin keys received from partners to the
+// registered AdminKeyReceiver. Keys received before a receiver is registered
+// are held in memory until one is. Keys are never written to storage.
+type adminKeyHandler struct {
+	receiver AdminKeyReceiver
+	pending  []receivedAdminKey
+	mux      sync.Mutex
+}
+
+// receivedAdminKey is a channel admin key waiting for an AdminKeyReceiver.
+type receivedAdminKey struct {
+	channelID *id.ID
+	adminKey  []byte
+}
+
+// RegisterAdminKeyReceiver registers the receiver that channel admin keys sent
+// with SendChannelAdminKey are passed to. Any keys received before registration
+// are passed to it immediately. Registering a new receiver replaces the
+// previous one.
+func (dc *dmClient) RegisterAdminKeyReceiver(akr AdminKeyReceiver) {
+	dc.adminKeys.mux.Lock()
+	defer dc.adminKeys.mux.Unlock()
+
+	dc.adminKeys.receiver = akr
+	for _, rak := range dc.adminKeys.pending {
+		dc.adminKeys.pass(rak)
+	}
+	dc.adminKeys.pending = nil
+}
+
+// receive passes the admin key to the registered AdminKeyReceiver or holds it
+// until one is registered.
+func (akh *adminKeyHandler) receive(channelID *id.ID, adminKey []byte) {
+	akh.mux.Lock()
+	defer akh.mux.Unlock()
+
+	rak := receivedAdminKey{channelID, adminKey}
+	if akh.receiver == nil {
+		jww.INFO.Printf("[DM] Holding admin key for channel %s until an "+
+			"admin key receiver is registered", channelID)
+		akh.pending = append(akh.pending, rak)
+		return
+	}
+	akh.pass(rak)
+}
+
+// pass hands the admin key to the registered AdminKeyReceiver. Must be called
+// under the lock.
+func (akh *adminKeyHandler) pass(rak receivedAdminKey) {
+	err := akh.receiver.ReceiveChannelAdminKey(rak.channelID, rak.adminKey)
+	if err != nil {
+		jww.ERROR.Printf("[DM] Failed to receive admin key for channel %s: "+
+			"%+v", rak.channelID, err)
+	}
+}
@@ -0,0 +1,65 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that admin keys received before an AdminKeyReceiver is registered are
+// held and passed to it on registration and that later keys are passed to it
+// directly.
+func Test_dmClient_RegisterAdminKeyReceiver(t *testing.T) {
+	dc := &dmClient{}
+	first := receivedAdminKey{id.NewIdFromString("first", id.User, t),
+		[]byte("first key")}
+	second := receivedAdminKey{id.NewIdFromString("second", id.User, t),
+		[]byte("second key")}
+
+	dc.adminKeys.receive(first.channelID, first.adminKey)
+	require.Len(t, dc.adminKeys.pending, 1)
+
+	akr := &mockAdminKeyReceiver{}
+	dc.RegisterAdminKeyReceiver(akr)
+	require.Empty(t, dc.adminKeys.pending)
+	require.Equal(t, []receivedAdminKey{first}, akr.keys)
+
+	dc.adminKeys.receive(second.channelID, second.adminKey)
+	require.Empty(t, dc.adminKeys.pending)
+	require.Equal(t, []receivedAdminKey{first, second}, akr.keys)
+}
+
+// Tests that an error returned by the AdminKeyReceiver does not keep the admin
+// key pending.
+func Test_adminKeyHandler_receive_ReceiverError(t *testing.T) {
+	dc := &dmClient{}
+	akr := &mockAdminKeyReceiver{err: errors.New("receive error")}
+	dc.RegisterAdminKeyReceiver(akr)
+
+	dc.adminKeys.receive(id.NewIdFromString("channel", id.User, t), []byte("key"))
+	require.Len(t, akr.keys, 1)
+	require.Empty(t, dc.adminKeys.pending)
+}
+
+// mockAdminKeyReceiver records the admin keys passed to
+// ReceiveChannelAdminKey.
+type mockAdminKeyReceiver struct {
+	keys []receivedAdminKey
+	err  error
+}
+
+func (m *mockAdminKeyReceiver) ReceiveChannelAdminKey(
+	channelID *id.ID, adminKey []byte) error {
+	m.keys = append(m.keys, receivedAdminKey{channelID, adminKey})
+	return m.err
+}
@@ -21,8 +21,11 @@ import (
 
 	"gitlab.com/elixxir/client/v4/cmix/identity"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/scheduler"
+	"gitlab.com/elixxir/client/v4/typingIndicator"
 	"gitlab.com/elixxir/crypto/codename"
 	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
 	"gitlab.com/elixxir/crypto/nike"
 	"gitlab.com/elixxir/crypto/nike/ecdh"
 	"gitlab.com/xx_network/primitives/id"
@@ -41,13 +44,27 @@ type dmClient struct {
 	myToken         uint32
 	receiver        EventModel
 
-	st SendTracker
-	nm NickNameManager
-	ps *partnerStore
+	st  SendTracker
+	nm  NickNameManager
+	ps  *partnerStore
+	rrs *readReceiptStore
 	*notifications
 	as  *ActionSaver
 	net cMixClient
 	rng *fastRNG.StreamGenerator
+	cbs Callbacks
+
+	// Limits how often typing indicators are sent
+	typing *typingIndicator.Limiter
+
+	// Deletes messages once the retention timer of their conversation expires
+	retention *retentionManager
+
+	// Sends messages that are scheduled to be sent at a future time
+	scheduler *scheduler.Scheduler
+
+	// Passes received channel admin keys to the channels manager
+	adminKeys adminKeyHandler
 }
 
 // NewDMClient creates a new client for direct messaging. This should
@@ -80,6 +97,16 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 		return nil, err
 	}
 
+	rrs, err := newReadReceiptStore(kv)
+	if err != nil {
+		return nil, err
+	}
+
+	retention, err := newRetentionManager(kv, receiver)
+	if err != nil {
+		return nil, err
+	}
+
 	privateEdwardsKey := myID.Privkey
 	myIDToken := myID.GetDMToken()
 
@@ -96,8 +123,15 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 			errors.Wrap(err, "failed to initialize DM notification manager")
 	}
 
-	// Set up listeners for notifications and blocked users on the partner store
-	err = n.ps.listen(n.updateSihTagsCB, updateBlockedUsers(cbs.BlockedUser))
+	// Set up listeners for notifications, blocked users, and message requests
+	// on the partner store. Request conversations are marked in the event
+	// model before the UI is notified.
+	requestCB := func(partnerPubKey ed25519.PublicKey, pending bool) {
+		receiver.UpdateMessageRequest(partnerPubKey, pending)
+		cbs.MessageRequest(partnerPubKey, pending)
+	}
+	err = n.ps.listen(n.updateSihTagsCB, updateBlockedUsers(cbs.BlockedUser),
+		updateMessageRequests(requestCB))
 	if err != nil {
 		return nil, err
 	}
@@ -113,10 +147,14 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 		st:              tracker,
 		nm:              nickManager,
 		ps:              ps,
+		rrs:             rrs,
+		retention:       retention,
 		notifications:   n,
 		as:              NewActionSaver(kv),
 		net:             net,
 		rng:             rng,
+		cbs:             cbs,
+		typing:          typingIndicator.NewLimiter(TypingInterval),
 	}
 
 	// Register the listener
@@ -125,6 +163,12 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 		jww.FATAL.Panicf("[DM] Failed to register listener: %+v", err)
 	}
 
+	dmc.scheduler, err = scheduler.NewOrLoad(
+		kv, scheduledSendsStoreKey, net, dmc.sendScheduled)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM scheduled sends")
+	}
+
 	return dmc, nil
 }
 
@@ -146,7 +190,9 @@ func updateBlockedUsers(
 					go cb(e.new.PublicKey, true)
 				}
 			case versioned.Deleted:
-				go cb(e.old.PublicKey, false)
+				if e.old.Status == statusBlocked {
+					go cb(e.old.PublicKey, false)
+				}
 			}
 		}
 	}
@@ -299,3 +345,10 @@ func (dcb *dummyCallback) NotificationUpdate(
 }
 func (dcb *dummyCallback) BlockedUser(ed25519.PublicKey, bool) {
 }
+func (dcb *dummyCallback) UserTyping(ed25519.PublicKey, string, bool) {
+}
+func (dcb *dummyCallback) MessageRequest(ed25519.PublicKey, bool) {
+}
+func (dcb *dummyCallback) ScheduledSendUpdate(
+	uint64, ed25519.PublicKey, cryptoMessage.ID, error) {
+}
@@ -8,12 +8,14 @@
 package dm
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"gitlab.com/elixxir/client/v4/broadcast"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	"google.golang.org/protobuf/proto"
 	"os"
 	"testing"
+	"time"
 
 	jww "github.com/spf13/jwalterweatherman"
 	"github.com/stretchr/testify/require"
@@ -21,6 +23,7 @@ import (
 	"gitlab.com/elixxir/client/v4/collective"
 	"gitlab.com/elixxir/crypto/codename"
 	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
 	"gitlab.com/elixxir/ekv"
 	"gitlab.com/xx_network/crypto/csprng"
 )
@@ -139,4 +142,722 @@ func TestE2EDMs(t *testing.T) {
 	rcvB3 := receiverB.Msgs[5]
 	silent := &SilentMessage{}
 	require.NoError(t, proto.Unmarshal([]byte(rcvB3.Message), silent))
+
+	// Send a channel admin key; it is passed to the partner's registered
+	// AdminKeyReceiver and is not stored by either side
+	akrA, akrB := &mockAdminKeyReceiver{}, &mockAdminKeyReceiver{}
+	clientA.RegisterAdminKeyReceiver(akrA)
+	clientB.RegisterAdminKeyReceiver(akrB)
+	numMsgsA := len(receiverA.Msgs)
+	_, _, _, err = clientA.SendChannelAdminKey(pubKey, dmToken,
+		ch.ReceptionID, []byte("adminKey"), params)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(receiverB.Msgs))
+	require.Equal(t, numMsgsA, len(receiverA.Msgs))
+	require.Empty(t, akrA.keys, "Sender received its own admin key.")
+	require.Equal(t, []receivedAdminKey{{ch.ReceptionID, []byte("adminKey")}},
+		akrB.keys)
+}
+
+// Tests that a typing indicator sent via dmClient.SendTyping is delivered to
+// the partner's Callbacks.UserTyping and is not stored by either side.
+func TestE2EDMs_Typing(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	nnmA := NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA)
+	require.NoError(t, nnmA.SetNickname("Alice"))
+	nnmB := NewNicknameManager(
+		deriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB)
+
+	typingCbs := &mockTypingCallbacks{typing: make(chan typingUpdate, 10)}
+
+	clientA, err := NewDMClient(&me, receiverA, NewSendTracker(ekvA), nnmA,
+		newMockNM(), netA, ekvA, crng, nil)
+	require.NoError(t, err)
+	clientB, err := NewDMClient(&partner, receiverB, NewSendTracker(ekvB),
+		nnmB, newMockNM(), netB, ekvB, crng, typingCbs)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+
+	// Typing indicators are only received once the message request is accepted
+	_, _, _, err =
+		clientA.SendText(partner.PubKey, partner.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+	require.NoError(t, clientB.AcceptMessageRequest(me.PubKey))
+	numMsgsA, numMsgsB := len(receiverA.Msgs), len(receiverB.Msgs)
+
+	// Start typing
+	_, _, _, err = clientA.SendTyping(
+		partner.PubKey, partner.GetDMToken(), true, params)
+	require.NoError(t, err)
+
+	select {
+	case u := <-typingCbs.typing:
+		require.Equal(t, me.PubKey, u.pubKey)
+		require.Equal(t, "Alice", u.nickname)
+		require.True(t, u.typing)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for typing indicator.")
+	}
+
+	// A second start within the interval is rate limited
+	_, _, _, err = clientA.SendTyping(
+		partner.PubKey, partner.GetDMToken(), true, params)
+	require.ErrorIs(t, err, TypingRateLimitErr)
+
+	// Stop typing
+	_, _, _, err = clientA.SendTyping(
+		partner.PubKey, partner.GetDMToken(), false, params)
+	require.NoError(t, err)
+
+	select {
+	case u := <-typingCbs.typing:
+		require.False(t, u.typing)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for typing indicator.")
+	}
+
+	// Typing indicators are never stored
+	require.Len(t, receiverA.Msgs, numMsgsA)
+	require.Len(t, receiverB.Msgs, numMsgsB)
+}
+
+// typingUpdate records a single call to Callbacks.UserTyping.
+type typingUpdate struct {
+	pubKey   ed25519.PublicKey
+	nickname string
+	typing   bool
+}
+
+// mockTypingCallbacks adheres to the Callbacks interface and reports typing
+// indicators on a channel.
+type mockTypingCallbacks struct {
+	dummyCallback
+	typing chan typingUpdate
+}
+
+func (m *mockTypingCallbacks) UserTyping(
+	partnerPubKey ed25519.PublicKey, nickname string, typing bool) {
+	m.typing <- typingUpdate{partnerPubKey, nickname, typing}
+}
+
+// Tests that a scheduled DM is held until the network is healthy, is then sent
+// to the partner through the normal send path, and is reported to
+// Callbacks.ScheduledSendUpdate. A cancelled send is never sent.
+func TestE2EDMs_Scheduled(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	nnmA := NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA)
+	nnmB := NewNicknameManager(
+		deriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB)
+
+	scheduledCbs := &mockScheduledCallbacks{
+		updates: make(chan scheduledSendUpdate, 10)}
+
+	clientA, err := newDmClient(&me, receiverA, NewSendTracker(ekvA), nnmA,
+		newMockNM(), netA, ekvA, crng, scheduledCbs)
+	require.NoError(t, err)
+	_, err = newDmClient(&partner, receiverB, NewSendTracker(ekvB), nnmB,
+		newMockNM(), netB, ekvB, crng, nil)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+	sendAt := time.Now().Add(50 * time.Millisecond)
+
+	// Sends in the past are rejected
+	_, err = clientA.ScheduleText(partner.PubKey, partner.GetDMToken(), "Hi",
+		time.Now().Add(-time.Second), params)
+	require.ErrorIs(t, err, InvalidScheduleTimeErr)
+
+	ss, err := clientA.ScheduleText(
+		partner.PubKey, partner.GetDMToken(), "Hi", sendAt, params)
+	require.NoError(t, err)
+	cancelled, err := clientA.ScheduleText(
+		partner.PubKey, partner.GetDMToken(), "Bye", sendAt, params)
+	require.NoError(t, err)
+	require.Len(t, clientA.GetScheduledSends(partner.PubKey), 2)
+	require.Empty(t, clientA.GetScheduledSends(me.PubKey))
+	require.NoError(t, clientA.CancelScheduledSend(cancelled.ID))
+	require.ErrorIs(t, clientA.CancelScheduledSend(cancelled.ID),
+		ScheduledSendNotFoundErr)
+
+	// Nothing is sent while the processes are not started
+	netA.setHealthy(true)
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, receiverB.Msgs)
+	require.Len(t, clientA.GetScheduledSends(nil), 1)
+
+	// Nothing is sent while the network is not healthy
+	netA.setHealthy(false)
+	stop, err := clientA.StartProcesses()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop.Close()) }()
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, receiverB.Msgs)
+
+	// The overdue send is sent once the network is healthy
+	netA.setHealthy(true)
+	select {
+	case u := <-scheduledCbs.updates:
+		require.NoError(t, u.err)
+		require.Equal(t, ss.ID, u.sendID)
+		require.Equal(t, partner.PubKey, u.pubKey)
+		require.Len(t, receiverB.Msgs, 1)
+		require.Equal(t, "Hi", receiverB.Msgs[0].Message)
+		require.Equal(t, u.messageID, receiverB.Msgs[0].MessageID)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for scheduled send.")
+	}
+	require.Empty(t, clientA.GetScheduledSends(nil))
+}
+
+// scheduledSendUpdate records a single call to Callbacks.ScheduledSendUpdate.
+type scheduledSendUpdate struct {
+	sendID    uint64
+	pubKey    ed25519.PublicKey
+	messageID cryptoMessage.ID
+	err       error
+}
+
+// mockScheduledCallbacks adheres to the Callbacks interface and reports
+// scheduled send updates on a channel.
+type mockScheduledCallbacks struct {
+	dummyCallback
+	updates chan scheduledSendUpdate
+}
+
+func (m *mockScheduledCallbacks) ScheduledSendUpdate(sendID uint64,
+	partnerPubKey ed25519.PublicKey, messageID cryptoMessage.ID, err error) {
+	m.updates <- scheduledSendUpdate{sendID, partnerPubKey, messageID, err}
+}
+
+// Tests that dmClient.MarkRead updates the read status locally and on the
+// partner's side, and that no receipt is sent when read receipts are disabled
+// for the partner or the partner is blocked.
+func TestE2EDMs_MarkRead(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	nnmA := NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA)
+	nnmB := NewNicknameManager(
+		deriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB)
+
+	clientA, err := NewDMClient(&me, receiverA, NewSendTracker(ekvA), nnmA,
+		newMockNM(), netA, ekvA, crng, nil)
+	require.NoError(t, err)
+	clientB, err := NewDMClient(&partner, receiverB, NewSendTracker(ekvB), nnmB,
+		newMockNM(), netB, ekvB, crng, nil)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+
+	msgID, _, _, err :=
+		clientA.SendText(partner.PubKey, partner.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+
+	// No receipts are sent until the message request is accepted
+	require.False(t, clientB.ReadReceiptsEnabled(me.PubKey))
+	require.NoError(t, clientB.AcceptMessageRequest(me.PubKey))
+
+	numMsgsA, numMsgsB := len(receiverA.Msgs), len(receiverB.Msgs)
+
+	// Partner reads the message
+	receiptID, _, _, err :=
+		clientB.MarkRead(me.PubKey, me.GetDMToken(), msgID, params)
+	require.NoError(t, err)
+	require.NotEqual(t, cryptoMessage.ID{}, receiptID)
+
+	// Marked read locally and via the self-sent receipt
+	expectedB := mockRead{me.PubKey, me.PubKey, msgID}
+	require.Equal(t, []mockRead{expectedB, expectedB}, receiverB.Reads)
+
+	// Marked read on the sender's side
+	require.Equal(t,
+		[]mockRead{{partner.PubKey, me.PubKey, msgID}}, receiverA.Reads)
+
+	// Receipts are never stored as messages
+	require.Len(t, receiverA.Msgs, numMsgsA)
+	require.Len(t, receiverB.Msgs, numMsgsB)
+
+	// No receipt is sent when read receipts are disabled
+	clientB.SetReadReceipts(me.PubKey, false)
+	require.False(t, clientB.ReadReceiptsEnabled(me.PubKey))
+	receiptID, _, _, err =
+		clientB.MarkRead(me.PubKey, me.GetDMToken(), msgID, params)
+	require.NoError(t, err)
+	require.Equal(t, cryptoMessage.ID{}, receiptID)
+	require.Len(t, receiverB.Reads, 3)
+	require.Len(t, receiverA.Reads, 1)
+
+	// No receipt is sent to blocked partners
+	clientB.SetReadReceipts(me.PubKey, true)
+	require.True(t, clientB.ReadReceiptsEnabled(me.PubKey))
+	clientB.BlockPartner(me.PubKey)
+	require.False(t, clientB.ReadReceiptsEnabled(me.PubKey))
+	receiptID, _, _, err =
+		clientB.MarkRead(me.PubKey, me.GetDMToken(), msgID, params)
+	require.NoError(t, err)
+	require.Equal(t, cryptoMessage.ID{}, receiptID)
+	require.Len(t, receiverA.Reads, 1)
+}
+
+// TestE2EDMs_Retention tests that a retention timer set by one partner is
+// applied on both sides of the conversation and that messages in the
+// conversation are deleted from both EventModels once they expire.
+func TestE2EDMs_Retention(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	nnmA := NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA)
+	nnmB := NewNicknameManager(
+		deriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB)
+
+	clientA, err := NewDMClient(&me, receiverA, NewSendTracker(ekvA), nnmA,
+		newMockNM(), netA, ekvA, crng, nil)
+	require.NoError(t, err)
+	clientB, err := NewDMClient(&partner, receiverB, NewSendTracker(ekvB), nnmB,
+		newMockNM(), netB, ekvB, crng, nil)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+
+	// The partner contacts the user first so that the retention timer is not
+	// dropped as part of a message request
+	_, _, _, err = clientB.SendText(me.PubKey, me.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+
+	_, _, _, err = clientA.SetRetention(
+		partner.PubKey, partner.GetDMToken(), 30*time.Second, params)
+	require.ErrorIs(t, err, InvalidRetentionErr)
+
+	_, _, _, err = clientA.SetRetention(
+		partner.PubKey, partner.GetDMToken(), time.Hour, params)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, clientA.GetRetention(partner.PubKey))
+	require.Equal(t, time.Hour, clientB.GetRetention(me.PubKey))
+
+	// Every message in the conversation older than the timer is deleted on
+	// both sides, including messages sent before the timer was set
+	now := time.Now()
+	for _, c := range []Client{clientA, clientB} {
+		_, err = c.(*dmClient).retention.purge(now)
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]time.Time{
+		string(partner.PubKey): now.Add(-time.Hour)}, receiverA.DeletedBefore)
+	require.Equal(t, map[string]time.Time{
+		string(me.PubKey): now.Add(-time.Hour)}, receiverB.DeletedBefore)
+
+	// Clearing the timer on the partner's side clears it on both sides
+	_, _, _, err = clientB.SetRetention(me.PubKey, me.GetDMToken(), 0, params)
+	require.NoError(t, err)
+	require.Zero(t, clientA.GetRetention(partner.PubKey))
+	require.Zero(t, clientB.GetRetention(me.PubKey))
+}
+
+// Tests that the first message from an unknown partner creates a message
+// request that is marked in the event model, that reactions, replies, silent
+// messages, deletions, and retention timers from the partner are dropped until
+// the request is resolved, that the notification level cannot be changed while
+// it is pending, and that declining and replying to a request resolve it.
+func TestE2EDMs_MessageRequest(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	nnmA := NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA)
+	nnmB := NewNicknameManager(
+		deriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB)
+
+	requestCbs := &mockRequestCallbacks{requests: make(chan requestUpdate, 10)}
+
+	clientA, err := NewDMClient(&me, receiverA, NewSendTracker(ekvA), nnmA,
+		newMockNM(), netA, ekvA, crng, nil)
+	require.NoError(t, err)
+	clientB, err := NewDMClient(&partner, receiverB, NewSendTracker(ekvB),
+		nnmB, newMockNM(), netB, ekvB, crng, requestCbs)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+	waitForRequest := func(pending bool) {
+		select {
+		case u := <-requestCbs.requests:
+			require.Equal(t, me.PubKey, u.pubKey)
+			require.Equal(t, pending, u.pending)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for message request update.")
+		}
+	}
+
+	// First contact creates a message request only on the receiving side
+	msgID, _, _, err :=
+		clientA.SendText(partner.PubKey, partner.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+	waitForRequest(true)
+	require.True(t, clientB.IsMessageRequest(me.PubKey))
+	require.Equal(t, []ed25519.PublicKey{me.PubKey}, clientB.GetMessageRequests())
+	require.False(t, clientA.IsMessageRequest(partner.PubKey))
+	require.Empty(t, clientA.GetMessageRequests())
+	require.True(t, receiverB.isRequest(me.PubKey))
+	require.False(t, receiverA.isRequest(partner.PubKey))
+	numMsgsB := len(receiverB.Msgs)
+
+	// Reactions, replies, silent messages, deletions, and retention timers are
+	// dropped while the request is pending
+	_, _, _, err = clientA.SendReaction(
+		partner.PubKey, partner.GetDMToken(), "👍", msgID, params)
+	require.NoError(t, err)
+	_, _, _, err = clientA.SendReply(
+		partner.PubKey, partner.GetDMToken(), "Hello?", msgID, params)
+	require.NoError(t, err)
+	_, _, _, err = clientA.SendSilent(
+		partner.PubKey, partner.GetDMToken(), params)
+	require.NoError(t, err)
+	_, _, _, err = clientA.DeleteMessage(
+		partner.PubKey, partner.GetDMToken(), msgID, params)
+	require.NoError(t, err)
+	_, _, _, err = clientA.SetRetention(
+		partner.PubKey, partner.GetDMToken(), time.Hour, params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, numMsgsB)
+	require.Empty(t, receiverB.Deleted)
+	require.Zero(t, clientB.GetRetention(me.PubKey))
+
+	// Changing the notification level does not accept the request
+	require.ErrorIs(t, clientB.SetMobileNotificationsLevel(me.PubKey, NotifyAll),
+		PendingMessageRequestErr)
+	require.True(t, clientB.IsMessageRequest(me.PubKey))
+
+	// Declining removes the request until the partner messages again
+	require.NoError(t, clientB.DeclineMessageRequest(me.PubKey))
+	waitForRequest(false)
+	require.False(t, clientB.IsMessageRequest(me.PubKey))
+	require.Empty(t, clientB.GetMessageRequests())
+	require.False(t, receiverB.isRequest(me.PubKey))
+	require.ErrorIs(t,
+		clientB.DeclineMessageRequest(me.PubKey), NoMessageRequestErr)
+
+	_, _, _, err =
+		clientA.SendText(partner.PubKey, partner.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+	waitForRequest(true)
+	require.True(t, clientB.IsMessageRequest(me.PubKey))
+	require.True(t, receiverB.isRequest(me.PubKey))
+
+	// Messaging the partner accepts the request
+	_, _, _, err =
+		clientB.SendText(me.PubKey, me.GetDMToken(), "Hey", params)
+	require.NoError(t, err)
+	waitForRequest(false)
+	require.False(t, clientB.IsMessageRequest(me.PubKey))
+	require.False(t, receiverB.isRequest(me.PubKey))
+	require.NoError(t, clientB.SetMobileNotificationsLevel(me.PubKey, NotifyAll))
+	require.ErrorIs(t,
+		clientB.AcceptMessageRequest(me.PubKey), NoMessageRequestErr)
+
+	numMsgsB = len(receiverB.Msgs)
+	_, _, _, err = clientA.SendReaction(
+		partner.PubKey, partner.GetDMToken(), "👍", msgID, params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, numMsgsB+1)
+}
+
+// requestUpdate records a single call to Callbacks.MessageRequest.
+type requestUpdate struct {
+	pubKey  ed25519.PublicKey
+	pending bool
+}
+
+// mockRequestCallbacks adheres to the Callbacks interface and reports message
+// request updates on a channel.
+type mockRequestCallbacks struct {
+	dummyCallback
+	requests chan requestUpdate
+}
+
+func (m *mockRequestCallbacks) MessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	m.requests <- requestUpdate{partnerPubKey, pending}
+}
+
+// Tests that a message sent to a group DM is delivered to every member under
+// the group's conversation with the same message ID, and that members can
+// reply to it.
+func TestE2EDMs_Group(t *testing.T) {
+	netA, netB, netC := newMockClient(t), newMockClient(t), newMockClient(t)
+	linkNets(netA, netB, netC)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	bob, _ := codename.GenerateIdentity(rng)
+	carol, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	newClient := func(identity codename.PrivateIdentity, net *mockClient) (
+		*dmClient, *mockReceiver) {
+		kv := collective.TestingKV(t, ekv.MakeMemstore(),
+			collective.StandardPrefexs, collective.NewMockRemote())
+		receiver := newMockReceiver()
+		nnm := NewNicknameManager(
+			DeriveReceptionID(identity.PubKey, identity.GetDMToken()), kv)
+		c, err := newDmClient(&identity, receiver, NewSendTracker(kv), nnm,
+			newMockNM(), net, kv, crng, nil)
+		require.NoError(t, err)
+		return c, receiver
+	}
+	clientA, receiverA := newClient(me, netA)
+	clientB, receiverB := newClient(bob, netB)
+	clientC, receiverC := newClient(carol, netC)
+
+	params := cmix.GetDefaultCMIXParams()
+
+	// Invalid members are rejected
+	_, err := clientA.CreateGroup("group", nil)
+	require.Error(t, err)
+	_, err = clientA.CreateGroup("group",
+		[]GroupMember{{me.PubKey, me.GetDMToken()}})
+	require.Error(t, err)
+	_, err = clientA.CreateGroup("group", []GroupMember{
+		{bob.PubKey, bob.GetDMToken()}, {bob.PubKey, bob.GetDMToken()}})
+	require.Error(t, err)
+
+	members := []GroupMember{
+		{bob.PubKey, bob.GetDMToken()}, {carol.PubKey, carol.GetDMToken()}}
+	groupID, err := clientA.CreateGroup("group", members)
+	require.NoError(t, err)
+	require.Len(t, groupID, groupIDLen)
+	creator, valid := groupCreator(groupID)
+	require.True(t, valid)
+	require.Equal(t, me.PubKey, creator)
+	group, err := clientA.GetGroup(groupID)
+	require.NoError(t, err)
+	require.Equal(t, members, group.Members)
+
+	_, err = clientA.GetGroup(make(ed25519.PublicKey, ed25519.PublicKeySize))
+	require.ErrorIs(t, err, GroupNotFoundErr)
+
+	// A new group is dropped until its creator is accepted and does not open a
+	// message request
+	_, _, err = clientA.SendGroupText(groupID, "Hi", params)
+	require.NoError(t, err)
+	for _, c := range []*dmClient{clientB, clientC} {
+		require.False(t, c.IsMessageRequest(me.PubKey))
+	}
+	for _, r := range []*mockReceiver{receiverB, receiverC} {
+		require.Empty(t, r.Msgs)
+		require.Nil(t, r.GetGroup(groupID))
+		require.False(t, r.isRequest(me.PubKey))
+	}
+	clientB.ps.set(me.PubKey, defaultStatus)
+	clientC.ps.set(me.PubKey, defaultStatus)
+
+	// Every member receives the message in the group with the same ID
+	msgID, _, err := clientA.SendGroupText(groupID, "Hi", params)
+	require.NoError(t, err)
+	for _, r := range []*mockReceiver{receiverB, receiverC} {
+		require.Len(t, r.Msgs, 1)
+		require.Equal(t, "Hi", r.Msgs[0].Message)
+		require.Equal(t, groupID, r.Msgs[0].PubKey)
+		require.Equal(t, msgID, r.Msgs[0].MessageID)
+		require.NotNil(t, r.GetGroup(groupID))
+		require.Equal(t, "group", r.GetGroup(groupID).Name)
+		require.Len(t, r.GetGroup(groupID).Members, 2)
+	}
+	require.True(t, receiverB.GetGroup(groupID).hasMember(me.PubKey))
+	require.True(t, receiverB.GetGroup(groupID).hasMember(carol.PubKey))
+
+	// A reply from another member reaches everyone else in the group
+	numMsgsA, numMsgsB := len(receiverA.Msgs), len(receiverB.Msgs)
+	replyID, _, err := clientC.SendGroupReply(groupID, "Hey", msgID, params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, numMsgsB+1)
+	require.Equal(t, replyID, receiverB.Msgs[numMsgsB].MessageID)
+	require.Equal(t, msgID, receiverB.Msgs[numMsgsB].ReplyTo)
+	require.Equal(t, groupID, receiverB.Msgs[numMsgsB].PubKey)
+	require.Len(t, receiverA.Msgs, numMsgsA+1)
+	require.Equal(t, replyID, receiverA.Msgs[numMsgsA].MessageID)
+
+	// Message types that are not allowed in groups are rejected
+	_, _, err = clientA.SendGroup(groupID, TypingType, nil, params)
+	require.Error(t, err)
+}
+
+// Tests that only the creator and admins of a group DM can change its name,
+// members, and admins, and that a group can never take over a conversation
+// with a partner.
+func TestE2EDMs_GroupAdmins(t *testing.T) {
+	netA, netB, netC := newMockClient(t), newMockClient(t), newMockClient(t)
+	linkNets(netA, netB, netC)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	bob, _ := codename.GenerateIdentity(rng)
+	carol, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	newClient := func(identity codename.PrivateIdentity, net *mockClient) (
+		*dmClient, *mockReceiver) {
+		kv := collective.TestingKV(t, ekv.MakeMemstore(),
+			collective.StandardPrefexs, collective.NewMockRemote())
+		receiver := newMockReceiver()
+		nnm := NewNicknameManager(
+			DeriveReceptionID(identity.PubKey, identity.GetDMToken()), kv)
+		c, err := newDmClient(&identity, receiver, NewSendTracker(kv), nnm,
+			newMockNM(), net, kv, crng, nil)
+		require.NoError(t, err)
+		return c, receiver
+	}
+	clientA, _ := newClient(me, netA)
+	clientB, receiverB := newClient(bob, netB)
+	clientC, receiverC := newClient(carol, netC)
+	clientB.ps.set(me.PubKey, defaultStatus)
+	clientC.ps.set(me.PubKey, defaultStatus)
+
+	params := cmix.GetDefaultCMIXParams()
+	bobMember := GroupMember{bob.PubKey, bob.GetDMToken()}
+	carolMember := GroupMember{carol.PubKey, carol.GetDMToken()}
+	meMember := GroupMember{me.PubKey, me.GetDMToken()}
+
+	groupID, err := clientA.CreateGroup(
+		"group", []GroupMember{bobMember, carolMember})
+	require.NoError(t, err)
+	_, _, err = clientA.SendGroupText(groupID, "Hi", params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, 1)
+
+	// Only the creator and admins can edit the group
+	err = clientC.EditGroup(
+		groupID, "carol's group", []GroupMember{meMember}, nil)
+	require.ErrorIs(t, err, NotGroupAdminErr)
+	err = clientA.EditGroup(groupID, "group", []GroupMember{bobMember},
+		[]ed25519.PublicKey{carol.PubKey})
+	require.Error(t, err)
+
+	// Changes from a member that is not an admin are ignored, but their
+	// message is still received
+	receiverC.UpdateGroup(ModelGroup{
+		GroupID: groupID,
+		Name:    "carol's group",
+		Members: []GroupMember{meMember, bobMember},
+		Admins:  []ed25519.PublicKey{carol.PubKey},
+	})
+	_, _, err = clientC.SendGroupText(groupID, "Hey", params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, 2)
+	require.Equal(t, "group", receiverB.GetGroup(groupID).Name)
+	require.Empty(t, receiverB.GetGroup(groupID).Admins)
+
+	// Changes from the creator are accepted
+	err = clientA.EditGroup(groupID, "new name",
+		[]GroupMember{bobMember, carolMember},
+		[]ed25519.PublicKey{carol.PubKey})
+	require.NoError(t, err)
+	_, _, err = clientA.SendGroupText(groupID, "Renamed", params)
+	require.NoError(t, err)
+	require.Equal(t, "new name", receiverB.GetGroup(groupID).Name)
+	require.Equal(t, []ed25519.PublicKey{carol.PubKey},
+		receiverB.GetGroup(groupID).Admins)
+
+	// Changes from an admin are accepted
+	require.NoError(t, clientC.EditGroup(groupID, "carol's group",
+		[]GroupMember{meMember, bobMember}, []ed25519.PublicKey{carol.PubKey}))
+	_, _, err = clientC.SendGroupText(groupID, "Renamed again", params)
+	require.NoError(t, err)
+	require.Equal(t, "carol's group", receiverB.GetGroup(groupID).Name)
+
+	// A group can only be learned from its creator
+	stream := crng.GetStream()
+	unknownID, err := newGroupID(me.PubKey, stream)
+	stream.Close()
+	require.NoError(t, err)
+	receiverC.UpdateGroup(ModelGroup{
+		GroupID: unknownID,
+		Name:    "fake",
+		Members: []GroupMember{bobMember},
+	})
+	numMsgsB := len(receiverB.Msgs)
+	_, _, err = clientC.SendGroupText(unknownID, "Fake", params)
+	require.NoError(t, err)
+	require.Len(t, receiverB.Msgs, numMsgsB)
+	require.Nil(t, receiverB.GetGroup(unknownID))
+
+	// A group ID can never be the public key of a partner
+	receiverC.UpdateGroup(ModelGroup{
+		GroupID: me.PubKey,
+		Name:    "fake",
+		Members: []GroupMember{bobMember},
+	})
+	_, _, err = clientC.SendGroupText(me.PubKey, "Fake", params)
+	require.Error(t, err)
+	require.Len(t, receiverB.Msgs, numMsgsB)
+	require.Nil(t, receiverB.GetGroup(me.PubKey))
 }
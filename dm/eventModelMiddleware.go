@@ -0,0 +1,200 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+)
+
+// EventModelMiddleware wraps an EventModel so that it can filter or transform
+// events before they reach it. The returned EventModel must pass every event it
+// does not drop on to next.
+//
+// The simplest way to write middleware is to embed next in a struct and only
+// override the methods that need to change. For example, to ignore reactions:
+//
+//	type dropReactions struct{ dm.EventModel }
+//
+//	func (dropReactions) ReceiveReaction(...) uint64 { return 0 }
+//
+//	func DropReactions(next dm.EventModel) dm.EventModel {
+//		return dropReactions{next}
+//	}
+//
+// Optional extensions of next, such as MessageExpirer, are not promoted
+// through an embedded EventModel and must be implemented by the middleware if
+// they are needed.
+type EventModelMiddleware func(next EventModel) EventModel
+
+// WrapEventModel wraps the model in each middleware. The first middleware is
+// the outermost, so it sees each event first.
+func WrapEventModel(
+	model EventModel, middleware ...EventModelMiddleware) EventModel {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		model = middleware[i](model)
+	}
+	return model
+}
+
+// ReceivedMessage contains the arguments of EventModel.Receive,
+// EventModel.ReceiveText, EventModel.ReceiveReply, and
+// EventModel.ReceiveReaction so that they can be inspected and modified by a
+// ReceiveFilter.
+type ReceivedMessage struct {
+	MessageID cryptoMessage.ID
+
+	// ReactionTo is the ID of the message being replied or reacted to. It is
+	// empty for messages that are not replies or reactions.
+	ReactionTo cryptoMessage.ID
+
+	Nickname       string
+	Text           string
+	PartnerPubKey  ed25519.PublicKey
+	SenderPubKey   ed25519.PublicKey
+	PartnerToken   uint32
+	CodesetVersion uint8
+	Timestamp      time.Time
+	Round          rounds.Round
+
+	// Type is the type of the message. Changing it has no effect except on
+	// messages received by EventModel.Receive.
+	Type   MessageType
+	Status Status
+}
+
+// ReceiveFilter is called on each message received by an EventModel wrapped by
+// FilterReceived. It may modify the message before it is passed on. If it
+// returns false, then the message is dropped.
+type ReceiveFilter func(msg *ReceivedMessage) bool
+
+// FilterReceived returns EventModelMiddleware that calls the filter on every
+// message received by the model, for example to drop messages from blocked
+// users or to redact text.
+//
+// Dropped messages never reach the model and 0 is returned as their UUID.
+// Messages sent by this user have the Unsent status when they are first
+// received; dropping them prevents their sent status from being updated.
+func FilterReceived(filter ReceiveFilter) EventModelMiddleware {
+	return func(next EventModel) EventModel {
+		return &receiveFilter{EventModel: next, filter: filter}
+	}
+}
+
+// receiveFilter is an EventModel that passes every received message through a
+// ReceiveFilter.
+type receiveFilter struct {
+	EventModel
+	filter ReceiveFilter
+}
+
+// Receive filters the message before passing it on.
+func (rf *receiveFilter) Receive(messageID cryptoMessage.ID, nickname string,
+	text []byte, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, mType MessageType, status Status) uint64 {
+	msg, ok := rf.apply(messageID, cryptoMessage.ID{}, nickname, string(text),
+		partnerPubKey, senderPubKey, partnerToken, codeset, timestamp, round,
+		mType, status)
+	if !ok {
+		return 0
+	}
+	return rf.EventModel.Receive(msg.MessageID, msg.Nickname, []byte(msg.Text),
+		msg.PartnerPubKey, msg.SenderPubKey, msg.PartnerToken,
+		msg.CodesetVersion, msg.Timestamp, msg.Round, msg.Type, msg.Status)
+}
+
+// ReceiveText filters the message before passing it on.
+func (rf *receiveFilter) ReceiveText(messageID cryptoMessage.ID, nickname,
+	text string, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, status Status) uint64 {
+	msg, ok := rf.apply(messageID, cryptoMessage.ID{}, nickname, text,
+		partnerPubKey, senderPubKey, partnerToken, codeset, timestamp, round,
+		TextType, status)
+	if !ok {
+		return 0
+	}
+	return rf.EventModel.ReceiveText(msg.MessageID, msg.Nickname, msg.Text,
+		msg.PartnerPubKey, msg.SenderPubKey, msg.PartnerToken,
+		msg.CodesetVersion, msg.Timestamp, msg.Round, msg.Status)
+}
+
+// ReceiveReply filters the reply before passing it on.
+func (rf *receiveFilter) ReceiveReply(messageID, reactionTo cryptoMessage.ID,
+	nickname, text string, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, status Status) uint64 {
+	msg, ok := rf.apply(messageID, reactionTo, nickname, text, partnerPubKey,
+		senderPubKey, partnerToken, codeset, timestamp, round, ReplyType,
+		status)
+	if !ok {
+		return 0
+	}
+	return rf.EventModel.ReceiveReply(msg.MessageID, msg.ReactionTo,
+		msg.Nickname, msg.Text, msg.PartnerPubKey, msg.SenderPubKey,
+		msg.PartnerToken, msg.CodesetVersion, msg.Timestamp, msg.Round,
+		msg.Status)
+}
+
+// ReceiveReaction filters the reaction before passing it on.
+func (rf *receiveFilter) ReceiveReaction(messageID,
+	reactionTo cryptoMessage.ID, nickname, reaction string, partnerPubKey,
+	senderPubKey ed25519.PublicKey, partnerToken uint32, codeset uint8,
+	timestamp time.Time, round rounds.Round, status Status) uint64 {
+	msg, ok := rf.apply(messageID, reactionTo, nickname, reaction,
+		partnerPubKey, senderPubKey, partnerToken, codeset, timestamp, round,
+		ReactionType, status)
+	if !ok {
+		return 0
+	}
+	return rf.EventModel.ReceiveReaction(msg.MessageID, msg.ReactionTo,
+		msg.Nickname, msg.Text, msg.PartnerPubKey, msg.SenderPubKey,
+		msg.PartnerToken, msg.CodesetVersion, msg.Timestamp, msg.Round,
+		msg.Status)
+}
+
+// apply builds a ReceivedMessage and passes it through the filter. Returns
+// false if the message is dropped.
+func (rf *receiveFilter) apply(messageID, reactionTo cryptoMessage.ID,
+	nickname, text string, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, mType MessageType, status Status) (
+	*ReceivedMessage, bool) {
+	msg := &ReceivedMessage{
+		MessageID:      messageID,
+		ReactionTo:     reactionTo,
+		Nickname:       nickname,
+		Text:           text,
+		PartnerPubKey:  partnerPubKey,
+		SenderPubKey:   senderPubKey,
+		PartnerToken:   partnerToken,
+		CodesetVersion: codeset,
+		Timestamp:      timestamp,
+		Round:          round,
+		Type:           mType,
+		Status:         status,
+	}
+	return msg, rf.filter(msg)
+}
+
+// needsTracking returns true if the wrapped model needs expiring messages to
+// be tracked.
+func (rf *receiveFilter) needsTracking() bool {
+	return needsTracking(rf.EventModel)
+}
+
+// deleteExpired deletes the expired messages from the wrapped model. Deletions
+// are not filtered.
+func (rf *receiveFilter) deleteExpired(partnerPubKey ed25519.PublicKey,
+	before time.Time, expired []expiringMessage) int {
+	return deleteExpired(rf.EventModel, partnerPubKey, before, expired)
+}
@@ -0,0 +1,92 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+)
+
+// Tests that WrapEventModel applies the middleware in order, with the first
+// middleware seeing events first.
+func TestWrapEventModel(t *testing.T) {
+	var order []string
+	appendText := func(s string) EventModelMiddleware {
+		return FilterReceived(func(msg *ReceivedMessage) bool {
+			order = append(order, s)
+			msg.Text += s
+			return true
+		})
+	}
+
+	model := newMockReceiver()
+	wrapped := WrapEventModel(model, appendText("a"), appendText("b"))
+	wrapped.Receive(cryptoMessage.ID{1}, "nickname", []byte("text"), nil, nil,
+		0, 0, time.Unix(0, 0), rounds.Round{}, TextType, Received)
+
+	if !reflect.DeepEqual([]string{"a", "b"}, order) {
+		t.Errorf("Unexpected middleware order: %v", order)
+	}
+	if len(model.Msgs) != 1 || model.Msgs[0].Message != "textab" {
+		t.Errorf("Unexpected messages: %+v", model.Msgs)
+	}
+}
+
+// Tests that FilterReceived drops messages rejected by the filter and passes on
+// changes made by the filter.
+func TestFilterReceived(t *testing.T) {
+	blocked := ed25519.PublicKey("blocked")
+	partner := ed25519.PublicKey("partner")
+	model := newMockReceiver()
+	wrapped := WrapEventModel(model,
+		FilterReceived(func(msg *ReceivedMessage) bool {
+			return !bytes.Equal(msg.SenderPubKey, blocked)
+		}),
+		FilterReceived(func(msg *ReceivedMessage) bool {
+			if msg.Type == ReplyType {
+				msg.Text = "[redacted]"
+			}
+			return true
+		}))
+
+	uuid := wrapped.ReceiveText(cryptoMessage.ID{1}, "nickname", "text",
+		blocked, blocked, 0, 0, time.Unix(0, 0), rounds.Round{}, Received)
+	if uuid != 0 || len(model.Msgs) != 0 {
+		t.Errorf("Message from blocked user not dropped: %+v", model.Msgs)
+	}
+
+	wrapped.ReceiveReply(cryptoMessage.ID{2}, cryptoMessage.ID{1}, "nickname",
+		"secret", partner, partner, 0, 0, time.Unix(0, 0), rounds.Round{},
+		Received)
+	wrapped.ReceiveReaction(cryptoMessage.ID{3}, cryptoMessage.ID{2},
+		"nickname", "👍", partner, partner, 0, 0, time.Unix(0, 0),
+		rounds.Round{}, Received)
+
+	expected := []mockMessage{
+		{Message: "[redacted]", PubKey: partner, MessageID: cryptoMessage.ID{2},
+			ReplyTo: cryptoMessage.ID{1}},
+		{Message: "👍", PubKey: partner, MessageID: cryptoMessage.ID{3},
+			ReplyTo: cryptoMessage.ID{2}},
+	}
+	if !reflect.DeepEqual(expected, model.Msgs) {
+		t.Errorf("Unexpected messages.\nexpected: %+v\nreceived: %+v",
+			expected, model.Msgs)
+	}
+
+	// Events other than received messages are passed through unchanged
+	wrapped.DeleteMessage(cryptoMessage.ID{3}, partner)
+	if len(model.Deleted) != 1 {
+		t.Errorf("Delete not passed through: %v", model.Deleted)
+	}
+}
@@ -0,0 +1,743 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/emoji"
+	"gitlab.com/elixxir/crypto/dm"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/crypto/nike"
+	"gitlab.com/elixxir/crypto/nike/ecdh"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+	"google.golang.org/protobuf/proto"
+)
+
+// Group DMs
+//
+// A group DM is a conversation between the user and up to MaxGroupMembers
+// other members. Each message sent to the group is wrapped in a GroupMessage,
+// which carries the group's ID, name, and full membership, and is sent as a
+// GroupType DM. The DM is encrypted separately for every member and sent to all
+// of them, and to the user's other devices, in a single round using
+// SendManyWithAssembler.
+//
+// A group ID is made of a prefix, the public key of the creator, and a random
+// nonce. It is longer than a public key so that it can never refer to a DM
+// conversation with a partner, and the creator of a group is known to every
+// member from its ID alone. Only the creator and the admins of a group can
+// change its name, members, and admins. Changes sent by anyone else are
+// ignored.
+//
+// The message ID of a group message is derived from the group ID instead of
+// the recipient's reception ID so that all members see the same message ID and
+// can reply and react to it. On reception, the wrapped message is passed to the
+// EventModel with the group ID as the partner public key, so the group is
+// presented as a single conversation.
+
+const (
+	groupVersion = 0
+
+	// MaxGroupMembers is the maximum number of members in a group DM, not
+	// including the user.
+	MaxGroupMembers = 8
+
+	// SendGroupTag is the base tag used when generating a debug tag for
+	// sending a message to a group.
+	SendGroupTag = "Group"
+
+	// groupIDPrefix is the first byte of every group ID.
+	groupIDPrefix = 'G'
+
+	// groupIDNonceLen is the length of the random nonce in a group ID.
+	groupIDNonceLen = 16
+
+	// groupIDLen is the length of a group ID. It is the prefix followed by the
+	// public key of the creator and the nonce.
+	groupIDLen = 1 + ed25519.PublicKeySize + groupIDNonceLen
+)
+
+// Error messages.
+var (
+	// GroupNotFoundErr is returned when a group DM does not exist in the
+	// EventModel.
+	GroupNotFoundErr = errors.New("group DM not found")
+
+	// NotGroupAdminErr is returned when the user attempts to change a group DM
+	// that they are not the creator or an admin of.
+	NotGroupAdminErr = errors.New("user is not an admin of the group DM")
+)
+
+// CreateGroup creates a new group DM with the given name and members and
+// returns its ID. The group is stored in the EventModel as a single
+// conversation with the group ID as its public key. The user is the creator of
+// the group. Members do not learn about the group until the first message is
+// sent to it.
+func (dc *dmClient) CreateGroup(
+	name string, members []GroupMember) (ed25519.PublicKey, error) {
+	if err := dc.validateGroupMembers(members); err != nil {
+		return nil, err
+	}
+
+	stream := dc.rng.GetStream()
+	groupID, err := newGroupID(dc.me.PubKey, stream)
+	stream.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate group ID")
+	}
+
+	jww.INFO.Printf("[DM] Created group %X with %d members",
+		groupID, len(members))
+	dc.receiver.UpdateGroup(ModelGroup{
+		GroupID: groupID,
+		Name:    name,
+		Members: members,
+	})
+
+	return groupID, nil
+}
+
+// EditGroup replaces the name, members, and admins of the group DM. Only the
+// creator and the admins of the group can edit it. The admins must be members
+// of the group or the user. Members learn about the change with the next
+// message sent to the group.
+//
+// Returns GroupNotFoundErr if the group does not exist and NotGroupAdminErr if
+// the user is not the creator or an admin of the group.
+func (dc *dmClient) EditGroup(groupID ed25519.PublicKey, name string,
+	members []GroupMember, admins []ed25519.PublicKey) error {
+	group := dc.receiver.GetGroup(groupID)
+	if group == nil {
+		return GroupNotFoundErr
+	} else if !group.isAdmin(dc.me.PubKey) {
+		return NotGroupAdminErr
+	}
+
+	if err := dc.validateGroupMembers(members); err != nil {
+		return err
+	}
+
+	edited := ModelGroup{
+		GroupID: groupID,
+		Name:    name,
+		Members: members,
+		Admins:  admins,
+	}
+	for i, admin := range admins {
+		if !admin.Equal(dc.me.PubKey) && !edited.hasMember(admin) {
+			return errors.Errorf("admin %d is not a member: %X", i, admin)
+		}
+	}
+
+	jww.INFO.Printf("[DM] Edited group %X with %d members and %d admins",
+		groupID, len(members), len(admins))
+	dc.receiver.UpdateGroup(edited)
+
+	return nil
+}
+
+// GetGroup returns the group DM with the given ID. Returns GroupNotFoundErr if
+// the group does not exist.
+func (dc *dmClient) GetGroup(groupID ed25519.PublicKey) (ModelGroup, error) {
+	group := dc.receiver.GetGroup(groupID)
+	if group == nil {
+		return ModelGroup{}, GroupNotFoundErr
+	}
+	return *group, nil
+}
+
+// newGroupID generates a new group ID for a group created by the user with the
+// given public key.
+func newGroupID(creator ed25519.PublicKey, rng io.Reader) (
+	ed25519.PublicKey, error) {
+	groupID := make(ed25519.PublicKey, 0, groupIDLen)
+	groupID = append(groupID, groupIDPrefix)
+	groupID = append(groupID, creator...)
+
+	nonce := make([]byte, groupIDNonceLen)
+	if _, err := io.ReadFull(rng, nonce); err != nil {
+		return nil, err
+	}
+
+	return append(groupID, nonce...), nil
+}
+
+// groupCreator returns the public key of the creator of the group. Returns
+// false if the ID is not a valid group ID.
+func groupCreator(groupID ed25519.PublicKey) (ed25519.PublicKey, bool) {
+	if len(groupID) != groupIDLen || groupID[0] != groupIDPrefix {
+		return nil, false
+	}
+	return groupID[1 : 1+ed25519.PublicKeySize], true
+}
+
+// validateGroupMembers returns an error if the list of members is empty, too
+// long, contains duplicates or the user, or contains an invalid public key or
+// token.
+func (dc *dmClient) validateGroupMembers(members []GroupMember) error {
+	if len(members) == 0 || len(members) > MaxGroupMembers {
+		return errors.Errorf("group must have between 1 and %d members, "+
+			"received %d", MaxGroupMembers, len(members))
+	}
+
+	seen := make(map[string]struct{}, len(members))
+	for i, m := range members {
+		if len(m.PubKey) != ed25519.PublicKeySize {
+			return errors.Errorf("invalid public key for member %d: %X",
+				i, m.PubKey)
+		} else if m.Token == 0 {
+			return errors.Errorf("invalid dmToken for member %d: %d",
+				i, m.Token)
+		} else if m.PubKey.Equal(dc.me.PubKey) {
+			return errors.Errorf("member %d is the user", i)
+		} else if _, exists := seen[string(m.PubKey)]; exists {
+			return errors.Errorf("member %d is a duplicate: %X", i, m.PubKey)
+		}
+		seen[string(m.PubKey)] = struct{}{}
+	}
+
+	return nil
+}
+
+// SendGroupText is used to send a formatted message to a group DM.
+func (dc *dmClient) SendGroupText(groupID ed25519.PublicKey, msg string,
+	params cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, error) {
+	tag := makeDebugTag(groupID, []byte(msg), SendMessageTag)
+	jww.INFO.Printf("[DM][%s] SendGroupText(%X)", tag, groupID)
+
+	txt := &Text{
+		Version: textVersion,
+		Text:    msg,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	txtMarshaled, err := proto.Marshal(txt)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	return dc.SendGroup(groupID, TextType, txtMarshaled, params)
+}
+
+// SendGroupReply is used to send a formatted reply to a message in a group DM.
+func (dc *dmClient) SendGroupReply(groupID ed25519.PublicKey, msg string,
+	replyTo cryptoMessage.ID, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, error) {
+	tag := makeDebugTag(groupID, []byte(msg), SendReplyTag)
+	jww.INFO.Printf("[DM][%s] SendGroupReply(%X, to %s)", tag, groupID,
+		replyTo)
+
+	txt := &Text{
+		Version:        textVersion,
+		Text:           msg,
+		ReplyMessageID: replyTo[:],
+	}
+
+	params = params.SetDebugTag(tag)
+
+	txtMarshaled, err := proto.Marshal(txt)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	return dc.SendGroup(groupID, ReplyType, txtMarshaled, params)
+}
+
+// SendGroupReaction is used to send a reaction to a message in a group DM. The
+// reaction must be a single emoji with no other characters, and will be
+// rejected otherwise.
+func (dc *dmClient) SendGroupReaction(groupID ed25519.PublicKey,
+	reaction string, reactTo cryptoMessage.ID, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, error) {
+	tag := makeDebugTag(groupID, []byte(reaction), SendReactionTag)
+	jww.INFO.Printf("[DM][%s] SendGroupReaction(%X, to %s)", tag, groupID,
+		reactTo)
+
+	if err := emoji.ValidateReaction(reaction); err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	react := &Reaction{
+		Version:           reactionVersion,
+		Reaction:          reaction,
+		ReactionMessageID: reactTo[:],
+	}
+
+	params = params.SetDebugTag(tag)
+
+	reactMarshaled, err := proto.Marshal(react)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	return dc.SendGroup(groupID, ReactionType, reactMarshaled, params)
+}
+
+// SendGroup is used to send a raw message to a group DM. In general, it should
+// be wrapped in a function that defines the wire protocol. Only message types
+// that are allowed in groups can be sent (see MessageType.allowedInGroup).
+func (dc *dmClient) SendGroup(groupID ed25519.PublicKey,
+	messageType MessageType, msg []byte, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, error) {
+	if !messageType.allowedInGroup() {
+		return cryptoMessage.ID{}, rounds.Round{}, errors.Errorf(
+			"message type %s cannot be sent to a group", messageType)
+	}
+
+	group := dc.receiver.GetGroup(groupID)
+	if group == nil {
+		return cryptoMessage.ID{}, rounds.Round{}, GroupNotFoundErr
+	}
+
+	members := make([]*GroupMessageMember, 0, len(group.Members)+1)
+	members = append(members, &GroupMessageMember{
+		PubKey: dc.me.PubKey,
+		Token:  dc.myToken,
+	})
+	for _, m := range group.Members {
+		members = append(members, &GroupMessageMember{
+			PubKey: m.PubKey,
+			Token:  m.Token,
+		})
+	}
+
+	admins := make([][]byte, len(group.Admins))
+	for i, admin := range group.Admins {
+		admins[i] = admin
+	}
+
+	groupMsg := &GroupMessage{
+		Version:     groupVersion,
+		GroupID:     groupID,
+		Name:        group.Name,
+		Members:     members,
+		PayloadType: uint32(messageType),
+		Payload:     msg,
+		Admins:      admins,
+	}
+
+	groupMarshaled, err := proto.Marshal(groupMsg)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	return dc.sendGroupMessage(group, messageType, groupMarshaled, params)
+}
+
+// sendGroupMessage sends the marshalled GroupMessage, containing a message of
+// the given MessageType, to all members of the group in a single round. The
+// message is tracked in the SendTracker so that it is shown in the group
+// conversation before it is sent.
+func (dc *dmClient) sendGroupMessage(group *ModelGroup,
+	messageType MessageType, payload []byte, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, error) {
+
+	// Note: We log sends on exit, and append what happened to the message
+	// this cuts down on clutter in the log.
+	sendPrint := fmt.Sprintf("[DM][%s] Sending from %s to group %X (%d "+
+		"members) at %s", params.DebugTag,
+		base64.StdEncoding.EncodeToString(dc.me.PubKey), group.GroupID,
+		len(group.Members), netTime.Now())
+	defer func() { jww.INFO.Println(sendPrint) }()
+
+	rng := dc.rng.GetStream()
+	defer rng.Close()
+
+	nickname, _ := dc.nm.GetNickname()
+
+	// Generate random nonce to be used for message ID generation. This makes
+	// it so two identical messages sent on the same round have different
+	// message IDs.
+	msgNonce := make([]byte, messageNonceSize)
+	if n, err := rng.Read(msgNonce); err != nil || n != messageNonceSize {
+		sendPrint += fmt.Sprintf(", failed to generate nonce: %+v", err)
+		return cryptoMessage.ID{}, rounds.Round{},
+			errors.Errorf("Failed to generate nonce: %+v", err)
+	}
+
+	directMessage := &DirectMessage{
+		DMToken:        dc.myToken,
+		PayloadType:    uint32(GroupType),
+		Payload:        payload,
+		Nickname:       nickname,
+		Nonce:          msgNonce,
+		LocalTimestamp: netTime.Now().UnixNano(),
+	}
+
+	if params.DebugTag == cmix.DefaultDebugTag {
+		params.DebugTag = directMessageDebugTag
+	}
+
+	sendPrint += fmt.Sprintf(", pending send %s", netTime.Now())
+	uuid, err := dc.st.DenotePendingSend(
+		group.GroupID, dc.me.PubKey, 0, GroupType, directMessage)
+	if err != nil {
+		sendPrint += fmt.Sprintf(", pending send failed %s", err.Error())
+		if errDenote := dc.st.FailedSend(uuid); errDenote != nil {
+			sendPrint += fmt.Sprintf(
+				", failed to denote failed dm send: %s", errDenote.Error())
+		}
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	rnd, err := sendGroup(dc.net, dc.selfReceptionID, group.Members,
+		dc.me.Privkey, dc.privateKey, dc.publicKey, directMessage, params,
+		dc.rng)
+	if err != nil {
+		sendPrint += fmt.Sprintf(", err on send: %+v", err)
+		if errDenote := dc.st.FailedSend(uuid); errDenote != nil {
+			sendPrint += fmt.Sprintf(
+				", failed to denote failed dm send: %s", errDenote.Error())
+		}
+		return cryptoMessage.ID{}, rounds.Round{}, err
+	}
+
+	// Now that we have a round ID, derive the msgID
+	directMessage.RoundID = uint64(rnd.ID)
+	msgID := cryptoMessage.DeriveDirectMessageID(
+		deriveGroupReceptionID(group.GroupID), directMessage)
+
+	sendPrint += fmt.Sprintf(", send rnd %s MsgID %s", rnd.ID, msgID)
+
+	// Sending to the group accepts any message requests from its members
+	for _, m := range group.Members {
+		dc.acceptOnSend(m.PubKey)
+	}
+
+	if err = dc.st.Sent(uuid, msgID, rnd); err != nil {
+		sendPrint += fmt.Sprintf(", dm send denote failed: %s ", err.Error())
+	}
+
+	if messageType.isStored() {
+		err = dc.retention.track(group.GroupID, msgID, dc.me.PubKey,
+			netTime.Now())
+		if err != nil {
+			sendPrint += fmt.Sprintf(
+				", failed to track message expiry: %s", err.Error())
+		}
+	}
+
+	return msgID, rnd, nil
+}
+
+// sendGroup encrypts the direct message for each member of the group and for
+// the user's other devices and sends all of them in the same round.
+func sendGroup(net cMixClient, myID *id.ID, members []GroupMember,
+	myEdwardsPrivKey ed25519.PrivateKey, myPrivateKey nike.PrivateKey,
+	myPublicKey nike.PublicKey, msg *DirectMessage, params cmix.CMIXParams,
+	rngGenerator *fastRNG.StreamGenerator) (rounds.Round, error) {
+
+	mt := GroupType.Marshal()
+	recipients := make([]*id.ID, 0, len(members)+1)
+	partnerPubKeys := make([]nike.PublicKey, len(members))
+	services := make([]message.CompressedService, len(members))
+	for i, m := range members {
+		partnerPubKeys[i] = ecdh.Edwards2EcdhNikePublicKey(m.PubKey)
+		recipients = append(recipients,
+			deriveReceptionID(partnerPubKeys[i].Bytes(), m.Token))
+		services[i] = message.CompressedService{
+			Identifier: m.PubKey,
+			Tags:       []string{dm.MakeSenderSihTag(m.PubKey, myEdwardsPrivKey)},
+			Metadata:   mt[:],
+		}
+	}
+	recipients = append(recipients, myID)
+
+	assemble := func(rid id.Round) ([]cmix.TargetedCmixMessage, error) {
+		rng := rngGenerator.GetStream()
+		defer rng.Close()
+
+		// Every member receives the same message so that they all derive
+		// the same message ID
+		dmMsg := &DirectMessage{
+			RoundID:        uint64(rid),
+			DMToken:        msg.DMToken,
+			PayloadType:    msg.PayloadType,
+			Payload:        msg.Payload,
+			Nickname:       msg.Nickname,
+			Nonce:          msg.Nonce,
+			LocalTimestamp: msg.LocalTimestamp,
+		}
+		dmSerial, err := proto.Marshal(dmMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		payloadLen := calcDMPayloadLen(net)
+		msgs := make([]cmix.TargetedCmixMessage, 0, len(recipients))
+		for i := range members {
+			ciphertext := dm.Cipher.Encrypt(dmSerial, myPrivateKey,
+				partnerPubKeys[i], rng, payloadLen)
+
+			fpBytes, encryptedPayload, mac, err2 := createCMIXFields(
+				ciphertext, payloadLen, rng)
+			if err2 != nil {
+				return nil, err2
+			}
+
+			msgs = append(msgs, cmix.TargetedCmixMessage{
+				Recipient:   recipients[i],
+				Payload:     encryptedPayload,
+				Fingerprint: format.NewFingerprint(fpBytes),
+				Service:     services[i],
+				Mac:         mac,
+			})
+		}
+
+		// SELF SEND
+		// The self send is encrypted to the user's own key since the group
+		// has no single partner
+		dmMsg.SelfRoundID = uint64(rid)
+		selfDMSerial, err := proto.Marshal(dmMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		selfCiphertext, err := dm.Cipher.EncryptSelf(
+			selfDMSerial, myPrivateKey, myPublicKey, payloadLen)
+		if err != nil {
+			return nil, err
+		}
+
+		fpBytes, encryptedPayload, mac, err := createCMIXFields(
+			selfCiphertext, payloadLen, rng)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, cmix.TargetedCmixMessage{
+			Recipient:   myID,
+			Payload:     encryptedPayload,
+			Fingerprint: format.NewFingerprint(fpBytes),
+			Service:     createRandomService(rng),
+			Mac:         mac,
+		})
+
+		return msgs, nil
+	}
+
+	rnd, _, err := net.SendManyWithAssembler(recipients, assemble, params)
+	return rnd, err
+}
+
+// receiveGroup unwraps a message sent to a group DM, updates the group's
+// membership in the EventModel, and processes the wrapped message as part of
+// the group conversation.
+//
+// The group is only updated if the sender is the creator or an admin of the
+// group. Changes from other members are ignored, but their message is still
+// received. A group that is not yet known can only be learned from its
+// creator.
+func (r *receiver) receiveGroup(messageID cryptoMessage.ID,
+	messageType MessageType, nickname string, content []byte,
+	partnerPubKey, senderPubKey ed25519.PublicKey, timestamp time.Time,
+	round rounds.Round, status Status) (uint64, error) {
+	groupMsg := &GroupMessage{}
+	if err := proto.Unmarshal(content, groupMsg); err != nil {
+		return 0, errors.Wrapf(err,
+			"failed unmarshal DM %s from %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	groupID := ed25519.PublicKey(groupMsg.GroupID)
+	innerType := MessageType(groupMsg.PayloadType)
+	creator, valid := groupCreator(groupID)
+	if !valid {
+		return 0, errors.Errorf("invalid group ID %X in DM %s from %X",
+			groupID, messageID, senderPubKey)
+	} else if !innerType.allowedInGroup() {
+		return 0, errors.Errorf("message type %s not allowed in group DM "+
+			"%s from %X", innerType, messageID, senderPubKey)
+	}
+
+	// Get all members except the user and ensure both the user and the sender
+	// are members
+	var isMember, fromMember bool
+	members := make([]GroupMember, 0, len(groupMsg.Members))
+	for _, m := range groupMsg.Members {
+		pubKey := ed25519.PublicKey(m.PubKey)
+		if pubKey.Equal(senderPubKey) {
+			fromMember = true
+		}
+		if pubKey.Equal(r.c.me.PubKey) {
+			isMember = true
+			continue
+		}
+		members = append(members, GroupMember{pubKey, m.Token})
+	}
+	if !isMember || !fromMember {
+		return 0, errors.Errorf("dropping group DM %s from %X: user or "+
+			"sender is not a member of group %X", messageID, senderPubKey,
+			groupID)
+	}
+
+	admins := make([]ed25519.PublicKey, len(groupMsg.Admins))
+	for i, admin := range groupMsg.Admins {
+		if len(admin) != ed25519.PublicKeySize {
+			return 0, errors.Errorf("invalid admin %X in DM %s from %X",
+				admin, messageID, senderPubKey)
+		}
+		admins[i] = admin
+	}
+
+	// Only members of an existing group can send to it and a new group can
+	// only be learned from its creator once the user has accepted them.
+	group := r.api.GetGroup(groupID)
+	fromMe := senderPubKey.Equal(r.c.me.PubKey)
+	if group != nil && !fromMe && !group.hasMember(senderPubKey) {
+		return 0, errors.Errorf("dropping group DM %s from %X: sender is "+
+			"not a member of group %X", messageID, senderPubKey, groupID)
+	} else if group == nil && !senderPubKey.Equal(creator) {
+		return 0, errors.Errorf("dropping group DM %s from %X: sender is "+
+			"not the creator of unknown group %X", messageID, senderPubKey,
+			groupID)
+	} else if group == nil && !fromMe && !r.c.isAccepted(senderPubKey) {
+		jww.INFO.Printf("[DM] Dropping %s in new group %X from user that "+
+			"has not been accepted: %X", innerType, groupID, senderPubKey)
+		return 0, nil
+	}
+
+	// Pending sends are skipped since the group is updated locally before
+	// sending
+	received := ModelGroup{
+		GroupID: groupID,
+		Name:    groupMsg.Name,
+		Members: members,
+		Admins:  admins,
+	}
+	if status != Unsent && (group == nil || !group.equal(received)) {
+		if group != nil && !group.isAdmin(senderPubKey) {
+			jww.WARN.Printf("[DM] Ignoring changes to group %X in DM %s "+
+				"from %X: sender is not an admin", groupID, messageID,
+				senderPubKey)
+		} else {
+			jww.INFO.Printf("[DM] Updating group %X with %d members and %d "+
+				"admins", groupID, len(members), len(admins))
+			r.api.UpdateGroup(received)
+		}
+	}
+
+	return r.receiveMessage(messageID, innerType, nickname, groupMsg.Payload,
+		0, groupID, senderPubKey, timestamp, receptionID.EphemeralIdentity{},
+		round, status)
+}
+
+// deriveMessageID derives the message ID of the direct message received on
+// the reception ID. Messages sent to a group DM derive their ID from the group
+// ID instead so that all members share the same message ID.
+func deriveMessageID(
+	receptionID *id.ID, directMsg *DirectMessage) cryptoMessage.ID {
+	if MessageType(directMsg.PayloadType) == GroupType {
+		groupMsg := &GroupMessage{}
+		err := proto.Unmarshal(directMsg.Payload, groupMsg)
+		if _, valid := groupCreator(groupMsg.GroupID); err == nil && valid {
+			receptionID = deriveGroupReceptionID(groupMsg.GroupID)
+		}
+	}
+	return cryptoMessage.DeriveDirectMessageID(receptionID, directMsg)
+}
+
+// deriveGroupReceptionID returns the ID used to derive the message IDs of
+// messages sent to the group. Nothing is ever sent to this ID.
+func deriveGroupReceptionID(groupID ed25519.PublicKey) *id.ID {
+	return deriveReceptionID(groupID, 0)
+}
+
+// allowedInGroup returns true if messages of the MessageType can be sent to a
+// group DM.
+func (mt MessageType) allowedInGroup() bool {
+	switch mt {
+	case TextType, ReplyType, ReactionType, SilentType, InvitationType,
+		DeleteType:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasMember returns true if the public key belongs to a member of the group.
+func (mg *ModelGroup) hasMember(pubKey ed25519.PublicKey) bool {
+	for _, m := range mg.Members {
+		if m.PubKey.Equal(pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdmin returns true if the public key belongs to the creator or an admin of
+// the group.
+func (mg *ModelGroup) isAdmin(pubKey ed25519.PublicKey) bool {
+	if creator, _ := groupCreator(mg.GroupID); creator.Equal(pubKey) {
+		return true
+	}
+	for _, admin := range mg.Admins {
+		if admin.Equal(pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal returns true if both groups have the same name, members, and admins.
+// The order of members and admins is ignored.
+func (mg *ModelGroup) equal(group ModelGroup) bool {
+	return mg.Name == group.Name && equalMembers(mg.Members, group.Members) &&
+		equalAdmins(mg.Admins, group.Admins)
+}
+
+// equalAdmins returns true if both lists contain the same public keys, in any
+// order.
+func equalAdmins(a, b []ed25519.PublicKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keys := make(map[string]struct{}, len(a))
+	for _, pubKey := range a {
+		keys[string(pubKey)] = struct{}{}
+	}
+	for _, pubKey := range b {
+		if _, exists := keys[string(pubKey)]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// equalMembers returns true if both lists contain the same members, in any
+// order.
+func equalMembers(a, b []GroupMember) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	tokens := make(map[string]uint32, len(a))
+	for _, m := range a {
+		tokens[string(m.PubKey)] = m.Token
+	}
+	for _, m := range b {
+		if token, exists := tokens[string(m.PubKey)]; !exists || token != m.Token {
+			return false
+		}
+	}
+	return true
+}
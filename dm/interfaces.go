@@ -19,6 +19,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/message"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	clientNotif "gitlab.com/elixxir/client/v4/notifications"
+	"gitlab.com/elixxir/client/v4/stoppable"
 	"gitlab.com/elixxir/crypto/codename"
 	"gitlab.com/elixxir/crypto/fastRNG"
 	cryptoMessage "gitlab.com/elixxir/crypto/message"
@@ -55,21 +56,106 @@ type Client interface {
 	// GetBlockedPartners returns all partners who are blocked by this user.
 	GetBlockedPartners() []ed25519.PublicKey
 
+	// AcceptMessageRequest accepts the pending message request from the
+	// partner. Returns NoMessageRequestErr if there is no pending request.
+	AcceptMessageRequest(partnerPubKey ed25519.PublicKey) error
+
+	// DeclineMessageRequest declines the pending message request from the
+	// partner. Returns NoMessageRequestErr if there is no pending request.
+	DeclineMessageRequest(partnerPubKey ed25519.PublicKey) error
+
+	// IsMessageRequest indicates if the partner has a pending message request.
+	IsMessageRequest(partnerPubKey ed25519.PublicKey) bool
+
+	// GetMessageRequests returns all partners with a pending message request.
+	GetMessageRequests() []ed25519.PublicKey
+
 	// GetNotificationLevel returns the notification level for the given channel.
 	GetNotificationLevel(
 		partnerPubKey ed25519.PublicKey) (NotificationLevel, error)
 
 	// SetMobileNotificationsLevel sets the notification level for the given DM
-	// conversation partner.
+	// conversation partner. Returns PendingMessageRequestErr if the partner has
+	// a pending message request.
 	SetMobileNotificationsLevel(
 		partnerPubKey ed25519.PublicKey, level NotificationLevel) error
 
+	// SetReadReceipts enables or disables sending read receipts to the
+	// partner. Read receipts are enabled for all partners by default, but are
+	// only sent when MarkRead is called.
+	SetReadReceipts(partnerPubKey ed25519.PublicKey, enabled bool)
+
+	// ReadReceiptsEnabled returns true if read receipts are sent to the
+	// partner. It returns false if read receipts are disabled for the partner
+	// or if the partner is blocked or has a pending message request.
+	ReadReceiptsEnabled(partnerPubKey ed25519.PublicKey) bool
+
+	// GetRetention returns the retention timer set on the conversation with
+	// the partner. Returns zero if messages are kept forever.
+	GetRetention(partnerPubKey ed25519.PublicKey) time.Duration
+
+	// CreateGroup creates a new group DM with the given name and members and
+	// returns its ID. The group is stored in the EventModel as a single
+	// conversation with the group ID as its public key. The user is the
+	// creator of the group. Members do not learn about the group until the
+	// first message is sent to it.
+	CreateGroup(name string, members []GroupMember) (ed25519.PublicKey, error)
+
+	// EditGroup replaces the name, members, and admins of the group DM. Only
+	// the creator and the admins of the group can edit it. Members learn about
+	// the change with the next message sent to the group. Returns
+	// GroupNotFoundErr if the group does not exist and NotGroupAdminErr if
+	// the user is not the creator or an admin of the group.
+	EditGroup(groupID ed25519.PublicKey, name string, members []GroupMember,
+		admins []ed25519.PublicKey) error
+
+	// GetGroup returns the group DM with the given ID. Returns
+	// GroupNotFoundErr if the group does not exist.
+	GetGroup(groupID ed25519.PublicKey) (ModelGroup, error)
+
+	// ScheduleSend queues a raw direct message to be sent to the partner at
+	// sendAt. Scheduled sends are saved to storage and are sent after a
+	// restart. When the message is sent or fails, Callbacks.ScheduledSendUpdate
+	// is called. Returns InvalidScheduleTimeErr if sendAt is not in the
+	// future.
+	ScheduleSend(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		messageType MessageType, msg []byte, sendAt time.Time,
+		params cmix.CMIXParams) (ScheduledSend, error)
+
+	// ScheduleText queues a text message to be sent to the partner at sendAt.
+	// See ScheduleSend for details on scheduling.
+	ScheduleText(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		msg string, sendAt time.Time, params cmix.CMIXParams) (
+		ScheduledSend, error)
+
+	// GetScheduledSends returns the messages queued to be sent to the partner,
+	// sorted by the time they are sent. If partnerPubKey is nil, then the
+	// messages queued for all partners are returned.
+	GetScheduledSends(partnerPubKey ed25519.PublicKey) []ScheduledSend
+
+	// CancelScheduledSend removes the message from the queue so that it is
+	// never sent. Returns ScheduledSendNotFoundErr if the message does not
+	// exist or was already sent.
+	CancelScheduledSend(sendID uint64) error
+
+	// RescheduleSend changes the time the queued message is sent at. Returns
+	// ScheduledSendNotFoundErr if the message does not exist or was already
+	// sent and InvalidScheduleTimeErr if sendAt is not in the future.
+	RescheduleSend(sendID uint64, sendAt time.Time) error
+
+	// StartProcesses starts the thread that deletes messages once the
+	// retention timer of their conversation expires and the scheduled sends.
+	// This function adheres to the [xxdk.Service] type.
+	StartProcesses() (stoppable.Stoppable, error)
+
 	NickNameManager
 }
 
 // Sender implementers allow the API user to send to a given partner over
 // cMix.
 type Sender interface {
+	GroupSender
+
 	// SendText is used to send a formatted message to another user.
 	SendText(partnerPubKey ed25519.PublicKey, partnerToken uint32,
 		msg string, params cmix.CMIXParams) (
@@ -103,6 +189,21 @@ type Sender interface {
 		host string, params cmix.CMIXParams) (
 		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
 
+	// SendChannelAdminKey is used to send to a DM partner the PEM-encoded
+	// admin key of a channel so that they can become the channel's owner. The
+	// message is never stored by either side; the partner passes the key to
+	// their registered AdminKeyReceiver. It adheres to
+	// channels.AdminKeySender.
+	SendChannelAdminKey(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		channelID *id.ID, adminKey []byte, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// RegisterAdminKeyReceiver registers the receiver that channel admin keys
+	// received from partners are passed to, usually the channels manager.
+	// Keys received before a receiver is registered are held in memory and
+	// passed to it on registration.
+	RegisterAdminKeyReceiver(akr AdminKeyReceiver)
+
 	// SendSilent is used to send to a channel a message with no notifications.
 	// Its primary purpose is to communicate new nicknames without calling
 	// SendMessage.
@@ -113,6 +214,42 @@ type Sender interface {
 		params cmix.CMIXParams) (
 		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
 
+	// SendTyping is used to signal to the partner that the user started or
+	// stopped typing. Typing indicators are sent at low priority, are only
+	// valid for TypingTimeout, and are never stored. They are delivered to the
+	// partner via [Callbacks.UserTyping].
+	//
+	// While the user is typing, the UI should call SendTyping with typing set
+	// to true every TypingInterval. Sending more often returns
+	// TypingRateLimitErr, which can be safely ignored.
+	SendTyping(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		typing bool, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// MarkRead marks all messages received from the partner, up to and
+	// including upTo, as read in the local database. If read receipts are
+	// enabled for the partner, then a read receipt is sent to them so that
+	// the messages are marked as read on their side too. Read receipts are
+	// never sent to blocked partners. If no receipt is sent, then MarkRead
+	// returns zero values and no error.
+	MarkRead(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		upTo cryptoMessage.ID, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SetRetention sets the retention timer of the conversation with the
+	// partner and announces it to them. Every message in the conversation,
+	// including those sent or received before the timer is set, is deleted
+	// from the EventModel once it is older than the timer. A retention of zero
+	// disables the timer. The retention must be zero or at least
+	// MinRetention.
+	//
+	// Event models that do not implement MessageExpirer are only notified,
+	// through EventModel.DeleteMessage, of messages sent or received while the
+	// timer is set.
+	SetRetention(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		retention time.Duration, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
 	// DeleteMessage sends a message to the partner to delete a message this
 	// user sent. Also deletes it from the local database.
 	DeleteMessage(partnerPubKey ed25519.PublicKey, partnerToken uint32,
@@ -136,6 +273,34 @@ type Sender interface {
 // ReceiverBuilder initialises the event model using the given path.
 type ReceiverBuilder func(path string) (EventModel, error)
 
+// GroupSender implementers allow the API user to send to all members of a
+// group DM over cMix. Each message is sent to every member in the same round
+// and all members see it under the same message ID.
+type GroupSender interface {
+	// SendGroupText is used to send a formatted message to a group DM.
+	SendGroupText(groupID ed25519.PublicKey, msg string,
+		params cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, error)
+
+	// SendGroupReply is used to send a formatted reply to a message in a
+	// group DM.
+	SendGroupReply(groupID ed25519.PublicKey, msg string,
+		replyTo cryptoMessage.ID, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, error)
+
+	// SendGroupReaction is used to send a reaction to a message in a group DM.
+	// The reaction must be a single emoji with no other characters, and will
+	// be rejected otherwise.
+	SendGroupReaction(groupID ed25519.PublicKey, reaction string,
+		reactTo cryptoMessage.ID, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, error)
+
+	// SendGroup is used to send a raw message to a group DM. In general, it
+	// should be wrapped in a function that defines the wire protocol. Only
+	// message types that are allowed in groups can be sent.
+	SendGroup(groupID ed25519.PublicKey, messageType MessageType, msg []byte,
+		params cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, error)
+}
+
 // EventModel is all of the reception functions an API user must implement.
 // This is similar to the event model system in channels.
 type EventModel interface {
@@ -244,12 +409,31 @@ type EventModel interface {
 	UpdateSentStatus(uuid uint64, messageID cryptoMessage.ID,
 		timestamp time.Time, round rounds.Round, status Status)
 
+	// UpdateReadStatus is called when messages in a conversation have been
+	// read. All messages in the conversation with the partner that were sent
+	// by senderPubKey, up to and including the message upTo, should have their
+	// ReceiptStatus set to Read.
+	//
+	// When the partner reads messages sent by this user, senderPubKey is this
+	// user's public key. When this user reads messages sent by the partner,
+	// senderPubKey is the partner's public key.
+	//
+	// If the message upTo does not exist, then no update should be made.
+	UpdateReadStatus(partnerPubKey, senderPubKey ed25519.PublicKey,
+		upTo cryptoMessage.ID)
+
 	// DeleteMessage deletes the message with the given message.ID belonging to
 	// the sender. If the message exists and belongs to the sender, then it is
 	// deleted and DeleteMessage returns true. If it does not exist, it returns
 	// false.
 	DeleteMessage(messageID cryptoMessage.ID, senderPubKey ed25519.PublicKey) bool
 
+	// UpdateMessageRequest marks whether the conversation with the partner is
+	// a pending message request. Request conversations should be kept in a
+	// separate inbox until they are accepted. It is called when a request is
+	// received and when it is accepted, declined, or blocked.
+	UpdateMessageRequest(partnerPubKey ed25519.PublicKey, pending bool)
+
 	// GetConversation returns any conversations held by the
 	// model (receiver)
 	GetConversation(senderPubKey ed25519.PublicKey) *ModelConversation
@@ -257,6 +441,30 @@ type EventModel interface {
 	// GetConversations returns any conversations held by the
 	// model (receiver)
 	GetConversations() []ModelConversation
+
+	// UpdateGroup creates or replaces the group DM. The group should be
+	// presented as a single conversation with the group ID as its public key
+	// and the group name as its nickname. All messages sent to the group are
+	// received with the group ID as their partner public key. A conversation
+	// with a partner must never be replaced by a group.
+	UpdateGroup(group ModelGroup)
+
+	// GetGroup returns the group DM with the given ID. Returns nil if the
+	// group does not exist.
+	GetGroup(groupID ed25519.PublicKey) *ModelGroup
+}
+
+// MessageExpirer is an optional extension of EventModel that deletes messages
+// by age. If the EventModel implements it, then messages are deleted with
+// DeleteMessagesBefore once the retention timer of their conversation expires.
+// Otherwise, the client keeps track of each message sent or received while the
+// timer is set and deletes each one with EventModel.DeleteMessage once it
+// expires.
+type MessageExpirer interface {
+	// DeleteMessagesBefore deletes every message in the conversation with the
+	// partner that has a timestamp before the given time, except for messages
+	// that have not been sent yet. Returns the number of messages deleted.
+	DeleteMessagesBefore(partnerPubKey ed25519.PublicKey, before time.Time) int
 }
 
 // cmixClient are the required cmix functions we need for direct messages
@@ -324,6 +532,13 @@ type SendTracker interface {
 	StopTracking(msgID cryptoMessage.ID, round rounds.Round) bool
 }
 
+// AdminKeyReceiver receives the channel admin keys sent to this user with
+// [Client.SendChannelAdminKey]. It is implemented by channels.Manager.
+type AdminKeyReceiver interface {
+	// ReceiveChannelAdminKey imports the PEM-encoded admin key of the channel.
+	ReceiveChannelAdminKey(channelID *id.ID, adminKey []byte) error
+}
+
 // Callbacks is an interface that a caller can adhere to in order to get updates
 // when sync events occur.
 type Callbacks interface {
@@ -344,4 +559,21 @@ type Callbacks interface {
 	// unblocked. It is also called on initial registration for every blocked
 	// user.
 	BlockedUser(user ed25519.PublicKey, blocked bool)
+
+	// MessageRequest is called anytime a message request is received from a
+	// partner or a pending request is accepted, declined, or blocked. It is
+	// also called on initial registration for every pending request.
+	MessageRequest(partnerPubKey ed25519.PublicKey, pending bool)
+
+	// UserTyping is called every time a partner starts or stops typing. The UI
+	// should stop showing the partner as typing if no new indicator is
+	// received from them within TypingTimeout. Typing indicators are never
+	// stored.
+	UserTyping(partnerPubKey ed25519.PublicKey, nickname string, typing bool)
+
+	// ScheduledSendUpdate is called when a scheduled send is sent or fails to
+	// send. On success, err is nil and messageID is the ID of the sent
+	// message.
+	ScheduledSendUpdate(sendID uint64, partnerPubKey ed25519.PublicKey,
+		messageID cryptoMessage.ID, err error)
 }
\ No newline at end of file
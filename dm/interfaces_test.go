@@ -10,6 +10,7 @@ package dm
 import (
 	"bytes"
 	"crypto/ed25519"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	cryptoMessage "gitlab.com/elixxir/crypto/message"
 	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/id/ephemeral"
 )
@@ -28,27 +30,36 @@ import (
 func createLinkedNets(t testing.TB) (*mockClient, *mockClient) {
 	client1 := newMockClient(t)
 	client2 := newMockClient(t)
-
-	client1.otherClient = client2
-	client2.otherClient = client1
+	linkNets(client1, client2)
 	return client1, client2
 }
 
+// linkNets links all the clients together so that a message sent by any of
+// them is delivered to whichever client registered the recipient.
+func linkNets(clients ...*mockClient) {
+	for _, c := range clients {
+		c.peers = clients
+	}
+}
+
 // newMockClient creates a client that can send messages
 func newMockClient(t testing.TB) *mockClient {
 	return &mockClient{
-		rndID:       uint64(0),
-		processors:  make(map[id.ID]message.Processor),
-		otherClient: nil,
+		rndID:      uint64(0),
+		processors: make(map[id.ID]message.Processor),
+		peers:      nil,
 		t: t,
 	}
 }
 
 type mockClient struct {
-	rndID       uint64
-	processors  map[id.ID]message.Processor
-	otherClient *mockClient
+	rndID      uint64
+	processors map[id.ID]message.Processor
+	peers      []*mockClient
 	t testing.TB
+
+	// healthCallbacks are called by setHealthy
+	healthCallbacks []func(bool)
 }
 
 func (mc *mockClient) GetMaxMessageLength() int {
@@ -58,30 +69,30 @@ func (mc *mockClient) GetMaxMessageLength() int {
 
 // This calls the assembler (encryption) function and returns mocked
 // but valid round IDs, etc.
-// When otherClient is not nil, this sends the messages to the linked
-// receiver.
+// When peers is not empty, this sends the messages to the linked
+// receivers.
 func (mc *mockClient) SendManyWithAssembler(recipients []*id.ID,
 	assembler cmix.ManyMessageAssembler, _ cmix.CMIXParams) (
 	rounds.Round, []ephemeral.Id, error) {
-	jww.INFO.Printf(
-		"SendManyWithAssembler: %s, %s", recipients[0], recipients[1])
+	jww.INFO.Printf("SendManyWithAssembler: %v", recipients)
 	mc.rndID += 1
-	id1, _, _, err := ephemeral.GetId(recipients[0], 8, time.Now().Unix())
-	if err != nil {
-		return rounds.Round{}, nil, err
+	ids := make([]ephemeral.Id, len(recipients))
+	for i, recipient := range recipients {
+		var err error
+		ids[i], _, _, err = ephemeral.GetId(recipient, 8, time.Now().Unix())
+		if err != nil {
+			return rounds.Round{}, nil, err
+		}
 	}
-	id2, _, _, err := ephemeral.GetId(recipients[1], 8, time.Now().Unix())
-	if err != nil {
-		return rounds.Round{}, nil, err
+	rnd := rounds.Round{
+		ID:         id.Round(mc.rndID),
+		Timestamps: map[states.Round]time.Time{states.QUEUED: time.Now()},
 	}
-	ids := []ephemeral.Id{id1, id2}
-	rnd := rounds.Round{ID: id.Round(mc.rndID)}
 	msgs, err := assembler(rnd.ID)
 	if err != nil {
 		mc.t.Fatal(err)
 	}
-	clients := []*mockClient{mc.otherClient, mc}
-	if mc.otherClient != nil {
+	if len(mc.peers) > 0 {
 		for i, recipient := range recipients {
 			msg := format.NewMessage(2048)
 			msg.SetKeyFP(msgs[i].Fingerprint)
@@ -96,13 +107,28 @@ func (mc *mockClient) SendManyWithAssembler(recipients []*id.ID,
 				EphId:  ids[i],
 				Source: recipients[i],
 			}
-			clients[i].processors[*recipients[i]].Process(
+			mc.getProcessor(recipients[i]).Process(
 				msg, []string{}, []byte{}, recID, rnd)
 		}
 	}
 	return rounds.Round{ID: id.Round(mc.rndID)}, ids, nil
 }
 
+// getProcessor returns the processor registered for the recipient on this
+// client or any of its linked peers.
+func (mc *mockClient) getProcessor(recipient *id.ID) message.Processor {
+	if p, exists := mc.processors[*recipient]; exists {
+		return p
+	}
+	for _, peer := range mc.peers {
+		if p, exists := peer.processors[*recipient]; exists {
+			return p
+		}
+	}
+	mc.t.Fatalf("no processor registered for recipient %s", recipient)
+	return nil
+}
+
 func (mc *mockClient) AddIdentity(*id.ID, time.Time, bool, message.Processor) {}
 func (mc *mockClient) AddIdentityWithHistory(id *id.ID, _ time.Time, _ time.Time,
 	_ bool, processor message.Processor) {
@@ -117,7 +143,17 @@ func (mc *mockClient) RemoveIdentity(*id.ID)      {}
 func (mc *mockClient) GetRoundResults(time.Duration, cmix.RoundEventCallback,
 	...id.Round) {
 }
-func (mc *mockClient) AddHealthCallback(func(bool)) uint64 { return 0 }
+func (mc *mockClient) AddHealthCallback(f func(bool)) uint64 {
+	mc.healthCallbacks = append(mc.healthCallbacks, f)
+	return uint64(len(mc.healthCallbacks) - 1)
+}
+
+// setHealthy calls every registered health callback with the health.
+func (mc *mockClient) setHealthy(healthy bool) {
+	for _, f := range mc.healthCallbacks {
+		f(healthy)
+	}
+}
 func (mc *mockClient) RemoveHealthCallback(uint64)         {}
 
 func (mr *mockReceiver) SendWithAssembler(recipient *id.ID,
@@ -153,13 +189,27 @@ func newMockReceiver() *mockReceiver {
 		Msgs:    make([]mockMessage, 0),
 		uuid:    0,
 		blocked: make([]ed25519.PublicKey, 0),
+		groups:  make(map[string]ModelGroup),
 	}
 }
 
 type mockReceiver struct {
-	Msgs    []mockMessage
+	Msgs          []mockMessage
+	Reads         []mockRead
+	Deleted       []cryptoMessage.ID
+	DeletedBefore map[string]time.Time
+	Requests      map[string]bool
+	requestsMux   sync.Mutex
 	uuid    uint64
 	blocked []ed25519.PublicKey
+	groups  map[string]ModelGroup
+}
+
+// mockRead records a single call to mockReceiver.UpdateReadStatus.
+type mockRead struct {
+	PartnerPubKey ed25519.PublicKey
+	SenderPubKey  ed25519.PublicKey
+	UpTo          cryptoMessage.ID
 }
 
 func (mr *mockReceiver) Receive(messageID cryptoMessage.ID, _ string,
@@ -228,12 +278,61 @@ func (mr *mockReceiver) UpdateSentStatus(_ uint64, messageID cryptoMessage.ID,
 	jww.INFO.Printf("UpdateSentStatus: %s", messageID)
 }
 
+func (mr *mockReceiver) UpdateReadStatus(partnerPubKey,
+	senderPubKey ed25519.PublicKey, upTo cryptoMessage.ID) {
+	jww.INFO.Printf("UpdateReadStatus: %X, %X, %s",
+		partnerPubKey, senderPubKey, upTo)
+	mr.Reads = append(mr.Reads, mockRead{partnerPubKey, senderPubKey, upTo})
+}
+
 func (mr *mockReceiver) DeleteMessage(
 	messageID cryptoMessage.ID, senderPubKey ed25519.PublicKey) bool {
 	jww.INFO.Printf("DeleteMessage: %s, %X", messageID, senderPubKey)
+	mr.Deleted = append(mr.Deleted, messageID)
 	return true
 }
 
+func (mr *mockReceiver) UpdateMessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	mr.requestsMux.Lock()
+	defer mr.requestsMux.Unlock()
+	if mr.Requests == nil {
+		mr.Requests = make(map[string]bool)
+	}
+	mr.Requests[string(partnerPubKey)] = pending
+}
+
+// isRequest returns true if the conversation with the partner is marked as a
+// pending message request.
+func (mr *mockReceiver) isRequest(partnerPubKey ed25519.PublicKey) bool {
+	mr.requestsMux.Lock()
+	defer mr.requestsMux.Unlock()
+	return mr.Requests[string(partnerPubKey)]
+}
+
+func (mr *mockReceiver) DeleteMessagesBefore(
+	partnerPubKey ed25519.PublicKey, before time.Time) int {
+	jww.INFO.Printf("DeleteMessagesBefore: %X, %s", partnerPubKey, before)
+	if mr.DeletedBefore == nil {
+		mr.DeletedBefore = make(map[string]time.Time)
+	}
+	mr.DeletedBefore[string(partnerPubKey)] = before
+	return 0
+}
+
+func (mr *mockReceiver) UpdateGroup(group ModelGroup) {
+	jww.INFO.Printf("UpdateGroup: %X", group.GroupID)
+	mr.groups[string(group.GroupID)] = group
+}
+
+func (mr *mockReceiver) GetGroup(groupID ed25519.PublicKey) *ModelGroup {
+	group, exists := mr.groups[string(groupID)]
+	if !exists {
+		return nil
+	}
+	return &group
+}
+
 func (mr *mockReceiver) GetConversation(pubKey ed25519.PublicKey) *ModelConversation {
 	convo := ModelConversation{}
 	convo.Pubkey = pubKey
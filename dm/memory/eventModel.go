@@ -0,0 +1,473 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// Package memory contains an in-memory implementation of [dm.EventModel]. It
+// is intended as a reference implementation for tests and prototypes that need
+// a working event model without an SQLite file or a hand-written mock. Nothing
+// is persisted.
+package memory
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"sort"
+	"sync"
+	"time"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+)
+
+// Verify that EventModel adheres to the dm.EventModel and dm.MessageExpirer
+// interfaces.
+var (
+	_ dm.EventModel     = (*EventModel)(nil)
+	_ dm.MessageExpirer = (*EventModel)(nil)
+)
+
+// EventModel is an in-memory [dm.EventModel]. It follows the same semantics as
+// the SQL event model in the storage package: a conversation is created for
+// the first message received from a partner, message IDs are unique, and a
+// group DM is stored as a conversation with the group ID as its public key.
+//
+// EventModel is safe for concurrent use. The snapshot methods (e.g.,
+// EventModel.Messages) return copies of the stored data so that tests can make
+// assertions without racing the DM client.
+type EventModel struct {
+	// conversations and groups are keyed on the partner public key or group ID
+	conversations map[string]*dm.ModelConversation
+	groups        map[string]*dm.ModelGroup
+
+	messages map[uint64]*dm.ModelMessage
+	uuids    map[message.ID]uint64
+
+	// nextUUID is the UUID assigned to the next received message. UUIDs start
+	// at 1 because 0 is returned when a message cannot be received.
+	nextUUID uint64
+
+	mux sync.RWMutex
+}
+
+// NewEventModel returns a new empty in-memory event model.
+func NewEventModel() *EventModel {
+	return &EventModel{
+		conversations: make(map[string]*dm.ModelConversation),
+		groups:        make(map[string]*dm.ModelGroup),
+		messages:      make(map[uint64]*dm.ModelMessage),
+		uuids:         make(map[message.ID]uint64),
+		nextUUID:      1,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Messages                                                                   //
+////////////////////////////////////////////////////////////////////////////////
+
+// Receive is called whenever a raw direct message is received.
+func (m *EventModel) Receive(messageID message.ID, nickname string,
+	text []byte, partnerPubKey, senderPubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	mType dm.MessageType, status dm.Status) uint64 {
+	return m.receive(messageID, message.ID{}, nickname, text, partnerPubKey,
+		senderPubKey, dmToken, codeset, timestamp, round, mType, status)
+}
+
+// ReceiveText is called whenever a direct message is received that is a text
+// type.
+func (m *EventModel) ReceiveText(messageID message.ID, nickname, text string,
+	partnerPubKey, senderPubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	status dm.Status) uint64 {
+	return m.receive(messageID, message.ID{}, nickname, []byte(text),
+		partnerPubKey, senderPubKey, dmToken, codeset, timestamp, round,
+		dm.TextType, status)
+}
+
+// ReceiveReply is called whenever a direct message is received that is a reply.
+func (m *EventModel) ReceiveReply(messageID, reactionTo message.ID, nickname,
+	text string, partnerPubKey, senderPubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	status dm.Status) uint64 {
+	return m.receive(messageID, reactionTo, nickname, []byte(text),
+		partnerPubKey, senderPubKey, dmToken, codeset, timestamp, round,
+		dm.ReplyType, status)
+}
+
+// ReceiveReaction is called whenever a reaction to a direct message is
+// received.
+func (m *EventModel) ReceiveReaction(messageID, reactionTo message.ID,
+	nickname, reaction string, partnerPubKey, senderPubKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, round rounds.Round,
+	status dm.Status) uint64 {
+	return m.receive(messageID, reactionTo, nickname, []byte(reaction),
+		partnerPubKey, senderPubKey, dmToken, codeset, timestamp, round,
+		dm.ReactionType, status)
+}
+
+// receive stores a new message and returns its UUID. The conversation with the
+// partner is created if it does not exist and its nickname and token are
+// updated if they changed. Returns 0 if a message with the same ID already
+// exists.
+func (m *EventModel) receive(messageID, parentID message.ID, nickname string,
+	text []byte, partnerPubKey, senderPubKey ed25519.PublicKey, dmToken uint32,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	mType dm.MessageType, status dm.Status) uint64 {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, exists := m.uuids[messageID]; exists {
+		jww.ERROR.Printf(
+			"[DM MEM] Failed to receive message %s: already exists", messageID)
+		return 0
+	}
+
+	// The nickname and token of a group are set by UpdateGroup instead
+	convo, exists := m.conversations[string(partnerPubKey)]
+	if !exists {
+		m.conversations[string(partnerPubKey)] = &dm.ModelConversation{
+			Pubkey:         copyBytes(partnerPubKey),
+			Nickname:       nickname,
+			Token:          dmToken,
+			CodesetVersion: codeset,
+		}
+	} else if !convo.IsGroup {
+		convo.Nickname = nickname
+		convo.Token = dmToken
+	}
+
+	uuid := m.nextUUID
+	m.nextUUID++
+	m.uuids[messageID] = uuid
+	m.messages[uuid] = &dm.ModelMessage{
+		UUID:               uuid,
+		MessageID:          messageID,
+		ConversationPubKey: copyBytes(partnerPubKey),
+		ParentMessageID:    parentID,
+		Timestamp:          timestamp,
+		SenderPubKey:       copyBytes(senderPubKey),
+		CodesetVersion:     codeset,
+		Status:             status,
+		Content:            copyBytes(text),
+		Type:               mType,
+		Round:              round.ID,
+	}
+
+	return uuid
+}
+
+// UpdateSentStatus is called whenever the sent status of a message has
+// changed. The message ID, timestamp, and round are only updated if they are
+// not zero.
+func (m *EventModel) UpdateSentStatus(uuid uint64, messageID message.ID,
+	timestamp time.Time, round rounds.Round, status dm.Status) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.messages[uuid]
+	if !exists {
+		jww.ERROR.Printf("[DM MEM] Failed to UpdateSentStatus: "+
+			"no message with UUID %d", uuid)
+		return
+	}
+
+	if messageID != (message.ID{}) && messageID != msg.MessageID {
+		if _, exists = m.uuids[messageID]; exists {
+			jww.ERROR.Printf("[DM MEM] Failed to UpdateSentStatus: "+
+				"message %s already exists", messageID)
+			return
+		}
+		delete(m.uuids, msg.MessageID)
+		m.uuids[messageID] = uuid
+		msg.MessageID = messageID
+	}
+	if round.ID != 0 {
+		msg.Round = round.ID
+	}
+	if !timestamp.IsZero() {
+		msg.Timestamp = timestamp
+	}
+	msg.Status = status
+}
+
+// UpdateReadStatus marks all messages in the conversation with the partner
+// that were sent by senderPubKey, up to and including the message upTo, as
+// read. If the message upTo does not exist in the conversation, then no update
+// is made.
+func (m *EventModel) UpdateReadStatus(partnerPubKey,
+	senderPubKey ed25519.PublicKey, upTo message.ID) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	target, exists := m.getMessage(upTo)
+	if !exists || !bytes.Equal(target.ConversationPubKey, partnerPubKey) {
+		jww.ERROR.Printf("[DM MEM] Failed to UpdateReadStatus: message %s "+
+			"not found in conversation", upTo)
+		return
+	}
+
+	for _, msg := range m.messages {
+		if bytes.Equal(msg.ConversationPubKey, partnerPubKey) &&
+			bytes.Equal(msg.SenderPubKey, senderPubKey) &&
+			!msg.Timestamp.After(target.Timestamp) {
+			msg.ReceiptStatus = dm.Read
+		}
+	}
+}
+
+// DeleteMessage deletes the message with the given message.ID belonging to the
+// sender. If the message exists and belongs to the sender, then it is deleted
+// and DeleteMessage returns true. If it does not exist, it returns false.
+func (m *EventModel) DeleteMessage(
+	messageID message.ID, senderPubKey ed25519.PublicKey) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists || !bytes.Equal(msg.SenderPubKey, senderPubKey) {
+		return false
+	}
+	delete(m.uuids, messageID)
+	delete(m.messages, msg.UUID)
+
+	return true
+}
+
+// DeleteMessagesBefore deletes every message in the conversation with the
+// partner that has a timestamp before the given time, except for messages that
+// have not been sent yet. Returns the number of messages deleted.
+func (m *EventModel) DeleteMessagesBefore(
+	partnerPubKey ed25519.PublicKey, before time.Time) int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	var deleted int
+	for uuid, msg := range m.messages {
+		if bytes.Equal(msg.ConversationPubKey, partnerPubKey) &&
+			msg.Timestamp.Before(before) && msg.Status != dm.Unsent {
+			delete(m.uuids, msg.MessageID)
+			delete(m.messages, uuid)
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+// getMessage returns the message with the given [message.ID]. Must be called
+// while the lock is held.
+func (m *EventModel) getMessage(messageID message.ID) (*dm.ModelMessage, bool) {
+	uuid, exists := m.uuids[messageID]
+	if !exists {
+		return nil, false
+	}
+	return m.messages[uuid], true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Conversations                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetConversation returns the conversation with the partner or group. Returns
+// nil if it does not exist.
+func (m *EventModel) GetConversation(
+	senderPubKey ed25519.PublicKey) *dm.ModelConversation {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	convo, exists := m.conversations[string(senderPubKey)]
+	if !exists {
+		return nil
+	}
+	c := copyConversation(*convo)
+	return &c
+}
+
+// GetConversations returns every conversation, ordered by public key.
+func (m *EventModel) GetConversations() []dm.ModelConversation {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	convos := make([]dm.ModelConversation, 0, len(m.conversations))
+	for _, convo := range m.conversations {
+		convos = append(convos, copyConversation(*convo))
+	}
+	sort.Slice(convos, func(i, j int) bool {
+		return bytes.Compare(convos[i].Pubkey, convos[j].Pubkey) < 0
+	})
+
+	return convos
+}
+
+// UpdateMessageRequest marks whether the conversation with the partner is a
+// pending message request. Does nothing if the conversation does not exist.
+func (m *EventModel) UpdateMessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if convo, exists := m.conversations[string(partnerPubKey)]; exists {
+		convo.IsRequest = pending
+	}
+}
+
+// UpdateGroup creates or replaces the group DM. The group is stored as a
+// conversation with the group ID as its public key and the group name as its
+// nickname. A conversation with a partner is never replaced by a group.
+func (m *EventModel) UpdateGroup(group dm.ModelGroup) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Only the name of an existing conversation changes so that the codeset
+	// and blocked status are kept
+	convo, exists := m.conversations[string(group.GroupID)]
+	if exists && !convo.IsGroup {
+		jww.ERROR.Printf("[DM MEM] Failed to update group %X: conversation "+
+			"is not a group", group.GroupID)
+		return
+	} else if !exists {
+		convo = &dm.ModelConversation{Pubkey: copyBytes(group.GroupID)}
+		m.conversations[string(group.GroupID)] = convo
+	}
+	convo.Nickname = group.Name
+	convo.IsGroup = true
+
+	g := copyGroup(group)
+	m.groups[string(group.GroupID)] = &g
+}
+
+// GetGroup returns the group DM with the given ID. Returns nil if the group
+// does not exist.
+func (m *EventModel) GetGroup(groupID ed25519.PublicKey) *dm.ModelGroup {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	group, exists := m.groups[string(groupID)]
+	if !exists {
+		return nil
+	}
+	g := copyGroup(*group)
+	return &g
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Snapshots                                                                  //
+////////////////////////////////////////////////////////////////////////////////
+
+// GetMessage returns a copy of the message with the given [message.ID].
+// Returns nil if the message does not exist.
+func (m *EventModel) GetMessage(messageID message.ID) *dm.ModelMessage {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	msg, exists := m.getMessage(messageID)
+	if !exists {
+		return nil
+	}
+	c := copyMessage(*msg)
+	return &c
+}
+
+// Messages returns a copy of every message in every conversation, in the order
+// they were received.
+func (m *EventModel) Messages() []dm.ModelMessage {
+	return m.filterMessages(func(*dm.ModelMessage) bool { return true })
+}
+
+// ConversationMessages returns a copy of every message in the conversation
+// with the partner or group, in the order they were received.
+func (m *EventModel) ConversationMessages(
+	partnerPubKey ed25519.PublicKey) []dm.ModelMessage {
+	return m.filterMessages(func(msg *dm.ModelMessage) bool {
+		return bytes.Equal(msg.ConversationPubKey, partnerPubKey)
+	})
+}
+
+// filterMessages returns a copy of every message that matches the filter,
+// ordered by UUID.
+func (m *EventModel) filterMessages(
+	filter func(msg *dm.ModelMessage) bool) []dm.ModelMessage {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	msgs := make([]dm.ModelMessage, 0, len(m.messages))
+	for _, msg := range m.messages {
+		if filter(msg) {
+			msgs = append(msgs, copyMessage(*msg))
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].UUID < msgs[j].UUID })
+
+	return msgs
+}
+
+// Groups returns a copy of every group DM, ordered by group ID.
+func (m *EventModel) Groups() []dm.ModelGroup {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	groups := make([]dm.ModelGroup, 0, len(m.groups))
+	for _, group := range m.groups {
+		groups = append(groups, copyGroup(*group))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return bytes.Compare(groups[i].GroupID, groups[j].GroupID) < 0
+	})
+
+	return groups
+}
+
+// copyConversation returns a copy of the conversation that shares no memory
+// with the original.
+func copyConversation(convo dm.ModelConversation) dm.ModelConversation {
+	convo.Pubkey = copyBytes(convo.Pubkey)
+	if convo.BlockedTimestamp != nil {
+		blocked := *convo.BlockedTimestamp
+		convo.BlockedTimestamp = &blocked
+	}
+	return convo
+}
+
+// copyGroup returns a copy of the group that shares no memory with the
+// original.
+func copyGroup(group dm.ModelGroup) dm.ModelGroup {
+	members := make([]dm.GroupMember, len(group.Members))
+	for j, member := range group.Members {
+		members[j] = dm.GroupMember{
+			PubKey: copyBytes(member.PubKey),
+			Token:  member.Token,
+		}
+	}
+	group.GroupID = copyBytes(group.GroupID)
+	group.Members = members
+	if group.Admins != nil {
+		admins := make([]ed25519.PublicKey, len(group.Admins))
+		for j, admin := range group.Admins {
+			admins[j] = copyBytes(admin)
+		}
+		group.Admins = admins
+	}
+	return group
+}
+
+// copyMessage returns a copy of the message that shares no memory with the
+// original.
+func copyMessage(msg dm.ModelMessage) dm.ModelMessage {
+	msg.ConversationPubKey = copyBytes(msg.ConversationPubKey)
+	msg.SenderPubKey = copyBytes(msg.SenderPubKey)
+	msg.Content = copyBytes(msg.Content)
+	return msg
+}
+
+// copyBytes returns a copy of the byte slice. Nil slices stay nil.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
@@ -0,0 +1,267 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package memory
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that messages can be received, updated, and deleted and that a
+// conversation is created for the partner.
+func TestEventModel_Messages(t *testing.T) {
+	m := NewEventModel()
+	me := ed25519.PublicKey("me")
+	partner := ed25519.PublicKey("partner")
+
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	uuid := m.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		time.Unix(0, 0), rounds.Round{ID: 1}, dm.Received)
+	if uuid == 0 {
+		t.Fatal("Failed to receive message.")
+	}
+	if dup := m.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		time.Unix(0, 0), rounds.Round{ID: 1}, dm.Received); dup != 0 {
+		t.Errorf("Received duplicate message with UUID %d.", dup)
+	}
+
+	expected := dm.ModelConversation{
+		Pubkey: partner, Nickname: "partner", Token: 5}
+	convo := m.GetConversation(partner)
+	if convo == nil || !reflect.DeepEqual(expected, *convo) {
+		t.Errorf("Unexpected conversation.\nexpected: %+v\nreceived: %+v",
+			expected, convo)
+	}
+
+	pendingID := message.DeriveChannelMessageID(&id.ID{1}, 2, []byte("pending"))
+	replyUUID := m.ReceiveReply(pendingID, msgID, "me", "reply", partner, me,
+		6, 0, time.Unix(1, 0), rounds.Round{}, dm.Unsent)
+	replyID := message.DeriveChannelMessageID(&id.ID{1}, 3, []byte("reply"))
+	m.UpdateSentStatus(
+		replyUUID, replyID, time.Unix(2, 0), rounds.Round{ID: 3}, dm.Sent)
+
+	reply := m.GetMessage(replyID)
+	if reply == nil || reply.UUID != replyUUID || reply.ParentMessageID != msgID ||
+		reply.Status != dm.Sent || reply.Round != 3 ||
+		!reply.Timestamp.Equal(time.Unix(2, 0)) {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+	if msg := m.GetMessage(pendingID); msg != nil {
+		t.Errorf("Got message by its pending ID: %+v", msg)
+	}
+
+	if m.DeleteMessage(replyID, partner) {
+		t.Error("Deleted message belonging to another sender.")
+	}
+	if !m.DeleteMessage(replyID, me) {
+		t.Error("Failed to delete reply.")
+	}
+	if msgs := m.ConversationMessages(partner); len(msgs) != 1 ||
+		msgs[0].MessageID != msgID {
+		t.Errorf("Unexpected messages: %+v", msgs)
+	}
+}
+
+// Tests that EventModel.DeleteMessagesBefore only deletes sent messages in the
+// conversation that are older than the given time.
+func TestEventModel_DeleteMessagesBefore(t *testing.T) {
+	m := NewEventModel()
+	partner := ed25519.PublicKey("partner")
+	other := ed25519.PublicKey("other")
+
+	received := []struct {
+		partner ed25519.PublicKey
+		ts      int64
+		status  dm.Status
+		deleted bool
+	}{
+		{partner, 1, dm.Received, true},
+		{partner, 2, dm.Unsent, false},
+		{other, 3, dm.Received, false},
+		{partner, 4, dm.Sent, true},
+		{partner, 5, dm.Received, false},
+	}
+	msgIDs := make([]message.ID, len(received))
+	for j, r := range received {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(strconv.Itoa(j)))
+		m.ReceiveText(msgIDs[j], "nickname", strconv.Itoa(j), r.partner,
+			r.partner, 5, 0, time.Unix(r.ts, 0), rounds.Round{ID: id.Round(j)},
+			r.status)
+	}
+
+	if n := m.DeleteMessagesBefore(partner, time.Unix(5, 0)); n != 2 {
+		t.Errorf("Unexpected number of deleted messages."+
+			"\nexpected: %d\nreceived: %d", 2, n)
+	}
+	for j, r := range received {
+		if deleted := m.GetMessage(msgIDs[j]) == nil; deleted != r.deleted {
+			t.Errorf("Message %d deleted: %t, expected %t", j, deleted,
+				r.deleted)
+		}
+	}
+}
+
+// Tests that EventModel.UpdateMessageRequest marks and unmarks a conversation
+// as a message request and does not create conversations that do not exist.
+func TestEventModel_UpdateMessageRequest(t *testing.T) {
+	m := NewEventModel()
+	partner := ed25519.PublicKey("partner")
+
+	m.UpdateMessageRequest(partner, true)
+	if convo := m.GetConversation(partner); convo != nil {
+		t.Fatalf("Created conversation that does not exist: %+v", convo)
+	}
+
+	m.ReceiveText(message.ID{1}, "partner", "text", partner, partner, 5, 0,
+		time.Unix(0, 0), rounds.Round{ID: 1}, dm.Received)
+	for _, pending := range []bool{true, false} {
+		m.UpdateMessageRequest(partner, pending)
+		convo := m.GetConversation(partner)
+		if convo == nil || convo.IsRequest != pending {
+			t.Errorf("Unexpected conversation after marking request as %t: "+
+				"%+v", pending, convo)
+		}
+	}
+}
+
+// Tests that EventModel.UpdateReadStatus only marks messages from the sender up
+// to and including the given message as read.
+func TestEventModel_UpdateReadStatus(t *testing.T) {
+	m := NewEventModel()
+	me := ed25519.PublicKey("me")
+	partner := ed25519.PublicKey("partner")
+
+	senders := []ed25519.PublicKey{partner, me, partner, partner}
+	msgIDs := make([]message.ID, len(senders))
+	for j, sender := range senders {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(strconv.Itoa(j)))
+		m.ReceiveText(msgIDs[j], "nickname", strconv.Itoa(j), partner, sender,
+			5, 0, time.Unix(int64(j), 0), rounds.Round{ID: id.Round(j)},
+			dm.Received)
+	}
+
+	m.UpdateReadStatus(partner, partner, msgIDs[2])
+
+	expected := []dm.ReceiptStatus{dm.Read, dm.NotRead, dm.Read, dm.NotRead}
+	for j, msg := range m.Messages() {
+		if msg.ReceiptStatus != expected[j] {
+			t.Errorf("Unexpected receipt status of message %d."+
+				"\nexpected: %s\nreceived: %s", j, expected[j], msg.ReceiptStatus)
+		}
+	}
+}
+
+// Tests that a group can be created and updated and that messages in the group
+// do not change its name.
+func TestEventModel_Groups(t *testing.T) {
+	m := NewEventModel()
+
+	group := dm.ModelGroup{
+		GroupID: ed25519.PublicKey("groupID"),
+		Name:    "group",
+		Members: []dm.GroupMember{
+			{PubKey: ed25519.PublicKey("alice"), Token: 1},
+			{PubKey: ed25519.PublicKey("bob"), Token: 2},
+		},
+	}
+	m.UpdateGroup(group)
+
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	m.ReceiveText(msgID, "alice", "text", group.GroupID,
+		group.Members[0].PubKey, 1, 0, time.Unix(0, 0), rounds.Round{ID: 1},
+		dm.Received)
+
+	group.Name = "new name"
+	group.Members = group.Members[1:]
+	group.Admins = []ed25519.PublicKey{group.Members[0].PubKey}
+	m.UpdateGroup(group)
+
+	received := m.GetGroup(group.GroupID)
+	if received == nil || !reflect.DeepEqual(group, *received) {
+		t.Errorf("Unexpected group.\nexpected: %+v\nreceived: %+v",
+			group, received)
+	}
+	if groups := m.Groups(); !reflect.DeepEqual([]dm.ModelGroup{group}, groups) {
+		t.Errorf("Unexpected groups: %+v", groups)
+	}
+
+	convos := m.GetConversations()
+	if len(convos) != 1 || !convos[0].IsGroup || convos[0].Nickname != group.Name {
+		t.Errorf("Group not stored as a conversation: %+v", convos)
+	}
+
+	if g := m.GetGroup(ed25519.PublicKey("alice")); g != nil {
+		t.Errorf("Got group that does not exist: %+v", g)
+	}
+
+	// A conversation with a partner is never replaced by a group
+	partner := ed25519.PublicKey("partner")
+	msgID = message.DeriveChannelMessageID(&id.ID{1}, 2, []byte("text"))
+	m.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		time.Unix(0, 0), rounds.Round{ID: 2}, dm.Received)
+	m.UpdateGroup(dm.ModelGroup{GroupID: partner, Name: "group"})
+	if g := m.GetGroup(partner); g != nil {
+		t.Errorf("Got group for DM conversation: %+v", g)
+	}
+	if convo := m.GetConversation(partner); convo.IsGroup ||
+		convo.Nickname != "partner" {
+		t.Errorf("DM conversation replaced by group: %+v", convo)
+	}
+}
+
+// Tests that the snapshot methods return copies that are not affected by later
+// changes to the EventModel and that the EventModel can be used concurrently.
+func TestEventModel_Snapshots(t *testing.T) {
+	m := NewEventModel()
+	partner := ed25519.PublicKey("partner")
+
+	var wg sync.WaitGroup
+	for j := 0; j < 10; j++ {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			text := strconv.Itoa(j)
+			msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte(text))
+			if m.ReceiveText(msgID, "partner", text, partner, partner, 5, 0,
+				time.Unix(0, 0), rounds.Round{ID: 1}, dm.Received) == 0 {
+				t.Errorf("Failed to receive message %q.", text)
+			}
+			m.GetConversations()
+		}(j)
+	}
+	wg.Wait()
+
+	msgs := m.Messages()
+	if len(msgs) != 10 {
+		t.Fatalf("Unexpected number of messages.\nexpected: %d\nreceived: %d",
+			10, len(msgs))
+	}
+	for j := range msgs {
+		if msgs[j].UUID != uint64(j+1) {
+			t.Errorf("Message %d has UUID %d.", j, msgs[j].UUID)
+		}
+	}
+
+	msgs[0].Content[0] = 'x'
+	msgs[0].ConversationPubKey[0] = 'x'
+	msg := m.GetMessage(msgs[0].MessageID)
+	if msg == nil || msg.Content[0] == 'x' ||
+		string(msg.ConversationPubKey) != string(partner) {
+		t.Errorf("Snapshot shares memory with the model: %+v", msg)
+	}
+}
@@ -37,6 +37,27 @@ const (
 	// DeleteType denotes that the message contains the ID of a message to
 	// delete.
 	DeleteType MessageType = 6
+
+	// TypingType denotes that the message signals that the sender started or
+	// stopped typing. It is delivered to the UI and is never stored.
+	TypingType MessageType = 7
+
+	// ReadReceiptType denotes that the message is a read receipt that marks
+	// messages sent to the sender as read. It is never stored as a message.
+	ReadReceiptType MessageType = 8
+
+	// RetentionType denotes that the message sets the retention timer of the
+	// conversation. Messages in the conversation are deleted once they are
+	// older than the timer.
+	RetentionType MessageType = 9
+
+	// GroupType denotes that the message was sent to a group DM. It wraps
+	// another message along with the group's ID and membership.
+	GroupType MessageType = 10
+
+	// AdminKeyType denotes that the message hands the partner the admin key of
+	// a channel. It is passed to the AdminKeyReceiver and never stored.
+	AdminKeyType MessageType = 11
 )
 
 // String returns a human-readable version of [MessageType], used for debugging
@@ -55,6 +76,16 @@ func (mt MessageType) String() string {
 		return "Invitation"
 	case DeleteType:
 		return "Delete"
+	case TypingType:
+		return "Typing"
+	case ReadReceiptType:
+		return "ReadReceipt"
+	case RetentionType:
+		return "Retention"
+	case GroupType:
+		return "Group"
+	case AdminKeyType:
+		return "AdminKey"
 	default:
 		return "Unknown messageType " + strconv.Itoa(int(mt))
 	}
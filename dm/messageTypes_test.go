@@ -17,8 +17,12 @@ func TestMessageType_String_Consistency(t *testing.T) {
 	expectedStrings := map[MessageType]string{
 		TextType: "Text", ReplyType: "Reply", ReactionType: "Reaction",
 		SilentType: "Silent", InvitationType: "Invitation", DeleteType: "Delete",
-		DeleteType + 1: fmt.Sprintf("Unknown messageType %d", DeleteType+1),
-		DeleteType + 2: fmt.Sprintf("Unknown messageType %d", DeleteType+2),
+		TypingType: "Typing", ReadReceiptType: "ReadReceipt",
+		RetentionType:    "Retention",
+		GroupType:        "Group",
+		AdminKeyType:     "AdminKey",
+		AdminKeyType + 1: fmt.Sprintf("Unknown messageType %d", AdminKeyType+1),
+		AdminKeyType + 2: fmt.Sprintf("Unknown messageType %d", AdminKeyType+2),
 	}
 
 	for mt, expected := range expectedStrings {
@@ -33,7 +37,8 @@ func TestMessageType_String_Consistency(t *testing.T) {
 // via UnmarshalMessageType matches the original.
 func TestMessageType_Marshal_UnmarshalMessageType(t *testing.T) {
 	tests := []MessageType{
-		TextType, ReplyType, ReactionType, SilentType, InvitationType, DeleteType}
+		TextType, ReplyType, ReactionType, SilentType, InvitationType, DeleteType,
+		TypingType, ReadReceiptType, RetentionType, GroupType, AdminKeyType}
 
 	for _, mt := range tests {
 		data := mt.Marshal()
@@ -45,3 +50,28 @@ func TestMessageType_Marshal_UnmarshalMessageType(t *testing.T) {
 		}
 	}
 }
+
+// Tests that only messages with content shown in a message request are allowed
+// from a partner with a pending request.
+func TestMessageType_allowedInRequest(t *testing.T) {
+	tests := map[MessageType]bool{
+		TextType:        true,
+		ReplyType:       false,
+		ReactionType:    false,
+		SilentType:      false,
+		InvitationType:  true,
+		DeleteType:      false,
+		TypingType:      false,
+		ReadReceiptType: false,
+		RetentionType:   false,
+		GroupType:       false,
+		AdminKeyType:    false,
+	}
+
+	for mt, expected := range tests {
+		if allowed := mt.allowedInRequest(); allowed != expected {
+			t.Errorf("Unexpected result for %s.\nexpected: %t\nreceived: %t",
+				mt, expected, allowed)
+		}
+	}
+}
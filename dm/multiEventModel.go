@@ -0,0 +1,242 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"time"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+)
+
+// Verify that multiEventModel adheres to the EventModel and
+// expiredMessageDeleter interfaces.
+var (
+	_ EventModel            = (*multiEventModel)(nil)
+	_ expiredMessageDeleter = (*multiEventModel)(nil)
+)
+
+// multiEventModel is an EventModel that forwards every event to several event
+// models. The first model is the primary; its return values are returned to
+// the caller.
+type multiEventModel struct {
+	primary   EventModel
+	secondary []EventModel
+
+	// uuids maps the UUID of a pending message in the primary model to its
+	// UUID in each secondary model. Only messages received with the Unsent
+	// status are tracked, since they are the only ones updated by UUID, and
+	// they are removed once they are Received or Failed.
+	uuids map[uint64][]uint64
+	mux   sync.Mutex
+}
+
+// NewMultiEventModel returns an EventModel that forwards every event to each of
+// the given event models, such as a database, a metrics sink, and a webhook
+// bridge. Events are forwarded in order.
+//
+// The first model is the primary model. The UUIDs, conversations, and groups
+// returned to the client come from it.
+//
+// Each model assigns its own UUIDs, so the composite model keeps a map from the
+// primary's UUID to the others' for messages that are pending send. The map is
+// held in memory; pending sends that are updated after a restart only update
+// the primary model.
+//
+// Expired messages are deleted from each model that implements MessageExpirer
+// by age and from the others message by message.
+//
+// Use WrapEventModel to filter or transform the events that reach an
+// individual model.
+func NewMultiEventModel(primary EventModel, others ...EventModel) EventModel {
+	return &multiEventModel{
+		primary:   primary,
+		secondary: others,
+		uuids:     make(map[uint64][]uint64),
+	}
+}
+
+// Receive is called whenever a raw direct message is received.
+func (mm *multiEventModel) Receive(messageID cryptoMessage.ID,
+	nickname string, text []byte, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, mType MessageType, status Status) uint64 {
+	return mm.receive(status, func(model EventModel) uint64 {
+		return model.Receive(messageID, nickname, text, partnerPubKey,
+			senderPubKey, partnerToken, codeset, timestamp, round, mType,
+			status)
+	})
+}
+
+// ReceiveText is called whenever a direct message is received.
+func (mm *multiEventModel) ReceiveText(messageID cryptoMessage.ID,
+	nickname, text string, partnerPubKey, senderPubKey ed25519.PublicKey,
+	partnerToken uint32, codeset uint8, timestamp time.Time,
+	round rounds.Round, status Status) uint64 {
+	return mm.receive(status, func(model EventModel) uint64 {
+		return model.ReceiveText(messageID, nickname, text, partnerPubKey,
+			senderPubKey, partnerToken, codeset, timestamp, round, status)
+	})
+}
+
+// ReceiveReply is called whenever a direct message is received that is a
+// reply.
+func (mm *multiEventModel) ReceiveReply(messageID,
+	reactionTo cryptoMessage.ID, nickname, text string, partnerPubKey,
+	senderPubKey ed25519.PublicKey, partnerToken uint32, codeset uint8,
+	timestamp time.Time, round rounds.Round, status Status) uint64 {
+	return mm.receive(status, func(model EventModel) uint64 {
+		return model.ReceiveReply(messageID, reactionTo, nickname, text,
+			partnerPubKey, senderPubKey, partnerToken, codeset, timestamp,
+			round, status)
+	})
+}
+
+// ReceiveReaction is called whenever a reaction to a direct message is
+// received.
+func (mm *multiEventModel) ReceiveReaction(messageID,
+	reactionTo cryptoMessage.ID, nickname, reaction string, partnerPubKey,
+	senderPubKey ed25519.PublicKey, partnerToken uint32, codeset uint8,
+	timestamp time.Time, round rounds.Round, status Status) uint64 {
+	return mm.receive(status, func(model EventModel) uint64 {
+		return model.ReceiveReaction(messageID, reactionTo, nickname,
+			reaction, partnerPubKey, senderPubKey, partnerToken, codeset,
+			timestamp, round, status)
+	})
+}
+
+// receive calls the receive function on every model and returns the UUID from
+// the primary model. The UUIDs from the other models are saved if the message
+// is pending send so that UpdateSentStatus can be forwarded.
+func (mm *multiEventModel) receive(
+	status Status, receive func(model EventModel) uint64) uint64 {
+	uuid := receive(mm.primary)
+
+	uuids := make([]uint64, len(mm.secondary))
+	for i, model := range mm.secondary {
+		uuids[i] = receive(model)
+	}
+
+	if status == Unsent && uuid != 0 && len(mm.secondary) > 0 {
+		mm.mux.Lock()
+		mm.uuids[uuid] = uuids
+		mm.mux.Unlock()
+	}
+
+	return uuid
+}
+
+// UpdateSentStatus is called whenever the sent status of a message has
+// changed. The update is forwarded to the other models using their UUID for
+// the message.
+func (mm *multiEventModel) UpdateSentStatus(uuid uint64,
+	messageID cryptoMessage.ID, timestamp time.Time, round rounds.Round,
+	status Status) {
+	mm.mux.Lock()
+	uuids, exists := mm.uuids[uuid]
+	if status == Received || status == Failed {
+		delete(mm.uuids, uuid)
+	}
+	mm.mux.Unlock()
+
+	mm.primary.UpdateSentStatus(uuid, messageID, timestamp, round, status)
+
+	if !exists && len(mm.secondary) > 0 {
+		jww.WARN.Printf("[DM] Multi event model has no UUIDs for message %d; "+
+			"only updating the primary model", uuid)
+		return
+	}
+
+	for i, model := range mm.secondary {
+		if uuids[i] != 0 {
+			model.UpdateSentStatus(uuids[i], messageID, timestamp, round, status)
+		}
+	}
+}
+
+// UpdateReadStatus is called whenever messages in a conversation are read.
+func (mm *multiEventModel) UpdateReadStatus(partnerPubKey,
+	senderPubKey ed25519.PublicKey, upTo cryptoMessage.ID) {
+	mm.primary.UpdateReadStatus(partnerPubKey, senderPubKey, upTo)
+	for _, model := range mm.secondary {
+		model.UpdateReadStatus(partnerPubKey, senderPubKey, upTo)
+	}
+}
+
+// DeleteMessage deletes the message from every model. Returns the result from
+// the primary model.
+func (mm *multiEventModel) DeleteMessage(
+	messageID cryptoMessage.ID, senderPubKey ed25519.PublicKey) bool {
+	deleted := mm.primary.DeleteMessage(messageID, senderPubKey)
+	for _, model := range mm.secondary {
+		model.DeleteMessage(messageID, senderPubKey)
+	}
+	return deleted
+}
+
+// needsTracking returns true if any model needs expiring messages to be
+// tracked.
+func (mm *multiEventModel) needsTracking() bool {
+	if needsTracking(mm.primary) {
+		return true
+	}
+	for _, model := range mm.secondary {
+		if needsTracking(model) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteExpired deletes the expired messages from every model in the way each
+// model supports. Returns the result from the primary model.
+func (mm *multiEventModel) deleteExpired(partnerPubKey ed25519.PublicKey,
+	before time.Time, expired []expiringMessage) int {
+	deleted := deleteExpired(mm.primary, partnerPubKey, before, expired)
+	for _, model := range mm.secondary {
+		deleteExpired(model, partnerPubKey, before, expired)
+	}
+	return deleted
+}
+
+// UpdateMessageRequest updates the conversation in every model.
+func (mm *multiEventModel) UpdateMessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	mm.primary.UpdateMessageRequest(partnerPubKey, pending)
+	for _, model := range mm.secondary {
+		model.UpdateMessageRequest(partnerPubKey, pending)
+	}
+}
+
+// GetConversation returns the conversation from the primary model.
+func (mm *multiEventModel) GetConversation(
+	senderPubKey ed25519.PublicKey) *ModelConversation {
+	return mm.primary.GetConversation(senderPubKey)
+}
+
+// GetConversations returns the conversations from the primary model.
+func (mm *multiEventModel) GetConversations() []ModelConversation {
+	return mm.primary.GetConversations()
+}
+
+// UpdateGroup creates or replaces the group DM in every model.
+func (mm *multiEventModel) UpdateGroup(group ModelGroup) {
+	mm.primary.UpdateGroup(group)
+	for _, model := range mm.secondary {
+		model.UpdateGroup(group)
+	}
+}
+
+// GetGroup returns the group DM from the primary model.
+func (mm *multiEventModel) GetGroup(groupID ed25519.PublicKey) *ModelGroup {
+	return mm.primary.GetGroup(groupID)
+}
@@ -0,0 +1,86 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+)
+
+// Tests that multiEventModel forwards received messages and sent status
+// updates to every model, using each model's UUID for the message.
+func Test_multiEventModel_Receive(t *testing.T) {
+	primary := &statusRecorder{mockReceiver: newMockReceiver()}
+	secondary := &statusRecorder{mockReceiver: newMockReceiver()}
+	secondary.uuid = 100
+	mm := NewMultiEventModel(primary, secondary)
+
+	partner := ed25519.PublicKey("partner")
+	uuid := mm.ReceiveText(cryptoMessage.ID{1}, "nickname", "text", partner,
+		partner, 5, 0, time.Unix(0, 0), rounds.Round{}, Unsent)
+	if uuid != 1 {
+		t.Errorf("Unexpected UUID.\nexpected: %d\nreceived: %d", 1, uuid)
+	}
+	for i, model := range []*statusRecorder{primary, secondary} {
+		if len(model.Msgs) != 1 || model.Msgs[0].Message != "text" {
+			t.Errorf("Model %d did not receive message: %+v", i, model.Msgs)
+		}
+	}
+
+	mm.UpdateSentStatus(uuid, cryptoMessage.ID{2}, time.Unix(1, 0),
+		rounds.Round{ID: 1}, Sent)
+	mm.UpdateSentStatus(uuid, cryptoMessage.ID{2}, time.Unix(1, 0),
+		rounds.Round{ID: 1}, Received)
+	if !reflect.DeepEqual([]uint64{1, 1}, primary.updated) {
+		t.Errorf("Unexpected primary UUIDs: %v", primary.updated)
+	}
+	if !reflect.DeepEqual([]uint64{101, 101}, secondary.updated) {
+		t.Errorf("Unexpected secondary UUIDs: %v", secondary.updated)
+	}
+
+	// The UUIDs are forgotten once the message is received
+	if _, exists := mm.(*multiEventModel).uuids[uuid]; exists {
+		t.Errorf("UUID %d not removed after it was received.", uuid)
+	}
+}
+
+// Tests that multiEventModel forwards groups to every model and returns them
+// from the primary model.
+func Test_multiEventModel_Groups(t *testing.T) {
+	primary, secondary := newMockReceiver(), newMockReceiver()
+	mm := NewMultiEventModel(primary, secondary)
+
+	group := ModelGroup{GroupID: ed25519.PublicKey("groupID"), Name: "group"}
+	mm.UpdateGroup(group)
+
+	if g := mm.GetGroup(group.GroupID); g == nil || !reflect.DeepEqual(group, *g) {
+		t.Errorf("Unexpected group.\nexpected: %+v\nreceived: %+v", group, g)
+	}
+	if g := secondary.GetGroup(group.GroupID); g == nil {
+		t.Error("Group not forwarded to secondary model.")
+	}
+}
+
+// statusRecorder is a mockReceiver that records the UUIDs passed to
+// UpdateSentStatus.
+type statusRecorder struct {
+	*mockReceiver
+	updated []uint64
+}
+
+func (sr *statusRecorder) UpdateSentStatus(uuid uint64,
+	messageID cryptoMessage.ID, timestamp time.Time, round rounds.Round,
+	status Status) {
+	sr.updated = append(sr.updated, uuid)
+	sr.mockReceiver.UpdateSentStatus(uuid, messageID, timestamp, round, status)
+}
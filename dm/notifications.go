@@ -179,9 +179,15 @@ func (n *notifications) GetNotificationLevel(
 }
 
 // SetMobileNotificationsLevel sets the notification level for the given DM
-// conversation partner.
+// conversation partner. Returns PendingMessageRequestErr if the partner has a
+// pending message request so that the request is not implicitly accepted.
 func (n *notifications) SetMobileNotificationsLevel(
 	partnerPubKey ed25519.PublicKey, level NotificationLevel) error {
+	if partner, exists := n.ps.get(partnerPubKey); exists &&
+		partner.Status == statusRequest {
+		return PendingMessageRequestErr
+	}
+
 	jww.INFO.Printf("[CH] Set notification level for DM partner %X to %s",
 		partnerPubKey, level)
 
@@ -192,7 +198,7 @@ func (n *notifications) SetMobileNotificationsLevel(
 // statusToLevel converts a partnerStatus to its equivalent NotificationLevel.
 func statusToLevel(status partnerStatus) NotificationLevel {
 	switch status {
-	case statusMute, statusBlocked:
+	case statusMute, statusBlocked, statusRequest:
 		return NotifyNone
 	case statusNotifyAll:
 		return NotifyAll
@@ -334,7 +340,7 @@ type NotificationLevel uint8
 
 var allowList = map[NotificationLevel]map[MessageType]struct{}{
 	NotifyNone: {},
-	NotifyAll:  {TextType: {}, ReplyType: {}},
+	NotifyAll:  {TextType: {}, ReplyType: {}, GroupType: {}},
 }
 
 const (
@@ -10,6 +10,7 @@ package dm
 import (
 	"bytes"
 	"crypto/ed25519"
+	"errors"
 	"io"
 	"math/rand"
 	"reflect"
@@ -255,12 +256,33 @@ func Test_notifications_SetMobileNotificationsLevel(t *testing.T) {
 	}
 }
 
+// Tests that notifications.SetMobileNotificationsLevel returns
+// PendingMessageRequestErr for a partner with a pending message request and
+// does not change their status.
+func Test_notifications_SetMobileNotificationsLevel_MessageRequest(t *testing.T) {
+	n := newTestNotifications(5738201, nil, t)
+	pubKey := newPubKey(rand.New(rand.NewSource(7836)))
+	n.ps.set(pubKey, statusRequest)
+
+	err := n.SetMobileNotificationsLevel(pubKey, NotifyAll)
+	if !errors.Is(err, PendingMessageRequestErr) {
+		t.Errorf("Unexpected error for partner with a message request."+
+			"\nexpected: %v\nreceived: %+v", PendingMessageRequestErr, err)
+	}
+
+	partner, exists := n.ps.get(pubKey)
+	if !exists || partner.Status != statusRequest {
+		t.Errorf("Message request accepted: %+v", partner)
+	}
+}
+
 // Unit test of statusToLevel.
 func Test_statusToLevel(t *testing.T) {
 	tests := map[partnerStatus]NotificationLevel{
 		statusMute:      NotifyNone,
 		statusNotifyAll: NotifyAll,
 		statusBlocked:   NotifyNone,
+		statusRequest:   NotifyNone,
 		6565:            NotifyAll,
 	}
 
@@ -0,0 +1,102 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	readReceiptsMapName      = "dmReadReceiptsMap"
+	readReceiptsMapVersion   = 0
+	readReceiptsStoreVersion = 0
+)
+
+// readReceiptStore keeps track of the partners that read receipts have been
+// disabled for. Read receipts are enabled for every partner not in the store.
+// The store is saved to the remote KV so that the setting is synchronised
+// between all instances of the user.
+type readReceiptStore struct {
+	remote versioned.KV
+}
+
+// newReadReceiptStore initialises a new readReceiptStore.
+func newReadReceiptStore(kv versioned.KV) (*readReceiptStore, error) {
+	remote, err := kv.Prefix(collective.StandardRemoteSyncPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readReceiptStore{remote}, nil
+}
+
+// set enables or disables read receipts for the partner.
+func (rrs *readReceiptStore) set(pubKey ed25519.PublicKey, enabled bool) {
+	elemName := marshalElementName(pubKey)
+
+	if enabled {
+		_, err := rrs.remote.DeleteMapElement(
+			readReceiptsMapName, elemName, readReceiptsMapVersion)
+		if err != nil {
+			jww.FATAL.Panicf("[DM] Failed to enable read receipts for "+
+				"partner %X: %+v", pubKey, err)
+		}
+		return
+	}
+
+	obj := &versioned.Object{
+		Version:   readReceiptsStoreVersion,
+		Timestamp: netTime.Now(),
+		Data:      []byte{},
+	}
+	err := rrs.remote.StoreMapElement(
+		readReceiptsMapName, elemName, obj, readReceiptsMapVersion)
+	if err != nil {
+		jww.FATAL.Panicf("[DM] Failed to disable read receipts for "+
+			"partner %X: %+v", pubKey, err)
+	}
+}
+
+// enabled returns true if read receipts are enabled for the partner.
+func (rrs *readReceiptStore) enabled(pubKey ed25519.PublicKey) bool {
+	elemName := marshalElementName(pubKey)
+	_, err := rrs.remote.GetMapElement(
+		readReceiptsMapName, elemName, readReceiptsMapVersion)
+	if err != nil {
+		if rrs.remote.Exists(err) {
+			jww.FATAL.Panicf("[DM] Failed to load read receipt setting for "+
+				"partner %X from storage: %+v", pubKey, err)
+		}
+		return true
+	}
+
+	return false
+}
+
+// SetReadReceipts enables or disables sending read receipts to the partner.
+// Read receipts are enabled for all partners by default, but are only sent
+// when [Sender.MarkRead] is called.
+func (dc *dmClient) SetReadReceipts(
+	partnerPubKey ed25519.PublicKey, enabled bool) {
+	dc.rrs.set(partnerPubKey, enabled)
+}
+
+// ReadReceiptsEnabled returns true if read receipts are sent to the partner.
+// It returns false if read receipts are disabled for the partner or if the
+// partner is blocked or has a pending message request.
+func (dc *dmClient) ReadReceiptsEnabled(partnerPubKey ed25519.PublicKey) bool {
+	return !dc.IsBlocked(partnerPubKey) && !dc.IsMessageRequest(partnerPubKey) &&
+		dc.rrs.enabled(partnerPubKey)
+}
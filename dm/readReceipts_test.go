@@ -0,0 +1,57 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/ekv"
+)
+
+// Tests that readReceiptStore.enabled returns the value set by
+// readReceiptStore.set and defaults to true.
+func Test_readReceiptStore_set_enabled(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	rrs, err := newReadReceiptStore(kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prng := rand.New(rand.NewSource(53426))
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+
+	if !rrs.enabled(pubKey) {
+		t.Errorf("Read receipts should be enabled by default.")
+	}
+
+	rrs.set(pubKey, false)
+	if rrs.enabled(pubKey) {
+		t.Errorf("Read receipts should be disabled after being disabled.")
+	}
+
+	// Other partners are unaffected
+	otherPubKey, _, _ := ed25519.GenerateKey(prng)
+	if !rrs.enabled(otherPubKey) {
+		t.Errorf("Read receipts should be enabled for other partners.")
+	}
+
+	rrs.set(pubKey, true)
+	if !rrs.enabled(pubKey) {
+		t.Errorf("Read receipts should be enabled after being enabled.")
+	}
+
+	// Enabling twice does not fail
+	rrs.set(pubKey, true)
+	if !rrs.enabled(pubKey) {
+		t.Errorf("Read receipts should be enabled after being enabled.")
+	}
+}
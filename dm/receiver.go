@@ -26,6 +26,7 @@ import (
 	"gitlab.com/elixxir/primitives/format"
 	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -77,7 +78,7 @@ func (dp *dmProcessor) Process(msg format.Message, _ []string, _ []byte,
 
 	jww.INFO.Printf("[DM] DeriveDirectMessage(%s...) Receive", myID)
 
-	msgID := message.DeriveDirectMessageID(myID, directMsg)
+	msgID := deriveMessageID(myID, directMsg)
 
 	// Check if we sent the message and ignore triggering if we sent
 	// This will happen when DMing with oneself, but the receive self
@@ -113,12 +114,23 @@ func (dp *dmProcessor) Process(msg format.Message, _ []string, _ []byte,
 
 	messageType := MessageType(directMsg.PayloadType)
 
-	// Check if the user is blocked
-	user := dp.r.c.ps.getOrSet(pubSigningKey)
-	if user.Status == statusBlocked {
+	// Check if the user is blocked or has a pending message request. A user
+	// contacting us for the first time is held as a message request. Group DMs
+	// never open a request; they are checked against the group in
+	// receiver.receiveGroup instead.
+	user, exists := dp.r.c.ps.get(pubSigningKey)
+	isRequest := (!exists || user.Status == statusRequest) &&
+		messageType != GroupType
+	if exists && user.Status == statusBlocked {
 		jww.INFO.Printf("Dropping message from blocked user: %s",
 			base64.RawStdEncoding.EncodeToString(pubSigningKey))
 		return
+	} else if isRequest && !messageType.allowedInRequest() {
+		jww.INFO.Printf("Dropping %s from user with message request: %s",
+			messageType, base64.RawStdEncoding.EncodeToString(pubSigningKey))
+		return
+	} else if isRequest && !exists {
+		dp.r.c.ps.getOrSet(pubSigningKey, statusRequest)
 	}
 
 	// partner Token is the sender Token
@@ -136,6 +148,12 @@ func (dp *dmProcessor) Process(msg format.Message, _ []string, _ []byte,
 		jww.WARN.Printf("Error processing for "+
 			"DM (UUID: %d): %+v", uuid, err)
 	}
+
+	// The conversation only exists in the event model once the first message
+	// is received, so it is marked as a request here
+	if isRequest {
+		dp.r.api.UpdateMessageRequest(pubSigningKey, true)
+	}
 }
 
 // selfProcessor processes a self-encrypted DM message.
@@ -178,7 +196,7 @@ func (sp *selfProcessor) Process(msg format.Message, _ []string, _ []byte,
 	jww.INFO.Printf("[DM] DeriveDirectMessage(%s...) ReceiveSelf",
 		partnerID)
 
-	msgID := message.DeriveDirectMessageID(partnerID, directMsg)
+	msgID := deriveMessageID(partnerID, directMsg)
 
 	// Check if we sent the message and ignore triggering if we
 	// sent, but mark the message as delivered
@@ -261,6 +279,16 @@ func (r *receiver) receiveMessage(msgID message.ID, messageType MessageType,
 		return 0, nil
 	}
 
+	// Track the message for deletion if the conversation has a retention
+	// timer. Messages pending send are tracked once they are sent.
+	if status != Unsent && messageType.isStored() {
+		err := r.c.retention.track(partnerPubKey, msgID, senderPubKey, ts)
+		if err != nil {
+			jww.ERROR.Printf("[DM] Failed to track expiry of message %s: "+
+				"%+v", msgID, err)
+		}
+	}
+
 	switch messageType {
 	case TextType:
 		return r.receiveTextMessage(msgID, messageType,
@@ -278,9 +306,24 @@ func (r *receiver) receiveMessage(msgID message.ID, messageType MessageType,
 		return r.receiveInvitation(msgID, messageType,
 			nick, plaintext, partnerDMToken, partnerPubKey,
 			senderPubKey, 0, ts, round, status)
+	case AdminKeyType:
+		return r.receiveChannelAdminKey(msgID, messageType, plaintext,
+			partnerPubKey, senderPubKey, ts, round)
 	case DeleteType:
 		return r.deleteMessage(msgID, messageType, plaintext, partnerPubKey,
 			senderPubKey, 0, ts, round)
+	case TypingType:
+		return r.receiveTyping(msgID, messageType, nick, plaintext,
+			partnerPubKey, senderPubKey, ts, round)
+	case ReadReceiptType:
+		return r.receiveReadReceipt(msgID, messageType, plaintext,
+			partnerPubKey, senderPubKey, ts, round)
+	case RetentionType:
+		return r.receiveRetention(msgID, messageType, nick, plaintext,
+			partnerDMToken, partnerPubKey, senderPubKey, ts, round, status)
+	case GroupType:
+		return r.receiveGroup(msgID, messageType, nick, plaintext,
+			partnerPubKey, senderPubKey, ts, round, status)
 	default:
 		return r.api.Receive(msgID, nick, plaintext,
 			partnerPubKey, senderPubKey,
@@ -429,6 +472,50 @@ func (r *receiver) receiveInvitation(messageID message.ID,
 		timestamp, round, InvitationType, status), nil
 }
 
+// receiveChannelAdminKey processes a channel admin key sent by the partner. The
+// key is passed to the registered AdminKeyReceiver and is never passed to the
+// EventModel. Keys sent by this user (received via self processing) are
+// dropped so that the previous owner never receives the key back.
+//
+// Always returns a 0 UUID.
+func (r *receiver) receiveChannelAdminKey(messageID message.ID,
+	messageType MessageType, content []byte, partnerPubKey,
+	senderPubKey ed25519.PublicKey, timestamp time.Time,
+	round rounds.Round) (uint64, error) {
+	adminKey := &ChannelAdminKey{}
+	if err := proto.Unmarshal(content, adminKey); err != nil {
+		return 0, errors.Wrapf(err, "Failed to admin key unmarshal DM %s "+
+			"with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp,
+			round.ID)
+	}
+
+	tag := makeDebugTag(partnerPubKey, adminKey.ChannelID, SendAdminKeyTag)
+
+	// Ignore admin keys sent by this user
+	if bytes.Equal(senderPubKey, r.c.me.PubKey) {
+		jww.TRACE.Printf("[%s] DM - Dropping own channel admin key %s",
+			tag, messageID)
+		return 0, nil
+	}
+
+	channelID, err := id.Unmarshal(adminKey.ChannelID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to unmarshal channel ID of DM "+
+			"%s with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp,
+			round.ID)
+	}
+
+	jww.INFO.Printf("[%s] DM - Received admin key for channel %s from "+
+		"partner %s", tag, channelID,
+		base64.StdEncoding.EncodeToString(partnerPubKey))
+
+	r.c.adminKeys.receive(channelID, adminKey.AdminKey)
+
+	return 0, nil
+}
+
 // deleteMessage processes a request to delete a message. If the target message,
 // exists, then it is deleted. If it does not exist, then it is added to the
 // action savor.
@@ -473,6 +560,139 @@ func (r *receiver) deleteMessage(messageID message.ID, messageType MessageType,
 	return 0, nil
 }
 
+// receiveTyping processes a typing indicator from a partner. Typing indicators
+// are passed to the UI via [Callbacks.UserTyping] and are never stored.
+// Indicators sent by this user (received via self processing) and indicators
+// older than TypingTimeout are dropped.
+//
+// Always returns a 0 UUID.
+func (r *receiver) receiveTyping(messageID message.ID, messageType MessageType,
+	nickname string, content []byte, partnerPubKey,
+	senderPubKey ed25519.PublicKey, timestamp time.Time,
+	round rounds.Round) (uint64, error) {
+	var msg Typing
+	if err := proto.Unmarshal(content, &msg); err != nil {
+		return 0, errors.Wrapf(err,
+			"failed unmarshal DM %s from %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	tag := makeDebugTag(partnerPubKey, content, SendTypingTag)
+
+	// Ignore our own typing indicators
+	if bytes.Equal(senderPubKey, r.c.me.PubKey) {
+		jww.TRACE.Printf("[%s] DM - Dropping own typing indicator %s",
+			tag, messageID)
+		return 0, nil
+	}
+
+	if age := netTime.Since(timestamp); age > TypingTimeout {
+		jww.DEBUG.Printf("[%s] DM - Dropping stale typing indicator %s "+
+			"from %s (age %s)", tag, messageID,
+			base64.RawStdEncoding.EncodeToString(senderPubKey), age)
+		return 0, nil
+	}
+
+	jww.DEBUG.Printf("[%s] DM - Received typing indicator (%t) from %s",
+		tag, msg.Typing, base64.RawStdEncoding.EncodeToString(senderPubKey))
+
+	go r.c.cbs.UserTyping(partnerPubKey, nickname, msg.Typing)
+
+	return 0, nil
+}
+
+// receiveReadReceipt processes a read receipt. A receipt from the partner marks
+// the messages this user sent to them as read. A receipt sent by this user
+// (received via self processing from another instance of this user) marks the
+// messages received from the partner as read.
+//
+// Always returns a 0 UUID.
+func (r *receiver) receiveReadReceipt(messageID message.ID,
+	messageType MessageType, content []byte, partnerPubKey,
+	senderPubKey ed25519.PublicKey, timestamp time.Time,
+	round rounds.Round) (uint64, error) {
+	var receipt ReadReceipt
+	if err := proto.Unmarshal(content, &receipt); err != nil {
+		return 0, errors.Wrapf(err,
+			"failed unmarshal DM %s from %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	upTo, err := message.UnmarshalID(receipt.UpToMessageID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to unmarshal read up to message "+
+			"ID in DM for message %s with partner key %X on type %s, ts: %s, "+
+			"round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	tag := makeDebugTag(partnerPubKey, upTo.Marshal(), MarkReadTag)
+
+	// The messages that were read are those sent by the other side of the
+	// conversation
+	readSender := r.c.me.PubKey
+	if bytes.Equal(senderPubKey, r.c.me.PubKey) {
+		readSender = partnerPubKey
+	}
+
+	jww.INFO.Printf("[%s] DM - Received read receipt up to %s from %X",
+		tag, upTo, senderPubKey)
+
+	r.api.UpdateReadStatus(partnerPubKey, readSender, upTo)
+
+	return 0, nil
+}
+
+// receiveRetention processes a message setting the retention timer of the
+// conversation. The timer is only updated if it was set after the current
+// timer. The message is passed to the EventModel as JSON so that the UI can
+// show that the timer changed.
+func (r *receiver) receiveRetention(messageID message.ID,
+	messageType MessageType, nickname string, content []byte,
+	dmToken uint32, partnerPubKey, senderPubKey ed25519.PublicKey,
+	timestamp time.Time, round rounds.Round, status Status) (uint64, error) {
+	retentionMsg := &Retention{}
+	if err := proto.Unmarshal(content, retentionMsg); err != nil {
+		return 0, errors.Wrapf(err,
+			"failed unmarshal DM %s from %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	retention := time.Duration(retentionMsg.Retention)
+	if retention < 0 || (retention > 0 && retention < MinRetention) {
+		return 0, errors.Errorf("invalid retention %s in DM %s from %x, "+
+			"type %s, ts: %s, round: %d: %+v", retention, messageID,
+			partnerPubKey, messageType, timestamp, round.ID,
+			InvalidRetentionErr)
+	}
+
+	// The timer is set locally when sending, so pending sends, which have a
+	// placeholder timestamp, are not applied
+	tag := makeDebugTag(partnerPubKey, content, SetRetentionTag)
+	if status != Unsent {
+		updated, err := r.c.retention.set(partnerPubKey, retention, timestamp)
+		if err != nil {
+			return 0, errors.Wrapf(err, "[%s] failed to set retention of DM "+
+				"conversation with %X", tag, partnerPubKey)
+		}
+		jww.INFO.Printf("[%s] DM - Received retention %s from %X "+
+			"(updated: %t)", tag, retention, senderPubKey, updated)
+	}
+
+	var retentionJson bytes.Buffer
+	enc := json.NewEncoder(&retentionJson)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(retentionMsg); err != nil {
+		return 0, errors.Wrapf(err, "Failed to json marshal DM %s "+
+			"with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	return r.api.Receive(messageID, nickname, retentionJson.Bytes(),
+		partnerPubKey, senderPubKey, dmToken, 0, timestamp, round,
+		RetentionType, status), nil
+}
+
 // This helper does the opposite of "createCMIXFields" in send.go
 func reconstructCiphertext(msg format.Message) []byte {
 	var res []byte
@@ -0,0 +1,158 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+)
+
+// NoMessageRequestErr is returned when accepting or declining a message request
+// from a partner who does not have a pending request.
+var NoMessageRequestErr = errors.New("no pending message request from partner")
+
+// PendingMessageRequestErr is returned when changing the notification level of
+// a partner who has a pending message request.
+var PendingMessageRequestErr = errors.New(
+	"partner has a pending message request that must be accepted first")
+
+// Message requests
+//
+// The first time a partner the user has never talked to sends them a DM, the
+// partner is added to the partner store with statusRequest and their
+// conversation is held as a message request until the user accepts, declines,
+// or blocks it. Only messages with content that can be shown in the request
+// (see MessageType.allowedInRequest) are passed to the EventModel; all other
+// messages from the partner, such as reactions, replies, typing indicators,
+// read receipts, deletions, and retention timers, are dropped until the request
+// is accepted. Group DMs never open a request; a new group is only learned
+// from a creator the user has accepted. Request conversations are marked in the EventModel with
+// EventModel.UpdateMessageRequest so that they can be kept in a separate
+// inbox. Partners with a pending request never trigger notifications and their
+// notification level cannot be changed until the request is accepted.
+//
+// Sending a message to a partner implicitly accepts their request, so partners
+// the user contacts first never become a request.
+
+// AcceptMessageRequest accepts the pending message request from the partner.
+// After accepting, all messages from the partner are received and
+// notifications are enabled. Returns NoMessageRequestErr if there is no pending request.
+func (dc *dmClient) AcceptMessageRequest(partnerPubKey ed25519.PublicKey) error {
+	if !dc.IsMessageRequest(partnerPubKey) {
+		return NoMessageRequestErr
+	}
+
+	jww.INFO.Printf("[DM] Accepted message request from %X", partnerPubKey)
+	dc.ps.set(partnerPubKey, defaultStatus)
+	return nil
+}
+
+// DeclineMessageRequest declines the pending message request from the partner
+// and removes it from the message request inbox. Messages that were already
+// received are not deleted. If the partner sends another message, a new
+// request is created; use [dmClient.BlockPartner] to stop receiving messages
+// from them. Returns NoMessageRequestErr if there is no pending request.
+func (dc *dmClient) DeclineMessageRequest(partnerPubKey ed25519.PublicKey) error {
+	if !dc.IsMessageRequest(partnerPubKey) {
+		return NoMessageRequestErr
+	}
+
+	jww.INFO.Printf("[DM] Declined message request from %X", partnerPubKey)
+	dc.ps.delete(partnerPubKey)
+	return nil
+}
+
+// IsMessageRequest indicates if the partner has a pending message request.
+func (dc *dmClient) IsMessageRequest(partnerPubKey ed25519.PublicKey) bool {
+	partner, exists := dc.ps.get(partnerPubKey)
+	if !exists {
+		return false
+	}
+
+	return partner.Status == statusRequest
+}
+
+// GetMessageRequests returns all partners with a pending message request.
+func (dc *dmClient) GetMessageRequests() []ed25519.PublicKey {
+	var requests []ed25519.PublicKey
+	init := func(n int) {
+		requests = make([]ed25519.PublicKey, 0, n)
+	}
+
+	add := func(partner *dmPartner) {
+		if partner.Status == statusRequest {
+			requests = append(requests, partner.PublicKey)
+		}
+	}
+
+	dc.ps.iterate(init, add)
+
+	return requests
+}
+
+// acceptOnSend adds the partner to the partner store with the default status
+// if they do not exist or if they have a pending message request. It is called
+// when sending a message to the partner.
+func (dc *dmClient) acceptOnSend(partnerPubKey ed25519.PublicKey) {
+	partner, exists := dc.ps.get(partnerPubKey)
+	if !exists || partner.Status == statusRequest {
+		dc.ps.set(partnerPubKey, defaultStatus)
+	}
+}
+
+// allowedInRequest returns true if messages of the MessageType are received
+// from a partner with a pending message request. Only these messages can open
+// a new request. Only content that is shown in the request is allowed; control
+// messages, such as read receipts, deletions, retention timers, group DMs, and
+// channel admin keys, are held back until the request is accepted.
+func (mt MessageType) allowedInRequest() bool {
+	switch mt {
+	case TextType, InvitationType:
+		return true
+	default:
+		return false
+	}
+}
+
+// isAccepted returns true if the partner is in the partner store and has
+// neither a pending message request nor been blocked.
+func (dc *dmClient) isAccepted(partnerPubKey ed25519.PublicKey) bool {
+	partner, exists := dc.ps.get(partnerPubKey)
+	return exists &&
+		partner.Status != statusRequest && partner.Status != statusBlocked
+}
+
+// updateMessageRequests is a callback registered on the partnerStore to receive
+// updates about new and resolved message requests.
+func updateMessageRequests(
+	cb func(partnerPubKey ed25519.PublicKey, pending bool)) func(edits []elementEdit) {
+	return func(edits []elementEdit) {
+		for _, e := range edits {
+			switch e.operation {
+			case versioned.Created, versioned.Loaded:
+				if e.new.Status == statusRequest {
+					go cb(e.new.PublicKey, true)
+				}
+			case versioned.Updated:
+				if e.old.Status == statusRequest && e.new.Status != statusRequest {
+					go cb(e.new.PublicKey, false)
+				} else if e.old.Status != statusRequest && e.new.Status == statusRequest {
+					go cb(e.new.PublicKey, true)
+				}
+			case versioned.Deleted:
+				if e.old.Status == statusRequest {
+					go cb(e.old.PublicKey, false)
+				}
+			}
+		}
+	}
+}
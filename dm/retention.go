@@ -0,0 +1,475 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// Thread stoppable name
+	retentionThreadStoppable = "DmRetentionThread"
+
+	// dmClientStoppable is the name of the stoppable that holds the processes
+	// started by the DM client.
+	dmClientStoppable = "DmClient"
+
+	// retentionCheckFrequency is how often expired messages are deleted.
+	retentionCheckFrequency = 30 * time.Second
+
+	// MinRetention is the shortest retention timer that can be set on a
+	// conversation.
+	MinRetention = time.Minute
+)
+
+// InvalidRetentionErr is returned when setting a retention timer that is
+// negative or shorter than MinRetention.
+var InvalidRetentionErr = errors.Errorf(
+	"retention must be zero or at least %s", MinRetention)
+
+// retentionManager keeps track of the retention timer set on each conversation
+// and deletes messages once their timer expires.
+//
+// A message expires once it is older than the retention timer of its
+// conversation. Expired messages are deleted from the EventModel by the thread
+// started by StartProcesses. If the EventModel implements MessageExpirer, this
+// includes messages sent or received before the timer was set. Otherwise, each
+// message sent or received while the timer is set is tracked and deleted with
+// EventModel.DeleteMessage once it expires.
+type retentionManager struct {
+	// timers is a map of partner public keys (encoded with
+	// marshalElementName) to their conversation's retention timer.
+	timers map[string]retentionTimer
+
+	// expiring is a map of partner public keys (encoded with
+	// marshalElementName) to the tracked messages in their conversation,
+	// ordered by timestamp.
+	expiring map[string][]expiringMessage
+
+	// model is the EventModel that expired messages are deleted from.
+	model EventModel
+
+	// trackMessages is true if the model needs each expiring message to be
+	// tracked because it cannot delete messages by age.
+	trackMessages bool
+
+	kv  versioned.KV
+	mux sync.Mutex
+}
+
+// expiringMessage is a message tracked so that it can be deleted with
+// EventModel.DeleteMessage once it expires.
+type expiringMessage struct {
+	MessageID    cryptoMessage.ID  `json:"messageID"`
+	SenderPubKey ed25519.PublicKey `json:"senderPubKey"`
+	Timestamp    time.Time         `json:"timestamp"`
+}
+
+// expiredMessageDeleter is implemented by event models that wrap other event
+// models so that expired messages are deleted from each wrapped model in the
+// way it supports.
+type expiredMessageDeleter interface {
+	// needsTracking returns true if any wrapped model needs expiring messages
+	// to be tracked.
+	needsTracking() bool
+
+	// deleteExpired deletes the expired messages from each wrapped model.
+	deleteExpired(partnerPubKey ed25519.PublicKey, before time.Time,
+		expired []expiringMessage) int
+}
+
+// retentionTimer is the retention timer set on a conversation.
+type retentionTimer struct {
+	// Retention is how long messages are kept for. Zero means forever.
+	Retention time.Duration `json:"retention"`
+
+	// Timestamp is when the timer was set. A timer is only replaced by a timer
+	// set at the same time or later.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// newRetentionManager loads the retentionManager from storage. If none exists
+// in storage, a new one is initialised.
+func newRetentionManager(
+	kv versioned.KV, model EventModel) (*retentionManager, error) {
+	rm := &retentionManager{
+		timers:        make(map[string]retentionTimer),
+		expiring:      make(map[string][]expiringMessage),
+		model:         model,
+		trackMessages: needsTracking(model),
+		kv:            kv,
+	}
+
+	if err := rm.load(); err != nil {
+		return nil, err
+	}
+
+	return rm, nil
+}
+
+// get returns the retention timer for the conversation. Returns zero if no
+// timer is set.
+func (rm *retentionManager) get(partnerPubKey ed25519.PublicKey) time.Duration {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	return rm.timers[marshalElementName(partnerPubKey)].Retention
+}
+
+// set sets the retention timer for the conversation if it was set at the same
+// time as or later than the current timer. Returns true if the timer was
+// updated.
+func (rm *retentionManager) set(partnerPubKey ed25519.PublicKey,
+	retention time.Duration, timestamp time.Time) (bool, error) {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	key := marshalElementName(partnerPubKey)
+	if current, exists := rm.timers[key]; exists &&
+		timestamp.Before(current.Timestamp) {
+		return false, nil
+	}
+
+	if retention == 0 {
+		// Messages are no longer tracked once the timer is disabled
+		delete(rm.timers, key)
+		if _, exists := rm.expiring[key]; exists {
+			delete(rm.expiring, key)
+			if err := rm.storeExpiring(partnerPubKey); err != nil {
+				return true, err
+			}
+		}
+	} else {
+		rm.timers[key] = retentionTimer{retention, timestamp.UTC().Round(0)}
+	}
+
+	return true, rm.storeTimers()
+}
+
+// track records a message sent or received in the conversation so that it can
+// be deleted once it expires. Messages are only tracked while the conversation
+// has a retention timer and if the EventModel cannot delete messages by age.
+func (rm *retentionManager) track(partnerPubKey ed25519.PublicKey,
+	messageID cryptoMessage.ID, senderPubKey ed25519.PublicKey,
+	timestamp time.Time) error {
+	if !rm.trackMessages {
+		return nil
+	}
+
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	key := marshalElementName(partnerPubKey)
+	if _, exists := rm.timers[key]; !exists {
+		return nil
+	}
+
+	messages := rm.expiring[key]
+	for _, em := range messages {
+		if em.MessageID == messageID {
+			return nil
+		}
+	}
+
+	em := expiringMessage{messageID, senderPubKey, timestamp.UTC().Round(0)}
+	i := sort.Search(len(messages), func(i int) bool {
+		return messages[i].Timestamp.After(em.Timestamp)
+	})
+	messages = append(messages, expiringMessage{})
+	copy(messages[i+1:], messages[i:])
+	messages[i] = em
+	rm.expiring[key] = messages
+
+	return rm.storeExpiring(partnerPubKey)
+}
+
+// purge deletes all messages that are older than the retention timer of their
+// conversation at the given time. Returns the number of messages deleted from
+// the EventModel.
+func (rm *retentionManager) purge(now time.Time) (int, error) {
+	type conversation struct {
+		partnerPubKey ed25519.PublicKey
+		before        time.Time
+		expired       []expiringMessage
+	}
+
+	var err error
+	rm.mux.Lock()
+	conversations := make([]conversation, 0, len(rm.timers))
+	for key, timer := range rm.timers {
+		partnerPubKey, errUnmarshal := unmarshalElementName(key)
+		if errUnmarshal != nil {
+			err = errors.Wrapf(errUnmarshal,
+				"failed to unmarshal partner public key %q", key)
+			continue
+		}
+
+		before := now.Add(-timer.Retention)
+		messages := rm.expiring[key]
+		i := sort.Search(len(messages), func(i int) bool {
+			return !messages[i].Timestamp.Before(before)
+		})
+		expired := make([]expiringMessage, i)
+		copy(expired, messages[:i])
+		conversations = append(conversations,
+			conversation{partnerPubKey, before, expired})
+	}
+	rm.mux.Unlock()
+
+	var deleted int
+	for _, c := range conversations {
+		deleted += deleteExpired(rm.model, c.partnerPubKey, c.before, c.expired)
+
+		// Stop tracking the deleted messages once they are gone from the
+		// model so that they are retried if the client stops before then
+		if len(c.expired) > 0 {
+			if errForget := rm.forget(c.partnerPubKey, c.expired); errForget != nil {
+				err = errForget
+			}
+		}
+	}
+
+	return deleted, err
+}
+
+// forget stops tracking the messages in the conversation.
+func (rm *retentionManager) forget(
+	partnerPubKey ed25519.PublicKey, messages []expiringMessage) error {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	key := marshalElementName(partnerPubKey)
+	forgotten := make(map[cryptoMessage.ID]bool, len(messages))
+	for _, em := range messages {
+		forgotten[em.MessageID] = true
+	}
+
+	var kept []expiringMessage
+	for _, em := range rm.expiring[key] {
+		if !forgotten[em.MessageID] {
+			kept = append(kept, em)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(rm.expiring, key)
+	} else {
+		rm.expiring[key] = kept
+	}
+
+	return rm.storeExpiring(partnerPubKey)
+}
+
+// needsTracking returns true if expiring messages must be tracked so that they
+// can be deleted from the EventModel with EventModel.DeleteMessage.
+func needsTracking(model EventModel) bool {
+	switch m := model.(type) {
+	case expiredMessageDeleter:
+		return m.needsTracking()
+	case MessageExpirer:
+		return false
+	default:
+		return true
+	}
+}
+
+// deleteExpired deletes the expired messages in the conversation from the
+// EventModel. Models that implement MessageExpirer delete every message older
+// than before; otherwise, each tracked expired message is deleted with
+// EventModel.DeleteMessage. Returns the number of messages deleted.
+func deleteExpired(model EventModel, partnerPubKey ed25519.PublicKey,
+	before time.Time, expired []expiringMessage) int {
+	switch m := model.(type) {
+	case expiredMessageDeleter:
+		return m.deleteExpired(partnerPubKey, before, expired)
+	case MessageExpirer:
+		return m.DeleteMessagesBefore(partnerPubKey, before)
+	}
+
+	var deleted int
+	for _, em := range expired {
+		if model.DeleteMessage(em.MessageID, em.SenderPubKey) {
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// StartProcesses starts the thread that deletes messages once the retention
+// timer of their conversation expires and the thread that sends scheduled
+// messages. This function adheres to the [xxdk.Service] type.
+func (dc *dmClient) StartProcesses() (stoppable.Stoppable, error) {
+	retentionStop := stoppable.NewSingle(retentionThreadStoppable)
+
+	// Start the thread
+	go dc.retention.purgeThread(retentionStop)
+
+	schedulerStop, err := dc.scheduler.StartProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	multi := stoppable.NewMulti(dmClientStoppable)
+	multi.Add(retentionStop)
+	multi.Add(schedulerStop)
+	return multi, nil
+}
+
+// purgeThread periodically deletes all expired messages. Messages that expired
+// while the client was stopped are deleted immediately on start.
+func (rm *retentionManager) purgeThread(stop *stoppable.Single) {
+	jww.INFO.Printf("[DM] Starting retention thread with stoppable %s",
+		stop.Name())
+
+	purge := func() {
+		n, err := rm.purge(netTime.Now())
+		if err != nil {
+			jww.ERROR.Printf("[DM] Failed to purge expired messages: %+v", err)
+		} else if n > 0 {
+			jww.DEBUG.Printf("[DM] Deleted %d expired messages.", n)
+		}
+	}
+
+	purge()
+
+	ticker := time.NewTicker(retentionCheckFrequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop.Quit():
+			jww.INFO.Printf("[DM] Stopping retention thread: stoppable %s "+
+				"quit", stop.Name())
+			stop.ToStopped()
+			return
+		case <-ticker.C:
+			purge()
+		}
+	}
+}
+
+// GetRetention returns the retention timer set on the conversation with the
+// partner. Returns zero if messages are kept forever.
+func (dc *dmClient) GetRetention(partnerPubKey ed25519.PublicKey) time.Duration {
+	return dc.retention.get(partnerPubKey)
+}
+
+// isStored returns true if messages of the MessageType are stored in the
+// EventModel and so need to be deleted when they expire.
+func (mt MessageType) isStored() bool {
+	switch mt {
+	case DeleteType, TypingType, ReadReceiptType, GroupType, AdminKeyType:
+		return false
+	default:
+		return true
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage values.
+const (
+	retentionTimersVer        = 0
+	retentionTimersKey        = "dmRetentionTimers"
+	expiringMessagesVer       = 0
+	expiringMessagesKeyPrefix = "dmExpiringMessages/"
+)
+
+// Error messages.
+const (
+	// retentionManager.load
+	loadRetentionTimersErr  = "could not load retention timers"
+	loadExpiringMessagesErr = "could not load expiring messages of conversation %q"
+)
+
+// load loads the timers and the messages tracked in each conversation with a
+// timer from storage. Nothing is loaded if they do not exist in storage.
+func (rm *retentionManager) load() error {
+	obj, err := rm.kv.Get(retentionTimersKey, retentionTimersVer)
+	if err == nil {
+		err = json.Unmarshal(obj.Data, &rm.timers)
+	}
+	if err != nil && rm.kv.Exists(err) {
+		return errors.Wrap(err, loadRetentionTimersErr)
+	}
+
+	for key := range rm.timers {
+		partnerPubKey, err2 := unmarshalElementName(key)
+		if err2 != nil {
+			return errors.Wrapf(err2, loadExpiringMessagesErr, key)
+		}
+
+		var messages []expiringMessage
+		obj, err2 = rm.kv.Get(
+			makeExpiringMessagesKey(partnerPubKey), expiringMessagesVer)
+		if err2 == nil {
+			err2 = json.Unmarshal(obj.Data, &messages)
+		}
+		if err2 != nil && rm.kv.Exists(err2) {
+			return errors.Wrapf(err2, loadExpiringMessagesErr, key)
+		} else if len(messages) > 0 {
+			rm.expiring[key] = messages
+		}
+	}
+
+	return nil
+}
+
+// storeTimers saves the retention timers to storage.
+func (rm *retentionManager) storeTimers() error {
+	data, err := json.Marshal(rm.timers)
+	if err != nil {
+		return err
+	}
+
+	return rm.kv.Set(retentionTimersKey, &versioned.Object{
+		Version:   retentionTimersVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	})
+}
+
+// storeExpiring saves the messages tracked in the conversation to storage. The
+// storage entry is deleted if no messages are tracked.
+func (rm *retentionManager) storeExpiring(partnerPubKey ed25519.PublicKey) error {
+	key := makeExpiringMessagesKey(partnerPubKey)
+	messages := rm.expiring[marshalElementName(partnerPubKey)]
+	if len(messages) == 0 {
+		return rm.kv.Delete(key, expiringMessagesVer)
+	}
+
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	return rm.kv.Set(key, &versioned.Object{
+		Version:   expiringMessagesVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	})
+}
+
+// makeExpiringMessagesKey generates the storage key for the messages tracked
+// in the conversation with the partner.
+func makeExpiringMessagesKey(partnerPubKey ed25519.PublicKey) string {
+	return expiringMessagesKeyPrefix + hex.EncodeToString(partnerPubKey)
+}
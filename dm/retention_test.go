@@ -0,0 +1,240 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/collective"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that retentionManager.set only replaces a timer with one set at the
+// same time or later and that a zero retention removes the timer.
+func Test_retentionManager_set(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	rm, err := newRetentionManager(kv, &mockReceiver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prng := rand.New(rand.NewSource(4321))
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	now := netTime.Now()
+
+	tests := []struct {
+		retention time.Duration
+		timestamp time.Time
+		updated   bool
+		expected  time.Duration
+	}{
+		{time.Hour, now, true, time.Hour},
+		{time.Minute, now.Add(-time.Second), false, time.Hour},
+		{time.Minute, now, true, time.Minute},
+		{0, now.Add(time.Second), true, 0},
+	}
+
+	for i, tt := range tests {
+		updated, err2 := rm.set(pubKey, tt.retention, tt.timestamp)
+		if err2 != nil {
+			t.Fatalf("Failed to set retention (%d): %+v", i, err2)
+		} else if updated != tt.updated {
+			t.Errorf("Unexpected update result (%d).\nexpected: %t"+
+				"\nreceived: %t", i, tt.updated, updated)
+		}
+
+		if r := rm.get(pubKey); r != tt.expected {
+			t.Errorf("Unexpected retention (%d).\nexpected: %s\nreceived: %s",
+				i, tt.expected, r)
+		}
+	}
+}
+
+// Tests that retentionManager.purge deletes the messages older than the timer
+// of every conversation with a timer from an EventModel that implements
+// MessageExpirer and that the timers are loaded from storage.
+func Test_retentionManager_purge(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	model := &mockReceiver{}
+	rm, err := newRetentionManager(kv, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prng := rand.New(rand.NewSource(8765))
+	hour, _, _ := ed25519.GenerateKey(prng)
+	day, _, _ := ed25519.GenerateKey(prng)
+	now := netTime.Now()
+
+	if _, err = rm.set(hour, time.Hour, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rm.set(day, 24*time.Hour, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages are not tracked when the model can delete them by age
+	err = rm.track(hour, cryptoMessage.ID{1}, hour, now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to track message: %+v", err)
+	} else if len(rm.expiring) != 0 {
+		t.Errorf("Tracked message for MessageExpirer: %v", rm.expiring)
+	}
+
+	// Reload from storage to simulate a restart
+	rm, err = newRetentionManager(kv, model)
+	if err != nil {
+		t.Fatalf("Failed to load retentionManager: %+v", err)
+	}
+	if r := rm.get(hour); r != time.Hour {
+		t.Errorf("Loaded unexpected retention.\nexpected: %s\nreceived: %s",
+			time.Hour, r)
+	}
+
+	if _, err = rm.purge(now); err != nil {
+		t.Fatalf("Failed to purge: %+v", err)
+	}
+
+	expected := map[string]time.Time{
+		string(hour): now.Add(-time.Hour),
+		string(day):  now.Add(-24 * time.Hour),
+	}
+	if !reflect.DeepEqual(expected, model.DeletedBefore) {
+		t.Errorf("Unexpected deleted messages.\nexpected: %v\nreceived: %v",
+			expected, model.DeletedBefore)
+	}
+	if len(model.Deleted) != 0 {
+		t.Errorf("Messages deleted with DeleteMessage: %v", model.Deleted)
+	}
+}
+
+// Tests that retentionManager.purge deletes each expired message tracked while
+// the conversation has a timer with EventModel.DeleteMessage when the model
+// does not implement MessageExpirer and that the tracked messages are loaded
+// from storage.
+func Test_retentionManager_purge_DeleteMessage(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	model := &messageDeleter{&mockReceiver{}}
+	rm, err := newRetentionManager(kv, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prng := rand.New(rand.NewSource(2468))
+	partner, _, _ := ed25519.GenerateKey(prng)
+	now := netTime.Now()
+
+	// Messages are only tracked once the timer is set
+	err = rm.track(partner, cryptoMessage.ID{1}, partner, now.Add(-3*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to track message: %+v", err)
+	}
+	if _, err = rm.set(partner, time.Hour, now.Add(-3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	tracked := []struct {
+		id        cryptoMessage.ID
+		timestamp time.Time
+	}{
+		{cryptoMessage.ID{3}, now.Add(-30 * time.Minute)},
+		{cryptoMessage.ID{2}, now.Add(-2 * time.Hour)},
+		{cryptoMessage.ID{4}, now.Add(-90 * time.Minute)},
+		{cryptoMessage.ID{2}, now.Add(-2 * time.Hour)},
+	}
+	for i, tt := range tracked {
+		if err = rm.track(partner, tt.id, partner, tt.timestamp); err != nil {
+			t.Fatalf("Failed to track message %d: %+v", i, err)
+		}
+	}
+
+	// Reload from storage to simulate a restart
+	rm, err = newRetentionManager(kv, model)
+	if err != nil {
+		t.Fatalf("Failed to load retentionManager: %+v", err)
+	}
+
+	n, err := rm.purge(now)
+	if err != nil {
+		t.Fatalf("Failed to purge: %+v", err)
+	} else if n != 2 {
+		t.Errorf("Unexpected number of deleted messages."+
+			"\nexpected: %d\nreceived: %d", 2, n)
+	}
+
+	expected := []cryptoMessage.ID{{2}, {4}}
+	if !reflect.DeepEqual(expected, model.EventModel.(*mockReceiver).Deleted) {
+		t.Errorf("Unexpected deleted messages.\nexpected: %v\nreceived: %v",
+			expected, model.EventModel.(*mockReceiver).Deleted)
+	}
+
+	// Deleted messages are no longer tracked and disabling the timer stops
+	// tracking the rest
+	key := marshalElementName(partner)
+	if len(rm.expiring[key]) != 1 ||
+		rm.expiring[key][0].MessageID != (cryptoMessage.ID{3}) {
+		t.Errorf("Unexpected tracked messages: %v", rm.expiring[key])
+	}
+	if _, err = rm.set(partner, 0, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := rm.expiring[key]; exists {
+		t.Errorf("Messages still tracked after timer disabled: %v",
+			rm.expiring[key])
+	}
+	_, err = kv.Get(makeExpiringMessagesKey(partner), expiringMessagesVer)
+	if kv.Exists(err) {
+		t.Errorf("Tracked messages not deleted from storage: %+v", err)
+	}
+}
+
+// Tests that deleteExpired deletes expired messages from each model of a
+// multiEventModel, including models wrapped in middleware, in the way each
+// model supports.
+func Test_deleteExpired_MultiEventModel(t *testing.T) {
+	expirer := &mockReceiver{}
+	deleter := &messageDeleter{&mockReceiver{}}
+	model := NewMultiEventModel(expirer, WrapEventModel(deleter,
+		FilterReceived(func(*ReceivedMessage) bool { return true })))
+
+	if !needsTracking(model) {
+		t.Errorf("Composite model with a model without MessageExpirer " +
+			"must track messages.")
+	}
+	if needsTracking(NewMultiEventModel(expirer)) {
+		t.Errorf("Composite model of MessageExpirer models tracks messages.")
+	}
+
+	prng := rand.New(rand.NewSource(1357))
+	partner, _, _ := ed25519.GenerateKey(prng)
+	before := netTime.Now()
+	expired := []expiringMessage{
+		{cryptoMessage.ID{1}, partner, before.Add(-time.Minute)}}
+	deleteExpired(model, partner, before, expired)
+
+	if !expirer.DeletedBefore[string(partner)].Equal(before) {
+		t.Errorf("Primary model not deleted by age: %v",
+			expirer.DeletedBefore)
+	}
+	deleted := deleter.EventModel.(*mockReceiver).Deleted
+	if !reflect.DeepEqual([]cryptoMessage.ID{{1}}, deleted) {
+		t.Errorf("Secondary model not deleted by message: %v", deleted)
+	}
+}
+
+// messageDeleter is an EventModel that does not implement MessageExpirer.
+type messageDeleter struct{ EventModel }
@@ -0,0 +1,199 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/scheduler"
+)
+
+// scheduledSendsStoreKey is the storage key of the DM scheduler.
+const scheduledSendsStoreKey = "dmScheduledSends"
+
+// Error messages.
+var (
+	// ScheduledSendNotFoundErr is returned when cancelling or rescheduling a
+	// scheduled send that does not exist or was already sent.
+	ScheduledSendNotFoundErr = scheduler.NotFoundErr
+
+	// InvalidScheduleTimeErr is returned when scheduling a send for a time
+	// that is not in the future.
+	InvalidScheduleTimeErr = scheduler.InvalidTimeErr
+)
+
+// ScheduledSend describes a direct message that is queued to be sent to a
+// partner at a future time.
+type ScheduledSend struct {
+	// ID uniquely identifies the scheduled send. It is used to cancel or
+	// reschedule the send.
+	ID uint64 `json:"id"`
+
+	// PartnerPubKey is the public key of the partner the message is sent to.
+	PartnerPubKey ed25519.PublicKey `json:"partnerPubKey"`
+
+	// PartnerToken is the DM token of the partner.
+	PartnerToken uint32 `json:"partnerToken"`
+
+	// MessageType is the type of the message.
+	MessageType MessageType `json:"messageType"`
+
+	// Payload is the message content passed to Client.Send.
+	Payload []byte `json:"payload"`
+
+	// Params are the cMix parameters used when sending the message.
+	Params cmix.CMIXParams `json:"params"`
+
+	// SendAt is the time the message is sent at. If the network is not
+	// healthy at that time, the message is sent once it becomes healthy.
+	SendAt time.Time `json:"sendAt"`
+}
+
+// scheduledSendDetails is everything in a ScheduledSend, other than the
+// partner and the time, that is saved in [scheduler.Send.Details].
+type scheduledSendDetails struct {
+	PartnerToken uint32          `json:"partnerToken"`
+	MessageType  MessageType     `json:"messageType"`
+	Payload      []byte          `json:"payload"`
+	Params       cmix.CMIXParams `json:"params"`
+}
+
+// newScheduledSend builds a ScheduledSend from the send saved in the
+// scheduler.
+func newScheduledSend(s scheduler.Send) (ScheduledSend, error) {
+	var details scheduledSendDetails
+	if err := json.Unmarshal(s.Details, &details); err != nil {
+		return ScheduledSend{}, errors.Wrapf(err,
+			"could not unmarshal scheduled send %d", s.ID)
+	}
+
+	return ScheduledSend{
+		ID:            s.ID,
+		PartnerPubKey: s.Conversation,
+		PartnerToken:  details.PartnerToken,
+		MessageType:   details.MessageType,
+		Payload:       details.Payload,
+		Params:        details.Params,
+		SendAt:        s.SendAt,
+	}, nil
+}
+
+// ScheduleSend queues a raw direct message to be sent to the partner at
+// sendAt. When the time is reached, the message is sent via Client.Send and
+// the result is reported via Callbacks.ScheduledSendUpdate.
+//
+// Returns InvalidScheduleTimeErr if sendAt is not in the future.
+func (dc *dmClient) ScheduleSend(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, messageType MessageType, msg []byte,
+	sendAt time.Time, params cmix.CMIXParams) (ScheduledSend, error) {
+	jww.INFO.Printf("[DM] ScheduleSend %s message to %s at %s", messageType,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), sendAt)
+
+	details, err := json.Marshal(scheduledSendDetails{
+		PartnerToken: partnerToken,
+		MessageType:  messageType,
+		Payload:      msg,
+		Params:       params,
+	})
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	conversation := make([]byte, len(partnerPubKey))
+	copy(conversation, partnerPubKey)
+	s, err := dc.scheduler.Add(conversation, details, sendAt)
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	return newScheduledSend(s)
+}
+
+// ScheduleText queues a text message to be sent to the partner at sendAt. See
+// Client.SendText for details on the message and Client.ScheduleSend for
+// details on scheduling.
+func (dc *dmClient) ScheduleText(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, msg string, sendAt time.Time,
+	params cmix.CMIXParams) (ScheduledSend, error) {
+	txt := &Text{
+		Version: textVersion,
+		Text:    msg,
+	}
+
+	txtMarshaled, err := proto.Marshal(txt)
+	if err != nil {
+		return ScheduledSend{}, err
+	}
+
+	return dc.ScheduleSend(
+		partnerPubKey, partnerToken, TextType, txtMarshaled, sendAt, params)
+}
+
+// GetScheduledSends returns the messages queued to be sent to the partner,
+// sorted by the time they are sent. If partnerPubKey is nil, then the messages
+// queued for all partners are returned.
+func (dc *dmClient) GetScheduledSends(
+	partnerPubKey ed25519.PublicKey) []ScheduledSend {
+	sends := dc.scheduler.Get(partnerPubKey)
+	list := make([]ScheduledSend, 0, len(sends))
+	for _, s := range sends {
+		ss, err := newScheduledSend(s)
+		if err != nil {
+			jww.ERROR.Printf("[DM] %+v", err)
+			continue
+		}
+		list = append(list, ss)
+	}
+	return list
+}
+
+// CancelScheduledSend removes the message from the queue so that it is never
+// sent. Returns ScheduledSendNotFoundErr if the message does not exist or was
+// already sent.
+func (dc *dmClient) CancelScheduledSend(sendID uint64) error {
+	jww.INFO.Printf("[DM] CancelScheduledSend %d", sendID)
+	return dc.scheduler.Cancel(sendID)
+}
+
+// RescheduleSend changes the time the queued message is sent at. Returns
+// ScheduledSendNotFoundErr if the message does not exist or was already sent
+// and InvalidScheduleTimeErr if sendAt is not in the future.
+func (dc *dmClient) RescheduleSend(sendID uint64, sendAt time.Time) error {
+	jww.INFO.Printf("[DM] RescheduleSend %d to %s", sendID, sendAt)
+	_, err := dc.scheduler.Reschedule(sendID, sendAt)
+	return err
+}
+
+// sendScheduled sends the scheduled message through the normal send path and
+// reports the result via Callbacks.ScheduledSendUpdate.
+func (dc *dmClient) sendScheduled(s scheduler.Send) {
+	ss, err := newScheduledSend(s)
+	if err != nil {
+		jww.ERROR.Printf("[DM] Failed to send scheduled send: %+v", err)
+		return
+	}
+
+	pubKeyStr := base64.RawStdEncoding.EncodeToString(ss.PartnerPubKey)
+	jww.INFO.Printf("[DM] Sending scheduled send %d to %s scheduled for %s",
+		ss.ID, pubKeyStr, ss.SendAt)
+	messageID, _, _, err := dc.Send(ss.PartnerPubKey, ss.PartnerToken,
+		ss.MessageType, ss.Payload, ss.Params)
+	if err != nil {
+		jww.ERROR.Printf("[DM] Failed to send scheduled send %d to %s: %+v",
+			ss.ID, pubKeyStr, err)
+	}
+	dc.cbs.ScheduledSendUpdate(ss.ID, ss.PartnerPubKey, messageID, err)
+}
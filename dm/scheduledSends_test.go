@@ -0,0 +1,62 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/scheduler"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a ScheduledSend added via dmClient.ScheduleSend is returned by
+// dmClient.GetScheduledSends with all of its fields after the scheduler is
+// reloaded from storage.
+func Test_dmClient_ScheduleSend_Load(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	s, err := scheduler.NewOrLoad(
+		kv, scheduledSendsStoreKey, newMockClient(t), nil)
+	require.NoError(t, err)
+	dc := &dmClient{scheduler: s}
+
+	prng := rand.New(rand.NewSource(2253))
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	sendAt := netTime.Now().Add(time.Hour)
+	var expected []ScheduledSend
+	for _, offset := range []time.Duration{3, 1, 2} {
+		ss, err2 := dc.ScheduleSend(pubKey, 5, TextType, []byte("hello"),
+			sendAt.Add(offset*time.Minute), cmix.GetDefaultCMIXParams())
+		require.NoError(t, err2)
+		expected = append(expected, ss)
+	}
+	expected = []ScheduledSend{expected[1], expected[2], expected[0]}
+
+	dc.scheduler, err = scheduler.NewOrLoad(
+		kv, scheduledSendsStoreKey, newMockClient(t), nil)
+	require.NoError(t, err)
+	received := dc.GetScheduledSends(pubKey)
+	require.Len(t, received, len(expected))
+	for i, ss := range received {
+		// The stoppable in the cMix params is not saved
+		require.Equal(t, expected[i].Params.DebugTag, ss.Params.DebugTag)
+		ss.Params = expected[i].Params
+		require.Equal(t, expected[i], ss)
+	}
+
+	other, _, _ := ed25519.GenerateKey(prng)
+	require.Empty(t, dc.GetScheduledSends(other))
+	require.Len(t, dc.GetScheduledSends(nil), len(expected))
+}
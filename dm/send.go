@@ -13,6 +13,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
@@ -20,6 +21,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/message"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	"gitlab.com/elixxir/client/v4/emoji"
+	"gitlab.com/elixxir/client/v4/typingIndicator"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	"gitlab.com/elixxir/crypto/dm"
 	"gitlab.com/elixxir/crypto/fastRNG"
@@ -41,6 +43,10 @@ const (
 	invitationVersion = 0
 	silentVersion     = 0
 	deleteVersion     = 0
+	typingVersion     = 0
+	receiptVersion    = 0
+	retentionVersion  = 0
+	adminKeyVersion   = 0
 
 	// SendMessageTag is the base tag used when generating a debug tag for
 	// sending a message.
@@ -66,6 +72,22 @@ const (
 	// delete message.
 	DeleteMessageTag = "Delete"
 
+	// SendTypingTag is the base tag used when generating a debug tag for a
+	// typing indicator.
+	SendTypingTag = "Typing"
+
+	// MarkReadTag is the base tag used when generating a debug tag for a read
+	// receipt.
+	MarkReadTag = "ReadReceipt"
+
+	// SetRetentionTag is the base tag used when generating a debug tag for a
+	// retention timer message.
+	SetRetentionTag = "Retention"
+
+	// SendAdminKeyTag is the base tag used when generating a debug tag for
+	// sending a channel admin key.
+	SendAdminKeyTag = "AdminKey"
+
 	directMessageDebugTag = "dm"
 	// The size of the nonce used in the message ID.
 	messageNonceSize = 4
@@ -207,6 +229,43 @@ func (dc *dmClient) SendSilent(partnerPubKey ed25519.PublicKey,
 		silentMarshaled, params)
 }
 
+// SendTyping is used to signal to the partner that the user started or stopped
+// typing. Typing indicators are sent at low priority, are only valid for
+// TypingTimeout, and are never stored. They are delivered to the partner via
+// [Callbacks.UserTyping].
+//
+// While the user is typing, the UI should call SendTyping with typing set to
+// true every TypingInterval. Sending more often returns TypingRateLimitErr,
+// which can be safely ignored.
+func (dc *dmClient) SendTyping(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, typing bool, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	if !dc.typing.Allow(partnerPubKey, typing, netTime.Now()) {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			TypingRateLimitErr
+	}
+
+	tag := makeDebugTag(partnerPubKey, nil, SendTypingTag)
+	jww.DEBUG.Printf("[DM][%s] SendTyping(%s, %t)", tag,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), typing)
+
+	typingMsg := &Typing{
+		Version: typingVersion,
+		Typing:  typing,
+	}
+
+	params = typingIndicator.Params(params.SetDebugTag(tag))
+
+	typingMarshaled, err := proto.Marshal(typingMsg)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	// Typing indicators are not tracked so that they are never stored
+	return dc.sendMessage(partnerPubKey, partnerToken, TypingType,
+		typingMarshaled, false, params)
+}
+
 // SendInvite is used to send to a DM partner an invitation to another
 // channel.
 func (dc *dmClient) SendInvite(partnerPubKey ed25519.PublicKey,
@@ -257,6 +316,36 @@ func (dc *dmClient) SendInvite(partnerPubKey ed25519.PublicKey,
 		invitationMarshaled, params)
 }
 
+// SendChannelAdminKey is used to send to a DM partner the PEM-encoded admin
+// key of a channel so that they can become the channel's owner. The message is
+// not tracked, so it is never stored in the EventModel; the partner passes the
+// key to their registered AdminKeyReceiver.
+func (dc *dmClient) SendChannelAdminKey(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, channelID *id.ID, adminKey []byte,
+	params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeDebugTag(partnerPubKey, channelID.Marshal(), SendAdminKeyTag)
+	params = params.SetDebugTag(tag)
+
+	jww.INFO.Printf("[DM][%s] SendChannelAdminKey(%s, for %s)", tag,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), channelID)
+
+	adminKeyMsg := &ChannelAdminKey{
+		Version:   adminKeyVersion,
+		ChannelID: channelID.Marshal(),
+		AdminKey:  adminKey,
+	}
+
+	adminKeyMarshaled, err := proto.Marshal(adminKeyMsg)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	// Admin keys are not tracked so that they are never stored
+	return dc.sendMessage(partnerPubKey, partnerToken, AdminKeyType,
+		adminKeyMarshaled, false, params)
+}
+
 // DeleteMessage sends a message to the partner to delete a message this user
 // sent. Also deletes it from the local database.
 func (dc *dmClient) DeleteMessage(partnerPubKey ed25519.PublicKey,
@@ -286,6 +375,84 @@ func (dc *dmClient) DeleteMessage(partnerPubKey ed25519.PublicKey,
 		partnerPubKey, partnerToken, DeleteType, deleteMarshaled, params)
 }
 
+// MarkRead marks all messages received from the partner, up to and including
+// upTo, as read in the local database. If read receipts are enabled for the
+// partner, then a read receipt is sent to them so that the messages are marked
+// as read on their side too. Read receipts are never sent to blocked partners.
+// If no receipt is sent, then MarkRead returns zero values and no error.
+func (dc *dmClient) MarkRead(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, upTo cryptoMessage.ID, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeDebugTag(partnerPubKey, upTo.Marshal(), MarkReadTag)
+	jww.INFO.Printf("[DM][%s] MarkRead(%s)", tag, upTo)
+
+	dc.receiver.UpdateReadStatus(partnerPubKey, partnerPubKey, upTo)
+
+	if !dc.ReadReceiptsEnabled(partnerPubKey) {
+		jww.DEBUG.Printf("[DM][%s] Read receipts disabled for partner %s",
+			tag, base64.RawStdEncoding.EncodeToString(partnerPubKey))
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, nil
+	}
+
+	receipt := &ReadReceipt{
+		Version:       receiptVersion,
+		UpToMessageID: upTo.Marshal(),
+	}
+
+	params = params.SetDebugTag(tag)
+	receiptMarshaled, err := proto.Marshal(receipt)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	// Read receipts are not tracked so that they are never stored
+	return dc.sendMessage(partnerPubKey, partnerToken, ReadReceiptType,
+		receiptMarshaled, false, params)
+}
+
+// SetRetention sets the retention timer of the conversation with the partner
+// and announces it to them. Every message in the conversation, including those
+// sent or received before the timer is set, is deleted from the EventModel once
+// it is older than the timer. A retention of zero disables the timer. The
+// retention must be zero or at least MinRetention.
+//
+// Event models that do not implement [MessageExpirer] are only notified,
+// through [EventModel.DeleteMessage], of messages sent or received while the
+// timer is set.
+//
+// Expired messages are only deleted while the thread started by
+// [dmClient.StartProcesses] is running.
+func (dc *dmClient) SetRetention(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, retention time.Duration, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	if retention < 0 || (retention > 0 && retention < MinRetention) {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			InvalidRetentionErr
+	}
+
+	tag := makeDebugTag(partnerPubKey, nil, SetRetentionTag)
+	jww.INFO.Printf("[DM][%s] SetRetention(%s)", tag, retention)
+
+	_, err := dc.retention.set(partnerPubKey, retention, netTime.Now())
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	retentionMsg := &Retention{
+		Version:   retentionVersion,
+		Retention: int64(retention),
+	}
+
+	params = params.SetDebugTag(tag)
+	retentionMarshaled, err := proto.Marshal(retentionMsg)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return dc.Send(
+		partnerPubKey, partnerToken, RetentionType, retentionMarshaled, params)
+}
+
 // Send is used to send a raw direct message to a DM partner. In general, it
 // should be wrapped in a function that defines the wire protocol.
 //
@@ -298,6 +465,19 @@ func (dc *dmClient) Send(partnerEdwardsPubKey ed25519.PublicKey,
 	partnerToken uint32, messageType MessageType, msg []byte,
 	params cmix.CMIXParams) (
 	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	return dc.sendMessage(
+		partnerEdwardsPubKey, partnerToken, messageType, msg, true, params)
+}
+
+// sendMessage sends a raw direct message to a DM partner.
+//
+// Set tracked to true if the message should be tracked in the SendTracker,
+// which allows messages to be shown locally before they are received on the
+// network. Untracked messages are never passed to the EventModel on send.
+func (dc *dmClient) sendMessage(partnerEdwardsPubKey ed25519.PublicKey,
+	partnerToken uint32, messageType MessageType, msg []byte, tracked bool,
+	params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 
 	if partnerToken == 0 {
 		return cryptoMessage.ID{}, rounds.Round{},
@@ -381,20 +561,28 @@ func (dc *dmClient) Send(partnerEdwardsPubKey ed25519.PublicKey,
 		params.DebugTag = directMessageDebugTag
 	}
 
-	sendPrint += fmt.Sprintf(", pending send %s", netTime.Now())
-	uuid, err := dc.st.DenotePendingSend(partnerEdwardsPubKey,
-		dc.me.PubKey, partnerToken, messageType, directMessage)
-	if err != nil {
-		sendPrint += fmt.Sprintf(", pending send failed %s",
-			err.Error())
-		errDenote := dc.st.FailedSend(uuid)
-		if errDenote != nil {
-			sendPrint += fmt.Sprintf(
-				", failed to denote failed dm send: %s",
-				errDenote.Error())
+	var uuid uint64
+	if tracked {
+		// Messaging a partner accepts any pending message request from them
+		dc.acceptOnSend(partnerEdwardsPubKey)
+
+		sendPrint += fmt.Sprintf(", pending send %s", netTime.Now())
+		uuid, err = dc.st.DenotePendingSend(partnerEdwardsPubKey,
+			dc.me.PubKey, partnerToken, messageType, directMessage)
+		if err != nil {
+			sendPrint += fmt.Sprintf(", pending send failed %s",
+				err.Error())
+			errDenote := dc.st.FailedSend(uuid)
+			if errDenote != nil {
+				sendPrint += fmt.Sprintf(
+					", failed to denote failed dm send: %s",
+					errDenote.Error())
+			}
+			return cryptoMessage.ID{}, rounds.Round{},
+				ephemeral.Id{}, err
 		}
-		return cryptoMessage.ID{}, rounds.Round{},
-			ephemeral.Id{}, err
+	} else {
+		sendPrint += ", not tracked"
 	}
 
 	rndID, ephIDs, err := send(dc.net, dc.selfReceptionID,
@@ -402,11 +590,13 @@ func (dc *dmClient) Send(partnerEdwardsPubKey ed25519.PublicKey,
 		partnerToken, directMessage, params, dc.rng)
 	if err != nil {
 		sendPrint += fmt.Sprintf(", err on send: %+v", err)
-		errDenote := dc.st.FailedSend(uuid)
-		if errDenote != nil {
-			sendPrint += fmt.Sprintf(
-				", failed to denote failed dm send: %s",
-				errDenote.Error())
+		if tracked {
+			errDenote := dc.st.FailedSend(uuid)
+			if errDenote != nil {
+				sendPrint += fmt.Sprintf(
+					", failed to denote failed dm send: %s",
+					errDenote.Error())
+			}
 		}
 		return cryptoMessage.ID{}, rounds.Round{},
 			ephemeral.Id{}, err
@@ -423,10 +613,21 @@ func (dc *dmClient) Send(partnerEdwardsPubKey ed25519.PublicKey,
 	sendPrint += fmt.Sprintf(", send eph %v rnd %s MsgID %s",
 		ephIDs, rndID.ID, msgID)
 
-	err = dc.st.Sent(uuid, msgID, rndID)
-	if err != nil {
-		sendPrint += fmt.Sprintf(", dm send denote failed: %s ",
-			err.Error())
+	if tracked {
+		err = dc.st.Sent(uuid, msgID, rndID)
+		if err != nil {
+			sendPrint += fmt.Sprintf(", dm send denote failed: %s ",
+				err.Error())
+		}
+
+		if messageType.isStored() {
+			errTrack := dc.retention.track(partnerEdwardsPubKey, msgID,
+				dc.me.PubKey, netTime.Now())
+			if errTrack != nil {
+				sendPrint += fmt.Sprintf(
+					", failed to track message expiry: %s", errTrack.Error())
+			}
+		}
 	}
 	return msgID, rndID, ephIDs[1], err
 
@@ -0,0 +1,156 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/cmix/simulated"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/crypto/codename"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+)
+
+// Tests that DM clients on the simulated network can send texts to each other
+// in both directions.
+func TestDMClient_SimulatedNetwork(t *testing.T) {
+	net, err := simulated.NewNetwork(simulated.DefaultParams())
+	if err != nil {
+		t.Fatalf("Failed to create simulated network: %+v", err)
+	}
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+
+	a, aID, aReceived := newSimulatedDMClient(t, net, crng)
+	b, bID, bReceived := newSimulatedDMClient(t, net, crng)
+	params := cmix.GetDefaultCMIXParams()
+
+	text := "Hi"
+	msgID, _, _, err := a.SendText(bID.PubKey, bID.GetDMToken(), text, params)
+	if err != nil {
+		t.Fatalf("Failed to send text: %+v", err)
+	}
+	waitForSimulatedDM(t, bReceived, msgID, text, aID.PubKey)
+
+	reply := "Hello"
+	msgID, _, _, err = b.SendText(aID.PubKey, aID.GetDMToken(), reply, params)
+	if err != nil {
+		t.Fatalf("Failed to send reply: %+v", err)
+	}
+	waitForSimulatedDM(t, aReceived, msgID, reply, bID.PubKey)
+}
+
+// waitForSimulatedDM waits for the text to be received from the sender.
+func waitForSimulatedDM(t *testing.T, received chan mockMessage,
+	msgID cryptoMessage.ID, text string, sender ed25519.PublicKey) {
+	select {
+	case r := <-received:
+		if r.MessageID != msgID {
+			t.Errorf("Received wrong message ID.\nexpected: %s\nreceived: %s",
+				msgID, r.MessageID)
+		}
+		if r.Message != text {
+			t.Errorf("Received wrong text.\nexpected: %q\nreceived: %q",
+				text, r.Message)
+		}
+		if !sender.Equal(r.PubKey) {
+			t.Errorf("Received text from wrong sender."+
+				"\nexpected: %x\nreceived: %x", sender, r.PubKey)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Timed out waiting for %q.", text)
+	}
+}
+
+// newSimulatedDMClient creates a DM client on its own client on the simulated
+// network. Returns the client, its identity, and a channel that receives every
+// text received from a partner. The client stops following the network when
+// the test ends.
+func newSimulatedDMClient(t *testing.T, net *simulated.Network,
+	crng *fastRNG.StreamGenerator) (Client, codename.PrivateIdentity,
+	chan mockMessage) {
+	rng := crng.GetStream()
+	identity, err := codename.GenerateIdentity(rng)
+	rng.Close()
+	if err != nil {
+		t.Fatalf("Failed to generate identity: %+v", err)
+	}
+
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	receptionID := DeriveReceptionID(identity.PubKey, identity.GetDMToken())
+	c := net.NewClient(receptionID, kv, nil)
+	s, err := c.Follow(nil)
+	if err != nil {
+		t.Fatalf("Failed to follow network: %+v", err)
+	}
+	t.Cleanup(func() {
+		if err = s.Close(); err != nil {
+			t.Errorf("Failed to stop following: %+v", err)
+		}
+		if err = stoppable.WaitForStopped(s, 5*time.Second); err != nil {
+			t.Errorf("Failed to wait for stop: %+v", err)
+		}
+	})
+
+	receiver := &simulatedReceiver{
+		mockReceiver: newMockReceiver(),
+		received:     make(chan mockMessage, 10),
+	}
+	dmc, err := NewDMClient(&identity, receiver, NewSendTracker(kv),
+		NewNicknameManager(receptionID, kv), newMockNM(), c, kv, crng, nil)
+	if err != nil {
+		t.Fatalf("Failed to create DM client: %+v", err)
+	}
+
+	return dmc, identity, receiver.received
+}
+
+// simulatedReceiver is a mockReceiver that reports received texts on a
+// channel. Calls to the mockReceiver are serialized since messages arrive from
+// the network concurrently.
+type simulatedReceiver struct {
+	*mockReceiver
+	received chan mockMessage
+	mux      sync.Mutex
+}
+
+func (sr *simulatedReceiver) ReceiveText(messageID cryptoMessage.ID,
+	nickname, text string, partnerKey, senderKey ed25519.PublicKey,
+	dmToken uint32, codeset uint8, timestamp time.Time, round rounds.Round,
+	status Status) uint64 {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	uuid := sr.mockReceiver.ReceiveText(messageID, nickname, text, partnerKey,
+		senderKey, dmToken, codeset, timestamp, round, status)
+	if status == Received && partnerKey.Equal(senderKey) {
+		sr.received <- mockMessage{
+			Message:   text,
+			PubKey:    senderKey,
+			DMToken:   dmToken,
+			MessageID: messageID,
+		}
+	}
+	return uuid
+}
+
+func (sr *simulatedReceiver) UpdateSentStatus(uuid uint64,
+	messageID cryptoMessage.ID, timestamp time.Time, round rounds.Round,
+	status Status) {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	sr.mockReceiver.UpdateSentStatus(uuid, messageID, timestamp, round, status)
+}
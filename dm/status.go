@@ -44,3 +44,30 @@ func (ss Status) String() string {
 		return "Invalid SentStatus: " + strconv.Itoa(int(ss))
 	}
 }
+
+// ReceiptStatus represents whether a direct message has been read by the
+// partner it was sent to.
+type ReceiptStatus uint8
+
+const (
+	// NotRead is the receipt status of a message that has not been read or
+	// for which no read receipt has been received.
+	NotRead ReceiptStatus = iota
+
+	// Read is the receipt status of a message once it has been read.
+	Read
+)
+
+// String returns a human-readable version of [ReceiptStatus], used for
+// debugging and logging. This function adheres to the [fmt.Stringer]
+// interface.
+func (rs ReceiptStatus) String() string {
+	switch rs {
+	case NotRead:
+		return "not read"
+	case Read:
+		return "read"
+	default:
+		return "Invalid ReceiptStatus: " + strconv.Itoa(int(rs))
+	}
+}
@@ -28,3 +28,11 @@ func TestStatusString(t *testing.T) {
 	require.Equal(t, failed.String(), "failed")
 	require.Equal(t, invalid.String(), "Invalid SentStatus: 4")
 }
+
+// TestReceiptStatusString strings should never change, so lock them with a
+// test.
+func TestReceiptStatusString(t *testing.T) {
+	require.Equal(t, NotRead.String(), "not read")
+	require.Equal(t, Read.String(), "read")
+	require.Equal(t, ReceiptStatus(2).String(), "Invalid ReceiptStatus: 2")
+}
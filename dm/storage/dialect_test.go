@@ -0,0 +1,277 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in WASM.
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// testDialectors lists the databases that TestDialects runs the event model
+// test suite against. Each function returns a dialector for an empty database.
+//
+// SQLite is always tested, both as SQLite and as a generic database without
+// the SQLite specific features. To test another database, register its dialector
+// from a file with a build tag so that its driver is only required when the tag
+// is set. For example:
+//
+//	//go:build postgres
+//
+//	func init() {
+//		testDialectors["postgres"] = func(*testing.T) gorm.Dialector {
+//			return postgres.Open(os.Getenv("POSTGRES_TEST_DSN"))
+//		}
+//	}
+var testDialectors = map[string]func(t *testing.T) gorm.Dialector{
+	"sqlite": func(t *testing.T) gorm.Dialector {
+		return sqlite.Open(fmt.Sprintf(temporaryDbPath, t.Name()))
+	},
+	"generic": func(t *testing.T) gorm.Dialector {
+		return genericDialector{sqlite.Open(
+			fmt.Sprintf(temporaryDbPath, t.Name()) + "&_foreign_keys=on")}
+	},
+}
+
+// genericDialector is a SQLite dialector that reports a different name. The
+// event model only enables SQLite features, such as the full-text search
+// index, when the dialector is named SQLite, so this tests the path taken by
+// every other database.
+type genericDialector struct {
+	gorm.Dialector
+}
+
+// Name returns a name that is not SQLite.
+func (genericDialector) Name() string { return "generic" }
+
+// Runs the dialect-agnostic event model test suite against every database in
+// testDialectors.
+func TestDialects(t *testing.T) {
+	tests := map[string]func(t *testing.T, model *impl){
+		"Messages": testDialectMessages,
+		"Groups":   testDialectGroups,
+		"Search":   testDialectSearch,
+	}
+
+	for name, newDialector := range testDialectors {
+		t.Run(name, func(t *testing.T) {
+			for testName, test := range tests {
+				t.Run(testName, func(t *testing.T) {
+					test(t, newDialectImpl(t, newDialector(t)))
+				})
+			}
+		})
+	}
+}
+
+// newDialectImpl opens a new impl with the dialector. All tables are dropped
+// when the test completes so that the next test starts with an empty database.
+func newDialectImpl(t *testing.T, dialector gorm.Dialector) *impl {
+	model, err := newImplWithDialector(dialector, &dummyCallbacks{})
+	if err != nil {
+		t.Fatalf("Failed to open %s database: %+v", dialector.Name(), err)
+	}
+
+	t.Cleanup(func() {
+		err2 := model.db.Migrator().DropTable(
+			&GroupAdmin{}, &GroupMember{}, &Message{}, &Conversation{})
+		if err2 != nil {
+			t.Errorf("Failed to drop tables: %+v", err2)
+		}
+		if sqlDb, err2 := model.db.DB(); err2 == nil {
+			_ = sqlDb.Close()
+		}
+	})
+
+	return model
+}
+
+// Tests that the tables created on MySQL are valid. MySQL rejects a literal
+// default on TEXT and BLOB columns, so none may have one, and the search text
+// must be a TEXT column so that it fits messages of any length. The statements
+// are generated with a dry run, so no server is needed.
+func TestMySQLSchema(t *testing.T) {
+	ddl := &ddlLogger{Interface: logger.Discard}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(127.0.0.1:3306)/db",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: ddl})
+	if err != nil {
+		t.Fatalf("Failed to open MySQL dialector: %+v", err)
+	}
+
+	err = db.Migrator().CreateTable(
+		&Conversation{}, &Message{}, &GroupMember{}, &GroupAdmin{})
+	if err != nil {
+		t.Fatalf("Failed to create tables: %+v", err)
+	}
+
+	textDefault := regexp.MustCompile(
+		"` (tiny|medium|long)?(text|blob)[^,]* DEFAULT ")
+	var messagesTable string
+	for _, statement := range ddl.statements {
+		if column := textDefault.FindString(statement); column != "" {
+			t.Errorf("TEXT or BLOB column has a default: %s\n%s",
+				column, statement)
+		}
+		if strings.HasPrefix(statement, "CREATE TABLE `dm_messages`") {
+			messagesTable = statement
+		}
+	}
+
+	if !strings.Contains(messagesTable, "`search_text` longtext,") {
+		t.Errorf("Search text column is not a nullable TEXT column: %s",
+			messagesTable)
+	}
+}
+
+// ddlLogger is a gorm logger that records the SQL of every statement.
+type ddlLogger struct {
+	logger.Interface
+	statements []string
+}
+
+func (l *ddlLogger) Trace(_ context.Context, _ time.Time,
+	fc func() (sql string, rowsAffected int64), _ error) {
+	sql, _ := fc()
+	l.statements = append(l.statements, sql)
+}
+
+// Tests that the conversation is created for a received message and that
+// messages can be read and deleted.
+func testDialectMessages(t *testing.T, model *impl) {
+	me := ed25519.PublicKey("me")
+	partner := ed25519.PublicKey("partner")
+	timestamp := time.Now().Round(time.Second).UTC()
+
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	model.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		timestamp, rounds.Round{ID: 1}, dm.Received)
+	convo := model.GetConversation(partner)
+	if convo == nil || convo.Nickname != "partner" || convo.Token != 5 ||
+		convo.IsGroup {
+		t.Errorf("Unexpected conversation: %+v", convo)
+	}
+	if convos := model.GetConversations(); len(convos) != 1 {
+		t.Errorf("Unexpected number of conversations."+
+			"\nexpected: %d\nreceived: %d", 1, len(convos))
+	}
+
+	replyID := message.DeriveChannelMessageID(&id.ID{1}, 2, []byte("reply"))
+	model.ReceiveReply(replyID, msgID, "me", "reply", partner, me, 6, 0,
+		timestamp.Add(time.Second), rounds.Round{ID: 2}, dm.Received)
+
+	model.UpdateReadStatus(partner, me, replyID)
+	msg := &Message{}
+	err := model.db.Where("message_id = ?", replyID.Bytes()).Take(msg).Error
+	if err != nil {
+		t.Fatalf("Failed to get reply: %+v", err)
+	}
+	if dm.ReceiptStatus(msg.ReceiptStatus) != dm.Read {
+		t.Errorf("Reply not marked as read: %s",
+			dm.ReceiptStatus(msg.ReceiptStatus))
+	}
+
+	if !model.DeleteMessage(replyID, me) {
+		t.Error("Failed to delete reply.")
+	}
+	if model.DeleteMessage(replyID, me) {
+		t.Error("Deleted reply twice.")
+	}
+}
+
+// Tests that groups can be created and updated.
+func testDialectGroups(t *testing.T, model *impl) {
+	group := dm.ModelGroup{
+		GroupID: ed25519.PublicKey("groupID"),
+		Name:    "group",
+		Members: []dm.GroupMember{
+			{PubKey: ed25519.PublicKey("alice"), Token: 1},
+			{PubKey: ed25519.PublicKey("bob"), Token: 2},
+		},
+	}
+	model.UpdateGroup(group)
+
+	group.Name = "new name"
+	group.Members = group.Members[1:]
+	group.Admins = []ed25519.PublicKey{group.Members[0].PubKey}
+	model.UpdateGroup(group)
+
+	received := model.GetGroup(group.GroupID)
+	if received == nil || !reflect.DeepEqual(group, *received) {
+		t.Errorf("Unexpected group.\nexpected: %+v\nreceived: %+v",
+			group, received)
+	}
+}
+
+// Tests that impl.Search returns the same messages with and without the
+// full-text search index.
+func testDialectSearch(t *testing.T, model *impl) {
+	partner := ed25519.PublicKey("partner")
+
+	texts := []string{"Hello world", "hello, there", "goodbye world",
+		"hello-world", "shell"}
+	msgIDs := make([]message.ID, len(texts))
+	timestamp := time.Now().Round(time.Second).UTC()
+	for j, text := range texts {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(text))
+		model.ReceiveText(msgIDs[j], "partner", text, partner, partner, 5, 0,
+			timestamp.Add(time.Duration(j)*time.Minute),
+			rounds.Round{ID: id.Round(j)}, dm.Received)
+	}
+
+	tests := []struct {
+		query    SearchQuery
+		expected []int
+	}{
+		{SearchQuery{Text: "hello"}, []int{3, 1, 0}},
+		{SearchQuery{Text: "HELLO world"}, []int{3, 0}},
+		{SearchQuery{Text: "hel*"}, []int{3, 1, 0}},
+		{SearchQuery{Text: "hello-world"}, []int{3, 0}},
+		{SearchQuery{Text: "ell"}, nil},
+		{SearchQuery{Text: "world", Limit: 1}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		msgs, err := model.Search(tt.query)
+		if err != nil {
+			t.Fatalf("Failed to search for %q: %+v", tt.query.Text, err)
+		}
+		var received []int
+		for _, msg := range msgs {
+			for j := range msgIDs {
+				if msg.MessageID == msgIDs[j] {
+					received = append(received, j)
+				}
+			}
+		}
+		if !reflect.DeepEqual(received, tt.expected) {
+			t.Errorf("Unexpected results for %q (full-text search: %t)."+
+				"\nexpected: %v\nreceived: %v", tt.query.Text,
+				model.fullTextSearch, tt.expected, received)
+		}
+	}
+}
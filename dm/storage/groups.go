@@ -0,0 +1,138 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateGroup creates or updates the group DM. The group is stored as a
+// Conversation with the group ID as its public key and the group name as its
+// nickname. Its members and admins are replaced with the given ones. A
+// Conversation with a partner is never replaced by a group.
+func (i *impl) UpdateGroup(group dm.ModelGroup) {
+	parentErr := "[DM SQL] failed to UpdateGroup: %+v"
+	jww.TRACE.Printf("[DM SQL] UpdateGroup(%X, %q, %d members, %d admins)",
+		group.GroupID, group.Name, len(group.Members), len(group.Admins))
+
+	convo := &Conversation{
+		Pubkey:   group.GroupID,
+		Nickname: group.Name,
+		IsGroup:  true,
+	}
+	members := make([]GroupMember, len(group.Members))
+	for j, m := range group.Members {
+		members[j] = GroupMember{
+			GroupId: group.GroupID,
+			PubKey:  m.PubKey,
+			Token:   m.Token,
+		}
+	}
+	admins := make([]GroupAdmin, len(group.Admins))
+	for j, admin := range group.Admins {
+		admins[j] = GroupAdmin{
+			GroupId: group.GroupID,
+			PubKey:  admin,
+		}
+	}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&Conversation{}).
+			Where("pubkey = ? AND is_group = ?", []byte(group.GroupID), false).
+			Count(&count).Error
+		if err != nil {
+			return err
+		} else if count > 0 {
+			return errors.Errorf(
+				"conversation %X is not a group", group.GroupID)
+		}
+
+		// Only the name of an existing group changes so that the codeset and
+		// blocked status are kept
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "pubkey"}},
+			DoUpdates: clause.AssignmentColumns([]string{"nickname"}),
+		}).Omit("Members", "Admins").Create(convo).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("group_id = ?", []byte(group.GroupID)).
+			Delete(&GroupMember{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("group_id = ?", []byte(group.GroupID)).
+			Delete(&GroupAdmin{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(members) > 0 {
+			if err = tx.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(admins) > 0 {
+			return tx.Create(&admins).Error
+		}
+		return nil
+	})
+	cancel()
+	if err != nil {
+		jww.ERROR.Printf(parentErr, err)
+	}
+}
+
+// GetGroup returns the group DM with the given ID. Returns nil if the group
+// does not exist.
+func (i *impl) GetGroup(groupID ed25519.PublicKey) *dm.ModelGroup {
+	parentErr := "[DM SQL] failed to GetGroup: %+v"
+
+	result := &Conversation{Pubkey: groupID}
+	ctx, cancel := newContext()
+	orderByID := func(tx *gorm.DB) *gorm.DB { return tx.Order("id") }
+	err := i.db.WithContext(ctx).Preload("Members", orderByID).
+		Preload("Admins", orderByID).
+		Where("is_group = ?", true).Take(result).Error
+	cancel()
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			jww.ERROR.Printf(parentErr, err)
+		}
+		return nil
+	}
+
+	group := &dm.ModelGroup{
+		GroupID: result.Pubkey,
+		Name:    result.Nickname,
+		Members: make([]dm.GroupMember, len(result.Members)),
+	}
+	for j, m := range result.Members {
+		group.Members[j] = dm.GroupMember{
+			PubKey: m.PubKey,
+			Token:  m.Token,
+		}
+	}
+	if len(result.Admins) > 0 {
+		group.Admins = make([]ed25519.PublicKey, len(result.Admins))
+		for j, admin := range result.Admins {
+			group.Admins[j] = admin.PubKey
+		}
+	}
+	return group
+}
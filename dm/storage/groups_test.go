@@ -0,0 +1,122 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in WASM.
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that a group stored with impl.UpdateGroup can be retrieved with
+// impl.GetGroup and that updating it replaces its name, members, and admins.
+func TestImpl_UpdateGroup_GetGroup(t *testing.T) {
+	m, err := newImpl("TestImpl_UpdateGroup_GetGroup", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groupID := ed25519.PublicKey("groupID")
+	if group := m.GetGroup(groupID); group != nil {
+		t.Errorf("Group returned before it was created: %+v", group)
+	}
+
+	expected := dm.ModelGroup{
+		GroupID: groupID,
+		Name:    "group",
+		Members: []dm.GroupMember{
+			{PubKey: ed25519.PublicKey("alice"), Token: 1},
+			{PubKey: ed25519.PublicKey("bob"), Token: 2},
+		},
+	}
+	m.UpdateGroup(expected)
+
+	group := m.GetGroup(groupID)
+	if group == nil || !reflect.DeepEqual(expected, *group) {
+		t.Errorf("Unexpected group.\nexpected: %+v\nreceived: %+v",
+			expected, group)
+	}
+
+	convo := m.GetConversation(groupID)
+	if convo == nil || !convo.IsGroup || convo.Nickname != expected.Name {
+		t.Errorf("Group not stored as a conversation: %+v", convo)
+	}
+
+	// Messages in the group must not change its name
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	m.ReceiveText(msgID, "alice", "text", groupID, expected.Members[0].PubKey,
+		expected.Members[0].Token, 0, time.Now(), rounds.Round{ID: 1},
+		dm.Received)
+
+	expected.Name = "new name"
+	expected.Members = []dm.GroupMember{
+		{PubKey: ed25519.PublicKey("bob"), Token: 2},
+		{PubKey: ed25519.PublicKey("carol"), Token: 3},
+	}
+	expected.Admins = []ed25519.PublicKey{ed25519.PublicKey("carol")}
+	m.UpdateGroup(expected)
+
+	group = m.GetGroup(groupID)
+	if group == nil || !reflect.DeepEqual(expected, *group) {
+		t.Errorf("Unexpected updated group.\nexpected: %+v\nreceived: %+v",
+			expected, group)
+	}
+}
+
+// Tests that impl.GetGroup returns nil for a conversation that is not a group.
+func TestImpl_GetGroup_NotGroup(t *testing.T) {
+	m, err := newImpl("TestImpl_GetGroup_NotGroup", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partner := ed25519.PublicKey("partner")
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	m.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		time.Now(), rounds.Round{ID: 1}, dm.Received)
+
+	if group := m.GetGroup(partner); group != nil {
+		t.Errorf("Group returned for DM conversation: %+v", group)
+	}
+}
+
+// Error path: Tests that impl.UpdateGroup does not replace a conversation that
+// is not a group.
+func TestImpl_UpdateGroup_NotGroup(t *testing.T) {
+	m, err := newImpl("TestImpl_UpdateGroup_NotGroup", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partner := ed25519.PublicKey("partner")
+	msgID := message.DeriveChannelMessageID(&id.ID{1}, 1, []byte("text"))
+	m.ReceiveText(msgID, "partner", "text", partner, partner, 5, 0,
+		time.Now(), rounds.Round{ID: 1}, dm.Received)
+
+	m.UpdateGroup(dm.ModelGroup{
+		GroupID: partner,
+		Name:    "group",
+		Members: []dm.GroupMember{{PubKey: ed25519.PublicKey("bob"), Token: 2}},
+	})
+
+	if group := m.GetGroup(partner); group != nil {
+		t.Errorf("Group returned for DM conversation: %+v", group)
+	}
+	convo := m.GetConversation(partner)
+	if convo == nil || convo.IsGroup || convo.Nickname != "partner" {
+		t.Errorf("DM conversation replaced by group: %+v", convo)
+	}
+}
@@ -28,6 +28,10 @@ const (
 
 	// Determines maximum runtime (in seconds) of DB queries.
 	dbTimeout = 3 * time.Second
+
+	// Name of the SQLite gorm.Dialector. Some features, such as the full-text
+	// search index, are only available on SQLite.
+	sqliteDialect = "sqlite"
 )
 
 // newContext builds a context for database operations.
@@ -197,6 +201,60 @@ func (i *impl) DeleteMessage(
 	return true
 }
 
+// Verify that impl adheres to the dm.MessageExpirer interface.
+var _ dm.MessageExpirer = (*impl)(nil)
+
+// DeleteMessagesBefore deletes every message in the conversation with the
+// partner that has a timestamp before the given time, except for messages that
+// have not been sent yet. Returns the number of messages deleted.
+func (i *impl) DeleteMessagesBefore(
+	partnerPubKey ed25519.PublicKey, before time.Time) int {
+	var deleted []*Message
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id", "message_id").Where("conversation_pub_key = ? "+
+			"AND timestamp < ? AND status != ?", []byte(partnerPubKey), before,
+			uint8(dm.Unsent)).Find(&deleted).Error
+		if err != nil || len(deleted) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(deleted))
+		for j := range deleted {
+			ids[j] = deleted[j].Id
+		}
+		return tx.Where("id IN ?", ids).Delete(&Message{}).Error
+	})
+	cancel()
+
+	if err != nil {
+		jww.ERROR.Printf("Failed to DeleteMessagesBefore: %+v", err)
+		return 0
+	}
+
+	for _, msg := range deleted {
+		var messageID message.ID
+		copy(messageID[:], msg.MessageId)
+		go i.cbs.MessageDeleted(messageID)
+	}
+
+	return len(deleted)
+}
+
+// UpdateMessageRequest marks whether the conversation with the partner is a
+// pending message request. Does nothing if the conversation does not exist.
+func (i *impl) UpdateMessageRequest(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Model(&Conversation{}).
+		Where("pubkey = ?", []byte(partnerPubKey)).
+		Update("is_request", pending).Error
+	cancel()
+	if err != nil {
+		jww.ERROR.Printf("Failed to UpdateMessageRequest: %+v", err)
+	}
+}
+
 func (i *impl) GetConversation(senderPubKey ed25519.PublicKey) *dm.ModelConversation {
 	parentErr := "Failed to GetConversation: %+v"
 	resultConvo, err := i.getConversation(senderPubKey)
@@ -211,6 +269,8 @@ func (i *impl) GetConversation(senderPubKey ed25519.PublicKey) *dm.ModelConversa
 		Token:            resultConvo.Token,
 		CodesetVersion:   resultConvo.CodesetVersion,
 		BlockedTimestamp: resultConvo.BlockedTimestamp,
+		IsGroup:          resultConvo.IsGroup,
+		IsRequest:        resultConvo.IsRequest,
 	}
 }
 
@@ -235,6 +295,8 @@ func (i *impl) GetConversations() []dm.ModelConversation {
 			Token:            resultConvo.Token,
 			CodesetVersion:   resultConvo.CodesetVersion,
 			BlockedTimestamp: resultConvo.BlockedTimestamp,
+			IsGroup:          resultConvo.IsGroup,
+			IsRequest:        resultConvo.IsRequest,
 		}
 	}
 	return conversations
@@ -270,8 +332,10 @@ func (i *impl) receiveWrapper(messageID message.ID, parentID *message.ID, nickna
 		jww.DEBUG.Printf(
 			"[DM SQL] Conversation with %s already joined", partnerKeyStr)
 
-		// Update Conversation if nickname was altered
-		isFromPartner := bytes.Equal(result.Pubkey, partnerKey)
+		// Update Conversation if nickname was altered. The nickname and
+		// dmToken of a group are set by UpdateGroup instead.
+		isFromPartner := bytes.Equal(result.Pubkey, partnerKey) &&
+			!result.IsGroup
 		nicknameChanged := result.Nickname != nickname
 		if isFromPartner && nicknameChanged {
 			jww.DEBUG.Printf("[DM SQL] Updating from nickname %s to %s",
@@ -310,6 +374,9 @@ func (i *impl) receiveWrapper(messageID message.ID, parentID *message.ID, nickna
 
 	msgToInsert := buildMessage(messageID.Bytes(), parentIdBytes, data,
 		partnerKey, senderKey, timestamp, round.ID, mType, codeset, status)
+	if !i.fullTextSearch {
+		msgToInsert.SearchText = searchText(data)
+	}
 
 	uuid, err := i.upsertMessage(msgToInsert)
 	if err != nil {
@@ -365,7 +432,7 @@ func (i *impl) upsertConversation(nickname string,
 	jww.DEBUG.Printf("[DM SQL] Attempting to upsertConversation: %+v", newConvo)
 
 	ctx, cancel := newContext()
-	err := i.db.WithContext(ctx).Save(newConvo).Error
+	err := i.db.WithContext(ctx).Save(&newConvo).Error
 	cancel()
 	if err != nil {
 		return errors.Errorf("[DM SQL] failed to upsertConversation: %+v", err)
@@ -20,6 +20,7 @@ import (
 	"gitlab.com/elixxir/crypto/message"
 	"gitlab.com/xx_network/primitives/id"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -117,6 +118,35 @@ func TestImpl_GetConversations(t *testing.T) {
 	}
 }
 
+// Tests that impl.UpdateMessageRequest marks and unmarks a conversation as a
+// message request and does not create conversations that do not exist.
+func TestImpl_UpdateMessageRequest(t *testing.T) {
+	m, err := newImpl("TestImpl_UpdateMessageRequest", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	partner := ed25519.PublicKey("partner")
+	m.UpdateMessageRequest(partner, true)
+	if convo := m.GetConversation(partner); convo != nil {
+		t.Fatalf("Created conversation that does not exist: %+v", convo)
+	}
+
+	err = m.upsertConversation("partner", partner, 5, 0, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, pending := range []bool{true, false} {
+		m.UpdateMessageRequest(partner, pending)
+		convo := m.GetConversation(partner)
+		if convo == nil || convo.IsRequest != pending {
+			t.Errorf("Unexpected conversation after marking request as %t: "+
+				"%+v", pending, convo)
+		}
+	}
+}
+
 // Test failed and successful deletes
 func TestWasmModel_DeleteMessage(t *testing.T) {
 	m, err := newImpl("TestWasmModel_DeleteMessage", &dummyCallbacks{}, true)
@@ -147,3 +177,43 @@ func TestWasmModel_DeleteMessage(t *testing.T) {
 	// Correct pub key, should have deleted
 	require.True(t, m.DeleteMessage(testMsgId, testBytes))
 }
+
+// Tests that impl.DeleteMessagesBefore only deletes sent messages in the
+// conversation that are older than the given time.
+func TestImpl_DeleteMessagesBefore(t *testing.T) {
+	m, err := newImpl("TestImpl_DeleteMessagesBefore", &dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	partner := ed25519.PublicKey("partner")
+	other := ed25519.PublicKey("other")
+	now := time.Now()
+
+	received := []struct {
+		partner ed25519.PublicKey
+		ts      time.Time
+		status  dm.Status
+		deleted bool
+	}{
+		{partner, now.Add(-3 * time.Hour), dm.Received, true},
+		{partner, now.Add(-3 * time.Hour), dm.Unsent, false},
+		{other, now.Add(-3 * time.Hour), dm.Received, false},
+		{partner, now.Add(-2 * time.Hour), dm.Sent, true},
+		{partner, now, dm.Received, false},
+	}
+	msgIDs := make([]message.ID, len(received))
+	for j, r := range received {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(strconv.Itoa(j)))
+		uuid := m.Receive(msgIDs[j], "nickname", []byte(strconv.Itoa(j)),
+			r.partner, r.partner, 0, 0, r.ts, rounds.Round{ID: id.Round(j)},
+			dm.TextType, r.status)
+		require.Positive(t, uuid)
+	}
+
+	require.Equal(t, 2, m.DeleteMessagesBefore(partner, now.Add(-time.Hour)))
+	for j, r := range received {
+		// A message that was deleted can no longer be deleted
+		require.Equal(t, !r.deleted,
+			m.DeleteMessage(msgIDs[j], r.partner), "Message %d", j)
+	}
+}
@@ -49,6 +49,11 @@ type Callbacks interface {
 type impl struct {
 	db  *gorm.DB // Stored database connection
 	cbs Callbacks
+
+	// fullTextSearch is true if the database supports the full-text search
+	// index used by Search. Otherwise, messages are matched with LIKE on
+	// their search text.
+	fullTextSearch bool
 }
 
 // NewEventModel initializes the [dm.EventModel] interface with appropriate backend.
@@ -58,6 +63,21 @@ func NewEventModel(dbFilePath string, cbs Callbacks) (dm.EventModel, error) {
 	return dm.EventModel(model), err
 }
 
+// NewEventModelWithDialector initializes the [dm.EventModel] interface with the
+// database opened by the given [gorm.Dialector]. This allows any database
+// supported by gorm to be used, such as PostgreSQL or MySQL, by passing in its
+// driver (e.g., postgres.Open(dsn)).
+//
+// There is intentionally no constructor that takes a DSN. Opening a DSN
+// requires the driver of its database, and importing every driver here would
+// add all of them to every client build. Callers open the dialector with the
+// driver they use instead.
+func NewEventModelWithDialector(
+	dialector gorm.Dialector, cbs Callbacks) (dm.EventModel, error) {
+	model, err := newImplWithDialector(dialector, cbs)
+	return dm.EventModel(model), err
+}
+
 // If useTemporary is set to true, this will use an in-RAM database.
 func newImpl(dbFilePath string, cbs Callbacks, useTemporary bool) (*impl, error) {
 
@@ -67,23 +87,32 @@ func newImpl(dbFilePath string, cbs Callbacks, useTemporary bool) (*impl, error)
 			"Using temporary in-memory database")
 	}
 
-	// Create the database connection
 	jww.INFO.Printf("Opening DB file at %s...", dbFilePath)
-	db, err := gorm.Open(sqlite.Open(dbFilePath), &gorm.Config{
+	return newImplWithDialector(sqlite.Open(dbFilePath), cbs)
+}
+
+func newImplWithDialector(dialector gorm.Dialector, cbs Callbacks) (*impl, error) {
+
+	// Create the database connection
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: logger.New(jww.TRACE, logger.Config{LogLevel: logger.Info}),
 	})
 	if err != nil {
-		return nil, errors.Errorf("Unable to initialize database backend: %+v", err)
-	}
-
-	// Enable foreign keys because they are disabled in SQLite by default
-	if err = db.Exec("PRAGMA foreign_keys = ON", nil).Error; err != nil {
-		return nil, err
+		return nil, errors.Errorf("Unable to initialize %s database "+
+			"backend: %+v", dialector.Name(), err)
 	}
 
-	// Enable Write Ahead Logging to enable multiple DB connections
-	if err = db.Exec("PRAGMA journal_mode = WAL;", nil).Error; err != nil {
-		return nil, err
+	isSqlite := db.Dialector.Name() == sqliteDialect
+	if isSqlite {
+		// Enable foreign keys because they are disabled in SQLite by default
+		if err = db.Exec("PRAGMA foreign_keys = ON", nil).Error; err != nil {
+			return nil, err
+		}
+
+		// Enable Write Ahead Logging to enable multiple DB connections
+		if err = db.Exec("PRAGMA journal_mode = WAL;", nil).Error; err != nil {
+			return nil, err
+		}
 	}
 
 	// Get and configure the internal database ConnPool
@@ -105,15 +134,27 @@ func newImpl(dbFilePath string, cbs Callbacks, useTemporary bool) (*impl, error)
 
 	// Initialize the database schema
 	// WARNING: Order is important. Do not change without database testing
-	err = db.AutoMigrate(&Conversation{}, &Message{})
+	err = db.AutoMigrate(
+		&Conversation{}, &Message{}, &GroupMember{}, &GroupAdmin{})
 	if err != nil {
 		return nil, err
 	}
 
+	// Initialize the full-text search index of messages. The index relies on
+	// SQLite FTS, so other databases fall back to matching the search text of
+	// messages with LIKE.
+	if isSqlite {
+		if err = initSearch(db); err != nil {
+			return nil, errors.Errorf(
+				"Unable to initialize message search index: %+v", err)
+		}
+	}
+
 	// Build the interface
 	di := &impl{
-		db:  db,
-		cbs: cbs,
+		db:             db,
+		cbs:            cbs,
+		fullTextSearch: isSqlite,
 	}
 
 	jww.INFO.Println("Database backend initialized successfully!")
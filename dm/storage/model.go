@@ -10,22 +10,33 @@ import (
 	"time"
 )
 
+// Byte slice columns that are keys or indexed have a size so that they are
+// stored as VARBINARY instead of BLOB in MySQL, which cannot index a BLOB
+// without a prefix length. The size is ignored by SQLite and PostgreSQL.
+
 // Message defines the IndexedDb representation of a single Message.
 //
 // A Message belongs to one Conversation.
 // A Message may belong to one Message (Parent).
 type Message struct {
 	Id                 int64  `gorm:"primaryKey;autoIncrement:true"`
-	MessageId          []byte `gorm:"uniqueIndex;not null"`
-	ConversationPubKey []byte `gorm:"index;not null"`
+	MessageId          []byte `gorm:"size:64;uniqueIndex;not null"`
+	ConversationPubKey []byte `gorm:"size:64;index;not null"`
 	ParentMessageId    []byte
 	Timestamp          time.Time `gorm:"index;not null"`
-	SenderPubKey       []byte    `gorm:"index;not null"`
+	SenderPubKey       []byte    `gorm:"size:64;index;not null"`
 	CodesetVersion     uint8     `gorm:"not null"`
 	Status             uint8     `gorm:"not null"`
+	ReceiptStatus      uint8     `gorm:"not null;default:0"`
 	Text               []byte    `gorm:"not null"`
 	Type               uint16    `gorm:"not null"`
 	Round              int64     `gorm:"not null"`
+
+	// SearchText is the lowercase words of Text used by Search on databases
+	// without the full-text search index. It is empty otherwise. It is always
+	// written on insert, so it has no default; a literal default is rejected
+	// on MySQL TEXT columns and a size would limit the length of messages.
+	SearchText string
 }
 
 // TableName overrides the table name used by Message.
@@ -37,7 +48,7 @@ func (Message) TableName() string {
 // message exchange between two recipients.
 // A Conversation has many Message objects.
 type Conversation struct {
-	Pubkey         []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	Pubkey         []byte `gorm:"size:64;primaryKey;not null;autoIncrement:false"`
 	Nickname       string `gorm:"not null"`
 	Token          uint32 `gorm:"not null"`
 	CodesetVersion uint8  `gorm:"not null"`
@@ -46,11 +57,55 @@ type Conversation struct {
 	// not been blocked, this will be a zero value.
 	BlockedTimestamp *time.Time `gorm:""`
 
+	// IsGroup is true if the conversation is a group DM. The Pubkey of a group
+	// is its group ID and the Nickname is the group's name.
+	IsGroup bool `gorm:"not null;default:false"`
+
+	// IsRequest is true if the conversation is a pending message request.
+	IsRequest bool `gorm:"not null;default:false"`
+
 	// Have to spell out this relationship because irregular PK name
 	Messages []Message `gorm:"foreignKey:ConversationPubKey;references:Pubkey;constraint:OnDelete:CASCADE"`
+
+	// Members of the group DM. Empty if the conversation is not a group.
+	Members []GroupMember `gorm:"foreignKey:GroupId;references:Pubkey;constraint:OnDelete:CASCADE"`
+
+	// Admins of the group DM. Empty if the conversation is not a group.
+	Admins []GroupAdmin `gorm:"foreignKey:GroupId;references:Pubkey;constraint:OnDelete:CASCADE"`
 }
 
 // TableName overrides the table name used by Message.
 func (Conversation) TableName() string {
 	return "dm_conversations"
 }
+
+// GroupMember defines the IndexedDb representation of a single member of a
+// group DM.
+//
+// A GroupMember belongs to one Conversation.
+type GroupMember struct {
+	Id      int64  `gorm:"primaryKey;autoIncrement:true"`
+	GroupId []byte `gorm:"size:64;index;not null;uniqueIndex:idx_dm_group_members_group_id_pub_key"`
+	PubKey  []byte `gorm:"size:64;not null;uniqueIndex:idx_dm_group_members_group_id_pub_key"`
+	Token   uint32 `gorm:"not null"`
+}
+
+// TableName overrides the table name used by GroupMember.
+func (GroupMember) TableName() string {
+	return "dm_group_members"
+}
+
+// GroupAdmin defines the IndexedDb representation of a single admin of a group
+// DM.
+//
+// A GroupAdmin belongs to one Conversation.
+type GroupAdmin struct {
+	Id      int64  `gorm:"primaryKey;autoIncrement:true"`
+	GroupId []byte `gorm:"size:64;index;not null;uniqueIndex:idx_dm_group_admins_group_id_pub_key"`
+	PubKey  []byte `gorm:"size:64;not null;uniqueIndex:idx_dm_group_admins_group_id_pub_key"`
+}
+
+// TableName overrides the table name used by GroupAdmin.
+func (GroupAdmin) TableName() string {
+	return "dm_group_admins"
+}
@@ -0,0 +1,65 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gorm.io/gorm"
+)
+
+// UpdateReadStatus marks all messages in the conversation with the partner
+// that were sent by senderPubKey, up to and including the message upTo, as
+// read. If the message upTo does not exist in the conversation, then no update
+// is made.
+func (i *impl) UpdateReadStatus(partnerPubKey, senderPubKey ed25519.PublicKey,
+	upTo message.ID) {
+	parentErr := "[DM SQL] failed to UpdateReadStatus: %+v"
+	jww.TRACE.Printf("[DM SQL] UpdateReadStatus(%X, %X, %s)",
+		partnerPubKey, senderPubKey, upTo)
+
+	var updated []*Message
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Get the timestamp of the last read message
+		target := &Message{}
+		err := tx.Where("message_id = ? AND conversation_pub_key = ?",
+			upTo.Bytes(), []byte(partnerPubKey)).Take(target).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Select("id").Where("conversation_pub_key = ? AND "+
+			"sender_pub_key = ? AND timestamp <= ? AND receipt_status != ?",
+			[]byte(partnerPubKey), []byte(senderPubKey), target.Timestamp,
+			uint8(dm.Read)).Find(&updated).Error
+		if err != nil || len(updated) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(updated))
+		for j := range updated {
+			ids[j] = updated[j].Id
+		}
+		return tx.Model(&Message{}).Where("id IN ?", ids).
+			Update("receipt_status", uint8(dm.Read)).Error
+	})
+	cancel()
+	if err != nil {
+		jww.ERROR.Printf(parentErr, err)
+		return
+	}
+
+	jww.DEBUG.Printf("[DM SQL] Marked %d messages from %X as read",
+		len(updated), senderPubKey)
+	for _, msg := range updated {
+		go i.cbs.MessageReceived(uint64(msg.Id), partnerPubKey, true, false)
+	}
+}
@@ -0,0 +1,76 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in WASM.
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.UpdateReadStatus only marks messages from the given sender in
+// the given conversation, up to and including the target message, as read.
+func TestImpl_UpdateReadStatus(t *testing.T) {
+	m, err := newImpl("TestImpl_UpdateReadStatus", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	me := ed25519.PublicKey("me")
+	alice := ed25519.PublicKey("alice")
+	bob := ed25519.PublicKey("bob")
+	start := time.Now().Round(0).UTC()
+	messages := []struct {
+		partner, sender ed25519.PublicKey
+	}{
+		{alice, me},
+		{alice, alice},
+		{bob, me},
+		{alice, me},
+		{alice, me},
+	}
+	msgIDs := make([]message.ID, len(messages))
+	for j, msg := range messages {
+		text := "message " + strconv.Itoa(j)
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(text))
+		m.ReceiveText(msgIDs[j], "nickname", text, msg.partner, msg.sender,
+			0, 0, start.Add(time.Duration(j)*time.Minute),
+			rounds.Round{ID: id.Round(j)}, dm.Received)
+	}
+
+	// Alice reads the messages I sent her up to message 3
+	m.UpdateReadStatus(alice, me, msgIDs[3])
+
+	// Reading an unknown message makes no changes
+	m.UpdateReadStatus(alice, alice, message.ID{})
+
+	expected := []dm.ReceiptStatus{dm.Read, dm.NotRead, dm.NotRead, dm.Read,
+		dm.NotRead}
+	for j, msgID := range msgIDs {
+		msg := &Message{}
+		err = m.db.Where("message_id = ?", msgID.Bytes()).Take(msg).Error
+		if err != nil {
+			t.Fatalf("Failed to get message %d: %+v", j, err)
+		}
+
+		if dm.ReceiptStatus(msg.ReceiptStatus) != expected[j] {
+			t.Errorf("Unexpected receipt status for message %d."+
+				"\nexpected: %s\nreceived: %s",
+				j, expected[j], dm.ReceiptStatus(msg.ReceiptStatus))
+		}
+	}
+}
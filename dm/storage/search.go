@@ -0,0 +1,251 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gorm.io/gorm"
+)
+
+// Searcher is implemented by the [dm.EventModel] returned by NewEventModel. It
+// allows for full-text search of stored messages.
+type Searcher interface {
+	// Search returns all messages whose text matches the SearchQuery, ordered
+	// from newest to oldest.
+	Search(query SearchQuery) ([]dm.ModelMessage, error)
+}
+
+// SearchQuery describes a full-text search of stored messages. All fields
+// except Text are optional; zero values apply no filter.
+type SearchQuery struct {
+	// Text is the text to search for. Each word in the text must appear in a
+	// message for it to match. Matching is case-insensitive. A word ending in
+	// "*" matches any word starting with it (e.g., "hel*" matches "hello").
+	Text string `json:"text"`
+
+	// ConversationPubKey limits the results to messages in the conversation
+	// with the given partner.
+	ConversationPubKey ed25519.PublicKey `json:"conversation_pub_key,omitempty"`
+
+	// SenderPubKey limits the results to messages sent by the given user.
+	SenderPubKey ed25519.PublicKey `json:"sender_pub_key,omitempty"`
+
+	// Types limits the results to messages of the given types.
+	Types []dm.MessageType `json:"types,omitempty"`
+
+	// Start and End limit the results to messages with timestamps in the range
+	// [Start, End).
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Limit is the maximum number of messages to return. If it is zero, all
+	// matching messages are returned.
+	Limit int `json:"limit"`
+}
+
+// Verify that impl adheres to the Searcher interface.
+var _ Searcher = (*impl)(nil)
+
+// searchSchema is the SQL that creates the full-text search index and the
+// triggers that keep it in sync with the dm_messages table.
+//
+// FTS4 is used instead of FTS5 because FTS5 requires building SQLite with a
+// build tag, while FTS4 is always available.
+var searchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS dm_messages_fts
+	USING fts4(content="dm_messages", text, tokenize=unicode61)`,
+	`CREATE TRIGGER IF NOT EXISTS dm_messages_fts_bu
+	BEFORE UPDATE OF text ON dm_messages
+	BEGIN DELETE FROM dm_messages_fts WHERE docid=old.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS dm_messages_fts_bd
+	BEFORE DELETE ON dm_messages
+	BEGIN DELETE FROM dm_messages_fts WHERE docid=old.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS dm_messages_fts_au
+	AFTER UPDATE OF text ON dm_messages
+	BEGIN INSERT INTO dm_messages_fts(docid, text) VALUES (new.id, new.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS dm_messages_fts_ai
+	AFTER INSERT ON dm_messages
+	BEGIN INSERT INTO dm_messages_fts(docid, text) VALUES (new.id, new.text);
+	END`,
+}
+
+// initSearch creates the full-text search index, if it does not already exist,
+// and indexes all existing messages.
+func initSearch(db *gorm.DB) error {
+	exists := db.Migrator().HasTable("dm_messages_fts")
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range searchSchema {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+
+		// Index messages stored before the index was created
+		if !exists {
+			return tx.Exec("INSERT INTO dm_messages_fts(dm_messages_fts) " +
+				"VALUES('rebuild')").Error
+		}
+		return nil
+	})
+}
+
+// Search returns all messages whose text matches the SearchQuery, ordered from
+// newest to oldest.
+func (i *impl) Search(query SearchQuery) ([]dm.ModelMessage, error) {
+	parentErr := "failed to Search"
+
+	match := buildMatchQuery(query.Text)
+	if match == "" {
+		return nil, errors.Errorf("%s: search text is empty", parentErr)
+	}
+
+	ctx, cancel := newContext()
+	tx := i.db.WithContext(ctx)
+	if i.fullTextSearch {
+		tx = tx.Where("id IN (SELECT docid FROM "+
+			"dm_messages_fts WHERE dm_messages_fts MATCH ?)", match)
+	} else {
+		for _, pattern := range buildLikePatterns(query.Text) {
+			tx = tx.Where("search_text LIKE ?", pattern)
+		}
+	}
+	if query.ConversationPubKey != nil {
+		tx = tx.Where(
+			"conversation_pub_key = ?", []byte(query.ConversationPubKey))
+	}
+	if query.SenderPubKey != nil {
+		tx = tx.Where("sender_pub_key = ?", []byte(query.SenderPubKey))
+	}
+	if len(query.Types) > 0 {
+		tx = tx.Where("type IN ?", query.Types)
+	}
+	if !query.Start.IsZero() {
+		tx = tx.Where("timestamp >= ?", query.Start)
+	}
+	if !query.End.IsZero() {
+		tx = tx.Where("timestamp < ?", query.End)
+	}
+	tx = tx.Order("timestamp desc").Order("id desc")
+
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var results []*Message
+	err := tx.Find(&results).Error
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	msgs := make([]dm.ModelMessage, len(results))
+	for j, result := range results {
+		msgs[j] = convertMessage(result)
+	}
+
+	return msgs, nil
+}
+
+// convertMessage is a private helper that converts a Message from storage into
+// a [dm.ModelMessage].
+func convertMessage(msg *Message) dm.ModelMessage {
+	var messageID, parentMessageID message.ID
+	copy(messageID[:], msg.MessageId)
+	copy(parentMessageID[:], msg.ParentMessageId)
+
+	return dm.ModelMessage{
+		UUID:               uint64(msg.Id),
+		MessageID:          messageID,
+		ConversationPubKey: msg.ConversationPubKey,
+		ParentMessageID:    parentMessageID,
+		Timestamp:          msg.Timestamp,
+		SenderPubKey:       msg.SenderPubKey,
+		CodesetVersion:     msg.CodesetVersion,
+		Status:             dm.Status(msg.Status),
+		ReceiptStatus:      dm.ReceiptStatus(msg.ReceiptStatus),
+		Content:            msg.Text,
+		Type:               dm.MessageType(msg.Type),
+		Round:              id.Round(msg.Round),
+	}
+}
+
+// buildMatchQuery converts search text into an FTS MATCH expression that
+// requires every word in the text. Each word is quoted so that characters in
+// the text are never interpreted as query syntax, except for a trailing "*",
+// which is kept to allow prefix searches.
+func buildMatchQuery(text string) string {
+	words := strings.Fields(text)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+
+		word = strings.ReplaceAll(word, `"`, `""`)
+		if prefix {
+			word += "*"
+		}
+		terms = append(terms, `"`+word+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// buildLikePatterns converts search text into LIKE patterns for the
+// search_text column that require every word in the text. Each pattern matches
+// the words of one search word as whole words, except for a trailing "*", which
+// allows the last word to be a prefix. The patterns match the same messages as
+// the expression built by buildMatchQuery. Words only contain letters and
+// numbers, so they never contain LIKE wildcards.
+func buildLikePatterns(text string) []string {
+	var patterns []string
+	for _, word := range strings.Fields(text) {
+		phrase := tokenize(word)
+		if len(phrase) == 0 {
+			continue
+		}
+
+		pattern := "% " + strings.Join(phrase, " ")
+		if strings.HasSuffix(word, "*") {
+			pattern += "%"
+		} else {
+			pattern += " %"
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// searchText returns the value of the search_text column for a message with
+// the text. It contains the lowercase words of the text separated and
+// surrounded by single spaces so that the patterns built by buildLikePatterns
+// only match whole words.
+func searchText(text string) string {
+	words := tokenize(text)
+	if len(words) == 0 {
+		return ""
+	}
+	return " " + strings.Join(words, " ") + " "
+}
+
+// tokenize splits the text into lowercase words the same way the unicode61
+// tokenizer of the full-text search index does.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,111 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in WASM.
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.Search returns the expected messages for each filter and
+// that the index is kept in sync when messages are deleted.
+func TestImpl_Search(t *testing.T) {
+	m, err := newImpl("TestImpl_Search", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	me := ed25519.PublicKey("me")
+	alice := ed25519.PublicKey("alice")
+	bob := ed25519.PublicKey("bob")
+	start := time.Now().Round(0).UTC()
+	messages := []struct {
+		partner, sender ed25519.PublicKey
+		text            string
+		mType           dm.MessageType
+	}{
+		{alice, alice, "Hello world", dm.TextType},
+		{alice, me, "hello there, Alice", dm.TextType},
+		{bob, bob, "HELLO from another conversation", dm.TextType},
+		{alice, alice, "hello", dm.ReplyType},
+		{alice, me, "goodbye world", dm.TextType},
+	}
+	msgIDs := make([]message.ID, len(messages))
+	for j, msg := range messages {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			&id.ID{1}, uint64(j), []byte(msg.text))
+		m.Receive(msgIDs[j], "nickname", []byte(msg.text), msg.partner,
+			msg.sender, 0, 0, start.Add(time.Duration(j)*time.Minute),
+			rounds.Round{ID: id.Round(j)}, msg.mType, dm.Received)
+	}
+
+	tests := []struct {
+		name     string
+		query    SearchQuery
+		expected []int
+	}{
+		{"Text", SearchQuery{Text: "hello"}, []int{3, 2, 1, 0}},
+		{"AllWords", SearchQuery{Text: "hello world"}, []int{0}},
+		{"Prefix", SearchQuery{Text: "hel*"}, []int{3, 2, 1, 0}},
+		{"Syntax", SearchQuery{Text: `"world" OR`}, nil},
+		{"Conversation", SearchQuery{
+			Text: "hello", ConversationPubKey: bob}, []int{2}},
+		{"Sender", SearchQuery{Text: "hello", SenderPubKey: me}, []int{1}},
+		{"Type", SearchQuery{
+			Text: "hello", Types: []dm.MessageType{dm.ReplyType}}, []int{3}},
+		{"DateRange", SearchQuery{Text: "hello",
+			Start: start.Add(time.Minute), End: start.Add(3 * time.Minute)},
+			[]int{2, 1}},
+		{"Limit", SearchQuery{Text: "hello", Limit: 2}, []int{3, 2}},
+	}
+
+	check := func(t *testing.T, query SearchQuery, expected []int) {
+		msgs, err2 := m.Search(query)
+		if err2 != nil {
+			t.Fatalf("Failed to search: %+v", err2)
+		}
+		var received []int
+		for _, msg := range msgs {
+			for j := range msgIDs {
+				if msg.MessageID == msgIDs[j] {
+					received = append(received, j)
+				}
+			}
+		}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("Unexpected search results for %q."+
+				"\nexpected: %v\nreceived: %v", query.Text, expected, received)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, tt.query, tt.expected)
+		})
+	}
+
+	// Delete a message and ensure it is not found
+	if !m.DeleteMessage(msgIDs[0], alice) {
+		t.Fatal("Failed to delete message.")
+	}
+	check(t, SearchQuery{Text: "world"}, []int{4})
+
+	// Searching without text is an error
+	if _, err = m.Search(SearchQuery{Text: "  "}); err == nil {
+		t.Error("Did not get an error when searching for empty text.")
+	}
+}
@@ -67,12 +67,14 @@ func (dmu *dmPartner) String() string {
 }
 
 // partnerStatus represents the notification status or blocked status of the DM
-// partner.
+// partner. A partner who contacted the user for the first time has
+// statusRequest until their message request is accepted, declined, or blocked.
 type partnerStatus uint32
 
 const (
 	statusMute      partnerStatus = 10
 	statusNotifyAll partnerStatus = 20
+	statusRequest   partnerStatus = 30
 	statusBlocked   partnerStatus = 50
 
 	// defaultStatus is set when adding a new partner or resetting a status.
@@ -128,8 +130,9 @@ func (ps *partnerStore) get(
 }
 
 // getOrSet returns the dmPartner from storage. If the partner does not exist,
-// then it is added with the default status and returned.
-func (ps *partnerStore) getOrSet(pubKey ed25519.PublicKey) *dmPartner {
+// then it is added with the given status and returned.
+func (ps *partnerStore) getOrSet(
+	pubKey ed25519.PublicKey, status partnerStatus) *dmPartner {
 	elemName := marshalElementName(pubKey)
 	obj, err := ps.remote.GetMapElement(dmMapName, elemName, dmMapVersion)
 	if err != nil {
@@ -138,10 +141,10 @@ func (ps *partnerStore) getOrSet(pubKey ed25519.PublicKey) *dmPartner {
 				pubKey, err)
 		}
 
-		ps.set(pubKey, defaultStatus)
+		ps.set(pubKey, status)
 		return &dmPartner{
 			PublicKey: pubKey,
-			Status:    defaultStatus,
+			Status:    status,
 		}
 	}
 
@@ -301,6 +304,8 @@ func (ps partnerStatus) String() string {
 		return "all"
 	case statusBlocked:
 		return "blocked"
+	case statusRequest:
+		return "request"
 	default:
 		return "INVALID STATUS: " + strconv.Itoa(int(ps))
 	}
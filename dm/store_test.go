@@ -84,7 +84,7 @@ func Test_partnerStore_getOrSet(t *testing.T) {
 		if deleted {
 			ps.delete(exp.PublicKey)
 		}
-		partner := ps.getOrSet(exp.PublicKey)
+		partner := ps.getOrSet(exp.PublicKey, defaultStatus)
 		if deleted {
 			exp.Status = defaultStatus
 		}
@@ -317,6 +317,7 @@ func Test_partnerStatus_String(t *testing.T) {
 		statusMute:      "mute",
 		statusNotifyAll: "all",
 		statusBlocked:   "blocked",
+		statusRequest:   "request",
 		32:              "INVALID STATUS: 32",
 	}
 
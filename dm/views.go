@@ -7,7 +7,13 @@
 
 package dm
 
-import "time"
+import (
+	"crypto/ed25519"
+	"time"
+
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
 
 type ModelConversation struct {
 	Pubkey         []byte `json:"pub_key"`
@@ -15,6 +21,52 @@ type ModelConversation struct {
 	Token          uint32 `json:"token"`
 	CodesetVersion uint8  `json:"codeset_version"`
 
+	// IsGroup is true if the conversation is a group DM. The Pubkey of a group
+	// conversation is the group ID and its Nickname is the group name.
+	IsGroup bool `json:"is_group"`
+
+	// IsRequest is true if the conversation is a pending message request from
+	// a partner the user has not accepted yet.
+	IsRequest bool `json:"is_request"`
+
 	// Deprecated: KV is the source of truth for blocked users.
 	BlockedTimestamp *time.Time `json:"blocked_timestamp"`
 }
+
+// ModelGroup contains the name and membership of a group DM. A group DM is
+// presented as a single conversation whose public key is the GroupID.
+type ModelGroup struct {
+	GroupID ed25519.PublicKey `json:"group_id"`
+	Name    string            `json:"name"`
+
+	// Members lists all members of the group except the user.
+	Members []GroupMember `json:"members"`
+
+	// Admins lists the public keys of the members, possibly including the
+	// user, that can change the name, members, and admins of the group. The
+	// creator of the group, whose public key is part of the GroupID, is always
+	// an admin and is not included.
+	Admins []ed25519.PublicKey `json:"admins"`
+}
+
+// GroupMember is a single member of a group DM.
+type GroupMember struct {
+	PubKey ed25519.PublicKey `json:"pub_key"`
+	Token  uint32            `json:"token"`
+}
+
+// ModelMessage contains a direct message and all of its information.
+type ModelMessage struct {
+	UUID               uint64            `json:"uuid"`
+	MessageID          message.ID        `json:"message_id"`
+	ConversationPubKey ed25519.PublicKey `json:"conversation_pub_key"`
+	ParentMessageID    message.ID        `json:"parent_message_id"`
+	Timestamp          time.Time         `json:"timestamp"`
+	SenderPubKey       ed25519.PublicKey `json:"sender_pub_key"`
+	CodesetVersion     uint8             `json:"codeset_version"`
+	Status             Status            `json:"status"`
+	ReceiptStatus      ReceiptStatus     `json:"receipt_status"`
+	Content            []byte            `json:"content"`
+	Type               MessageType       `json:"type"`
+	Round              id.Round          `json:"round"`
+}
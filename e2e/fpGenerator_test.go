@@ -137,6 +137,16 @@ func (m *mockFpgCmix) SendMany(messages []cmix.TargetedCmixMessage, params cmix.
 func (m *mockFpgCmix) SendManyWithAssembler(recipients []*id.ID, assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	return rounds.Round{}, nil, nil
 }
+func (m *mockFpgCmix) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	return 0, nil
+}
+func (m *mockFpgCmix) GetQueuedSends() []cmix.QueuedSend {
+	return nil
+}
+func (m *mockFpgCmix) CancelQueuedSend(uint64) error {
+	return nil
+}
+func (m *mockFpgCmix) SetOutboxCallback(cmix.OutboxCallback)                  {}
 func (m *mockFpgCmix) AddIdentity(*id.ID, time.Time, bool, message.Processor) {}
 func (m *mockFpgCmix) AddIdentityWithHistory(id *id.ID, validUntil,
 	beginning time.Time, persistent bool, _ message.Processor) {
@@ -266,6 +266,16 @@ func (m *mockNetManager) SendMany(messages []cmix.TargetedCmixMessage, params cm
 func (m *mockNetManager) SendManyWithAssembler(recipients []*id.ID, assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	return rounds.Round{}, nil, nil
 }
+func (m *mockNetManager) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	return 0, nil
+}
+func (m *mockNetManager) GetQueuedSends() []cmix.QueuedSend {
+	return nil
+}
+func (m *mockNetManager) CancelQueuedSend(uint64) error {
+	return nil
+}
+func (m *mockNetManager) SetOutboxCallback(cmix.OutboxCallback) {}
 
 func (m *mockNetManager) AddIdentity(id *id.ID, validUntil time.Time, persistent bool, _ message.Processor) {
 }
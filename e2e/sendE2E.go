@@ -51,8 +51,12 @@ func (m *manager) SendE2E(mt catalog.MessageType, recipient *id.ID,
 	if handleCritical {
 		m.crit.AddProcessing(mt, recipient, payload, params)
 		// Set critical to false so that the network layer does not make the
-		// messages critical as well
+		// messages critical as well. The encrypted messages cannot be resent
+		// from the cMix outbox because they cannot be decrypted once the
+		// session rekeys, so they are re-encrypted on every resend instead.
+		// They keep the critical priority.
 		params.Critical = false
+		params.Priority = cmix.Critical
 	}
 
 	sendReport, err := m.sendE2E(mt, recipient, payload, params)
@@ -231,6 +231,16 @@ func (m *mockCmix) SendMany(messages []cmix.TargetedCmixMessage, params cmix.CMI
 func (m *mockCmix) SendManyWithAssembler(recipients []*id.ID, assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	return rounds.Round{}, nil, nil
 }
+func (m *mockCmix) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	return 0, nil
+}
+func (m *mockCmix) GetQueuedSends() []cmix.QueuedSend {
+	return nil
+}
+func (m *mockCmix) CancelQueuedSend(uint64) error {
+	return nil
+}
+func (m *mockCmix) SetOutboxCallback(cmix.OutboxCallback)                  {}
 func (m *mockCmix) AddIdentity(*id.ID, time.Time, bool, message.Processor) {}
 func (m *mockCmix) AddIdentityWithHistory(id *id.ID, validUntil, beginning time.Time, persistent bool, _ message.Processor) {
 }
@@ -0,0 +1,173 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package e2e
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/simulated"
+	ft "gitlab.com/elixxir/client/v4/fileTransfer"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/client/v4/xxdk"
+	"gitlab.com/elixxir/client/v4/xxdk/xxdktest"
+	ftCrypto "gitlab.com/elixxir/crypto/fileTransfer"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that a file sent over E2E between two clients on the simulated network
+// is received in full.
+func TestWrapper_SimulatedNetwork(t *testing.T) {
+	net, err := simulated.NewNetwork(simulated.DefaultParams())
+	if err != nil {
+		t.Fatalf("Failed to create simulated network: %+v", err)
+	}
+
+	sender, _ := newSimulatedWrapper(t, net, 1)
+	receiver, received := newSimulatedWrapper(t, net, 2)
+	if _, err = sender.user.MakePrecannedAuthenticatedChannel(2); err != nil {
+		t.Fatalf("Failed to make channel to receiver: %+v", err)
+	}
+	if _, err = receiver.user.MakePrecannedAuthenticatedChannel(1); err != nil {
+		t.Fatalf("Failed to make channel to sender: %+v", err)
+	}
+
+	fileName, fileType := "myFile", "txt"
+	fileData := []byte(loremIpsum)
+	preview := []byte("Lorem ipsum dolor sit amet")
+	sent := make(chan error, 1)
+	sentCb := func(completed bool, _, _ uint16, _ ft.SentTransfer,
+		_ ft.FilePartTracker, err error) {
+		if completed || err != nil {
+			select {
+			case sent <- err:
+			default:
+			}
+		}
+	}
+	_, err = sender.w.Send(receiver.user.GetReceptionIdentity().ID, fileName,
+		fileType, fileData, 2.0, preview, sentCb, 0)
+	if err != nil {
+		t.Fatalf("Failed to send file: %+v", err)
+	}
+
+	var tid *ftCrypto.TransferID
+	select {
+	case r := <-received:
+		tid = r.tid
+		if r.fileName != fileName || r.fileType != fileType {
+			t.Errorf("Received wrong file name and type."+
+				"\nexpected: %q, %q\nreceived: %q, %q",
+				fileName, fileType, r.fileName, r.fileType)
+		}
+		if !r.sender.Cmp(sender.user.GetReceptionIdentity().ID) {
+			t.Errorf("Received file from wrong sender."+
+				"\nexpected: %s\nreceived: %s",
+				sender.user.GetReceptionIdentity().ID, r.sender)
+		}
+		if !bytes.Equal(preview, r.preview) {
+			t.Errorf("Received wrong preview.\nexpected: %q\nreceived: %q",
+				preview, r.preview)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting to receive new file transfer.")
+	}
+
+	done := make(chan error, 1)
+	receivedCb := func(completed bool, _, _ uint16, _ ft.ReceivedTransfer,
+		_ ft.FilePartTracker, err error) {
+		if completed || err != nil {
+			select {
+			case done <- err:
+			default:
+			}
+		}
+	}
+	err = receiver.w.RegisterReceivedProgressCallback(tid, receivedCb, 0)
+	if err != nil {
+		t.Fatalf("Failed to register received progress callback: %+v", err)
+	}
+
+	for _, c := range []chan error{sent, done} {
+		select {
+		case err = <-c:
+			if err != nil {
+				t.Fatalf("File transfer failed: %+v", err)
+			}
+		case <-time.After(30 * time.Second):
+			t.Fatal("Timed out waiting for file transfer to complete.")
+		}
+	}
+
+	receivedFile, err := receiver.w.Receive(tid)
+	if err != nil {
+		t.Fatalf("Failed to receive file: %+v", err)
+	}
+	if !bytes.Equal(fileData, receivedFile) {
+		t.Errorf("Received file does not match sent."+
+			"\nsent:     %q\nreceived: %q", fileData, receivedFile)
+	}
+}
+
+// simulatedWrapper is a file transfer Wrapper on a precanned client on the
+// simulated network.
+type simulatedWrapper struct {
+	user *xxdk.E2e
+	w    *Wrapper
+}
+
+// simulatedReceived contains the values passed to the ft.ReceiveCallback of a
+// simulatedWrapper.
+type simulatedReceived struct {
+	tid      *ftCrypto.TransferID
+	fileName string
+	fileType string
+	sender   *id.ID
+	preview  []byte
+}
+
+// newSimulatedWrapper creates a file transfer Wrapper on a new precanned client
+// on the simulated network. Returns the wrapper and a channel that receives
+// every new file transfer. Its processes are stopped when the test ends.
+func newSimulatedWrapper(t *testing.T, net *simulated.Network,
+	precannedID uint) (simulatedWrapper, chan simulatedReceived) {
+	user := xxdktest.NewSimulatedE2e(t, net, precannedID)
+
+	ftParams := ft.DefaultParams()
+	ftParams.MaxThroughput = math.MaxInt
+	ftManager, err := ft.NewManager(ftParams, user)
+	if err != nil {
+		t.Fatalf("Failed to make new file transfer manager: %+v", err)
+	}
+	stop, err := ftManager.StartProcesses()
+	if err != nil {
+		t.Fatalf("Failed to start file transfer processes: %+v", err)
+	}
+	t.Cleanup(func() {
+		if err = stop.Close(); err != nil {
+			t.Errorf("Failed to close file transfer processes: %+v", err)
+		}
+		if err = stoppable.WaitForStopped(stop, 5*time.Second); err != nil {
+			t.Errorf("Failed to stop file transfer processes: %+v", err)
+		}
+	})
+
+	received := make(chan simulatedReceived, 10)
+	receiveCB := func(tid *ftCrypto.TransferID, fileName, fileType string,
+		sender *id.ID, _ uint32, preview []byte) {
+		received <- simulatedReceived{tid, fileName, fileType, sender, preview}
+	}
+	w, err := NewWrapper(receiveCB, DefaultParams(), ftManager, user)
+	if err != nil {
+		t.Fatalf("Failed to create file transfer wrapper: %+v", err)
+	}
+
+	return simulatedWrapper{user, w}, received
+}
@@ -154,6 +154,18 @@ func (m *mockCmix) SendManyWithAssembler(recipients []*id.ID, assembler cmix.Man
 	//TODO implement me
 	panic("implement me")
 }
+func (m *mockCmix) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockCmix) GetQueuedSends() []cmix.QueuedSend {
+	panic("implement me")
+}
+func (m *mockCmix) CancelQueuedSend(uint64) error {
+	panic("implement me")
+}
+func (m *mockCmix) SetOutboxCallback(cmix.OutboxCallback) {
+	panic("implement me")
+}
 
 func (m *mockCmix) SendWithAssembler(*id.ID, cmix.MessageAssembler,
 	cmix.CMIXParams) (rounds.Round, ephemeral.Id, error) {
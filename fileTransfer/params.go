@@ -35,10 +35,15 @@ type Params struct {
 
 // DefaultParams returns a Params object filled with the default values.
 func DefaultParams() Params {
+	// File parts are sent with bulk priority so that they do not starve
+	// interactive sends
+	cmixParams := cmix.GetDefaultCMIXParams()
+	cmixParams.Priority = cmix.Bulk
+
 	return Params{
 		MaxThroughput: defaultMaxThroughput,
 		SendTimeout:   defaultSendTimeout,
-		Cmix:          cmix.GetDefaultCMIXParams(),
+		Cmix:          cmixParams,
 	}
 }
 
@@ -21,6 +21,7 @@ func TestDefaultParams(t *testing.T) {
 		SendTimeout:   defaultSendTimeout,
 		Cmix:          cmix.GetDefaultCMIXParams(),
 	}
+	expected.Cmix.Priority = cmix.Bulk
 	received := DefaultParams()
 	received.Cmix.Stop = expected.Cmix.Stop
 
@@ -45,6 +46,7 @@ func TestGetParameters(t *testing.T) {
 			Stop:             nil,
 			BlacklistedNodes: cmix.NodeMap{},
 			Critical:         true,
+			Priority:         cmix.Bulk,
 		},
 	}
 	expectedData, err := json.Marshal(expected)
@@ -83,6 +83,16 @@ func (tnm *testNetworkManager) SendMany(messages []cmix.TargetedCmixMessage, par
 func (tnm *testNetworkManager) SendManyWithAssembler(recipients []*id.ID, assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	return rounds.Round{}, nil, nil
 }
+func (tnm *testNetworkManager) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	return 0, nil
+}
+func (tnm *testNetworkManager) GetQueuedSends() []cmix.QueuedSend {
+	return nil
+}
+func (tnm *testNetworkManager) CancelQueuedSend(uint64) error {
+	return nil
+}
+func (tnm *testNetworkManager) SetOutboxCallback(cmix.OutboxCallback)             {}
 func (*testNetworkManager) AddService(*id.ID, message.Service, message.Processor) {}
 func (*testNetworkManager) IncreaseParallelNodeRegistration(int) func() (stoppable.Stoppable, error) {
 	return nil
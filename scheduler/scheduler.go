@@ -0,0 +1,412 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package scheduler queues messages to be sent at a future time. It keeps the
+// queue in storage and sends each message once its time is reached. It is
+// shared by channels and direct messages, which each store the details needed
+// to send their own messages.
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Error messages.
+var (
+	// NotFoundErr is returned when canceling or rescheduling a send that does
+	// not exist or was already sent.
+	NotFoundErr = errors.New("scheduled send not found")
+
+	// InvalidTimeErr is returned when scheduling a send at a time that is not
+	// in the future.
+	InvalidTimeErr = errors.New("scheduled time must be in the future")
+)
+
+// Send is a message queued to be sent at a future time.
+type Send struct {
+	// ID uniquely identifies the send. It is used to cancel or reschedule the
+	// send.
+	ID uint64 `json:"id"`
+
+	// Conversation identifies the conversation the message is sent to, such
+	// as a channel ID or the public key of a DM partner.
+	Conversation []byte `json:"conversation"`
+
+	// Details contains everything else needed to send the message. It is
+	// encoded by the user of the Scheduler.
+	Details []byte `json:"details"`
+
+	// SendAt is the time the message is sent at. If the network is not
+	// healthy at that time, the message is sent once it becomes healthy.
+	SendAt time.Time `json:"sendAt"`
+}
+
+// SendFunc sends a message once its time is reached. It is responsible for
+// reporting the result of the send.
+type SendFunc func(s Send)
+
+// HealthTracker registers a callback that is called with the health of the
+// network every time it changes.
+type HealthTracker interface {
+	AddHealthCallback(f func(bool)) uint64
+}
+
+// Scheduler keeps the list of scheduled sends and sends each one once its time
+// is reached. Scheduled sends are saved to storage so that they are sent after
+// a restart. Sends whose time passed while the client was stopped are sent as
+// soon as the network is healthy.
+//
+// Sends are only made while the processes started by
+// Scheduler.StartProcesses are running. Each send is removed from storage
+// before it is sent, so a send interrupted by the client stopping is not sent
+// again.
+type Scheduler struct {
+	// sends is a map of scheduled sends keyed on their ID.
+	sends map[uint64]*Send
+
+	// nextID is the ID given to the next scheduled send.
+	nextID uint64
+
+	// send sends the message through the normal send path.
+	send SendFunc
+
+	// healthy is true while the network is healthy. Sends are held until the
+	// network becomes healthy.
+	healthy bool
+
+	// running is true while the processes are running. Sends are held until
+	// they are started.
+	running bool
+
+	// timer fires when the earliest scheduled send is due. It is only set
+	// while the processes are running.
+	timer *time.Timer
+
+	// sending tracks sends in progress so that stopping waits for them.
+	sending sync.WaitGroup
+
+	kv         versioned.KV
+	storageKey string
+	mux        sync.Mutex
+}
+
+// NewOrLoad loads the Scheduler saved under the storage key, if it exists.
+// Otherwise, it initialises a new empty Scheduler. The Scheduler starts sending
+// once its processes are started and the network becomes healthy.
+func NewOrLoad(kv versioned.KV, storageKey string, net HealthTracker,
+	send SendFunc) (*Scheduler, error) {
+	s := &Scheduler{
+		sends:      make(map[uint64]*Send),
+		send:       send,
+		kv:         kv,
+		storageKey: storageKey,
+	}
+
+	if err := s.load(); err != nil && kv.Exists(err) {
+		return nil, err
+	}
+
+	net.AddHealthCallback(s.healthUpdate)
+
+	return s, nil
+}
+
+// Add schedules a send to the conversation at sendAt and returns it with its
+// ID set. Returns InvalidTimeErr if sendAt is not in the future.
+func (s *Scheduler) Add(
+	conversation, details []byte, sendAt time.Time) (Send, error) {
+	if !sendAt.After(netTime.Now()) {
+		return Send{}, InvalidTimeErr
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ss := &Send{
+		ID:           s.nextID,
+		Conversation: conversation,
+		Details:      details,
+		SendAt:       sendAt.UTC().Round(0),
+	}
+	s.nextID++
+	s.sends[ss.ID] = ss
+
+	if err := s.save(); err != nil {
+		delete(s.sends, ss.ID)
+		return Send{}, err
+	}
+	s.resetTimerUnsafe()
+
+	return *ss, nil
+}
+
+// Get returns all scheduled sends to the conversation sorted by the time they
+// are sent. If conversation is nil, then scheduled sends to all conversations
+// are returned.
+func (s *Scheduler) Get(conversation []byte) []Send {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	list := make([]Send, 0, len(s.sends))
+	for _, ss := range s.sends {
+		if conversation == nil || bytes.Equal(ss.Conversation, conversation) {
+			list = append(list, *ss)
+		}
+	}
+
+	sortSends(list)
+	return list
+}
+
+// Cancel removes the scheduled send. Returns NotFoundErr if it does not exist
+// or was already sent.
+func (s *Scheduler) Cancel(sendID uint64) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, exists := s.sends[sendID]; !exists {
+		return NotFoundErr
+	}
+
+	delete(s.sends, sendID)
+	s.resetTimerUnsafe()
+	return s.save()
+}
+
+// Reschedule changes the time of the scheduled send. Returns NotFoundErr if it
+// does not exist or was already sent and InvalidTimeErr if sendAt is not in the
+// future.
+func (s *Scheduler) Reschedule(sendID uint64, sendAt time.Time) (Send, error) {
+	if !sendAt.After(netTime.Now()) {
+		return Send{}, InvalidTimeErr
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ss, exists := s.sends[sendID]
+	if !exists {
+		return Send{}, NotFoundErr
+	}
+
+	ss.SendAt = sendAt.UTC().Round(0)
+	s.resetTimerUnsafe()
+	return *ss, s.save()
+}
+
+// RemoveConversation removes all scheduled sends to the conversation and
+// returns them.
+func (s *Scheduler) RemoveConversation(conversation []byte) ([]Send, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var removed []Send
+	for sendID, ss := range s.sends {
+		if bytes.Equal(ss.Conversation, conversation) {
+			removed = append(removed, *ss)
+			delete(s.sends, sendID)
+		}
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	sortSends(removed)
+	s.resetTimerUnsafe()
+	return removed, s.save()
+}
+
+// StartProcesses starts sending scheduled sends. The timer of the next send is
+// stopped when the returned stoppable is stopped, and stopping waits for sends
+// in progress to finish. This function adheres to the [xxdk.Service] type.
+func (s *Scheduler) StartProcesses() (stoppable.Stoppable, error) {
+	stop := stoppable.NewSingle("ScheduledSends/" + s.storageKey)
+
+	s.mux.Lock()
+	s.running = true
+	s.resetTimerUnsafe()
+	s.mux.Unlock()
+
+	// Send everything that became due while the processes were stopped
+	go s.sendDue(netTime.Now())
+
+	go func() {
+		<-stop.Quit()
+
+		s.mux.Lock()
+		s.running = false
+		s.resetTimerUnsafe()
+		s.mux.Unlock()
+
+		s.sending.Wait()
+		stop.ToStopped()
+	}()
+
+	return stop, nil
+}
+
+// healthUpdate is registered as a network health callback. It sends all
+// overdue sends when the network becomes healthy.
+func (s *Scheduler) healthUpdate(healthy bool) {
+	s.mux.Lock()
+	s.healthy = healthy
+	s.mux.Unlock()
+
+	if healthy {
+		s.sendDue(netTime.Now())
+	}
+}
+
+// sendDue sends all scheduled sends due at or before now, if the processes are
+// running and the network is healthy. Sends are removed from storage before
+// they are sent.
+func (s *Scheduler) sendDue(now time.Time) {
+	s.mux.Lock()
+	if !s.running || !s.healthy {
+		s.mux.Unlock()
+		return
+	}
+
+	var due []Send
+	for sendID, ss := range s.sends {
+		if !ss.SendAt.After(now) {
+			due = append(due, *ss)
+			delete(s.sends, sendID)
+		}
+	}
+
+	if len(due) > 0 {
+		if err := s.save(); err != nil {
+			jww.ERROR.Printf("[SCHEDULER] Failed to save scheduled sends "+
+				"%s: %+v", s.storageKey, err)
+		}
+	}
+	s.resetTimerUnsafe()
+
+	// Added while the mutex is locked and running is true so that stopping
+	// waits for these sends
+	s.sending.Add(1)
+	s.mux.Unlock()
+	defer s.sending.Done()
+
+	sortSends(due)
+	for _, ss := range due {
+		s.send(ss)
+	}
+}
+
+// resetTimerUnsafe sets the timer to fire when the earliest scheduled send is
+// due. The timer is stopped if there are no scheduled sends or if the processes
+// are not running.
+//
+// This function is not thread safe and should only be called while the mutex
+// is locked.
+func (s *Scheduler) resetTimerUnsafe() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if !s.running {
+		return
+	}
+
+	var next time.Time
+	for _, ss := range s.sends {
+		if next.IsZero() || ss.SendAt.Before(next) {
+			next = ss.SendAt
+		}
+	}
+
+	if !next.IsZero() {
+		s.timer = time.AfterFunc(netTime.Until(next), func() {
+			s.sendDue(netTime.Now())
+		})
+	}
+}
+
+// sortSends sorts the list by the time each send is sent, then by ID.
+func sortSends(list []Send) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SendAt.Equal(list[j].SendAt) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].SendAt.Before(list[j].SendAt)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// Storage version.
+const storeVersion = 0
+
+// disk is the structure of the Scheduler saved to storage.
+type disk struct {
+	NextID uint64 `json:"nextID"`
+	Sends  []Send `json:"sends"`
+}
+
+// save stores every scheduled send to storage.
+//
+// This function is not thread safe and should only be called while the mutex
+// is locked.
+func (s *Scheduler) save() error {
+	d := disk{
+		NextID: s.nextID,
+		Sends:  make([]Send, 0, len(s.sends)),
+	}
+	for _, ss := range s.sends {
+		d.Sends = append(d.Sends, *ss)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   storeVersion,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return s.kv.Set(s.storageKey, obj)
+}
+
+// load gets every scheduled send from storage.
+func (s *Scheduler) load() error {
+	obj, err := s.kv.Get(s.storageKey, storeVersion)
+	if err != nil {
+		return err
+	}
+
+	var d disk
+	if err = json.Unmarshal(obj.Data, &d); err != nil {
+		return errors.Wrap(err, "could not unmarshal scheduled sends")
+	}
+
+	s.nextID = d.NextID
+	for i := range d.Sends {
+		s.sends[d.Sends[i].ID] = &d.Sends[i]
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that NewOrLoad initialises a new empty Scheduler and loads the sends
+// saved by a previous one.
+func TestNewOrLoad(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	s, err := NewOrLoad(kv, "testKey", mockHealthTracker{}, nil)
+	require.NoError(t, err)
+	require.Empty(t, s.Get(nil))
+
+	sendAt := netTime.Now().Add(time.Hour)
+	expected := make([]Send, 3)
+	for i := range expected {
+		expected[i], err = s.Add([]byte("conversation"), []byte{byte(i)},
+			sendAt.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), expected[i].ID)
+	}
+
+	loaded, err := NewOrLoad(kv, "testKey", mockHealthTracker{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, s.nextID, loaded.nextID)
+	require.Equal(t, expected, loaded.Get(nil))
+
+	other, err := NewOrLoad(kv, "otherKey", mockHealthTracker{}, nil)
+	require.NoError(t, err)
+	require.Empty(t, other.Get(nil))
+}
+
+// Tests that Scheduler.Get returns only the sends to the conversation, sorted
+// by the time they are sent.
+func TestScheduler_Get(t *testing.T) {
+	s := newTestScheduler(t, newTestSends())
+
+	conversations := [][]byte{[]byte("a"), []byte("b")}
+	now := netTime.Now()
+	for i, offset := range []int{5, 1, 3, 2, 4} {
+		_, err := s.Add(conversations[i%2], nil,
+			now.Add(time.Duration(offset)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	all := s.Get(nil)
+	require.Len(t, all, 5)
+	for i := 1; i < len(all); i++ {
+		require.True(t, all[i-1].SendAt.Before(all[i].SendAt))
+	}
+
+	list := s.Get(conversations[1])
+	require.Len(t, list, 2)
+	require.Equal(t, uint64(1), list[0].ID)
+	require.Equal(t, uint64(3), list[1].ID)
+}
+
+// Tests that Scheduler.Cancel and Scheduler.Reschedule modify the send and
+// return NotFoundErr for an unknown send and InvalidTimeErr for a time in the
+// past.
+func TestScheduler_CancelReschedule(t *testing.T) {
+	s := newTestScheduler(t, newTestSends())
+
+	now := netTime.Now()
+	_, err := s.Add(nil, nil, now.Add(-time.Second))
+	require.ErrorIs(t, err, InvalidTimeErr)
+
+	a, err := s.Add(nil, nil, now.Add(time.Minute))
+	require.NoError(t, err)
+	b, err := s.Add(nil, nil, now.Add(2*time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Cancel(a.ID))
+	require.ErrorIs(t, s.Cancel(a.ID), NotFoundErr)
+
+	_, err = s.Reschedule(b.ID, now.Add(-time.Second))
+	require.ErrorIs(t, err, InvalidTimeErr)
+
+	sendAt := now.Add(time.Hour)
+	ss, err := s.Reschedule(b.ID, sendAt)
+	require.NoError(t, err)
+	require.True(t, sendAt.Equal(ss.SendAt))
+	require.Equal(t, []Send{ss}, s.Get(nil))
+
+	_, err = s.Reschedule(a.ID, sendAt)
+	require.ErrorIs(t, err, NotFoundErr)
+}
+
+// Tests that Scheduler.RemoveConversation removes only the sends to the
+// conversation.
+func TestScheduler_RemoveConversation(t *testing.T) {
+	s := newTestScheduler(t, newTestSends())
+
+	conversations := [][]byte{[]byte("a"), []byte("b")}
+	sendAt := netTime.Now().Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		_, err := s.Add(conversations[i%2], nil, sendAt)
+		require.NoError(t, err)
+	}
+
+	removed, err := s.RemoveConversation(conversations[0])
+	require.NoError(t, err)
+	require.Len(t, removed, 2)
+	require.Empty(t, s.Get(conversations[0]))
+	require.Len(t, s.Get(conversations[1]), 2)
+}
+
+// Tests that Scheduler.sendDue holds due sends until the processes are running
+// and the network is healthy, and then sends them in order.
+func TestScheduler_sendDue(t *testing.T) {
+	ts := newTestSends()
+	s := newTestScheduler(t, ts)
+
+	now := netTime.Now()
+	for _, offset := range []time.Duration{3, 1, 2} {
+		_, err := s.Add(nil, nil, now.Add(offset*time.Millisecond))
+		require.NoError(t, err)
+	}
+	future, err := s.Add(nil, nil, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	s.sendDue(now.Add(time.Second))
+	require.Empty(t, ts.get())
+
+	s.healthUpdate(true)
+	require.Empty(t, ts.get())
+	require.Len(t, s.Get(nil), 4)
+
+	s.mux.Lock()
+	s.running = true
+	s.mux.Unlock()
+	s.sendDue(now.Add(time.Second))
+	sent := ts.get()
+	require.Len(t, sent, 3)
+	for i, sendID := range []uint64{1, 2, 0} {
+		require.Equal(t, sendID, sent[i].ID)
+	}
+	require.Equal(t, []Send{future}, s.Get(nil))
+}
+
+// Tests that the Scheduler sends a scheduled send when its timer fires once
+// the processes are started and stops the timer once they are stopped.
+func TestScheduler_StartProcesses(t *testing.T) {
+	ts := newTestSends()
+	s := newTestScheduler(t, ts)
+	s.healthUpdate(true)
+
+	ss, err := s.Add(nil, nil, netTime.Now().Add(25*time.Millisecond))
+	require.NoError(t, err)
+	s.mux.Lock()
+	require.Nil(t, s.timer)
+	s.mux.Unlock()
+
+	stop, err := s.StartProcesses()
+	require.NoError(t, err)
+
+	select {
+	case sendID := <-ts.done:
+		require.Equal(t, ss.ID, sendID)
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for scheduled send.")
+	}
+	require.Empty(t, s.Get(nil))
+
+	_, err = s.Add(nil, nil, netTime.Now().Add(25*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, stop.Close())
+	err = stoppable.WaitForStopped(stop, time.Second)
+	require.NoError(t, err)
+
+	s.mux.Lock()
+	require.Nil(t, s.timer)
+	s.mux.Unlock()
+
+	select {
+	case sendID := <-ts.done:
+		t.Fatalf("Send %d sent after the processes stopped.", sendID)
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.Len(t, s.Get(nil), 1)
+}
+
+// newTestScheduler returns a new empty Scheduler backed by memory.
+func newTestScheduler(t *testing.T, ts *testSends) *Scheduler {
+	s, err := NewOrLoad(versioned.NewKV(ekv.MakeMemstore()), "testKey",
+		mockHealthTracker{}, ts.send)
+	require.NoError(t, err)
+	return s
+}
+
+// testSends records every send made by a Scheduler.
+type testSends struct {
+	sent []Send
+	done chan uint64
+	mux  sync.Mutex
+}
+
+func newTestSends() *testSends {
+	return &testSends{done: make(chan uint64, 10)}
+}
+
+func (ts *testSends) send(s Send) {
+	ts.mux.Lock()
+	ts.sent = append(ts.sent, s)
+	ts.mux.Unlock()
+	ts.done <- s.ID
+}
+
+func (ts *testSends) get() []Send {
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+	return append([]Send{}, ts.sent...)
+}
+
+// mockHealthTracker adheres to the HealthTracker interface.
+type mockHealthTracker struct{}
+
+func (mockHealthTracker) AddHealthCallback(func(bool)) uint64 { return 0 }
@@ -0,0 +1,95 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package typingIndicator contains the rate limiting and send parameters of
+// typing indicators that are shared by channels and direct messages.
+package typingIndicator
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+)
+
+const (
+	// Interval is the minimum amount of time between two typing indicators
+	// sent to the same conversation while the user is typing. The UI should
+	// resend the indicator at this interval for as long as the user keeps
+	// typing.
+	Interval = 5 * time.Second
+
+	// Timeout is how long a typing indicator is valid for. Indicators older
+	// than this are dropped on reception, and the UI should stop showing a user
+	// as typing if it has not received a new indicator from them within this
+	// time.
+	Timeout = 3 * Interval
+
+	// RoundTries is the maximum number of rounds to try to send a typing
+	// indicator on. Typing indicators are low priority and are not worth
+	// retrying.
+	RoundTries = 1
+)
+
+// Limiter limits how often typing indicators are sent to each conversation.
+type Limiter struct {
+	interval time.Duration
+
+	// started is the time the last indicator that the user started typing was
+	// sent to each conversation. A conversation is removed once the user stops
+	// typing.
+	started map[string]time.Time
+	mux     sync.Mutex
+}
+
+// NewLimiter returns a new Limiter that allows one typing indicator per
+// conversation every interval.
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{
+		interval: interval,
+		started:  make(map[string]time.Time),
+	}
+}
+
+// Allow returns true if a typing indicator can be sent to the conversation. The
+// conversation is identified by any key unique to it, such as a channel ID or
+// the public key of a DM partner.
+//
+// Indicators that the user is typing are allowed at most once per interval. An
+// indicator that the user stopped typing is only allowed if an indicator that
+// they started typing was sent first.
+func (l *Limiter) Allow(conversation []byte, typing bool, now time.Time) bool {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	key := string(conversation)
+	started, exists := l.started[key]
+	if !typing {
+		delete(l.started, key)
+		return exists
+	}
+
+	if exists && now.Sub(started) < l.interval {
+		return false
+	}
+
+	l.started[key] = now
+	return true
+}
+
+// Params modifies the cMix parameters so that typing indicators are sent at low
+// priority and are abandoned once they are no longer valid.
+func Params(params cmix.CMIXParams) cmix.CMIXParams {
+	params.Critical = false
+	if params.RoundTries == 0 || params.RoundTries > RoundTries {
+		params.RoundTries = RoundTries
+	}
+	if params.Timeout == 0 || params.Timeout > Timeout {
+		params.Timeout = Timeout
+	}
+	return params
+}
@@ -0,0 +1,72 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package typingIndicator
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that Limiter.Allow only allows one typing indicator per interval for
+// each conversation and only allows a stop after a start.
+func TestLimiter_Allow(t *testing.T) {
+	l := NewLimiter(Interval)
+	conv1, conv2 := []byte("conversation1"), []byte("conversation2")
+	now := netTime.Now()
+
+	tests := []struct {
+		conversation []byte
+		typing       bool
+		now          time.Time
+		expected     bool
+	}{
+		{conv1, false, now, false},
+		{conv1, true, now, true},
+		{conv1, true, now.Add(Interval / 2), false},
+		{conv2, true, now.Add(Interval / 2), true},
+		{conv1, true, now.Add(Interval), true},
+		{conv1, false, now.Add(Interval), true},
+		{conv1, false, now.Add(Interval), false},
+		{conv1, true, now.Add(Interval), true},
+	}
+
+	for i, tt := range tests {
+		allowed := l.Allow(tt.conversation, tt.typing, tt.now)
+		if allowed != tt.expected {
+			t.Errorf("Unexpected result for typing=%t (%d)."+
+				"\nexpected: %t\nreceived: %t",
+				tt.typing, i, tt.expected, allowed)
+		}
+	}
+}
+
+// Tests that Params limits the round tries and timeout and leaves smaller
+// values unchanged.
+func TestParams(t *testing.T) {
+	params := Params(cmix.GetDefaultCMIXParams())
+	if params.RoundTries != RoundTries {
+		t.Errorf("Incorrect RoundTries.\nexpected: %d\nreceived: %d",
+			RoundTries, params.RoundTries)
+	}
+	if params.Timeout != Timeout {
+		t.Errorf("Incorrect Timeout.\nexpected: %s\nreceived: %s",
+			Timeout, params.Timeout)
+	}
+
+	params = Params(cmix.CMIXParams{Timeout: time.Second, Critical: true})
+	if params.Timeout != time.Second {
+		t.Errorf("Smaller Timeout was changed.\nexpected: %s\nreceived: %s",
+			time.Second, params.Timeout)
+	}
+	if params.Critical {
+		t.Errorf("Typing indicators must not be critical.")
+	}
+}
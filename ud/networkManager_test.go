@@ -205,6 +205,18 @@ func (tnm *testNetworkManager) SendManyWithAssembler(recipients []*id.ID, assemb
 	//TODO implement me
 	panic("implement me")
 }
+func (tnm *testNetworkManager) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	panic("implement me")
+}
+func (tnm *testNetworkManager) GetQueuedSends() []cmix.QueuedSend {
+	panic("implement me")
+}
+func (tnm *testNetworkManager) CancelQueuedSend(uint64) error {
+	panic("implement me")
+}
+func (tnm *testNetworkManager) SetOutboxCallback(cmix.OutboxCallback) {
+	panic("implement me")
+}
 
 func (tnm *testNetworkManager) RemoveIdentity(id *id.ID) {
 	//TODO implement me
@@ -325,6 +325,39 @@ func LoadSynchronizedCmix(storageDir, remoteStoragePathPrefix string, password [
 	return loadCmix(c, parameters)
 }
 
+// NetworkBuilder builds the cmix.Client used by a Cmix object loaded with
+// LoadCmixWithNetwork from the reception ID, KV, and event reporter of the
+// loaded storage.
+type NetworkBuilder func(receptionID *id.ID, kv versioned.KV,
+	events event.Reporter) cmix.Client
+
+// LoadCmixWithNetwork initializes a Cmix object from existing storage that
+// uses the cmix.Client returned by newNetwork instead of connecting to the
+// real network. It is intended for tests; see the xxdktest package for loading
+// clients on a simulated network.
+func LoadCmixWithNetwork(storageDir string, password []byte,
+	newNetwork NetworkBuilder) (*Cmix, error) {
+	jww.INFO.Printf("LoadCmixWithNetwork(%s)", storageDir)
+
+	c, err := OpenCmix(storageDir, password)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c.network = newNetwork(
+		c.storage.GetReceptionID(), c.storage.GetKV(), c.events)
+
+	jww.INFO.Printf("Client loaded with custom network: \n\tTransmissionID: %s",
+		c.GetTransmissionIdentity().ID)
+
+	err = c.registerFollower()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
 func loadCmix(c *Cmix, parameters CMIXParams) (*Cmix, error) {
 	var err error
 	c.network, err = cmix.NewClient(
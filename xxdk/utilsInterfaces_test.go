@@ -139,6 +139,16 @@ func (t *testNetworkManagerGeneric) SendMany(messages []cmix.TargetedCmixMessage
 func (t *testNetworkManagerGeneric) SendManyWithAssembler(recipients []*id.ID, assembler cmix.ManyMessageAssembler, params cmix.CMIXParams) (rounds.Round, []ephemeral.Id, error) {
 	return rounds.Round{}, []ephemeral.Id{}, nil
 }
+func (t *testNetworkManagerGeneric) QueueSend(*id.ID, format.Fingerprint, cmix.Service, []byte, []byte, cmix.CMIXParams) (uint64, error) {
+	return 0, nil
+}
+func (t *testNetworkManagerGeneric) GetQueuedSends() []cmix.QueuedSend {
+	return nil
+}
+func (t *testNetworkManagerGeneric) CancelQueuedSend(uint64) error {
+	return nil
+}
+func (t *testNetworkManagerGeneric) SetOutboxCallback(cmix.OutboxCallback) {}
 func (t *testNetworkManagerGeneric) GetInstance() *network.Instance {
 	return t.instance
 }
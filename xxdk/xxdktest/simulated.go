@@ -0,0 +1,103 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package xxdktest provides utilities for running xxdk clients on a simulated
+// cMix network in integration tests. It is kept separate from xxdk so that
+// production builds do not depend on the simulated network.
+package xxdktest
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/simulated"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/event"
+	"gitlab.com/elixxir/client/v4/xxdk"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// stopTimeout is the maximum amount of time to wait for a client's network
+// follower to stop during test cleanup.
+const stopTimeout = 10 * time.Second
+
+// LoadSimulatedCmix initializes a Cmix object from existing storage on a
+// simulated network instead of the real network. The storage must have been
+// created with the NDF of the simulated network.
+func LoadSimulatedCmix(storageDir string, password []byte,
+	net *simulated.Network) (*xxdk.Cmix, error) {
+	return xxdk.LoadCmixWithNetwork(storageDir, password,
+		func(receptionID *id.ID, kv versioned.KV,
+			events event.Reporter) cmix.Client {
+			return net.NewClient(receptionID, kv, events)
+		})
+}
+
+// NewSimulatedE2e creates a precanned E2E client on the simulated network that
+// is following the network. Its storage is in a temporary directory of the
+// test. The network follower is stopped when the test finishes and cleanup
+// waits for all of its processes to stop before the directory is removed.
+func NewSimulatedE2e(
+	t testing.TB, net *simulated.Network, precannedID uint) *xxdk.E2e {
+	marshalledDef, err := net.NDF().Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal NDF: %+v", err)
+	}
+	storageDir := t.TempDir()
+	password := []byte("hunter2")
+
+	err = xxdk.NewPrecannedCmix(
+		precannedID, string(marshalledDef), storageDir, password)
+	if err != nil {
+		t.Fatalf("Failed to create precanned client %d: %+v", precannedID, err)
+	}
+
+	c, err := LoadSimulatedCmix(storageDir, password, net)
+	if err != nil {
+		t.Fatalf("Failed to load simulated client %d: %+v", precannedID, err)
+	}
+
+	identity, err := xxdk.MakeLegacyReceptionIdentity(c)
+	if err != nil {
+		t.Fatalf("Failed to make identity: %+v", err)
+	}
+
+	user, err := xxdk.Login(c, xxdk.DefaultAuthCallbacks{}, identity,
+		xxdk.GetDefaultE2EParams())
+	if err != nil {
+		t.Fatalf("Failed to log in client %d: %+v", precannedID, err)
+	}
+
+	if err = c.StartNetworkFollower(5 * time.Second); err != nil {
+		t.Fatalf("Failed to start network follower: %+v", err)
+	}
+
+	// Registered after t.TempDir so that it runs before the directory is
+	// removed
+	t.Cleanup(func() { StopNetworkFollower(t, c) })
+
+	return user
+}
+
+// StopNetworkFollower stops the network follower of the client and waits for
+// all of its processes to stop so that nothing writes to storage afterwards.
+func StopNetworkFollower(t testing.TB, c *xxdk.Cmix) {
+	if err := c.StopNetworkFollower(); err != nil {
+		t.Errorf("Failed to stop network follower: %+v", err)
+		return
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for c.HasRunningProcessies() {
+		if time.Now().After(deadline) {
+			t.Errorf("Network follower did not stop after %s", stopTimeout)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
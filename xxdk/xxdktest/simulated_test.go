@@ -0,0 +1,68 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package xxdktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/catalog"
+	"gitlab.com/elixxir/client/v4/cmix/simulated"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	"gitlab.com/elixxir/client/v4/xxdk"
+)
+
+// Tests that two precanned clients on a simulated network can exchange E2E
+// messages in both directions.
+func TestLoadSimulatedCmix(t *testing.T) {
+	net, err := simulated.NewNetwork(simulated.DefaultParams())
+	require.NoError(t, err)
+
+	user1 := NewSimulatedE2e(t, net, 1)
+	user2 := NewSimulatedE2e(t, net, 2)
+
+	contact2, err := user1.MakePrecannedAuthenticatedChannel(2)
+	require.NoError(t, err)
+	contact1, err := user2.MakePrecannedAuthenticatedChannel(1)
+	require.NoError(t, err)
+
+	received1 := make(chan receive.Message, 10)
+	user1.GetE2E().RegisterChannel(
+		"user1", contact2.ID, catalog.XxMessage, received1)
+	received2 := make(chan receive.Message, 10)
+	user2.GetE2E().RegisterChannel(
+		"user2", contact1.ID, catalog.XxMessage, received2)
+
+	params := xxdk.GetDefaultE2EParams().Base
+	payload1 := []byte("Hello from user 1.")
+	_, err = user1.GetE2E().SendE2E(
+		catalog.XxMessage, contact2.ID, payload1, params)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received2:
+		require.Equal(t, payload1, msg.Payload)
+		require.Equal(t, contact1.ID, msg.Sender)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for message from user 1.")
+	}
+
+	payload2 := []byte("Hello from user 2.")
+	_, err = user2.GetE2E().SendE2E(
+		catalog.XxMessage, contact1.ID, payload2, params)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received1:
+		require.Equal(t, payload2, msg.Payload)
+		require.Equal(t, contact2.ID, msg.Sender)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for message from user 2.")
+	}
+}